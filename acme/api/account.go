@@ -77,6 +77,32 @@ func (a *AccountService) Update(accountURL string, req acme.Account) (acme.Accou
 	return account, nil
 }
 
+// UpdateContacts replaces the contacts of an account.
+// An empty list of contacts removes all the contacts.
+func (a *AccountService) UpdateContacts(accountURL string, contacts []string) (acme.Account, error) {
+	if accountURL == "" {
+		return acme.Account{}, errors.New("account[update]: empty URL")
+	}
+
+	if contacts == nil {
+		contacts = []string{}
+	}
+
+	// The contact field is always sent: acme.Account omits an empty list.
+	req := struct {
+		Contact []string `json:"contact"`
+	}{Contact: contacts}
+
+	var account acme.Account
+
+	_, err := a.core.post(accountURL, req, &account)
+	if err != nil {
+		return acme.Account{}, err
+	}
+
+	return account, nil
+}
+
 // Deactivate Deactivates an account.
 func (a *AccountService) Deactivate(accountURL string) error {
 	if accountURL == "" {
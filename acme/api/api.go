@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -15,7 +16,9 @@ import (
 	"github.com/digicert/lego/v4/acme/api/internal/nonces"
 	"github.com/digicert/lego/v4/acme/api/internal/secure"
 	"github.com/digicert/lego/v4/acme/api/internal/sender"
+	"github.com/digicert/lego/v4/events"
 	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/progress"
 )
 
 // Core ACME/LE core API.
@@ -24,6 +27,9 @@ type Core struct {
 	nonceManager *nonces.Manager
 	jws          *secure.JWS
 	directory    acme.Directory
+	logger       *slog.Logger
+	events       *events.Bus
+	progress     *progress.Tracker
 	HTTPClient   *http.Client
 
 	common         service // Reuse a single struct instead of allocating one for each service on the heap.
@@ -47,7 +53,7 @@ func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey cr
 
 	jws := secure.NewJWS(privateKey, kid, nonceManager)
 
-	c := &Core{doer: doer, nonceManager: nonceManager, jws: jws, directory: dir, HTTPClient: httpClient}
+	c := &Core{doer: doer, nonceManager: nonceManager, jws: jws, directory: dir, HTTPClient: httpClient, events: events.NewBus()}
 
 	c.common.core = c
 	c.Accounts = (*AccountService)(&c.common)
@@ -59,6 +65,45 @@ func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey cr
 	return c, nil
 }
 
+// SetLogger sets the logger used by the core and the components built on top of it (certifier, solvers, ...).
+func (a *Core) SetLogger(logger *slog.Logger) {
+	a.logger = logger
+}
+
+// Logger returns the logger of the core, or the default logger.
+func (a *Core) Logger() *slog.Logger {
+	if a == nil || a.logger == nil {
+		return log.Default()
+	}
+
+	return a.logger
+}
+
+// Events returns the event bus shared by the core and the components built on top of it.
+func (a *Core) Events() *events.Bus {
+	if a == nil {
+		return nil
+	}
+
+	return a.events
+}
+
+// SetProgressReporter sets the reporter of the progress of the long operations (obtain, renew).
+// A nil reporter disables the reporting.
+func (a *Core) SetProgressReporter(reporter progress.Reporter) {
+	a.progress = progress.NewTracker(reporter)
+}
+
+// Progress returns the progress tracker shared by the core and the components built on top of it.
+// The returned tracker can be nil (reporting disabled), and is safe to use in this case.
+func (a *Core) Progress() *progress.Tracker {
+	if a == nil {
+		return nil
+	}
+
+	return a.progress
+}
+
 // post performs an HTTP POST request and parses the response body as JSON,
 // into the provided respBody object.
 func (a *Core) post(uri string, reqBody, response any) (*http.Response, error) {
@@ -100,7 +145,7 @@ func (a *Core) retrievablePost(uri string, content []byte, response any) (*http.
 	}
 
 	notify := func(err error, duration time.Duration) {
-		log.Infof("retry due to: %v", err)
+		a.Logger().Info("acme: retry.", "error", err, "delay", duration)
 	}
 
 	return backoff.Retry(ctx, operation,
@@ -142,6 +187,11 @@ func (a *Core) GetKeyAuthorization(token string) (string, error) {
 	return a.jws.GetKeyAuthorization(token)
 }
 
+// GetKid Gets the key identifier (the account URL).
+func (a *Core) GetKid() string {
+	return a.jws.GetKid()
+}
+
 func (a *Core) GetDirectory() acme.Directory {
 	return a.directory
 }
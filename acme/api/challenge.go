@@ -10,15 +10,20 @@ type ChallengeService service
 
 // New Creates a challenge.
 func (c *ChallengeService) New(chlgURL string) (acme.ExtendedChallenge, error) {
+	// Challenge initiation is done by sending a JWS payload containing the trivial JSON object `{}`.
+	// We use an empty struct instance as the postJSON payload here to achieve this result.
+	return c.Respond(chlgURL, struct{}{})
+}
+
+// Respond Creates a challenge with a response (ex: the CSR of the onion-csr-01 challenge).
+func (c *ChallengeService) Respond(chlgURL string, response any) (acme.ExtendedChallenge, error) {
 	if chlgURL == "" {
 		return acme.ExtendedChallenge{}, errors.New("challenge[new]: empty URL")
 	}
 
-	// Challenge initiation is done by sending a JWS payload containing the trivial JSON object `{}`.
-	// We use an empty struct instance as the postJSON payload here to achieve this result.
 	var chlng acme.ExtendedChallenge
 
-	resp, err := c.core.post(chlgURL, struct{}{}, &chlng)
+	resp, err := c.core.post(chlgURL, response, &chlng)
 	if err != nil {
 		return acme.ExtendedChallenge{}, err
 	}
@@ -33,10 +33,17 @@ func (j *JWS) SetKid(kid string) {
 	j.kid = kid
 }
 
+// GetKid Gets the key identifier.
+func (j *JWS) GetKid() string {
+	return j.kid
+}
+
 // SignContent Signs a content with the JWS.
 func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, error) {
 	var alg jose.SignatureAlgorithm
 
+	key := j.privKey
+
 	switch k := j.privKey.(type) {
 	case *rsa.PrivateKey:
 		alg = jose.RS256
@@ -45,12 +52,23 @@ func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, e
 			alg = jose.ES256
 		} else if k.Curve == elliptic.P384() {
 			alg = jose.ES384
+		} else if k.Curve == elliptic.P521() {
+			alg = jose.ES512
 		}
+	case crypto.Signer:
+		// The private key is not available (ex: HSM), the signature is delegated to the signer.
+		signer, err := newOpaqueSigner(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create opaque signer: %w", err)
+		}
+
+		alg = signer.alg
+		key = signer
 	}
 
 	signKey := jose.SigningKey{
 		Algorithm: alg,
-		Key:       jose.JSONWebKey{Key: j.privKey, KeyID: j.kid},
+		Key:       jose.JSONWebKey{Key: key, KeyID: j.kid},
 	}
 
 	options := jose.SignerOptions{
@@ -79,7 +97,7 @@ func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, e
 
 // SignEABContent Signs an external account binding content with the JWS.
 func (j *JWS) SignEABContent(url, kid string, hmac []byte) (*jose.JSONWebSignature, error) {
-	jwk := jose.JSONWebKey{Key: j.privKey}
+	jwk := jose.JSONWebKey{Key: publicKey(j.privKey)}
 
 	jwkJSON, err := jwk.Public().MarshalJSON()
 	if err != nil {
@@ -110,17 +128,8 @@ func (j *JWS) SignEABContent(url, kid string, hmac []byte) (*jose.JSONWebSignatu
 
 // GetKeyAuthorization Gets the key authorization for a token.
 func (j *JWS) GetKeyAuthorization(token string) (string, error) {
-	var publicKey crypto.PublicKey
-
-	switch k := j.privKey.(type) {
-	case *ecdsa.PrivateKey:
-		publicKey = k.Public()
-	case *rsa.PrivateKey:
-		publicKey = k.Public()
-	}
-
 	// Generate the Key Authorization for the challenge
-	jwk := &jose.JSONWebKey{Key: publicKey}
+	jwk := &jose.JSONWebKey{Key: publicKey(j.privKey)}
 
 	thumbBytes, err := jwk.Thumbprint(crypto.SHA256)
 	if err != nil {
@@ -0,0 +1,118 @@
+package secure
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+var _ jose.OpaqueSigner = (*opaqueSigner)(nil)
+
+// opaqueSigner signs the JWS with a crypto.Signer (ex: a key stored in an HSM).
+type opaqueSigner struct {
+	signer crypto.Signer
+	alg    jose.SignatureAlgorithm
+}
+
+func newOpaqueSigner(signer crypto.Signer) (*opaqueSigner, error) {
+	var alg jose.SignatureAlgorithm
+
+	switch k := signer.Public().(type) {
+	case *rsa.PublicKey:
+		alg = jose.RS256
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			alg = jose.ES256
+		case elliptic.P384():
+			alg = jose.ES384
+		case elliptic.P521():
+			alg = jose.ES512
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Curve.Params().Name)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported public key type: %T", k)
+	}
+
+	return &opaqueSigner{signer: signer, alg: alg}, nil
+}
+
+func (o *opaqueSigner) Public() *jose.JSONWebKey {
+	return &jose.JSONWebKey{Key: o.signer.Public(), Algorithm: string(o.alg)}
+}
+
+func (o *opaqueSigner) Algs() []jose.SignatureAlgorithm {
+	return []jose.SignatureAlgorithm{o.alg}
+}
+
+func (o *opaqueSigner) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	var hash crypto.Hash
+
+	switch alg {
+	case jose.RS256, jose.ES256:
+		hash = crypto.SHA256
+	case jose.ES384:
+		hash = crypto.SHA384
+	case jose.ES512:
+		hash = crypto.SHA512
+	default:
+		return nil, jose.ErrUnsupportedAlgorithm
+	}
+
+	hasher := hash.New()
+	_, _ = hasher.Write(payload)
+
+	signature, err := o.signer.Sign(rand.Reader, hasher.Sum(nil), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := o.signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return signature, nil
+	}
+
+	// The crypto.Signer returns an ASN.1 signature, but the JWS uses the concatenation of R and S (RFC 7518, section 3.4).
+	return toRawECDSASignature(signature, (publicKey.Curve.Params().BitSize+7)/8)
+}
+
+func toRawECDSASignature(signature []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(signature, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECDSA signature: %w", err)
+	}
+
+	if len(rest) > 0 {
+		return nil, errors.New("invalid ECDSA signature: trailing data")
+	}
+
+	raw := make([]byte, 2*size)
+
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+
+	return raw, nil
+}
+
+// publicKey returns the public key of a private key.
+func publicKey(privateKey crypto.PrivateKey) crypto.PublicKey {
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil
+	}
+
+	return signer.Public()
+}
@@ -0,0 +1,104 @@
+package secure
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"testing"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hiddenSigner hides the private key (ex: HSM).
+type hiddenSigner struct {
+	signer crypto.Signer
+}
+
+func (h *hiddenSigner) Public() crypto.PublicKey {
+	return h.signer.Public()
+}
+
+func (h *hiddenSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return h.signer.Sign(r, digest, opts)
+}
+
+func Test_opaqueSigner(t *testing.T) {
+	ecKey256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecKey384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	ecKey521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		key      crypto.Signer
+		expected jose.SignatureAlgorithm
+	}{
+		{desc: "P256", key: ecKey256, expected: jose.ES256},
+		{desc: "P384", key: ecKey384, expected: jose.ES384},
+		{desc: "P521", key: ecKey521, expected: jose.ES512},
+		{desc: "RSA", key: rsaKey, expected: jose.RS256},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			signer, err := newOpaqueSigner(&hiddenSigner{signer: test.key})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, signer.alg)
+
+			joseSigner, err := jose.NewSigner(
+				jose.SigningKey{Algorithm: signer.alg, Key: jose.JSONWebKey{Key: signer, KeyID: "kid"}},
+				&jose.SignerOptions{},
+			)
+			require.NoError(t, err)
+
+			signed, err := joseSigner.Sign([]byte("content"))
+			require.NoError(t, err)
+
+			jws, err := jose.ParseSigned(signed.FullSerialize(), []jose.SignatureAlgorithm{test.expected})
+			require.NoError(t, err)
+
+			assert.Equal(t, "kid", jws.Signatures[0].Header.KeyID)
+
+			payload, err := jws.Verify(test.key.Public())
+			require.NoError(t, err)
+
+			assert.Equal(t, []byte("content"), payload)
+		})
+	}
+}
+
+func Test_newOpaqueSigner_unsupportedCurve(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = newOpaqueSigner(&hiddenSigner{signer: key})
+	require.EqualError(t, err, "unsupported curve: P-224")
+}
+
+func TestJWS_GetKeyAuthorization_signer(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	expected, err := NewJWS(key, "", nil).GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	keyAuth, err := NewJWS(&hiddenSigner{signer: key}, "", nil).GetKeyAuthorization("token")
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, keyAuth)
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"runtime"
 	"strings"
 
 	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/internal/useragent"
 )
 
 type RequestOption func(*http.Request) error
@@ -116,7 +118,7 @@ func (d *Doer) do(req *http.Request, response any) (*http.Response, error) {
 // formatUserAgent builds and returns the User-Agent string to use in requests.
 func (d *Doer) formatUserAgent() string {
 	ua := fmt.Sprintf("%s %s (%s; %s; %s)", d.userAgent, ourUserAgent, ourUserAgentComment, runtime.GOOS, runtime.GOARCH)
-	return strings.TrimSpace(ua)
+	return useragent.Append(strings.TrimSpace(ua))
 }
 
 func checkError(req *http.Request, resp *http.Response) error {
@@ -157,11 +159,33 @@ func checkError(req *http.Request, resp *http.Response) error {
 			RetryAfter:     resp.Header.Get("Retry-After"),
 		}
 
+	case errorDetails.HTTPStatus == http.StatusForbidden && errorDetails.Type == acme.UserActionRequiredErr:
+		return &acme.UserActionRequiredError{
+			ProblemDetails:    errorDetails,
+			TermsOfServiceURL: getTermsOfServiceLink(resp.Header),
+		}
+
 	default:
 		return errorDetails
 	}
 }
 
+// getTermsOfServiceLink gets the URL of the terms of service from the Link header.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.3
+func getTermsOfServiceLink(header http.Header) string {
+	linkExpr := regexp.MustCompile(`<(.+?)>(?:;[^;]+)*?;\s*rel="(.+?)"`)
+
+	for _, link := range header["Link"] {
+		for _, m := range linkExpr.FindAllStringSubmatch(link, -1) {
+			if len(m) == 3 && m[2] == "terms-of-service" {
+				return m[1]
+			}
+		}
+	}
+
+	return ""
+}
+
 type httpsOnly struct {
 	rt http.RoundTripper
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/internal/useragent"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -72,6 +73,19 @@ func TestDo_CustomUserAgent(t *testing.T) {
 	assert.Len(t, strings.Split(ua, " "), 5)
 }
 
+func TestDo_ApplicationUserAgent(t *testing.T) {
+	t.Cleanup(func() { _ = useragent.SetApplication("") })
+
+	err := useragent.SetApplication("embedder/2.0.0")
+	require.NoError(t, err)
+
+	doer := NewDoer(http.DefaultClient, "MyApp/1.2.3")
+
+	ua := doer.formatUserAgent()
+	assert.True(t, strings.HasPrefix(ua, "MyApp/1.2.3 "+ourUserAgent))
+	assert.True(t, strings.HasSuffix(ua, ") embedder/2.0.0"))
+}
+
 func TestDo_failWithHTTP(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
 	t.Cleanup(server.Close)
@@ -123,6 +137,25 @@ func Test_checkError(t *testing.T) {
 			},
 			assert: errorAs[*acme.RateLimitedError],
 		},
+		{
+			desc: "userActionRequired",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"Link": []string{`<https://example.com/dir>; rel="index", <https://example.com/tos/v2.pdf>; rel="terms-of-service"`},
+				},
+				Body: io.NopCloser(bytes.NewBufferString(`{"type":"urn:ietf:params:acme:error:userActionRequired","detail":"message","instance":"https://example.com/tos","status":403}`)),
+			},
+			assert: func(t *testing.T, err error) {
+				t.Helper()
+
+				var uarErr *acme.UserActionRequiredError
+				require.ErrorAs(t, err, &uarErr)
+
+				assert.Equal(t, "https://example.com/tos/v2.pdf", uarErr.TermsOfServiceURL)
+				assert.Equal(t, "https://example.com/tos", uarErr.Instance)
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/digicert/lego/v4/acme"
@@ -26,6 +28,9 @@ type OrderOptions struct {
 	ReplacesCertID string
 }
 
+// ErrProfileNotSupported is returned when the requested profile is not advertised by the CA.
+var ErrProfileNotSupported = errors.New("acme: profile not supported by the CA")
+
 type OrderService service
 
 // New Creates a new order.
@@ -51,6 +56,11 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 		}
 
 		if opts.Profile != "" {
+			err := checkProfile(o.core.GetDirectory(), opts.Profile)
+			if err != nil {
+				return acme.ExtendedOrder{}, err
+			}
+
 			orderReq.Profile = opts.Profile
 		}
 	}
@@ -136,3 +146,18 @@ func (o *OrderService) UpdateForCSR(orderURL string, csr []byte) (acme.ExtendedO
 
 	return acme.ExtendedOrder{Order: order}, nil
 }
+
+// checkProfile checks that the profile is advertised by the CA (directory metadata).
+// - https://www.ietf.org/id/draft-ietf-acme-profiles-00.html#section-3
+func checkProfile(directory acme.Directory, profile string) error {
+	if len(directory.Meta.Profiles) == 0 {
+		return fmt.Errorf("%w: %q: the CA doesn't advertise any profile", ErrProfileNotSupported, profile)
+	}
+
+	if _, ok := directory.Meta.Profiles[profile]; !ok {
+		return fmt.Errorf("%w: %q: supported profiles: %s",
+			ErrProfileNotSupported, profile, strings.Join(slices.Sorted(maps.Keys(directory.Meta.Profiles)), ", "))
+	}
+
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -124,3 +126,62 @@ func readSignedBody(r *http.Request, privateKey *rsa.PrivateKey) ([]byte, error)
 
 	return body, nil
 }
+
+func TestOrderService_NewWithOptions_profile(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	server := servermock.NewBuilder(
+		func(server *httptest.Server) (*httptest.Server, error) {
+			return server, nil
+		}).
+		Route("GET /dir", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			serverURL := fmt.Sprintf("https://%s", req.Context().Value(http.LocalAddrContextKey))
+
+			servermock.JSONEncode(acme.Directory{
+				NewNonceURL:   serverURL + "/nonce",
+				NewAccountURL: serverURL + "/account",
+				NewOrderURL:   serverURL + "/newOrder",
+				Meta: acme.Meta{
+					Profiles: map[string]string{
+						"classic":    "The default profile.",
+						"shortlived": "A profile for short-lived certificates.",
+					},
+				},
+			}).ServeHTTP(rw, req)
+		})).
+		Route("HEAD /nonce", http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.Header().Set("Replay-Nonce", "12345")
+		})).
+		Route("POST /newOrder", servermock.JSONEncode(acme.Order{
+			Status:      acme.StatusPending,
+			Identifiers: []acme.Identifier{{Type: "dns", Value: "example.com"}},
+			Profile:     "shortlived",
+		})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	order, err := core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{Profile: "shortlived"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "shortlived", order.Profile)
+
+	_, err = core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{Profile: "unknown"})
+	require.ErrorIs(t, err, ErrProfileNotSupported)
+	require.EqualError(t, err, `acme: profile not supported by the CA: "unknown": supported profiles: classic, shortlived`)
+}
+
+func TestOrderService_NewWithOptions_profileNotAdvertised(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	_, err = core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{Profile: "shortlived"})
+	require.EqualError(t, err, `acme: profile not supported by the CA: "shortlived": the CA doesn't advertise any profile`)
+}
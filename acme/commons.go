@@ -293,6 +293,22 @@ type Challenge struct {
 
 	// https://www.rfc-editor.org/rfc/rfc8555.html#section-8.1
 	KeyAuthorization string `json:"keyAuthorization"`
+
+	// nonce (onion-csr-01, required, string):
+	// A Base64url-encoded nonce, used in the caSigningNonce attribute of the CSR.
+	// https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+	Nonce string `json:"nonce,omitempty"`
+
+	// Response is the payload sent to the server to initiate the validation of the challenge.
+	// The trivial JSON object `{}` is sent if it's nil.
+	Response any `json:"-"`
+}
+
+// OnionCSRResponse is the response to an onion-csr-01 challenge.
+// https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+type OnionCSRResponse struct {
+	// csr (required, string): the Base64url-encoded DER CSR, signed by the key of the hidden service.
+	CSR string `json:"csr"`
 }
 
 func (c *Challenge) Err() error {
@@ -7,10 +7,15 @@ import (
 
 // Errors types.
 const (
-	errNS              = "urn:ietf:params:acme:error:"
-	BadNonceErr        = errNS + "badNonce"
-	AlreadyReplacedErr = errNS + "alreadyReplaced"
-	RateLimitedErr     = errNS + "rateLimited"
+	errNS                 = "urn:ietf:params:acme:error:"
+	BadNonceErr           = errNS + "badNonce"
+	AlreadyReplacedErr    = errNS + "alreadyReplaced"
+	RateLimitedErr        = errNS + "rateLimited"
+	UserActionRequiredErr = errNS + "userActionRequired"
+	CAAErr                = errNS + "caa"
+	DNSErr                = errNS + "dns"
+	UnauthorizedErr       = errNS + "unauthorized"
+	ConnectionErr         = errNS + "connection"
 )
 
 // ProblemDetails the problem details object.
@@ -91,3 +96,19 @@ type RateLimitedError struct {
 func (e *RateLimitedError) Unwrap() error {
 	return e.ProblemDetails
 }
+
+// UserActionRequiredError represents the error which is returned
+// if the server requires an action from the user (ex: the terms of service have changed).
+// The Instance field of the problem details contains the URL of the page describing the action.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.3
+type UserActionRequiredError struct {
+	*ProblemDetails
+
+	// TermsOfServiceURL the URL of the new terms of service (from the `Link` header with the relation `terms-of-service`).
+	// Empty if the action is not related to the terms of service.
+	TermsOfServiceURL string
+}
+
+func (e *UserActionRequiredError) Unwrap() error {
+	return e.ProblemDetails
+}
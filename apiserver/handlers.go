@@ -0,0 +1,347 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/storage"
+)
+
+// CertificateRequest the body of a certificate request.
+type CertificateRequest struct {
+	Domains []string `json:"domains"`
+	Profile string   `json:"profile,omitempty"`
+}
+
+// RevokeRequest the body of a revocation request.
+type RevokeRequest struct {
+	// Reason the revocation reason code (RFC 5280, section 5.3.1).
+	Reason *uint `json:"reason,omitempty"`
+}
+
+// Certificate the status of a certificate.
+type Certificate struct {
+	ID      string   `json:"id"`
+	Domains []string `json:"domains"`
+	Profile string   `json:"profile,omitempty"`
+
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Renewing bool   `json:"renewing,omitempty"`
+
+	SerialNumber string    `json:"serialNumber,omitempty"`
+	NotBefore    time.Time `json:"notBefore,omitzero"`
+	NotAfter     time.Time `json:"notAfter,omitzero"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// APIError the body of an error response.
+type APIError struct {
+	Message string `json:"error"`
+}
+
+// Handler returns the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /v1/certificates", s.authenticated(s.createCertificate))
+	mux.HandleFunc("GET /v1/certificates", s.authenticated(s.listCertificates))
+	mux.HandleFunc("GET /v1/certificates/{id}", s.authenticated(s.getCertificate))
+	mux.HandleFunc("GET /v1/certificates/{id}/bundle", s.authenticated(s.getBundle))
+	mux.HandleFunc("POST /v1/certificates/{id}/revoke", s.authenticated(s.revokeCertificate))
+
+	return mux
+}
+
+type tenantHandlerFunc func(rw http.ResponseWriter, req *http.Request, tenant *Tenant)
+
+func (s *Server) authenticated(next tenantHandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			rw.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(rw, http.StatusUnauthorized, "missing bearer token")
+
+			return
+		}
+
+		tenant := s.tenants.authenticate(token)
+		if tenant == nil {
+			rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(rw, http.StatusUnauthorized, "invalid token")
+
+			return
+		}
+
+		next(rw, req, tenant)
+	}
+}
+
+func (s *Server) createCertificate(rw http.ResponseWriter, req *http.Request, tenant *Tenant) {
+	var body CertificateRequest
+
+	err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, 1<<20)).Decode(&body)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+
+	domains := make([]string, 0, len(body.Domains))
+
+	for _, domain := range body.Domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+
+		if domain == "" || slices.Contains(domains, domain) {
+			continue
+		}
+
+		if !tenant.Allowed(domain) {
+			writeError(rw, http.StatusForbidden, fmt.Sprintf("domain %q is not allowed", domain))
+			return
+		}
+
+		domains = append(domains, domain)
+	}
+
+	if len(domains) == 0 {
+		writeError(rw, http.StatusBadRequest, "no domains")
+		return
+	}
+
+	now := time.Now().UTC()
+
+	rec := &record{
+		ID:        certificateID(domains[0]),
+		Tenant:    tenant.Name,
+		Domains:   domains,
+		Profile:   body.Profile,
+		Status:    StatusPending,
+		CreatedAt: now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.store.get(req.Context(), tenant.Name, rec.ID)
+
+	switch {
+	case err == nil && (existing.Status == StatusPending || existing.Renewing):
+		writeError(rw, http.StatusConflict, fmt.Sprintf("certificate %q is already being obtained", rec.ID))
+		return
+
+	case err == nil && existing.Status == StatusValid:
+		// The current certificate stays available until the new one is obtained.
+		previous := *existing
+
+		rec = existing
+		rec.Domains = domains
+		rec.Profile = body.Profile
+		rec.Renewing = true
+
+		existing = &previous
+
+	case err == nil:
+		rec.CreatedAt = existing.CreatedAt
+
+	case errors.Is(err, storage.ErrNotExist):
+		existing = nil
+
+	default:
+		s.internalError(rw, err)
+		return
+	}
+
+	err = s.store.put(req.Context(), rec)
+	if err != nil {
+		s.internalError(rw, err)
+		return
+	}
+
+	if !s.enqueue(job{tenant: rec.Tenant, id: rec.ID}) {
+		s.rollback(req.Context(), rec, existing)
+
+		writeError(rw, http.StatusServiceUnavailable, "too many pending requests, retry later")
+
+		return
+	}
+
+	rw.Header().Set("Location", "/v1/certificates/"+rec.ID)
+	writeJSON(rw, http.StatusAccepted, rec.view())
+}
+
+// rollback restores the previous state of a record (or removes it) when a request cannot be queued.
+func (s *Server) rollback(ctx context.Context, rec, previous *record) {
+	var err error
+
+	if previous == nil {
+		err = s.store.backend.Remove(ctx, s.store.name(rec.Tenant, rec.ID))
+	} else {
+		err = s.store.put(ctx, previous)
+	}
+
+	if err != nil {
+		log.Warn("apiserver: could not restore the certificate.", "tenant", rec.Tenant, "id", rec.ID, "error", err)
+	}
+}
+
+func (s *Server) listCertificates(rw http.ResponseWriter, req *http.Request, tenant *Tenant) {
+	records, err := s.store.list(req.Context(), tenant.Name)
+	if err != nil {
+		s.internalError(rw, err)
+		return
+	}
+
+	certs := make([]Certificate, 0, len(records))
+	for _, rec := range records {
+		certs = append(certs, rec.view())
+	}
+
+	writeJSON(rw, http.StatusOK, certs)
+}
+
+func (s *Server) getCertificate(rw http.ResponseWriter, req *http.Request, tenant *Tenant) {
+	rec, ok := s.readRecord(rw, req, tenant)
+	if !ok {
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, rec.view())
+}
+
+func (s *Server) getBundle(rw http.ResponseWriter, req *http.Request, tenant *Tenant) {
+	rec, ok := s.readRecord(rw, req, tenant)
+	if !ok {
+		return
+	}
+
+	if rec.Status != StatusValid {
+		writeError(rw, http.StatusConflict, fmt.Sprintf("certificate %q is %s", rec.ID, rec.Status))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-pem-file")
+	rw.WriteHeader(http.StatusOK)
+
+	_, _ = rw.Write(rec.Certificate)
+	_, _ = rw.Write(rec.PrivateKey)
+}
+
+func (s *Server) revokeCertificate(rw http.ResponseWriter, req *http.Request, tenant *Tenant) {
+	var body RevokeRequest
+
+	if req.ContentLength != 0 {
+		err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, 1<<20)).Decode(&body)
+		if err != nil {
+			writeError(rw, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+			return
+		}
+	}
+
+	rec, ok := s.readRecord(rw, req, tenant)
+	if !ok {
+		return
+	}
+
+	if rec.Status != StatusValid {
+		writeError(rw, http.StatusConflict, fmt.Sprintf("certificate %q is %s", rec.ID, rec.Status))
+		return
+	}
+
+	err := s.certifier.RevokeWithReason(rec.Certificate, body.Reason)
+	if err != nil {
+		writeError(rw, http.StatusBadGateway, fmt.Sprintf("revoke: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+
+	// Reloads the record: it may have been renewed in the meantime.
+	current, err := s.store.get(req.Context(), tenant.Name, rec.ID)
+	if err == nil {
+		rec = current
+	}
+
+	rec.Status = StatusRevoked
+	rec.Error = ""
+
+	err = s.store.put(req.Context(), rec)
+
+	s.mu.Unlock()
+
+	if err != nil {
+		s.internalError(rw, err)
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, rec.view())
+}
+
+func (s *Server) readRecord(rw http.ResponseWriter, req *http.Request, tenant *Tenant) (*record, bool) {
+	id := req.PathValue("id")
+
+	if !validID(id) {
+		writeError(rw, http.StatusNotFound, "certificate not found")
+		return nil, false
+	}
+
+	rec, err := s.store.get(req.Context(), tenant.Name, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			writeError(rw, http.StatusNotFound, "certificate not found")
+			return nil, false
+		}
+
+		s.internalError(rw, err)
+
+		return nil, false
+	}
+
+	return rec, true
+}
+
+func (s *Server) internalError(rw http.ResponseWriter, err error) {
+	log.Warn("apiserver: internal error.", "error", err)
+
+	writeError(rw, http.StatusInternalServerError, "internal error")
+}
+
+func (r *record) view() Certificate {
+	return Certificate{
+		ID:           r.ID,
+		Domains:      r.Domains,
+		Profile:      r.Profile,
+		Status:       r.Status,
+		Error:        r.Error,
+		Renewing:     r.Renewing,
+		SerialNumber: r.SerialNumber,
+		NotBefore:    r.NotBefore,
+		NotAfter:     r.NotAfter,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+// validID checks that an ID cannot escape the directory of the tenant.
+func validID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+func writeError(rw http.ResponseWriter, status int, msg string) {
+	writeJSON(rw, status, APIError{Message: msg})
+}
+
+func writeJSON(rw http.ResponseWriter, status int, data any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(data)
+}
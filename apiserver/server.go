@@ -0,0 +1,277 @@
+// Package apiserver provides a REST API to obtain, renew, and revoke certificates on behalf of several tenants
+// (certificate-as-a-service).
+//
+// Endpoints (authenticated with the bearer token of a tenant):
+//   - POST /v1/certificates: requests a certificate (`{"domains": ["example.com"], "profile": ""}`), the certificate is obtained asynchronously.
+//   - GET /v1/certificates: lists the certificates of the tenant.
+//   - GET /v1/certificates/{id}: returns the status of a certificate.
+//   - GET /v1/certificates/{id}/bundle: downloads the certificate chain and the private key (PEM).
+//   - POST /v1/certificates/{id}/revoke: revokes a certificate (`{"reason": 0}`).
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/storage"
+)
+
+// Certifier obtains and revokes the certificates (ex: *certificate.Certifier).
+type Certifier interface {
+	Obtain(request certificate.ObtainRequest) (*certificate.Resource, error)
+	RevokeWithReason(cert []byte, reason *uint) error
+}
+
+// Config the configuration of the Server.
+type Config struct {
+	Certifier Certifier
+	Tenants   Tenants
+
+	// Storage the backend storing the certificates, in the directory Dir.
+	Storage storage.Storage
+	Dir     string
+
+	// Bundle if true, the certificates are bundled with the issuer certificates.
+	Bundle bool
+
+	// RenewInterval the interval between two checks of the certificates to renew (default: 1 hour).
+	RenewInterval time.Duration
+
+	// QueueSize the maximum number of pending requests (default: 100).
+	QueueSize int
+}
+
+// Server the certificate API.
+//
+// The certificates are obtained one at a time by a single worker:
+// the challenge solvers (ex: the HTTP-01 server) cannot be shared by concurrent orders.
+type Server struct {
+	certifier     Certifier
+	tenants       Tenants
+	store         *store
+	bundle        bool
+	renewInterval time.Duration
+
+	queue chan job
+
+	// mu serializes the updates of the records.
+	mu sync.Mutex
+}
+
+type job struct {
+	tenant string
+	id     string
+}
+
+// New creates a new Server.
+func New(config Config) (*Server, error) {
+	if config.Certifier == nil {
+		return nil, errors.New("apiserver: missing certifier")
+	}
+
+	if len(config.Tenants) == 0 {
+		return nil, errors.New("apiserver: no tenant")
+	}
+
+	if config.Storage == nil {
+		config.Storage = storage.NewFileSystem()
+	}
+
+	if config.RenewInterval <= 0 {
+		config.RenewInterval = time.Hour
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = 100
+	}
+
+	return &Server{
+		certifier:     config.Certifier,
+		tenants:       config.Tenants,
+		store:         &store{backend: config.Storage, dir: config.Dir},
+		bundle:        config.Bundle,
+		renewInterval: config.RenewInterval,
+		queue:         make(chan job, config.QueueSize),
+	}, nil
+}
+
+// Run processes the requests and renews the certificates until the context is done.
+func (s *Server) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	wg.Go(func() { s.work(ctx) })
+
+	s.resume(ctx)
+
+	ticker := time.NewTicker(s.renewInterval)
+	defer ticker.Stop()
+
+	s.renewAll(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+
+			return nil
+
+		case <-ticker.C:
+			s.renewAll(ctx)
+		}
+	}
+}
+
+// enqueue adds a job to the queue, without blocking.
+func (s *Server) enqueue(j job) bool {
+	select {
+	case s.queue <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Server) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case j := <-s.queue:
+			s.process(ctx, j)
+		}
+	}
+}
+
+// process obtains (or renews) a certificate.
+func (s *Server) process(ctx context.Context, j job) {
+	rec, err := s.store.get(ctx, j.tenant, j.id)
+	if err != nil {
+		log.Warn("apiserver: could not read the certificate.", "tenant", j.tenant, "id", j.id, "error", err)
+		return
+	}
+
+	if rec.Status == StatusRevoked {
+		return
+	}
+
+	log.Info("apiserver: obtaining the certificate.", "tenant", rec.Tenant, "domains", rec.Domains)
+
+	res, err := s.certifier.Obtain(certificate.ObtainRequest{
+		Domains: rec.Domains,
+		Bundle:  s.bundle,
+		Profile: rec.Profile,
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The record may have been updated (ex: revoked) during the issuance.
+	current, errG := s.store.get(ctx, j.tenant, j.id)
+	if errG == nil {
+		rec = current
+	}
+
+	rec.Renewing = false
+
+	if err != nil {
+		log.Warn("apiserver: could not obtain the certificate.", "tenant", rec.Tenant, "domains", rec.Domains, "error", err)
+
+		rec.Error = err.Error()
+
+		if rec.Status != StatusValid {
+			rec.Status = StatusFailed
+		}
+	} else if rec.Status != StatusRevoked {
+		err = rec.setResource(res)
+		if err != nil {
+			rec.Status = StatusFailed
+			rec.Error = err.Error()
+		}
+	}
+
+	err = s.store.put(ctx, rec)
+	if err != nil {
+		log.Warn("apiserver: could not store the certificate.", "tenant", rec.Tenant, "id", rec.ID, "error", err)
+	}
+}
+
+// resume enqueues the pending requests (ex: after a restart).
+func (s *Server) resume(ctx context.Context) {
+	records, err := s.store.list(ctx, "")
+	if err != nil {
+		log.Warn("apiserver: could not list the certificates.", "error", err)
+		return
+	}
+
+	for _, rec := range records {
+		if rec.Status == StatusPending || rec.Renewing {
+			s.enqueue(job{tenant: rec.Tenant, id: rec.ID})
+		}
+	}
+}
+
+// renewAll enqueues the renewal of the certificates which expire soon.
+func (s *Server) renewAll(ctx context.Context) {
+	records, err := s.store.list(ctx, "")
+	if err != nil {
+		log.Warn("apiserver: could not list the certificates.", "error", err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, rec := range records {
+		if rec.Status != StatusValid || rec.Renewing || !needRenewal(rec, now) {
+			continue
+		}
+
+		s.mu.Lock()
+
+		rec.Renewing = true
+
+		err = s.store.put(ctx, rec)
+
+		s.mu.Unlock()
+
+		if err != nil {
+			log.Warn("apiserver: could not store the certificate.", "tenant", rec.Tenant, "id", rec.ID, "error", err)
+			continue
+		}
+
+		if !s.enqueue(job{tenant: rec.Tenant, id: rec.ID}) {
+			log.Warn("apiserver: the queue is full, the renewal is postponed.", "tenant", rec.Tenant, "id", rec.ID)
+		}
+	}
+}
+
+// needRenewal checks if a certificate must be renewed: when 1/3 of its lifetime is left.
+func needRenewal(rec *record, now time.Time) bool {
+	lifetime := rec.NotAfter.Sub(rec.NotBefore)
+
+	return rec.NotAfter.Sub(now) <= lifetime/3
+}
+
+func (r *record) setResource(res *certificate.Resource) error {
+	certs, err := certcrypto.ParsePEMBundle(res.Certificate)
+	if err != nil {
+		return fmt.Errorf("parse certificate: %w", err)
+	}
+
+	r.Status = StatusValid
+	r.Error = ""
+	r.Certificate = res.Certificate
+	r.PrivateKey = res.PrivateKey
+	r.IssuerCertificate = res.IssuerCertificate
+	r.SerialNumber = certs[0].SerialNumber.Text(16)
+	r.NotBefore = certs[0].NotBefore
+	r.NotAfter = certs[0].NotAfter
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/storage"
+)
+
+// Status the status of a certificate.
+type Status string
+
+// Statuses.
+const (
+	// StatusPending the certificate is being obtained (or renewed, if it has never been obtained).
+	StatusPending Status = "pending"
+
+	// StatusValid the certificate has been obtained.
+	StatusValid Status = "valid"
+
+	// StatusFailed the certificate could not be obtained.
+	StatusFailed Status = "failed"
+
+	// StatusRevoked the certificate has been revoked.
+	StatusRevoked Status = "revoked"
+)
+
+// record a certificate managed by the API, stored as JSON.
+type record struct {
+	ID      string   `json:"id"`
+	Tenant  string   `json:"tenant"`
+	Domains []string `json:"domains"`
+	Profile string   `json:"profile,omitempty"`
+
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// Renewing a renewal is in progress (the certificate stays valid).
+	Renewing bool `json:"renewing,omitempty"`
+
+	SerialNumber string    `json:"serialNumber,omitempty"`
+	NotBefore    time.Time `json:"notBefore,omitzero"`
+	NotAfter     time.Time `json:"notAfter,omitzero"`
+
+	Certificate       []byte `json:"certificate,omitempty"`
+	PrivateKey        []byte `json:"privateKey,omitempty"`
+	IssuerCertificate []byte `json:"issuerCertificate,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// store persists the records in a storage backend: `<dir>/<tenant>/<id>.json`.
+type store struct {
+	backend storage.Storage
+	dir     string
+}
+
+func (s *store) name(tenant, id string) string {
+	return path.Join(s.dir, tenant, id+".json")
+}
+
+func (s *store) get(ctx context.Context, tenant, id string) (*record, error) {
+	data, err := s.backend.ReadFile(ctx, s.name(tenant, id))
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &record{}
+
+	err = json.Unmarshal(data, rec)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s/%s: %w", tenant, id, err)
+	}
+
+	return rec, nil
+}
+
+func (s *store) put(ctx context.Context, rec *record) error {
+	rec.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(rec, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return s.backend.WriteFile(ctx, s.name(rec.Tenant, rec.ID), data)
+}
+
+// list lists the records of a tenant, or of all the tenants if tenant is empty.
+func (s *store) list(ctx context.Context, tenant string) ([]*record, error) {
+	dir := s.dir
+	if tenant != "" {
+		dir = path.Join(s.dir, tenant)
+	}
+
+	names, err := s.backend.List(ctx, dir)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var records []*record
+
+	prefix := filepath.ToSlash(filepath.Clean(s.dir)) + "/"
+
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		rel := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(name)), prefix)
+
+		t, file, ok := strings.Cut(rel, "/")
+		if !ok || strings.Contains(file, "/") {
+			continue
+		}
+
+		rec, err := s.get(ctx, t, strings.TrimSuffix(file, ".json"))
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// certificateID returns the ID of a certificate from its main domain.
+func certificateID(domain string) string {
+	return strings.ReplaceAll(strings.ToLower(domain), "*", "_")
+}
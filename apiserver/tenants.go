@@ -0,0 +1,125 @@
+package apiserver
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+var tenantNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Tenant a client of the API.
+type Tenant struct {
+	Name string `toml:"-"`
+
+	// Token the bearer token of the tenant.
+	Token string `toml:"token"`
+
+	// TokenFile a file containing the bearer token (used when Token is empty).
+	TokenFile string `toml:"token_file"`
+
+	// Domains the domains allowed for the tenant:
+	// a domain (`example.com`), or all the subdomains of a domain (`*.example.com`, including the wildcard names).
+	Domains []string `toml:"domains"`
+}
+
+// Allowed checks if a domain is allowed for the tenant.
+func (t *Tenant) Allowed(domain string) bool {
+	domain = strings.ToLower(domain)
+
+	for _, pattern := range t.Domains {
+		pattern = strings.ToLower(pattern)
+
+		if domain == pattern {
+			return true
+		}
+
+		base, ok := strings.CutPrefix(pattern, "*.")
+		if ok && strings.HasSuffix(domain, "."+base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tenants the tenants of the API.
+type Tenants []*Tenant
+
+// LoadTenants loads the tenants from a TOML file:
+//
+//	[tenants.team-a]
+//	token_file = "/etc/lego/team-a.token"
+//	domains = ["team-a.example.com", "*.team-a.example.com"]
+func LoadTenants(filename string) (Tenants, error) {
+	var file struct {
+		Tenants map[string]*Tenant `toml:"tenants"`
+	}
+
+	_, err := toml.DecodeFile(filename, &file)
+	if err != nil {
+		return nil, fmt.Errorf("apiserver: load tenants: %w", err)
+	}
+
+	var tenants Tenants
+
+	for _, name := range slices.Sorted(maps.Keys(file.Tenants)) {
+		tenant := file.Tenants[name]
+
+		if !tenantNameRe.MatchString(name) {
+			return nil, fmt.Errorf("apiserver: invalid tenant name %q", name)
+		}
+
+		tenant.Name = name
+
+		if tenant.Token == "" && tenant.TokenFile != "" {
+			token, err := os.ReadFile(tenant.TokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("apiserver: tenant %s: %w", name, err)
+			}
+
+			tenant.Token = strings.TrimSpace(string(token))
+		}
+
+		if tenant.Token == "" {
+			return nil, fmt.Errorf("apiserver: tenant %s: missing token", name)
+		}
+
+		if len(tenant.Domains) == 0 {
+			return nil, fmt.Errorf("apiserver: tenant %s: missing domains", name)
+		}
+
+		tenants = append(tenants, tenant)
+	}
+
+	if len(tenants) == 0 {
+		return nil, errors.New("apiserver: no tenant")
+	}
+
+	return tenants, nil
+}
+
+// authenticate returns the tenant of a bearer token.
+func (t Tenants) authenticate(token string) *Tenant {
+	if token == "" {
+		return nil
+	}
+
+	var found *Tenant
+
+	// All the tokens are compared, in constant time.
+	for _, tenant := range t {
+		if subtle.ConstantTimeCompare([]byte(tenant.Token), []byte(token)) == 1 {
+			found = tenant
+		}
+	}
+
+	return found
+}
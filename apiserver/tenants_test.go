@@ -0,0 +1,120 @@
+package apiserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenant_Allowed(t *testing.T) {
+	tenant := &Tenant{Domains: []string{"example.com", "*.team.example.org"}}
+
+	testCases := []struct {
+		domain   string
+		expected bool
+	}{
+		{domain: "example.com", expected: true},
+		{domain: "EXAMPLE.com", expected: true},
+		{domain: "www.example.com", expected: false},
+		{domain: "a.team.example.org", expected: true},
+		{domain: "a.b.team.example.org", expected: true},
+		{domain: "*.team.example.org", expected: true},
+		{domain: "team.example.org", expected: false},
+		{domain: "evilteam.example.org", expected: false},
+		{domain: "example.net", expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.domain, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, tenant.Allowed(test.domain))
+		})
+	}
+}
+
+func TestLoadTenants(t *testing.T) {
+	dir := t.TempDir()
+
+	tokenFile := filepath.Join(dir, "team-b.token")
+
+	err := os.WriteFile(tokenFile, []byte("secret-b\n"), 0o600)
+	require.NoError(t, err)
+
+	filename := filepath.Join(dir, "tenants.toml")
+
+	err = os.WriteFile(filename, []byte(`
+[tenants.team-b]
+token_file = "`+filepath.ToSlash(tokenFile)+`"
+domains = ["*.team-b.example.com"]
+
+[tenants.team-a]
+token = "secret-a"
+domains = ["team-a.example.com", "*.team-a.example.com"]
+`), 0o600)
+	require.NoError(t, err)
+
+	tenants, err := LoadTenants(filename)
+	require.NoError(t, err)
+
+	require.Len(t, tenants, 2)
+
+	assert.Equal(t, "team-a", tenants[0].Name)
+	assert.Equal(t, "secret-a", tenants[0].Token)
+	assert.Equal(t, "team-b", tenants[1].Name)
+	assert.Equal(t, "secret-b", tenants[1].Token)
+
+	assert.Equal(t, tenants[1], tenants.authenticate("secret-b"))
+	assert.Nil(t, tenants.authenticate("secret"))
+	assert.Nil(t, tenants.authenticate(""))
+}
+
+func TestLoadTenants_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "no tenant",
+			content:  ``,
+			expected: "apiserver: no tenant",
+		},
+		{
+			desc: "invalid name",
+			content: `[tenants."team a"]
+token = "secret"
+domains = ["example.com"]`,
+			expected: `apiserver: invalid tenant name "team a"`,
+		},
+		{
+			desc: "missing token",
+			content: `[tenants.team]
+domains = ["example.com"]`,
+			expected: "apiserver: tenant team: missing token",
+		},
+		{
+			desc: "missing domains",
+			content: `[tenants.team]
+token = "secret"`,
+			expected: "apiserver: tenant team: missing domains",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filename := filepath.Join(t.TempDir(), "tenants.toml")
+
+			err := os.WriteFile(filename, []byte(test.content), 0o600)
+			require.NoError(t, err)
+
+			_, err = LoadTenants(filename)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package audit provides an append-only log of the certificate lifecycle events.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Actions.
+const (
+	ActionObtain = "obtain"
+	ActionRenew  = "renew"
+	ActionRevoke = "revoke"
+)
+
+// Results.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Event is an audit log entry.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Account  string    `json:"account,omitempty"`
+	Domains  []string  `json:"domains,omitempty"`
+	OrderURL string    `json:"orderUrl,omitempty"`
+	CertURL  string    `json:"certUrl,omitempty"`
+	Serial   string    `json:"serial,omitempty"`
+	Result   string    `json:"result"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Logger writes the audit events.
+// The implementations must be safe for concurrent use.
+type Logger interface {
+	Log(event Event) error
+}
+
+// Open creates a Logger from a target:
+//   - "syslog:" for the local syslog.
+//   - "syslog://host:port" (UDP) or "syslog+tcp://host:port" for a remote syslog.
+//   - a file path otherwise.
+func Open(target string) (Logger, error) {
+	switch {
+	case target == "":
+		return nil, errors.New("audit: missing target")
+
+	case target == "syslog:":
+		return openSyslog("", "")
+
+	case strings.HasPrefix(target, "syslog://"), strings.HasPrefix(target, "syslog+tcp://"):
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("audit: %w", err)
+		}
+
+		network := "udp"
+		if u.Scheme == "syslog+tcp" {
+			network = "tcp"
+		}
+
+		return openSyslog(network, u.Host)
+
+	default:
+		logger, err := NewFileLogger(target)
+		if err != nil {
+			return nil, err
+		}
+
+		return logger, nil
+	}
+}
+
+func openSyslog(network, raddr string) (Logger, error) {
+	logger, err := NewSyslogLogger(network, raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return logger, nil
+}
+
+// FileLogger writes the events as JSON lines, appended to a file.
+type FileLogger struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileLogger opens (or creates) a file in append mode.
+func NewFileLogger(filename string) (*FileLogger, error) {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("audit: %w", err)
+	}
+
+	return &FileLogger{file: file}, nil
+}
+
+// Log writes an event, and flushes it to the disk.
+func (l *FileLogger) Log(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("audit: marshal event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err = l.file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("audit: write event: %w", err)
+	}
+
+	err = l.file.Sync()
+	if err != nil {
+		return fmt.Errorf("audit: sync: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the file.
+func (l *FileLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
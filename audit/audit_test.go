@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLogger_Log(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	events := []Event{
+		{
+			Time:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Action:   ActionObtain,
+			Account:  "https://example.com/acme/acct/1",
+			Domains:  []string{"example.com", "*.example.com"},
+			OrderURL: "https://example.com/acme/order/1",
+			CertURL:  "https://example.com/acme/cert/1",
+			Serial:   "3e1724a96e5f3c",
+			Result:   ResultSuccess,
+		},
+		{
+			Time:    time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Action:  ActionRevoke,
+			Domains: []string{"example.com"},
+			Result:  ResultFailure,
+			Error:   "boom",
+		},
+	}
+
+	// The events are appended to the existing content.
+	for _, event := range events {
+		logger, err := NewFileLogger(filename)
+		require.NoError(t, err)
+
+		err = logger.Log(event)
+		require.NoError(t, err)
+
+		require.NoError(t, logger.Close())
+	}
+
+	file, err := os.Open(filename)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	var got []Event
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		require.NoError(t, err)
+
+		got = append(got, event)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, events, got)
+}
+
+func TestOpen(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	logger, err := Open(filename)
+	require.NoError(t, err)
+
+	assert.IsType(t, &FileLogger{}, logger)
+}
+
+func TestOpen_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		target   string
+		expected string
+	}{
+		{
+			desc:     "empty",
+			target:   "",
+			expected: "audit: missing target",
+		},
+		{
+			desc:     "missing directory",
+			target:   filepath.Join(t.TempDir(), "missing", "audit.log"),
+			expected: "audit: open ",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			logger, err := Open(test.target)
+			require.ErrorContains(t, err, test.expected)
+
+			assert.Nil(t, logger)
+		})
+	}
+}
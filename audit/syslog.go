@@ -0,0 +1,52 @@
+//go:build !windows && !plan9
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+)
+
+const syslogTag = "lego"
+
+// SyslogLogger writes the events as JSON messages to syslog.
+type SyslogLogger struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogLogger connects to a syslog daemon.
+// If network and raddr are empty, it connects to the local syslog.
+func NewSyslogLogger(network, raddr string) (*SyslogLogger, error) {
+	writer, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_AUTHPRIV, syslogTag)
+	if err != nil {
+		return nil, fmt.Errorf("audit: syslog: %w", err)
+	}
+
+	return &SyslogLogger{writer: writer}, nil
+}
+
+// Log writes an event: failures are written with the error severity.
+func (l *SyslogLogger) Log(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("audit: marshal event: %w", err)
+	}
+
+	if event.Result == ResultFailure {
+		err = l.writer.Err(string(data))
+	} else {
+		err = l.writer.Info(string(data))
+	}
+
+	if err != nil {
+		return fmt.Errorf("audit: syslog: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (l *SyslogLogger) Close() error {
+	return l.writer.Close()
+}
@@ -0,0 +1,23 @@
+//go:build windows || plan9
+
+package audit
+
+import "errors"
+
+// SyslogLogger is not supported on this platform.
+type SyslogLogger struct{}
+
+// NewSyslogLogger is not supported on this platform.
+func NewSyslogLogger(_, _ string) (*SyslogLogger, error) {
+	return nil, errors.New("audit: syslog is not supported on this platform")
+}
+
+// Log is not supported on this platform.
+func (l *SyslogLogger) Log(Event) error {
+	return errors.New("audit: syslog is not supported on this platform")
+}
+
+// Close is not supported on this platform.
+func (l *SyslogLogger) Close() error {
+	return nil
+}
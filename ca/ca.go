@@ -0,0 +1,220 @@
+// Package ca provides presets of ACME certificate authorities (directory URLs),
+// and the bootstrap of the External Account Binding (EAB) credentials from the REST API of the CAs that offer it.
+package ca
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/digicert/lego/v4/ca/digicert"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/lego"
+)
+
+// Preset names.
+const (
+	LetsEncrypt = "letsencrypt"
+	ZeroSSL     = "zerossl"
+	Buypass     = "buypass"
+	Google      = "google"
+	DigiCert    = "digicert"
+)
+
+// EAB the External Account Binding credentials.
+type EAB struct {
+	KeyID   string
+	HMACKey string
+}
+
+// EABOptions the options to fetch the EAB credentials.
+type EABOptions struct {
+	// APIKey the credential of the account:
+	//   - ZeroSSL: the access key (optional if Email is defined).
+	//   - Google Trust Services: the Google Cloud project ID (the credentials are the Application Default Credentials).
+	//   - DigiCert: the CertCentral API key.
+	APIKey string
+
+	// Email the email of the account (ZeroSSL without API key).
+	Email string
+
+	// Organization the ID or the name of the organization (DigiCert).
+	Organization string
+
+	// Product the name ID of the product (DigiCert).
+	Product string
+
+	// HTTPClient the HTTP client used to call the API (optional).
+	HTTPClient *http.Client
+}
+
+// Preset an ACME certificate authority.
+type Preset struct {
+	Name string
+
+	DirectoryURL string
+
+	// StagingDirectoryURL the directory of the test environment (empty if the CA doesn't have one).
+	StagingDirectoryURL string
+
+	// EABRequired the CA requires EAB credentials to register an account.
+	EABRequired bool
+
+	// KeyPolicy the private keys accepted by the CA for the certificates (nil if unknown).
+	KeyPolicy *certcrypto.KeyPolicy
+
+	eab func(ctx context.Context, staging bool, opts EABOptions) (*EAB, error)
+}
+
+// Directory returns the directory URL of the production, or of the test environment.
+func (p *Preset) Directory(staging bool) (string, error) {
+	if !staging {
+		return p.DirectoryURL, nil
+	}
+
+	if p.StagingDirectoryURL == "" {
+		return "", fmt.Errorf("ca: %s: no staging environment", p.Name)
+	}
+
+	return p.StagingDirectoryURL, nil
+}
+
+// SupportsEAB checks if the EAB credentials can be fetched from the API of the CA.
+func (p *Preset) SupportsEAB() bool {
+	return p.eab != nil
+}
+
+// ExternalAccountBinding fetches new EAB credentials from the API of the CA.
+func (p *Preset) ExternalAccountBinding(ctx context.Context, staging bool, opts EABOptions) (*EAB, error) {
+	if p.eab == nil {
+		return nil, fmt.Errorf("ca: %s: the EAB credentials cannot be fetched", p.Name)
+	}
+
+	eab, err := p.eab(ctx, staging, opts)
+	if err != nil {
+		return nil, fmt.Errorf("ca: %s: %w", p.Name, err)
+	}
+
+	return eab, nil
+}
+
+// Presets returns the presets, sorted by name.
+func Presets() []*Preset {
+	presets := []*Preset{
+		{
+			Name:                LetsEncrypt,
+			DirectoryURL:        lego.LEDirectoryProduction,
+			StagingDirectoryURL: lego.LEDirectoryStaging,
+			// https://letsencrypt.org/docs/integration-guide/#supported-key-algorithms
+			KeyPolicy: &certcrypto.KeyPolicy{
+				RSAMinBits:      2048,
+				RSAMaxBits:      4096,
+				RSABitsMultiple: 1024,
+				RSAExponents:    []int{certcrypto.RSADefaultExponent},
+				Curves:          []string{"P-256", "P-384"},
+			},
+		},
+		{
+			Name:         ZeroSSL,
+			DirectoryURL: "https://acme.zerossl.com/v2/DV90",
+			EABRequired:  true,
+			eab: func(ctx context.Context, _ bool, opts EABOptions) (*EAB, error) {
+				return zeroSSLEAB(ctx, zeroSSLBaseURL, opts)
+			},
+		},
+		{
+			Name:                Buypass,
+			DirectoryURL:        "https://api.buypass.com/acme/directory",
+			StagingDirectoryURL: "https://api.test4.buypass.no/acme/directory",
+		},
+		{
+			Name:                Google,
+			DirectoryURL:        "https://dv.acme-v02.api.pki.goog/directory",
+			StagingDirectoryURL: "https://dv.acme-v02.test-api.pki.goog/directory",
+			EABRequired:         true,
+			eab: func(ctx context.Context, staging bool, opts EABOptions) (*EAB, error) {
+				endpoint := googleBaseURL
+				if staging {
+					endpoint = googleStagingBaseURL
+				}
+
+				return googleEAB(ctx, endpoint, opts)
+			},
+		},
+		{
+			Name:         DigiCert,
+			DirectoryURL: digicert.DirectoryURL,
+			EABRequired:  true,
+			eab: func(ctx context.Context, _ bool, opts EABOptions) (*EAB, error) {
+				return digiCertEAB(ctx, nil, opts)
+			},
+		},
+	}
+
+	slices.SortFunc(presets, func(a, b *Preset) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return presets
+}
+
+// Get returns a preset by name.
+func Get(name string) (*Preset, error) {
+	name = strings.ToLower(name)
+
+	for _, preset := range Presets() {
+		if preset.Name == name {
+			return preset, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ca: unknown CA %q (supported: %s)", name, strings.Join(Names(), ", "))
+}
+
+// GetByDirectory returns the preset of a directory URL (production or test environment), or nil if unknown.
+func GetByDirectory(dirURL string) *Preset {
+	for _, preset := range Presets() {
+		if dirURL == preset.DirectoryURL || preset.StagingDirectoryURL != "" && dirURL == preset.StagingDirectoryURL {
+			return preset
+		}
+	}
+
+	return nil
+}
+
+// Names returns the names of the presets.
+func Names() []string {
+	var names []string
+	for _, preset := range Presets() {
+		names = append(names, preset.Name)
+	}
+
+	return names
+}
+
+func digiCertEAB(ctx context.Context, client *digicert.Client, opts EABOptions) (*EAB, error) {
+	if client == nil {
+		var err error
+
+		client, err = digicert.NewClient(opts.APIKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.HTTPClient != nil {
+			client.HTTPClient = opts.HTTPClient
+		}
+	}
+
+	result, err := client.ExternalAccountBinding(ctx, digicert.Options{
+		Organization: opts.Organization,
+		Product:      opts.Product,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EAB{KeyID: result.KeyID, HMACKey: result.HMACKey}, nil
+}
@@ -0,0 +1,148 @@
+package ca
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/digicert/lego/v4/ca/digicert"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+)
+
+func TestGet(t *testing.T) {
+	preset, err := Get("ZeroSSL")
+	require.NoError(t, err)
+
+	assert.Equal(t, ZeroSSL, preset.Name)
+	assert.True(t, preset.EABRequired)
+	assert.True(t, preset.SupportsEAB())
+
+	_, err = Get("example")
+	require.EqualError(t, err, `ca: unknown CA "example" (supported: buypass, digicert, google, letsencrypt, zerossl)`)
+}
+
+func TestPreset_Directory(t *testing.T) {
+	preset, err := Get(LetsEncrypt)
+	require.NoError(t, err)
+
+	dirURL, err := preset.Directory(true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://acme-staging-v02.api.letsencrypt.org/directory", dirURL)
+
+	preset, err = Get(ZeroSSL)
+	require.NoError(t, err)
+
+	_, err = preset.Directory(true)
+	require.EqualError(t, err, "ca: zerossl: no staging environment")
+}
+
+func TestGetByDirectory(t *testing.T) {
+	preset := GetByDirectory("https://acme-staging-v02.api.letsencrypt.org/directory")
+	require.NotNil(t, preset)
+
+	assert.Equal(t, LetsEncrypt, preset.Name)
+	require.NotNil(t, preset.KeyPolicy)
+
+	require.NoError(t, preset.KeyPolicy.Check(certcrypto.EC256))
+	require.EqualError(t, preset.KeyPolicy.Check(certcrypto.EC521), "ECDSA curve P-521 not accepted by the CA (accepted: [P-256 P-384])")
+
+	assert.Nil(t, GetByDirectory("https://acme.example.com/directory"))
+}
+
+func TestPreset_ExternalAccountBinding_unsupported(t *testing.T) {
+	preset, err := Get(Buypass)
+	require.NoError(t, err)
+
+	assert.False(t, preset.SupportsEAB())
+
+	_, err = preset.ExternalAccountBinding(t.Context(), false, EABOptions{APIKey: "secret"})
+	require.EqualError(t, err, "ca: buypass: the EAB credentials cannot be fetched")
+}
+
+func Test_zeroSSLEAB(t *testing.T) {
+	server := servermock.NewBuilder[*httptest.Server](
+		func(server *httptest.Server) (*httptest.Server, error) { return server, nil }).
+		Route("POST /acme/eab-credentials",
+			servermock.RawStringResponse(`{"success": true, "eab_kid": "kid-1", "eab_hmac_key": "aG1hYw"}`),
+			servermock.CheckQueryParameter().Strict().With("access_key", "secret")).
+		Route("POST /acme/eab-credentials-email",
+			servermock.RawStringResponse(`{"success": true, "eab_kid": "kid-2", "eab_hmac_key": "aG1hYw"}`),
+			servermock.CheckForm().UsePostForm().Strict().With("email", "you@example.com")).
+		Build(t)
+
+	eab, err := zeroSSLEAB(t.Context(), server.URL, EABOptions{APIKey: "secret", HTTPClient: server.Client()})
+	require.NoError(t, err)
+
+	assert.Equal(t, &EAB{KeyID: "kid-1", HMACKey: "aG1hYw"}, eab)
+
+	eab, err = zeroSSLEAB(t.Context(), server.URL, EABOptions{Email: "you@example.com", HTTPClient: server.Client()})
+	require.NoError(t, err)
+
+	assert.Equal(t, &EAB{KeyID: "kid-2", HMACKey: "aG1hYw"}, eab)
+
+	_, err = zeroSSLEAB(t.Context(), server.URL, EABOptions{})
+	require.EqualError(t, err, "an access key or an email is required")
+}
+
+func Test_zeroSSLEAB_error(t *testing.T) {
+	server := servermock.NewBuilder[*httptest.Server](
+		func(server *httptest.Server) (*httptest.Server, error) { return server, nil }).
+		Route("POST /acme/eab-credentials",
+			servermock.RawStringResponse(`{"success": false, "error": {"code": 101, "type": "invalid_access_key", "info": "You have not supplied a valid API Access Key."}}`)).
+		Build(t)
+
+	_, err := zeroSSLEAB(t.Context(), server.URL, EABOptions{APIKey: "secret", HTTPClient: server.Client()})
+	require.EqualError(t, err, "generate EAB credentials: 101: invalid_access_key: You have not supplied a valid API Access Key.")
+}
+
+func Test_googleEAB(t *testing.T) {
+	server := servermock.NewBuilder[*httptest.Server](
+		func(server *httptest.Server) (*httptest.Server, error) { return server, nil }).
+		// The MAC key is encoded twice (bytes field).
+		Route("POST /v1/projects/my-project/locations/global/externalAccountKeys",
+			servermock.RawStringResponse(`{"name": "projects/my-project/locations/global/externalAccountKeys/1", "keyId": "kid-1", "b64MacKey": "YUcxaFl3"}`)).
+		Build(t)
+
+	eab, err := googleEAB(t.Context(), server.URL+"/", EABOptions{APIKey: "my-project", HTTPClient: server.Client()},
+		option.WithoutAuthentication())
+	require.NoError(t, err)
+
+	assert.Equal(t, &EAB{KeyID: "kid-1", HMACKey: "aG1hYw"}, eab)
+
+	_, err = googleEAB(t.Context(), server.URL+"/", EABOptions{})
+	require.EqualError(t, err, "the Google Cloud project ID is required")
+}
+
+func Test_digiCertEAB(t *testing.T) {
+	client := servermock.NewBuilder[*digicert.Client](
+		func(server *httptest.Server) (*digicert.Client, error) {
+			client, err := digicert.NewClient("secret")
+			if err != nil {
+				return nil, err
+			}
+
+			client.BaseURL, _ = url.Parse(server.URL + "/services/v2/")
+			client.HTTPClient = server.Client()
+
+			return client, nil
+		},
+		servermock.CheckHeader().With("X-DC-DEVKEY", "secret")).
+		Route("GET /services/v2/organization",
+			servermock.RawStringResponse(`{"organizations": [{"id": 1, "name": "Example Inc.", "status": "active"}]}`)).
+		Route("GET /services/v2/product",
+			servermock.RawStringResponse(`{"products": [{"name_id": "ssl_plus", "name": "Standard SSL"}]}`)).
+		Route("POST /services/v2/key/acme-url",
+			servermock.RawStringResponse(`{"id": 42, "kid": "kid-1", "hmac_key": "aG1hYw"}`).WithStatusCode(http.StatusCreated)).
+		Build(t)
+
+	eab, err := digiCertEAB(t.Context(), client, EABOptions{APIKey: "secret"})
+	require.NoError(t, err)
+
+	assert.Equal(t, &EAB{KeyID: "kid-1", HMACKey: "aG1hYw"}, eab)
+}
@@ -0,0 +1,244 @@
+// Package digicert provisions the External Account Binding (EAB) credentials of the DigiCert ACME service
+// from a CertCentral API key.
+package digicert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DirectoryURL the URL of the DigiCert ACME directory.
+const DirectoryURL = "https://acme.digicert.com/v2/acme/directory/"
+
+// DefaultBaseURL the URL of the CertCentral Services API.
+const DefaultBaseURL = "https://www.digicert.com/services/v2/"
+
+// DefaultProduct the default product (Standard SSL).
+const DefaultProduct = "ssl_plus"
+
+// Options the options to provision the EAB credentials.
+type Options struct {
+	// Organization the ID or the name of the organization.
+	// Optional if the account has only one active organization.
+	Organization string
+
+	// Product the name ID of the product (default: ssl_plus).
+	Product string
+
+	// Name the name of the ACME directory URL, as displayed in CertCentral (default: "lego").
+	Name string
+
+	// ValidityDays the validity of the certificates, in days (default: the maximum validity of the product).
+	ValidityDays int
+}
+
+// Client a CertCentral API client.
+type Client struct {
+	apiKey string
+
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(apiKey string) (*Client, error) {
+	if apiKey == "" {
+		return nil, errors.New("digicert: missing API key")
+	}
+
+	baseURL, _ := url.Parse(DefaultBaseURL)
+
+	return &Client{
+		apiKey:     apiKey,
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Organizations lists the organizations of the account.
+func (c *Client) Organizations(ctx context.Context) ([]Organization, error) {
+	var result organizationsResponse
+
+	err := c.do(ctx, http.MethodGet, c.BaseURL.JoinPath("organization"), nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("digicert: list organizations: %w", err)
+	}
+
+	return result.Organizations, nil
+}
+
+// Products lists the products available for the account.
+func (c *Client) Products(ctx context.Context) ([]Product, error) {
+	var result productsResponse
+
+	err := c.do(ctx, http.MethodGet, c.BaseURL.JoinPath("product"), nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("digicert: list products: %w", err)
+	}
+
+	return result.Products, nil
+}
+
+// CreateACMEURL creates an ACME directory URL, and its EAB credentials.
+func (c *Client) CreateACMEURL(ctx context.Context, request ACMEURLRequest) (*ACMEURL, error) {
+	var result ACMEURL
+
+	err := c.do(ctx, http.MethodPost, c.BaseURL.JoinPath("key", "acme-url"), request, &result)
+	if err != nil {
+		return nil, fmt.Errorf("digicert: create ACME URL: %w", err)
+	}
+
+	if result.KeyID == "" || result.HMACKey == "" {
+		return nil, errors.New("digicert: create ACME URL: missing EAB credentials in the response")
+	}
+
+	if result.DirectoryURL == "" {
+		result.DirectoryURL = DirectoryURL
+	}
+
+	return &result, nil
+}
+
+// ExternalAccountBinding selects the organization and the product, then creates an ACME directory URL, and its EAB credentials.
+func (c *Client) ExternalAccountBinding(ctx context.Context, opts Options) (*ACMEURL, error) {
+	org, err := c.findOrganization(ctx, opts.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	product := opts.Product
+	if product == "" {
+		product = DefaultProduct
+	}
+
+	err = c.checkProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = "lego"
+	}
+
+	return c.CreateACMEURL(ctx, ACMEURLRequest{
+		Name:           name,
+		ProductNameID:  product,
+		OrganizationID: org.ID,
+		ValidityDays:   opts.ValidityDays,
+	})
+}
+
+func (c *Client) findOrganization(ctx context.Context, value string) (*Organization, error) {
+	orgs, err := c.Organizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []Organization
+
+	for _, org := range orgs {
+		if value != "" && (strconv.Itoa(org.ID) == value || strings.EqualFold(org.Name, value)) {
+			return &org, nil
+		}
+
+		if org.Status == "" || org.Status == "active" {
+			active = append(active, org)
+		}
+	}
+
+	if value != "" {
+		return nil, fmt.Errorf("digicert: organization %q not found", value)
+	}
+
+	if len(active) != 1 {
+		names := make([]string, 0, len(active))
+		for _, org := range active {
+			names = append(names, fmt.Sprintf("%d (%s)", org.ID, org.Name))
+		}
+
+		return nil, fmt.Errorf("digicert: %d active organizations, an organization must be selected: %s",
+			len(active), strings.Join(names, ", "))
+	}
+
+	return &active[0], nil
+}
+
+func (c *Client) checkProduct(ctx context.Context, nameID string) error {
+	products, err := c.Products(ctx)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+
+	for _, product := range products {
+		if product.NameID == nameID {
+			return nil
+		}
+
+		names = append(names, product.NameID)
+	}
+
+	return fmt.Errorf("digicert: product %q not available: %s", nameID, strings.Join(names, ", "))
+}
+
+func (c *Client) do(ctx context.Context, method string, endpoint *url.URL, payload, result any) error {
+	body := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(body).Encode(payload)
+		if err != nil {
+			return fmt.Errorf("create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("X-DC-DEVKEY", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		apiErr := &APIError{}
+		if json.Unmarshal(raw, apiErr) == nil && len(apiErr.Errors) > 0 {
+			return apiErr
+		}
+
+		return fmt.Errorf("unexpected status code: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("unmarshal response: %w: %s", err, string(raw))
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package digicert
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient("secret")
+			if err != nil {
+				return nil, err
+			}
+
+			client.BaseURL, _ = url.Parse(server.URL + "/services/v2/")
+			client.HTTPClient = server.Client()
+
+			return client, nil
+		},
+		servermock.CheckHeader().
+			With("X-DC-DEVKEY", "secret").
+			WithAccept("application/json"),
+	)
+}
+
+const (
+	organizationsJSON = `{"organizations": [
+	{"id": 112233, "name": "Example Inc.", "status": "active"},
+	{"id": 445566, "name": "Old Inc.", "status": "inactive"}
+]}`
+
+	productsJSON = `{"products": [
+	{"name_id": "ssl_plus", "name": "Standard SSL", "type": "ssl_certificate"},
+	{"name_id": "ssl_ev_basic", "name": "Basic EV", "type": "ssl_certificate"}
+]}`
+)
+
+func TestClient_ExternalAccountBinding(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /services/v2/organization", servermock.RawStringResponse(organizationsJSON)).
+		Route("GET /services/v2/product", servermock.RawStringResponse(productsJSON)).
+		Route("POST /services/v2/key/acme-url",
+			servermock.RawStringResponse(`{"id": 42, "acme_directory_url": "https://acme.digicert.com/v2/acme/directory/", "kid": "kid-1", "hmac_key": "aG1hYw"}`).
+				WithStatusCode(http.StatusCreated),
+			servermock.CheckRequestJSONBody(`{"name":"lego","product_name_id":"ssl_ev_basic","organization_id":112233}`)).
+		Build(t)
+
+	eab, err := client.ExternalAccountBinding(t.Context(), Options{Product: "ssl_ev_basic"})
+	require.NoError(t, err)
+
+	expected := &ACMEURL{
+		ID:           42,
+		DirectoryURL: DirectoryURL,
+		KeyID:        "kid-1",
+		HMACKey:      "aG1hYw",
+	}
+
+	assert.Equal(t, expected, eab)
+}
+
+func TestClient_ExternalAccountBinding_organization(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /services/v2/organization", servermock.RawStringResponse(`{"organizations": [
+	{"id": 1, "name": "A", "status": "active"},
+	{"id": 2, "name": "B", "status": "active"}
+]}`)).
+		Route("GET /services/v2/product", servermock.RawStringResponse(productsJSON)).
+		Route("POST /services/v2/key/acme-url",
+			servermock.RawStringResponse(`{"id": 42, "kid": "kid-1", "hmac_key": "aG1hYw"}`),
+			servermock.CheckRequestJSONBody(`{"name":"prod","product_name_id":"ssl_plus","organization_id":2,"validity_days":90}`)).
+		Build(t)
+
+	_, err := client.ExternalAccountBinding(t.Context(), Options{})
+	require.EqualError(t, err, "digicert: 2 active organizations, an organization must be selected: 1 (A), 2 (B)")
+
+	_, err = client.ExternalAccountBinding(t.Context(), Options{Organization: "C"})
+	require.EqualError(t, err, `digicert: organization "C" not found`)
+
+	eab, err := client.ExternalAccountBinding(t.Context(), Options{Organization: "b", Name: "prod", ValidityDays: 90})
+	require.NoError(t, err)
+
+	assert.Equal(t, DirectoryURL, eab.DirectoryURL)
+	assert.Equal(t, "kid-1", eab.KeyID)
+}
+
+func TestClient_ExternalAccountBinding_product(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /services/v2/organization", servermock.RawStringResponse(organizationsJSON)).
+		Route("GET /services/v2/product", servermock.RawStringResponse(productsJSON)).
+		Build(t)
+
+	_, err := client.ExternalAccountBinding(t.Context(), Options{Product: "ssl_multi_domain"})
+	require.EqualError(t, err, `digicert: product "ssl_multi_domain" not available: ssl_plus, ssl_ev_basic`)
+}
+
+func TestClient_Organizations_error(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /services/v2/organization",
+			servermock.RawStringResponse(`{"errors": [{"code": "access_denied", "message": "Invalid API key."}]}`).
+				WithStatusCode(http.StatusUnauthorized)).
+		Build(t)
+
+	_, err := client.Organizations(t.Context())
+	require.EqualError(t, err, "digicert: list organizations: access_denied: Invalid API key.")
+}
+
+func TestClient_CreateACMEURL_missingCredentials(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /services/v2/key/acme-url", servermock.RawStringResponse(`{"id": 42}`)).
+		Build(t)
+
+	_, err := client.CreateACMEURL(t.Context(), ACMEURLRequest{Name: "lego", ProductNameID: "ssl_plus", OrganizationID: 1})
+	require.EqualError(t, err, "digicert: create ACME URL: missing EAB credentials in the response")
+}
+
+func TestNewClient_missingAPIKey(t *testing.T) {
+	_, err := NewClient("")
+	require.EqualError(t, err, "digicert: missing API key")
+}
@@ -0,0 +1,61 @@
+package digicert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Organization a CertCentral organization.
+type Organization struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+}
+
+// Product a CertCentral product.
+type Product struct {
+	NameID string `json:"name_id"`
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+}
+
+// ACMEURLRequest the request to create an ACME directory URL.
+type ACMEURLRequest struct {
+	Name           string `json:"name"`
+	ProductNameID  string `json:"product_name_id"`
+	OrganizationID int    `json:"organization_id"`
+	ValidityDays   int    `json:"validity_days,omitempty"`
+}
+
+// ACMEURL an ACME directory URL, and its External Account Binding credentials.
+type ACMEURL struct {
+	ID           int    `json:"id"`
+	DirectoryURL string `json:"acme_directory_url"`
+	KeyID        string `json:"kid"`
+	HMACKey      string `json:"hmac_key"`
+}
+
+type organizationsResponse struct {
+	Organizations []Organization `json:"organizations"`
+}
+
+type productsResponse struct {
+	Products []Product `json:"products"`
+}
+
+// APIError the errors returned by the CertCentral API.
+type APIError struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (a *APIError) Error() string {
+	var msg []string
+	for _, e := range a.Errors {
+		msg = append(msg, fmt.Sprintf("%s: %s", e.Code, e.Message))
+	}
+
+	return strings.Join(msg, ", ")
+}
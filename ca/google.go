@@ -0,0 +1,61 @@
+package ca
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/publicca/v1"
+)
+
+const (
+	googleBaseURL        = "https://publicca.googleapis.com/"
+	googleStagingBaseURL = "https://preprod-publicca.googleapis.com/"
+)
+
+// googleEAB creates an external account key with the Public CA API.
+// The API key is the Google Cloud project ID, the credentials are the Application Default Credentials.
+// https://cloud.google.com/certificate-manager/docs/public-ca-tutorial
+func googleEAB(ctx context.Context, endpoint string, opts EABOptions, options ...option.ClientOption) (*EAB, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("the Google Cloud project ID is required")
+	}
+
+	options = append([]option.ClientOption{option.WithEndpoint(endpoint)}, options...)
+
+	if opts.HTTPClient != nil {
+		options = append(options, option.WithHTTPClient(opts.HTTPClient))
+	}
+
+	service, err := publicca.NewService(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := fmt.Sprintf("projects/%s/locations/global", opts.APIKey)
+
+	key, err := service.Projects.Locations.ExternalAccountKeys.Create(parent, &publicca.ExternalAccountKey{}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("create external account key: %w", err)
+	}
+
+	return &EAB{KeyID: key.KeyId, HMACKey: decodeGoogleMACKey(key.B64MacKey)}, nil
+}
+
+// decodeGoogleMACKey returns the base64url-encoded MAC key.
+// The API returns the key as a bytes field: the base64url-encoded key is encoded again (standard base64) in the JSON.
+func decodeGoogleMACKey(value string) string {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return value
+	}
+
+	_, err = base64.RawURLEncoding.DecodeString(string(decoded))
+	if err != nil {
+		return value
+	}
+
+	return string(decoded)
+}
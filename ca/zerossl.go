@@ -0,0 +1,94 @@
+package ca
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const zeroSSLBaseURL = "https://api.zerossl.com"
+
+type zeroSSLResponse struct {
+	Success    bool   `json:"success"`
+	KeyID      string `json:"eab_kid"`
+	HMACKey    string `json:"eab_hmac_key"`
+	ErrorValue *struct {
+		Code int    `json:"code"`
+		Type string `json:"type"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+// zeroSSLEAB generates EAB credentials with the access key of the account, or with the email of the account.
+// https://zerossl.com/documentation/acme/generate-eab-credentials/
+func zeroSSLEAB(ctx context.Context, baseURL string, opts EABOptions) (*EAB, error) {
+	endpoint, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+
+	switch {
+	case opts.APIKey != "":
+		endpoint = endpoint.JoinPath("acme", "eab-credentials")
+		endpoint.RawQuery = url.Values{"access_key": {opts.APIKey}}.Encode()
+
+	case opts.Email != "":
+		endpoint = endpoint.JoinPath("acme", "eab-credentials-email")
+		body = strings.NewReader(url.Values{"email": {opts.Email}}.Encode())
+
+	default:
+		return nil, errors.New("an access key or an email is required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The access key is in the URL.
+		return nil, errors.New("generate EAB credentials: " + strings.ReplaceAll(err.Error(), opts.APIKey, "***"))
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var result zeroSSLResponse
+
+	err = json.Unmarshal(raw, &result)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected response: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	if !result.Success || resp.StatusCode != http.StatusOK {
+		if result.ErrorValue != nil {
+			return nil, fmt.Errorf("generate EAB credentials: %d: %s: %s", result.ErrorValue.Code, result.ErrorValue.Type, result.ErrorValue.Info)
+		}
+
+		return nil, fmt.Errorf("generate EAB credentials: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	return &EAB{KeyID: result.KeyID, HMACKey: result.HMACKey}, nil
+}
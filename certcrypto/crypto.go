@@ -26,6 +26,7 @@ import (
 const (
 	EC256   = KeyType("P256")
 	EC384   = KeyType("P384")
+	EC521   = KeyType("P521")
 	RSA2048 = KeyType("2048")
 	RSA3072 = KeyType("3072")
 	RSA4096 = KeyType("4096")
@@ -119,12 +120,15 @@ func ParsePEMPrivateKey(key []byte) (crypto.PrivateKey, error) {
 	return nil, errors.New("failed to parse private key")
 }
 
+// GeneratePrivateKey generates a private key of a predefined KeyType, or of a KeyType created with RSAKeyType.
 func GeneratePrivateKey(keyType KeyType) (crypto.PrivateKey, error) {
 	switch keyType {
 	case EC256:
 		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	case EC384:
 		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case EC521:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	case RSA2048:
 		return rsa.GenerateKey(rand.Reader, 2048)
 	case RSA3072:
@@ -135,7 +139,13 @@ func GeneratePrivateKey(keyType KeyType) (crypto.PrivateKey, error) {
 		return rsa.GenerateKey(rand.Reader, 8192)
 	}
 
-	return nil, fmt.Errorf("invalid KeyType: %s", keyType)
+	// The other RSA sizes and exponents (see RSAKeyType).
+	spec, err := keyType.Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	return generateKey(spec)
 }
 
 // Deprecated: uses [CreateCSR] instead.
@@ -185,8 +195,15 @@ func CreateCSR(privateKey crypto.PrivateKey, opts CSROptions) ([]byte, error) {
 	return x509.CreateCertificateRequest(rand.Reader, &template, privateKey)
 }
 
+// PEMEncode encodes data (private key, certificate request, DER certificate) to PEM.
+// It returns nil if the data cannot be encoded (ex: a private key stored in an HSM).
 func PEMEncode(data any) []byte {
-	return pem.EncodeToMemory(PEMBlock(data))
+	block := PEMBlock(data)
+	if block == nil {
+		return nil
+	}
+
+	return pem.EncodeToMemory(block)
 }
 
 func PEMBlock(data any) *pem.Block {
@@ -3,6 +3,8 @@ package certcrypto
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/pem"
@@ -27,6 +29,14 @@ func TestGeneratePrivateKey(t *testing.T) {
 	assert.NotNil(t, key)
 }
 
+func TestGeneratePrivateKey_EC521(t *testing.T) {
+	key, err := GeneratePrivateKey(EC521)
+	require.NoError(t, err)
+
+	require.IsType(t, &ecdsa.PrivateKey{}, key)
+	assert.Equal(t, elliptic.P521(), key.(*ecdsa.PrivateKey).Curve)
+}
+
 func TestGenerateCSR(t *testing.T) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
 	require.NoError(t, err, "Error generating private key")
@@ -0,0 +1,84 @@
+package certcrypto
+
+import (
+	"crypto/x509"
+	"fmt"
+	"slices"
+)
+
+// KeyPolicy the private keys accepted by a CA.
+// The zero value accepts all the keys.
+type KeyPolicy struct {
+	// RSAMinBits and RSAMaxBits the bounds of the size of the RSA modulus (0: no bound).
+	RSAMinBits int
+	RSAMaxBits int
+
+	// RSABitsMultiple the size of the RSA modulus must be a multiple of this value (0: any size).
+	RSABitsMultiple int
+
+	// RSAExponents the accepted RSA public exponents (empty: all).
+	RSAExponents []int
+
+	// Curves the names of the accepted ECDSA curves (ex: P-256) (empty: all).
+	Curves []string
+}
+
+// Check checks if the keys of a type are accepted.
+// A nil policy accepts all the keys.
+func (p *KeyPolicy) Check(keyType KeyType) error {
+	if p == nil {
+		return nil
+	}
+
+	spec, err := keyType.Spec()
+	if err != nil {
+		return err
+	}
+
+	return p.check(spec)
+}
+
+func (p *KeyPolicy) check(spec KeySpec) error {
+	switch spec.Algorithm {
+	case x509.RSA:
+		if p.RSAMinBits > 0 && spec.Bits < p.RSAMinBits || p.RSAMaxBits > 0 && spec.Bits > p.RSAMaxBits {
+			return fmt.Errorf("RSA key size %d not accepted by the CA (%s)", spec.Bits, p.rsaSizes())
+		}
+
+		if p.RSABitsMultiple > 0 && spec.Bits%p.RSABitsMultiple != 0 {
+			return fmt.Errorf("RSA key size %d not accepted by the CA (%s)", spec.Bits, p.rsaSizes())
+		}
+
+		if len(p.RSAExponents) > 0 && !slices.Contains(p.RSAExponents, spec.Exponent) {
+			return fmt.Errorf("RSA exponent %d not accepted by the CA (accepted: %v)", spec.Exponent, p.RSAExponents)
+		}
+
+	case x509.ECDSA:
+		name := curveName(spec.Curve)
+
+		if len(p.Curves) > 0 && !slices.Contains(p.Curves, name) {
+			return fmt.Errorf("ECDSA curve %s not accepted by the CA (accepted: %v)", name, p.Curves)
+		}
+
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", spec.Algorithm)
+	}
+
+	return nil
+}
+
+func (p *KeyPolicy) rsaSizes() string {
+	msg := fmt.Sprintf("accepted: from %d", max(p.RSAMinBits, RSAMinBits))
+
+	if p.RSAMaxBits > 0 {
+		msg += fmt.Sprintf(" to %d", p.RSAMaxBits)
+	}
+
+	msg += " bits"
+
+	if p.RSABitsMultiple > 0 {
+		msg += fmt.Sprintf(", multiple of %d", p.RSABitsMultiple)
+	}
+
+	return msg
+}
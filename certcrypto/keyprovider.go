@@ -0,0 +1,65 @@
+package certcrypto
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeyProvider loads a private key stored outside lego (HSM, TPM, KMS, ...) from its URI (ex: `pkcs11:token=lego;object=account`).
+// The private key never leaves its storage: the signatures are made through the returned crypto.Signer.
+type KeyProvider func(ctx context.Context, uri string) (crypto.Signer, error)
+
+var keyProviders = struct {
+	sync.RWMutex
+	providers map[string]KeyProvider
+}{providers: map[string]KeyProvider{}}
+
+// RegisterKeyProvider registers a KeyProvider for a URI scheme (ex: `pkcs11`).
+// An existing provider for the same scheme is replaced.
+func RegisterKeyProvider(scheme string, provider KeyProvider) {
+	keyProviders.Lock()
+	defer keyProviders.Unlock()
+
+	keyProviders.providers[strings.ToLower(scheme)] = provider
+}
+
+// IsKeyURI checks if a value is the URI of a key handled by a registered KeyProvider.
+func IsKeyURI(value string) bool {
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok {
+		return false
+	}
+
+	keyProviders.RLock()
+	defer keyProviders.RUnlock()
+
+	_, ok = keyProviders.providers[strings.ToLower(scheme)]
+
+	return ok
+}
+
+// LoadKey loads a private key from its URI by using the KeyProvider registered for the URI scheme.
+func LoadKey(ctx context.Context, uri string) (crypto.Signer, error) {
+	scheme, _, ok := strings.Cut(uri, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid key URI: %q", uri)
+	}
+
+	keyProviders.RLock()
+	provider, ok := keyProviders.providers[strings.ToLower(scheme)]
+	keyProviders.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no key provider registered for the scheme %q", scheme)
+	}
+
+	signer, err := provider(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("load key %s: %w", scheme, err)
+	}
+
+	return signer, nil
+}
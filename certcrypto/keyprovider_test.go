@@ -0,0 +1,42 @@
+package certcrypto
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	RegisterKeyProvider("test", func(_ context.Context, uri string) (crypto.Signer, error) {
+		assert.Equal(t, "test:key=1", uri)
+
+		return key, nil
+	})
+
+	assert.True(t, IsKeyURI("test:key=1"))
+	assert.True(t, IsKeyURI("TEST:key=1"))
+	assert.False(t, IsKeyURI(`C:\lego\key.pem`))
+	assert.False(t, IsKeyURI("key.pem"))
+
+	signer, err := LoadKey(t.Context(), "test:key=1")
+	require.NoError(t, err)
+
+	assert.Equal(t, key, signer)
+}
+
+func TestLoadKey_error(t *testing.T) {
+	_, err := LoadKey(t.Context(), "key.pem")
+	require.EqualError(t, err, `invalid key URI: "key.pem"`)
+
+	_, err = LoadKey(t.Context(), "unknown:key=1")
+	require.EqualError(t, err, `no key provider registered for the scheme "unknown"`)
+}
@@ -0,0 +1,257 @@
+package certcrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Parameters of the RSA keys.
+const (
+	RSAMinBits         = 2048
+	RSAMaxBits         = 16384
+	RSADefaultExponent = 65537
+)
+
+// KeySpec the parameters of a private key.
+type KeySpec struct {
+	// Algorithm x509.RSA or x509.ECDSA.
+	Algorithm x509.PublicKeyAlgorithm
+
+	// Bits the size of the RSA modulus.
+	Bits int
+
+	// Exponent the RSA public exponent.
+	Exponent int
+
+	// Curve the ECDSA curve.
+	Curve elliptic.Curve
+}
+
+// RSAKeyType returns the KeyType of an RSA key of any size (RSAMinBits to RSAMaxBits), with a public exponent (0 means RSADefaultExponent).
+// The KeyType of a key with the default exponent is the size of the modulus (ex: "2048" is RSA2048),
+// the exponent is added otherwise (ex: "4096:3").
+func RSAKeyType(bits, exponent int) KeyType {
+	if exponent == 0 || exponent == RSADefaultExponent {
+		return KeyType(strconv.Itoa(bits))
+	}
+
+	return KeyType(fmt.Sprintf("%d:%d", bits, exponent))
+}
+
+// ECKeyType returns the KeyType of an ECDSA key on a curve (P-256, P-384, or P-521).
+func ECKeyType(curve elliptic.Curve) (KeyType, error) {
+	switch curve {
+	case elliptic.P256():
+		return EC256, nil
+	case elliptic.P384():
+		return EC384, nil
+	case elliptic.P521():
+		return EC521, nil
+	}
+
+	return "", fmt.Errorf("unsupported ECDSA curve: %s", curveName(curve))
+}
+
+// ParseKeyType parses a key type:
+//   - the predefined key types: rsa2048, rsa3072, rsa4096, rsa8192, ec256, ec384, ec521.
+//   - an RSA key of any size, with an optional public exponent: rsa:<bits>[:<exponent>] (ex: rsa:2560, rsa:4096:3).
+//   - an ECDSA key on an explicit curve: ec:<curve>, with the NIST or SECG names (ex: ec:P-384, ec:secp384r1, ec:prime256v1).
+func ParseKeyType(value string) (KeyType, error) {
+	algo, params, found := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	if !found {
+		switch algo {
+		case "rsa2048":
+			return RSA2048, nil
+		case "rsa3072":
+			return RSA3072, nil
+		case "rsa4096":
+			return RSA4096, nil
+		case "rsa8192":
+			return RSA8192, nil
+		case "ec256":
+			return EC256, nil
+		case "ec384":
+			return EC384, nil
+		case "ec521":
+			return EC521, nil
+		}
+
+		return "", fmt.Errorf("unsupported key type: %s", value)
+	}
+
+	switch algo {
+	case "rsa":
+		rawBits, rawExponent, _ := strings.Cut(params, ":")
+
+		bits, err := strconv.Atoi(rawBits)
+		if err != nil {
+			return "", fmt.Errorf("invalid RSA key size: %s", value)
+		}
+
+		exponent := RSADefaultExponent
+
+		if rawExponent != "" {
+			exponent, err = strconv.Atoi(rawExponent)
+			if err != nil {
+				return "", fmt.Errorf("invalid RSA exponent: %s", value)
+			}
+		}
+
+		keyType := RSAKeyType(bits, exponent)
+
+		_, err = keyType.Spec()
+		if err != nil {
+			return "", err
+		}
+
+		return keyType, nil
+
+	case "ec", "ecdsa":
+		curve, err := parseCurve(params)
+		if err != nil {
+			return "", err
+		}
+
+		return ECKeyType(curve)
+
+	default:
+		return "", fmt.Errorf("unsupported key type: %s", value)
+	}
+}
+
+// Spec returns the parameters of the keys of this type.
+func (k KeyType) Spec() (KeySpec, error) {
+	switch k {
+	case EC256:
+		return KeySpec{Algorithm: x509.ECDSA, Curve: elliptic.P256()}, nil
+	case EC384:
+		return KeySpec{Algorithm: x509.ECDSA, Curve: elliptic.P384()}, nil
+	case EC521:
+		return KeySpec{Algorithm: x509.ECDSA, Curve: elliptic.P521()}, nil
+	}
+
+	rawBits, rawExponent, found := strings.Cut(string(k), ":")
+
+	bits, err := strconv.Atoi(rawBits)
+	if err != nil {
+		return KeySpec{}, fmt.Errorf("invalid KeyType: %s", k)
+	}
+
+	spec := KeySpec{Algorithm: x509.RSA, Bits: bits, Exponent: RSADefaultExponent}
+
+	if found {
+		spec.Exponent, err = strconv.Atoi(rawExponent)
+		if err != nil {
+			return KeySpec{}, fmt.Errorf("invalid KeyType: %s", k)
+		}
+	}
+
+	if spec.Bits < RSAMinBits || spec.Bits > RSAMaxBits {
+		return KeySpec{}, fmt.Errorf("invalid KeyType: %s: the RSA key size must be between %d and %d bits", k, RSAMinBits, RSAMaxBits)
+	}
+
+	if spec.Exponent < 3 || spec.Exponent%2 == 0 || spec.Exponent > 1<<31-1 {
+		return KeySpec{}, fmt.Errorf("invalid KeyType: %s: the RSA exponent must be an odd number between 3 and 2^31-1", k)
+	}
+
+	return spec, nil
+}
+
+// generateKey generates a private key from its parameters.
+func generateKey(spec KeySpec) (crypto.PrivateKey, error) {
+	switch spec.Algorithm {
+	case x509.RSA:
+		if spec.Exponent == RSADefaultExponent {
+			return rsa.GenerateKey(rand.Reader, spec.Bits)
+		}
+
+		return generateRSAKey(spec.Bits, spec.Exponent)
+
+	case x509.ECDSA:
+		return ecdsa.GenerateKey(spec.Curve, rand.Reader)
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", spec.Algorithm)
+	}
+}
+
+// generateRSAKey generates a 2-prime RSA key with a public exponent other than 65537,
+// which is not supported by rsa.GenerateKey.
+func generateRSAKey(bits, exponent int) (*rsa.PrivateKey, error) {
+	e := big.NewInt(int64(exponent))
+	one := big.NewInt(1)
+
+	for {
+		p, err := rand.Prime(rand.Reader, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+
+		q, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+
+		totient := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+
+		// The exponent must be coprime with the totient.
+		d := new(big.Int).ModInverse(e, totient)
+		if d == nil {
+			continue
+		}
+
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: n, E: exponent},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+
+		err = key.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		key.Precompute()
+
+		return key, nil
+	}
+}
+
+func parseCurve(name string) (elliptic.Curve, error) {
+	switch strings.ToLower(name) {
+	case "p-256", "p256", "secp256r1", "prime256v1":
+		return elliptic.P256(), nil
+	case "p-384", "p384", "secp384r1":
+		return elliptic.P384(), nil
+	case "p-521", "p521", "secp521r1":
+		return elliptic.P521(), nil
+	}
+
+	return nil, errors.New("unsupported ECDSA curve: " + name)
+}
+
+func curveName(curve elliptic.Curve) string {
+	if curve == nil || curve.Params() == nil {
+		return "unknown"
+	}
+
+	return curve.Params().Name
+}
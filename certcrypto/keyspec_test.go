@@ -0,0 +1,143 @@
+package certcrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyType(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected KeyType
+	}{
+		{value: "rsa2048", expected: RSA2048},
+		{value: "RSA8192", expected: RSA8192},
+		{value: "ec384", expected: EC384},
+		{value: "rsa:2560", expected: KeyType("2560")},
+		{value: "rsa:4096", expected: RSA4096},
+		{value: "rsa:4096:65537", expected: RSA4096},
+		{value: "rsa:3072:3", expected: KeyType("3072:3")},
+		{value: "ec:P-256", expected: EC256},
+		{value: "ec:secp384r1", expected: EC384},
+		{value: "ec:prime256v1", expected: EC256},
+		{value: "ecdsa:p521", expected: EC521},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			t.Parallel()
+
+			keyType, err := ParseKeyType(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, keyType)
+		})
+	}
+}
+
+func TestParseKeyType_errors(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{value: "rsa1024", expected: "unsupported key type: rsa1024"},
+		{value: "dsa:2048", expected: "unsupported key type: dsa:2048"},
+		{value: "rsa:abc", expected: "invalid RSA key size: rsa:abc"},
+		{value: "rsa:1024", expected: "invalid KeyType: 1024: the RSA key size must be between 2048 and 16384 bits"},
+		{value: "rsa:2048:x", expected: "invalid RSA exponent: rsa:2048:x"},
+		{value: "rsa:2048:4", expected: "invalid KeyType: 2048:4: the RSA exponent must be an odd number between 3 and 2^31-1"},
+		{value: "ec:P-224", expected: "unsupported ECDSA curve: p-224"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseKeyType(test.value)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestGeneratePrivateKey_RSAKeyType(t *testing.T) {
+	key, err := GeneratePrivateKey(RSAKeyType(2304, 0))
+	require.NoError(t, err)
+
+	require.IsType(t, &rsa.PrivateKey{}, key)
+	assert.Equal(t, 2304, key.(*rsa.PrivateKey).N.BitLen())
+	assert.Equal(t, RSADefaultExponent, key.(*rsa.PrivateKey).E)
+}
+
+func TestGeneratePrivateKey_RSAKeyType_exponent(t *testing.T) {
+	key, err := GeneratePrivateKey(RSAKeyType(2048, 3))
+	require.NoError(t, err)
+
+	require.IsType(t, &rsa.PrivateKey{}, key)
+
+	rsaKey := key.(*rsa.PrivateKey)
+	assert.Equal(t, 2048, rsaKey.N.BitLen())
+	assert.Equal(t, 3, rsaKey.E)
+
+	// The key can be used to create a CSR.
+	_, err = CreateCSR(rsaKey, CSROptions{Domain: "example.com"})
+	require.NoError(t, err)
+}
+
+func TestGeneratePrivateKey_ECKeyType(t *testing.T) {
+	keyType, err := ECKeyType(elliptic.P384())
+	require.NoError(t, err)
+
+	key, err := GeneratePrivateKey(keyType)
+	require.NoError(t, err)
+
+	require.IsType(t, &ecdsa.PrivateKey{}, key)
+	assert.Equal(t, elliptic.P384(), key.(*ecdsa.PrivateKey).Curve)
+}
+
+func TestKeyPolicy_Check(t *testing.T) {
+	policy := &KeyPolicy{
+		RSAMinBits:      2048,
+		RSAMaxBits:      4096,
+		RSABitsMultiple: 1024,
+		RSAExponents:    []int{RSADefaultExponent},
+		Curves:          []string{"P-256", "P-384"},
+	}
+
+	testCases := []struct {
+		keyType  KeyType
+		expected string
+	}{
+		{keyType: RSA2048},
+		{keyType: RSA4096},
+		{keyType: EC384},
+		{keyType: RSA8192, expected: "RSA key size 8192 not accepted by the CA (accepted: from 2048 to 4096 bits, multiple of 1024)"},
+		{keyType: RSAKeyType(2560, 0), expected: "RSA key size 2560 not accepted by the CA (accepted: from 2048 to 4096 bits, multiple of 1024)"},
+		{keyType: RSAKeyType(2048, 3), expected: "RSA exponent 3 not accepted by the CA (accepted: [65537])"},
+		{keyType: EC521, expected: "ECDSA curve P-521 not accepted by the CA (accepted: [P-256 P-384])"},
+		{keyType: KeyType("foo"), expected: "invalid KeyType: foo"},
+	}
+
+	for _, test := range testCases {
+		t.Run(string(test.keyType), func(t *testing.T) {
+			t.Parallel()
+
+			err := policy.Check(test.keyType)
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestKeyPolicy_Check_nil(t *testing.T) {
+	var policy *KeyPolicy
+
+	require.NoError(t, policy.Check(RSAKeyType(2048, 3)))
+}
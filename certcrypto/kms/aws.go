@@ -0,0 +1,178 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+type awsGetPublicKeyResponse struct {
+	PublicKey []byte `json:"PublicKey"`
+	KeyUsage  string `json:"KeyUsage"`
+}
+
+type awsSignRequest struct {
+	KeyID            string `json:"KeyId"`
+	Message          []byte `json:"Message"`
+	MessageType      string `json:"MessageType"`
+	SigningAlgorithm string `json:"SigningAlgorithm"`
+}
+
+type awsSignResponse struct {
+	Signature []byte `json:"Signature"`
+}
+
+type awsClient struct {
+	cfg    aws.Config
+	signer *v4.Signer
+	keyID  string
+}
+
+// NewAWSSigner returns a crypto.Signer backed by an asymmetric AWS KMS key (SIGN_VERIFY).
+// The key ID can be a key ID, an alias (`alias/<name>`), or an ARN.
+func NewAWSSigner(ctx context.Context, cfg aws.Config, keyID string) (crypto.Signer, error) {
+	client := &awsClient{cfg: cfg, signer: v4.NewSigner(), keyID: keyID}
+
+	var result awsGetPublicKeyResponse
+
+	err := client.do(ctx, "GetPublicKey", map[string]string{"KeyId": keyID}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("kms: aws: get public key %s: %w", keyID, err)
+	}
+
+	if result.KeyUsage != "" && result.KeyUsage != "SIGN_VERIFY" {
+		return nil, fmt.Errorf("kms: aws: the key %s cannot be used to sign: %s", keyID, result.KeyUsage)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(result.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("kms: aws: parse public key %s: %w", keyID, err)
+	}
+
+	return &signer{publicKey: publicKey, sign: client.sign(publicKey)}, nil
+}
+
+func (c *awsClient) sign(publicKey crypto.PublicKey) signFunc {
+	return func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		hash, pss, err := signatureAlgorithm(publicKey, opts)
+		if err != nil {
+			return nil, fmt.Errorf("kms: aws: %w", err)
+		}
+
+		var algorithm string
+
+		switch {
+		case pss:
+			algorithm = "RSASSA_PSS_" + hash
+		case isECDSA(publicKey):
+			algorithm = "ECDSA_" + hash
+		default:
+			algorithm = "RSASSA_PKCS1_V1_5_" + hash
+		}
+
+		request := awsSignRequest{
+			KeyID:            c.keyID,
+			Message:          digest,
+			MessageType:      "DIGEST",
+			SigningAlgorithm: algorithm,
+		}
+
+		var result awsSignResponse
+
+		err = c.do(ctx, "Sign", request, &result)
+		if err != nil {
+			return nil, fmt.Errorf("kms: aws: sign: %w", err)
+		}
+
+		// The ECDSA signatures are already ASN.1 encoded.
+		return result.Signature, nil
+	}
+}
+
+func (c *awsClient) do(ctx context.Context, action string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to create request JSON body: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://kms.%s.amazonaws.com/", c.cfg.Region)
+	if c.cfg.BaseEndpoint != nil {
+		endpoint = *c.cfg.BaseEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+
+	if c.cfg.Credentials == nil {
+		return errors.New("the credentials are missing")
+	}
+
+	credentials, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(body)
+
+	err = c.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), "kms", c.cfg.Region, time.Now())
+	if err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	var client httpDoer = http.DefaultClient
+	if c.cfg.HTTPClient != nil {
+		client = c.cfg.HTTPClient
+	}
+
+	return doJSON(client, req, result)
+}
+
+func openAWS(ctx context.Context, uri string) (crypto.Signer, error) {
+	_, keyID, _ := strings.Cut(uri, ":")
+	if keyID == "" {
+		return nil, errors.New("kms: aws: the key ID is missing")
+	}
+
+	var optFns []func(*awsconfig.LoadOptions) error
+
+	if arn.IsARN(keyID) {
+		parsed, err := arn.Parse(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("kms: aws: %w", err)
+		}
+
+		optFns = append(optFns, awsconfig.WithRegion(parsed.Region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("kms: aws: load config: %w", err)
+	}
+
+	return NewAWSSigner(ctx, cfg, keyID)
+}
+
+func isECDSA(publicKey crypto.PublicKey) bool {
+	_, ok := publicKey.(*ecdsa.PublicKey)
+	return ok
+}
@@ -0,0 +1,117 @@
+package kms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockAWSBuilder() *servermock.Builder[aws.Config] {
+	return servermock.NewBuilder[aws.Config](
+		func(server *httptest.Server) (aws.Config, error) {
+			return aws.Config{
+				Region:       "us-east-1",
+				BaseEndpoint: aws.String(server.URL),
+				HTTPClient:   server.Client(),
+				Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+			}, nil
+		},
+		servermock.CheckHeader().
+			WithContentType("application/x-amz-json-1.1").
+			WithRegexp("Authorization", `^AWS4-HMAC-SHA256 Credential=key/\d+/us-east-1/kms/aws4_request, .+`),
+	)
+}
+
+func TestNewAWSSigner(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cfg := mockAWSBuilder().
+		Route("POST /", awsKMSHandler(t, privateKey)).
+		Build(t)
+
+	signer, err := NewAWSSigner(t.Context(), cfg, "alias/lego")
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(signer.Public()))
+
+	digest := sha256.Sum256([]byte("lego"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+}
+
+func TestNewAWSSigner_error(t *testing.T) {
+	cfg := mockAWSBuilder().
+		Route("POST /",
+			servermock.RawStringResponse(`{"__type":"NotFoundException","message":"Alias is not found."}`).
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	_, err := NewAWSSigner(t.Context(), cfg, "alias/lego")
+	require.ErrorContains(t, err, `kms: aws: get public key alias/lego: POST`)
+	require.ErrorContains(t, err, `unexpected status code: [status code: 400] body: {"__type":"NotFoundException","message":"Alias is not found."}`)
+}
+
+func awsKMSHandler(t *testing.T, privateKey *ecdsa.PrivateKey) http.HandlerFunc {
+	t.Helper()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		var result any
+
+		switch strings.TrimPrefix(req.Header.Get("X-Amz-Target"), "TrentService.") {
+		case "GetPublicKey":
+			publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			result = awsGetPublicKeyResponse{PublicKey: publicKey, KeyUsage: "SIGN_VERIFY"}
+
+		case "Sign":
+			var request awsSignRequest
+
+			err := json.NewDecoder(req.Body).Decode(&request)
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if request.KeyID != "alias/lego" || request.MessageType != "DIGEST" || request.SigningAlgorithm != "ECDSA_SHA_256" {
+				http.Error(rw, "invalid request", http.StatusBadRequest)
+				return
+			}
+
+			signature, err := ecdsa.SignASN1(rand.Reader, privateKey, request.Message)
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			result = awsSignResponse{Signature: signature}
+
+		default:
+			http.Error(rw, "unknown action", http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(result)
+	}
+}
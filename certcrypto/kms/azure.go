@@ -0,0 +1,222 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const azureAPIVersion = "7.4"
+
+// azureScope the OAuth2 scope of the Key Vault API.
+const azureScope = "https://vault.azure.net/.default"
+
+type azureJSONWebKey struct {
+	KID string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type azureKeyResponse struct {
+	Key azureJSONWebKey `json:"key"`
+}
+
+type azureSignRequest struct {
+	Alg   string `json:"alg"`
+	Value string `json:"value"`
+}
+
+type azureSignResponse struct {
+	Value string `json:"value"`
+}
+
+type azureClient struct {
+	httpClient *http.Client
+	credential azcore.TokenCredential
+	keyID      string
+}
+
+// NewAzureSigner returns a crypto.Signer backed by an Azure Key Vault key (EC or RSA).
+// The key ID is the URL of the key: `https://<vault>.vault.azure.net/keys/<name>[/<version>]`.
+func NewAzureSigner(ctx context.Context, credential azcore.TokenCredential, keyID string) (crypto.Signer, error) {
+	return newAzureSigner(ctx, &azureClient{httpClient: http.DefaultClient, credential: credential, keyID: keyID})
+}
+
+func newAzureSigner(ctx context.Context, client *azureClient) (crypto.Signer, error) {
+	var result azureKeyResponse
+
+	err := client.do(ctx, http.MethodGet, client.keyID, nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("kms: azure: get key %s: %w", client.keyID, err)
+	}
+
+	publicKey, err := result.Key.publicKey()
+	if err != nil {
+		return nil, fmt.Errorf("kms: azure: parse public key %s: %w", client.keyID, err)
+	}
+
+	// Pins the version of the key.
+	if result.Key.KID != "" {
+		client.keyID = result.Key.KID
+	}
+
+	return &signer{publicKey: publicKey, sign: client.sign(publicKey)}, nil
+}
+
+func (c *azureClient) sign(publicKey crypto.PublicKey) signFunc {
+	return func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		hash, pss, err := signatureAlgorithm(publicKey, opts)
+		if err != nil {
+			return nil, fmt.Errorf("kms: azure: %w", err)
+		}
+
+		// SHA_256 -> 256
+		size := strings.TrimPrefix(hash, "SHA_")
+
+		var alg string
+
+		switch {
+		case pss:
+			alg = "PS" + size
+		case isECDSA(publicKey):
+			alg = "ES" + size
+		default:
+			alg = "RS" + size
+		}
+
+		request := azureSignRequest{Alg: alg, Value: base64.RawURLEncoding.EncodeToString(digest)}
+
+		var result azureSignResponse
+
+		err = c.do(ctx, http.MethodPost, strings.TrimSuffix(c.keyID, "/")+"/sign", request, &result)
+		if err != nil {
+			return nil, fmt.Errorf("kms: azure: sign: %w", err)
+		}
+
+		signature, err := base64.RawURLEncoding.DecodeString(result.Value)
+		if err != nil {
+			return nil, fmt.Errorf("kms: azure: sign: %w", err)
+		}
+
+		if isECDSA(publicKey) {
+			return toASN1Signature(signature)
+		}
+
+		return signature, nil
+	}
+}
+
+func (c *azureClient) do(ctx context.Context, method, endpoint string, payload, result any) error {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid key ID: %w", err)
+	}
+
+	query := endpointURL.Query()
+	query.Set("api-version", azureAPIVersion)
+	endpointURL.RawQuery = query.Encode()
+
+	req, err := newJSONRequest(ctx, method, endpointURL.String(), payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureScope}})
+	if err != nil {
+		return fmt.Errorf("get token: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+
+	return doJSON(c.httpClient, req, result)
+}
+
+func (k azureJSONWebKey) publicKey() (crypto.PublicKey, error) {
+	switch strings.TrimSuffix(k.Kty, "-HSM") {
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %w", err)
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("e: %w", err)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type: %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(raw), nil
+}
+
+func openAzure(ctx context.Context, uri string) (crypto.Signer, error) {
+	_, keyID, _ := strings.Cut(uri, ":")
+	if keyID == "" {
+		return nil, errors.New("kms: azure: the key ID is missing")
+	}
+
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("kms: azure: %w", err)
+	}
+
+	return NewAzureSigner(ctx, credential, keyID)
+}
@@ -0,0 +1,124 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "secret", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestNewAzureSigner(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	client := servermock.NewBuilder[*azureClient](
+		func(server *httptest.Server) (*azureClient, error) {
+			return &azureClient{httpClient: server.Client(), credential: fakeCredential{}, keyID: server.URL + "/keys/account"}, nil
+		},
+		servermock.CheckHeader().WithAuthorization("Bearer secret"),
+		servermock.CheckQueryParameter().Strict().With("api-version", "7.4"),
+	).
+		Route("GET /keys/account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_ = json.NewEncoder(rw).Encode(azureKeyResponse{Key: azureJSONWebKey{
+					KID: "http://" + req.Host + "/keys/account/abc123",
+					Kty: "EC-HSM",
+					Crv: "P-384",
+					X:   base64.RawURLEncoding.EncodeToString(privateKey.X.FillBytes(make([]byte, 48))),
+					Y:   base64.RawURLEncoding.EncodeToString(privateKey.Y.FillBytes(make([]byte, 48))),
+				}})
+			})).
+		Route("POST /keys/account/abc123/sign",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				var request azureSignRequest
+
+				err := json.NewDecoder(req.Body).Decode(&request)
+				if err != nil || request.Alg != "ES384" {
+					http.Error(rw, "invalid request", http.StatusBadRequest)
+					return
+				}
+
+				digest, err := base64.RawURLEncoding.DecodeString(request.Value)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				signature := append(r.FillBytes(make([]byte, 48)), s.FillBytes(make([]byte, 48))...)
+
+				_ = json.NewEncoder(rw).Encode(azureSignResponse{Value: base64.RawURLEncoding.EncodeToString(signature)})
+			}),
+			servermock.CheckHeader().WithContentType("application/json")).
+		Build(t)
+
+	signer, err := newAzureSigner(t.Context(), client)
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(signer.Public()))
+
+	digest := sha512.Sum384([]byte("lego"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA384)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+}
+
+func Test_azureJSONWebKey_publicKey_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		key      azureJSONWebKey
+		expected string
+	}{
+		{
+			desc:     "unsupported key type",
+			key:      azureJSONWebKey{Kty: "oct"},
+			expected: `unsupported key type: "oct"`,
+		},
+		{
+			desc:     "unsupported curve",
+			key:      azureJSONWebKey{Kty: "EC", Crv: "P-256K"},
+			expected: `unsupported curve: "P-256K"`,
+		},
+		{
+			desc:     "missing modulus",
+			key:      azureJSONWebKey{Kty: "RSA", E: "AQAB"},
+			expected: "n: empty value",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := test.key.publicKey()
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+const gcpBaseURL = "https://cloudkms.googleapis.com/v1/"
+
+// gcpScope the OAuth2 scope of the Cloud KMS API.
+const gcpScope = "https://www.googleapis.com/auth/cloudkms"
+
+type gcpPublicKeyResponse struct {
+	Pem       string `json:"pem"`
+	Algorithm string `json:"algorithm"`
+}
+
+type gcpSignRequest struct {
+	Digest map[string][]byte `json:"digest"`
+}
+
+type gcpSignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+type gcpClient struct {
+	httpClient *http.Client
+	baseURL    string
+	name       string
+}
+
+// NewGCPSigner returns a crypto.Signer backed by a Google Cloud KMS asymmetric signing key version.
+// The name is the resource name of the key version:
+// `projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>/cryptoKeyVersions/<version>`.
+// The HTTP client must be authenticated (ex: google.DefaultClient).
+func NewGCPSigner(ctx context.Context, httpClient *http.Client, name string) (crypto.Signer, error) {
+	return newGCPSigner(ctx, &gcpClient{httpClient: httpClient, baseURL: gcpBaseURL, name: name})
+}
+
+func newGCPSigner(ctx context.Context, client *gcpClient) (crypto.Signer, error) {
+	req, err := newJSONRequest(ctx, http.MethodGet, client.baseURL+client.name+"/publicKey", nil)
+	if err != nil {
+		return nil, fmt.Errorf("kms: gcp: %w", err)
+	}
+
+	var result gcpPublicKeyResponse
+
+	err = doJSON(client.httpClient, req, &result)
+	if err != nil {
+		return nil, fmt.Errorf("kms: gcp: get public key %s: %w", client.name, err)
+	}
+
+	block, _ := pem.Decode([]byte(result.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("kms: gcp: get public key %s: invalid PEM", client.name)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("kms: gcp: parse public key %s: %w", client.name, err)
+	}
+
+	return &signer{publicKey: publicKey, sign: client.sign(publicKey, result.Algorithm)}, nil
+}
+
+func (c *gcpClient) sign(publicKey crypto.PublicKey, algorithm string) signFunc {
+	return func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		hash, pss, err := signatureAlgorithm(publicKey, opts)
+		if err != nil {
+			return nil, fmt.Errorf("kms: gcp: %w", err)
+		}
+
+		// The padding is defined by the algorithm of the key.
+		if pss != strings.Contains(algorithm, "_PSS_") {
+			return nil, fmt.Errorf("kms: gcp: the signature options don't match the key algorithm %s", algorithm)
+		}
+
+		request := gcpSignRequest{
+			Digest: map[string][]byte{strings.ToLower(strings.ReplaceAll(hash, "_", "")): digest},
+		}
+
+		req, err := newJSONRequest(ctx, http.MethodPost, c.baseURL+c.name+":asymmetricSign", request)
+		if err != nil {
+			return nil, fmt.Errorf("kms: gcp: %w", err)
+		}
+
+		var result gcpSignResponse
+
+		err = doJSON(c.httpClient, req, &result)
+		if err != nil {
+			return nil, fmt.Errorf("kms: gcp: sign: %w", err)
+		}
+
+		// The ECDSA signatures are already ASN.1 encoded.
+		return result.Signature, nil
+	}
+}
+
+func openGCP(ctx context.Context, uri string) (crypto.Signer, error) {
+	_, name, _ := strings.Cut(uri, ":")
+	if name == "" {
+		return nil, errors.New("kms: gcp: the key version name is missing")
+	}
+
+	httpClient, err := google.DefaultClient(ctx, gcpScope)
+	if err != nil {
+		return nil, fmt.Errorf("kms: gcp: %w", err)
+	}
+
+	return NewGCPSigner(ctx, httpClient, strings.TrimPrefix(name, "/"))
+}
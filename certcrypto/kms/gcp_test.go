@@ -0,0 +1,98 @@
+package kms
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gcpKeyName = "projects/lego/locations/global/keyRings/acme/cryptoKeys/account/cryptoKeyVersions/1"
+
+func mockGCPBuilder() *servermock.Builder[*gcpClient] {
+	return servermock.NewBuilder[*gcpClient](
+		func(server *httptest.Server) (*gcpClient, error) {
+			return &gcpClient{httpClient: server.Client(), baseURL: server.URL + "/v1/", name: gcpKeyName}, nil
+		},
+		servermock.CheckHeader().WithAccept("application/json"),
+	)
+}
+
+func TestNewGCPSigner(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	client := mockGCPBuilder().
+		Route("GET /v1/"+gcpKeyName+"/publicKey", gcpPublicKeyHandler(t, privateKey, "RSA_SIGN_PKCS1_2048_SHA256")).
+		Route("POST /v1/"+gcpKeyName+":asymmetricSign",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				var request gcpSignRequest
+
+				err := json.NewDecoder(req.Body).Decode(&request)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, request.Digest["sha256"])
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				_ = json.NewEncoder(rw).Encode(gcpSignResponse{Signature: signature})
+			}),
+			servermock.CheckHeader().WithContentType("application/json")).
+		Build(t)
+
+	signer, err := newGCPSigner(t.Context(), client)
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(signer.Public()))
+
+	digest := sha256.Sum256([]byte("lego"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	require.NoError(t, rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func TestNewGCPSigner_algorithmMismatch(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	client := mockGCPBuilder().
+		Route("GET /v1/"+gcpKeyName+"/publicKey", gcpPublicKeyHandler(t, privateKey, "RSA_SIGN_PKCS1_2048_SHA256")).
+		Build(t)
+
+	signer, err := newGCPSigner(t.Context(), client)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("lego"))
+
+	_, err = signer.Sign(rand.Reader, digest[:], &rsa.PSSOptions{Hash: crypto.SHA256})
+	require.EqualError(t, err, "kms: gcp: the signature options don't match the key algorithm RSA_SIGN_PKCS1_2048_SHA256")
+}
+
+func gcpPublicKeyHandler(t *testing.T, privateKey *rsa.PrivateKey, algorithm string) http.Handler {
+	t.Helper()
+
+	raw, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return servermock.JSONEncode(gcpPublicKeyResponse{
+		Pem:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: raw})),
+		Algorithm: algorithm,
+	})
+}
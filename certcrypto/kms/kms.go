@@ -0,0 +1,169 @@
+// Package kms provides crypto.Signer implementations backed by cloud key management services
+// (AWS KMS, Google Cloud KMS, Azure Key Vault).
+//
+// The private keys never leave the KMS: only the public key is fetched, and the signatures are made by the KMS.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+)
+
+// Schemes of the KMS key URIs.
+const (
+	// SchemeAWS AWS KMS key URI: `awskms:<key ID, alias, or ARN>`.
+	SchemeAWS = "awskms"
+	// SchemeGCP Google Cloud KMS key URI: `gcpkms:projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>/cryptoKeyVersions/<version>`.
+	SchemeGCP = "gcpkms"
+	// SchemeAzure Azure Key Vault key URI: `azurekv:https://<vault>.vault.azure.net/keys/<name>[/<version>]`.
+	SchemeAzure = "azurekv"
+)
+
+// signTimeout the timeout of a signature request.
+// crypto.Signer doesn't have a context.
+const signTimeout = 30 * time.Second
+
+// Register registers the KMS key providers, using the default credentials of each cloud.
+func Register() {
+	certcrypto.RegisterKeyProvider(SchemeAWS, openAWS)
+	certcrypto.RegisterKeyProvider(SchemeGCP, openGCP)
+	certcrypto.RegisterKeyProvider(SchemeAzure, openAzure)
+}
+
+// signFunc signs a digest.
+type signFunc func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+
+var _ crypto.Signer = (*signer)(nil)
+
+// signer a key stored in a KMS.
+type signer struct {
+	publicKey crypto.PublicKey
+	sign      signFunc
+}
+
+func (s *signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+func (s *signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), signTimeout)
+	defer cancel()
+
+	return s.sign(ctx, digest, opts)
+}
+
+// signatureAlgorithm returns the name of the hash function and if RSA-PSS is used.
+func signatureAlgorithm(publicKey crypto.PublicKey, opts crypto.SignerOpts) (string, bool, error) {
+	var hash string
+
+	switch opts.HashFunc() {
+	case crypto.SHA256:
+		hash = "SHA_256"
+	case crypto.SHA384:
+		hash = "SHA_384"
+	case crypto.SHA512:
+		hash = "SHA_512"
+	default:
+		return "", false, fmt.Errorf("unsupported hash function: %s", opts.HashFunc())
+	}
+
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return hash, false, nil
+
+	case *rsa.PublicKey:
+		_, pss := opts.(*rsa.PSSOptions)
+
+		return hash, pss, nil
+
+	default:
+		return "", false, fmt.Errorf("unsupported public key type: %T", publicKey)
+	}
+}
+
+// httpDoer an HTTP client.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// doJSON sends a request, and decodes the JSON response.
+func doJSON(client httpDoer, req *http.Request, result any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: read response: %w", req.Method, req.URL.Redacted(), err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s %s: unexpected status code: [status code: %d] body: %s",
+			req.Method, req.URL.Redacted(), resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("%s %s: unable to unmarshal response: %w", req.Method, req.URL.Redacted(), err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, method, endpoint string, payload any) (*http.Request, error) {
+	var body io.Reader = http.NoBody
+
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// toASN1Signature converts an ECDSA signature (concatenation of R and S) to the ASN.1 format used by crypto.Signer.
+func toASN1Signature(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, errors.New("invalid ECDSA signature")
+	}
+
+	size := len(raw) / 2
+
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(raw[:size]),
+		S: new(big.Int).SetBytes(raw[size:]),
+	})
+}
@@ -0,0 +1,187 @@
+package certcrypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha3"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OnionTLD is the special-use domain of the Tor hidden services.
+const OnionTLD = "onion"
+
+// onionVersion is the version of the hidden services addresses (v3).
+const onionVersion = 0x03
+
+// CSR attributes required by the CA/Browser Forum for the .onion names.
+// https://cabforum.org/working-groups/server/baseline-requirements/documents/ (Appendix B)
+var (
+	oidCASigningNonce        = asn1.ObjectIdentifier{2, 23, 140, 41}
+	oidApplicantSigningNonce = asn1.ObjectIdentifier{2, 23, 140, 42}
+
+	oidExtensionRequest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+	oidExtensionSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidSignatureEd25519        = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// applicantNonceSize is the size of the applicantSigningNonce (at least 64 bits of entropy).
+const applicantNonceSize = 16
+
+// IsOnion checks if the domain is a .onion name (or a subdomain of it).
+func IsOnion(domain string) bool {
+	return strings.HasSuffix(strings.ToLower(strings.TrimSuffix(domain, ".")), "."+OnionTLD)
+}
+
+// OnionAddress returns the v3 .onion address of the hidden service public key.
+// https://spec.torproject.org/rend-spec/encoding-onion-addresses.html
+func OnionAddress(publicKey ed25519.PublicKey) string {
+	h := sha3.New256()
+	h.Write([]byte(".onion checksum"))
+	h.Write(publicKey)
+	h.Write([]byte{onionVersion})
+
+	raw := make([]byte, 0, ed25519.PublicKeySize+3)
+	raw = append(raw, publicKey...)
+	raw = append(raw, h.Sum(nil)[:2]...)
+	raw = append(raw, onionVersion)
+
+	return strings.ToLower(base32.StdEncoding.EncodeToString(raw)) + "." + OnionTLD
+}
+
+// OnionServiceName returns the .onion address part of a domain (ex: `www.xxx.onion` -> `xxx.onion`).
+func OnionServiceName(domain string) (string, error) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domain, ".")), ".")
+	if len(labels) < 2 || labels[len(labels)-1] != OnionTLD {
+		return "", fmt.Errorf("%s is not a .onion name", domain)
+	}
+
+	return strings.Join(labels[len(labels)-2:], "."), nil
+}
+
+// GenerateOnionCSR creates a CSR signed by the key of a hidden service,
+// with the caSigningNonce and applicantSigningNonce attributes required by the CA/Browser Forum.
+// The key must be an Ed25519 key, the .onion names of the domains must be the address of this key.
+// It returns the DER encoded CSR.
+func GenerateOnionCSR(signer crypto.Signer, domains []string, caNonce []byte) ([]byte, error) {
+	publicKey, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the key of a hidden service must be an Ed25519 key: %T", signer.Public())
+	}
+
+	if len(domains) == 0 {
+		return nil, errors.New("no domains")
+	}
+
+	if len(caNonce) < 8 {
+		return nil, errors.New("the CA nonce must have at least 64 bits")
+	}
+
+	address := OnionAddress(publicKey)
+
+	for _, domain := range domains {
+		name, err := OnionServiceName(strings.TrimPrefix(domain, "*."))
+		if err != nil {
+			return nil, err
+		}
+
+		if name != address {
+			return nil, fmt.Errorf("%s doesn't match the address of the hidden service key (%s)", domain, address)
+		}
+	}
+
+	applicantNonce := make([]byte, applicantNonceSize)
+
+	_, err := rand.Read(applicantNonce)
+	if err != nil {
+		return nil, fmt.Errorf("applicant nonce: %w", err)
+	}
+
+	tbs, err := marshalOnionCSRInfo(publicKey, domains, caNonce, applicantNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Sign(rand.Reader, tbs, crypto.Hash(0))
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	return asn1.Marshal(struct {
+		Info               asn1.RawValue
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          asn1.BitString
+	}{
+		Info:               asn1.RawValue{FullBytes: tbs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSignatureEd25519},
+		Signature:          asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+}
+
+// marshalOnionCSRInfo creates the CertificationRequestInfo of an onion CSR.
+// The standard library doesn't support attributes other than the extension request.
+// https://www.rfc-editor.org/rfc/rfc2986.html#section-4
+func marshalOnionCSRInfo(publicKey ed25519.PublicKey, domains []string, caNonce, applicantNonce []byte) ([]byte, error) {
+	spki, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	subject, err := asn1.Marshal(pkix.Name{CommonName: domains[0]}.ToRDNSequence())
+	if err != nil {
+		return nil, fmt.Errorf("subject: %w", err)
+	}
+
+	var names []asn1.RawValue
+	for _, domain := range domains {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, Bytes: []byte(domain)})
+	}
+
+	san, err := asn1.Marshal(names)
+	if err != nil {
+		return nil, fmt.Errorf("subject alternative names: %w", err)
+	}
+
+	extensions, err := asn1.Marshal([]pkix.Extension{{Id: oidExtensionSubjectAltName, Value: san}})
+	if err != nil {
+		return nil, fmt.Errorf("extensions: %w", err)
+	}
+
+	var attributes []asn1.RawValue
+
+	for _, attr := range []struct {
+		oid   asn1.ObjectIdentifier
+		value any
+	}{
+		{oid: oidExtensionRequest, value: asn1.RawValue{FullBytes: extensions}},
+		{oid: oidCASigningNonce, value: caNonce},
+		{oid: oidApplicantSigningNonce, value: applicantNonce},
+	} {
+		raw, errM := asn1.Marshal(struct {
+			Type   asn1.ObjectIdentifier
+			Values []any `asn1:"set"`
+		}{Type: attr.oid, Values: []any{attr.value}})
+		if errM != nil {
+			return nil, fmt.Errorf("attribute %s: %w", attr.oid, errM)
+		}
+
+		attributes = append(attributes, asn1.RawValue{FullBytes: raw})
+	}
+
+	return asn1.Marshal(struct {
+		Version       int
+		Subject       asn1.RawValue
+		PublicKey     asn1.RawValue
+		RawAttributes []asn1.RawValue `asn1:"tag:0"`
+	}{
+		Subject:       asn1.RawValue{FullBytes: subject},
+		PublicKey:     asn1.RawValue{FullBytes: spki},
+		RawAttributes: attributes,
+	})
+}
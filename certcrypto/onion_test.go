@@ -0,0 +1,161 @@
+package certcrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base32"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnionAddress(t *testing.T) {
+	// The hidden service of the Tor Project.
+	address := "2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion"
+
+	raw, err := base32.StdEncoding.DecodeString(strings.ToUpper(strings.TrimSuffix(address, ".onion")))
+	require.NoError(t, err)
+
+	assert.Equal(t, address, OnionAddress(raw[:ed25519.PublicKeySize]))
+}
+
+func TestOnionServiceName(t *testing.T) {
+	testCases := []struct {
+		domain     string
+		expected   string
+		requireErr require.ErrorAssertionFunc
+	}{
+		{domain: "abc.onion", expected: "abc.onion", requireErr: require.NoError},
+		{domain: "www.abc.onion", expected: "abc.onion", requireErr: require.NoError},
+		{domain: "www.ABC.onion.", expected: "abc.onion", requireErr: require.NoError},
+		{domain: "example.com", requireErr: require.Error},
+		{domain: "onion", requireErr: require.Error},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.domain, func(t *testing.T) {
+			t.Parallel()
+
+			name, err := OnionServiceName(test.domain)
+			test.requireErr(t, err)
+
+			assert.Equal(t, test.expected, name)
+		})
+	}
+}
+
+func TestGenerateOnionCSR(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	address := OnionAddress(publicKey)
+
+	caNonce := []byte("0123456789abcdef")
+
+	raw, err := GenerateOnionCSR(privateKey, []string{address, "*." + address}, caNonce)
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	require.NoError(t, csr.CheckSignature())
+
+	assert.Equal(t, address, csr.Subject.CommonName)
+	assert.Equal(t, []string{address, "*." + address}, csr.DNSNames)
+	assert.Equal(t, publicKey, csr.PublicKey)
+
+	var info struct {
+		Raw        asn1.RawContent
+		Version    int
+		Subject    asn1.RawValue
+		PublicKey  asn1.RawValue
+		Attributes []struct {
+			Type   asn1.ObjectIdentifier
+			Values []asn1.RawValue `asn1:"set"`
+		} `asn1:"tag:0"`
+	}
+
+	_, err = asn1.Unmarshal(csr.RawTBSCertificateRequest, &info)
+	require.NoError(t, err)
+
+	nonces := map[string][]byte{}
+
+	for _, attr := range info.Attributes {
+		require.Len(t, attr.Values, 1)
+
+		if !attr.Type.Equal(oidCASigningNonce) && !attr.Type.Equal(oidApplicantSigningNonce) {
+			continue
+		}
+
+		var nonce []byte
+		_, err = asn1.Unmarshal(attr.Values[0].FullBytes, &nonce)
+		require.NoError(t, err)
+
+		nonces[attr.Type.String()] = nonce
+	}
+
+	assert.Equal(t, caNonce, nonces[oidCASigningNonce.String()])
+	assert.Len(t, nonces[oidApplicantSigningNonce.String()], applicantNonceSize)
+}
+
+func TestGenerateOnionCSR_errors(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	address := OnionAddress(publicKey)
+
+	ecKey, err := GeneratePrivateKey(EC256)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		signer   crypto.Signer
+		domains  []string
+		caNonce  []byte
+		expected string
+	}{
+		{
+			desc:     "not an Ed25519 key",
+			signer:   ecKey.(*ecdsa.PrivateKey),
+			domains:  []string{address},
+			caNonce:  []byte("0123456789abcdef"),
+			expected: "the key of a hidden service must be an Ed25519 key: *ecdsa.PublicKey",
+		},
+		{
+			desc:     "short nonce",
+			signer:   privateKey,
+			domains:  []string{address},
+			caNonce:  []byte("0123"),
+			expected: "the CA nonce must have at least 64 bits",
+		},
+		{
+			desc:     "other address",
+			signer:   privateKey,
+			domains:  []string{"www.example.onion"},
+			caNonce:  []byte("0123456789abcdef"),
+			expected: "www.example.onion doesn't match the address of the hidden service key (" + address + ")",
+		},
+		{
+			desc:     "not a .onion name",
+			signer:   privateKey,
+			domains:  []string{"example.com"},
+			caNonce:  []byte("0123456789abcdef"),
+			expected: "example.com is not a .onion name",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := GenerateOnionCSR(test.signer, test.domains, test.caNonce)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package pkcs11 handles the keys stored in a PKCS#11 token (HSM, smart card, ...) identified by a PKCS#11 URI (RFC 7512).
+//
+// This package is a hook for the applications using lego as a library, the lego binary doesn't support the PKCS#11 tokens:
+// lego doesn't embed a PKCS#11 implementation, an Opener (ex: based on `github.com/ThalesGroup/crypto11`) must be registered with Register.
+package pkcs11
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/digicert/lego/v4/certcrypto"
+)
+
+// Scheme the scheme of the PKCS#11 URIs.
+const Scheme = "pkcs11"
+
+// URI a PKCS#11 URI (RFC 7512).
+// Example: `pkcs11:token=lego;object=account?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=/etc/lego/pin`.
+type URI struct {
+	// Path attributes.
+	Token        string
+	Manufacturer string
+	Serial       string
+	Model        string
+	SlotID       *int
+	Object       string
+	Type         string
+	ID           []byte
+
+	// Query attributes.
+	ModuleName string
+	ModulePath string
+	PINValue   string
+	PINSource  string
+}
+
+// Opener opens a key stored in a PKCS#11 token.
+type Opener func(ctx context.Context, uri *URI) (crypto.Signer, error)
+
+// Register registers an Opener as the certcrypto.KeyProvider of the PKCS#11 URIs.
+func Register(opener Opener) {
+	certcrypto.RegisterKeyProvider(Scheme, func(ctx context.Context, raw string) (crypto.Signer, error) {
+		uri, err := ParseURI(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		return opener(ctx, uri)
+	})
+}
+
+// ParseURI parses a PKCS#11 URI (RFC 7512).
+func ParseURI(raw string) (*URI, error) {
+	rest, ok := strings.CutPrefix(raw, Scheme+":")
+	if !ok {
+		return nil, fmt.Errorf("pkcs11: invalid URI: the scheme must be %q", Scheme)
+	}
+
+	pathPart, queryPart, _ := strings.Cut(rest, "?")
+
+	uri := &URI{}
+
+	for attr := range strings.SplitSeq(pathPart, ";") {
+		if attr == "" {
+			continue
+		}
+
+		name, value, err := parseAttribute(attr)
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "token":
+			uri.Token = value
+		case "manufacturer":
+			uri.Manufacturer = value
+		case "serial":
+			uri.Serial = value
+		case "model":
+			uri.Model = value
+		case "slot-id":
+			slotID, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("pkcs11: invalid slot-id: %w", err)
+			}
+
+			uri.SlotID = &slotID
+		case "object":
+			uri.Object = value
+		case "type":
+			uri.Type = value
+		case "id":
+			uri.ID = []byte(value)
+		default:
+			// The unknown attributes are ignored (RFC 7512, section 2.3).
+		}
+	}
+
+	for attr := range strings.SplitSeq(queryPart, "&") {
+		if attr == "" {
+			continue
+		}
+
+		name, value, err := parseAttribute(attr)
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "module-name":
+			uri.ModuleName = value
+		case "module-path":
+			uri.ModulePath = value
+		case "pin-value":
+			uri.PINValue = value
+		case "pin-source":
+			uri.PINSource = value
+		default:
+			// The unknown attributes are ignored (RFC 7512, section 2.3).
+		}
+	}
+
+	if uri.PINValue != "" && uri.PINSource != "" {
+		return nil, errors.New("pkcs11: pin-value and pin-source are mutually exclusive")
+	}
+
+	return uri, nil
+}
+
+// PIN returns the PIN of the token: the pin-value attribute, or the content of the file defined by the pin-source attribute.
+func (u *URI) PIN() (string, error) {
+	if u.PINSource == "" {
+		return u.PINValue, nil
+	}
+
+	data, err := os.ReadFile(strings.TrimPrefix(u.PINSource, "file:"))
+	if err != nil {
+		return "", fmt.Errorf("pkcs11: read pin-source: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func parseAttribute(attr string) (name, value string, err error) {
+	name, rawValue, ok := strings.Cut(attr, "=")
+	if !ok {
+		return "", "", fmt.Errorf("pkcs11: invalid attribute: %q", attr)
+	}
+
+	value, err = url.PathUnescape(rawValue)
+	if err != nil {
+		return "", "", fmt.Errorf("pkcs11: invalid attribute %q: %w", name, err)
+	}
+
+	return strings.ToLower(name), value, nil
+}
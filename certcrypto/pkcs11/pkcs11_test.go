@@ -0,0 +1,134 @@
+package pkcs11
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURI(t *testing.T) {
+	slotID := 2
+
+	testCases := []struct {
+		desc     string
+		uri      string
+		expected *URI
+	}{
+		{
+			desc:     "token and object",
+			uri:      "pkcs11:token=lego;object=account",
+			expected: &URI{Token: "lego", Object: "account"},
+		},
+		{
+			desc: "all the attributes",
+			uri:  "pkcs11:token=My%20Token;manufacturer=SoftHSM;serial=1234;model=v2;slot-id=2;object=account;type=private;id=%01%02?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-value=1234",
+			expected: &URI{
+				Token:        "My Token",
+				Manufacturer: "SoftHSM",
+				Serial:       "1234",
+				Model:        "v2",
+				SlotID:       &slotID,
+				Object:       "account",
+				Type:         "private",
+				ID:           []byte{1, 2},
+				ModulePath:   "/usr/lib/softhsm/libsofthsm2.so",
+				PINValue:     "1234",
+			},
+		},
+		{
+			desc:     "unknown attributes",
+			uri:      "pkcs11:token=lego;x-foo=bar?x-bar=foo",
+			expected: &URI{Token: "lego"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			uri, err := ParseURI(test.uri)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, uri)
+		})
+	}
+}
+
+func TestParseURI_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		uri      string
+		expected string
+	}{
+		{
+			desc:     "invalid scheme",
+			uri:      "tpm:handle=1",
+			expected: `pkcs11: invalid URI: the scheme must be "pkcs11"`,
+		},
+		{
+			desc:     "invalid attribute",
+			uri:      "pkcs11:token",
+			expected: `pkcs11: invalid attribute: "token"`,
+		},
+		{
+			desc:     "invalid slot-id",
+			uri:      "pkcs11:slot-id=a",
+			expected: `pkcs11: invalid slot-id: strconv.Atoi: parsing "a": invalid syntax`,
+		},
+		{
+			desc:     "pin-value and pin-source",
+			uri:      "pkcs11:token=lego?pin-value=1234&pin-source=/tmp/pin",
+			expected: "pkcs11: pin-value and pin-source are mutually exclusive",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseURI(test.uri)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestURI_PIN(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pin")
+
+	require.NoError(t, os.WriteFile(file, []byte("1234\n"), 0o600))
+
+	uri, err := ParseURI("pkcs11:token=lego?pin-source=file:" + file)
+	require.NoError(t, err)
+
+	pin, err := uri.PIN()
+	require.NoError(t, err)
+
+	assert.Equal(t, "1234", pin)
+}
+
+func TestRegister(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	Register(func(_ context.Context, uri *URI) (crypto.Signer, error) {
+		assert.Equal(t, "account", uri.Object)
+
+		return key, nil
+	})
+
+	assert.True(t, certcrypto.IsKeyURI("pkcs11:token=lego;object=account"))
+
+	signer, err := certcrypto.LoadKey(t.Context(), "pkcs11:token=lego;object=account")
+	require.NoError(t, err)
+
+	assert.Equal(t, key, signer)
+}
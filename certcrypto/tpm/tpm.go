@@ -0,0 +1,180 @@
+// Package tpm handles the private keys sealed in the local TPM 2.0, identified by a TPM URI.
+//
+// On Windows, the keys are managed by the "Microsoft Platform Crypto Provider" (TPM-backed key storage provider).
+// On the other platforms, an Opener (ex: based on `github.com/google/go-tpm`) must be registered with Register.
+//
+// The keys are generated inside the TPM when they don't exist, and they never leave it:
+// only the public key is exported, and the signatures (CSR, JWS) are made by the TPM.
+package tpm
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/digicert/lego/v4/certcrypto"
+)
+
+// Scheme the scheme of the TPM URIs.
+const Scheme = "tpm"
+
+// Key scopes.
+const (
+	ScopeUser    = "user"
+	ScopeMachine = "machine"
+)
+
+// URI a TPM key URI.
+// Example: `tpm:name=lego-example.com;type=P256;scope=machine`.
+type URI struct {
+	// Name the name of the key.
+	Name string
+	// KeyType the type of the key, used when the key is generated (default: P256).
+	KeyType certcrypto.KeyType
+	// Scope the scope of the key: user (default) or machine.
+	Scope string
+}
+
+// Opener opens (or generates) a key sealed in the TPM.
+type Opener func(ctx context.Context, uri *URI) (crypto.Signer, error)
+
+// Register registers an Opener as the certcrypto.KeyProvider of the TPM URIs.
+func Register(opener Opener) {
+	certcrypto.RegisterKeyProvider(Scheme, func(ctx context.Context, raw string) (crypto.Signer, error) {
+		uri, err := ParseURI(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		return opener(ctx, uri)
+	})
+}
+
+// ParseURI parses a TPM key URI.
+func ParseURI(raw string) (*URI, error) {
+	rest, ok := strings.CutPrefix(raw, Scheme+":")
+	if !ok {
+		return nil, fmt.Errorf("tpm: invalid URI: the scheme must be %q", Scheme)
+	}
+
+	uri := &URI{KeyType: certcrypto.EC256, Scope: ScopeUser}
+
+	for attr := range strings.SplitSeq(rest, ";") {
+		if attr == "" {
+			continue
+		}
+
+		name, rawValue, ok := strings.Cut(attr, "=")
+		if !ok {
+			return nil, fmt.Errorf("tpm: invalid attribute: %q", attr)
+		}
+
+		value, err := url.PathUnescape(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("tpm: invalid attribute %q: %w", name, err)
+		}
+
+		switch strings.ToLower(name) {
+		case "name":
+			uri.Name = value
+		case "type":
+			uri.KeyType = certcrypto.KeyType(value)
+		case "scope":
+			uri.Scope = strings.ToLower(value)
+		default:
+			return nil, fmt.Errorf("tpm: unknown attribute: %q", name)
+		}
+	}
+
+	if uri.Name == "" {
+		return nil, errors.New("tpm: the key name is missing")
+	}
+
+	switch uri.KeyType {
+	case certcrypto.EC256, certcrypto.EC384, certcrypto.RSA2048:
+	default:
+		return nil, fmt.Errorf("tpm: unsupported key type: %q", uri.KeyType)
+	}
+
+	if uri.Scope != ScopeUser && uri.Scope != ScopeMachine {
+		return nil, fmt.Errorf("tpm: invalid scope: %q", uri.Scope)
+	}
+
+	return uri, nil
+}
+
+// parseECCPublicBlob parses a BCRYPT_ECCKEY_BLOB (magic, key size, X, Y).
+func parseECCPublicBlob(blob []byte) (*ecdsa.PublicKey, error) {
+	if len(blob) < 8 {
+		return nil, errors.New("invalid ECC public key blob")
+	}
+
+	size := int(binary.LittleEndian.Uint32(blob[4:8]))
+
+	if len(blob) != 8+2*size {
+		return nil, errors.New("invalid ECC public key blob: invalid length")
+	}
+
+	var curve elliptic.Curve
+
+	switch size {
+	case 32:
+		curve = elliptic.P256()
+	case 48:
+		curve = elliptic.P384()
+	default:
+		return nil, fmt.Errorf("invalid ECC public key blob: unsupported key size: %d", size)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(blob[8 : 8+size]),
+		Y:     new(big.Int).SetBytes(blob[8+size:]),
+	}, nil
+}
+
+// parseRSAPublicBlob parses a BCRYPT_RSAKEY_BLOB (magic, bit length, exponent size, modulus size, ..., exponent, modulus).
+func parseRSAPublicBlob(blob []byte) (*rsa.PublicKey, error) {
+	if len(blob) < 24 {
+		return nil, errors.New("invalid RSA public key blob")
+	}
+
+	expSize := int(binary.LittleEndian.Uint32(blob[8:12]))
+	modSize := int(binary.LittleEndian.Uint32(blob[12:16]))
+
+	if expSize > 8 || len(blob) < 24+expSize+modSize {
+		return nil, errors.New("invalid RSA public key blob: invalid length")
+	}
+
+	exponent := new(big.Int).SetBytes(blob[24 : 24+expSize])
+
+	return &rsa.PublicKey{
+		E: int(exponent.Int64()),
+		N: new(big.Int).SetBytes(blob[24+expSize : 24+expSize+modSize]),
+	}, nil
+}
+
+// toASN1Signature converts an ECDSA signature (concatenation of R and S) to the ASN.1 format used by crypto.Signer.
+func toASN1Signature(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, errors.New("invalid ECDSA signature")
+	}
+
+	size := len(raw) / 2
+
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(raw[:size]),
+		S: new(big.Int).SetBytes(raw[size:]),
+	})
+}
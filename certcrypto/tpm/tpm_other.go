@@ -0,0 +1,14 @@
+//go:build !windows
+
+package tpm
+
+import (
+	"context"
+	"crypto"
+	"errors"
+)
+
+// Open opens (or generates) a key sealed in the TPM.
+func Open(_ context.Context, _ *URI) (crypto.Signer, error) {
+	return nil, errors.New("tpm: the TPM keys are only supported on Windows, an Opener must be registered on this platform")
+}
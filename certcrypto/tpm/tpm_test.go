@@ -0,0 +1,160 @@
+package tpm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURI(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		uri      string
+		expected *URI
+	}{
+		{
+			desc:     "name only",
+			uri:      "tpm:name=lego",
+			expected: &URI{Name: "lego", KeyType: certcrypto.EC256, Scope: ScopeUser},
+		},
+		{
+			desc:     "all the attributes",
+			uri:      "tpm:name=lego%20cert;type=2048;scope=MACHINE",
+			expected: &URI{Name: "lego cert", KeyType: certcrypto.RSA2048, Scope: ScopeMachine},
+		},
+		{
+			desc:     "P384",
+			uri:      "tpm:type=P384;name=lego;",
+			expected: &URI{Name: "lego", KeyType: certcrypto.EC384, Scope: ScopeUser},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			uri, err := ParseURI(test.uri)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, uri)
+		})
+	}
+}
+
+func TestParseURI_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		uri      string
+		expected string
+	}{
+		{
+			desc:     "invalid scheme",
+			uri:      "pkcs11:name=lego",
+			expected: `tpm: invalid URI: the scheme must be "tpm"`,
+		},
+		{
+			desc:     "missing name",
+			uri:      "tpm:type=P256",
+			expected: "tpm: the key name is missing",
+		},
+		{
+			desc:     "unknown attribute",
+			uri:      "tpm:name=lego;foo=bar",
+			expected: `tpm: unknown attribute: "foo"`,
+		},
+		{
+			desc:     "invalid attribute",
+			uri:      "tpm:name",
+			expected: `tpm: invalid attribute: "name"`,
+		},
+		{
+			desc:     "unsupported key type",
+			uri:      "tpm:name=lego;type=4096",
+			expected: `tpm: unsupported key type: "4096"`,
+		},
+		{
+			desc:     "invalid scope",
+			uri:      "tpm:name=lego;scope=global",
+			expected: `tpm: invalid scope: "global"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseURI(test.uri)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func Test_parseECCPublicBlob(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	blob := binary.LittleEndian.AppendUint32(nil, 0x33534345) // BCRYPT_ECDSA_PUBLIC_P384_MAGIC
+	blob = binary.LittleEndian.AppendUint32(blob, 48)
+	blob = append(blob, privateKey.X.FillBytes(make([]byte, 48))...)
+	blob = append(blob, privateKey.Y.FillBytes(make([]byte, 48))...)
+
+	publicKey, err := parseECCPublicBlob(blob)
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(publicKey))
+
+	_, err = parseECCPublicBlob(blob[:50])
+	require.Error(t, err)
+}
+
+func Test_parseRSAPublicBlob(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	exponent := []byte{0x01, 0x00, 0x01}
+	modulus := privateKey.N.Bytes()
+
+	blob := binary.LittleEndian.AppendUint32(nil, 0x31415352) // BCRYPT_RSAPUBLIC_MAGIC
+	blob = binary.LittleEndian.AppendUint32(blob, 2048)
+	blob = binary.LittleEndian.AppendUint32(blob, uint32(len(exponent)))
+	blob = binary.LittleEndian.AppendUint32(blob, uint32(len(modulus)))
+	blob = append(blob, make([]byte, 8)...)
+	blob = append(blob, exponent...)
+	blob = append(blob, modulus...)
+
+	publicKey, err := parseRSAPublicBlob(blob)
+	require.NoError(t, err)
+
+	assert.True(t, privateKey.PublicKey.Equal(publicKey))
+
+	_, err = parseRSAPublicBlob(blob[:100])
+	require.Error(t, err)
+}
+
+func Test_toASN1Signature(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("lego"))
+
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+	require.NoError(t, err)
+
+	raw := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	signature, err := toASN1Signature(raw)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+
+	_, err = toASN1Signature(raw[:63])
+	require.Error(t, err)
+}
@@ -0,0 +1,233 @@
+//go:build windows
+
+package tpm
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"golang.org/x/sys/windows"
+)
+
+// ProviderName the name of the TPM-backed key storage provider.
+const ProviderName = "Microsoft Platform Crypto Provider"
+
+const (
+	nteBadKeyset         = 0x80090016
+	ncryptMachineKeyFlag = 0x00000020
+	bcryptPadPKCS1       = 0x00000002
+)
+
+var (
+	ncrypt = windows.NewLazySystemDLL("ncrypt.dll")
+
+	procNCryptOpenStorageProvider = ncrypt.NewProc("NCryptOpenStorageProvider")
+	procNCryptOpenKey             = ncrypt.NewProc("NCryptOpenKey")
+	procNCryptCreatePersistedKey  = ncrypt.NewProc("NCryptCreatePersistedKey")
+	procNCryptSetProperty         = ncrypt.NewProc("NCryptSetProperty")
+	procNCryptFinalizeKey         = ncrypt.NewProc("NCryptFinalizeKey")
+	procNCryptExportKey           = ncrypt.NewProc("NCryptExportKey")
+	procNCryptSignHash            = ncrypt.NewProc("NCryptSignHash")
+	procNCryptFreeObject          = ncrypt.NewProc("NCryptFreeObject")
+)
+
+var _ crypto.Signer = (*signer)(nil)
+
+// signer a key stored in the TPM.
+type signer struct {
+	key       uintptr
+	publicKey crypto.PublicKey
+}
+
+// Open opens (or generates) a key sealed in the TPM.
+func Open(_ context.Context, uri *URI) (crypto.Signer, error) {
+	var flags uintptr
+	if uri.Scope == ScopeMachine {
+		flags = ncryptMachineKeyFlag
+	}
+
+	var provider uintptr
+
+	err := call(procNCryptOpenStorageProvider, uintptr(unsafe.Pointer(&provider)), utf16Ptr(ProviderName), 0)
+	if err != nil {
+		return nil, fmt.Errorf("tpm: open provider: %w", err)
+	}
+
+	defer func() { _ = call(procNCryptFreeObject, provider) }()
+
+	var key uintptr
+
+	err = call(procNCryptOpenKey, provider, uintptr(unsafe.Pointer(&key)), utf16Ptr(uri.Name), 0, flags)
+
+	var errno windows.Errno
+	if errors.As(err, &errno) && uint32(errno) == nteBadKeyset {
+		key, err = createKey(provider, uri, flags)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("tpm: open key %s: %w", uri.Name, err)
+	}
+
+	publicKey, err := exportPublicKey(key, uri.KeyType)
+	if err != nil {
+		_ = call(procNCryptFreeObject, key)
+		return nil, fmt.Errorf("tpm: export public key %s: %w", uri.Name, err)
+	}
+
+	return &signer{key: key, publicKey: publicKey}, nil
+}
+
+func (s *signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+func (s *signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	switch s.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		raw, err := signHash(s.key, 0, digest, 0)
+		if err != nil {
+			return nil, fmt.Errorf("tpm: sign: %w", err)
+		}
+
+		return toASN1Signature(raw)
+
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			return nil, errors.New("tpm: RSA-PSS signatures are not supported")
+		}
+
+		var algID string
+
+		switch opts.HashFunc() {
+		case crypto.SHA256:
+			algID = "SHA256"
+		case crypto.SHA384:
+			algID = "SHA384"
+		case crypto.SHA512:
+			algID = "SHA512"
+		default:
+			return nil, fmt.Errorf("tpm: unsupported hash function: %s", opts.HashFunc())
+		}
+
+		// BCRYPT_PKCS1_PADDING_INFO
+		paddingInfo := struct{ algID *uint16 }{algID: windows.StringToUTF16Ptr(algID)}
+
+		signature, err := signHash(s.key, uintptr(unsafe.Pointer(&paddingInfo)), digest, bcryptPadPKCS1)
+		if err != nil {
+			return nil, fmt.Errorf("tpm: sign: %w", err)
+		}
+
+		return signature, nil
+
+	default:
+		return nil, fmt.Errorf("tpm: unsupported public key type: %T", s.publicKey)
+	}
+}
+
+func createKey(provider uintptr, uri *URI, flags uintptr) (uintptr, error) {
+	var algorithm string
+
+	switch uri.KeyType {
+	case certcrypto.EC256:
+		algorithm = "ECDSA_P256"
+	case certcrypto.EC384:
+		algorithm = "ECDSA_P384"
+	default:
+		algorithm = "RSA"
+	}
+
+	var key uintptr
+
+	err := call(procNCryptCreatePersistedKey, provider, uintptr(unsafe.Pointer(&key)),
+		utf16Ptr(algorithm), utf16Ptr(uri.Name), 0, flags)
+	if err != nil {
+		return 0, fmt.Errorf("create: %w", err)
+	}
+
+	if algorithm == "RSA" {
+		length := uint32(2048)
+
+		err = call(procNCryptSetProperty, key, utf16Ptr("Length"), uintptr(unsafe.Pointer(&length)), 4, 0)
+		if err != nil {
+			_ = call(procNCryptFreeObject, key)
+			return 0, fmt.Errorf("set length: %w", err)
+		}
+	}
+
+	err = call(procNCryptFinalizeKey, key, 0)
+	if err != nil {
+		_ = call(procNCryptFreeObject, key)
+		return 0, fmt.Errorf("finalize: %w", err)
+	}
+
+	return key, nil
+}
+
+func exportPublicKey(key uintptr, keyType certcrypto.KeyType) (crypto.PublicKey, error) {
+	blobType := "ECCPUBLICBLOB"
+	if keyType == certcrypto.RSA2048 {
+		blobType = "RSAPUBLICBLOB"
+	}
+
+	var size uint32
+
+	err := call(procNCryptExportKey, key, 0, utf16Ptr(blobType), 0, 0, 0, uintptr(unsafe.Pointer(&size)), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, size)
+
+	err = call(procNCryptExportKey, key, 0, utf16Ptr(blobType), 0,
+		uintptr(unsafe.Pointer(&blob[0])), uintptr(size), uintptr(unsafe.Pointer(&size)), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if blobType == "RSAPUBLICBLOB" {
+		return parseRSAPublicBlob(blob[:size])
+	}
+
+	return parseECCPublicBlob(blob[:size])
+}
+
+func signHash(key, paddingInfo uintptr, digest []byte, flags uintptr) ([]byte, error) {
+	var size uint32
+
+	err := call(procNCryptSignHash, key, paddingInfo, uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)),
+		0, 0, uintptr(unsafe.Pointer(&size)), flags)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := make([]byte, size)
+
+	err = call(procNCryptSignHash, key, paddingInfo, uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)),
+		uintptr(unsafe.Pointer(&signature[0])), uintptr(size), uintptr(unsafe.Pointer(&size)), flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return signature[:size], nil
+}
+
+// call calls a NCrypt function, the result is a SECURITY_STATUS.
+func call(proc *windows.LazyProc, args ...uintptr) error {
+	r, _, _ := proc.Call(args...)
+	if r != 0 {
+		return windows.Errno(r)
+	}
+
+	return nil
+}
+
+func utf16Ptr(value string) uintptr {
+	return uintptr(unsafe.Pointer(windows.StringToUTF16Ptr(value)))
+}
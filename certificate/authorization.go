@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/digicert/lego/v4/acme"
-	"github.com/digicert/lego/v4/log"
 )
 
 func (c *Certifier) getAuthorizations(order acme.ExtendedOrder) ([]acme.Authorization, error) {
@@ -40,7 +39,7 @@ func (c *Certifier) getAuthorizations(order acme.ExtendedOrder) ([]acme.Authoriz
 	}
 
 	for i, auth := range order.Authorizations {
-		log.Infof("[%s] AuthURL: %s", order.Identifiers[i].Value, auth)
+		c.core.Logger().Info("acme: authorization.", "domain", order.Identifiers[i].Value, "url", auth)
 	}
 
 	close(resc)
@@ -53,19 +52,19 @@ func (c *Certifier) deactivateAuthorizations(order acme.ExtendedOrder, force boo
 	for _, authzURL := range order.Authorizations {
 		auth, err := c.core.Authorizations.Get(authzURL)
 		if err != nil {
-			log.Infof("Unable to get the authorization for %s: %v", authzURL, err)
+			c.core.Logger().Warn("acme: Unable to get the authorization.", "url", authzURL, "error", err)
 			continue
 		}
 
 		if auth.Status == acme.StatusValid && !force {
-			log.Infof("Skipping deactivating of valid auth: %s", authzURL)
+			c.core.Logger().Info("acme: Skipping deactivating of valid authorization.", "url", authzURL)
 			continue
 		}
 
-		log.Infof("Deactivating auth: %s", authzURL)
+		c.core.Logger().Info("acme: Deactivating authorization.", "url", authzURL)
 
 		if c.core.Authorizations.Deactivate(authzURL) != nil {
-			log.Infof("Unable to deactivate the authorization: %s", authzURL)
+			c.core.Logger().Warn("acme: Unable to deactivate the authorization.", "url", authzURL)
 		}
 	}
 }
@@ -0,0 +1,373 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/audit"
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/metrics"
+	"github.com/digicert/lego/v4/progress"
+)
+
+// BatchOptions the options of ObtainBatch.
+type BatchOptions struct {
+	// Concurrency the maximum number of orders finalized at the same time (default: 1).
+	Concurrency int
+}
+
+// BatchResult the result of a request of ObtainBatch.
+type BatchResult struct {
+	Resource *Resource
+	Err      error
+}
+
+type batchItem struct {
+	request ObtainRequest
+	domains []string
+	event   *audit.Event
+	order   acme.ExtendedOrder
+	err     error
+}
+
+// ObtainBatch obtains several certificates.
+// The results are in the same order as the requests.
+//
+// All the orders are created first, then their authorizations are solved together:
+// an authorization shared by several orders is solved only once,
+// and the authorizations already valid (reused by the CA) are skipped.
+// A failed authorization only fails the requests using it.
+//
+// Like Obtain, a request never returns a partial certificate.
+func (c *Certifier) ObtainBatch(requests []ObtainRequest, opts *BatchOptions) []BatchResult {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+
+	c.core.Logger().Info("acme: Obtaining a batch of certificates.", "count", len(requests))
+
+	c.core.Progress().Start(progress.PhaseOrder, "")
+
+	items := make([]*batchItem, len(requests))
+
+	// The CA can return the same pending order for the same identifiers.
+	orders := make(map[string]int)
+
+	for i, request := range requests {
+		item := &batchItem{
+			request: request,
+			event:   &audit.Event{Action: audit.ActionObtain, Domains: request.Domains},
+		}
+
+		items[i] = item
+
+		item.err = c.createBatchOrder(item)
+		if item.err != nil {
+			continue
+		}
+
+		if j, ok := orders[item.order.Location]; ok {
+			item.err = fmt.Errorf("same order as the request %d (%s)", j, item.order.Location)
+			continue
+		}
+
+		orders[item.order.Location] = i
+	}
+
+	c.core.Progress().Start(progress.PhaseAuthorization, "")
+
+	failed := c.solveBatch(items)
+
+	for _, item := range items {
+		if item.err != nil || item.order.Location == "" {
+			continue
+		}
+
+		failures := newObtainError()
+
+		for _, authzURL := range item.order.Authorizations {
+			if f, ok := failed[authzURL]; ok {
+				failures.Add(f.domain, f.err)
+			}
+		}
+
+		item.err = failures.Join()
+		if item.err != nil {
+			// Do not generate partial SAN certificates.
+			c.deactivateAuthorizations(item.order, item.request.AlwaysDeactivateAuthorizations)
+		}
+	}
+
+	c.core.Progress().Start(progress.PhaseFinalization, "")
+
+	results := c.finalizeBatch(items, opts.Concurrency)
+
+	var succeeded bool
+
+	for i, item := range items {
+		metrics.RecordIssuance(results[i].Err)
+		c.audit(item.event, results[i].Resource, results[i].Err)
+		c.publishObtained(item.event, results[i].Resource, results[i].Err)
+
+		succeeded = succeeded || results[i].Err == nil
+	}
+
+	if succeeded {
+		c.done(nil)
+	}
+
+	return results
+}
+
+func (c *Certifier) createBatchOrder(item *batchItem) error {
+	if len(item.request.Domains) == 0 {
+		return errors.New("no domains to obtain a certificate for")
+	}
+
+	domains, err := ValidateIdentifiers(item.request.Domains)
+	if err != nil {
+		return err
+	}
+
+	item.domains = domains
+
+	orderOpts := &api.OrderOptions{
+		NotBefore:      item.request.NotBefore,
+		NotAfter:       item.request.NotAfter,
+		Profile:        item.request.Profile,
+		ReplacesCertID: item.request.ReplacesCertID,
+	}
+
+	order, err := c.core.Orders.NewWithOptions(item.domains, orderOpts)
+	if err != nil {
+		return err
+	}
+
+	item.order = order
+	item.event.OrderURL = order.Location
+
+	c.core.Events().Publish(events.OrderCreated{Domains: item.domains, OrderURL: order.Location})
+
+	return nil
+}
+
+type batchFailure struct {
+	domain string
+	err    error
+}
+
+// solveBatch solves the authorizations of the orders, and returns the failed authorizations (by URL).
+//
+// The authorizations are deduplicated by URL (the CAs usually reuse the pending authorizations of an account).
+// The different authorizations of the same identifier are solved in successive rounds,
+// an identifier cannot be solved twice at the same time (ex: the same DNS record).
+func (c *Certifier) solveBatch(items []*batchItem) map[string]batchFailure {
+	failed := make(map[string]batchFailure)
+
+	var (
+		urls        []string
+		identifiers = make(map[string]string)
+	)
+
+	for _, item := range items {
+		if item.err != nil {
+			continue
+		}
+
+		for i, authzURL := range item.order.Authorizations {
+			if !slices.Contains(urls, authzURL) {
+				urls = append(urls, authzURL)
+			}
+
+			if i < len(item.order.Identifiers) {
+				identifiers[authzURL] = item.order.Identifiers[i].Value
+			}
+		}
+	}
+
+	authorizations := c.getBatchAuthorizations(urls, identifiers, failed)
+
+	// The rounds: the pending authorizations of each identifier.
+	var (
+		domains []string
+		pending = make(map[string][]string)
+	)
+
+	for _, authzURL := range urls {
+		authz, ok := authorizations[authzURL]
+		if !ok || authz.Status == acme.StatusValid {
+			continue
+		}
+
+		domain := challenge.GetTargetedDomain(authz)
+
+		if _, ok := pending[domain]; !ok {
+			domains = append(domains, domain)
+		}
+
+		pending[domain] = append(pending[domain], authzURL)
+	}
+
+	for round := 0; ; round++ {
+		var (
+			roundURLs []string
+			roundAuth []acme.Authorization
+		)
+
+		for _, domain := range domains {
+			if round >= len(pending[domain]) {
+				continue
+			}
+
+			authzURL := pending[domain][round]
+
+			// The identifier has already failed.
+			if f, ok := failedDomain(failed, pending[domain][:round]); ok {
+				failed[authzURL] = f
+				continue
+			}
+
+			roundURLs = append(roundURLs, authzURL)
+			roundAuth = append(roundAuth, authorizations[authzURL])
+		}
+
+		if len(roundAuth) == 0 {
+			break
+		}
+
+		err := c.resolver.Solve(roundAuth)
+		if err == nil {
+			continue
+		}
+
+		// Finds the failed authorizations of the round.
+		for i, authzURL := range roundURLs {
+			authz, errG := c.core.Authorizations.Get(authzURL)
+			if errG == nil && authz.Status == acme.StatusValid {
+				continue
+			}
+
+			domain := challenge.GetTargetedDomain(roundAuth[i])
+
+			failed[authzURL] = batchFailure{domain: domain, err: domainErr(err, domain)}
+		}
+	}
+
+	return failed
+}
+
+// domainErrors is implemented by the errors of the resolver with an error by domain.
+type domainErrors interface {
+	DomainError(domain string) error
+}
+
+// domainErr returns the error of the domain, or the error of the round if the resolver doesn't provide it.
+func domainErr(err error, domain string) error {
+	var errs domainErrors
+	if errors.As(err, &errs) {
+		if errD := errs.DomainError(domain); errD != nil {
+			return errD
+		}
+	}
+
+	return err
+}
+
+func failedDomain(failed map[string]batchFailure, urls []string) (batchFailure, bool) {
+	for _, authzURL := range urls {
+		if f, ok := failed[authzURL]; ok {
+			return f, true
+		}
+	}
+
+	return batchFailure{}, false
+}
+
+// getBatchAuthorizations fetches the authorizations, the failures are added to failed.
+// The identifiers (by URL) are the identifiers of the orders, used to report the authorizations that cannot be fetched.
+func (c *Certifier) getBatchAuthorizations(urls []string, identifiers map[string]string, failed map[string]batchFailure) map[string]acme.Authorization {
+	delay := time.Second / time.Duration(c.overallRequestLimit)
+
+	var (
+		mu             sync.Mutex
+		wg             sync.WaitGroup
+		authorizations = make(map[string]acme.Authorization)
+	)
+
+	for _, authzURL := range urls {
+		time.Sleep(delay)
+
+		wg.Go(func() {
+			authz, err := c.core.Authorizations.Get(authzURL)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				failed[authzURL] = batchFailure{domain: identifiers[authzURL], err: err}
+				return
+			}
+
+			authorizations[authzURL] = authz
+		})
+	}
+
+	wg.Wait()
+
+	return authorizations
+}
+
+func (c *Certifier) finalizeBatch(items []*batchItem, concurrency int) []BatchResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(items))
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		if item.err != nil {
+			results[i] = BatchResult{Err: item.err}
+			continue
+		}
+
+		sem <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			c.core.Logger().Info("acme: Validations succeeded; requesting certificates.", "domains", strings.Join(item.domains, ", "))
+
+			cert, err := c.getForOrder(context.Background(), item.domains, item.order, item.request)
+			if err != nil {
+				failures := newObtainError()
+				for _, domain := range item.domains {
+					failures.Add(domain, err)
+				}
+
+				err = failures.Join()
+			}
+
+			if item.request.AlwaysDeactivateAuthorizations {
+				c.deactivateAuthorizations(item.order, true)
+			}
+
+			results[i] = BatchResult{Resource: cert, Err: err}
+		})
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,317 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchACMEServer a fake CA: the authorizations are stored by URL.
+type batchACMEServer struct {
+	mu sync.Mutex
+
+	// reuse if true, the pending authorizations of an identifier are reused by the orders.
+	reuse bool
+
+	orders int
+	status map[string]string
+	domain map[string]string
+}
+
+func newBatchACMEServer(reuse bool, valid ...string) *batchACMEServer {
+	s := &batchACMEServer{reuse: reuse, status: make(map[string]string), domain: make(map[string]string)}
+
+	for _, domain := range valid {
+		s.status["/authz/"+domain] = acme.StatusValid
+		s.domain["/authz/"+domain] = domain
+	}
+
+	return s
+}
+
+func (s *batchACMEServer) build(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return tester.MockACMEServer().
+		Route("POST /newOrder", http.HandlerFunc(s.newOrder)).
+		Route("POST /authz/", http.HandlerFunc(s.getAuthorization)).
+		Route("POST /finalize/", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_ = json.NewEncoder(rw).Encode(acme.Order{
+				Status:      acme.StatusValid,
+				Certificate: "https://" + req.Host + "/certificate",
+			})
+		})).
+		Route("POST /certificate", servermock.RawStringResponse(certResponseMock)).
+		BuildHTTPS(t)
+}
+
+func (s *batchACMEServer) newOrder(rw http.ResponseWriter, req *http.Request) {
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+
+	_ = json.NewDecoder(req.Body).Decode(&jws)
+
+	payload, _ := base64.RawURLEncoding.DecodeString(jws.Payload)
+
+	var order acme.Order
+
+	_ = json.Unmarshal(payload, &order)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders++
+
+	for _, identifier := range order.Identifiers {
+		if identifier.Value == "error.example.com" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(rw).Encode(acme.ProblemDetails{
+				Type:       "urn:ietf:params:acme:error:rejectedIdentifier",
+				Detail:     "forbidden",
+				HTTPStatus: http.StatusBadRequest,
+			})
+
+			return
+		}
+
+		authzURL := "/authz/" + identifier.Value
+		if !s.reuse && s.status[authzURL] != acme.StatusValid {
+			authzURL = fmt.Sprintf("/authz/%s/%d", identifier.Value, s.orders)
+		}
+
+		if _, ok := s.status[authzURL]; !ok {
+			s.status[authzURL] = acme.StatusPending
+			s.domain[authzURL] = identifier.Value
+		}
+
+		order.Authorizations = append(order.Authorizations, "https://"+req.Host+authzURL)
+	}
+
+	order.Status = acme.StatusPending
+	order.Finalize = fmt.Sprintf("https://%s/finalize/%d", req.Host, s.orders)
+
+	rw.Header().Set("Location", fmt.Sprintf("https://%s/order/%d", req.Host, s.orders))
+	rw.WriteHeader(http.StatusCreated)
+
+	_ = json.NewEncoder(rw).Encode(order)
+}
+
+func (s *batchACMEServer) getAuthorization(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.domain[req.URL.Path] == "unavailable.example.com" {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(acme.ProblemDetails{
+			Type:       "urn:ietf:params:acme:error:malformed",
+			Detail:     "authorization not found",
+			HTTPStatus: http.StatusNotFound,
+		})
+
+		return
+	}
+
+	_ = json.NewEncoder(rw).Encode(acme.Authorization{
+		Status:     s.status[req.URL.Path],
+		Identifier: acme.Identifier{Type: "dns", Value: s.domain[req.URL.Path]},
+	})
+}
+
+// solve marks the authorizations of the domains as valid.
+func (s *batchACMEServer) solve(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for authzURL, d := range s.domain {
+		if d == domain && s.status[authzURL] == acme.StatusPending {
+			s.status[authzURL] = acme.StatusValid
+			return
+		}
+	}
+}
+
+// batchSolveError an error by domain, like the errors of the resolver.
+type batchSolveError map[string]error
+
+func (e batchSolveError) Error() string {
+	var errs []error
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...).Error()
+}
+
+func (e batchSolveError) DomainError(domain string) error {
+	return e[domain]
+}
+
+type batchResolverMock struct {
+	server *batchACMEServer
+	fail   map[string]bool
+
+	rounds [][]string
+}
+
+func (r *batchResolverMock) Solve(authorizations []acme.Authorization) error {
+	var domains []string
+
+	failures := make(batchSolveError)
+
+	for _, authz := range authorizations {
+		domain := challenge.GetTargetedDomain(authz)
+		domains = append(domains, domain)
+
+		if r.fail[domain] {
+			failures[domain] = fmt.Errorf("%s: invalid", domain)
+			continue
+		}
+
+		r.server.solve(domain)
+	}
+
+	r.rounds = append(r.rounds, domains)
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return failures
+}
+
+func setupBatchCertifier(t *testing.T, server *batchACMEServer, resolver *batchResolverMock) *Certifier {
+	t.Helper()
+
+	httpServer := server.build(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(httpServer.Client(), "lego-test", httpServer.URL+"/dir", httpServer.URL+"/account/1", key)
+	require.NoError(t, err)
+
+	return NewCertifier(core, resolver, CertifierOptions{KeyType: certcrypto.EC256, OverallRequestLimit: 1000})
+}
+
+func TestCertifier_ObtainBatch(t *testing.T) {
+	server := newBatchACMEServer(true, "c.example.com")
+	resolver := &batchResolverMock{server: server}
+
+	certifier := setupBatchCertifier(t, server, resolver)
+
+	results := certifier.ObtainBatch([]ObtainRequest{
+		{Domains: []string{"a.example.com", "b.example.com"}},
+		{Domains: []string{"b.example.com", "c.example.com"}},
+		{Domains: []string{"error.example.com"}},
+		{},
+	}, &BatchOptions{Concurrency: 2})
+
+	require.Len(t, results, 4)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "a.example.com", results[0].Resource.Domain)
+	assert.NotEmpty(t, results[0].Resource.Certificate)
+
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, "b.example.com", results[1].Resource.Domain)
+
+	require.ErrorContains(t, results[2].Err, "forbidden")
+	require.EqualError(t, results[3].Err, "no domains to obtain a certificate for")
+
+	// The shared authorization is solved once, the valid authorization is skipped.
+	assert.Equal(t, [][]string{{"a.example.com", "b.example.com"}}, resolver.rounds)
+}
+
+func TestCertifier_ObtainBatch_rounds(t *testing.T) {
+	server := newBatchACMEServer(false)
+	resolver := &batchResolverMock{server: server}
+
+	certifier := setupBatchCertifier(t, server, resolver)
+
+	results := certifier.ObtainBatch([]ObtainRequest{
+		{Domains: []string{"a.example.com", "b.example.com"}},
+		{Domains: []string{"b.example.com"}},
+	}, nil)
+
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+
+	// The 2 authorizations of b.example.com are not solved at the same time.
+	assert.Equal(t, [][]string{{"a.example.com", "b.example.com"}, {"b.example.com"}}, resolver.rounds)
+}
+
+func TestCertifier_ObtainBatch_failure(t *testing.T) {
+	server := newBatchACMEServer(false)
+	resolver := &batchResolverMock{server: server, fail: map[string]bool{"b.example.com": true}}
+
+	certifier := setupBatchCertifier(t, server, resolver)
+
+	results := certifier.ObtainBatch([]ObtainRequest{
+		{Domains: []string{"a.example.com", "b.example.com"}},
+		{Domains: []string{"c.example.com"}},
+		{Domains: []string{"b.example.com"}},
+	}, nil)
+
+	require.ErrorContains(t, results[0].Err, "b.example.com: invalid")
+	assert.Nil(t, results[0].Resource)
+
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, "c.example.com", results[1].Resource.Domain)
+
+	// The identifier has already failed: the second authorization is not solved.
+	require.ErrorContains(t, results[2].Err, "b.example.com: invalid")
+
+	assert.Equal(t, [][]string{{"a.example.com", "b.example.com", "c.example.com"}}, resolver.rounds)
+}
+
+func TestCertifier_ObtainBatch_failureByAuthorization(t *testing.T) {
+	server := newBatchACMEServer(false)
+	resolver := &batchResolverMock{server: server, fail: map[string]bool{"b.example.com": true, "c.example.com": true}}
+
+	certifier := setupBatchCertifier(t, server, resolver)
+
+	results := certifier.ObtainBatch([]ObtainRequest{
+		{Domains: []string{"a.example.com", "b.example.com"}},
+		{Domains: []string{"c.example.com"}},
+	}, nil)
+
+	// Each failed authorization has its own error, not the error of the round.
+	require.ErrorContains(t, results[0].Err, "b.example.com: invalid")
+	assert.NotContains(t, results[0].Err.Error(), "c.example.com")
+
+	require.ErrorContains(t, results[1].Err, "c.example.com: invalid")
+	assert.NotContains(t, results[1].Err.Error(), "b.example.com")
+}
+
+func TestCertifier_ObtainBatch_authorizationUnavailable(t *testing.T) {
+	server := newBatchACMEServer(false)
+	resolver := &batchResolverMock{server: server}
+
+	certifier := setupBatchCertifier(t, server, resolver)
+
+	results := certifier.ObtainBatch([]ObtainRequest{
+		{Domains: []string{"a.example.com", "unavailable.example.com"}},
+	}, nil)
+
+	// The failure is reported with the identifier, not the URL of the authorization.
+	require.ErrorContains(t, results[0].Err, "\nunavailable.example.com: acme: error: 404")
+	assert.NotContains(t, results[0].Err.Error(), "\nhttps://")
+}
@@ -2,6 +2,7 @@ package certificate
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/base64"
@@ -12,14 +13,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cenkalti/backoff/v5"
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/audit"
 	"github.com/digicert/lego/v4/certcrypto"
 	"github.com/digicert/lego/v4/challenge"
-	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/metrics"
 	"github.com/digicert/lego/v4/platform/wait"
+	"github.com/digicert/lego/v4/progress"
 	"golang.org/x/crypto/ocsp"
-	"golang.org/x/net/idna"
 )
 
 const (
@@ -31,6 +35,10 @@ const (
 	// ZeroSSL has a limit of 7.
 	// https://help.zerossl.com/hc/en-us/articles/17864245480093-Advantages-over-Using-Let-s-Encrypt#h_01HT4Z1JCJFJQFJ1M3P7S085Q9
 	DefaultOverallRequestLimit = 18
+
+	// DefaultTimeout is the default maximum duration of the wait for the certificate,
+	// after the finalization of the order.
+	DefaultTimeout = 30 * time.Second
 )
 
 // maxBodySize is the maximum size of body that we will read.
@@ -121,11 +129,27 @@ type resolver interface {
 	Solve(authorizations []acme.Authorization) error
 }
 
+// contextResolver is implemented by the resolvers that can stop solving the challenges when the context is done.
+type contextResolver interface {
+	SolveWithContext(ctx context.Context, authorizations []acme.Authorization) error
+}
+
 type CertifierOptions struct {
-	KeyType             certcrypto.KeyType
-	Timeout             time.Duration
+	KeyType certcrypto.KeyType
+
+	// Timeout is the maximum duration of the wait for the certificate (finalization and download).
+	// If zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// OrderTimeout is the maximum duration of an obtain or a renew, from the creation of the order to the download of the certificate.
+	// If zero, only the context bounds the issuance.
+	OrderTimeout time.Duration
+
 	OverallRequestLimit int
 	DisableCommonName   bool
+
+	// AuditLogger records the certificate lifecycle events (obtain, renew, revoke), if not nil.
+	AuditLogger audit.Logger
 }
 
 // Certifier A service to obtain/renew/revoke certificates.
@@ -157,18 +181,59 @@ func NewCertifier(core *api.Core, resolver resolver, options CertifierOptions) *
 // This function will never return a partial certificate.
 // If one domain in the list fails, the whole certificate will fail.
 func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
+	return c.ObtainWithContext(context.Background(), request)
+}
+
+// ObtainWithContext is like Obtain, but the issuance is bounded by the context.
+//
+// When the context is done, the pending waits (propagation, validation, finalization) are interrupted,
+// the challenges already presented are cleaned up, the authorizations are deactivated,
+// and the returned error wraps the cause of the cancellation.
+func (c *Certifier) ObtainWithContext(ctx context.Context, request ObtainRequest) (*Resource, error) {
+	return c.recordObtain(ctx, audit.ActionObtain, request, c.solve)
+}
+
+// solveFunc solves the authorizations of an order.
+type solveFunc func(ctx context.Context, authz []acme.Authorization) error
+
+func (c *Certifier) recordObtain(ctx context.Context, action string, request ObtainRequest, solve solveFunc) (*Resource, error) {
+	event := &audit.Event{Action: action, Domains: request.Domains}
+
+	ctx, cancel := c.withOrderTimeout(ctx)
+	defer cancel()
+
+	cert, err := c.obtain(ctx, request, event, solve)
+
+	c.done(err)
+	metrics.RecordIssuance(err)
+	c.audit(event, cert, err)
+	c.publishObtained(event, cert, err)
+
+	return cert, err
+}
+
+func (c *Certifier) obtain(ctx context.Context, request ObtainRequest, event *audit.Event, solve solveFunc) (*Resource, error) {
 	if len(request.Domains) == 0 {
 		return nil, errors.New("no domains to obtain a certificate for")
 	}
 
-	domains := sanitizeDomain(request.Domains)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("acme: order not created: %w", context.Cause(ctx))
+	}
+
+	domains, err := ValidateIdentifiers(request.Domains)
+	if err != nil {
+		return nil, fmt.Errorf("acme: order not created: %w", err)
+	}
 
 	if request.Bundle {
-		log.Infof("[%s] acme: Obtaining bundled SAN certificate", strings.Join(domains, ", "))
+		c.core.Logger().Info("acme: Obtaining bundled SAN certificate.", "domains", strings.Join(domains, ", "))
 	} else {
-		log.Infof("[%s] acme: Obtaining SAN certificate", strings.Join(domains, ", "))
+		c.core.Logger().Info("acme: Obtaining SAN certificate.", "domains", strings.Join(domains, ", "))
 	}
 
+	c.core.Progress().Start(progress.PhaseOrder, "")
+
 	orderOpts := &api.OrderOptions{
 		NotBefore:      request.NotBefore,
 		NotAfter:       request.NotAfter,
@@ -181,6 +246,10 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		return nil, err
 	}
 
+	event.OrderURL = order.Location
+
+	c.core.Events().Publish(events.OrderCreated{Domains: domains, OrderURL: order.Location})
+
 	authz, err := c.getAuthorizations(order)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
@@ -188,18 +257,27 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	c.core.Progress().Start(progress.PhaseAuthorization, "")
+
+	err = solve(ctx, authz)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
 		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
 		return nil, err
 	}
 
-	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(domains, ", "))
+	if ctx.Err() != nil {
+		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
+		return nil, fmt.Errorf("acme: order not finalized: %w", context.Cause(ctx))
+	}
+
+	c.core.Logger().Info("acme: Validations succeeded; requesting certificates.", "domains", strings.Join(domains, ", "))
+
+	c.core.Progress().Start(progress.PhaseFinalization, "")
 
 	failures := newObtainError()
 
-	cert, err := c.getForOrder(domains, order, request)
+	cert, err := c.getForOrder(ctx, domains, order, request)
 	if err != nil {
 		for _, auth := range authz {
 			failures.Add(challenge.GetTargetedDomain(auth), err)
@@ -223,20 +301,58 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 // This function will never return a partial certificate.
 // If one domain in the list fails, the whole certificate will fail.
 func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error) {
+	return c.ObtainForCSRWithContext(context.Background(), request)
+}
+
+// ObtainForCSRWithContext is like ObtainForCSR, but the issuance is bounded by the context (see ObtainWithContext).
+func (c *Certifier) ObtainForCSRWithContext(ctx context.Context, request ObtainForCSRRequest) (*Resource, error) {
+	return c.recordObtainForCSR(ctx, audit.ActionObtain, request)
+}
+
+func (c *Certifier) recordObtainForCSR(ctx context.Context, action string, request ObtainForCSRRequest) (*Resource, error) {
+	event := &audit.Event{Action: action}
+	if request.CSR != nil {
+		event.Domains = certcrypto.ExtractDomainsCSR(request.CSR)
+	}
+
+	ctx, cancel := c.withOrderTimeout(ctx)
+	defer cancel()
+
+	cert, err := c.obtainForCSR(ctx, request, event)
+
+	c.done(err)
+	metrics.RecordIssuance(err)
+	c.audit(event, cert, err)
+	c.publishObtained(event, cert, err)
+
+	return cert, err
+}
+
+func (c *Certifier) obtainForCSR(ctx context.Context, request ObtainForCSRRequest, event *audit.Event) (*Resource, error) {
 	if request.CSR == nil {
 		return nil, errors.New("cannot obtain resource for CSR: CSR is missing")
 	}
 
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("acme: order not created: %w", context.Cause(ctx))
+	}
+
 	// figure out what domains it concerns
 	// start with the common name
 	domains := certcrypto.ExtractDomainsCSR(request.CSR)
 
+	if _, err := ValidateIdentifiers(domains); err != nil {
+		return nil, fmt.Errorf("acme: order not created: %w", err)
+	}
+
 	if request.Bundle {
-		log.Infof("[%s] acme: Obtaining bundled SAN certificate given a CSR", strings.Join(domains, ", "))
+		c.core.Logger().Info("acme: Obtaining bundled SAN certificate given a CSR.", "domains", strings.Join(domains, ", "))
 	} else {
-		log.Infof("[%s] acme: Obtaining SAN certificate given a CSR", strings.Join(domains, ", "))
+		c.core.Logger().Info("acme: Obtaining SAN certificate given a CSR.", "domains", strings.Join(domains, ", "))
 	}
 
+	c.core.Progress().Start(progress.PhaseOrder, "")
+
 	orderOpts := &api.OrderOptions{
 		NotBefore:      request.NotBefore,
 		NotAfter:       request.NotAfter,
@@ -249,6 +365,10 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		return nil, err
 	}
 
+	event.OrderURL = order.Location
+
+	c.core.Events().Publish(events.OrderCreated{Domains: domains, OrderURL: order.Location})
+
 	authz, err := c.getAuthorizations(order)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
@@ -256,14 +376,23 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	c.core.Progress().Start(progress.PhaseAuthorization, "")
+
+	err = c.solve(ctx, authz)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
 		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
 		return nil, err
 	}
 
-	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(domains, ", "))
+	if ctx.Err() != nil {
+		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
+		return nil, fmt.Errorf("acme: order not finalized: %w", context.Cause(ctx))
+	}
+
+	c.core.Logger().Info("acme: Validations succeeded; requesting certificates.", "domains", strings.Join(domains, ", "))
+
+	c.core.Progress().Start(progress.PhaseFinalization, "")
 
 	failures := newObtainError()
 
@@ -272,7 +401,7 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		privateKey = certcrypto.PEMEncode(request.PrivateKey)
 	}
 
-	cert, err := c.getForCSR(domains, order, request.Bundle, request.CSR.Raw, privateKey, request.PreferredChain)
+	cert, err := c.getForCSR(ctx, domains, order, request.Bundle, request.CSR.Raw, privateKey, request.PreferredChain)
 	if err != nil {
 		for _, auth := range authz {
 			failures.Add(challenge.GetTargetedDomain(auth), err)
@@ -291,7 +420,7 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	return cert, failures.Join()
 }
 
-func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, request ObtainRequest) (*Resource, error) {
+func (c *Certifier) getForOrder(ctx context.Context, domains []string, order acme.ExtendedOrder, request ObtainRequest) (*Resource, error) {
 	privateKey := request.PrivateKey
 
 	if privateKey == nil {
@@ -338,10 +467,10 @@ func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, requ
 		return nil, err
 	}
 
-	return c.getForCSR(domains, order, request.Bundle, csr, certcrypto.PEMEncode(privateKey), request.PreferredChain)
+	return c.getForCSR(ctx, domains, order, request.Bundle, csr, certcrypto.PEMEncode(privateKey), request.PreferredChain)
 }
 
-func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle bool, csr, privateKeyPem []byte, preferredChain string) (*Resource, error) {
+func (c *Certifier) getForCSR(ctx context.Context, domains []string, order acme.ExtendedOrder, bundle bool, csr, privateKeyPem []byte, preferredChain string) (*Resource, error) {
 	respOrder, err := c.core.Orders.UpdateForCSR(order.Finalize, csr)
 	if err != nil {
 		return nil, err
@@ -367,10 +496,15 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 
 	timeout := c.options.Timeout
 	if c.options.Timeout <= 0 {
-		timeout = 30 * time.Second
+		timeout = DefaultTimeout
 	}
 
-	err = wait.For("certificate", timeout, timeout/60, func() (bool, error) {
+	var attempt int
+
+	err = wait.ForWithContext(ctx, "certificate", timeout, backoff.NewConstantBackOff(timeout/60), func() (bool, error) {
+		attempt++
+		c.core.Progress().Attempt(progress.PhaseFinalization, "", attempt)
+
 		ord, errW := c.core.Orders.Get(order.Location)
 		if errW != nil {
 			return false, errW
@@ -387,6 +521,25 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 	return certRes, err
 }
 
+// withOrderTimeout bounds the context with the order timeout, if any.
+func (c *Certifier) withOrderTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.options.OrderTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeoutCause(ctx, c.options.OrderTimeout,
+		fmt.Errorf("order timeout (%s) exceeded: %w", c.options.OrderTimeout, context.DeadlineExceeded))
+}
+
+// solve solves the challenges of the authorizations, the context is used only if the resolver supports it.
+func (c *Certifier) solve(ctx context.Context, authz []acme.Authorization) error {
+	if r, ok := c.resolver.(contextResolver); ok {
+		return r.SolveWithContext(ctx, authz)
+	}
+
+	return c.resolver.Solve(authz)
+}
+
 // checkResponse checks to see if the certificate is ready and a link is contained in the response.
 //
 // If so, loads it into certRes and returns true.
@@ -413,7 +566,7 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 	certRes.CertStableURL = order.Certificate
 
 	if preferredChain == "" {
-		log.Infof("[%s] Server responded with a certificate.", certRes.Domain)
+		c.core.Logger().Info("Server responded with a certificate.", "domain", certRes.Domain)
 
 		return true, nil
 	}
@@ -425,7 +578,8 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 		}
 
 		if ok {
-			log.Infof("[%s] Server responded with a certificate for the preferred certificate chains %q.", certRes.Domain, preferredChain)
+			c.core.Logger().Info("Server responded with a certificate for the preferred certificate chains.",
+				"domain", certRes.Domain, "preferredChain", preferredChain)
 
 			certRes.IssuerCertificate = cert.Issuer
 			certRes.Certificate = cert.Cert
@@ -436,7 +590,8 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 		}
 	}
 
-	log.Infof("lego has been configured to prefer certificate chains with issuer %q, but no chain from the CA matched this issuer. Using the default certificate chain instead.", preferredChain)
+	c.core.Logger().Info("lego has been configured to prefer certificate chains with an issuer, but no chain from the CA matched this issuer. Using the default certificate chain instead.",
+		"domain", certRes.Domain, "preferredChain", preferredChain)
 
 	return true, nil
 }
@@ -463,7 +618,15 @@ func (c *Certifier) RevokeWithReason(cert []byte, reason *uint) error {
 		Reason:      reason,
 	}
 
-	return c.core.Certificates.Revoke(revokeMsg)
+	err = c.core.Certificates.Revoke(revokeMsg)
+
+	c.audit(&audit.Event{
+		Action:  audit.ActionRevoke,
+		Domains: certcrypto.ExtractDomains(x509Cert),
+		Serial:  formatSerial(x509Cert),
+	}, nil, err)
+
+	return err
 }
 
 // RenewOptions options used by Certifier.RenewWithOptions.
@@ -513,6 +676,11 @@ func (c *Certifier) Renew(certRes Resource, bundle, mustStaple bool, preferredCh
 //
 // For private key reuse the PrivateKey property of the passed in Resource should be non-nil.
 func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*Resource, error) {
+	return c.RenewWithContext(context.Background(), certRes, options)
+}
+
+// RenewWithContext is like RenewWithOptions, but the renewal is bounded by the context (see ObtainWithContext).
+func (c *Certifier) RenewWithContext(ctx context.Context, certRes Resource, options *RenewOptions) (*Resource, error) {
 	// Input certificate is PEM encoded.
 	// Decode it here as we may need the decoded cert later on in the renewal process.
 	// The input may be a bundle or a single certificate.
@@ -528,7 +696,7 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 
 	// This is just meant to be informal for the user.
 	timeLeft := x509Cert.NotAfter.Sub(time.Now().UTC())
-	log.Infof("[%s] acme: Trying renewal with %d hours remaining", certRes.Domain, int(timeLeft.Hours()))
+	c.core.Logger().Info("acme: Trying renewal.", "domain", certRes.Domain, "hoursRemaining", int(timeLeft.Hours()))
 
 	// We always need to request a new certificate to renew.
 	// Start by checking to see if the certificate was based off a CSR,
@@ -550,7 +718,7 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 			request.AlwaysDeactivateAuthorizations = options.AlwaysDeactivateAuthorizations
 		}
 
-		return c.ObtainForCSR(request)
+		return c.recordObtainForCSR(ctx, audit.ActionRenew, request)
 	}
 
 	var privateKey crypto.PrivateKey
@@ -577,7 +745,7 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 		request.AlwaysDeactivateAuthorizations = options.AlwaysDeactivateAuthorizations
 	}
 
-	return c.Obtain(request)
+	return c.recordObtain(ctx, audit.ActionRenew, request, c.solve)
 }
 
 // GetOCSP takes a PEM encoded cert or cert bundle returning the raw OCSP response,
@@ -723,17 +891,59 @@ func checkOrderStatus(order acme.ExtendedOrder) (bool, error) {
 // That is, it MUST be encoded according to the rules in Section 7 of [RFC5280].
 //
 // https://www.rfc-editor.org/rfc/rfc5280.html#section-7
-func sanitizeDomain(domains []string) []string {
-	var sanitizedDomains []string
+// publishObtained emits a CertificateObtained event if the certificate has been obtained.
+// done reports the end of an operation to the progress reporter.
+func (c *Certifier) done(err error) {
+	if err != nil {
+		return
+	}
 
-	for _, domain := range domains {
-		sanitizedDomain, err := idna.ToASCII(domain)
-		if err != nil {
-			log.Infof("skip domain %q: unable to sanitize (punnycode): %v", domain, err)
-		} else {
-			sanitizedDomains = append(sanitizedDomains, sanitizedDomain)
+	c.core.Progress().Start(progress.PhaseDone, "")
+}
+
+func (c *Certifier) publishObtained(event *audit.Event, cert *Resource, err error) {
+	if err != nil || cert == nil {
+		return
+	}
+
+	c.core.Events().Publish(events.CertificateObtained{
+		Domain:   cert.Domain,
+		Domains:  event.Domains,
+		OrderURL: event.OrderURL,
+		CertURL:  cert.CertURL,
+	})
+}
+
+// audit records an event with the AuditLogger, if any.
+func (c *Certifier) audit(event *audit.Event, cert *Resource, err error) {
+	if c.options.AuditLogger == nil {
+		return
+	}
+
+	event.Time = time.Now().UTC()
+	event.Account = c.core.GetKid()
+	event.Result = audit.ResultSuccess
+
+	if err != nil {
+		event.Result = audit.ResultFailure
+		event.Error = err.Error()
+	}
+
+	if cert != nil {
+		event.CertURL = cert.CertURL
+
+		certificates, errP := certcrypto.ParsePEMBundle(cert.Certificate)
+		if errP == nil && len(certificates) > 0 {
+			event.Serial = formatSerial(certificates[0])
 		}
 	}
 
-	return sanitizedDomains
+	errL := c.options.AuditLogger.Log(*event)
+	if errL != nil {
+		c.core.Logger().Warn("Could not write the audit log.", "action", event.Action, "error", errL)
+	}
+}
+
+func formatSerial(cert *x509.Certificate) string {
+	return fmt.Sprintf("%x", cert.SerialNumber)
 }
@@ -1,17 +1,28 @@
 package certificate
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/audit"
 	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	chlgresolver "github.com/digicert/lego/v4/challenge/resolver"
 	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/platform/tester/acmeserver"
+	"github.com/digicert/lego/v4/platform/tester/fakedns"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/digicert/lego/v4/progress"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -391,6 +402,303 @@ func Test_checkOrderStatus(t *testing.T) {
 	}
 }
 
+func TestCertifier_RevokeWithReason_audit(t *testing.T) {
+	server, core, prober := setupACMEServer(t)
+
+	auditLogger := &auditLoggerMock{}
+
+	certifier := NewCertifier(core, prober, CertifierOptions{KeyType: certcrypto.RSA2048, AuditLogger: auditLogger})
+
+	resource, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.NoError(t, err)
+
+	cert, err := certcrypto.ParsePEMCertificate(resource.Certificate)
+	require.NoError(t, err)
+
+	err = certifier.Revoke(resource.Certificate)
+	require.NoError(t, err)
+
+	assert.True(t, server.Revoked(cert))
+
+	require.Len(t, auditLogger.events, 2)
+
+	event := auditLogger.events[1]
+
+	assert.NotZero(t, event.Time)
+	event.Time = time.Time{}
+
+	expected := audit.Event{
+		Action:  audit.ActionRevoke,
+		Account: core.GetKid(),
+		Domains: []string{"example.com"},
+		Serial:  fmt.Sprintf("%x", cert.SerialNumber),
+		Result:  audit.ResultSuccess,
+	}
+
+	assert.Equal(t, expected, event)
+}
+
+func TestCertifier_Obtain_audit(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /newOrder", servermock.JSONEncode(acme.ProblemDetails{
+			Type:       acme.RateLimitedErr,
+			Detail:     "too many certificates",
+			HTTPStatus: http.StatusTooManyRequests,
+		}).WithStatusCode(http.StatusTooManyRequests)).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account/1", key)
+	require.NoError(t, err)
+
+	auditLogger := &auditLoggerMock{}
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, AuditLogger: auditLogger})
+
+	_, err = certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}})
+	require.Error(t, err)
+
+	require.Len(t, auditLogger.events, 1)
+
+	event := auditLogger.events[0]
+
+	assert.Equal(t, audit.ActionObtain, event.Action)
+	assert.Equal(t, server.URL+"/account/1", event.Account)
+	assert.Equal(t, []string{"example.com"}, event.Domains)
+	assert.Equal(t, audit.ResultFailure, event.Result)
+	assert.Contains(t, event.Error, "too many certificates")
+	assert.Empty(t, event.OrderURL)
+	assert.Empty(t, event.Serial)
+}
+
+func TestCertifier_Obtain_progress(t *testing.T) {
+	_, core, prober := setupACMEServer(t)
+
+	var phases []progress.Progress
+
+	core.SetProgressReporter(progress.ReporterFunc(func(p progress.Progress) {
+		phases = append(phases, p)
+	}))
+
+	certifier := NewCertifier(core, prober, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	_, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.NoError(t, err)
+
+	expected := []progress.Progress{
+		{Phase: progress.PhaseOrder, Percent: 0},
+		{Phase: progress.PhaseAuthorization, Percent: 10},
+		{Phase: progress.PhasePropagation, Percent: 10, Domain: "example.com", Attempt: 1},
+		{Phase: progress.PhaseAuthorization, Percent: 80, Domain: "example.com"},
+		{Phase: progress.PhaseFinalization, Percent: 80},
+		{Phase: progress.PhaseDone, Percent: 100},
+	}
+
+	assert.Equal(t, expected, phases)
+}
+
+func TestCertifier_ObtainWithContext_finalizationDeadline(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /newOrder", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Location", "https://"+req.Host+"/order/1")
+			rw.WriteHeader(http.StatusCreated)
+
+			_ = json.NewEncoder(rw).Encode(acme.Order{
+				Status:         acme.StatusReady,
+				Identifiers:    []acme.Identifier{{Type: "dns", Value: "acme.wtf"}},
+				Authorizations: []string{"https://" + req.Host + "/authz/1"},
+				Finalize:       "https://" + req.Host + "/finalize",
+			})
+		})).
+		Route("POST /authz/1", servermock.JSONEncode(acme.Authorization{
+			Status:     acme.StatusValid,
+			Identifier: acme.Identifier{Type: "dns", Value: "acme.wtf"},
+		})).
+		Route("POST /finalize", servermock.JSONEncode(acme.Order{Status: acme.StatusProcessing})).
+		Route("POST /order/1", servermock.JSONEncode(acme.Order{Status: acme.StatusProcessing})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account/1", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, Timeout: time.Minute})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, err = certifier.ObtainWithContext(ctx, ObtainRequest{Domains: []string{"acme.wtf"}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
+
+func TestCertifier_Obtain_orderTimeout(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /newOrder", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Location", "https://"+req.Host+"/order/1")
+			rw.WriteHeader(http.StatusCreated)
+
+			_ = json.NewEncoder(rw).Encode(acme.Order{
+				Status:         acme.StatusReady,
+				Identifiers:    []acme.Identifier{{Type: "dns", Value: "acme.wtf"}},
+				Authorizations: []string{"https://" + req.Host + "/authz/1"},
+				Finalize:       "https://" + req.Host + "/finalize",
+			})
+		})).
+		Route("POST /authz/1", servermock.JSONEncode(acme.Authorization{
+			Status:     acme.StatusValid,
+			Identifier: acme.Identifier{Type: "dns", Value: "acme.wtf"},
+		})).
+		Route("POST /finalize", servermock.JSONEncode(acme.Order{Status: acme.StatusProcessing})).
+		Route("POST /order/1", servermock.JSONEncode(acme.Order{Status: acme.StatusProcessing})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account/1", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{
+		KeyType:      certcrypto.RSA2048,
+		Timeout:      time.Minute,
+		OrderTimeout: 500 * time.Millisecond,
+	})
+
+	start := time.Now()
+
+	_, err = certifier.Obtain(ObtainRequest{Domains: []string{"acme.wtf"}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "order timeout (500ms) exceeded")
+
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
+
+func TestCertifier_ObtainWithContext_canceledDuringSolve(t *testing.T) {
+	var deactivated atomic.Bool
+
+	server := tester.MockACMEServer().
+		Route("POST /newOrder", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Location", "https://"+req.Host+"/order/1")
+			rw.WriteHeader(http.StatusCreated)
+
+			_ = json.NewEncoder(rw).Encode(acme.Order{
+				Status:         acme.StatusPending,
+				Identifiers:    []acme.Identifier{{Type: "dns", Value: "acme.wtf"}},
+				Authorizations: []string{"https://" + req.Host + "/authz/1"},
+				Finalize:       "https://" + req.Host + "/finalize",
+			})
+		})).
+		Route("POST /authz/1", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			var jws struct {
+				Payload string `json:"payload"`
+			}
+
+			_ = json.NewDecoder(req.Body).Decode(&jws)
+
+			payload, _ := base64.RawURLEncoding.DecodeString(jws.Payload)
+
+			var authz acme.Authorization
+
+			_ = json.Unmarshal(payload, &authz)
+
+			if authz.Status == acme.StatusDeactivated {
+				deactivated.Store(true)
+			}
+
+			_ = json.NewEncoder(rw).Encode(acme.Authorization{
+				Status:     acme.StatusPending,
+				Identifier: acme.Identifier{Type: "dns", Value: "acme.wtf"},
+			})
+		})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account/1", key)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	certifier := NewCertifier(core, &contextResolverMock{cancel: cancel}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	_, err = certifier.ObtainWithContext(ctx, ObtainRequest{Domains: []string{"acme.wtf"}})
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.True(t, deactivated.Load(), "the authorization should be deactivated")
+}
+
+func TestCertifier_ObtainWithContext_canceled(t *testing.T) {
+	// No newOrder route: the order must not be created.
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account/1", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err = certifier.ObtainWithContext(ctx, ObtainRequest{Domains: []string{"acme.wtf"}})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+type auditLoggerMock struct {
+	events []audit.Event
+}
+
+func (a *auditLoggerMock) Log(event audit.Event) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+// setupACMEServer starts an ACME test server validating the DNS-01 challenges with a fakedns provider,
+// and returns the client of a registered account, and the resolver solving the DNS-01 challenges with the provider.
+func setupACMEServer(t *testing.T) (*acmeserver.Server, *api.Core, *chlgresolver.Prober) {
+	t.Helper()
+
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := fakedns.NewProvider("127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	server := acmeserver.New(t, acmeserver.WithDNSResolver(provider.Addr()))
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	solversManager := chlgresolver.NewSolversManager(core)
+
+	err = solversManager.SetDNS01Provider(provider,
+		dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+		dns01.DisableAuthoritativeNssPropagationRequirement(),
+	)
+	require.NoError(t, err)
+
+	return server, core, chlgresolver.NewProber(solversManager)
+}
+
 type resolverMock struct {
 	error error
 }
@@ -398,3 +706,16 @@ type resolverMock struct {
 func (r *resolverMock) Solve(_ []acme.Authorization) error {
 	return r.error
 }
+
+// contextResolverMock cancels the context while solving the challenges.
+type contextResolverMock struct {
+	resolverMock
+
+	cancel context.CancelFunc
+}
+
+func (r *contextResolverMock) SolveWithContext(_ context.Context, _ []acme.Authorization) error {
+	r.cancel()
+
+	return nil
+}
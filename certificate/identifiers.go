@@ -0,0 +1,136 @@
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/digicert/lego/v4/log"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	maxDomainLength = 253
+	maxLabelLength  = 63
+)
+
+// IdentifierError is the error of an invalid identifier.
+type IdentifierError struct {
+	Identifier string
+	Err        error
+}
+
+func (e *IdentifierError) Error() string {
+	return fmt.Sprintf("invalid identifier %q: %v", e.Identifier, e.Err)
+}
+
+func (e *IdentifierError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateIdentifiers checks the identifiers (domains and IP addresses) before the creation of an order,
+// and returns them in the form expected by the ACME server (A-labels, lowercase, without trailing dot).
+//
+// The checks are:
+//   - the domains are converted to A-labels (punycode), the invalid Unicode domains are rejected.
+//   - the labels contain only letters, digits, and hyphens, and don't start or end with a hyphen.
+//   - the length of the labels and of the domains.
+//   - a wildcard is only allowed as the complete leftmost label (`*.example.com`).
+//   - a wildcard cannot cover a public suffix (`*.co.uk`).
+//
+// A warning is logged for the domains that are public suffixes (ex: `co.uk`).
+//
+// The errors of all the invalid identifiers are returned together.
+func ValidateIdentifiers(identifiers []string) ([]string, error) {
+	var (
+		valid []string
+		errs  []error
+	)
+
+	for _, identifier := range identifiers {
+		value, err := validateIdentifier(identifier)
+		if err != nil {
+			errs = append(errs, &IdentifierError{Identifier: identifier, Err: err})
+			continue
+		}
+
+		valid = append(valid, value)
+	}
+
+	return valid, errors.Join(errs...)
+}
+
+func validateIdentifier(identifier string) (string, error) {
+	if identifier == "" {
+		return "", errors.New("empty identifier")
+	}
+
+	if ip := net.ParseIP(identifier); ip != nil {
+		return ip.String(), nil
+	}
+
+	domain := strings.TrimSuffix(identifier, ".")
+
+	base, wildcard := strings.CutPrefix(domain, "*.")
+	if strings.Contains(base, "*") {
+		return "", errors.New("a wildcard is only allowed as the complete leftmost label (ex: *.example.com)")
+	}
+
+	if wildcard && net.ParseIP(base) != nil {
+		return "", errors.New("a wildcard cannot be used with an IP address")
+	}
+
+	ascii, err := idna.Lookup.ToASCII(base)
+	if err != nil {
+		return "", fmt.Errorf("cannot convert to A-labels: %w", err)
+	}
+
+	err = checkDomainSyntax(ascii)
+	if err != nil {
+		return "", err
+	}
+
+	if suffix, icann := publicsuffix.PublicSuffix(ascii); icann && suffix == ascii {
+		if wildcard {
+			return "", fmt.Errorf("a wildcard cannot cover the public suffix %q", ascii)
+		}
+
+		log.Warn("The domain is a public suffix, the ACME server will probably reject it.", "domain", identifier)
+	}
+
+	if wildcard {
+		return "*." + ascii, nil
+	}
+
+	return ascii, nil
+}
+
+func checkDomainSyntax(domain string) error {
+	if len(domain) > maxDomainLength {
+		return fmt.Errorf("the domain is longer than %d characters", maxDomainLength)
+	}
+
+	for label := range strings.SplitSeq(domain, ".") {
+		if label == "" {
+			return errors.New("empty label")
+		}
+
+		if len(label) > maxLabelLength {
+			return fmt.Errorf("the label %q is longer than %d characters", label, maxLabelLength)
+		}
+
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("the label %q starts or ends with a hyphen", label)
+		}
+
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return fmt.Errorf("the label %q contains an invalid character: %q", label, r)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package certificate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIdentifiers(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		identifiers []string
+		expected    []string
+	}{
+		{
+			desc:        "domains",
+			identifiers: []string{"example.com", "WWW.Example.com", "example.org."},
+			expected:    []string{"example.com", "www.example.com", "example.org"},
+		},
+		{
+			desc:        "wildcard",
+			identifiers: []string{"*.example.com"},
+			expected:    []string{"*.example.com"},
+		},
+		{
+			desc:        "IDN",
+			identifiers: []string{"münchen.example", "*.bücher.example"},
+			expected:    []string{"xn--mnchen-3ya.example", "*.xn--bcher-kva.example"},
+		},
+		{
+			desc:        "IP addresses",
+			identifiers: []string{"192.0.2.1", "2001:DB8::1"},
+			expected:    []string{"192.0.2.1", "2001:db8::1"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			identifiers, err := ValidateIdentifiers(test.identifiers)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, identifiers)
+		})
+	}
+}
+
+func TestValidateIdentifiers_errors(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		identifier string
+		expected   string
+	}{
+		{
+			desc:     "empty",
+			expected: `invalid identifier "": empty identifier`,
+		},
+		{
+			desc:       "wildcard not leftmost",
+			identifier: "www.*.example.com",
+			expected:   `invalid identifier "www.*.example.com": a wildcard is only allowed as the complete leftmost label (ex: *.example.com)`,
+		},
+		{
+			desc:       "partial wildcard",
+			identifier: "w*.example.com",
+			expected:   `invalid identifier "w*.example.com": a wildcard is only allowed as the complete leftmost label (ex: *.example.com)`,
+		},
+		{
+			desc:       "double wildcard",
+			identifier: "*.*.example.com",
+			expected:   `invalid identifier "*.*.example.com": a wildcard is only allowed as the complete leftmost label (ex: *.example.com)`,
+		},
+		{
+			desc:       "wildcard IP",
+			identifier: "*.192.0.2.1",
+			expected:   `invalid identifier "*.192.0.2.1": a wildcard cannot be used with an IP address`,
+		},
+		{
+			desc:       "wildcard public suffix",
+			identifier: "*.co.uk",
+			expected:   `invalid identifier "*.co.uk": a wildcard cannot cover the public suffix "co.uk"`,
+		},
+		{
+			desc:       "empty label",
+			identifier: "www..example.com",
+			expected:   `invalid identifier "www..example.com": empty label`,
+		},
+		{
+			desc:       "hyphen",
+			identifier: "-www.example.com",
+			expected:   `invalid identifier "-www.example.com": cannot convert to A-labels: idna: invalid label "-www"`,
+		},
+		{
+			desc:       "invalid character",
+			identifier: "exa_mple.com",
+			expected:   `invalid identifier "exa_mple.com": cannot convert to A-labels: idna: disallowed rune U+005F`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ValidateIdentifiers([]string{test.identifier})
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestValidateIdentifiers_aggregated(t *testing.T) {
+	identifiers, err := ValidateIdentifiers([]string{"example.com", "exa mple.com", "example..org"})
+	require.Error(t, err)
+
+	var identErr *IdentifierError
+	require.ErrorAs(t, err, &identErr)
+	assert.Equal(t, "exa mple.com", identErr.Identifier)
+
+	assert.ErrorContains(t, err, `"example..org"`)
+	assert.Equal(t, []string{"example.com"}, identifiers)
+}
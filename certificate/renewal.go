@@ -1,9 +1,11 @@
 package certificate
 
 import (
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -131,3 +133,16 @@ func MakeARICertID(leaf *x509.Certificate) (string, error) {
 	// Construct the final identifier by concatenating AKI and Serial Number.
 	return fmt.Sprintf("%s.%s", aki, serial), nil
 }
+
+// RenewalSpread returns a deterministic offset, in [0, window), derived from the certificate.
+// Subtracted from the renewal date, it spreads the renewals of many certificates over the window,
+// even when all the clients check their certificates at the same time (ex: same cron schedule).
+func RenewalSpread(cert *x509.Certificate, window time.Duration) time.Duration {
+	if cert == nil || window <= 0 {
+		return 0
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+
+	return time.Duration(binary.BigEndian.Uint64(sum[:8]) % uint64(window))
+}
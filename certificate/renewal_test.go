@@ -3,6 +3,7 @@ package certificate
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
 	"net/http"
 	"testing"
 	"time"
@@ -237,3 +238,24 @@ func TestRenewalInfoResponse_ShouldRenew(t *testing.T) {
 		assert.Nil(t, rt)
 	})
 }
+
+func TestRenewalSpread(t *testing.T) {
+	window := 72 * time.Hour
+
+	certA := &x509.Certificate{Raw: []byte("certificate A")}
+	certB := &x509.Certificate{Raw: []byte("certificate B")}
+
+	offsetA := RenewalSpread(certA, window)
+
+	assert.GreaterOrEqual(t, offsetA, time.Duration(0))
+	assert.Less(t, offsetA, window)
+
+	// Deterministic.
+	assert.Equal(t, offsetA, RenewalSpread(&x509.Certificate{Raw: []byte("certificate A")}, window))
+
+	// Specific to each certificate.
+	assert.NotEqual(t, offsetA, RenewalSpread(certB, window))
+
+	assert.Zero(t, RenewalSpread(certA, 0))
+	assert.Zero(t, RenewalSpread(nil, window))
+}
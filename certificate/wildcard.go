@@ -0,0 +1,108 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/audit"
+	"github.com/digicert/lego/v4/challenge"
+)
+
+// ObtainWildcard obtains a certificate for a domain and all its subdomains (`example.com` and `*.example.com`).
+//
+// The request defines the options of the certificate, its Domains must be empty:
+// the base domain is used for the CommonName field, and the wildcard domain is added to the Subject Alternate Names.
+//
+// The authorizations are solved with the DNS-01 challenge (the only challenge allowed for a wildcard domain).
+// The base domain and the wildcard domain use the same TXT record name (`_acme-challenge.example.com`) with different values:
+// the authorizations are solved one after the other, so the DNS providers that keep only one value per record name are supported.
+func (c *Certifier) ObtainWildcard(baseDomain string, request ObtainRequest) (*Resource, error) {
+	return c.ObtainWildcardWithContext(context.Background(), baseDomain, request)
+}
+
+// ObtainWildcardWithContext is like ObtainWildcard, but the issuance is bounded by the context (see ObtainWithContext).
+func (c *Certifier) ObtainWildcardWithContext(ctx context.Context, baseDomain string, request ObtainRequest) (*Resource, error) {
+	if len(request.Domains) > 0 {
+		return nil, errors.New("the domains of a wildcard request are defined by the base domain")
+	}
+
+	baseDomain = strings.TrimPrefix(baseDomain, "*.")
+	if baseDomain == "" {
+		return nil, errors.New("the base domain is empty")
+	}
+
+	request.Domains = []string{baseDomain, "*." + baseDomain}
+
+	return c.recordObtain(ctx, audit.ActionObtain, request, c.solveWithDNS01)
+}
+
+// solveWithDNS01 solves the authorizations with the DNS-01 challenge only.
+// The authorizations of the same identifier (the base domain and the wildcard domain) are solved in separate rounds.
+func (c *Certifier) solveWithDNS01(ctx context.Context, authz []acme.Authorization) error {
+	failures := newObtainError()
+
+	var filtered []acme.Authorization
+
+	for _, auth := range authz {
+		if auth.Status == acme.StatusValid {
+			continue
+		}
+
+		auth.Challenges = slices.DeleteFunc(slices.Clone(auth.Challenges), func(chlg acme.Challenge) bool {
+			return chlg.Type != string(challenge.DNS01)
+		})
+
+		if len(auth.Challenges) == 0 {
+			failures.Add(challenge.GetTargetedDomain(auth), errors.New("acme: the server doesn't offer the DNS-01 challenge"))
+			continue
+		}
+
+		filtered = append(filtered, auth)
+	}
+
+	if err := failures.Join(); err != nil {
+		return err
+	}
+
+	for i, round := range identifierRounds(filtered) {
+		if i > 0 {
+			c.core.Logger().Info("acme: Solving the next authorizations of the same identifiers.", "round", i+1)
+		}
+
+		err := c.solve(ctx, round)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// identifierRounds splits the authorizations into rounds where each identifier appears only once.
+func identifierRounds(authz []acme.Authorization) [][]acme.Authorization {
+	var rounds [][]acme.Authorization
+
+	for _, auth := range authz {
+		placed := false
+
+		for i, round := range rounds {
+			if slices.ContainsFunc(round, func(a acme.Authorization) bool { return a.Identifier.Value == auth.Identifier.Value }) {
+				continue
+			}
+
+			rounds[i] = append(round, auth)
+			placed = true
+
+			break
+		}
+
+		if !placed {
+			rounds = append(rounds, []acme.Authorization{auth})
+		}
+	}
+
+	return rounds
+}
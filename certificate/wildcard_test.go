@@ -0,0 +1,120 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingResolverMock records the authorizations of each call.
+type recordingResolverMock struct {
+	calls [][]acme.Authorization
+}
+
+func (r *recordingResolverMock) Solve(authz []acme.Authorization) error {
+	r.calls = append(r.calls, authz)
+
+	return nil
+}
+
+func newWildcardCertifier(t *testing.T, resolver resolver) *Certifier {
+	t.Helper()
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	return NewCertifier(core, resolver, CertifierOptions{KeyType: certcrypto.RSA2048})
+}
+
+func TestCertifier_solveWithDNS01(t *testing.T) {
+	resolver := &recordingResolverMock{}
+
+	certifier := newWildcardCertifier(t, resolver)
+
+	authz := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: "http-01"}, {Type: "dns-01"}, {Type: "tls-alpn-01"}},
+		},
+		{
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+			Wildcard:   true,
+			Challenges: []acme.Challenge{{Type: "dns-01"}},
+		},
+		{
+			Status:     acme.StatusValid,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.org"},
+		},
+	}
+
+	err := certifier.solveWithDNS01(t.Context(), authz)
+	require.NoError(t, err)
+
+	require.Len(t, resolver.calls, 2)
+
+	require.Len(t, resolver.calls[0], 1)
+	assert.False(t, resolver.calls[0][0].Wildcard)
+	assert.Equal(t, []acme.Challenge{{Type: "dns-01"}}, resolver.calls[0][0].Challenges)
+
+	require.Len(t, resolver.calls[1], 1)
+	assert.True(t, resolver.calls[1][0].Wildcard)
+
+	// The original authorizations are not modified.
+	assert.Len(t, authz[0].Challenges, 3)
+}
+
+func TestCertifier_solveWithDNS01_noDNS01(t *testing.T) {
+	resolver := &recordingResolverMock{}
+
+	certifier := newWildcardCertifier(t, resolver)
+
+	authz := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: "http-01"}},
+		},
+	}
+
+	err := certifier.solveWithDNS01(t.Context(), authz)
+	require.ErrorContains(t, err, "acme: the server doesn't offer the DNS-01 challenge")
+
+	assert.Empty(t, resolver.calls)
+}
+
+func TestCertifier_ObtainWildcard_errors(t *testing.T) {
+	certifier := newWildcardCertifier(t, &recordingResolverMock{})
+
+	_, err := certifier.ObtainWildcard("example.com", ObtainRequest{Domains: []string{"example.org"}})
+	require.EqualError(t, err, "the domains of a wildcard request are defined by the base domain")
+
+	_, err = certifier.ObtainWildcard("*.", ObtainRequest{})
+	require.EqualError(t, err, "the base domain is empty")
+}
+
+func Test_identifierRounds(t *testing.T) {
+	authz := []acme.Authorization{
+		{Identifier: acme.Identifier{Value: "a.example.com"}},
+		{Identifier: acme.Identifier{Value: "a.example.com"}, Wildcard: true},
+		{Identifier: acme.Identifier{Value: "b.example.com"}},
+		{Identifier: acme.Identifier{Value: "b.example.com"}, Wildcard: true},
+		{Identifier: acme.Identifier{Value: "c.example.com"}},
+	}
+
+	rounds := identifierRounds(authz)
+
+	require.Len(t, rounds, 2)
+	assert.Equal(t, []acme.Authorization{authz[0], authz[2], authz[4]}, rounds[0])
+	assert.Equal(t, []acme.Authorization{authz[1], authz[3]}, rounds[1])
+}
@@ -0,0 +1,179 @@
+// Package certstore imports certificates into the Windows certificate store, and binds them to IIS (HTTP.sys).
+package certstore
+
+import (
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // the thumbprint of a certificate is its SHA-1 hash.
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// DefaultStoreName the name of the personal certificate store ("My").
+const DefaultStoreName = "My"
+
+// iisAppID the application ID of IIS, used by the HTTP.sys SSL bindings.
+const iisAppID = "{4dc3e181-e14b-4a21-b022-59fc669b0914}"
+
+// ErrNotSupported is returned on the platforms other than Windows.
+var ErrNotSupported = errors.New("certstore: the Windows certificate store is only available on Windows")
+
+// Options the options of the import.
+type Options struct {
+	// FriendlyName the friendly name of the certificate (displayed by the MMC and IIS).
+	FriendlyName string
+
+	// StoreName the name of the LocalMachine store (default: My).
+	StoreName string
+}
+
+// Thumbprint returns the thumbprint (uppercase hexadecimal SHA-1 hash) of a certificate.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw) //nolint:gosec // the thumbprint of a certificate is its SHA-1 hash.
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// encodePFX builds a PFX (PKCS#12) from PEM encoded certificate, private key, and issuer certificate.
+// The password is random because the PFX is only used in memory.
+func encodePFX(certPEM, keyPEM, issuerPEM []byte) (data []byte, password string, leaf *x509.Certificate, err error) {
+	certs, err := certcrypto.ParsePEMBundle(certPEM)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("certstore: parse certificate: %w", err)
+	}
+
+	leaf = certs[0]
+
+	chain := certs[1:]
+
+	if len(chain) == 0 && len(issuerPEM) > 0 {
+		chain, err = certcrypto.ParsePEMBundle(issuerPEM)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("certstore: parse issuer certificate: %w", err)
+		}
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(keyPEM)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("certstore: parse private key: %w", err)
+	}
+
+	password = rand.Text()
+
+	// LegacyDES is supported by all the versions of Windows Server.
+	data, err = pkcs12.LegacyDES.Encode(privateKey, leaf, chain, password)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("certstore: encode PFX: %w", err)
+	}
+
+	return data, password, leaf, nil
+}
+
+// sslCertArgs returns the arguments of `netsh http <action> sslcert` for an IIS binding.
+// The binding is `<ip>:<port>` (ex: `0.0.0.0:443`) or `<hostname>:<port>` for an SNI binding (ex: `example.com:443`).
+func sslCertArgs(action, binding, thumbprint, storeName string) ([]string, error) {
+	host, port, err := net.SplitHostPort(binding)
+	if err != nil {
+		return nil, fmt.Errorf("certstore: invalid binding %q: %w", binding, err)
+	}
+
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("certstore: invalid binding %q: the host and the port are required", binding)
+	}
+
+	endpoint := "hostnameport=" + net.JoinHostPort(host, port)
+	if net.ParseIP(host) != nil {
+		endpoint = "ipport=" + net.JoinHostPort(host, port)
+	}
+
+	if storeName == "" {
+		storeName = DefaultStoreName
+	}
+
+	args := []string{"http", action, "sslcert", endpoint}
+
+	if action != "delete" {
+		args = append(args, "certhash="+thumbprint, "appid="+iisAppID, "certstorename="+storeName)
+	}
+
+	return args, nil
+}
+
+// appcmdSites the output of `appcmd list site /xml`.
+type appcmdSites struct {
+	Sites []struct {
+		Name     string `xml:"SITE.NAME,attr"`
+		Bindings string `xml:"bindings,attr"`
+	} `xml:"SITE"`
+}
+
+// parseSiteBindings returns the HTTPS bindings of an IIS site, from the output of `appcmd list site /xml`,
+// in the format used by UpdateIISBinding.
+// The bindings of IIS are `<protocol>/<ip>:<port>:<hostname>` (ex: `https/*:443:example.com`).
+func parseSiteBindings(data []byte, site string) ([]string, error) {
+	var sites appcmdSites
+
+	err := xml.Unmarshal(data, &sites)
+	if err != nil {
+		return nil, fmt.Errorf("certstore: parse the sites: %w", err)
+	}
+
+	for _, s := range sites.Sites {
+		if !strings.EqualFold(s.Name, site) {
+			continue
+		}
+
+		var bindings []string
+
+		for raw := range strings.SplitSeq(s.Bindings, ",") {
+			info, ok := strings.CutPrefix(strings.TrimSpace(raw), "https/")
+			if !ok {
+				continue
+			}
+
+			rest, hostname, _ := cutLast(info, ":")
+			ip, port, _ := cutLast(rest, ":")
+
+			if _, err := strconv.Atoi(port); err != nil {
+				return nil, fmt.Errorf("certstore: site %q: invalid binding %q", site, raw)
+			}
+
+			if hostname != "" {
+				bindings = append(bindings, net.JoinHostPort(hostname, port))
+				continue
+			}
+
+			ip = strings.Trim(ip, "[]")
+			if ip == "*" || ip == "" {
+				ip = "0.0.0.0"
+			}
+
+			bindings = append(bindings, net.JoinHostPort(ip, port))
+		}
+
+		if len(bindings) == 0 {
+			return nil, fmt.Errorf("certstore: site %q: no HTTPS binding", site)
+		}
+
+		return bindings, nil
+	}
+
+	return nil, fmt.Errorf("certstore: site %q not found", site)
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+len(sep):], true
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package certstore
+
+import "context"
+
+// Import imports a certificate (and its private key) into a LocalMachine store, then returns its thumbprint.
+func Import(_, _, _ []byte, _ *Options) (string, error) {
+	return "", ErrNotSupported
+}
+
+// SiteBindings returns the HTTPS bindings of an IIS site.
+func SiteBindings(_ context.Context, _ string) ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// UpdateIISBinding updates (or creates) the HTTP.sys SSL binding used by IIS with a certificate.
+func UpdateIISBinding(_ context.Context, _, _, _ string) error {
+	return ErrNotSupported
+}
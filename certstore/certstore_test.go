@@ -0,0 +1,145 @@
+package certstore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestThumbprint(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("data")}
+
+	assert.Equal(t, "A17C9AAA61E80A1BF71D0D850AF4E5BAA9800BBD", Thumbprint(cert))
+}
+
+func Test_encodePFX(t *testing.T) {
+	certPEM, keyPEM := generateCertificate(t, "example.com")
+	issuerPEM, _ := generateCertificate(t, "Issuer")
+
+	data, password, leaf, err := encodePFX(certPEM, keyPEM, issuerPEM)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", leaf.Subject.CommonName)
+
+	privateKey, cert, caCerts, err := pkcs12.DecodeChain(data, password)
+	require.NoError(t, err)
+
+	assert.NotNil(t, privateKey)
+	assert.Equal(t, leaf.Raw, cert.Raw)
+	require.Len(t, caCerts, 1)
+	assert.Equal(t, "Issuer", caCerts[0].Subject.CommonName)
+}
+
+func Test_encodePFX_invalidKey(t *testing.T) {
+	certPEM, _ := generateCertificate(t, "example.com")
+
+	_, _, _, err := encodePFX(certPEM, []byte("invalid"), nil)
+	require.ErrorContains(t, err, "certstore: parse private key")
+}
+
+func Test_sslCertArgs(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		action   string
+		binding  string
+		expected []string
+	}{
+		{
+			desc:    "IP binding",
+			action:  "update",
+			binding: "0.0.0.0:443",
+			expected: []string{
+				"http", "update", "sslcert", "ipport=0.0.0.0:443",
+				"certhash=ABC", "appid=" + iisAppID, "certstorename=My",
+			},
+		},
+		{
+			desc:    "IPv6 binding",
+			action:  "add",
+			binding: "[::]:8443",
+			expected: []string{
+				"http", "add", "sslcert", "ipport=[::]:8443",
+				"certhash=ABC", "appid=" + iisAppID, "certstorename=My",
+			},
+		},
+		{
+			desc:    "SNI binding",
+			action:  "update",
+			binding: "example.com:443",
+			expected: []string{
+				"http", "update", "sslcert", "hostnameport=example.com:443",
+				"certhash=ABC", "appid=" + iisAppID, "certstorename=My",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			args, err := sslCertArgs(test.action, test.binding, "ABC", "")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, args)
+		})
+	}
+}
+
+func Test_sslCertArgs_invalid(t *testing.T) {
+	_, err := sslCertArgs("update", "example.com", "ABC", "")
+	require.ErrorContains(t, err, `certstore: invalid binding "example.com"`)
+
+	_, err = sslCertArgs("update", ":443", "ABC", "")
+	require.ErrorContains(t, err, "the host and the port are required")
+}
+
+func Test_parseSiteBindings(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<appcmd>
+    <SITE SITE.NAME="Default Web Site" SITE.ID="1" bindings="http/*:80:,https/*:443:,https/*:443:example.com,https/[::]:8443:,https/10.0.0.1:9443:" state="Started" />
+    <SITE SITE.NAME="Intranet" SITE.ID="2" bindings="http/*:8080:" state="Started" />
+</appcmd>
+`)
+
+	bindings, err := parseSiteBindings(data, "default web site")
+	require.NoError(t, err)
+
+	expected := []string{"0.0.0.0:443", "example.com:443", "[::]:8443", "10.0.0.1:9443"}
+
+	assert.Equal(t, expected, bindings)
+
+	_, err = parseSiteBindings(data, "Intranet")
+	require.EqualError(t, err, `certstore: site "Intranet": no HTTPS binding`)
+
+	_, err = parseSiteBindings(data, "Other")
+	require.EqualError(t, err, `certstore: site "Other" not found`)
+}
+
+func generateCertificate(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der)), certcrypto.PEMEncode(privateKey)
+}
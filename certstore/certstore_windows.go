@@ -0,0 +1,190 @@
+//go:build windows
+
+package certstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const certFriendlyNamePropID = 11
+
+var procCertSetCertificateContextProperty = windows.NewLazySystemDLL("crypt32.dll").NewProc("CertSetCertificateContextProperty")
+
+// Import imports a certificate (and its private key) into a LocalMachine store, then returns its thumbprint.
+// The private key is persisted in the machine key set.
+// The issuer certificates are added to the intermediate certificates store ("CA").
+func Import(certPEM, keyPEM, issuerPEM []byte, opts *Options) (string, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	storeName := opts.StoreName
+	if storeName == "" {
+		storeName = DefaultStoreName
+	}
+
+	pfxData, password, leaf, err := encodePFX(certPEM, keyPEM, issuerPEM)
+	if err != nil {
+		return "", err
+	}
+
+	pfxPassword, err := windows.UTF16PtrFromString(password)
+	if err != nil {
+		return "", fmt.Errorf("certstore: %w", err)
+	}
+
+	blob := &windows.CryptDataBlob{Size: uint32(len(pfxData)), Data: &pfxData[0]}
+
+	pfxStore, err := windows.PFXImportCertStore(blob, pfxPassword, windows.CRYPT_MACHINE_KEYSET|windows.PKCS12_ALLOW_OVERWRITE_KEY)
+	if err != nil {
+		return "", fmt.Errorf("certstore: import PFX: %w", err)
+	}
+
+	defer func() { _ = windows.CertCloseStore(pfxStore, 0) }()
+
+	myStore, err := openStore(storeName)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = windows.CertCloseStore(myStore, 0) }()
+
+	caStore, err := openStore("CA")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = windows.CertCloseStore(caStore, 0) }()
+
+	var (
+		certCtx *windows.CertContext
+		found   bool
+	)
+
+	for {
+		certCtx, err = windows.CertEnumCertificatesInStore(pfxStore, certCtx)
+		if err != nil {
+			if errors.Is(err, windows.Errno(windows.CRYPT_E_NOT_FOUND)) {
+				break
+			}
+
+			return "", fmt.Errorf("certstore: enumerate certificates: %w", err)
+		}
+
+		encoded := unsafe.Slice(certCtx.EncodedCert, certCtx.Length)
+
+		if !bytes.Equal(encoded, leaf.Raw) {
+			err = windows.CertAddCertificateContextToStore(caStore, certCtx, windows.CERT_STORE_ADD_USE_EXISTING, nil)
+			if err != nil {
+				_ = windows.CertFreeCertificateContext(certCtx)
+				return "", fmt.Errorf("certstore: add issuer certificate: %w", err)
+			}
+
+			continue
+		}
+
+		found = true
+
+		if opts.FriendlyName != "" {
+			err = setFriendlyName(certCtx, opts.FriendlyName)
+			if err != nil {
+				_ = windows.CertFreeCertificateContext(certCtx)
+				return "", err
+			}
+		}
+
+		err = windows.CertAddCertificateContextToStore(myStore, certCtx, windows.CERT_STORE_ADD_REPLACE_EXISTING, nil)
+		if err != nil {
+			_ = windows.CertFreeCertificateContext(certCtx)
+			return "", fmt.Errorf("certstore: add certificate: %w", err)
+		}
+	}
+
+	if !found {
+		return "", errors.New("certstore: the certificate is missing from the PFX")
+	}
+
+	return Thumbprint(leaf), nil
+}
+
+// UpdateIISBinding updates (or creates) the HTTP.sys SSL binding used by IIS with a certificate.
+// The binding is `<ip>:<port>` (ex: `0.0.0.0:443`) or `<hostname>:<port>` for an SNI binding (ex: `example.com:443`).
+func UpdateIISBinding(ctx context.Context, binding, thumbprint, storeName string) error {
+	args, err := sslCertArgs("update", binding, thumbprint, storeName)
+	if err != nil {
+		return err
+	}
+
+	output, err := exec.CommandContext(ctx, "netsh", args...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	// The binding doesn't exist yet.
+	args, err = sslCertArgs("add", binding, thumbprint, storeName)
+	if err != nil {
+		return err
+	}
+
+	addOutput, err := exec.CommandContext(ctx, "netsh", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("certstore: update binding %s: %w: %s %s",
+			binding, err, bytes.TrimSpace(output), bytes.TrimSpace(addOutput))
+	}
+
+	return nil
+}
+
+// SiteBindings returns the HTTPS bindings of an IIS site, in the format used by UpdateIISBinding.
+func SiteBindings(ctx context.Context, site string) ([]string, error) {
+	appcmd := filepath.Join(os.Getenv("windir"), "system32", "inetsrv", "appcmd.exe")
+
+	output, err := exec.CommandContext(ctx, appcmd, "list", "site", "/xml").Output()
+	if err != nil {
+		return nil, fmt.Errorf("certstore: list the IIS sites: %w", err)
+	}
+
+	return parseSiteBindings(output, site)
+}
+
+func openStore(name string) (windows.Handle, error) {
+	storeName, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, fmt.Errorf("certstore: %w", err)
+	}
+
+	store, err := windows.CertOpenStore(windows.CERT_STORE_PROV_SYSTEM, 0, 0,
+		windows.CERT_SYSTEM_STORE_LOCAL_MACHINE, uintptr(unsafe.Pointer(storeName)))
+	if err != nil {
+		return 0, fmt.Errorf("certstore: open store LocalMachine\\%s: %w", name, err)
+	}
+
+	return store, nil
+}
+
+func setFriendlyName(certCtx *windows.CertContext, friendlyName string) error {
+	// The value is a null-terminated UTF-16 string, the size includes the terminator.
+	name, err := windows.UTF16FromString(friendlyName)
+	if err != nil {
+		return fmt.Errorf("certstore: %w", err)
+	}
+
+	blob := &windows.CryptDataBlob{Size: uint32(len(name) * 2), Data: (*byte)(unsafe.Pointer(&name[0]))}
+
+	r, _, err := procCertSetCertificateContextProperty.Call(
+		uintptr(unsafe.Pointer(certCtx)), certFriendlyNamePropID, 0, uintptr(unsafe.Pointer(blob)))
+	if r == 0 {
+		return fmt.Errorf("certstore: set friendly name: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/log"
+)
+
+// ChallengeRecord is a TXT record of a DNS-01 challenge found in a zone.
+type ChallengeRecord struct {
+	// ID is the identifier of the record used by the provider.
+	ID string
+
+	// FQDN is the full-qualified name of the record (i.e. `_acme-challenge.[domain].`).
+	FQDN string
+
+	// Value is the content of the record.
+	Value string
+
+	// CreatedAt is the creation date of the record, zero if the provider doesn't know it.
+	CreatedAt time.Time
+}
+
+// ErrUnsupportedProvider is returned when the DNS provider cannot list the challenge records (RecordLister).
+var ErrUnsupportedProvider = errors.New("unsupported provider: the DNS provider cannot list the challenge records")
+
+// RecordLister is implemented by the DNS providers able to list and delete the challenge records of a zone.
+// It's used to remove the records left behind by an interrupted process.
+type RecordLister interface {
+	// ListChallengeRecords returns the TXT records of the zone whose name starts with `_acme-challenge`.
+	ListChallengeRecords(ctx context.Context, zone string) ([]ChallengeRecord, error)
+
+	// DeleteChallengeRecord deletes a record returned by ListChallengeRecords.
+	DeleteChallengeRecord(ctx context.Context, zone string, record ChallengeRecord) error
+}
+
+// CleanupOptions are the options of CleanupOrphanedRecords.
+type CleanupOptions struct {
+	// OlderThan is the minimum age of the records to remove.
+	OlderThan time.Duration
+
+	// DryRun only reports the records to remove.
+	DryRun bool
+}
+
+// CanListChallengeRecords reports whether the DNS provider (or the provider wrapped by it) implements RecordLister.
+func CanListChallengeRecords(provider challenge.Provider) bool {
+	_, ok := unwrapProvider(provider).(RecordLister)
+
+	return ok
+}
+
+// CleanupOrphanedRecords removes the challenge records of the zones older than opts.OlderThan.
+// The records without a creation date are kept, with a warning.
+// It returns the removed records (or the records to remove for a dry run),
+// and ErrUnsupportedProvider if the DNS provider cannot list the challenge records.
+func CleanupOrphanedRecords(ctx context.Context, provider challenge.Provider, zones []string, opts CleanupOptions) ([]ChallengeRecord, error) {
+	lister, ok := provider.(RecordLister)
+	if !ok || !CanListChallengeRecords(provider) {
+		return nil, fmt.Errorf("%w (%T)", ErrUnsupportedProvider, unwrapProvider(provider))
+	}
+
+	if opts.OlderThan <= 0 {
+		return nil, errors.New("the minimum age of the records must be positive")
+	}
+
+	limit := time.Now().Add(-opts.OlderThan)
+
+	var (
+		removed []ChallengeRecord
+		errs    []error
+	)
+
+	for _, zone := range zones {
+		zone = ToFqdn(zone)
+
+		records, err := lister.ListChallengeRecords(ctx, zone)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("zone %s: %w", zone, err))
+			continue
+		}
+
+		for _, record := range records {
+			if !isChallengeRecord(record.FQDN) {
+				continue
+			}
+
+			if record.CreatedAt.IsZero() {
+				log.Warn("Keep a challenge record without creation date: its age is unknown.", "fqdn", record.FQDN, "id", record.ID)
+				continue
+			}
+
+			if record.CreatedAt.After(limit) {
+				continue
+			}
+
+			if !opts.DryRun {
+				err = lister.DeleteChallengeRecord(ctx, zone, record)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("zone %s: record %s (%s): %w", zone, record.FQDN, record.ID, err))
+					continue
+				}
+
+				log.Info("Orphaned challenge record removed.", "fqdn", record.FQDN, "id", record.ID, "createdAt", record.CreatedAt)
+			}
+
+			removed = append(removed, record)
+		}
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+func isChallengeRecord(fqdn string) bool {
+	return strings.HasPrefix(strings.ToLower(fqdn), "_acme-challenge.")
+}
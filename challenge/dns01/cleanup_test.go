@@ -0,0 +1,115 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordListerMock struct {
+	records map[string][]ChallengeRecord
+	deleted []string
+	failed  map[string]error
+}
+
+func (m *recordListerMock) Present(_, _, _ string) error { return nil }
+
+func (m *recordListerMock) CleanUp(_, _, _ string) error { return nil }
+
+func (m *recordListerMock) ListChallengeRecords(_ context.Context, zone string) ([]ChallengeRecord, error) {
+	return m.records[zone], nil
+}
+
+func (m *recordListerMock) DeleteChallengeRecord(_ context.Context, _ string, record ChallengeRecord) error {
+	if err := m.failed[record.ID]; err != nil {
+		return err
+	}
+
+	m.deleted = append(m.deleted, record.ID)
+
+	return nil
+}
+
+func newRecordListerMock() *recordListerMock {
+	now := time.Now()
+
+	return &recordListerMock{
+		records: map[string][]ChallengeRecord{
+			"example.com.": {
+				{ID: "old", FQDN: "_acme-challenge.example.com.", CreatedAt: now.Add(-48 * time.Hour)},
+				{ID: "recent", FQDN: "_acme-challenge.www.example.com.", CreatedAt: now.Add(-time.Hour)},
+				{ID: "unknown", FQDN: "_acme-challenge.api.example.com."},
+				{ID: "other", FQDN: "other.example.com.", CreatedAt: now.Add(-48 * time.Hour)},
+			},
+			"example.org.": {
+				{ID: "old-org", FQDN: "_ACME-Challenge.example.org.", CreatedAt: now.Add(-72 * time.Hour)},
+			},
+		},
+	}
+}
+
+func TestCleanupOrphanedRecords(t *testing.T) {
+	provider := newRecordListerMock()
+
+	removed, err := CleanupOrphanedRecords(t.Context(), provider, []string{"example.com", "example.org."}, CleanupOptions{OlderThan: 24 * time.Hour})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"old", "old-org"}, provider.deleted)
+	require.Len(t, removed, 2)
+	assert.Equal(t, "old", removed[0].ID)
+	assert.Equal(t, "old-org", removed[1].ID)
+}
+
+func TestCleanupOrphanedRecords_dryRun(t *testing.T) {
+	provider := newRecordListerMock()
+
+	removed, err := CleanupOrphanedRecords(t.Context(), provider, []string{"example.com"}, CleanupOptions{OlderThan: 24 * time.Hour, DryRun: true})
+	require.NoError(t, err)
+
+	assert.Empty(t, provider.deleted)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "old", removed[0].ID)
+}
+
+func TestCleanupOrphanedRecords_deleteError(t *testing.T) {
+	provider := newRecordListerMock()
+	provider.failed = map[string]error{"old": errors.New("boom")}
+
+	removed, err := CleanupOrphanedRecords(t.Context(), provider, []string{"example.com", "example.org"}, CleanupOptions{OlderThan: 24 * time.Hour})
+	require.EqualError(t, err, "zone example.com.: record _acme-challenge.example.com. (old): boom")
+
+	assert.Equal(t, []string{"old-org"}, provider.deleted)
+	require.Len(t, removed, 1)
+}
+
+func TestCleanupOrphanedRecords_zoneCoordinator(t *testing.T) {
+	provider := newRecordListerMock()
+
+	removed, err := CleanupOrphanedRecords(t.Context(), NewZoneCoordinator(provider), []string{"example.com"}, CleanupOptions{OlderThan: 24 * time.Hour})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"old"}, provider.deleted)
+	require.Len(t, removed, 1)
+}
+
+func TestCanListChallengeRecords(t *testing.T) {
+	assert.True(t, CanListChallengeRecords(newRecordListerMock()))
+	assert.True(t, CanListChallengeRecords(NewZoneCoordinator(newRecordListerMock())))
+	assert.False(t, CanListChallengeRecords(&providerMock{}))
+	assert.False(t, CanListChallengeRecords(NewZoneCoordinator(&providerMock{})))
+}
+
+func TestCleanupOrphanedRecords_errors(t *testing.T) {
+	_, err := CleanupOrphanedRecords(t.Context(), &providerMock{}, []string{"example.com"}, CleanupOptions{OlderThan: time.Hour})
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+
+	_, err = CleanupOrphanedRecords(t.Context(), NewZoneCoordinator(&providerMock{}), []string{"example.com"}, CleanupOptions{OlderThan: time.Hour})
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+
+	_, err = CleanupOrphanedRecords(t.Context(), newRecordListerMock(), []string{"example.com"}, CleanupOptions{})
+	require.EqualError(t, err, "the minimum age of the records must be positive")
+}
@@ -0,0 +1,446 @@
+package dns01
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+)
+
+// ZoneCoordinator wraps a DNS provider shared by several certificates (or several solvers) of the same run.
+// It's opt-in: the DNS provider must be wrapped explicitly (the CLI doesn't use it).
+//
+//   - The identical records (same FQDN and same value) are created once, and removed when the last user cleans them.
+//   - The writes (Present, CleanUp, DeleteChallengeRecord) of a zone are serialized, not coalesced:
+//     each write is still a call to the provider (except the batches, see below).
+//   - The listing of the challenge records of a zone (RecordLister) is shared until the next write in the zone.
+//   - The batches (PresentAll, CleanUpAll) are forwarded to the provider if it implements challenge.ProviderBatch.
+//
+// A ZoneCoordinator is safe for concurrent use.
+type ZoneCoordinator struct {
+	provider challenge.Provider
+
+	findZone func(fqdn string) (string, error)
+
+	mu      sync.Mutex
+	zones   map[string]*zoneLookup
+	locks   map[string]*sync.Mutex
+	records map[string]*sharedRecord
+	lists   map[string]*recordList
+}
+
+type zoneLookup struct {
+	done chan struct{}
+	zone string
+	err  error
+}
+
+type sharedRecord struct {
+	done chan struct{}
+	err  error
+	refs int
+}
+
+type recordList struct {
+	done    chan struct{}
+	records []ChallengeRecord
+	err     error
+}
+
+// NewZoneCoordinator creates a ZoneCoordinator for the provider.
+func NewZoneCoordinator(provider challenge.Provider) *ZoneCoordinator {
+	return &ZoneCoordinator{
+		provider: provider,
+		findZone: FindZoneByFqdn,
+		zones:    make(map[string]*zoneLookup),
+		locks:    make(map[string]*sync.Mutex),
+		records:  make(map[string]*sharedRecord),
+		lists:    make(map[string]*recordList),
+	}
+}
+
+// Unwrap returns the wrapped provider.
+func (c *ZoneCoordinator) Unwrap() challenge.Provider {
+	return c.provider
+}
+
+// Present creates the TXT record, unless an identical record is already created (or being created).
+func (c *ZoneCoordinator) Present(domain, token, keyAuth string) error {
+	info := GetChallengeInfo(domain, keyAuth)
+	key := info.EffectiveFQDN + " " + info.Value
+
+	c.mu.Lock()
+
+	if rec, ok := c.records[key]; ok {
+		rec.refs++
+		c.mu.Unlock()
+
+		<-rec.done
+
+		return rec.err
+	}
+
+	rec := &sharedRecord{done: make(chan struct{}), refs: 1}
+	c.records[key] = rec
+
+	c.mu.Unlock()
+
+	rec.err = c.write(info.EffectiveFQDN, func() error {
+		return c.provider.Present(domain, token, keyAuth)
+	})
+
+	if rec.err != nil {
+		c.mu.Lock()
+		delete(c.records, key)
+		c.mu.Unlock()
+	}
+
+	close(rec.done)
+
+	return rec.err
+}
+
+// CleanUp removes the TXT record when it's not used by another challenge.
+func (c *ZoneCoordinator) CleanUp(domain, token, keyAuth string) error {
+	info := GetChallengeInfo(domain, keyAuth)
+	key := info.EffectiveFQDN + " " + info.Value
+
+	c.mu.Lock()
+
+	if rec, ok := c.records[key]; ok {
+		rec.refs--
+		if rec.refs > 0 {
+			c.mu.Unlock()
+			return nil
+		}
+
+		delete(c.records, key)
+	}
+
+	c.mu.Unlock()
+
+	return c.write(info.EffectiveFQDN, func() error {
+		return c.provider.CleanUp(domain, token, keyAuth)
+	})
+}
+
+// PresentAll creates the TXT records with a single call to the wrapped provider,
+// except the records already created (or being created).
+// The wrapped provider must implement challenge.ProviderBatch.
+func (c *ZoneCoordinator) PresentAll(challenges []challenge.ChallengeInfo) error {
+	provider, ok := c.provider.(challenge.ProviderBatch)
+	if !ok {
+		return fmt.Errorf("the DNS provider %T cannot present several challenges at once", c.provider)
+	}
+
+	var (
+		owned   []challenge.ChallengeInfo
+		keys    []string
+		fqdns   []string
+		created = make(map[string]*sharedRecord)
+		shared  []*sharedRecord
+	)
+
+	c.mu.Lock()
+
+	for _, chlg := range challenges {
+		info := GetChallengeInfo(chlg.Domain, chlg.KeyAuth)
+		key := info.EffectiveFQDN + " " + info.Value
+
+		if rec, ok := c.records[key]; ok {
+			rec.refs++
+
+			if _, own := created[key]; !own {
+				shared = append(shared, rec)
+			}
+
+			continue
+		}
+
+		rec := &sharedRecord{done: make(chan struct{}), refs: 1}
+		c.records[key] = rec
+		created[key] = rec
+
+		owned = append(owned, chlg)
+		keys = append(keys, key)
+		fqdns = append(fqdns, info.EffectiveFQDN)
+	}
+
+	c.mu.Unlock()
+
+	var err error
+
+	if len(owned) > 0 {
+		err = c.writeAll(fqdns, func() error {
+			return provider.PresentAll(owned)
+		})
+
+		c.mu.Lock()
+
+		for _, key := range keys {
+			rec := created[key]
+			rec.err = err
+
+			if err != nil {
+				delete(c.records, key)
+			}
+
+			close(rec.done)
+		}
+
+		c.mu.Unlock()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range shared {
+		<-rec.done
+
+		if rec.err != nil {
+			return rec.err
+		}
+	}
+
+	return nil
+}
+
+// CleanUpAll removes the TXT records not used by other challenges with a single call to the wrapped provider.
+// The wrapped provider must implement challenge.ProviderBatch.
+func (c *ZoneCoordinator) CleanUpAll(challenges []challenge.ChallengeInfo) error {
+	provider, ok := c.provider.(challenge.ProviderBatch)
+	if !ok {
+		return fmt.Errorf("the DNS provider %T cannot clean up several challenges at once", c.provider)
+	}
+
+	var (
+		unused []challenge.ChallengeInfo
+		fqdns  []string
+	)
+
+	c.mu.Lock()
+
+	for _, chlg := range challenges {
+		info := GetChallengeInfo(chlg.Domain, chlg.KeyAuth)
+		key := info.EffectiveFQDN + " " + info.Value
+
+		if rec, ok := c.records[key]; ok {
+			rec.refs--
+			if rec.refs > 0 {
+				continue
+			}
+
+			delete(c.records, key)
+		}
+
+		unused = append(unused, chlg)
+		fqdns = append(fqdns, info.EffectiveFQDN)
+	}
+
+	c.mu.Unlock()
+
+	if len(unused) == 0 {
+		return nil
+	}
+
+	return c.writeAll(fqdns, func() error {
+		return provider.CleanUpAll(unused)
+	})
+}
+
+// Timeout returns the timeout and the interval of the wrapped provider.
+func (c *ZoneCoordinator) Timeout() (timeout, interval time.Duration) {
+	if p, ok := c.provider.(challenge.ProviderTimeout); ok {
+		return p.Timeout()
+	}
+
+	return DefaultPropagationTimeout, DefaultPollingInterval
+}
+
+// zoneOf returns the zone of the FQDN, used to serialize the writes.
+// The lookup is done once per FQDN for the lifetime of the ZoneCoordinator.
+func (c *ZoneCoordinator) zoneOf(fqdn string) (string, error) {
+	c.mu.Lock()
+
+	if lookup, ok := c.zones[fqdn]; ok {
+		c.mu.Unlock()
+
+		<-lookup.done
+
+		return lookup.zone, lookup.err
+	}
+
+	lookup := &zoneLookup{done: make(chan struct{})}
+	c.zones[fqdn] = lookup
+
+	c.mu.Unlock()
+
+	lookup.zone, lookup.err = c.findZone(fqdn)
+
+	if lookup.err != nil {
+		// The errors are not kept: the next call retries.
+		c.mu.Lock()
+		delete(c.zones, fqdn)
+		c.mu.Unlock()
+	}
+
+	close(lookup.done)
+
+	return lookup.zone, lookup.err
+}
+
+// ListChallengeRecords implements RecordLister.
+// The listing of a zone is shared until the next write in this zone.
+func (c *ZoneCoordinator) ListChallengeRecords(ctx context.Context, zone string) ([]ChallengeRecord, error) {
+	lister, ok := c.provider.(RecordLister)
+	if !ok {
+		return nil, fmt.Errorf("%w (%T)", ErrUnsupportedProvider, c.provider)
+	}
+
+	zone = ToFqdn(zone)
+
+	c.mu.Lock()
+
+	if list, ok := c.lists[zone]; ok {
+		c.mu.Unlock()
+
+		<-list.done
+
+		return list.records, list.err
+	}
+
+	list := &recordList{done: make(chan struct{})}
+	c.lists[zone] = list
+
+	c.mu.Unlock()
+
+	list.records, list.err = lister.ListChallengeRecords(ctx, zone)
+
+	if list.err != nil {
+		c.mu.Lock()
+		if c.lists[zone] == list {
+			delete(c.lists, zone)
+		}
+		c.mu.Unlock()
+	}
+
+	close(list.done)
+
+	return list.records, list.err
+}
+
+// DeleteChallengeRecord implements RecordLister.
+func (c *ZoneCoordinator) DeleteChallengeRecord(ctx context.Context, zone string, record ChallengeRecord) error {
+	lister, ok := c.provider.(RecordLister)
+	if !ok {
+		return fmt.Errorf("%w (%T)", ErrUnsupportedProvider, c.provider)
+	}
+
+	zone = ToFqdn(zone)
+
+	unlock := c.lockZone(zone)
+	defer unlock()
+
+	return lister.DeleteChallengeRecord(ctx, zone, record)
+}
+
+// write calls fn while holding the lock of the zone of the FQDN.
+// If the zone cannot be found, the write is done without lock: the provider is responsible for the error.
+func (c *ZoneCoordinator) write(fqdn string, fn func() error) error {
+	zone, err := c.zoneOf(fqdn)
+	if err != nil {
+		return fn()
+	}
+
+	unlock := c.lockZone(zone)
+	defer unlock()
+
+	return fn()
+}
+
+// writeAll calls fn while holding the locks of the zones of the FQDNs.
+// The locks are acquired in the order of the zone names, so two batches cannot wait for each other.
+func (c *ZoneCoordinator) writeAll(fqdns []string, fn func() error) error {
+	var zones []string
+
+	for _, fqdn := range fqdns {
+		zone, err := c.zoneOf(fqdn)
+		if err != nil {
+			continue
+		}
+
+		zones = append(zones, zone)
+	}
+
+	slices.Sort(zones)
+
+	var unlocks []func()
+
+	for _, zone := range slices.Compact(zones) {
+		unlocks = append(unlocks, c.lockZone(zone))
+	}
+
+	defer func() {
+		for _, unlock := range slices.Backward(unlocks) {
+			unlock()
+		}
+	}()
+
+	return fn()
+}
+
+// lockZone locks the writes of a zone, and invalidates its listing.
+func (c *ZoneCoordinator) lockZone(zone string) func() {
+	c.mu.Lock()
+
+	lock, ok := c.locks[zone]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.locks[zone] = lock
+	}
+
+	c.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		c.mu.Lock()
+		delete(c.lists, zone)
+		c.mu.Unlock()
+
+		lock.Unlock()
+	}
+}
+
+// unwrapProvider returns the provider wrapped by a ZoneCoordinator (or any type with an Unwrap method).
+func unwrapProvider(provider challenge.Provider) challenge.Provider {
+	for {
+		w, ok := provider.(interface{ Unwrap() challenge.Provider })
+		if !ok {
+			return provider
+		}
+
+		provider = w.Unwrap()
+	}
+}
+
+// batchProvider returns the ProviderBatch to use for the provider,
+// if the provider (or the provider wrapped by it) implements challenge.ProviderBatch.
+// The outermost implementation is used, so a wrapper forwarding the batches (ex: ZoneCoordinator) is not bypassed.
+func batchProvider(provider challenge.Provider) (challenge.ProviderBatch, bool) {
+	if _, ok := unwrapProvider(provider).(challenge.ProviderBatch); !ok {
+		return nil, false
+	}
+
+	for {
+		if p, ok := provider.(challenge.ProviderBatch); ok {
+			return p, true
+		}
+
+		provider = provider.(interface{ Unwrap() challenge.Provider }).Unwrap()
+	}
+}
@@ -0,0 +1,214 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingProvider struct {
+	mu       sync.Mutex
+	presents int
+	cleanups int
+	lists    int
+	err      error
+}
+
+func (p *countingProvider) Present(_, _, _ string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.presents++
+
+	return p.err
+}
+
+func (p *countingProvider) CleanUp(_, _, _ string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cleanups++
+
+	return nil
+}
+
+func (p *countingProvider) ListChallengeRecords(_ context.Context, _ string) ([]ChallengeRecord, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lists++
+
+	return []ChallengeRecord{{ID: "a", FQDN: "_acme-challenge.example.com."}}, nil
+}
+
+func (p *countingProvider) DeleteChallengeRecord(_ context.Context, _ string, _ ChallengeRecord) error {
+	return nil
+}
+
+func (p *countingProvider) Sequential() time.Duration {
+	return time.Second
+}
+
+func newTestZoneCoordinator(t *testing.T, provider *countingProvider) (*ZoneCoordinator, *atomic.Int32) {
+	t.Helper()
+
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	lookups := &atomic.Int32{}
+
+	c := NewZoneCoordinator(provider)
+	c.findZone = func(fqdn string) (string, error) {
+		lookups.Add(1)
+		return "example.com.", nil
+	}
+
+	return c, lookups
+}
+
+func TestZoneCoordinator_Present(t *testing.T) {
+	provider := &countingProvider{}
+
+	c, lookups := newTestZoneCoordinator(t, provider)
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			assert.NoError(t, c.Present("example.com", "token", "keyAuth"))
+		})
+	}
+
+	wg.Wait()
+
+	require.NoError(t, c.Present("www.example.com", "token", "keyAuth"))
+
+	assert.Equal(t, 2, provider.presents)
+	assert.EqualValues(t, 2, lookups.Load())
+
+	for range 10 {
+		require.NoError(t, c.CleanUp("example.com", "token", "keyAuth"))
+	}
+
+	// The record is removed only by the last cleanup.
+	assert.Equal(t, 1, provider.cleanups)
+}
+
+func TestZoneCoordinator_Present_error(t *testing.T) {
+	provider := &countingProvider{err: errors.New("boom")}
+
+	c, _ := newTestZoneCoordinator(t, provider)
+
+	require.EqualError(t, c.Present("example.com", "token", "keyAuth"), "boom")
+
+	// The failed record is not shared.
+	provider.err = nil
+
+	require.NoError(t, c.Present("example.com", "token", "keyAuth"))
+
+	assert.Equal(t, 2, provider.presents)
+}
+
+func TestZoneCoordinator_PresentAll(t *testing.T) {
+	provider := &providerBatchMock{}
+
+	c := NewZoneCoordinator(provider)
+	c.findZone = func(fqdn string) (string, error) {
+		return "example.com.", nil
+	}
+
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	require.NoError(t, c.Present("example.com", "a", "keyAuthA"))
+
+	challenges := []challenge.ChallengeInfo{
+		{Domain: "example.com", Token: "a", KeyAuth: "keyAuthA"},
+		{Domain: "example.com", Token: "b", KeyAuth: "keyAuthB"},
+	}
+
+	require.NoError(t, c.PresentAll(challenges))
+
+	// The record already created by Present is not created again.
+	require.Len(t, provider.challenges, 1)
+	assert.Equal(t, "b", provider.challenges[0].Token)
+
+	require.NoError(t, c.CleanUpAll(challenges))
+
+	// The record still used by the first Present is not removed.
+	require.Len(t, provider.cleaned, 1)
+	assert.Equal(t, "b", provider.cleaned[0].Token)
+
+	require.NoError(t, c.CleanUp("example.com", "a", "keyAuthA"))
+}
+
+func TestZoneCoordinator_PresentAll_error(t *testing.T) {
+	provider := &providerBatchMock{presentAll: errors.New("boom")}
+
+	c := NewZoneCoordinator(provider)
+	c.findZone = func(fqdn string) (string, error) {
+		return "example.com.", nil
+	}
+
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	challenges := []challenge.ChallengeInfo{{Domain: "example.com", Token: "a", KeyAuth: "keyAuthA"}}
+
+	require.EqualError(t, c.PresentAll(challenges), "boom")
+
+	// The failed records are not shared.
+	provider.presentAll = nil
+
+	require.NoError(t, c.PresentAll(challenges))
+
+	assert.Len(t, provider.challenges, 2)
+}
+
+func TestZoneCoordinator_PresentAll_unsupported(t *testing.T) {
+	c := NewZoneCoordinator(&providerMock{})
+
+	err := c.PresentAll([]challenge.ChallengeInfo{{Domain: "example.com", Token: "a", KeyAuth: "keyAuthA"}})
+	require.ErrorContains(t, err, "cannot present several challenges at once")
+}
+
+func TestZoneCoordinator_ListChallengeRecords(t *testing.T) {
+	provider := &countingProvider{}
+
+	c, _ := newTestZoneCoordinator(t, provider)
+
+	for range 3 {
+		records, err := c.ListChallengeRecords(t.Context(), "example.com")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+	}
+
+	assert.Equal(t, 1, provider.lists)
+
+	// A write invalidates the listing of the zone.
+	require.NoError(t, c.Present("example.com", "token", "keyAuth"))
+
+	_, err := c.ListChallengeRecords(t.Context(), "example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, provider.lists)
+}
+
+func TestZoneCoordinator_ListChallengeRecords_unsupported(t *testing.T) {
+	c := NewZoneCoordinator(&providerMock{})
+
+	_, err := c.ListChallengeRecords(t.Context(), "example.com")
+	require.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestZoneCoordinator_wrappedProvider(t *testing.T) {
+	chlg := NewChallenge(nil, nil, NewZoneCoordinator(&countingProvider{}))
+
+	ok, interval := chlg.Sequential()
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, interval)
+}
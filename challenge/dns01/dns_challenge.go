@@ -1,18 +1,25 @@
 package dns01
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cenkalti/backoff/v5"
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
 	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/events"
 	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/metrics"
 	"github.com/digicert/lego/v4/platform/wait"
+	"github.com/digicert/lego/v4/progress"
 	"github.com/miekg/dns"
+	"golang.org/x/net/idna"
 )
 
 const (
@@ -28,8 +35,35 @@ const (
 
 type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
 
+// ValidateContextFunc is like ValidateFunc, but the validation is interrupted as soon as the context is done.
+type ValidateContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
 type ChallengeOption func(*Challenge) error
 
+// SetValidateContext sets the validation function used by SolveWithContext.
+// Without it, the context is only checked before the validation.
+func SetValidateContext(validate ValidateContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
+// SetPropagationTimeout overrides the propagation timeout and the polling interval of the DNS provider.
+// A zero value keeps the value of the provider.
+func SetPropagationTimeout(timeout, interval time.Duration) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if timeout < 0 || interval < 0 {
+			return errors.New("the propagation timeout and the polling interval cannot be negative")
+		}
+
+		chlg.propagationTimeout = timeout
+		chlg.pollingInterval = interval
+
+		return nil
+	}
+}
+
 // CondOption Conditional challenge option.
 func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
 	if !condition {
@@ -44,11 +78,18 @@ func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
 
 // Challenge implements the dns-01 challenge.
 type Challenge struct {
-	core       *api.Core
-	validate   ValidateFunc
-	provider   challenge.Provider
-	preCheck   preCheck
-	dnsTimeout time.Duration
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateContextFunc
+	provider    challenge.Provider
+	preCheck    preCheck
+	dnsTimeout  time.Duration
+
+	// propagationTimeout and pollingInterval override the values of the provider when they are not zero.
+	propagationTimeout time.Duration
+	pollingInterval    time.Duration
+
+	providerRetry providerRetry
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
@@ -58,12 +99,22 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 		provider:   provider,
 		preCheck:   newPreCheck(),
 		dnsTimeout: 10 * time.Second,
+
+		providerRetry: providerRetry{attempts: 1},
+	}
+
+	if p, ok := unwrapProvider(provider).(authoritativeNameservers); ok {
+		chlg.preCheck.authoritativeNss = p.AuthoritativeNameservers()
+	}
+
+	if p, ok := unwrapProvider(provider).(propagationCheckProvider); ok {
+		chlg.preCheck.providerCheck = p.PropagationCheck()
 	}
 
 	for _, opt := range opts {
 		err := opt(chlg)
 		if err != nil {
-			log.Infof("challenge option error: %v", err)
+			chlg.core.Logger().Warn("challenge option error.", "error", err)
 		}
 	}
 
@@ -73,8 +124,13 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 // PreSolve just submits the txt record to the dns provider.
 // It does not validate record propagation, or do anything at all with the acme server.
 func (c *Challenge) PreSolve(authz acme.Authorization) error {
+	return c.PreSolveWithContext(context.Background(), authz)
+}
+
+// PreSolveWithContext is like PreSolve, but the retries of the DNS provider call are interrupted as soon as the context is done.
+func (c *Challenge) PreSolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
-	log.Infof("[%s] acme: Preparing to solve DNS-01", domain)
+	c.core.Logger().Info("acme: Preparing to solve DNS-01.", "domain", domain)
 
 	chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
 	if err != nil {
@@ -91,17 +147,103 @@ func (c *Challenge) PreSolve(authz acme.Authorization) error {
 		return err
 	}
 
-	err = c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = c.callProvider(ctx, domain, "present", func() error {
+		return c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	})
 	if err != nil {
 		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
 	}
 
+	c.core.Events().Publish(events.ChallengePresented{Domain: domain, Type: string(challenge.DNS01)})
+
 	return nil
 }
 
+// PreSolveAll submits the TXT records of the authorizations with a single call to the DNS provider,
+// if the provider implements challenge.ProviderBatch.
+// It returns false if the provider doesn't support it: the records must be submitted by PreSolve.
+// The retries of the DNS provider call are interrupted as soon as the context is done.
+func (c *Challenge) PreSolveAll(ctx context.Context, authzs []acme.Authorization) (bool, error) {
+	provider, ok := batchProvider(c.provider)
+	if !ok {
+		return false, nil
+	}
+
+	domains, challenges, err := c.challengeInfos(authzs, "acme: Preparing to solve DNS-01.")
+	if err != nil {
+		return true, err
+	}
+
+	err = c.callProvider(ctx, strings.Join(domains, ", "), "present", func() error {
+		return provider.PresentAll(challenges)
+	})
+	if err != nil {
+		return true, fmt.Errorf("[%s] acme: error presenting tokens: %w", strings.Join(domains, ", "), err)
+	}
+
+	for _, domain := range domains {
+		c.core.Events().Publish(events.ChallengePresented{Domain: domain, Type: string(challenge.DNS01)})
+	}
+
+	return true, nil
+}
+
+// CleanUpAll removes the TXT records of the authorizations with a single call to the DNS provider,
+// if the provider implements challenge.ProviderBatch.
+// It returns false if the provider doesn't support it: the records must be removed by CleanUp.
+func (c *Challenge) CleanUpAll(authzs []acme.Authorization) (bool, error) {
+	provider, ok := batchProvider(c.provider)
+	if !ok {
+		return false, nil
+	}
+
+	domains, challenges, err := c.challengeInfos(authzs, "acme: Cleaning DNS-01 challenge.")
+	if err != nil {
+		return true, err
+	}
+
+	return true, c.callProvider(context.Background(), strings.Join(domains, ", "), "cleanup", func() error {
+		return provider.CleanUpAll(challenges)
+	})
+}
+
+// challengeInfos returns the targeted domains and the parameters of the DNS-01 challenges of the authorizations.
+func (c *Challenge) challengeInfos(authzs []acme.Authorization, msg string) ([]string, []challenge.ChallengeInfo, error) {
+	var (
+		domains    []string
+		challenges []challenge.ChallengeInfo
+	)
+
+	for _, authz := range authzs {
+		domain := challenge.GetTargetedDomain(authz)
+		c.core.Logger().Info(msg, "domain", domain)
+
+		chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		domains = append(domains, domain)
+		challenges = append(challenges, challenge.ChallengeInfo{Domain: authz.Identifier.Value, Token: chlng.Token, KeyAuth: keyAuth})
+	}
+
+	return domains, challenges, nil
+}
+
+// Solve waits for the propagation of the DNS record, and validates the challenge.
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext is like Solve, but the waits (propagation, validation) are interrupted as soon as the context is done.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
-	log.Infof("[%s] acme: Trying to solve DNS-01", domain)
+	c.core.Logger().Info("acme: Trying to solve DNS-01.", "domain", domain)
 
 	chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
 	if err != nil {
@@ -125,30 +267,112 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		timeout, interval = DefaultPropagationTimeout, DefaultPollingInterval
 	}
 
-	log.Infof("[%s] acme: Checking DNS record propagation. [nameservers=%s]", domain, strings.Join(recursiveNameservers, ","))
+	if c.propagationTimeout > 0 {
+		timeout = c.propagationTimeout
+	}
+
+	if c.pollingInterval > 0 {
+		interval = c.pollingInterval
+	}
+
+	check := c.preCheck
+
+	if p, ok := unwrapProvider(c.provider).(propagationHintsProvider); ok {
+		hintWait, nameservers := p.PropagationHints(info.EffectiveFQDN)
+
+		if len(nameservers) > 0 {
+			check.authoritativeNss = nameservers
+		}
+
+		if hintWait > 0 {
+			c.core.Logger().Info("acme: Waiting before checking DNS record propagation, as requested by the DNS provider.",
+				"domain", domain, "wait", hintWait)
+
+			err = wait.Sleep(ctx, hintWait)
+			if err != nil {
+				return fmt.Errorf("[%s] acme: propagation: %w", domain, err)
+			}
+		}
+	}
+
+	c.core.Logger().Info("acme: Checking DNS record propagation.",
+		"domain", domain, "nameservers", strings.Join(recursiveNameservers, ","))
+
+	err = wait.Sleep(ctx, interval)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: propagation: %w", domain, err)
+	}
 
-	time.Sleep(interval)
+	start := time.Now()
 
-	err = wait.For("propagation", timeout, interval, func() (bool, error) {
-		stop, errP := c.preCheck.call(domain, info.EffectiveFQDN, info.Value)
+	var attempt int
+
+	err = wait.ForWithContext(ctx, "propagation", timeout, backoff.NewConstantBackOff(interval), func() (bool, error) {
+		attempt++
+		c.core.Progress().Attempt(progress.PhasePropagation, domain, attempt)
+
+		if c.providerPropagated(domain, authz.Identifier.Value, chlng.Token) {
+			return true, nil
+		}
+
+		stop, errP := check.call(domain, info.EffectiveFQDN, info.Value)
 		if !stop || errP != nil {
-			log.Infof("[%s] acme: Waiting for DNS record propagation.", domain)
+			c.core.Logger().Info("acme: Waiting for DNS record propagation.", "domain", domain)
 		}
 
 		return stop, errP
 	})
+
+	metrics.ObserveDuration(metrics.PropagationDuration, start, metrics.Labels{"result": metrics.Result(err)})
+
 	if err != nil {
 		return err
 	}
 
+	c.core.Events().Publish(events.PropagationConfirmed{Domain: domain, FQDN: info.EffectiveFQDN, Duration: time.Since(start)})
+
 	chlng.KeyAuthorization = keyAuth
 
+	return c.validateWithContext(ctx, domain, chlng)
+}
+
+// providerPropagated returns true if the provider reports the challenge as propagated (challenge.ProviderPropagated).
+// An error of the provider is not fatal: the propagation is checked with the DNS queries.
+func (c *Challenge) providerPropagated(domain, identifier, token string) bool {
+	provider, ok := unwrapProvider(c.provider).(challenge.ProviderPropagated)
+	if !ok {
+		return false
+	}
+
+	propagated, err := provider.Propagated(identifier, token)
+	if err != nil {
+		c.core.Logger().Warn("acme: The DNS provider could not report the propagation.", "domain", domain, "error", err)
+		return false
+	}
+
+	if propagated {
+		c.core.Logger().Info("acme: The DNS provider reports the record as propagated.", "domain", domain)
+	}
+
+	return propagated
+}
+
+func (c *Challenge) validateWithContext(ctx context.Context, domain string, chlng acme.Challenge) error {
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("[%s] acme: validation: %w", domain, context.Cause(ctx))
+	}
+
 	return c.validate(c.core, domain, chlng)
 }
 
 // CleanUp cleans the challenge.
 func (c *Challenge) CleanUp(authz acme.Authorization) error {
-	log.Infof("[%s] acme: Cleaning DNS-01 challenge", challenge.GetTargetedDomain(authz))
+	domain := challenge.GetTargetedDomain(authz)
+	c.core.Logger().Info("acme: Cleaning DNS-01 challenge.", "domain", domain)
 
 	chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
 	if err != nil {
@@ -160,11 +384,21 @@ func (c *Challenge) CleanUp(authz acme.Authorization) error {
 		return err
 	}
 
-	return c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+	return c.callProvider(context.Background(), domain, "cleanup", func() error {
+		return c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+	})
+}
+
+// Concurrent reports whether the challenges of several authorizations can be solved concurrently:
+// only the DNS providers declaring that they're safe for concurrent use (challenge.ProviderConcurrent).
+func (c *Challenge) Concurrent() bool {
+	p, ok := unwrapProvider(c.provider).(challenge.ProviderConcurrent)
+
+	return ok && p.Concurrent()
 }
 
 func (c *Challenge) Sequential() (bool, time.Duration) {
-	if p, ok := c.provider.(sequential); ok {
+	if p, ok := unwrapProvider(c.provider).(sequential); ok {
 		return ok, p.Sequential()
 	}
 
@@ -175,6 +409,26 @@ type sequential interface {
 	Sequential() time.Duration
 }
 
+// authoritativeNameservers is implemented by providers that know the authoritative name servers of the zones they manage.
+// When the returned list is not empty, the propagation check queries those name servers directly.
+type authoritativeNameservers interface {
+	AuthoritativeNameservers() []string
+}
+
+// propagationCheckProvider is implemented by providers that check the propagation of the records with their API
+// (ex: the private zones, not reachable by the DNS queries).
+// When the returned function is not nil, it replaces the DNS queries of the propagation check.
+type propagationCheckProvider interface {
+	PropagationCheck() PreCheckFunc
+}
+
+// propagationHintsProvider is implemented by providers that receive propagation hints when they create a record
+// (ex: the time to wait before checking the record, the name servers to query).
+// The hints are used for the record only, a zero value means no hint.
+type propagationHintsProvider interface {
+	PropagationHints(fqdn string) (wait time.Duration, nameservers []string)
+}
+
 // GetRecord returns a DNS record which will fulfill the `dns-01` challenge.
 //
 // Deprecated: use GetChallengeInfo instead.
@@ -210,6 +464,11 @@ func GetChallengeInfo(domain, keyAuth string) ChallengeInfo {
 }
 
 func getChallengeFQDN(domain string, followCNAME bool) string {
+	// The internationalized domains are converted to A-labels.
+	if ascii, err := idna.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+
 	fqdn := fmt.Sprintf("_acme-challenge.%s.", domain)
 
 	if !followCNAME {
@@ -232,7 +491,7 @@ func getChallengeFQDN(domain string, followCNAME bool) string {
 			break
 		}
 
-		log.Infof("Found CNAME entry for %q: %q", fqdn, cname)
+		log.Info("Found CNAME entry.", "fqdn", fqdn, "cname", cname)
 
 		fqdn = cname
 	}
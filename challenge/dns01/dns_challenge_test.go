@@ -32,6 +32,70 @@ func (p *providerTimeoutMock) Present(domain, token, keyAuth string) error { ret
 func (p *providerTimeoutMock) CleanUp(domain, token, keyAuth string) error { return p.cleanUp }
 func (p *providerTimeoutMock) Timeout() (time.Duration, time.Duration)     { return p.timeout, p.interval }
 
+type providerHintsMock struct {
+	providerMock
+
+	wait        time.Duration
+	nameservers []string
+
+	fqdn string
+}
+
+func (p *providerHintsMock) PropagationHints(fqdn string) (time.Duration, []string) {
+	p.fqdn = fqdn
+
+	return p.wait, p.nameservers
+}
+
+type providerBatchMock struct {
+	providerMock
+
+	presentAll error
+	challenges []challenge.ChallengeInfo
+
+	cleanUpAll error
+	cleaned    []challenge.ChallengeInfo
+}
+
+func (p *providerBatchMock) PresentAll(challenges []challenge.ChallengeInfo) error {
+	p.challenges = append(p.challenges, challenges...)
+
+	return p.presentAll
+}
+
+func (p *providerBatchMock) CleanUpAll(challenges []challenge.ChallengeInfo) error {
+	p.cleaned = append(p.cleaned, challenges...)
+
+	return p.cleanUpAll
+}
+
+type providerConcurrentMock struct {
+	providerMock
+
+	concurrent bool
+}
+
+func (p *providerConcurrentMock) Concurrent() bool { return p.concurrent }
+
+type providerPropagatedMock struct {
+	providerMock
+
+	// results of the successive calls to Propagated.
+	results []bool
+	err     error
+	calls   int
+}
+
+func (p *providerPropagatedMock) Propagated(_, _ string) (bool, error) {
+	p.calls++
+
+	if p.err != nil {
+		return false, p.err
+	}
+
+	return p.results[min(p.calls, len(p.results))-1], nil
+}
+
 func TestChallenge_PreSolve(t *testing.T) {
 	server := tester.MockACMEServer().BuildHTTPS(t)
 
@@ -114,6 +178,172 @@ func TestChallenge_PreSolve(t *testing.T) {
 	}
 }
 
+func TestChallenge_PreSolveAll(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authzs := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Wildcard:   true,
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "b"}},
+		},
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		provider := &providerBatchMock{}
+
+		ok, err := NewChallenge(core, nil, provider).PreSolveAll(t.Context(), authzs)
+		require.NoError(t, err)
+
+		assert.True(t, ok)
+
+		require.Len(t, provider.challenges, 2)
+		assert.Equal(t, "example.com", provider.challenges[0].Domain)
+		assert.Equal(t, "a", provider.challenges[0].Token)
+		assert.Equal(t, "b", provider.challenges[1].Token)
+	})
+
+	t.Run("batch error", func(t *testing.T) {
+		provider := &providerBatchMock{presentAll: errors.New("OOPS")}
+
+		ok, err := NewChallenge(core, nil, provider).PreSolveAll(t.Context(), authzs)
+		require.EqualError(t, err, "[example.com, *.example.com] acme: error presenting tokens: OOPS")
+
+		assert.True(t, ok)
+	})
+
+	t.Run("wrapped batch", func(t *testing.T) {
+		provider := &providerBatchMock{}
+
+		ok, err := NewChallenge(core, nil, NewZoneCoordinator(provider)).PreSolveAll(t.Context(), authzs)
+		require.NoError(t, err)
+
+		assert.True(t, ok)
+
+		require.Len(t, provider.challenges, 2)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		ok, err := NewChallenge(core, nil, &providerMock{}).PreSolveAll(t.Context(), authzs)
+		require.NoError(t, err)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("wrapped not supported", func(t *testing.T) {
+		ok, err := NewChallenge(core, nil, NewZoneCoordinator(&providerMock{})).PreSolveAll(t.Context(), authzs)
+		require.NoError(t, err)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestChallenge_CleanUpAll(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authzs := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Wildcard:   true,
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "b"}},
+		},
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		provider := &providerBatchMock{}
+
+		ok, err := NewChallenge(core, nil, provider).CleanUpAll(authzs)
+		require.NoError(t, err)
+
+		assert.True(t, ok)
+
+		require.Len(t, provider.cleaned, 2)
+		assert.Equal(t, "example.com", provider.cleaned[0].Domain)
+		assert.Equal(t, "a", provider.cleaned[0].Token)
+		assert.Equal(t, "b", provider.cleaned[1].Token)
+	})
+
+	t.Run("batch error", func(t *testing.T) {
+		provider := &providerBatchMock{cleanUpAll: errors.New("OOPS")}
+
+		ok, err := NewChallenge(core, nil, provider).CleanUpAll(authzs)
+		require.EqualError(t, err, "OOPS")
+
+		assert.True(t, ok)
+	})
+
+	t.Run("wrapped batch", func(t *testing.T) {
+		provider := &providerBatchMock{}
+
+		ok, err := NewChallenge(core, nil, NewZoneCoordinator(provider)).CleanUpAll(authzs)
+		require.NoError(t, err)
+
+		assert.True(t, ok)
+
+		require.Len(t, provider.cleaned, 2)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		ok, err := NewChallenge(core, nil, &providerMock{}).CleanUpAll(authzs)
+		require.NoError(t, err)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestChallenge_Concurrent(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		provider challenge.Provider
+		expected bool
+	}{
+		{
+			desc:     "not declared",
+			provider: &providerMock{},
+		},
+		{
+			desc:     "concurrent",
+			provider: &providerConcurrentMock{concurrent: true},
+			expected: true,
+		},
+		{
+			desc:     "not concurrent",
+			provider: &providerConcurrentMock{},
+		},
+		{
+			desc:     "wrapped",
+			provider: NewZoneCoordinator(&providerConcurrentMock{concurrent: true}),
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, NewChallenge(nil, nil, test.provider).Concurrent())
+		})
+	}
+}
+
 func TestChallenge_Solve(t *testing.T) {
 	useAsNameserver(t, dnsmock.NewServer().
 		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
@@ -206,6 +436,129 @@ func TestChallenge_Solve(t *testing.T) {
 	}
 }
 
+func TestChallenge_Solve_propagationHints(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
+		Build(t))
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &providerHintsMock{wait: 200 * time.Millisecond, nameservers: []string{"ns1.example.com"}}
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		provider,
+		SetPropagationTimeout(time.Second, time.Millisecond),
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return true, nil }),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Value: "example.com",
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.DNS01.String()},
+		},
+	}
+
+	start := time.Now()
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, time.Since(start), provider.wait)
+	assert.Equal(t, "_acme-challenge.example.com.", provider.fqdn)
+
+	// The hints are only used for the record.
+	assert.Empty(t, chlg.preCheck.authoritativeNss)
+}
+
+func TestChallenge_Solve_providerPropagated(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Value: "example.com",
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.DNS01.String()},
+		},
+	}
+
+	testCases := []struct {
+		desc           string
+		provider       *providerPropagatedMock
+		wrapped        bool
+		expectedChecks int
+		expectedCalls  int
+	}{
+		{
+			desc:          "propagated",
+			provider:      &providerPropagatedMock{results: []bool{true}},
+			expectedCalls: 1,
+		},
+		{
+			desc:           "propagated after the DNS checks",
+			provider:       &providerPropagatedMock{results: []bool{false, false, true}},
+			expectedChecks: 2,
+			expectedCalls:  3,
+		},
+		{
+			desc:           "provider error",
+			provider:       &providerPropagatedMock{err: errors.New("oops")},
+			expectedChecks: 1,
+			expectedCalls:  1,
+		},
+		{
+			desc:          "wrapped provider",
+			provider:      &providerPropagatedMock{results: []bool{true}},
+			wrapped:       true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var checks int
+
+			var provider challenge.Provider = test.provider
+			if test.wrapped {
+				provider = NewZoneCoordinator(test.provider)
+			}
+
+			chlg := NewChallenge(core,
+				func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+				provider,
+				SetPropagationTimeout(time.Second, time.Millisecond),
+				WithPropagationChecker(func(_, _ string) (bool, error) {
+					checks++
+
+					// The DNS check succeeds only if the provider fails.
+					return test.provider.err != nil, nil
+				}),
+			)
+
+			err = chlg.Solve(authz)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedChecks, checks)
+			assert.Equal(t, test.expectedCalls, test.provider.calls)
+		})
+	}
+}
+
 func TestChallenge_CleanUp(t *testing.T) {
 	server := tester.MockACMEServer().BuildHTTPS(t)
 
@@ -339,3 +692,30 @@ func TestGetChallengeInfo_CNAME_disabled(t *testing.T) {
 
 	assert.Equal(t, expected, info)
 }
+
+func TestGetChallengeInfo_IDN(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.xn--mnchen-3ya.example. CNAME", dnsmock.Noop).
+		Build(t))
+
+	info := GetChallengeInfo("münchen.example", "123")
+
+	expected := ChallengeInfo{
+		FQDN:          "_acme-challenge.xn--mnchen-3ya.example.",
+		EffectiveFQDN: "_acme-challenge.xn--mnchen-3ya.example.",
+		Value:         "123", // DigiCert fork: raw keyAuth (no SHA256/base64url)
+	}
+
+	assert.Equal(t, expected, info)
+}
+
+func TestSetPropagationTimeout(t *testing.T) {
+	chlg := NewChallenge(nil, nil, &providerTimeoutMock{timeout: time.Minute, interval: time.Second},
+		SetPropagationTimeout(10*time.Minute, 0))
+
+	assert.Equal(t, 10*time.Minute, chlg.propagationTimeout)
+	assert.Zero(t, chlg.pollingInterval)
+
+	err := SetPropagationTimeout(-1, 0)(chlg)
+	require.EqualError(t, err, "the propagation timeout and the polling interval cannot be negative")
+}
@@ -11,7 +11,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/digicert/lego/v4/log"
 	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
 )
 
 const defaultResolvConf = "/etc/resolv.conf"
@@ -24,7 +26,7 @@ var defaultNameservers = []string{
 }
 
 // recursiveNameservers are used to pre-check DNS propagation.
-var recursiveNameservers = getNameservers(defaultResolvConf, defaultNameservers)
+var recursiveNameservers = getSystemNameservers(defaultNameservers)
 
 // soaCacheEntry holds a cached SOA record (only selected fields).
 type soaCacheEntry struct {
@@ -79,6 +81,29 @@ func getNameservers(path string, defaults []string) []string {
 	return ParseNameservers(config.Servers)
 }
 
+// filterNameservers removes the unusable addresses (unspecified, deprecated site-local) and the duplicates.
+func filterNameservers(ips []net.IP) []string {
+	var servers []string
+
+	for _, ip := range ips {
+		if ip == nil || ip.IsUnspecified() {
+			continue
+		}
+
+		// Windows reports the deprecated site-local addresses (fec0:0:0:ffff::1, ::2, ::3)
+		// when no IPv6 DNS server is configured.
+		if ip.To4() == nil && ip[0] == 0xfe && ip[1]&0xc0 == 0xc0 {
+			continue
+		}
+
+		if !slices.Contains(servers, ip.String()) {
+			servers = append(servers, ip.String())
+		}
+	}
+
+	return servers
+}
+
 func ParseNameservers(servers []string) []string {
 	var resolvers []string
 
@@ -146,15 +171,45 @@ func FindZoneByFqdn(fqdn string) (string, error) {
 
 // FindZoneByFqdnCustom determines the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
+// The zones which are ICANN public suffixes (registry apexes, ex: `co.uk.`) are rejected,
+// a warning is logged for the other public suffixes (ex: `github.io.`).
 func FindZoneByFqdnCustom(fqdn string, nameservers []string) (string, error) {
 	soa, err := lookupSoaByFqdn(fqdn, nameservers)
 	if err != nil {
 		return "", fmt.Errorf("[fqdn=%s] %w", fqdn, err)
 	}
 
+	err = checkZone(fqdn, soa.zone)
+	if err != nil {
+		return "", fmt.Errorf("[fqdn=%s] %w", fqdn, err)
+	}
+
 	return soa.zone, nil
 }
 
+// checkZone checks that a zone is not a public suffix:
+// a zone too broad means that the zone of the domain was not found (ex: a missing delegation),
+// and the records cannot be created there.
+func checkZone(fqdn, zone string) error {
+	name := UnFqdn(zone)
+	if name == "" || name == UnFqdn(fqdn) {
+		return nil
+	}
+
+	suffix, icann := publicsuffix.PublicSuffix(name)
+	if suffix != name {
+		return nil
+	}
+
+	if icann {
+		return fmt.Errorf("the zone %s is a public suffix: the zone of the domain was not found", zone)
+	}
+
+	log.Warn("The zone is a public suffix, the zone of the domain was probably not found.", "fqdn", fqdn, "zone", zone)
+
+	return nil
+}
+
 func lookupSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
 	// Do we have it cached and is it still fresh?
 	entAny, ok := fqdnSoaCache.Load(fqdn)
@@ -2,6 +2,7 @@ package dns01
 
 import (
 	"errors"
+	"net"
 	"sort"
 	"testing"
 
@@ -128,6 +129,9 @@ type lookupSoaByFqdnTestCase struct {
 	primaryNs     string
 	nameservers   []string
 	expectedError string
+
+	// expectedZoneError the error of FindZoneByFqdnCustom when it's different from expectedError.
+	expectedZoneError string
 }
 
 func lookupSoaByFqdnTestCases(t *testing.T) []lookupSoaByFqdnTestCase {
@@ -173,6 +177,7 @@ func lookupSoaByFqdnTestCases(t *testing.T) []lookupSoaByFqdnTestCase {
 					Build(t).
 					String(),
 			},
+			expectedZoneError: "[fqdn=example.com.ac.] the zone ac. is a public suffix: the zone of the domain was not found",
 		},
 		{
 			desc:      "domain is a cross-zone CNAME",
@@ -240,7 +245,9 @@ func TestFindZoneByFqdnCustom(t *testing.T) {
 			ClearFqdnCache()
 
 			zone, err := FindZoneByFqdnCustom(test.fqdn, test.nameservers)
-			if test.expectedError != "" {
+			if test.expectedZoneError != "" {
+				require.EqualError(t, err, test.expectedZoneError)
+			} else if test.expectedError != "" {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, test.expectedError)
 			} else {
@@ -363,3 +370,58 @@ func TestDNSError_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterNameservers(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("192.168.1.1"),
+		net.IPv4(192, 168, 1, 1).To4(),
+		nil,
+		net.IPv4zero,
+		net.ParseIP("fec0:0:0:ffff::1"),
+		net.ParseIP("2001:4860:4860::8844"),
+		net.ParseIP("10.0.0.1"),
+	}
+
+	expected := []string{"192.168.1.1", "2001:4860:4860::8844", "10.0.0.1"}
+
+	assert.Equal(t, expected, filterNameservers(ips))
+}
+
+func Test_checkZone(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fqdn     string
+		zone     string
+		expected string
+	}{
+		{desc: "registrable domain", fqdn: "_acme-challenge.www.example.com.", zone: "example.com."},
+		{desc: "subdomain", fqdn: "_acme-challenge.www.example.com.", zone: "www.example.com."},
+		{desc: "private public suffix", fqdn: "_acme-challenge.example.github.io.", zone: "github.io."},
+		{desc: "the zone is the domain", fqdn: "co.uk.", zone: "co.uk."},
+		{
+			desc:     "TLD",
+			fqdn:     "_acme-challenge.example.com.",
+			zone:     "com.",
+			expected: "the zone com. is a public suffix: the zone of the domain was not found",
+		},
+		{
+			desc:     "eTLD",
+			fqdn:     "_acme-challenge.example.co.uk.",
+			zone:     "co.uk.",
+			expected: "the zone co.uk. is a public suffix: the zone of the domain was not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkZone(test.fqdn, test.zone)
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
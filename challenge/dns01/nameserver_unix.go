@@ -6,3 +6,8 @@ import "time"
 
 // dnsTimeout is used to override the default DNS timeout of 10 seconds.
 var dnsTimeout = 10 * time.Second
+
+// getSystemNameservers attempts to get systems nameservers (resolv.conf) before falling back to the defaults.
+func getSystemNameservers(defaults []string) []string {
+	return getNameservers(defaultResolvConf, defaults)
+}
@@ -2,7 +2,73 @@
 
 package dns01
 
-import "time"
+import (
+	"errors"
+	"net"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
 
 // dnsTimeout is used to override the default DNS timeout of 20 seconds.
 var dnsTimeout = 20 * time.Second
+
+// getSystemNameservers attempts to get systems nameservers before falling back to the defaults.
+// There is no resolv.conf on Windows: the nameservers are the DNS servers of the network adapters that are up.
+func getSystemNameservers(defaults []string) []string {
+	ips, err := adaptersDNSServers()
+	if err != nil {
+		return getNameservers(defaultResolvConf, defaults)
+	}
+
+	servers := filterNameservers(ips)
+	if len(servers) == 0 {
+		return getNameservers(defaultResolvConf, defaults)
+	}
+
+	return ParseNameservers(servers)
+}
+
+// adaptersDNSServers returns the DNS servers of the network adapters that are up (same approach as the Go resolver).
+func adaptersDNSServers() ([]net.IP, error) {
+	size := uint32(15000)
+
+	var buf []byte
+
+	for {
+		buf = make([]byte, size)
+
+		length := size
+
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC, windows.GAA_FLAG_INCLUDE_PREFIX, 0,
+			(*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])), &length)
+		if err == nil {
+			if length == 0 {
+				return nil, nil
+			}
+
+			break
+		}
+
+		if !errors.Is(err, windows.ERROR_BUFFER_OVERFLOW) || length <= size {
+			return nil, err
+		}
+
+		size = length
+	}
+
+	var ips []net.IP
+
+	for adapter := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])); adapter != nil; adapter = adapter.Next {
+		if adapter.OperStatus != windows.IfOperStatusUp {
+			continue
+		}
+
+		for server := adapter.FirstDnsServerAddress; server != nil; server = server.Next {
+			ips = append(ips, server.Address.IP())
+		}
+	}
+
+	return ips, nil
+}
@@ -1,6 +1,7 @@
 package dns01
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -28,6 +29,22 @@ func WrapPreCheck(wrap WrapPreCheckFunc) ChallengeOption {
 	}
 }
 
+// WithPropagationChecker replaces the propagation check (the DNS queries to the name servers) by a custom check.
+// The check is called until it returns true, an error, or the propagation timeout is reached.
+// It can be used behind a split-horizon DNS, or to rely on the status API of a DNS provider (ex: Route53 ChangeInfo).
+// The check also replaces the propagation check of the DNS provider, and it is wrapped by WrapPreCheck and PropagationWait.
+func WithPropagationChecker(check func(fqdn, value string) (bool, error)) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if check == nil {
+			return errors.New("the propagation checker is nil")
+		}
+
+		chlg.preCheck.customCheck = check
+
+		return nil
+	}
+}
+
 // DisableCompletePropagationRequirement obsolete.
 //
 // Deprecated: use DisableAuthoritativeNssPropagationRequirement instead.
@@ -70,6 +87,16 @@ type preCheck struct {
 
 	// require the TXT record to be propagated to all recursive name servers
 	requireRecursiveNssPropagation bool
+
+	// authoritative name servers provided by the DNS provider,
+	// used instead of looking up the NS records of the zone.
+	authoritativeNss []string
+
+	// propagation check provided by the DNS provider, used instead of the DNS queries.
+	providerCheck PreCheckFunc
+
+	// propagation check provided by the user, used instead of the DNS queries and the check of the DNS provider.
+	customCheck PreCheckFunc
 }
 
 func newPreCheck() preCheck {
@@ -79,11 +106,20 @@ func newPreCheck() preCheck {
 }
 
 func (p preCheck) call(domain, fqdn, value string) (bool, error) {
+	check := p.checkDNSPropagation
+	if p.providerCheck != nil {
+		check = p.providerCheck
+	}
+
+	if p.customCheck != nil {
+		check = p.customCheck
+	}
+
 	if p.checkFunc == nil {
-		return p.checkDNSPropagation(fqdn, value)
+		return check(fqdn, value)
 	}
 
-	return p.checkFunc(domain, fqdn, value, p.checkDNSPropagation)
+	return p.checkFunc(domain, fqdn, value, check)
 }
 
 // checkDNSPropagation checks if the expected TXT record has been propagated to all authoritative nameservers.
@@ -109,9 +145,12 @@ func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 		return true, nil
 	}
 
-	authoritativeNss, err := lookupNameservers(fqdn)
-	if err != nil {
-		return false, err
+	authoritativeNss := p.authoritativeNss
+	if len(authoritativeNss) == 0 {
+		authoritativeNss, err = lookupNameservers(fqdn)
+		if err != nil {
+			return false, err
+		}
 	}
 
 	found, err := checkNameserversPropagation(fqdn, value, authoritativeNss, true)
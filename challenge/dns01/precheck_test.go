@@ -79,6 +79,31 @@ func Test_preCheck_checkDNSPropagation(t *testing.T) {
 	}
 }
 
+func Test_preCheck_checkDNSPropagation_providerNameservers(t *testing.T) {
+	server := dnsmock.NewServer().
+		Query("example.com. TXT",
+			dnsmock.Answer(
+				fakeTXT("example.com.", "one"),
+				fakeTXT("example.com.", "two"),
+			),
+		).
+		Build(t)
+
+	mockResolver(t, server)
+
+	// The name server doesn't answer to SOA and NS queries:
+	// the check must only rely on the name servers provided by the DNS provider.
+	useAsNameserver(t, server)
+
+	check := newPreCheck()
+	check.authoritativeNss = []string{"127.0.0.1"}
+
+	ok, err := check.checkDNSPropagation("example.com.", "two")
+	require.NoError(t, err)
+
+	assert.True(t, ok)
+}
+
 func Test_checkNameserversPropagation_authoritativeNss(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -143,3 +168,53 @@ func Test_checkNameserversPropagation_authoritativeNss(t *testing.T) {
 		})
 	}
 }
+
+type providerCheckMock struct {
+	providerMock
+
+	fqdn, value string
+}
+
+func (p *providerCheckMock) PropagationCheck() PreCheckFunc {
+	return func(fqdn, value string) (bool, error) {
+		p.fqdn, p.value = fqdn, value
+
+		return true, nil
+	}
+}
+
+func Test_preCheck_call_providerCheck(t *testing.T) {
+	provider := &providerCheckMock{}
+
+	chlg := NewChallenge(nil, nil, provider)
+
+	// No DNS server is configured: the check must only rely on the DNS provider.
+	ok, err := chlg.preCheck.call("example.com", "_acme-challenge.example.com.", "value")
+	require.NoError(t, err)
+
+	assert.True(t, ok)
+	assert.Equal(t, "_acme-challenge.example.com.", provider.fqdn)
+	assert.Equal(t, "value", provider.value)
+}
+
+func Test_preCheck_call_customCheck(t *testing.T) {
+	provider := &providerCheckMock{}
+
+	var fqdn, value string
+
+	chlg := NewChallenge(nil, nil, provider, WithPropagationChecker(func(f, v string) (bool, error) {
+		fqdn, value = f, v
+
+		return true, nil
+	}))
+
+	ok, err := chlg.preCheck.call("example.com", "_acme-challenge.example.com.", "value")
+	require.NoError(t, err)
+
+	assert.True(t, ok)
+	assert.Equal(t, "_acme-challenge.example.com.", fqdn)
+	assert.Equal(t, "value", value)
+
+	// The custom check replaces the check of the DNS provider.
+	assert.Empty(t, provider.fqdn)
+}
@@ -0,0 +1,161 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/digicert/lego/v4/platform/wait"
+)
+
+// DefaultProviderRetryDelay default delay before the first retry of a DNS provider call.
+const DefaultProviderRetryDelay = 2 * time.Second
+
+// providerRetry defines the retries of the calls to the DNS provider.
+type providerRetry struct {
+	attempts int
+	delay    time.Duration
+}
+
+// WithProviderRetry retries the calls to the DNS provider (Present, PresentAll, CleanUp) failing with a retryable error
+// (see IsRetryableError), up to `attempts` calls in total.
+// The delay before the first retry is `delay` (DefaultProviderRetryDelay if zero), and it's doubled for each following retry.
+// The retries are independent of the propagation wait: they only concern the calls to the API of the provider.
+func WithProviderRetry(attempts int, delay time.Duration) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if attempts < 1 {
+			return errors.New("the number of attempts must be at least 1")
+		}
+
+		if delay < 0 {
+			return errors.New("the retry delay cannot be negative")
+		}
+
+		if delay == 0 {
+			delay = DefaultProviderRetryDelay
+		}
+
+		chlg.providerRetry = providerRetry{attempts: attempts, delay: delay}
+
+		return nil
+	}
+}
+
+// NewRetryableError marks an error of a DNS provider as retryable:
+// the call failed without side effect (ex: the request was rejected before its processing), and it can be repeated.
+func NewRetryableError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &retryableError{err: err}
+}
+
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryableError) Retryable() bool {
+	return true
+}
+
+// retryable is implemented by the errors which know if the failed call can be repeated (ex: NewRetryableError).
+type retryable interface {
+	Retryable() bool
+}
+
+// idempotentWriter is implemented by the DNS providers whose calls can be repeated without side effect
+// (ex: the record is upserted, so a repeated Present doesn't create a duplicate record).
+type idempotentWriter interface {
+	IdempotentWrites() bool
+}
+
+// statusCoder is implemented by the errors of the API clients of the DNS providers which know the HTTP status code of the response.
+type statusCoder interface {
+	HTTPStatusCode() int
+}
+
+// IsTransientError returns true if the error of a DNS provider is likely to be transient:
+// a network error (ex: timeout, connection reset), or a server error (HTTP 5xx) of the API.
+//
+// A transient error doesn't mean that the call can be repeated:
+// the server may have processed the request before the failure (see IsRetryableError).
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.HTTPStatusCode() >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// IsRetryableError returns true if a call to a DNS provider, failing with the error, can be repeated:
+//   - the error is marked as retryable (ex: NewRetryableError).
+//   - the connection to the API failed, so the request was not sent.
+//   - the error is transient (see IsTransientError), and the calls of the provider are idempotent.
+//
+// A call of a provider which is not idempotent (ex: the creation of a record) is never repeated after a server error or a timeout:
+// the record may have been created, and a repeated call can create a duplicate record.
+func IsRetryableError(err error, idempotent bool) bool {
+	if err == nil {
+		return false
+	}
+
+	var r retryable
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return idempotent && IsTransientError(err)
+}
+
+// callProvider calls the DNS provider, and retries the call on retryable errors.
+// The wait before a retry is interrupted as soon as the context is done.
+func (c *Challenge) callProvider(ctx context.Context, domain, action string, call func() error) error {
+	err := call()
+
+	p, ok := unwrapProvider(c.provider).(idempotentWriter)
+	idempotent := ok && p.IdempotentWrites()
+
+	delay := c.providerRetry.delay
+
+	for attempt := 2; attempt <= c.providerRetry.attempts && IsRetryableError(err, idempotent); attempt++ {
+		c.core.Logger().Warn("acme: retryable error of the DNS provider, retrying.",
+			"domain", domain, "action", action, "attempt", attempt, "delay", delay, "error", err)
+
+		errW := wait.Sleep(ctx, delay)
+		if errW != nil {
+			return errors.Join(err, errW)
+		}
+
+		delay *= 2
+
+		err = call()
+	}
+
+	return err
+}
@@ -0,0 +1,317 @@
+package dns01
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statusCodeError struct {
+	statusCode int
+}
+
+func (e statusCodeError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.statusCode)
+}
+
+func (e statusCodeError) HTTPStatusCode() int {
+	return e.statusCode
+}
+
+// providerFlakyMock fails with the errors, then succeeds.
+type providerFlakyMock struct {
+	errs []error
+
+	presentCalls int
+	cleanUpCalls int
+}
+
+func (p *providerFlakyMock) Present(_, _, _ string) error {
+	p.presentCalls++
+
+	return p.next(p.presentCalls)
+}
+
+func (p *providerFlakyMock) CleanUp(_, _, _ string) error {
+	p.cleanUpCalls++
+
+	return p.next(p.cleanUpCalls)
+}
+
+// providerIdempotentMock is a providerFlakyMock declaring idempotent writes.
+type providerIdempotentMock struct {
+	*providerFlakyMock
+}
+
+func (p *providerIdempotentMock) IdempotentWrites() bool {
+	return true
+}
+
+func (p *providerFlakyMock) next(calls int) error {
+	if calls > len(p.errs) {
+		return nil
+	}
+
+	return p.errs[calls-1]
+}
+
+func TestIsTransientError(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		err    error
+		assert assert.BoolAssertionFunc
+	}{
+		{
+			desc:   "nil",
+			assert: assert.False,
+		},
+		{
+			desc: "network error",
+			err: fmt.Errorf("foo: %w", &url.Error{
+				Op:  "Post",
+				URL: "https://api.example.com",
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			}),
+			assert: assert.True,
+		},
+		{
+			desc:   "timeout",
+			err:    fmt.Errorf("foo: %w", context.DeadlineExceeded),
+			assert: assert.True,
+		},
+		{
+			desc:   "server error",
+			err:    fmt.Errorf("foo: %w", statusCodeError{statusCode: 503}),
+			assert: assert.True,
+		},
+		{
+			desc:   "client error",
+			err:    fmt.Errorf("foo: %w", statusCodeError{statusCode: 401}),
+			assert: assert.False,
+		},
+		{
+			desc:   "other error",
+			err:    errors.New("zone not found"),
+			assert: assert.False,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.assert(t, IsTransientError(test.err))
+		})
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		err        error
+		idempotent bool
+		assert     assert.BoolAssertionFunc
+	}{
+		{
+			desc:   "nil",
+			assert: assert.False,
+		},
+		{
+			desc:   "marked as retryable",
+			err:    fmt.Errorf("foo: %w", NewRetryableError(errors.New("rate limited"))),
+			assert: assert.True,
+		},
+		{
+			desc: "connection error",
+			err: fmt.Errorf("foo: %w", &url.Error{
+				Op:  "Post",
+				URL: "https://api.example.com",
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			}),
+			assert: assert.True,
+		},
+		{
+			desc: "network error after the connection",
+			err: fmt.Errorf("foo: %w", &url.Error{
+				Op:  "Post",
+				URL: "https://api.example.com",
+				Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")},
+			}),
+			assert: assert.False,
+		},
+		{
+			desc:   "server error",
+			err:    fmt.Errorf("foo: %w", statusCodeError{statusCode: 503}),
+			assert: assert.False,
+		},
+		{
+			desc:       "server error of an idempotent provider",
+			err:        fmt.Errorf("foo: %w", statusCodeError{statusCode: 503}),
+			idempotent: true,
+			assert:     assert.True,
+		},
+		{
+			desc:       "client error of an idempotent provider",
+			err:        fmt.Errorf("foo: %w", statusCodeError{statusCode: 401}),
+			idempotent: true,
+			assert:     assert.False,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.assert(t, IsRetryableError(test.err, test.idempotent))
+		})
+	}
+}
+
+func TestWithProviderRetry_errors(t *testing.T) {
+	err := WithProviderRetry(0, time.Second)(&Challenge{})
+	require.EqualError(t, err, "the number of attempts must be at least 1")
+
+	err = WithProviderRetry(3, -time.Second)(&Challenge{})
+	require.EqualError(t, err, "the retry delay cannot be negative")
+
+	chlg := &Challenge{}
+
+	err = WithProviderRetry(3, 0)(chlg)
+	require.NoError(t, err)
+
+	assert.Equal(t, providerRetry{attempts: 3, delay: DefaultProviderRetryDelay}, chlg.providerRetry)
+}
+
+func TestChallenge_PreSolve_providerRetry(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		attempts      int
+		idempotent    bool
+		errs          []error
+		expectedCalls int
+		requireErr    require.ErrorAssertionFunc
+	}{
+		{
+			desc:          "no retry",
+			attempts:      1,
+			idempotent:    true,
+			errs:          []error{statusCodeError{statusCode: 502}},
+			expectedCalls: 1,
+			requireErr:    require.Error,
+		},
+		{
+			desc:          "transient errors of an idempotent provider",
+			attempts:      3,
+			idempotent:    true,
+			errs:          []error{statusCodeError{statusCode: 502}, statusCodeError{statusCode: 500}},
+			expectedCalls: 3,
+			requireErr:    require.NoError,
+		},
+		{
+			desc:          "transient errors of a non-idempotent provider",
+			attempts:      3,
+			errs:          []error{statusCodeError{statusCode: 502}},
+			expectedCalls: 1,
+			requireErr:    require.Error,
+		},
+		{
+			desc:          "retryable errors of a non-idempotent provider",
+			attempts:      3,
+			errs:          []error{NewRetryableError(statusCodeError{statusCode: 503}), NewRetryableError(errors.New("rate limited"))},
+			expectedCalls: 3,
+			requireErr:    require.NoError,
+		},
+		{
+			desc:          "too many transient errors",
+			attempts:      2,
+			idempotent:    true,
+			errs:          []error{statusCodeError{statusCode: 502}, statusCodeError{statusCode: 500}},
+			expectedCalls: 2,
+			requireErr:    require.Error,
+		},
+		{
+			desc:          "permanent error",
+			attempts:      3,
+			idempotent:    true,
+			errs:          []error{statusCodeError{statusCode: 403}},
+			expectedCalls: 1,
+			requireErr:    require.Error,
+		},
+	}
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			flaky := &providerFlakyMock{errs: test.errs}
+
+			var provider challenge.Provider = flaky
+			if test.idempotent {
+				provider = &providerIdempotentMock{providerFlakyMock: flaky}
+			}
+
+			chlg := NewChallenge(core, nil, provider, WithProviderRetry(test.attempts, time.Millisecond))
+
+			err := chlg.PreSolve(authz)
+			test.requireErr(t, err)
+
+			assert.Equal(t, test.expectedCalls, flaky.presentCalls)
+
+			err = chlg.CleanUp(authz)
+			test.requireErr(t, err)
+
+			assert.Equal(t, test.expectedCalls, flaky.cleanUpCalls)
+		})
+	}
+}
+
+func TestChallenge_PreSolveWithContext_providerRetry_canceled(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+	}
+
+	provider := &providerFlakyMock{errs: []error{NewRetryableError(errors.New("rate limited"))}}
+
+	chlg := NewChallenge(core, nil, provider, WithProviderRetry(3, time.Hour))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err = chlg.PreSolveWithContext(ctx, authz)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, 1, provider.presentCalls)
+}
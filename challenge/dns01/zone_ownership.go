@@ -0,0 +1,86 @@
+package dns01
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/digicert/lego/v4/challenge"
+)
+
+// ErrZoneNameserversUnsupported is returned by CheckZoneOwnership when the DNS provider doesn't report the name servers of its zones.
+var ErrZoneNameserversUnsupported = errors.New("the DNS provider doesn't report the name servers of its zones")
+
+// zoneNameservers is implemented by providers that report the name servers of the zones they manage.
+type zoneNameservers interface {
+	ZoneNameservers(zone string) ([]string, error)
+}
+
+// CheckZoneOwnership verifies that the DNS provider serves the zone of the challenge record of each domain:
+// the NS records of the zone must contain at least one of the name servers reported by the provider.
+// It catches the configuration mistakes (ex: wrong account, wrong zone) before the creation of the orders.
+//
+// It returns ErrZoneNameserversUnsupported if the provider doesn't report its name servers
+// (see the optional methods `ZoneNameservers(zone string) ([]string, error)` and `AuthoritativeNameservers() []string`).
+func CheckZoneOwnership(provider challenge.Provider, domains ...string) error {
+	provider = unwrapProvider(provider)
+
+	switch provider.(type) {
+	case zoneNameservers, authoritativeNameservers:
+	default:
+		return ErrZoneNameserversUnsupported
+	}
+
+	var errs []error
+
+	for _, domain := range domains {
+		err := checkZoneOwnership(provider, domain)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[%s] %w", domain, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func checkZoneOwnership(provider challenge.Provider, domain string) error {
+	info := GetChallengeInfo(strings.TrimPrefix(domain, "*."), "")
+
+	zone, err := FindZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("could not find zone: %w", err)
+	}
+
+	var expected []string
+
+	switch p := provider.(type) {
+	case zoneNameservers:
+		expected, err = p.ZoneNameservers(zone)
+		if err != nil {
+			return fmt.Errorf("[zone=%s] could not get the name servers from the DNS provider: %w", zone, err)
+		}
+
+	case authoritativeNameservers:
+		expected = p.AuthoritativeNameservers()
+	}
+
+	if len(expected) == 0 {
+		// The provider doesn't report the name servers of this zone.
+		return nil
+	}
+
+	actual, err := lookupNameservers(info.EffectiveFQDN)
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range expected {
+		if slices.Contains(actual, strings.ToLower(ToFqdn(ns))) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("[zone=%s] the zone is served by %s, not by the name servers of the DNS provider (%s): check the account and the zone used by the DNS provider",
+		zone, strings.Join(actual, ", "), strings.Join(expected, ", "))
+}
@@ -0,0 +1,80 @@
+package dns01
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/dnsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerZoneNameserversMock struct {
+	providerMock
+
+	nameservers map[string][]string
+	err         error
+}
+
+func (p *providerZoneNameserversMock) ZoneNameservers(zone string) ([]string, error) {
+	return p.nameservers[zone], p.err
+}
+
+func TestCheckZoneOwnership(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
+		Query("_acme-challenge.example.com. SOA", dnsmock.Noop).
+		Query("example.com. SOA", dnsmock.SOA("")).
+		Query("example.com. NS",
+			dnsmock.Answer(
+				fakeNS("example.com.", "ns1.example.net."),
+				fakeNS("example.com.", "ns2.example.net."),
+			),
+		).
+		Build(t))
+
+	testCases := []struct {
+		desc     string
+		provider *providerZoneNameserversMock
+		expected string
+	}{
+		{
+			desc: "match",
+			provider: &providerZoneNameserversMock{
+				nameservers: map[string][]string{"example.com.": {"NS2.example.net"}},
+			},
+		},
+		{
+			desc: "mismatch",
+			provider: &providerZoneNameserversMock{
+				nameservers: map[string][]string{"example.com.": {"ns1.example.org."}},
+			},
+			expected: "[example.com] [zone=example.com.] the zone is served by ns1.example.net., ns2.example.net., not by the name servers of the DNS provider (ns1.example.org.): check the account and the zone used by the DNS provider",
+		},
+		{
+			desc:     "no name servers for the zone",
+			provider: &providerZoneNameserversMock{},
+		},
+		{
+			desc:     "provider error",
+			provider: &providerZoneNameserversMock{err: errors.New("zone not found")},
+			expected: "[example.com] [zone=example.com.] could not get the name servers from the DNS provider: zone not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := CheckZoneOwnership(test.provider, "example.com", "*.example.com")
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestCheckZoneOwnership_unsupported(t *testing.T) {
+	err := CheckZoneOwnership(&providerMock{}, "example.com")
+	assert.ErrorIs(t, err, ErrZoneNameserversUnsupported)
+}
@@ -1,19 +1,33 @@
 package http01
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
 	"github.com/digicert/lego/v4/challenge"
-	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/platform/wait"
 )
 
 type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
 
+// ValidateContextFunc is like ValidateFunc, but the validation is interrupted as soon as the context is done.
+type ValidateContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
 type ChallengeOption func(*Challenge) error
 
+// SetValidateContext sets the validation function used by SolveWithContext.
+// Without it, the context is only checked before the validation.
+func SetValidateContext(validate ValidateContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
 // SetDelay sets a delay between the start of the HTTP server and the challenge validation.
 func SetDelay(delay time.Duration) ChallengeOption {
 	return func(chlg *Challenge) error {
@@ -28,10 +42,11 @@ func ChallengePath(token string) string {
 }
 
 type Challenge struct {
-	core     *api.Core
-	validate ValidateFunc
-	provider challenge.Provider
-	delay    time.Duration
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateContextFunc
+	provider    challenge.Provider
+	delay       time.Duration
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
@@ -44,7 +59,7 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 	for _, opt := range opts {
 		err := opt(chlg)
 		if err != nil {
-			log.Infof("challenge option error: %v", err)
+			chlg.core.Logger().Warn("challenge option error.", "error", err)
 		}
 	}
 
@@ -55,9 +70,23 @@ func (c *Challenge) SetProvider(provider challenge.Provider) {
 	c.provider = provider
 }
 
+// Concurrent reports whether the challenges of several authorizations can be solved concurrently:
+// the built-in server (ProviderServer) serves one token at a time.
+func (c *Challenge) Concurrent() bool {
+	_, ok := c.provider.(*ProviderServer)
+
+	return !ok
+}
+
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext is like Solve, but the delay and the validation are interrupted as soon as the context is done.
+// The token is always cleaned up.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
-	log.Infof("[%s] acme: Trying to solve HTTP-01", domain)
+	c.core.Logger().Info("acme: Trying to solve HTTP-01.", "domain", domain)
 
 	chlng, err := challenge.FindChallenge(challenge.HTTP01, authz)
 	if err != nil {
@@ -75,18 +104,36 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
 	}
 
+	c.core.Events().Publish(events.ChallengePresented{Domain: domain, Type: string(challenge.HTTP01)})
+
 	defer func() {
 		err := c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
 		if err != nil {
-			log.Warnf("[%s] acme: cleaning up failed: %v", domain, err)
+			c.core.Logger().Warn("acme: cleaning up failed.", "domain", domain, "error", err)
+			c.core.Events().Publish(events.CleanupFailed{Domain: domain, Type: string(challenge.HTTP01), Err: err})
 		}
 	}()
 
 	if c.delay > 0 {
-		time.Sleep(c.delay)
+		err = wait.Sleep(ctx, c.delay)
+		if err != nil {
+			return fmt.Errorf("[%s] acme: delay: %w", domain, err)
+		}
 	}
 
 	chlng.KeyAuthorization = keyAuth
 
+	return c.validateWithContext(ctx, domain, chlng)
+}
+
+func (c *Challenge) validateWithContext(ctx context.Context, domain string, chlng acme.Challenge) error {
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("[%s] acme: validation: %w", domain, context.Cause(ctx))
+	}
+
 	return c.validate(c.core, domain, chlng)
 }
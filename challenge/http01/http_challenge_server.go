@@ -1,6 +1,7 @@
 package http01
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"net"
@@ -21,9 +22,43 @@ type ProviderServer struct {
 
 	socketMode fs.FileMode
 
-	matcher  domainMatcher
-	done     chan bool
+	// extra the additional addresses, see AddListener.
+	extra []listenAddress
+
+	matcher   domainMatcher
+	listeners []*challengeListener
+	onReady   func(ListenerStatus)
+}
+
+type listenAddress struct {
+	network string
+	address string
+}
+
+type challengeListener struct {
 	listener net.Listener
+	done     chan bool
+}
+
+// ListenerStatus is the state of a listener of the ProviderServer, reported when the server starts.
+type ListenerStatus struct {
+	// Network the network of the listener (ex: tcp, tcp4, tcp6, unix).
+	Network string
+
+	// Address the requested address.
+	Address string
+
+	// BoundAddress the address really listened (ex: the port chosen by the system for the port 0).
+	// Empty if the listener failed.
+	BoundAddress string
+
+	// Err the error of the listener, if any.
+	Err error
+}
+
+// Ready reports whether the listener accepts connections.
+func (l ListenerStatus) Ready() bool {
+	return l.Err == nil
 }
 
 // NewProviderServer creates a new ProviderServer on the selected interface and port.
@@ -41,41 +76,84 @@ func NewUnixProviderServer(socketPath string, mode fs.FileMode) *ProviderServer
 	return &ProviderServer{network: "unix", address: socketPath, socketMode: mode, matcher: &hostMatcher{}}
 }
 
+// AddListener adds an address listened by the server, in addition to the main address.
+// It allows to listen on IPv4 and IPv6 separately (tcp4, tcp6),
+// or on an alternate port receiving the requests redirected by a NAT.
+func (s *ProviderServer) AddListener(network, address string) {
+	s.extra = append(s.extra, listenAddress{network: network, address: address})
+}
+
+// SetReadyHook defines a function called with the state of each listener when the server starts.
+func (s *ProviderServer) SetReadyHook(fn func(ListenerStatus)) {
+	s.onReady = fn
+}
+
 // Present starts a web server and makes the token available at `ChallengePath(token)` for web requests.
+// The server starts if at least one of its listeners is ready.
 func (s *ProviderServer) Present(domain, token, keyAuth string) error {
-	var err error
+	addresses := append([]listenAddress{{network: s.network, address: s.GetAddress()}}, s.extra...)
 
-	s.listener, err = net.Listen(s.network, s.GetAddress())
-	if err != nil {
-		return fmt.Errorf("could not start HTTP server for challenge: %w", err)
-	}
+	var errs []error
 
-	if s.network == "unix" {
-		if err = os.Chmod(s.address, s.socketMode); err != nil {
-			return fmt.Errorf("chmod %s: %w", s.address, err)
+	for _, addr := range addresses {
+		l, err := s.listen(addr)
+
+		status := ListenerStatus{Network: addr.network, Address: addr.address, Err: err}
+
+		if err != nil {
+			errs = append(errs, err)
+
+			log.Warn("The HTTP-01 challenge server could not listen.", "network", addr.network, "address", addr.address, "error", err)
+		} else {
+			status.BoundAddress = l.listener.Addr().String()
+
+			s.listeners = append(s.listeners, l)
+
+			go s.serve(l, domain, token, keyAuth)
 		}
-	}
 
-	s.done = make(chan bool)
+		if s.onReady != nil {
+			s.onReady(status)
+		}
+	}
 
-	go s.serve(domain, token, keyAuth)
+	if len(s.listeners) == 0 {
+		return fmt.Errorf("could not start HTTP server for challenge: %w", errors.Join(errs...))
+	}
 
 	return nil
 }
 
+func (s *ProviderServer) listen(addr listenAddress) (*challengeListener, error) {
+	listener, err := net.Listen(addr.network, addr.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if addr.network == "unix" {
+		if err = os.Chmod(addr.address, s.socketMode); err != nil {
+			_ = listener.Close()
+
+			return nil, fmt.Errorf("chmod %s: %w", addr.address, err)
+		}
+	}
+
+	return &challengeListener{listener: listener, done: make(chan bool)}, nil
+}
+
 func (s *ProviderServer) GetAddress() string {
 	return s.address
 }
 
 // CleanUp closes the HTTP server and removes the token from `ChallengePath(token)`.
 func (s *ProviderServer) CleanUp(domain, token, keyAuth string) error {
-	if s.listener == nil {
-		return nil
-	}
+	for _, l := range s.listeners {
+		l.listener.Close()
 
-	s.listener.Close()
+		<-l.done
+	}
 
-	<-s.done
+	s.listeners = nil
 
 	return nil
 }
@@ -103,7 +181,7 @@ func (s *ProviderServer) SetProxyHeader(headerName string) {
 	}
 }
 
-func (s *ProviderServer) serve(domain, token, keyAuth string) {
+func (s *ProviderServer) serve(l *challengeListener, domain, token, keyAuth string) {
 	path := ChallengePath(token)
 
 	// The incoming request will be validated to prevent DNS rebind attacks.
@@ -120,12 +198,13 @@ func (s *ProviderServer) serve(domain, token, keyAuth string) {
 				return
 			}
 
-			log.Infof("[%s] Served key authentication", domain)
+			log.Info("Served key authentication.", "domain", domain)
 
 			return
 		}
 
-		log.Warnf("Received request for domain %s with method %s but the domain did not match any challenge. Please ensure you are passing the %s header properly.", r.Host, r.Method, s.matcher.name())
+		log.Warn("Received request for a domain that did not match any challenge. Please ensure you are passing the header properly.",
+			"domain", r.Host, "method", r.Method, "header", s.matcher.name())
 
 		_, err := w.Write([]byte("TEST"))
 		if err != nil {
@@ -140,10 +219,10 @@ func (s *ProviderServer) serve(domain, token, keyAuth string) {
 	// we don't want any lingering connections, so disable KeepAlives.
 	httpServer.SetKeepAlivesEnabled(false)
 
-	err := httpServer.Serve(s.listener)
+	err := httpServer.Serve(l.listener)
 	if err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
-		log.Println(err)
+		log.Error("HTTP-01 challenge server error.", "error", err)
 	}
 
-	s.done <- true
+	l.done <- true
 }
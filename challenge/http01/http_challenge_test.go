@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -14,6 +15,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
@@ -217,6 +219,97 @@ func TestChallengeInvalidPort(t *testing.T) {
 	assert.Contains(t, err.Error(), "123456")
 }
 
+func TestProviderServer_AddListener(t *testing.T) {
+	providerServer := NewProviderServer("127.0.0.1", "0")
+	providerServer.AddListener("tcp4", "127.0.0.1:0")
+	providerServer.AddListener("tcp", "127.0.0.1:123456")
+
+	var statuses []ListenerStatus
+
+	providerServer.SetReadyHook(func(status ListenerStatus) {
+		statuses = append(statuses, status)
+	})
+
+	err := providerServer.Present("localhost", "token", "keyAuth")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = providerServer.CleanUp("localhost", "token", "keyAuth") })
+
+	require.Len(t, statuses, 3)
+
+	for _, status := range statuses[:2] {
+		require.True(t, status.Ready())
+
+		req, err := http.NewRequest(http.MethodGet, "http://"+status.BoundAddress+ChallengePath("token"), http.NoBody)
+		require.NoError(t, err)
+
+		req.Host = "localhost"
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		require.NoError(t, err)
+		assert.Equal(t, "keyAuth", string(body))
+	}
+
+	assert.False(t, statuses[2].Ready())
+	assert.Equal(t, "127.0.0.1:123456", statuses[2].Address)
+	assert.Empty(t, statuses[2].BoundAddress)
+}
+
+func TestChallenge_SolveWithContext_canceled(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	validate := func(_ *api.Core, _ string, _ acme.Challenge) error {
+		return errors.New("unexpected validation")
+	}
+
+	provider := &providerMock{}
+
+	solver := NewChallenge(core, validate, provider, SetDelay(time.Minute))
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{
+			{Type: challenge.HTTP01.String(), Token: "http"},
+		},
+	}
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	err = solver.SolveWithContext(ctx, authz)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, 1, provider.present)
+	assert.Equal(t, 1, provider.cleanUp)
+}
+
+type providerMock struct {
+	present int
+	cleanUp int
+}
+
+func (p *providerMock) Present(_, _, _ string) error {
+	p.present++
+	return nil
+}
+
+func (p *providerMock) CleanUp(_, _, _ string) error {
+	p.cleanUp++
+	return nil
+}
+
 type testProxyHeader struct {
 	name   string
 	values []string
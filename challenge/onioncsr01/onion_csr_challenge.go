@@ -0,0 +1,101 @@
+// Package onioncsr01 implements the onion-csr-01 challenge, for the .onion names of the Tor hidden services.
+package onioncsr01
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/challenge"
+)
+
+type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
+
+// ValidateContextFunc is like ValidateFunc, but the validation is interrupted as soon as the context is done.
+type ValidateContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
+type ChallengeOption func(*Challenge) error
+
+// SetValidateContext sets the validation function used by SolveWithContext.
+// Without it, the context is only checked before the validation.
+func SetValidateContext(validate ValidateContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
+// Challenge solves the onion-csr-01 challenge: the CA nonce is signed, inside a CSR, by the key of the hidden service.
+// There is nothing to present, the hidden service doesn't need to be reachable.
+type Challenge struct {
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateContextFunc
+	key         crypto.Signer
+}
+
+// NewChallenge creates a solver for the onion-csr-01 challenge, key is the Ed25519 key of the hidden service.
+func NewChallenge(core *api.Core, validate ValidateFunc, key crypto.Signer, opts ...ChallengeOption) *Challenge {
+	chlg := &Challenge{
+		core:     core,
+		validate: validate,
+		key:      key,
+	}
+
+	for _, opt := range opts {
+		err := opt(chlg)
+		if err != nil {
+			chlg.core.Logger().Warn("challenge option error.", "error", err)
+		}
+	}
+
+	return chlg
+}
+
+// Solve creates the CSR and sends it to the server.
+func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext is like Solve, but the validation is interrupted as soon as the context is done.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
+	domain := challenge.GetTargetedDomain(authz)
+	c.core.Logger().Info("acme: Trying to solve ONION-CSR-01.", "domain", domain)
+
+	chlng, err := challenge.FindChallenge(challenge.OnionCSR01, authz)
+	if err != nil {
+		return err
+	}
+
+	// The nonce may include padding characters.
+	nonce, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(chlng.Nonce, "="))
+	if err != nil {
+		return fmt.Errorf("[%s] acme: invalid nonce: %w", domain, err)
+	}
+
+	csr, err := certcrypto.GenerateOnionCSR(c.key, []string{domain}, nonce)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: error creating the CSR: %w", domain, err)
+	}
+
+	chlng.Response = acme.OnionCSRResponse{CSR: base64.RawURLEncoding.EncodeToString(csr)}
+
+	return c.validateWithContext(ctx, authz.Identifier.Value, chlng)
+}
+
+func (c *Challenge) validateWithContext(ctx context.Context, domain string, chlng acme.Challenge) error {
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("[%s] acme: validation: %w", domain, context.Cause(ctx))
+	}
+
+	return c.validate(c.core, domain, chlng)
+}
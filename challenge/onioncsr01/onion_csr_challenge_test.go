@@ -0,0 +1,102 @@
+package onioncsr01
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallenge(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	publicKey, onionKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	domain := certcrypto.OnionAddress(publicKey)
+
+	var called bool
+
+	mockValidate := func(_ *api.Core, _ string, chlng acme.Challenge) error {
+		called = true
+
+		response, ok := chlng.Response.(acme.OnionCSRResponse)
+		require.True(t, ok)
+
+		raw, err := base64.RawURLEncoding.DecodeString(response.CSR)
+		require.NoError(t, err)
+
+		csr, err := x509.ParseCertificateRequest(raw)
+		require.NoError(t, err)
+
+		require.NoError(t, csr.CheckSignature())
+
+		assert.Equal(t, []string{"*." + domain}, csr.DNSNames)
+		assert.Equal(t, publicKey, csr.PublicKey)
+
+		return nil
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	solver := NewChallenge(core, mockValidate, onionKey)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Type:  "dns",
+			Value: domain,
+		},
+		Wildcard: true,
+		Challenges: []acme.Challenge{
+			// The nonce includes the padding characters.
+			{Type: challenge.OnionCSR01.String(), Nonce: "MDEyMzQ1Njc4OWFiY2RlZg=="},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.NoError(t, err)
+
+	assert.True(t, called)
+}
+
+func TestChallenge_otherAddress(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	_, onionKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	solver := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }, onionKey)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Type:  "dns",
+			Value: "example.onion",
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.OnionCSR01.String(), Nonce: "MDEyMzQ1Njc4OWFiY2RlZg"},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.ErrorContains(t, err, "[example.onion] acme: error creating the CSR: example.onion doesn't match the address of the hidden service key")
+}
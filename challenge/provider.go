@@ -6,6 +6,9 @@ import "time"
 // provider. Present presents the solution to a challenge available to
 // be solved. CleanUp will be called by the challenge if Present ends
 // in a non-error state.
+//
+// A Provider is shared by the challenges of a client:
+// Present and CleanUp are called concurrently only if the Provider implements ProviderConcurrent.
 type Provider interface {
 	Present(domain, token, keyAuth string) error
 	CleanUp(domain, token, keyAuth string) error
@@ -26,3 +29,41 @@ type ProviderTimeout interface {
 	Provider
 	Timeout() (timeout, interval time.Duration)
 }
+
+// ProviderBatch allows for implementing a Provider able to present and clean up several challenges at once
+// (ex: the challenges of `example.com` and `*.example.com` are two TXT records with the same name),
+// to reduce the calls to the API of the provider (ex: a single refresh of a zone).
+// If a Provider provides the PresentAll and CleanUpAll methods, they're used instead of Present and CleanUp
+// when several challenges are solved together (not in the sequential mode).
+type ProviderBatch interface {
+	Provider
+	PresentAll(challenges []ChallengeInfo) error
+	CleanUpAll(challenges []ChallengeInfo) error
+}
+
+// ProviderPropagated allows for implementing a Provider able to report the propagation of a challenge
+// with its API (ex: the status of a change, the refresh of a zone).
+// If a Provider provides a Propagated method, it's called before the DNS queries of each propagation check:
+// when it returns true, the challenge is considered as propagated, without DNS queries.
+// When it returns false or an error, the DNS queries are done as usual.
+type ProviderPropagated interface {
+	Provider
+	Propagated(domain, token string) (bool, error)
+}
+
+// ProviderConcurrent allows for implementing a Provider safe for concurrent use:
+// Present and CleanUp may be called from several goroutines (ex: the state kept between the calls is protected by a mutex).
+// If a Provider provides a Concurrent method returning true, the authorizations can be solved concurrently,
+// otherwise they're solved one after the other.
+type ProviderConcurrent interface {
+	Provider
+	Concurrent() bool
+}
+
+// ChallengeInfo contains the parameters of a challenge presented by a ProviderBatch
+// (the parameters of Provider.Present).
+type ChallengeInfo struct {
+	Domain  string
+	Token   string
+	KeyAuth string
+}
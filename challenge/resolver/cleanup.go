@@ -0,0 +1,155 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/platform/wait"
+)
+
+// DefaultCleanupInterval the default delay between the attempts of cleanup.
+const DefaultCleanupInterval = 2 * time.Second
+
+// CleanupPolicy defines the timeout and the retries of the cleanup of each challenge.
+// The zero value tries each cleanup once, without time limit.
+type CleanupPolicy struct {
+	// Timeout bounds each attempt, if positive.
+	// The cleanup methods of the providers can't be canceled: an attempt exceeding the timeout is abandoned.
+	Timeout time.Duration
+
+	// Retries the number of attempts after a failed attempt.
+	Retries int
+
+	// Interval the delay between the attempts (DefaultCleanupInterval if not positive).
+	Interval time.Duration
+}
+
+// cleanUp cleans the challenge of the authorization, according to the policy.
+// The failure is reported (CleanupFailed event) and returned:
+// the callers continue with the other challenges, and log the aggregated errors.
+func cleanUp(core *api.Core, policy CleanupPolicy, solvr solver, authz acme.Authorization) error {
+	cleaner, ok := solvr.(cleanup)
+	if !ok {
+		return nil
+	}
+
+	domain := challenge.GetTargetedDomain(authz)
+
+	err := retryCleanup(core, policy, domain, func() error {
+		return cleaner.CleanUp(authz)
+	})
+	if err == nil {
+		return nil
+	}
+
+	core.Events().Publish(events.CleanupFailed{Domain: domain, Type: solverType(solvr), Err: err})
+
+	return fmt.Errorf("[%s] %w", domain, err)
+}
+
+// cleanUpAll cleans the challenges of the authorizations with a single call to the solver, according to the policy.
+// It returns false if the solver doesn't support it: the challenges must be cleaned up by cleanUp.
+// The failure is reported (CleanupFailed event for each authorization) and returned.
+func cleanUpAll(core *api.Core, policy CleanupPolicy, cleaner batchCleaner, authzs []acme.Authorization) (bool, error) {
+	var domains []string
+	for _, authz := range authzs {
+		domains = append(domains, challenge.GetTargetedDomain(authz))
+	}
+
+	var unsupported atomic.Bool
+
+	err := retryCleanup(core, policy, strings.Join(domains, ", "), func() error {
+		ok, err := cleaner.CleanUpAll(authzs)
+		if !ok {
+			unsupported.Store(true)
+		}
+
+		return err
+	})
+	if unsupported.Load() {
+		return false, nil
+	}
+
+	if err == nil {
+		return true, nil
+	}
+
+	for _, domain := range domains {
+		core.Events().Publish(events.CleanupFailed{Domain: domain, Type: solverType(cleaner), Err: err})
+	}
+
+	return true, fmt.Errorf("[%s] %w", strings.Join(domains, ", "), err)
+}
+
+// retryCleanup calls the cleanup function, and retries it according to the policy.
+func retryCleanup(core *api.Core, policy CleanupPolicy, domain string, fn func() error) error {
+	interval := policy.Interval
+	if interval <= 0 {
+		interval = DefaultCleanupInterval
+	}
+
+	var err error
+
+	for attempt := 0; attempt <= policy.Retries; attempt++ {
+		if attempt > 0 {
+			core.Logger().Info("acme: retrying the cleanup.", "domain", domain, "attempt", attempt+1, "error", err)
+
+			// The cleanup is not interrupted by the cancellation of the issuance.
+			_ = wait.Sleep(context.Background(), interval)
+		}
+
+		err = cleanUpOnce(fn, policy.Timeout)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// cleanUpOnce calls the cleanup function, bounded by the timeout if positive.
+// A panic of the solver is returned as an error, so the other challenges are still cleaned up.
+func cleanUpOnce(fn func() error, timeout time.Duration) error {
+	result := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("cleanup panicked: %v", r)
+			}
+		}()
+
+		result <- fn()
+	}()
+
+	if timeout <= 0 {
+		return <-result
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("cleanup timeout (%s) exceeded", timeout)
+	}
+}
+
+// logCleanupErrors logs the aggregated errors of the cleanups, if any.
+func logCleanupErrors(core *api.Core, errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	core.Logger().Warn("acme: cleaning up failed.", "failures", len(errs), "error", errors.Join(errs...))
+}
@@ -0,0 +1,204 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanupMock fails the first calls to CleanUp with the errors, then succeeds.
+type cleanupMock struct {
+	errs  []error
+	delay time.Duration
+	panic bool
+
+	// the abandoned calls (timeout) are still running during the assertions.
+	calls atomic.Int32
+}
+
+func (s *cleanupMock) Solve(_ acme.Authorization) error {
+	return nil
+}
+
+func (s *cleanupMock) CleanUp(_ acme.Authorization) error {
+	s.calls.Add(1)
+
+	if s.panic {
+		panic("boom")
+	}
+
+	time.Sleep(s.delay)
+
+	if len(s.errs) == 0 {
+		return nil
+	}
+
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+
+	return err
+}
+
+// batchCleanupMock cleans up the challenges of several authorizations at once, like cleanupMock.
+type batchCleanupMock struct {
+	cleanupMock
+
+	unsupported bool
+}
+
+func (s *batchCleanupMock) CleanUpAll(authzs []acme.Authorization) (bool, error) {
+	if s.unsupported {
+		return false, nil
+	}
+
+	return true, s.CleanUp(authzs[0])
+}
+
+func setupCore(t *testing.T) *api.Core {
+	t.Helper()
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	return core
+}
+
+func TestCleanUp(t *testing.T) {
+	core := setupCore(t)
+
+	errCleanup := errors.New("cleanup error")
+
+	testCases := []struct {
+		desc            string
+		solver          *cleanupMock
+		policy          CleanupPolicy
+		expectedError   string
+		expectedCounter int32
+	}{
+		{
+			desc:            "success",
+			solver:          &cleanupMock{},
+			expectedCounter: 1,
+		},
+		{
+			desc:            "failure without retry",
+			solver:          &cleanupMock{errs: []error{errCleanup}},
+			expectedError:   "[example.com] cleanup error",
+			expectedCounter: 1,
+		},
+		{
+			desc:            "success after retry",
+			solver:          &cleanupMock{errs: []error{errCleanup}},
+			policy:          CleanupPolicy{Retries: 2, Interval: time.Millisecond},
+			expectedCounter: 2,
+		},
+		{
+			desc:            "failure after retries",
+			solver:          &cleanupMock{errs: []error{errCleanup, errCleanup, errCleanup}},
+			policy:          CleanupPolicy{Retries: 2, Interval: time.Millisecond},
+			expectedError:   "[example.com] cleanup error",
+			expectedCounter: 3,
+		},
+		{
+			desc:            "timeout",
+			solver:          &cleanupMock{delay: 100 * time.Millisecond},
+			policy:          CleanupPolicy{Timeout: 10 * time.Millisecond},
+			expectedError:   "[example.com] cleanup timeout (10ms) exceeded",
+			expectedCounter: 1,
+		},
+		{
+			desc:            "panic",
+			solver:          &cleanupMock{panic: true},
+			expectedError:   "[example.com] cleanup panicked: boom",
+			expectedCounter: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := cleanUp(core, test.policy, test.solver, createStubAuthorizationHTTP01("example.com", acme.StatusProcessing))
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+
+			assert.Equal(t, test.expectedCounter, test.solver.calls.Load())
+		})
+	}
+}
+
+func TestCleanUpAll(t *testing.T) {
+	core := setupCore(t)
+
+	errCleanup := errors.New("cleanup error")
+
+	testCases := []struct {
+		desc              string
+		solver            *batchCleanupMock
+		policy            CleanupPolicy
+		expectedSupported bool
+		expectedError     string
+		expectedCounter   int32
+	}{
+		{
+			desc:              "success",
+			solver:            &batchCleanupMock{},
+			expectedSupported: true,
+			expectedCounter:   1,
+		},
+		{
+			desc:              "not supported",
+			solver:            &batchCleanupMock{unsupported: true},
+			expectedSupported: false,
+		},
+		{
+			desc:              "success after retry",
+			solver:            &batchCleanupMock{cleanupMock: cleanupMock{errs: []error{errCleanup}}},
+			policy:            CleanupPolicy{Retries: 2, Interval: time.Millisecond},
+			expectedSupported: true,
+			expectedCounter:   2,
+		},
+		{
+			desc:              "failure after retries",
+			solver:            &batchCleanupMock{cleanupMock: cleanupMock{errs: []error{errCleanup, errCleanup}}},
+			policy:            CleanupPolicy{Retries: 1, Interval: time.Millisecond},
+			expectedSupported: true,
+			expectedError:     "[a.example.com, b.example.com] cleanup error",
+			expectedCounter:   2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			authzs := []acme.Authorization{
+				createStubAuthorizationDNS01("a.example.com", false),
+				createStubAuthorizationDNS01("b.example.com", false),
+			}
+
+			supported, err := cleanUpAll(core, test.policy, test.solver, authzs)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+
+			assert.Equal(t, test.expectedSupported, supported)
+			assert.Equal(t, test.expectedCounter, test.solver.calls.Load())
+		})
+	}
+}
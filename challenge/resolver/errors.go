@@ -31,3 +31,8 @@ func (e obtainError) Error() string {
 func (e obtainError) Unwrap() []error {
 	return slices.AppendSeq(make([]error, 0, len(e)), maps.Values(e))
 }
+
+// DomainError returns the error of a domain (nil if the domain had no problem).
+func (e obtainError) DomainError(domain string) error {
+	return e[domain]
+}
@@ -68,3 +68,12 @@ func Test_obtainError_Unwrap(t *testing.T) {
 		})
 	}
 }
+
+func Test_obtainError_DomainError(t *testing.T) {
+	err := obtainError{
+		"example.com": errors.New("oops"),
+	}
+
+	require.EqualError(t, err.DomainError("example.com"), "oops")
+	require.NoError(t, err.DomainError("example.org"))
+}
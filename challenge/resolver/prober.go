@@ -1,12 +1,21 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
 	"github.com/digicert/lego/v4/challenge"
-	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/challenge/http01"
+	"github.com/digicert/lego/v4/challenge/tlsalpn01"
+	"github.com/digicert/lego/v4/platform/wait"
+	"golang.org/x/net/publicsuffix"
 )
 
 // Interface for all challenge solvers to implement.
@@ -14,29 +23,69 @@ type solver interface {
 	Solve(authorization acme.Authorization) error
 }
 
+// Interface for challenges where the waits (propagation, validation) can be interrupted.
+type contextSolver interface {
+	SolveWithContext(ctx context.Context, authorization acme.Authorization) error
+}
+
 // Interface for challenges like dns, where we can set a record in advance for ALL challenges.
 // This saves quite a bit of time vs creating the records and solving them serially.
 type preSolver interface {
 	PreSolve(authorization acme.Authorization) error
 }
 
+// Interface for challenges where the submission of the challenges can be interrupted.
+type contextPreSolver interface {
+	PreSolveWithContext(ctx context.Context, authorization acme.Authorization) error
+}
+
+// Interface for challenges like dns, where the challenges of several authorizations can be submitted at once
+// (ex: a single call to the DNS provider for the records of `example.com` and `*.example.com`).
+// It returns false if the batch is not supported: the challenges are submitted by PreSolve.
+type batchPreSolver interface {
+	PreSolveAll(ctx context.Context, authorizations []acme.Authorization) (bool, error)
+}
+
 // Interface for challenges like dns, where we can solve all the challenges before to delete them.
 type cleanup interface {
 	CleanUp(authorization acme.Authorization) error
 }
 
+// Interface for challenges like dns, where the challenges of several authorizations can be deleted at once.
+// It returns false if the batch is not supported: the challenges are deleted by CleanUp.
+type batchCleaner interface {
+	CleanUpAll(authorizations []acme.Authorization) (bool, error)
+}
+
 type sequential interface {
 	Sequential() (bool, time.Duration)
 }
 
+// Interface for challenges where the challenges of several authorizations can be solved concurrently.
+type concurrent interface {
+	Concurrent() bool
+}
+
 // an authz with the solver we have chosen and the index of the challenge associated with it.
 type selectedAuthSolver struct {
-	authz  acme.Authorization
-	solver solver
+	authz    acme.Authorization
+	solver   solver
+	chlgType challenge.Type
+
+	// fallbacks are the next solvers, tried in order if the solving fails before the validation by the server.
+	fallbacks []typedSolver
 }
 
 type Prober struct {
 	solverManager *SolverManager
+
+	// challengeTimeout bounds the solving of each authorization, if positive.
+	challengeTimeout time.Duration
+
+	cleanupPolicy CleanupPolicy
+
+	// concurrency the maximum number of authorizations solved concurrently.
+	concurrency int
 }
 
 func NewProber(solverManager *SolverManager) *Prober {
@@ -45,11 +94,51 @@ func NewProber(solverManager *SolverManager) *Prober {
 	}
 }
 
+// SetChallengeTimeout defines the maximum duration of the solving of each authorization
+// (presentation, propagation, and validation of the challenge).
+// A zero or negative timeout removes the limit.
+func (p *Prober) SetChallengeTimeout(timeout time.Duration) {
+	p.challengeTimeout = timeout
+}
+
+// SetCleanupPolicy defines the timeout and the retries of the cleanup of each challenge.
+func (p *Prober) SetCleanupPolicy(policy CleanupPolicy) {
+	p.cleanupPolicy = policy
+}
+
+// SetConcurrentSolves defines the maximum number of authorizations solved concurrently.
+// The challenges of the same zone are still presented one after the other.
+// Only the challenges safe for concurrent use are solved concurrently (ex: not with the built-in HTTP-01 and TLS-ALPN-01 servers).
+// A value lower than 2 disables the concurrent solving.
+func (p *Prober) SetConcurrentSolves(n int) {
+	p.concurrency = n
+}
+
 // Solve Looks through the challenge combinations to find a solvable match.
 // Then solves the challenges in series and returns.
 func (p *Prober) Solve(authorizations []acme.Authorization) error {
+	return p.SolveWithContext(context.Background(), authorizations)
+}
+
+// SolveWithContext is like Solve, but stops solving the challenges as soon as the context is done.
+// The challenges already presented are always cleaned up.
+func (p *Prober) SolveWithContext(ctx context.Context, authorizations []acme.Authorization) error {
 	failures := make(obtainError)
 
+	var (
+		solved   int
+		solvedMu sync.Mutex
+	)
+
+	// authorized reports the progress when an authorization is solved, or has failed.
+	authorized := func(domain string) {
+		solvedMu.Lock()
+		defer solvedMu.Unlock()
+
+		solved++
+		p.solverManager.core.Progress().Authorized(domain, solved, len(authorizations))
+	}
+
 	var (
 		authSolvers           []*selectedAuthSolver
 		authSolversSequential []*selectedAuthSolver
@@ -62,12 +151,21 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 		domain := challenge.GetTargetedDomain(authz)
 		if authz.Status == acme.StatusValid {
 			// Boulder might recycle recent validated authz (see issue #267)
-			log.Infof("[%s] acme: authorization already valid; skipping challenge", domain)
+			p.solverManager.core.Logger().Info("acme: authorization already valid; skipping challenge.", "domain", domain)
+			authorized(domain)
+
 			continue
 		}
 
-		if solvr := p.solverManager.chooseSolver(authz); solvr != nil {
-			authSolver := &selectedAuthSolver{authz: authz, solver: solvr}
+		if candidates := p.solverManager.chooseSolvers(authz); len(candidates) > 0 {
+			solvr := candidates[0].solver
+
+			authSolver := &selectedAuthSolver{
+				authz:     authz,
+				solver:    solvr,
+				chlgType:  candidates[0].chlgType,
+				fallbacks: candidates[1:],
+			}
 
 			switch s := solvr.(type) {
 			case sequential:
@@ -84,9 +182,11 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 		}
 	}
 
-	parallelSolve(authSolvers, failures)
+	parallelSolve(ctx, p.solverManager.core, authSolvers, failures, authorized, p.challengeTimeout, p.cleanupPolicy, p.concurrency)
 
-	sequentialSolve(authSolversSequential, failures)
+	sequentialSolve(ctx, p.solverManager.core, authSolversSequential, failures, authorized, p.challengeTimeout, p.cleanupPolicy)
+
+	fallbackSolve(ctx, p.solverManager.core, slices.Concat(authSolvers, authSolversSequential), failures, p.challengeTimeout, p.cleanupPolicy)
 
 	// Be careful not to return an empty failures map,
 	// for even an empty obtainError is a non-nil error value
@@ -97,30 +197,45 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 	return nil
 }
 
-func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+func sequentialSolve(ctx context.Context, core *api.Core, authSolvers []*selectedAuthSolver, failures obtainError, authorized func(domain string), timeout time.Duration, policy CleanupPolicy) {
 	// Some CA are using the same token,
 	// this can be a problem with the DNS01 challenge when the DNS provider doesn't support duplicate TXT records.
 	// In the sequential mode, this is not a problem because we can solve the challenges in order.
 	// But it can reduce the number of call the DNS provider APIs.
 	uniq := make(map[string]struct{})
 
+	var cleanupErrs []error
+
+	defer func() { logCleanupErrors(core, cleanupErrs) }()
+
 	for i, authSolver := range authSolvers {
 		// Submit the challenge
 		domain := challenge.GetTargetedDomain(authSolver.authz)
 
+		if ctx.Err() != nil {
+			failures[domain] = canceled(ctx, domain)
+			authorized(domain)
+
+			continue
+		}
+
 		chlg, _ := challenge.FindChallenge(challenge.DNS01, authSolver.authz)
 
-		if solvr, ok := authSolver.solver.(preSolver); ok {
+		if _, ok := authSolver.solver.(preSolver); ok {
 			if _, ok := uniq[authSolver.authz.Identifier.Value+chlg.Token]; ok && chlg.Token != "" {
-				log.Infof("acme: duplicate token for %q (DNS-01); skipping pre-solve.", authSolver.authz.Identifier.Value)
+				core.Logger().Info("acme: duplicate token (DNS-01); skipping pre-solve.", "domain", authSolver.authz.Identifier.Value)
 				continue
 			}
 
-			err := solvr.PreSolve(authSolver.authz)
+			err := preSolve(ctx, authSolver.solver, authSolver.authz)
 			if err != nil {
 				failures[domain] = err
 
-				cleanUp(authSolver.solver, authSolver.authz)
+				if errC := cleanUp(core, policy, authSolver.solver, authSolver.authz); errC != nil {
+					cleanupErrs = append(cleanupErrs, errC)
+				}
+
+				authorized(domain)
 
 				continue
 			}
@@ -129,101 +244,466 @@ func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
 		}
 
 		// Solve challenge
-		err := authSolver.solver.Solve(authSolver.authz)
+		err := solve(ctx, authSolver.solver, authSolver.authz, timeout)
+
+		authorized(domain)
+
 		if err != nil {
 			failures[domain] = err
 
-			cleanUp(authSolver.solver, authSolver.authz)
+			if errC := cleanUp(core, policy, authSolver.solver, authSolver.authz); errC != nil {
+				cleanupErrs = append(cleanupErrs, errC)
+			}
 
 			continue
 		}
 
 		if _, ok := uniq[authSolver.authz.Identifier.Value+chlg.Token]; ok || chlg.Token == "" {
 			// Clean challenge
-			cleanUp(authSolver.solver, authSolver.authz)
+			if errC := cleanUp(core, policy, authSolver.solver, authSolver.authz); errC != nil {
+				cleanupErrs = append(cleanupErrs, errC)
+			}
 
 			if len(authSolvers)-1 > i {
 				solvr := authSolver.solver.(sequential)
 				_, interval := solvr.Sequential()
-				log.Infof("sequence: wait for %s", interval)
-				time.Sleep(interval)
+				core.Logger().Info("sequence: wait.", "interval", interval)
+				_ = wait.Sleep(ctx, interval)
 			}
 
 			delete(uniq, authSolver.authz.Identifier.Value+chlg.Token)
 		} else {
-			log.Infof("acme: duplicate token for %q (DNS-01); skipping cleanup.", authSolver.authz.Identifier.Value)
+			core.Logger().Info("acme: duplicate token (DNS-01); skipping cleanup.", "domain", authSolver.authz.Identifier.Value)
 		}
 	}
 }
 
-func parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+func parallelSolve(ctx context.Context, core *api.Core, authSolvers []*selectedAuthSolver, failures obtainError, authorized func(domain string), timeout time.Duration, policy CleanupPolicy, concurrency int) {
 	// Some CA are using the same token,
 	// this can be a problem with the DNS01 challenge when the DNS provider doesn't support duplicate TXT records.
 	uniq := make(map[string]struct{})
 
-	// For all valid preSolvers, first submit the challenges, so they have max time to propagate
+	var toPreSolve []*selectedAuthSolver
+
 	for _, authSolver := range authSolvers {
 		authz := authSolver.authz
 
 		chlg, err := challenge.FindChallenge(challenge.DNS01, authz)
 		if err == nil {
 			if _, ok := uniq[authz.Identifier.Value+chlg.Token]; ok {
-				log.Infof("acme: duplicate token for %q (DNS-01); skipping pre-solve.", authSolver.authz.Identifier.Value)
+				core.Logger().Info("acme: duplicate token (DNS-01); skipping pre-solve.", "domain", authSolver.authz.Identifier.Value)
 				continue
 			}
 
 			uniq[authz.Identifier.Value+chlg.Token] = struct{}{}
 		}
 
-		if solvr, ok := authSolver.solver.(preSolver); ok {
-			err := solvr.PreSolve(authz)
+		toPreSolve = append(toPreSolve, authSolver)
+	}
+
+	var failuresMu sync.Mutex
+
+	fail := func(domain string, err error) {
+		failuresMu.Lock()
+		defer failuresMu.Unlock()
+
+		failures[domain] = err
+	}
+
+	// For all valid preSolvers, first submit the challenges, so they have max time to propagate.
+	// The zones are handled concurrently, but the challenges of a zone are submitted one after the other.
+	forEach(groupByZone(batchPreSolve(ctx, toPreSolve, failures), concurrency), concurrency, func(group []*selectedAuthSolver) {
+		for _, authSolver := range group {
+			authz := authSolver.authz
+
+			err := preSolve(ctx, authSolver.solver, authz)
 			if err != nil {
-				failures[challenge.GetTargetedDomain(authz)] = err
+				fail(challenge.GetTargetedDomain(authz), err)
 			}
 		}
-	}
+	})
 
 	defer func() {
-		// Clean all created TXT records
+		// Clean all created TXT records: a failed cleanup doesn't prevent the cleanup of the other challenges.
+		var toCleanUp []*selectedAuthSolver
+
 		for _, authSolver := range authSolvers {
 			chlg, err := challenge.FindChallenge(challenge.DNS01, authSolver.authz)
 			if err == nil {
 				if _, ok := uniq[authSolver.authz.Identifier.Value+chlg.Token]; ok {
 					delete(uniq, authSolver.authz.Identifier.Value+chlg.Token)
 				} else {
-					log.Infof("acme: duplicate token for %q (DNS-01); skipping cleanup.", authSolver.authz.Identifier.Value)
+					core.Logger().Info("acme: duplicate token (DNS-01); skipping cleanup.", "domain", authSolver.authz.Identifier.Value)
 					continue
 				}
 			}
 
-			cleanUp(authSolver.solver, authSolver.authz)
+			toCleanUp = append(toCleanUp, authSolver)
+		}
+
+		remaining, cleanupErrs := batchCleanUp(core, policy, toCleanUp)
+
+		for _, authSolver := range remaining {
+			if err := cleanUp(core, policy, authSolver.solver, authSolver.authz); err != nil {
+				cleanupErrs = append(cleanupErrs, err)
+			}
 		}
+
+		logCleanupErrors(core, cleanupErrs)
 	}()
 
 	// Finally solve all challenges for real
-	for _, authSolver := range authSolvers {
-		authz := authSolver.authz
+	var toSolve, toSolveSequentially []*selectedAuthSolver
 
-		domain := challenge.GetTargetedDomain(authz)
+	for _, authSolver := range authSolvers {
+		domain := challenge.GetTargetedDomain(authSolver.authz)
 		if failures[domain] != nil {
 			// already failed in previous loop
+			authorized(domain)
+
 			continue
 		}
 
-		err := authSolver.solver.Solve(authz)
+		if concurrency > 1 && isConcurrent(authSolver.solver) {
+			toSolve = append(toSolve, authSolver)
+		} else {
+			toSolveSequentially = append(toSolveSequentially, authSolver)
+		}
+	}
+
+	solveAuthz := func(authSolver *selectedAuthSolver) {
+		domain := challenge.GetTargetedDomain(authSolver.authz)
+
+		if ctx.Err() != nil {
+			fail(domain, canceled(ctx, domain))
+			authorized(domain)
+
+			return
+		}
+
+		err := solve(ctx, authSolver.solver, authSolver.authz, timeout)
+
+		authorized(domain)
+
 		if err != nil {
-			failures[domain] = err
+			fail(domain, err)
 		}
 	}
+
+	forEach(toSolve, concurrency, solveAuthz)
+
+	for _, authSolver := range toSolveSequentially {
+		solveAuthz(authSolver)
+	}
 }
 
-func cleanUp(solvr solver, authz acme.Authorization) {
-	if solvr, ok := solvr.(cleanup); ok {
-		domain := challenge.GetTargetedDomain(authz)
+// groupByZone groups the authorizations by zone (the registrable domain),
+// the authorizations of the solvers which are not safe for concurrent use are grouped together.
+// Without concurrency, all the authorizations are in the same group.
+func groupByZone(authSolvers []*selectedAuthSolver, concurrency int) [][]*selectedAuthSolver {
+	if concurrency < 2 {
+		return [][]*selectedAuthSolver{authSolvers}
+	}
+
+	var (
+		order  []string
+		groups = make(map[string][]*selectedAuthSolver)
+	)
+
+	for _, authSolver := range authSolvers {
+		var key string
+		if isConcurrent(authSolver.solver) {
+			key = zoneKey(authSolver.authz.Identifier.Value)
+		}
+
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+
+		groups[key] = append(groups[key], authSolver)
+	}
+
+	result := make([][]*selectedAuthSolver, 0, len(order))
+	for _, key := range order {
+		result = append(result, groups[key])
+	}
+
+	return result
+}
+
+// zoneKey returns the registrable domain of a domain (ex: `example.com` for `*.www.example.com`),
+// which is the zone of the domain, or a parent of its zone.
+func zoneKey(domain string) string {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+
+	key, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return domain
+	}
+
+	return key
+}
+
+func isConcurrent(solvr any) bool {
+	s, ok := solvr.(concurrent)
+
+	return ok && s.Concurrent()
+}
+
+// forEach calls fn for each item, with up to `concurrency` calls at the same time.
+func forEach[T any](items []T, concurrency int, fn func(T)) {
+	if concurrency < 2 || len(items) < 2 {
+		for _, item := range items {
+			fn(item)
+		}
+
+		return
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for _, item := range items {
+		sem <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			fn(item)
+		})
+	}
+
+	wg.Wait()
+}
+
+// batchPreSolve submits at once the challenges of the authorizations sharing a solver able to do it (batchPreSolver).
+// It returns the authorizations still to pre-solve, in the same order.
+func batchPreSolve(ctx context.Context, authSolvers []*selectedAuthSolver, failures obtainError) []*selectedAuthSolver {
+	var (
+		order  []batchPreSolver
+		groups = make(map[batchPreSolver][]*selectedAuthSolver)
+	)
+
+	for _, authSolver := range authSolvers {
+		solvr, ok := authSolver.solver.(batchPreSolver)
+		if !ok {
+			continue
+		}
+
+		if _, ok := groups[solvr]; !ok {
+			order = append(order, solvr)
+		}
+
+		groups[solvr] = append(groups[solvr], authSolver)
+	}
+
+	done := make(map[*selectedAuthSolver]struct{})
+
+	for _, solvr := range order {
+		group := groups[solvr]
+		if len(group) < 2 {
+			continue
+		}
+
+		var authzs []acme.Authorization
+		for _, authSolver := range group {
+			authzs = append(authzs, authSolver.authz)
+		}
+
+		ok, err := solvr.PreSolveAll(ctx, authzs)
+		if !ok {
+			continue
+		}
+
+		for _, authSolver := range group {
+			if err != nil {
+				failures[challenge.GetTargetedDomain(authSolver.authz)] = err
+			}
+
+			done[authSolver] = struct{}{}
+		}
+	}
+
+	var remaining []*selectedAuthSolver
+
+	for _, authSolver := range authSolvers {
+		if _, ok := done[authSolver]; !ok {
+			remaining = append(remaining, authSolver)
+		}
+	}
+
+	return remaining
+}
+
+// batchCleanUp cleans up at once the challenges of the authorizations sharing a solver able to do it (batchCleaner).
+// It returns the authorizations still to clean up, in the same order, and the errors of the batches.
+func batchCleanUp(core *api.Core, policy CleanupPolicy, authSolvers []*selectedAuthSolver) ([]*selectedAuthSolver, []error) {
+	var (
+		order  []batchCleaner
+		groups = make(map[batchCleaner][]*selectedAuthSolver)
+	)
+
+	for _, authSolver := range authSolvers {
+		solvr, ok := authSolver.solver.(batchCleaner)
+		if !ok {
+			continue
+		}
+
+		if _, ok := groups[solvr]; !ok {
+			order = append(order, solvr)
+		}
+
+		groups[solvr] = append(groups[solvr], authSolver)
+	}
+
+	var errs []error
+
+	done := make(map[*selectedAuthSolver]struct{})
+
+	for _, solvr := range order {
+		group := groups[solvr]
+		if len(group) < 2 {
+			continue
+		}
+
+		var authzs []acme.Authorization
+		for _, authSolver := range group {
+			authzs = append(authzs, authSolver.authz)
+		}
+
+		ok, err := cleanUpAll(core, policy, solvr, authzs)
+		if !ok {
+			continue
+		}
 
-		err := solvr.CleanUp(authz)
 		if err != nil {
-			log.Warnf("[%s] acme: cleaning up failed: %v ", domain, err)
+			errs = append(errs, err)
+		}
+
+		for _, authSolver := range group {
+			done[authSolver] = struct{}{}
+		}
+	}
+
+	var remaining []*selectedAuthSolver
+
+	for _, authSolver := range authSolvers {
+		if _, ok := done[authSolver]; !ok {
+			remaining = append(remaining, authSolver)
 		}
 	}
+
+	return remaining, errs
+}
+
+// fallbackSolve solves the failed authorizations with the next solvers (the other challenge types offered by the server).
+// A fallback is only possible if the failed challenge has not been validated by the server
+// (ex: the DNS propagation has timed out): the server considers only one attempt by authorization.
+func fallbackSolve(ctx context.Context, core *api.Core, authSolvers []*selectedAuthSolver, failures obtainError, timeout time.Duration, policy CleanupPolicy) {
+	for _, authSolver := range authSolvers {
+		domain := challenge.GetTargetedDomain(authSolver.authz)
+
+		for failures[domain] != nil && len(authSolver.fallbacks) > 0 && ctx.Err() == nil {
+			if !challengePending(core, authSolver.authz, authSolver.chlgType) {
+				break
+			}
+
+			next := authSolver.fallbacks[0]
+
+			core.Logger().Warn("acme: challenge failed, trying the next challenge type.",
+				"domain", domain, "type", authSolver.chlgType, "next", next.chlgType, "error", failures[domain])
+
+			authSolver.solver = next.solver
+			authSolver.chlgType = next.chlgType
+			authSolver.fallbacks = authSolver.fallbacks[1:]
+
+			err := solveOne(ctx, core, authSolver.solver, authSolver.authz, timeout, policy)
+			if err != nil {
+				failures[domain] = err
+				continue
+			}
+
+			delete(failures, domain)
+		}
+	}
+}
+
+// challengePending reports whether the challenge has not been validated by the server,
+// so the authorization can still be solved with another challenge.
+func challengePending(core *api.Core, authz acme.Authorization, chlgType challenge.Type) bool {
+	chlg, err := challenge.FindChallenge(chlgType, authz)
+	if err != nil || chlg.URL == "" {
+		return false
+	}
+
+	current, err := core.Challenges.Get(chlg.URL)
+	if err != nil {
+		return false
+	}
+
+	return current.Status == acme.StatusPending
+}
+
+// solveOne presents, solves, and cleans up the challenge of one authorization.
+func solveOne(ctx context.Context, core *api.Core, solvr solver, authz acme.Authorization, timeout time.Duration, policy CleanupPolicy) error {
+	defer func() {
+		if err := cleanUp(core, policy, solvr, authz); err != nil {
+			logCleanupErrors(core, []error{err})
+		}
+	}()
+
+	err := preSolve(ctx, solvr, authz)
+	if err != nil {
+		return err
+	}
+
+	return solve(ctx, solvr, authz, timeout)
+}
+
+// preSolve submits the challenge of the authorization, if the solver supports it (preSolver).
+func preSolve(ctx context.Context, solvr solver, authz acme.Authorization) error {
+	if s, ok := solvr.(contextPreSolver); ok {
+		return s.PreSolveWithContext(ctx, authz)
+	}
+
+	if s, ok := solvr.(preSolver); ok {
+		return s.PreSolve(authz)
+	}
+
+	return nil
+}
+
+func solve(ctx context.Context, solvr solver, authz acme.Authorization, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout,
+			fmt.Errorf("challenge timeout (%s) exceeded: %w", timeout, context.DeadlineExceeded))
+		defer cancel()
+	}
+
+	if s, ok := solvr.(contextSolver); ok {
+		return s.SolveWithContext(ctx, authz)
+	}
+
+	return solvr.Solve(authz)
+}
+
+func canceled(ctx context.Context, domain string) error {
+	return fmt.Errorf("[%s] acme: challenge not solved: %w", domain, context.Cause(ctx))
+}
+
+// solverType returns the challenge type of a solver, if known.
+func solverType(solvr any) string {
+	switch solvr.(type) {
+	case *dns01.Challenge:
+		return string(challenge.DNS01)
+	case *http01.Challenge:
+		return string(challenge.HTTP01)
+	case *tlsalpn01.Challenge:
+		return string(challenge.TLSALPN01)
+	default:
+		return ""
+	}
 }
@@ -1,7 +1,9 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/acme"
@@ -40,6 +42,33 @@ func (s *preSolverMock) String() string {
 	return fmt.Sprintf("PreSolve: %d, Solve: %d, CleanUp: %d", s.preSolveCounter, s.solveCounter, s.cleanUpCounter)
 }
 
+// batchSolverMock submits the challenges of several authorizations at once.
+type batchSolverMock struct {
+	preSolverMock
+
+	preSolveAll        error
+	preSolveAllCounter int
+
+	cleanUpAll        error
+	cleanUpAllCounter int
+}
+
+func (s *batchSolverMock) PreSolveAll(_ context.Context, _ []acme.Authorization) (bool, error) {
+	s.preSolveAllCounter++
+
+	return true, s.preSolveAll
+}
+
+func (s *batchSolverMock) CleanUpAll(_ []acme.Authorization) (bool, error) {
+	s.cleanUpAllCounter++
+
+	return true, s.cleanUpAll
+}
+
+func (s *batchSolverMock) String() string {
+	return fmt.Sprintf("PreSolveAll: %d, %s, CleanUpAll: %d", s.preSolveAllCounter, s.preSolverMock.String(), s.cleanUpAllCounter)
+}
+
 func createStubAuthorizationHTTP01(domain, status string) acme.Authorization {
 	return createStubAuthorization(domain, status, false, acme.Challenge{
 		Type:      challenge.HTTP01.String(),
@@ -77,3 +106,67 @@ func createStubAuthorization(domain, status string, wildcard bool, chlgs ...acme
 		Challenges: chlgs,
 	}
 }
+
+// contextSolverMock cancels the context during the first call to SolveWithContext.
+type contextSolverMock struct {
+	preSolverMock
+
+	cancel context.CancelFunc
+}
+
+func (s *contextSolverMock) SolveWithContext(_ context.Context, authorization acme.Authorization) error {
+	s.cancel()
+
+	return s.Solve(authorization)
+}
+
+// blockingSolverMock waits until the context is done.
+type blockingSolverMock struct {
+	preSolverMock
+}
+
+func (s *blockingSolverMock) SolveWithContext(ctx context.Context, _ acme.Authorization) error {
+	<-ctx.Done()
+
+	return context.Cause(ctx)
+}
+
+// concurrentSolverMock is safe for concurrent use, and records the maximum number of concurrent calls to Solve.
+type concurrentSolverMock struct {
+	mu sync.Mutex
+
+	solved   []string
+	inFlight int
+	maxCalls int
+}
+
+func (s *concurrentSolverMock) Solve(authorization acme.Authorization) error {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxCalls = max(s.maxCalls, s.inFlight)
+	s.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight--
+	s.solved = append(s.solved, authorization.Identifier.Value)
+
+	return nil
+}
+
+func (s *concurrentSolverMock) CleanUp(_ acme.Authorization) error {
+	return nil
+}
+
+func (s *concurrentSolverMock) Concurrent() bool {
+	return true
+}
+
+// noopProvider a DNS provider creating no records.
+type noopProvider struct{}
+
+func (noopProvider) Present(_, _, _ string) error { return nil }
+func (noopProvider) CleanUp(_, _, _ string) error { return nil }
@@ -1,12 +1,23 @@
 package resolver
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
 	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/platform/tester/acmeserver"
+	"github.com/digicert/lego/v4/platform/tester/fakedns"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,6 +69,49 @@ func TestProber_Solve(t *testing.T) {
 				challenge.DNS01: "PreSolve: 4, Solve: 6, CleanUp: 4",
 			},
 		},
+		{
+			desc: "DNS-01 batch",
+			solvers: map[challenge.Type]solver{
+				challenge.DNS01: &batchSolverMock{
+					preSolverMock: preSolverMock{
+						preSolve: map[string]error{},
+						solve:    map[string]error{},
+						cleanUp:  map[string]error{},
+					},
+				},
+			},
+			authz: []acme.Authorization{
+				createStubAuthorizationDNS01("a.example", false),
+				createStubAuthorizationDNS01("b.example", false),
+			},
+			expectedCounters: map[challenge.Type]string{
+				challenge.DNS01: "PreSolveAll: 1, PreSolve: 0, Solve: 2, CleanUp: 0, CleanUpAll: 1",
+			},
+		},
+		{
+			desc: "DNS-01 batch error",
+			solvers: map[challenge.Type]solver{
+				challenge.DNS01: &batchSolverMock{
+					preSolverMock: preSolverMock{
+						preSolve: map[string]error{},
+						solve:    map[string]error{},
+						cleanUp:  map[string]error{},
+					},
+					preSolveAll: errors.New("batch error"),
+				},
+			},
+			authz: []acme.Authorization{
+				createStubAuthorizationDNS01("a.example", false),
+				createStubAuthorizationDNS01("b.example", false),
+			},
+			expectedError: `error: one or more domains had a problem:
+[a.example] batch error
+[b.example] batch error
+`,
+			expectedCounters: map[challenge.Type]string{
+				challenge.DNS01: "PreSolveAll: 1, PreSolve: 0, Solve: 0, CleanUp: 0, CleanUpAll: 1",
+			},
+		},
 		{
 			desc: "already valid",
 			solvers: map[challenge.Type]solver{
@@ -155,3 +209,343 @@ func TestProber_Solve(t *testing.T) {
 		})
 	}
 }
+
+func TestProber_Solve_cleanupFailed(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	var got []events.Event
+
+	core.Events().Subscribe(func(event events.Event) {
+		got = append(got, event)
+	})
+
+	cleanUpErr := errors.New("clean error example.net")
+
+	prober := &Prober{
+		solverManager: &SolverManager{
+			core: core,
+			solvers: map[challenge.Type]solver{
+				challenge.HTTP01: &preSolverMock{
+					preSolve: map[string]error{},
+					solve:    map[string]error{},
+					cleanUp:  map[string]error{"example.net": cleanUpErr},
+				},
+			},
+		},
+	}
+
+	err = prober.Solve([]acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.net", acme.StatusProcessing),
+	})
+	require.NoError(t, err)
+
+	expected := []events.Event{
+		events.CleanupFailed{Domain: "example.net", Err: cleanUpErr},
+	}
+
+	assert.Equal(t, expected, got)
+}
+
+func TestProber_Solve_fallback(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		status           string
+		expectedError    string
+		expectedCounters map[challenge.Type]string
+	}{
+		{
+			desc:   "challenge not validated",
+			status: acme.StatusPending,
+			expectedCounters: map[challenge.Type]string{
+				challenge.DNS01:  "PreSolve: 1, Solve: 1, CleanUp: 1",
+				challenge.HTTP01: "PreSolve: 1, Solve: 1, CleanUp: 1",
+			},
+		},
+		{
+			desc:   "challenge validated",
+			status: acme.StatusInvalid,
+			expectedError: `error: one or more domains had a problem:
+[example.com] propagation timeout
+`,
+			expectedCounters: map[challenge.Type]string{
+				challenge.DNS01:  "PreSolve: 1, Solve: 1, CleanUp: 1",
+				challenge.HTTP01: "PreSolve: 0, Solve: 0, CleanUp: 0",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			server := tester.MockACMEServer().
+				Route("POST /chlg/dns", servermock.JSONEncode(acme.Challenge{Type: "dns-01", Status: test.status})).
+				BuildHTTPS(t)
+
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err)
+
+			core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+			require.NoError(t, err)
+
+			solvers := map[challenge.Type]solver{
+				challenge.DNS01: &preSolverMock{
+					solve: map[string]error{"example.com": errors.New("propagation timeout")},
+				},
+				challenge.HTTP01: &preSolverMock{},
+			}
+
+			manager := NewSolversManager(core)
+			manager.solvers = solvers
+			manager.SetChallengePreference(challenge.DNS01)
+
+			authz := createStubAuthorization("example.com", acme.StatusPending, false,
+				acme.Challenge{Type: "dns-01", URL: server.URL + "/chlg/dns"},
+				acme.Challenge{Type: "http-01", URL: server.URL + "/chlg/http"},
+			)
+
+			err = NewProber(manager).Solve([]acme.Authorization{authz})
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for n, s := range solvers {
+				assert.Equal(t, test.expectedCounters[n], fmt.Sprintf("%s", s))
+			}
+		})
+	}
+}
+
+func TestProber_SolveWithContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	solvr := &contextSolverMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{},
+			solve:    map[string]error{},
+			cleanUp:  map[string]error{},
+		},
+		cancel: cancel,
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	err := prober.SolveWithContext(ctx, []acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.org", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.net", acme.StatusProcessing),
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.EqualError(t, err, "error: one or more domains had a problem:\n"+
+		"[example.net] [example.net] acme: challenge not solved: context canceled\n"+
+		"[example.org] [example.org] acme: challenge not solved: context canceled\n")
+
+	// The challenges already presented are cleaned up.
+	assert.Equal(t, "PreSolve: 3, Solve: 1, CleanUp: 3", solvr.String())
+}
+
+func TestProber_SolveWithContext_challengeTimeout(t *testing.T) {
+	solvr := &blockingSolverMock{}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	prober.SetChallengeTimeout(50 * time.Millisecond)
+
+	err := prober.SolveWithContext(t.Context(), []acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.org", acme.StatusProcessing),
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.EqualError(t, err, "error: one or more domains had a problem:\n"+
+		"[example.com] challenge timeout (50ms) exceeded: context deadline exceeded\n"+
+		"[example.org] challenge timeout (50ms) exceeded: context deadline exceeded\n")
+}
+
+func TestProber_SolveWithContext_concurrent(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		concurrency int
+		expected    int
+	}{
+		{desc: "disabled", concurrency: 0, expected: 1},
+		{desc: "limited", concurrency: 2, expected: 2},
+		{desc: "unlimited", concurrency: 10, expected: 4},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			solvr := &concurrentSolverMock{}
+
+			prober := &Prober{
+				solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+			}
+
+			prober.SetConcurrentSolves(test.concurrency)
+
+			err := prober.SolveWithContext(t.Context(), []acme.Authorization{
+				createStubAuthorizationHTTP01("a.example.com", acme.StatusProcessing),
+				createStubAuthorizationHTTP01("b.example.com", acme.StatusProcessing),
+				createStubAuthorizationHTTP01("example.org", acme.StatusProcessing),
+				createStubAuthorizationHTTP01("example.net", acme.StatusProcessing),
+			})
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, []string{"a.example.com", "b.example.com", "example.org", "example.net"}, solvr.solved)
+			assert.Equal(t, test.expected, solvr.maxCalls)
+		})
+	}
+}
+
+func TestProber_Solve_acmeServer(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := fakedns.NewProvider("127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	server := acmeserver.New(t, acmeserver.WithDNSResolver(provider.Addr()))
+
+	core := setupAccount(t, server)
+
+	solversManager := NewSolversManager(core)
+
+	err = solversManager.SetDNS01Provider(provider,
+		dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+		dns01.DisableAuthoritativeNssPropagationRequirement(),
+	)
+	require.NoError(t, err)
+
+	authzURLs, authzs := newOrder(t, core, "example.com", "*.example.com", "example.org")
+
+	err = NewProber(solversManager).Solve(authzs)
+	require.NoError(t, err)
+
+	for _, authzURL := range authzURLs {
+		authz, err := core.Authorizations.Get(authzURL)
+		require.NoError(t, err)
+
+		assert.Equal(t, acme.StatusValid, authz.Status, authz.Identifier.Value)
+	}
+
+	// The challenge records are cleaned up.
+	assert.Empty(t, provider.Records("_acme-challenge.example.com."))
+	assert.Empty(t, provider.Records("_acme-challenge.example.org."))
+}
+
+func TestProber_Solve_acmeServer_invalid(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := fakedns.NewProvider("127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	server := acmeserver.New(t, acmeserver.WithDNSResolver(provider.Addr()))
+
+	core := setupAccount(t, server)
+
+	solversManager := NewSolversManager(core)
+
+	// The record is never presented: the propagation check is skipped to reach the validation.
+	err = solversManager.SetDNS01Provider(noopProvider{},
+		dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+		dns01.WrapPreCheck(func(_, _, _ string, _ dns01.PreCheckFunc) (bool, error) {
+			return true, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	_, authzs := newOrder(t, core, "example.com")
+
+	err = NewProber(solversManager).Solve(authzs)
+	require.ErrorContains(t, err, "incorrect TXT record")
+}
+
+// setupAccount registers an account on the ACME test server,
+// and returns the client signing the requests with the account URL.
+func setupAccount(t *testing.T, server *acmeserver.Server) *api.Core {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	return core
+}
+
+// newOrder creates an order, and returns the URLs of the authorizations with the authorizations.
+func newOrder(t *testing.T, core *api.Core, domains ...string) ([]string, []acme.Authorization) {
+	t.Helper()
+
+	order, err := core.Orders.New(domains)
+	require.NoError(t, err)
+
+	var authzs []acme.Authorization
+
+	for _, authzURL := range order.Authorizations {
+		authz, err := core.Authorizations.Get(authzURL)
+		require.NoError(t, err)
+
+		authzs = append(authzs, authz)
+	}
+
+	return order.Authorizations, authzs
+}
+
+func Test_groupByZone(t *testing.T) {
+	concurrentSolver := &concurrentSolverMock{}
+	sequentialSolver := &preSolverMock{}
+
+	authSolvers := []*selectedAuthSolver{
+		{authz: createStubAuthorizationDNS01("*.example.com", true), solver: concurrentSolver},
+		{authz: createStubAuthorizationDNS01("example.org", false), solver: concurrentSolver},
+		{authz: createStubAuthorizationDNS01("www.example.com", false), solver: concurrentSolver},
+		{authz: createStubAuthorizationHTTP01("a.example.net", acme.StatusProcessing), solver: sequentialSolver},
+		{authz: createStubAuthorizationHTTP01("b.example.org", acme.StatusProcessing), solver: sequentialSolver},
+	}
+
+	groups := groupByZone(authSolvers, 2)
+
+	var got [][]string
+
+	for _, group := range groups {
+		var domains []string
+		for _, authSolver := range group {
+			domains = append(domains, authSolver.authz.Identifier.Value)
+		}
+
+		got = append(got, domains)
+	}
+
+	expected := [][]string{
+		{"*.example.com", "www.example.com"},
+		{"example.org"},
+		{"a.example.net", "b.example.org"},
+	}
+
+	assert.Equal(t, expected, got)
+
+	assert.Len(t, groupByZone(authSolvers, 1), 1)
+}
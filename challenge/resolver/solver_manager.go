@@ -2,9 +2,13 @@ package resolver
 
 import (
 	"context"
+	"crypto"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
@@ -13,9 +17,11 @@ import (
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/challenge/http01"
+	"github.com/digicert/lego/v4/challenge/onioncsr01"
 	"github.com/digicert/lego/v4/challenge/tlsalpn01"
-	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/metrics"
 	"github.com/digicert/lego/v4/platform/wait"
+	"github.com/digicert/lego/v4/progress"
 )
 
 type byType []acme.Challenge
@@ -27,58 +33,264 @@ func (a byType) Less(i, j int) bool { return a[i].Type > a[j].Type }
 type SolverManager struct {
 	core    *api.Core
 	solvers map[challenge.Type]solver
+
+	// preferences are the challenge types tried first, in order.
+	preferences []challenge.Type
+
+	// domainPreferences are the challenge types allowed, in order, by domain pattern.
+	domainPreferences map[string][]challenge.Type
+}
+
+// Solver is the interface of the solvers of custom challenge types.
+//
+// A solver can also implement the optional interfaces used by the Prober:
+//   - PreSolve(authz acme.Authorization) error: called for all the authorizations before solving them.
+//   - PreSolveWithContext(ctx context.Context, authz acme.Authorization) error: like PreSolve, but it can be interrupted.
+//   - CleanUp(authz acme.Authorization) error: called for all the authorizations after solving them.
+//   - Sequential() (bool, time.Duration): the authorizations are solved one after the other, with a delay.
+//   - SolveWithContext(ctx context.Context, authz acme.Authorization) error: the solving can be interrupted.
+//   - Concurrent() bool: the authorizations can be solved concurrently (see Prober.SetConcurrentSolves).
+type Solver interface {
+	Solve(authz acme.Authorization) error
 }
 
+// ValidateFunc asks the server to validate a challenge, and waits for the result.
+// The challenge Response is sent to initiate the validation, if not nil.
+type ValidateFunc func(ctx context.Context, core *api.Core, domain string, chlg acme.Challenge) error
+
+// NewSolverFunc creates the solver of a custom challenge type.
+type NewSolverFunc func(core *api.Core, validate ValidateFunc) Solver
+
 func NewSolversManager(core *api.Core) *SolverManager {
 	return &SolverManager{
-		solvers: map[challenge.Type]solver{},
-		core:    core,
+		solvers:           map[challenge.Type]solver{},
+		domainPreferences: map[string][]challenge.Type{},
+		core:              core,
 	}
 }
 
 // SetHTTP01Provider specifies a custom provider p that can solve the given HTTP-01 challenge.
 func (c *SolverManager) SetHTTP01Provider(p challenge.Provider, opts ...http01.ChallengeOption) error {
-	c.solvers[challenge.HTTP01] = http01.NewChallenge(c.core, validate, p, opts...)
+	c.solvers[challenge.HTTP01] = http01.NewChallenge(c.core, validate, p, append([]http01.ChallengeOption{http01.SetValidateContext(validateWithContext)}, opts...)...)
 	return nil
 }
 
 // SetTLSALPN01Provider specifies a custom provider p that can solve the given TLS-ALPN-01 challenge.
 func (c *SolverManager) SetTLSALPN01Provider(p challenge.Provider, opts ...tlsalpn01.ChallengeOption) error {
-	c.solvers[challenge.TLSALPN01] = tlsalpn01.NewChallenge(c.core, validate, p, opts...)
+	c.solvers[challenge.TLSALPN01] = tlsalpn01.NewChallenge(c.core, validate, p, append([]tlsalpn01.ChallengeOption{tlsalpn01.SetValidateContext(validateWithContext)}, opts...)...)
 	return nil
 }
 
 // SetDNS01Provider specifies a custom provider p that can solve the given DNS-01 challenge.
 func (c *SolverManager) SetDNS01Provider(p challenge.Provider, opts ...dns01.ChallengeOption) error {
-	c.solvers[challenge.DNS01] = dns01.NewChallenge(c.core, validate, p, opts...)
+	c.solvers[challenge.DNS01] = dns01.NewChallenge(c.core, validate, p, append([]dns01.ChallengeOption{dns01.SetValidateContext(validateWithContext)}, opts...)...)
+	return nil
+}
+
+// SetOnionCSR01Key specifies the key of the hidden service used to solve the given ONION-CSR-01 challenge.
+// The key must be an Ed25519 key.
+func (c *SolverManager) SetOnionCSR01Key(key crypto.Signer, opts ...onioncsr01.ChallengeOption) error {
+	if _, ok := key.Public().(ed25519.PublicKey); !ok {
+		return fmt.Errorf("the key of a hidden service must be an Ed25519 key: %T", key.Public())
+	}
+
+	c.solvers[challenge.OnionCSR01] = onioncsr01.NewChallenge(c.core, validate, key, append([]onioncsr01.ChallengeOption{onioncsr01.SetValidateContext(validateWithContext)}, opts...)...)
+
+	return nil
+}
+
+// SetSolver registers the solver of a challenge type.
+// It allows to solve new or experimental challenge types, or to replace the solver of a built-in type.
+func (c *SolverManager) SetSolver(chlgType challenge.Type, newSolver NewSolverFunc) error {
+	if chlgType == "" {
+		return errors.New("the challenge type is empty")
+	}
+
+	if newSolver == nil {
+		return fmt.Errorf("%s: the solver constructor is nil", chlgType)
+	}
+
+	solvr := newSolver(c.core, validateWithContext)
+	if solvr == nil {
+		return fmt.Errorf("%s: the solver is nil", chlgType)
+	}
+
+	c.solvers[chlgType] = solvr
+
 	return nil
 }
 
+// SetChallengePreference defines the challenge types tried first, in order,
+// when several types offered by the server have a solver.
+// The other types are tried after them, in the default order.
+func (c *SolverManager) SetChallengePreference(types ...challenge.Type) {
+	c.preferences = slices.Clone(types)
+}
+
+// SetDomainChallengePreference defines the challenge types used, in order, for the domains matching a pattern.
+// The other types are not used for these domains, even if they have a solver.
+//
+// The pattern is a domain (`example.com`), a wildcard pattern matching the subdomains and the wildcard domain (`*.example.com`),
+// or `*` matching all the domains.
+// The most specific pattern is used: the domain, then the closest wildcard pattern, then `*`.
+// The domains without a matching pattern use the global preference (SetChallengePreference).
+// Without types, the pattern is removed.
+func (c *SolverManager) SetDomainChallengePreference(pattern string, types ...challenge.Type) error {
+	pattern = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(pattern), "."))
+
+	if pattern == "" {
+		return errors.New("the domain pattern is empty")
+	}
+
+	if strings.Contains(strings.TrimPrefix(pattern, "*."), "*") && pattern != "*" {
+		return fmt.Errorf("%s: invalid domain pattern: the wildcard is only allowed as the first label", pattern)
+	}
+
+	if len(types) == 0 {
+		delete(c.domainPreferences, pattern)
+		return nil
+	}
+
+	c.domainPreferences[pattern] = slices.Clone(types)
+
+	return nil
+}
+
+// domainPreference returns the challenge types of the most specific pattern matching the domain.
+func (c *SolverManager) domainPreference(domain string) ([]challenge.Type, bool) {
+	if len(c.domainPreferences) == 0 {
+		return nil, false
+	}
+
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if types, ok := c.domainPreferences[domain]; ok {
+		return types, true
+	}
+
+	for parent := domain; ; {
+		_, after, found := strings.Cut(parent, ".")
+		if !found {
+			break
+		}
+
+		if types, ok := c.domainPreferences["*."+after]; ok {
+			return types, true
+		}
+
+		parent = after
+	}
+
+	types, ok := c.domainPreferences["*"]
+
+	return types, ok
+}
+
 // Remove removes a challenge type from the available solvers.
 func (c *SolverManager) Remove(chlgType challenge.Type) {
 	delete(c.solvers, chlgType)
 }
 
+// typedSolver is a solver and the challenge type it solves.
+type typedSolver struct {
+	chlgType challenge.Type
+	solver   solver
+}
+
 // Checks all challenges from the server in order and returns the first matching solver.
 func (c *SolverManager) chooseSolver(authz acme.Authorization) solver {
+	candidates := c.chooseSolvers(authz)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return candidates[0].solver
+}
+
+// chooseSolvers checks all challenges from the server in order and returns the matching solvers, in order.
+// The first solver is used, the next ones are the fallbacks.
+func (c *SolverManager) chooseSolvers(authz acme.Authorization) []typedSolver {
 	// Allow to have a deterministic challenge order
 	sort.Sort(byType(authz.Challenges))
 
 	domain := challenge.GetTargetedDomain(authz)
-	for _, chlg := range authz.Challenges {
-		if solvr, ok := c.solvers[challenge.Type(chlg.Type)]; ok {
-			log.Infof("[%s] acme: use %s solver", domain, chlg.Type)
-			return solvr
+
+	preferences := c.preferences
+	challenges := authz.Challenges
+
+	if types, ok := c.domainPreference(domain); ok {
+		preferences = types
+		challenges = slices.DeleteFunc(slices.Clone(authz.Challenges), func(chlg acme.Challenge) bool {
+			return !slices.Contains(types, challenge.Type(chlg.Type))
+		})
+	}
+
+	if len(preferences) > 0 {
+		sort.SliceStable(challenges, func(i, j int) bool {
+			return preference(preferences, challenges[i].Type) < preference(preferences, challenges[j].Type)
+		})
+	}
+
+	var candidates []typedSolver
+
+	for _, chlg := range challenges {
+		solvr, ok := c.solvers[challenge.Type(chlg.Type)]
+		if !ok {
+			c.core.Logger().Info("acme: Could not find solver.", "domain", domain, "type", chlg.Type)
+			continue
 		}
 
-		log.Infof("[%s] acme: Could not find solver for: %s", domain, chlg.Type)
+		if len(candidates) == 0 {
+			c.core.Logger().Info("acme: use solver.", "domain", domain, "type", chlg.Type)
+		}
+
+		candidates = append(candidates, typedSolver{chlgType: challenge.Type(chlg.Type), solver: solvr})
 	}
 
-	return nil
+	return candidates
+}
+
+// preference returns the rank of a challenge type: the index in the preferences, or the number of preferences.
+func preference(preferences []challenge.Type, chlgType string) int {
+	idx := slices.Index(preferences, challenge.Type(chlgType))
+	if idx < 0 {
+		return len(preferences)
+	}
+
+	return idx
 }
 
 func validate(core *api.Core, domain string, chlg acme.Challenge) error {
-	chlng, err := core.Challenges.New(chlg.URL)
+	return validateWithContext(context.Background(), core, domain, chlg)
+}
+
+func validateWithContext(ctx context.Context, core *api.Core, domain string, chlg acme.Challenge) error {
+	start := time.Now()
+
+	err := validateChallenge(ctx, core, domain, chlg)
+
+	metrics.ObserveDuration(metrics.ChallengeDuration, start, metrics.Labels{"type": chlg.Type, "result": metrics.Result(err)})
+
+	return err
+}
+
+func validateChallenge(ctx context.Context, core *api.Core, domain string, chlg acme.Challenge) error {
+	// Don't ask the server to validate the challenge when the caller has given up.
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	var (
+		chlng acme.ExtendedChallenge
+		err   error
+	)
+
+	if chlg.Response != nil {
+		chlng, err = core.Challenges.Respond(chlg.URL, chlg.Response)
+	} else {
+		chlng, err = core.Challenges.New(chlg.URL)
+	}
+
 	if err != nil {
 		return fmt.Errorf("failed to initiate challenge: %w", err)
 	}
@@ -89,7 +301,7 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 	}
 
 	if valid {
-		log.Infof("[%s] The server validated our request", domain)
+		core.Logger().Info("The server validated our request.", "domain", domain)
 		return nil
 	}
 
@@ -102,15 +314,18 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 		retryAfter = 5 * time.Second
 	}
 
-	ctx := context.Background()
-
 	bo := backoff.NewExponentialBackOff()
 	bo.InitialInterval = retryAfter
 	bo.MaxInterval = 10 * retryAfter
 
+	var attempt int
+
 	// After the path is sent, the ACME server will access our server.
 	// Repeatedly check the server for an updated status on our request.
 	operation := func() error {
+		attempt++
+		core.Progress().Attempt(progress.PhaseValidation, domain, attempt)
+
 		authz, err := core.Authorizations.Get(chlng.AuthorizationURL)
 		if err != nil {
 			return backoff.Permanent(err)
@@ -122,7 +337,7 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 		}
 
 		if valid {
-			log.Infof("[%s] The server validated our request", domain)
+			core.Logger().Info("The server validated our request.", "domain", domain)
 			return nil
 		}
 
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/go-jose/go-jose/v4"
@@ -32,6 +34,171 @@ func TestByType(t *testing.T) {
 	assert.Equal(t, expected, challenges)
 }
 
+func TestSolverManager_SetSolver(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	manager := NewSolversManager(core)
+
+	solvr := &preSolverMock{}
+
+	err = manager.SetSolver("device-attest-01", func(c *api.Core, validate ValidateFunc) Solver {
+		assert.Same(t, core, c)
+		assert.NotNil(t, validate)
+
+		return solvr
+	})
+	require.NoError(t, err)
+
+	authz := createStubAuthorization("example.com", acme.StatusPending, false,
+		acme.Challenge{Type: "device-attest-01"}, acme.Challenge{Type: "http-01"})
+
+	assert.Same(t, solvr, manager.chooseSolver(authz))
+}
+
+func TestSolverManager_SetSolver_errors(t *testing.T) {
+	manager := NewSolversManager(nil)
+
+	err := manager.SetSolver("", func(*api.Core, ValidateFunc) Solver { return &preSolverMock{} })
+	require.EqualError(t, err, "the challenge type is empty")
+
+	err = manager.SetSolver("device-attest-01", nil)
+	require.EqualError(t, err, "device-attest-01: the solver constructor is nil")
+
+	err = manager.SetSolver("device-attest-01", func(*api.Core, ValidateFunc) Solver { return nil })
+	require.EqualError(t, err, "device-attest-01: the solver is nil")
+}
+
+func TestSolverManager_SetChallengePreference(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	dnsSolver := &preSolverMock{}
+	httpSolver := &preSolverMock{}
+
+	manager := NewSolversManager(core)
+	manager.solvers[challenge.DNS01] = dnsSolver
+	manager.solvers[challenge.HTTP01] = httpSolver
+
+	newAuthz := func() acme.Authorization {
+		return createStubAuthorization("example.com", acme.StatusPending, false,
+			acme.Challenge{Type: "dns-01"}, acme.Challenge{Type: "http-01"})
+	}
+
+	// Default order.
+	assert.Same(t, httpSolver, manager.chooseSolver(newAuthz()))
+
+	manager.SetChallengePreference(challenge.DNS01)
+	assert.Same(t, dnsSolver, manager.chooseSolver(newAuthz()))
+
+	// A preferred type without a solver is skipped.
+	manager.SetChallengePreference(challenge.TLSALPN01, challenge.DNS01)
+	assert.Same(t, dnsSolver, manager.chooseSolver(newAuthz()))
+}
+
+func TestSolverManager_SetDomainChallengePreference(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	dnsSolver := &preSolverMock{}
+	httpSolver := &preSolverMock{}
+	tlsSolver := &preSolverMock{}
+
+	manager := NewSolversManager(core)
+	manager.solvers[challenge.DNS01] = dnsSolver
+	manager.solvers[challenge.HTTP01] = httpSolver
+	manager.solvers[challenge.TLSALPN01] = tlsSolver
+
+	manager.SetChallengePreference(challenge.TLSALPN01)
+
+	require.NoError(t, manager.SetDomainChallengePreference("*.example.com", challenge.DNS01))
+	require.NoError(t, manager.SetDomainChallengePreference("www.example.com.", challenge.HTTP01, challenge.DNS01))
+	require.NoError(t, manager.SetDomainChallengePreference("*.internal.example.com", challenge.TLSALPN01))
+
+	newAuthz := func(domain string, wildcard bool) acme.Authorization {
+		return createStubAuthorization(domain, acme.StatusPending, wildcard,
+			acme.Challenge{Type: "dns-01"}, acme.Challenge{Type: "http-01"}, acme.Challenge{Type: "tls-alpn-01"})
+	}
+
+	testCases := []struct {
+		desc     string
+		authz    acme.Authorization
+		expected solver
+	}{
+		{
+			desc:     "wildcard domain",
+			authz:    newAuthz("example.com", true),
+			expected: dnsSolver,
+		},
+		{
+			desc:     "subdomain",
+			authz:    newAuthz("api.example.com", false),
+			expected: dnsSolver,
+		},
+		{
+			desc:     "exact domain",
+			authz:    newAuthz("WWW.example.com", false),
+			expected: httpSolver,
+		},
+		{
+			desc:     "closest wildcard pattern",
+			authz:    newAuthz("a.internal.example.com", false),
+			expected: tlsSolver,
+		},
+		{
+			desc:     "global preference",
+			authz:    newAuthz("example.org", false),
+			expected: tlsSolver,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Same(t, test.expected, manager.chooseSolver(test.authz))
+		})
+	}
+
+	// The types not allowed for a domain are not used.
+	authz := createStubAuthorization("www.example.com", acme.StatusPending, false, acme.Challenge{Type: "tls-alpn-01"})
+	assert.Nil(t, manager.chooseSolver(authz))
+
+	// The catch-all pattern.
+	require.NoError(t, manager.SetDomainChallengePreference("*", challenge.HTTP01))
+	assert.Same(t, httpSolver, manager.chooseSolver(newAuthz("example.org", false)))
+
+	// Without types, the pattern is removed.
+	require.NoError(t, manager.SetDomainChallengePreference("*"))
+	assert.Same(t, tlsSolver, manager.chooseSolver(newAuthz("example.org", false)))
+}
+
+func TestSolverManager_SetDomainChallengePreference_errors(t *testing.T) {
+	manager := NewSolversManager(nil)
+
+	err := manager.SetDomainChallengePreference(" ", challenge.DNS01)
+	require.EqualError(t, err, "the domain pattern is empty")
+
+	err = manager.SetDomainChallengePreference("*.*.example.com", challenge.DNS01)
+	require.EqualError(t, err, "*.*.example.com: invalid domain pattern: the wildcard is only allowed as the first label")
+
+	err = manager.SetDomainChallengePreference("www*.example.com", challenge.DNS01)
+	require.EqualError(t, err, "www*.example.com: invalid domain pattern: the wildcard is only allowed as the first label")
+}
+
 func TestValidate(t *testing.T) {
 	var statuses []string
 
@@ -129,6 +296,60 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidate_response(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	server := tester.MockACMEServer().
+		Route("POST /chlg",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := readSignedBody(privateKey, req)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				if string(body) != `{"csr":"Y3Ny"}` {
+					http.Error(rw, fmt.Sprintf("unexpected body: %s", body), http.StatusBadRequest)
+					return
+				}
+
+				chlg := &acme.Challenge{Type: "onion-csr-01", Status: acme.StatusValid, URL: "http://example.com/"}
+
+				servermock.JSONEncode(chlg).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	chlg := acme.Challenge{
+		Type:     "onion-csr-01",
+		URL:      server.URL + "/chlg",
+		Response: acme.OnionCSRResponse{CSR: "Y3Ny"},
+	}
+
+	err = validate(core, "example.onion", chlg)
+	require.NoError(t, err)
+}
+
+func TestValidateWithContext_canceled(t *testing.T) {
+	// No challenge route: the server must not be asked to validate the challenge.
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err = validateWithContext(ctx, core, "example.com", acme.Challenge{Type: "http-01", Token: "token", URL: server.URL + "/chlg"})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func Test_checkChallengeStatus(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -254,29 +475,33 @@ func Test_checkAuthorizationStatus(t *testing.T) {
 // or if the JWS body is not the empty JSON payload "{}" or a POST-as-GET payload "" an error is returned.
 // We use this to verify challenge POSTs to the ts below do not send a JWS body.
 func validateNoBody(privateKey *rsa.PrivateKey, r *http.Request) error {
-	reqBody, err := io.ReadAll(r.Body)
+	body, err := readSignedBody(privateKey, r)
 	if err != nil {
 		return err
 	}
 
+	if bodyStr := string(body); bodyStr != "{}" && bodyStr != "" {
+		return fmt.Errorf(`expected JWS POST body "{}" or "", got %q`, bodyStr)
+	}
+
+	return nil
+}
+
+func readSignedBody(privateKey *rsa.PrivateKey, r *http.Request) ([]byte, error) {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	sigAlgs := []jose.SignatureAlgorithm{jose.RS256}
 
 	jws, err := jose.ParseSigned(string(reqBody), sigAlgs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	body, err := jws.Verify(&jose.JSONWebKey{
+	return jws.Verify(&jose.JSONWebKey{
 		Key:       privateKey.Public(),
 		Algorithm: "RSA",
 	})
-	if err != nil {
-		return err
-	}
-
-	if bodyStr := string(body); bodyStr != "{}" && bodyStr != "" {
-		return fmt.Errorf(`expected JWS POST body "{}" or "", got %q`, bodyStr)
-	}
-
-	return nil
 }
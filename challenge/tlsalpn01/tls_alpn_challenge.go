@@ -1,6 +1,7 @@
 package tlsalpn01
 
 import (
+	"context"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/tls"
@@ -13,7 +14,9 @@ import (
 	"github.com/digicert/lego/v4/acme/api"
 	"github.com/digicert/lego/v4/certcrypto"
 	"github.com/digicert/lego/v4/challenge"
-	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/platform/wait"
+	"golang.org/x/net/idna"
 )
 
 // idPeAcmeIdentifierV1 is the SMI Security for PKIX Certification Extension OID referencing the ACME extension.
@@ -22,8 +25,20 @@ var idPeAcmeIdentifierV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
 
 type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
 
+// ValidateContextFunc is like ValidateFunc, but the validation is interrupted as soon as the context is done.
+type ValidateContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
 type ChallengeOption func(*Challenge) error
 
+// SetValidateContext sets the validation function used by SolveWithContext.
+// Without it, the context is only checked before the validation.
+func SetValidateContext(validate ValidateContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
 // SetDelay sets a delay between the start of the TLS listener and the challenge validation.
 func SetDelay(delay time.Duration) ChallengeOption {
 	return func(chlg *Challenge) error {
@@ -33,10 +48,11 @@ func SetDelay(delay time.Duration) ChallengeOption {
 }
 
 type Challenge struct {
-	core     *api.Core
-	validate ValidateFunc
-	provider challenge.Provider
-	delay    time.Duration
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateContextFunc
+	provider    challenge.Provider
+	delay       time.Duration
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
@@ -49,7 +65,7 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 	for _, opt := range opts {
 		err := opt(chlg)
 		if err != nil {
-			log.Infof("challenge option error: %v", err)
+			chlg.core.Logger().Warn("challenge option error.", "error", err)
 		}
 	}
 
@@ -60,10 +76,24 @@ func (c *Challenge) SetProvider(provider challenge.Provider) {
 	c.provider = provider
 }
 
+// Concurrent reports whether the challenges of several authorizations can be solved concurrently:
+// the built-in server (ProviderServer) serves one token at a time.
+func (c *Challenge) Concurrent() bool {
+	_, ok := c.provider.(*ProviderServer)
+
+	return !ok
+}
+
 // Solve manages the provider to validate and solve the challenge.
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext is like Solve, but the delay and the validation are interrupted as soon as the context is done.
+// The certificate is always cleaned up.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := authz.Identifier.Value
-	log.Infof("[%s] acme: Trying to solve TLS-ALPN-01", challenge.GetTargetedDomain(authz))
+	c.core.Logger().Info("acme: Trying to solve TLS-ALPN-01.", "domain", challenge.GetTargetedDomain(authz))
 
 	chlng, err := challenge.FindChallenge(challenge.TLSALPN01, authz)
 	if err != nil {
@@ -81,19 +111,37 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return fmt.Errorf("[%s] acme: error presenting token: %w", challenge.GetTargetedDomain(authz), err)
 	}
 
+	c.core.Events().Publish(events.ChallengePresented{Domain: challenge.GetTargetedDomain(authz), Type: string(challenge.TLSALPN01)})
+
 	defer func() {
 		err := c.provider.CleanUp(domain, chlng.Token, keyAuth)
 		if err != nil {
-			log.Warnf("[%s] acme: cleaning up failed: %v", challenge.GetTargetedDomain(authz), err)
+			c.core.Logger().Warn("acme: cleaning up failed.", "domain", challenge.GetTargetedDomain(authz), "error", err)
+			c.core.Events().Publish(events.CleanupFailed{Domain: challenge.GetTargetedDomain(authz), Type: string(challenge.TLSALPN01), Err: err})
 		}
 	}()
 
 	if c.delay > 0 {
-		time.Sleep(c.delay)
+		err = wait.Sleep(ctx, c.delay)
+		if err != nil {
+			return fmt.Errorf("[%s] acme: delay: %w", challenge.GetTargetedDomain(authz), err)
+		}
 	}
 
 	chlng.KeyAuthorization = keyAuth
 
+	return c.validateWithContext(ctx, domain, chlng)
+}
+
+func (c *Challenge) validateWithContext(ctx context.Context, domain string, chlng acme.Challenge) error {
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("[%s] acme: validation: %w", domain, context.Cause(ctx))
+	}
+
 	return c.validate(c.core, domain, chlng)
 }
 
@@ -128,6 +176,11 @@ func ChallengeBlocks(domain, keyAuth string) ([]byte, []byte, error) {
 	rsaPrivateKey := tempPrivateKey.(*rsa.PrivateKey)
 
 	// Generate the PEM certificate using the provided private key, domain, and extra extensions.
+	// The internationalized domains are converted to A-labels.
+	if ascii, errA := idna.ToASCII(domain); errA == nil {
+		domain = ascii
+	}
+
 	tempCertPEM, err := certcrypto.GeneratePemCert(rsaPrivateKey, domain, extensions)
 	if err != nil {
 		return nil, nil, err
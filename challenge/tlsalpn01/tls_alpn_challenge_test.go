@@ -197,3 +197,10 @@ func TestChallengeIPaddress(t *testing.T) {
 
 	require.NoError(t, solver.Solve(authz))
 }
+
+func TestChallengeCert_IDN(t *testing.T) {
+	cert, err := ChallengeCert("münchen.example", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"xn--mnchen-3ya.example"}, cert.Leaf.DNSNames)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/digicert/lego/v4/registration"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	accountBundleVersion = 1
+	accountBundleKDF     = "scrypt"
+)
+
+// The scrypt parameters recommended for interactive logins (2^15).
+const (
+	scryptN      = 32768
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// accountBundle is the content of an exported account.
+type accountBundle struct {
+	Server       string                 `json:"server"`
+	Email        string                 `json:"email,omitempty"`
+	Registration *registration.Resource `json:"registration"`
+	// Key the private key of the account (PEM).
+	Key        string    `json:"key"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// encryptedAccountBundle is the portable file: the bundle encrypted with AES-GCM,
+// with a key derived from the passphrase by scrypt.
+type encryptedAccountBundle struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+func encryptAccountBundle(bundle *accountBundle, passphrase string) ([]byte, error) {
+	plaintext, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newBundleCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(encryptedAccountBundle{
+		Version: accountBundleVersion,
+		KDF:     accountBundleKDF,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    aead.Seal(nil, nonce, plaintext, nil),
+	}, "", "\t")
+}
+
+func decryptAccountBundle(data []byte, passphrase string) (*accountBundle, error) {
+	var encrypted encryptedAccountBundle
+
+	err := json.Unmarshal(data, &encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account bundle: %w", err)
+	}
+
+	if encrypted.Version != accountBundleVersion || encrypted.KDF != accountBundleKDF {
+		return nil, fmt.Errorf("unsupported account bundle (version: %d, kdf: %q)", encrypted.Version, encrypted.KDF)
+	}
+
+	aead, err := newBundleCipher(passphrase, encrypted.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(encrypted.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid account bundle: invalid nonce")
+	}
+
+	plaintext, err := aead.Open(nil, encrypted.Nonce, encrypted.Data, nil)
+	if err != nil {
+		return nil, errors.New("could not decrypt the account bundle: wrong passphrase or corrupted bundle")
+	}
+
+	var bundle accountBundle
+
+	err = json.Unmarshal(plaintext, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account bundle: %w", err)
+	}
+
+	return &bundle, nil
+}
+
+func newBundleCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("the passphrase is empty")
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -1,9 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"crypto"
 	"encoding/json"
-	"encoding/pem"
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/digicert/lego/v4/lego"
 	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/registration"
+	"github.com/digicert/lego/v4/storage"
 	"github.com/urfave/cli/v2"
 )
 
@@ -64,6 +66,7 @@ type AccountsStorage struct {
 	rootUserPath    string
 	keysPath        string
 	accountFilePath string
+	backend         storage.Storage
 	ctx             *cli.Context
 }
 
@@ -94,19 +97,18 @@ func NewAccountsStorage(ctx *cli.Context) *AccountsStorage {
 		rootUserPath:    rootUserPath,
 		keysPath:        filepath.Join(rootUserPath, baseKeysFolderName),
 		accountFilePath: filepath.Join(rootUserPath, accountFileName),
+		backend:         newStorage(ctx),
 		ctx:             ctx,
 	}
 }
 
 func (s *AccountsStorage) ExistsAccountFilePath() bool {
-	accountFile := filepath.Join(s.rootUserPath, accountFileName)
-	if _, err := os.Stat(accountFile); os.IsNotExist(err) {
-		return false
-	} else if err != nil {
+	exists, err := s.backend.Exists(context.Background(), s.accountFilePath)
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	return true
+	return exists
 }
 
 func (s *AccountsStorage) GetRootPath() string {
@@ -131,11 +133,11 @@ func (s *AccountsStorage) Save(account *Account) error {
 		return err
 	}
 
-	return os.WriteFile(s.accountFilePath, jsonBytes, filePerm)
+	return s.backend.WriteFile(context.Background(), s.accountFilePath, jsonBytes)
 }
 
 func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
-	fileBytes, err := os.ReadFile(s.accountFilePath)
+	fileBytes, err := s.backend.ReadFile(context.Background(), s.accountFilePath)
 	if err != nil {
 		log.Fatalf("Could not load file for account %s: %v", s.GetUserID(), err)
 	}
@@ -167,13 +169,26 @@ func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
 }
 
 func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.PrivateKey {
-	accKeyPath := filepath.Join(s.keysPath, s.GetUserID()+".key")
+	if keyURI := s.ctx.String(flgAccountKeyURI); keyURI != "" {
+		privateKey, err := certcrypto.LoadKey(context.Background(), keyURI)
+		if err != nil {
+			log.Fatalf("Could not load the private key of the account %s: %v", s.GetUserID(), err)
+		}
+
+		return privateKey
+	}
+
+	accKeyPath := s.getPrivateKeyPath()
 
-	if _, err := os.Stat(accKeyPath); os.IsNotExist(err) {
+	exists, err := s.backend.Exists(context.Background(), accKeyPath)
+	if err != nil {
+		log.Fatalf("Could not check the private key of the account %s: %v", s.GetUserID(), err)
+	}
+
+	if !exists {
 		log.Printf("No key found for account %s. Generating a %s key.", s.GetUserID(), keyType)
-		s.createKeysFolder()
 
-		privateKey, err := generatePrivateKey(accKeyPath, keyType)
+		privateKey, err := s.generatePrivateKey(accKeyPath, keyType)
 		if err != nil {
 			log.Fatalf("Could not generate RSA private account key for account %s: %v", s.GetUserID(), err)
 		}
@@ -183,7 +198,12 @@ func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.Priva
 		return privateKey
 	}
 
-	privateKey, err := loadPrivateKey(accKeyPath)
+	keyBytes, err := s.backend.ReadFile(context.Background(), accKeyPath)
+	if err != nil {
+		log.Fatalf("Could not load RSA private key from file %s: %v", accKeyPath, err)
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(keyBytes)
 	if err != nil {
 		log.Fatalf("Could not load RSA private key from file %s: %v", accKeyPath, err)
 	}
@@ -191,35 +211,40 @@ func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.Priva
 	return privateKey
 }
 
-func (s *AccountsStorage) createKeysFolder() {
-	if err := createNonExistingFolder(s.keysPath); err != nil {
-		log.Fatalf("Could not check/create directory for account %s: %v", s.GetUserID(), err)
-	}
+// ReadPrivateKey reads the private key of the account (PEM).
+func (s *AccountsStorage) ReadPrivateKey() ([]byte, error) {
+	return s.backend.ReadFile(context.Background(), s.getPrivateKeyPath())
 }
 
-func generatePrivateKey(file string, keyType certcrypto.KeyType) (crypto.PrivateKey, error) {
-	privateKey, err := certcrypto.GeneratePrivateKey(keyType)
-	if err != nil {
-		return nil, err
-	}
+// SavePrivateKey saves the private key of the account.
+func (s *AccountsStorage) SavePrivateKey(privateKey crypto.PrivateKey) error {
+	return s.backend.WriteFile(context.Background(), s.getPrivateKeyPath(), certcrypto.PEMEncode(privateKey))
+}
 
-	certOut, err := os.Create(file)
+func (s *AccountsStorage) getPrivateKeyPath() string {
+	return filepath.Join(s.keysPath, s.GetUserID()+".key")
+}
+
+func (s *AccountsStorage) generatePrivateKey(name string, keyType certcrypto.KeyType) (crypto.PrivateKey, error) {
+	privateKey, err := certcrypto.GeneratePrivateKey(keyType)
 	if err != nil {
 		return nil, err
 	}
-	defer certOut.Close()
-
-	pemKey := certcrypto.PEMBlock(privateKey)
 
-	err = pem.Encode(certOut, pemKey)
+	err = s.backend.WriteFile(context.Background(), name, certcrypto.PEMEncode(privateKey))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("could not save the private key: %w", err)
 	}
 
 	return privateKey, nil
 }
 
+// loadPrivateKey loads a private key from a PEM file, or from a key URI (ex: `tpm:name=lego-cert`).
 func loadPrivateKey(file string) (crypto.PrivateKey, error) {
+	if certcrypto.IsKeyURI(file) {
+		return certcrypto.LoadKey(context.Background(), file)
+	}
+
 	keyBytes, err := os.ReadFile(file)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/digicert/lego/v4/ca"
+	"github.com/digicert/lego/v4/lego"
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/registration"
+	"github.com/urfave/cli/v2"
+)
+
+// getCAPreset returns the CA preset selected by --ca (or --digicert.api-key), nil if there is none.
+func getCAPreset(ctx *cli.Context) *ca.Preset {
+	name := ctx.String(flgCA)
+	if name == "" && ctx.IsSet(flgDigiCertAPIKey) {
+		name = ca.DigiCert
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	preset, err := ca.Get(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return preset
+}
+
+// setupCAServer uses the directory of the CA preset as server, if the server is not explicitly defined.
+func setupCAServer(ctx *cli.Context) error {
+	preset := getCAPreset(ctx)
+	if preset == nil || ctx.IsSet(flgServer) {
+		return nil
+	}
+
+	dirURL, err := preset.Directory(ctx.Bool(flgCAStaging))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Set(flgServer, dirURL)
+}
+
+// canFetchEAB checks if the EAB credentials can be fetched from the API of the CA.
+func canFetchEAB(ctx *cli.Context) bool {
+	preset := getCAPreset(ctx)
+
+	return preset != nil && preset.SupportsEAB()
+}
+
+// registerWithCAPreset fetches new EAB credentials from the API of the CA, then registers the account.
+func registerWithCAPreset(ctx *cli.Context, client *lego.Client, preset *ca.Preset, accepted bool) (*registration.Resource, error) {
+	apiKey := ctx.String(flgCAAPIKey)
+	if apiKey == "" {
+		apiKey = ctx.String(flgDigiCertAPIKey)
+	}
+
+	eab, err := preset.ExternalAccountBinding(ctx.Context, ctx.Bool(flgCAStaging), ca.EABOptions{
+		APIKey:       apiKey,
+		Email:        ctx.String(flgEmail),
+		Organization: ctx.String(flgDigiCertOrganization),
+		Product:      ctx.String(flgDigiCertProduct),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("EAB credentials created by %s (key ID: %s).", preset.Name, eab.KeyID)
+
+	return client.Registration.RegisterWithExternalAccountBinding(registration.RegisterEABOptions{
+		TermsOfServiceAgreed: accepted,
+		Kid:                  eab.KeyID,
+		HmacEncoded:          eab.HMACKey,
+	})
+}
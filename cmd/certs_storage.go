@@ -2,13 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/digicert/lego/v4/certcrypto"
 	"github.com/digicert/lego/v4/certificate"
 	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/storage"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/net/idna"
 	"software.sslmate.com/src/go-pkcs12"
@@ -56,6 +58,37 @@ type CertificatesStorage struct {
 	pfxPassword string
 	pfxFormat   string
 	filename    string // Deprecated
+	backend     storage.Storage
+
+	// server, account, and options are recorded in the metadata of the certificates.
+	server  string
+	account string
+	options certificateOptions
+}
+
+// certificateMetadata is the content of the resource file of a certificate.
+// It records the CA directory and the account which issued the certificate,
+// so the certificate can be renewed or revoked with them.
+type certificateMetadata struct {
+	certificate.Resource
+
+	Server  string `json:"server,omitempty"`
+	Account string `json:"account,omitempty"`
+
+	Options *certificateOptions `json:"options,omitempty"`
+}
+
+// certificateOptions are the domains and the options used to obtain a certificate.
+// They are reused to renew or revoke the certificate by its name (`--name`).
+type certificateOptions struct {
+	Domains        []string `json:"domains,omitempty"`
+	KeyType        string   `json:"keyType,omitempty"`
+	MustStaple     bool     `json:"mustStaple,omitempty"`
+	PreferredChain string   `json:"preferredChain,omitempty"`
+	Profile        string   `json:"profile,omitempty"`
+	HTTP           bool     `json:"http,omitempty"`
+	TLS            bool     `json:"tls,omitempty"`
+	DNS            string   `json:"dns,omitempty"`
 }
 
 // NewCertificatesStorage create a new certificates storage.
@@ -76,20 +109,18 @@ func NewCertificatesStorage(ctx *cli.Context) *CertificatesStorage {
 		pfxPassword: ctx.String(flgPFXPass),
 		pfxFormat:   pfxFormat,
 		filename:    ctx.String(flgFilename),
-	}
-}
-
-func (s *CertificatesStorage) CreateRootFolder() {
-	err := createNonExistingFolder(s.rootPath)
-	if err != nil {
-		log.Fatalf("Could not check/create path: %v", err)
-	}
-}
-
-func (s *CertificatesStorage) CreateArchiveFolder() {
-	err := createNonExistingFolder(s.archivePath)
-	if err != nil {
-		log.Fatalf("Could not check/create path: %v", err)
+		backend:     newStorage(ctx),
+		server:      ctx.String(flgServer),
+		account:     ctx.String(flgEmail),
+		options: certificateOptions{
+			KeyType:        ctx.String(flgKeyType),
+			MustStaple:     ctx.Bool(flgMustStaple),
+			PreferredChain: ctx.String(flgPreferredChain),
+			Profile:        ctx.String(flgProfile),
+			HTTP:           ctx.Bool(flgHTTP),
+			TLS:            ctx.Bool(flgTLS),
+			DNS:            ctx.String(flgDNS),
+		},
 	}
 }
 
@@ -125,7 +156,14 @@ func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
 		log.Fatalf("Unable to save PEM or PFX without private key for domain %s. Are you using a CSR?", domain)
 	}
 
-	jsonBytes, err := json.MarshalIndent(certRes, "", "\t")
+	metadata := certificateMetadata{
+		Resource: *certRes,
+		Server:   s.server,
+		Account:  s.account,
+		Options:  s.certificateOptions(certRes),
+	}
+
+	jsonBytes, err := json.MarshalIndent(metadata, "", "\t")
 	if err != nil {
 		log.Fatalf("Unable to marshal CertResource for domain %s\n\t%v", domain, err)
 	}
@@ -136,41 +174,197 @@ func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
 	}
 }
 
+// certificateOptions returns the options of the certificate, with the domains of the certificate.
+// The main domain is the first one.
+func (s *CertificatesStorage) certificateOptions(certRes *certificate.Resource) *certificateOptions {
+	options := s.options
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return &options
+	}
+
+	options.Domains = append([]string{certRes.Domain},
+		slices.DeleteFunc(certcrypto.ExtractDomains(cert), func(domain string) bool { return domain == certRes.Domain })...)
+
+	// The domains are recorded with U-labels, they are converted to A-labels when the certificate is renewed.
+	for i, domain := range options.Domains {
+		if unicode, err := idna.ToUnicode(domain); err == nil {
+			options.Domains[i] = unicode
+		}
+	}
+
+	return &options
+}
+
 func (s *CertificatesStorage) ReadResource(domain string) certificate.Resource {
-	raw, err := s.ReadFile(domain, resourceExt)
+	metadata, err := s.ReadMetadata(domain)
 	if err != nil {
 		log.Fatalf("Error while loading the meta data for domain %s\n\t%v", domain, err)
 	}
 
-	var resource certificate.Resource
-	if err = json.Unmarshal(raw, &resource); err != nil {
-		log.Fatalf("Error while marshaling the meta data for domain %s\n\t%v", domain, err)
+	return metadata.Resource
+}
+
+// ReadMetadata reads the resource file of a certificate.
+func (s *CertificatesStorage) ReadMetadata(domain string) (*certificateMetadata, error) {
+	raw, err := s.ReadFile(domain, resourceExt)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata certificateMetadata
+	if err = json.Unmarshal(raw, &metadata); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
 	}
 
-	return resource
+	return &metadata, nil
 }
 
-func (s *CertificatesStorage) ExistsFile(domain, extension string) bool {
-	filePath := s.GetFileName(domain, extension)
+// useCertificateOrigin uses the CA directory and the account recorded in the metadata of the certificate,
+// if the server and the email are not explicitly defined.
+// It returns false if the certificate was issued by another CA than the explicitly defined one.
+// The certificates issued by older versions of lego have no recorded origin.
+func useCertificateOrigin(ctx *cli.Context, certsStorage *CertificatesStorage, domain string) bool {
+	exists, err := certsStorage.backend.Exists(context.Background(), certsStorage.GetFileName(domain, resourceExt))
+	if err != nil || !exists {
+		return true
+	}
+
+	metadata, err := certsStorage.ReadMetadata(domain)
+	if err != nil {
+		log.Warnf("[%s] Unable to read the metadata of the certificate: %v", domain, err)
+		return true
+	}
+
+	if metadata.Account != "" {
+		if !ctx.IsSet(flgEmail) {
+			err = ctx.Set(flgEmail, metadata.Account)
+			if err != nil {
+				log.Fatalf("[%s] Unable to use the account of the certificate: %v", domain, err)
+			}
+
+			certsStorage.account = metadata.Account
+		} else if ctx.String(flgEmail) != metadata.Account {
+			log.Warnf("[%s] The certificate was issued to the account %s, not to %s.", domain, metadata.Account, ctx.String(flgEmail))
+		}
+	}
+
+	if metadata.Server == "" {
+		return true
+	}
+
+	if !ctx.IsSet(flgServer) {
+		err = ctx.Set(flgServer, metadata.Server)
+		if err != nil {
+			log.Fatalf("[%s] Unable to use the CA of the certificate: %v", domain, err)
+		}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return false
-	} else if err != nil {
+		certsStorage.server = metadata.Server
+
+		return true
+	}
+
+	return sameServer(ctx.String(flgServer), metadata.Server)
+}
+
+// readCertificateName reads the metadata of the certificate named by `--name`.
+func readCertificateName(ctx *cli.Context, certsStorage *CertificatesStorage) (*certificateMetadata, error) {
+	name := ctx.String(flgName)
+
+	metadata, err := certsStorage.ReadMetadata(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the metadata of the certificate %s: %w", name, err)
+	}
+
+	return metadata, nil
+}
+
+// useCertificateOptions uses the domains and the options recorded in the metadata of a certificate,
+// if they are not explicitly defined.
+// The challenges are used only if no challenge is explicitly defined.
+func useCertificateOptions(ctx *cli.Context, metadata *certificateMetadata) error {
+	options := metadata.Options
+	if options == nil || len(options.Domains) == 0 {
+		return fmt.Errorf("the certificate %s has no recorded domains: use --%s", metadata.Domain, flgDomains)
+	}
+
+	for _, domain := range options.Domains {
+		err := ctx.Set(flgDomains, domain)
+		if err != nil {
+			return err
+		}
+	}
+
+	values := map[string]string{
+		flgKeyType:        options.KeyType,
+		flgMustStaple:     strconv.FormatBool(options.MustStaple),
+		flgPreferredChain: options.PreferredChain,
+		flgProfile:        options.Profile,
+	}
+
+	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) && !ctx.IsSet(flgOnion) {
+		values[flgHTTP] = strconv.FormatBool(options.HTTP)
+		values[flgTLS] = strconv.FormatBool(options.TLS)
+		values[flgDNS] = options.DNS
+	}
+
+	for flag, value := range values {
+		if value == "" || ctx.IsSet(flag) {
+			continue
+		}
+
+		err := ctx.Set(flag, value)
+		if err != nil {
+			return fmt.Errorf("unable to set --%s: %w", flag, err)
+		}
+	}
+
+	return nil
+}
+
+// sameServer compares two ACME directory URLs.
+func sameServer(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+func (s *CertificatesStorage) ExistsFile(domain, extension string) bool {
+	exists, err := s.backend.Exists(context.Background(), s.GetFileName(domain, extension))
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	return true
+	return exists
 }
 
 func (s *CertificatesStorage) ReadFile(domain, extension string) ([]byte, error) {
-	return os.ReadFile(s.GetFileName(domain, extension))
+	return s.backend.ReadFile(context.Background(), s.GetFileName(domain, extension))
 }
 
 func (s *CertificatesStorage) GetFileName(domain, extension string) string {
-	filename := sanitizedDomain(domain) + extension
+	filename := s.baseFileName(domain) + extension
 	return filepath.Join(s.rootPath, filename)
 }
 
+// baseFileName returns the name of the files of a certificate, without extension.
+// The files written with A-labels by the previous versions are still used (ex: `xn--mnchen-3ya.example.crt`),
+// the new files use U-labels (ex: `münchen.example.crt`).
+func (s *CertificatesStorage) baseFileName(domain string) string {
+	name := sanitizedDomain(domain)
+
+	legacy := legacySanitizedDomain(domain)
+	if legacy == name {
+		return name
+	}
+
+	exists, err := s.backend.Exists(context.Background(), filepath.Join(s.rootPath, legacy+certExt))
+	if err == nil && exists {
+		return legacy
+	}
+
+	return name
+}
+
 func (s *CertificatesStorage) ReadCertificate(domain, extension string) ([]*x509.Certificate, error) {
 	content, err := s.ReadFile(domain, extension)
 	if err != nil {
@@ -186,12 +380,12 @@ func (s *CertificatesStorage) WriteFile(domain, extension string, data []byte) e
 	if s.filename != "" {
 		baseFileName = s.filename
 	} else {
-		baseFileName = sanitizedDomain(domain)
+		baseFileName = s.baseFileName(domain)
 	}
 
 	filePath := filepath.Join(s.rootPath, baseFileName+extension)
 
-	return os.WriteFile(filePath, data, filePerm)
+	return s.backend.WriteFile(context.Background(), filePath, data)
 }
 
 func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *certificate.Resource) error {
@@ -218,48 +412,65 @@ func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *cert
 }
 
 func (s *CertificatesStorage) WritePFXFile(domain string, certRes *certificate.Resource) error {
+	pfxBytes, err := s.EncodePFX(domain, certRes)
+	if err != nil {
+		return err
+	}
+
+	return s.WriteFile(domain, pfxExt, pfxBytes)
+}
+
+// EncodePFX encodes the certificate, its chain, and its private key to PKCS#12,
+// with the password and the format of the storage.
+func (s *CertificatesStorage) EncodePFX(domain string, certRes *certificate.Resource) ([]byte, error) {
 	certPemBlock, _ := pem.Decode(certRes.Certificate)
 	if certPemBlock == nil {
-		return fmt.Errorf("unable to parse Certificate for domain %s", domain)
+		return nil, fmt.Errorf("unable to parse Certificate for domain %s", domain)
 	}
 
 	cert, err := x509.ParseCertificate(certPemBlock.Bytes)
 	if err != nil {
-		return fmt.Errorf("unable to load Certificate for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to load Certificate for domain %s: %w", domain, err)
 	}
 
 	certChain, err := getCertificateChain(certRes)
 	if err != nil {
-		return fmt.Errorf("unable to get certificate chain for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to get certificate chain for domain %s: %w", domain, err)
 	}
 
 	privateKey, err := certcrypto.ParsePEMPrivateKey(certRes.PrivateKey)
 	if err != nil {
-		return fmt.Errorf("unable to parse PrivateKey for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to parse PrivateKey for domain %s: %w", domain, err)
 	}
 
 	encoder, err := getPFXEncoder(s.pfxFormat)
 	if err != nil {
-		return fmt.Errorf("PFX encoder: %w", err)
+		return nil, fmt.Errorf("PFX encoder: %w", err)
 	}
 
 	pfxBytes, err := encoder.Encode(privateKey, cert, certChain, s.pfxPassword)
 	if err != nil {
-		return fmt.Errorf("unable to encode PFX data for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to encode PFX data for domain %s: %w", domain, err)
 	}
 
-	return s.WriteFile(domain, pfxExt, pfxBytes)
+	return pfxBytes, nil
 }
 
 func (s *CertificatesStorage) MoveToArchive(domain string) error {
-	baseFilename := filepath.Join(s.rootPath, sanitizedDomain(domain))
+	ctx := context.Background()
 
-	matches, err := filepath.Glob(baseFilename + ".*")
+	baseFilename := filepath.Join(s.rootPath, s.baseFileName(domain))
+
+	names, err := s.backend.List(ctx, s.rootPath)
 	if err != nil {
 		return err
 	}
 
-	for _, oldFile := range matches {
+	for _, oldFile := range names {
+		if filepath.Dir(oldFile) != filepath.Clean(s.rootPath) {
+			continue
+		}
+
 		if strings.TrimSuffix(oldFile, filepath.Ext(oldFile)) != baseFilename && oldFile != baseFilename+issuerExt {
 			continue
 		}
@@ -268,7 +479,7 @@ func (s *CertificatesStorage) MoveToArchive(domain string) error {
 		filename := date + "." + filepath.Base(oldFile)
 		newFile := filepath.Join(s.archivePath, filename)
 
-		err = os.Rename(oldFile, newFile)
+		err = s.backend.Rename(ctx, oldFile, newFile)
 		if err != nil {
 			return err
 		}
@@ -316,7 +527,18 @@ func getPFXEncoder(pfxFormat string) (*pkcs12.Encoder, error) {
 }
 
 // sanitizedDomain Make sure no funny chars are in the cert names (like wildcards ;)).
+// The internationalized domains are kept with U-labels.
 func sanitizedDomain(domain string) string {
+	safe, err := idna.ToUnicode(strings.NewReplacer(":", "-", "*", "_").Replace(domain))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return safe
+}
+
+// legacySanitizedDomain returns the name used by the previous versions (A-labels).
+func legacySanitizedDomain(domain string) string {
 	safe, err := idna.ToASCII(strings.NewReplacer(":", "-", "*", "_").Replace(domain))
 	if err != nil {
 		log.Fatal(err)
@@ -1,13 +1,24 @@
 package cmd
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"math/big"
 	"os"
 	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
 )
 
 func TestCertificatesStorage_MoveToArchive(t *testing.T) {
@@ -16,6 +27,7 @@ func TestCertificatesStorage_MoveToArchive(t *testing.T) {
 	storage := CertificatesStorage{
 		rootPath:    t.TempDir(),
 		archivePath: t.TempDir(),
+		backend:     storage.NewFileSystem(),
 	}
 
 	domainFiles := generateTestFiles(t, storage.rootPath, domain)
@@ -44,6 +56,7 @@ func TestCertificatesStorage_MoveToArchive_noFileRelatedToDomain(t *testing.T) {
 	storage := CertificatesStorage{
 		rootPath:    t.TempDir(),
 		archivePath: t.TempDir(),
+		backend:     storage.NewFileSystem(),
 	}
 
 	domainFiles := generateTestFiles(t, storage.rootPath, "example.org")
@@ -71,6 +84,7 @@ func TestCertificatesStorage_MoveToArchive_ambiguousDomain(t *testing.T) {
 	storage := CertificatesStorage{
 		rootPath:    t.TempDir(),
 		archivePath: t.TempDir(),
+		backend:     storage.NewFileSystem(),
 	}
 
 	domainFiles := generateTestFiles(t, storage.rootPath, domain)
@@ -113,3 +127,248 @@ func generateTestFiles(t *testing.T, dir, domain string) []string {
 
 	return filenames
 }
+
+func TestCertificatesStorage_ReadMetadata(t *testing.T) {
+	certsStorage := &CertificatesStorage{
+		rootPath: t.TempDir(),
+		backend:  storage.NewFileSystem(),
+		server:   "https://ca.example.com/directory",
+		account:  "user@example.com",
+	}
+
+	err := os.WriteFile(certsStorage.GetFileName("example.com", resourceExt),
+		[]byte(`{"domain":"example.com","certUrl":"https://ca.example.com/cert/1","server":"https://ca.example.com/directory","account":"user@example.com"}`), 0o600)
+	require.NoError(t, err)
+
+	metadata, err := certsStorage.ReadMetadata("example.com")
+	require.NoError(t, err)
+
+	expected := &certificateMetadata{
+		Resource: certificate.Resource{
+			Domain:  "example.com",
+			CertURL: "https://ca.example.com/cert/1",
+		},
+		Server:  "https://ca.example.com/directory",
+		Account: "user@example.com",
+	}
+
+	assert.Equal(t, expected, metadata)
+}
+
+func Test_useCertificateOrigin(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []string
+		metadata string
+		expected bool
+		server   string
+		email    string
+	}{
+		{
+			desc:     "recorded origin",
+			metadata: `{"domain":"example.com","server":"https://ca.example.com/directory","account":"user@example.com"}`,
+			expected: true,
+			server:   "https://ca.example.com/directory",
+			email:    "user@example.com",
+		},
+		{
+			desc:     "no recorded origin",
+			metadata: `{"domain":"example.com"}`,
+			expected: true,
+			server:   "https://acme-v02.api.letsencrypt.org/directory",
+		},
+		{
+			desc:     "no metadata",
+			expected: true,
+			server:   "https://acme-v02.api.letsencrypt.org/directory",
+		},
+		{
+			desc:     "same explicit server",
+			args:     []string{"--server", "https://ca.example.com/directory/", "--email", "other@example.com"},
+			metadata: `{"domain":"example.com","server":"https://ca.example.com/directory","account":"user@example.com"}`,
+			expected: true,
+			server:   "https://ca.example.com/directory/",
+			email:    "other@example.com",
+		},
+		{
+			desc:     "other explicit server",
+			args:     []string{"--server", "https://other.example.com/directory"},
+			metadata: `{"domain":"example.com","server":"https://ca.example.com/directory","account":"user@example.com"}`,
+			expected: false,
+			server:   "https://other.example.com/directory",
+			email:    "user@example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			certsStorage := &CertificatesStorage{
+				rootPath: t.TempDir(),
+				backend:  storage.NewFileSystem(),
+			}
+
+			if test.metadata != "" {
+				err := os.WriteFile(certsStorage.GetFileName("example.com", resourceExt), []byte(test.metadata), 0o600)
+				require.NoError(t, err)
+			}
+
+			ctx := newTestContext(t, []cli.Flag{
+				&cli.StringFlag{Name: flgServer, Value: "https://acme-v02.api.letsencrypt.org/directory"},
+				&cli.StringFlag{Name: flgEmail},
+			}, test.args...)
+
+			ok := useCertificateOrigin(ctx, certsStorage, "example.com")
+			assert.Equal(t, test.expected, ok)
+
+			assert.Equal(t, test.server, ctx.String(flgServer))
+			assert.Equal(t, test.email, ctx.String(flgEmail))
+		})
+	}
+}
+
+func TestCertificatesStorage_SaveResource_options(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"www.example.com", "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	certsStorage := &CertificatesStorage{
+		rootPath: t.TempDir(),
+		backend:  storage.NewFileSystem(),
+		options: certificateOptions{
+			KeyType: "ec384",
+			Profile: "shortlived",
+			DNS:     "manual",
+		},
+	}
+
+	certsStorage.SaveResource(&certificate.Resource{Domain: "example.com", Certificate: cert})
+
+	metadata, err := certsStorage.ReadMetadata("example.com")
+	require.NoError(t, err)
+
+	expected := &certificateOptions{
+		Domains: []string{"example.com", "www.example.com"},
+		KeyType: "ec384",
+		Profile: "shortlived",
+		DNS:     "manual",
+	}
+
+	assert.Equal(t, expected, metadata.Options)
+}
+
+func TestCertificatesStorage_GetFileName_IDN(t *testing.T) {
+	certsStorage := &CertificatesStorage{
+		rootPath: t.TempDir(),
+		backend:  storage.NewFileSystem(),
+	}
+
+	// The new files use U-labels.
+	assert.Equal(t, filepath.Join(certsStorage.rootPath, "_.münchen.example.crt"), certsStorage.GetFileName("*.xn--mnchen-3ya.example", certExt))
+	assert.Equal(t, filepath.Join(certsStorage.rootPath, "_.münchen.example.crt"), certsStorage.GetFileName("*.münchen.example", certExt))
+
+	// The files written with A-labels by the previous versions are still used.
+	legacy := filepath.Join(certsStorage.rootPath, "_.xn--mnchen-3ya.example.crt")
+	require.NoError(t, os.WriteFile(legacy, []byte("cert"), 0o600))
+
+	assert.Equal(t, legacy, certsStorage.GetFileName("*.münchen.example", certExt))
+}
+
+func Test_useCertificateOptions(t *testing.T) {
+	metadata := &certificateMetadata{
+		Resource: certificate.Resource{Domain: "example.com"},
+		Options: &certificateOptions{
+			Domains:    []string{"example.com", "www.example.com"},
+			KeyType:    "ec384",
+			MustStaple: true,
+			Profile:    "shortlived",
+			HTTP:       true,
+			DNS:        "manual",
+		},
+	}
+
+	testCases := []struct {
+		desc    string
+		args    []string
+		keyType string
+		profile string
+		http    bool
+		dns     string
+	}{
+		{
+			desc:    "recorded options",
+			keyType: "ec384",
+			profile: "shortlived",
+			http:    true,
+			dns:     "manual",
+		},
+		{
+			desc:    "explicit options",
+			args:    []string{"--key-type", "rsa2048", "--profile", "classic", "--tls"},
+			keyType: "rsa2048",
+			profile: "classic",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := newTestContext(t, []cli.Flag{
+				&cli.StringSliceFlag{Name: flgDomains},
+				&cli.StringFlag{Name: flgKeyType, Value: "ec256"},
+				&cli.BoolFlag{Name: flgMustStaple},
+				&cli.StringFlag{Name: flgPreferredChain},
+				&cli.StringFlag{Name: flgProfile},
+				&cli.BoolFlag{Name: flgHTTP},
+				&cli.BoolFlag{Name: flgTLS},
+				&cli.StringFlag{Name: flgDNS},
+				&cli.StringFlag{Name: flgOnion},
+			}, test.args...)
+
+			err := useCertificateOptions(ctx, metadata)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"example.com", "www.example.com"}, ctx.StringSlice(flgDomains))
+			assert.True(t, ctx.Bool(flgMustStaple))
+			assert.Equal(t, test.keyType, ctx.String(flgKeyType))
+			assert.Equal(t, test.profile, ctx.String(flgProfile))
+			assert.Equal(t, test.http, ctx.Bool(flgHTTP))
+			assert.Equal(t, test.dns, ctx.String(flgDNS))
+		})
+	}
+}
+
+func Test_useCertificateOptions_noOptions(t *testing.T) {
+	ctx := newTestContext(t, []cli.Flag{&cli.StringSliceFlag{Name: flgDomains}})
+
+	err := useCertificateOptions(ctx, &certificateMetadata{Resource: certificate.Resource{Domain: "example.com"}})
+	require.EqualError(t, err, "the certificate example.com has no recorded domains: use --domains")
+}
+
+func newTestContext(t *testing.T, flags []cli.Flag, args ...string) *cli.Context {
+	t.Helper()
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	for _, f := range flags {
+		require.NoError(t, f.Apply(set))
+	}
+
+	require.NoError(t, set.Parse(args))
+
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
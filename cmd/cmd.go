@@ -9,6 +9,11 @@ func CreateCommands() []*cli.Command {
 		createRevoke(),
 		createRenew(),
 		createDNSHelp(),
+		createDNS(),
+		createAccount(),
 		createList(),
+		createStorage(),
+		createCerts(),
+		createServeAPI(),
 	}
 }
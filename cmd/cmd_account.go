@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgAccountContact    = "contact"
+	flgAccountNoContact  = "no-contact"
+	flgAccountFile       = "file"
+	flgAccountPassphrase = "passphrase"
+	flgAccountOverwrite  = "overwrite"
+)
+
+func createAccount() *cli.Command {
+	return &cli.Command{
+		Name:  "account",
+		Usage: "Manage the ACME account.",
+		Subcommands: []*cli.Command{
+			{
+				Name: "update",
+				Usage: "Update the contacts of the account (the email addresses used by the CA to send the expiration notices)." +
+					" The account is defined by --email and --server.",
+				Action: accountUpdate,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  flgAccountContact,
+						Usage: "An email address of the account. Can be specified multiple times. Replaces the current contacts.",
+					},
+					&cli.BoolFlag{
+						Name:  flgAccountNoContact,
+						Usage: "Remove all the contacts of the account.",
+					},
+				},
+			},
+			{
+				Name: "export",
+				Usage: "Export the account (private key, registration, and metadata) to an encrypted bundle." +
+					" The account is defined by --email and --server.",
+				Action: accountExport,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     flgAccountFile,
+						Usage:    "The path of the bundle.",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     flgAccountPassphrase,
+						EnvVars:  []string{envAccountPass},
+						Usage:    "The passphrase used to encrypt the bundle.",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name: "import",
+				Usage: "Import an account from an encrypted bundle into the storage." +
+					" The account is stored for the server and the email of the bundle.",
+				Action: accountImport,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     flgAccountFile,
+						Usage:    "The path of the bundle.",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     flgAccountPassphrase,
+						EnvVars:  []string{envAccountPass},
+						Usage:    "The passphrase used to decrypt the bundle.",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  flgAccountOverwrite,
+						Usage: "Replace the account if it already exists in the storage.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func accountUpdate(ctx *cli.Context) error {
+	contacts := ctx.StringSlice(flgAccountContact)
+
+	if len(contacts) == 0 && !ctx.Bool(flgAccountNoContact) {
+		return fmt.Errorf("use --%s to define the contacts, or --%s to remove them", flgAccountContact, flgAccountNoContact)
+	}
+
+	if len(contacts) > 0 && ctx.Bool(flgAccountNoContact) {
+		return fmt.Errorf("--%s and --%s are mutually exclusive", flgAccountContact, flgAccountNoContact)
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	defer lockStorage(ctx, accountsStorage.backend)()
+
+	if !accountsStorage.ExistsAccountFilePath() {
+		return errors.New("the account doesn't exist: use 'run' to register a new account")
+	}
+
+	account, keyType := setupAccount(ctx, accountsStorage)
+
+	if account.Registration == nil {
+		return fmt.Errorf("account %s is not registered: use 'run' to register a new account", account.Email)
+	}
+
+	client := newClient(ctx, account, keyType)
+
+	reg, err := client.Registration.UpdateContacts(contacts...)
+	if err != nil {
+		return fmt.Errorf("could not update the contacts of the account %s: %w", account.Email, err)
+	}
+
+	account.Registration = reg
+
+	err = accountsStorage.Save(account)
+	if err != nil {
+		return fmt.Errorf("could not save the account %s: %w", account.Email, err)
+	}
+
+	if len(reg.Body.Contact) == 0 {
+		log.Printf("The contacts of the account %s were removed.", account.Email)
+	} else {
+		log.Printf("The contacts of the account %s were updated: %s", account.Email, strings.Join(reg.Body.Contact, ", "))
+	}
+
+	return nil
+}
+
+func accountExport(ctx *cli.Context) error {
+	if ctx.String(flgAccountKeyURI) != "" {
+		return fmt.Errorf("the private key of the account is not stored by lego (--%s): it can't be exported", flgAccountKeyURI)
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	defer lockStorage(ctx, accountsStorage.backend)()
+
+	if !accountsStorage.ExistsAccountFilePath() {
+		return errors.New("the account doesn't exist: use 'run' to register a new account")
+	}
+
+	keyBytes, err := accountsStorage.ReadPrivateKey()
+	if err != nil {
+		return fmt.Errorf("could not read the private key of the account %s: %w", accountsStorage.GetUserID(), err)
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(keyBytes)
+	if err != nil {
+		return fmt.Errorf("could not parse the private key of the account %s: %w", accountsStorage.GetUserID(), err)
+	}
+
+	account := accountsStorage.LoadAccount(privateKey)
+
+	data, err := encryptAccountBundle(&accountBundle{
+		Server:       ctx.String(flgServer),
+		Email:        account.Email,
+		Registration: account.Registration,
+		Key:          string(keyBytes),
+		ExportedAt:   time.Now().UTC(),
+	}, ctx.String(flgAccountPassphrase))
+	if err != nil {
+		return fmt.Errorf("could not encrypt the account %s: %w", accountsStorage.GetUserID(), err)
+	}
+
+	err = os.WriteFile(ctx.String(flgAccountFile), data, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not write the bundle: %w", err)
+	}
+
+	log.Printf("The account %s was exported to %s.", accountsStorage.GetUserID(), ctx.String(flgAccountFile))
+
+	return nil
+}
+
+func accountImport(ctx *cli.Context) error {
+	data, err := os.ReadFile(ctx.String(flgAccountFile))
+	if err != nil {
+		return fmt.Errorf("could not read the bundle: %w", err)
+	}
+
+	bundle, err := decryptAccountBundle(data, ctx.String(flgAccountPassphrase))
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey([]byte(bundle.Key))
+	if err != nil {
+		return fmt.Errorf("could not parse the private key of the bundle: %w", err)
+	}
+
+	// The account is stored for the server and the email of the bundle.
+	for _, flag := range [][2]string{{flgServer, bundle.Server}, {flgEmail, bundle.Email}} {
+		name, value := flag[0], flag[1]
+
+		if ctx.IsSet(name) && ctx.String(name) != value {
+			return fmt.Errorf("the bundle contains an account for --%s %q, not %q", name, value, ctx.String(name))
+		}
+
+		err = ctx.Set(name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	defer lockStorage(ctx, accountsStorage.backend)()
+
+	if accountsStorage.ExistsAccountFilePath() && !ctx.Bool(flgAccountOverwrite) {
+		return fmt.Errorf("the account %s already exists for %s: use --%s to replace it", accountsStorage.GetUserID(), bundle.Server, flgAccountOverwrite)
+	}
+
+	err = accountsStorage.SavePrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("could not save the private key of the account %s: %w", accountsStorage.GetUserID(), err)
+	}
+
+	err = accountsStorage.Save(&Account{Email: bundle.Email, Registration: bundle.Registration})
+	if err != nil {
+		return fmt.Errorf("could not save the account %s: %w", accountsStorage.GetUserID(), err)
+	}
+
+	log.Printf("The account %s was imported for %s (exported on %s).",
+		accountsStorage.GetUserID(), bundle.Server, bundle.ExportedAt.Format(time.RFC3339))
+
+	return nil
+}
@@ -1,23 +1,113 @@
 package cmd
 
 import (
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certcrypto/kms"
+	"github.com/digicert/lego/v4/certcrypto/tpm"
+	"github.com/digicert/lego/v4/errcatalog"
 	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/metrics"
+	"github.com/digicert/lego/v4/metrics/prometheus"
 	"github.com/urfave/cli/v2"
 )
 
 func Before(ctx *cli.Context) error {
+	err := setupLogger(ctx)
+	if err != nil {
+		return err
+	}
+
 	if ctx.String(flgPath) == "" {
 		log.Fatalf("Could not determine current working directory. Please pass --%s.", flgPath)
 	}
 
-	err := createNonExistingFolder(ctx.String(flgPath))
+	if ctx.String(flgStorage) == storageFileSystem {
+		err = createNonExistingFolder(ctx.String(flgPath))
+		if err != nil {
+			log.Fatalf("Could not check/create path: %v", err)
+		}
+	}
+
+	err = setupCAServer(ctx)
 	if err != nil {
-		log.Fatalf("Could not check/create path: %v", err)
+		return err
 	}
 
 	if ctx.String(flgServer) == "" {
 		log.Fatalf("Could not determine current working server. Please pass --%s.", flgServer)
 	}
 
+	if filename := ctx.Path(flgMetricsTextfile); filename != "" {
+		sink := prometheus.New()
+		metrics.SetDefault(sink)
+
+		// The metrics of the failures must also be written when lego exits because of an error.
+		log.AtExit(func() {
+			err := sink.WriteFile(filename)
+			if err != nil {
+				log.Warn("Could not write the metrics file.", "error", err)
+			}
+		})
+	}
+
+	kms.Register()
+
+	// The TPM opener can be replaced by a custom build (ex: go-tpm based).
+	if !certcrypto.IsKeyURI(tpm.Scheme + ":") {
+		tpm.Register(tpm.Open)
+	}
+
+	return nil
+}
+
+// setupLogger replaces the default logger according to the "log.*" options.
+func setupLogger(ctx *cli.Context) error {
+	level, err := log.ParseLevel(ctx.String(flgLogLevel))
+	if err != nil {
+		return err
+	}
+
+	_, err = errcatalog.ParseVerbosity(ctx.String(flgLogErrorVerbosity))
+	if err != nil {
+		return err
+	}
+
+	logger, err := log.New(os.Stderr, ctx.String(flgLogFormat), level)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool(flgJSON) && slices.Contains(jsonCommands, ctx.Args().First()) {
+		report := newJSONReport(os.Stdout, ctx.Args().First())
+
+		logger = slog.New(reportHandler{Handler: logger.Handler(), report: report})
+
+		// The report of a failure must also be written when lego exits because of an error.
+		log.AtExit(report.write)
+
+		cliReport = report
+	}
+
+	log.SetDefault(logger)
+
 	return nil
 }
+
+// After writes the metrics file, if any.
+func After(ctx *cli.Context) error {
+	filename := ctx.Path(flgMetricsTextfile)
+	if filename == "" {
+		return nil
+	}
+
+	sink, ok := metrics.Default().(*prometheus.Sink)
+	if !ok {
+		return nil
+	}
+
+	return sink.WriteFile(filename)
+}
@@ -0,0 +1,438 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgCertsCert      = "cert"
+	flgCertsKey       = "key"
+	flgCertsIssuer    = "issuer"
+	flgCertsOverwrite = "overwrite"
+	flgCertsFormat    = "format"
+	flgCertsInclude   = "include"
+	flgCertsOutput    = "output"
+)
+
+// Archive formats.
+const (
+	archiveTar = "tar"
+	archiveZip = "zip"
+)
+
+// The components of an exported certificate.
+const (
+	componentKey      = "key"
+	componentCert     = "cert"
+	componentChain    = "chain"
+	componentPFX      = "pfx"
+	componentMetadata = "metadata"
+)
+
+func createCerts() *cli.Command {
+	return &cli.Command{
+		Name:  "certs",
+		Usage: "Manage the certificates of the storage.",
+		Subcommands: []*cli.Command{
+			{
+				Name: "import",
+				Usage: "Import a certificate obtained outside of lego into the storage, so it can be renewed by lego." +
+					" The certificate is recorded for --server and --email, and with the challenge options (--http, --tls, --dns).",
+				Action: certsImport,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     flgCertsCert,
+						Usage:    "The path of the certificate (PEM). The certificate may be followed by its issuer chain.",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     flgCertsKey,
+						Usage:    "The path of the private key of the certificate (PEM).",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  flgCertsIssuer,
+						Usage: "The path of the issuer chain (PEM), if it is not included in the certificate file.",
+					},
+					&cli.StringFlag{
+						Name:  flgName,
+						Usage: "The name of the certificate in the storage. Defaults to the main domain of the certificate.",
+					},
+					&cli.BoolFlag{
+						Name:  flgCertsOverwrite,
+						Usage: "Replace the certificate if it already exists in the storage.",
+					},
+				},
+			},
+			{
+				Name: "export",
+				Usage: "Export a stored certificate to an archive, to hand it off to other systems." +
+					" The PFX component uses --pfx.pass and --pfx.format.",
+				Action: certsExport,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     flgName,
+						Usage:    "The name of the certificate (see 'lego list').",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  flgCertsFormat,
+						Usage: "The format of the archive. Supported: tar, zip.",
+						Value: archiveTar,
+					},
+					&cli.StringSliceFlag{
+						Name: flgCertsInclude,
+						Usage: "A component to include in the archive. Can be specified multiple times." +
+							" Supported: key, cert, chain, pfx, metadata. Defaults to key, cert, chain, and metadata.",
+					},
+					&cli.StringFlag{
+						Name:  flgCertsOutput,
+						Usage: "The path of the archive. Defaults to the name of the certificate, with the extension of the format.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func certsImport(ctx *cli.Context) error {
+	certPEM, err := os.ReadFile(ctx.String(flgCertsCert))
+	if err != nil {
+		return fmt.Errorf("could not read the certificate: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(ctx.String(flgCertsKey))
+	if err != nil {
+		return fmt.Errorf("could not read the private key: %w", err)
+	}
+
+	var issuerPEM []byte
+
+	if ctx.IsSet(flgCertsIssuer) {
+		issuerPEM, err = os.ReadFile(ctx.String(flgCertsIssuer))
+		if err != nil {
+			return fmt.Errorf("could not read the issuer chain: %w", err)
+		}
+	}
+
+	certRes, keyType, err := readExternalCertificate(certPEM, issuerPEM, keyPEM, ctx.String(flgName))
+	if err != nil {
+		return err
+	}
+
+	// The renewals use the key type of the imported certificate.
+	if !ctx.IsSet(flgKeyType) {
+		err = ctx.Set(flgKeyType, keyType)
+		if err != nil {
+			return err
+		}
+	}
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	defer lockStorage(ctx, certsStorage.backend)()
+
+	if certsStorage.ExistsFile(certRes.Domain, certExt) && !ctx.Bool(flgCertsOverwrite) {
+		return fmt.Errorf("the certificate %s already exists: use --%s to replace it", certRes.Domain, flgCertsOverwrite)
+	}
+
+	certsStorage.SaveResource(certRes)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("The certificate %s was imported for %s (expires on %s).",
+		certRes.Domain, ctx.String(flgServer), cert.NotAfter.Format(time.RFC3339))
+
+	return nil
+}
+
+func certsExport(ctx *cli.Context) error {
+	format := ctx.String(flgCertsFormat)
+	if format != archiveTar && format != archiveZip {
+		return fmt.Errorf("unsupported archive format: %s", format)
+	}
+
+	components := ctx.StringSlice(flgCertsInclude)
+	if len(components) == 0 {
+		components = []string{componentKey, componentCert, componentChain, componentMetadata}
+	}
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	defer lockStorage(ctx, certsStorage.backend)()
+
+	name := ctx.String(flgName)
+
+	if !certsStorage.ExistsFile(name, certExt) {
+		return fmt.Errorf("the certificate %s doesn't exist (see 'lego list')", name)
+	}
+
+	files, err := exportedFiles(certsStorage, name, components, ctx.IsSet(flgCertsInclude))
+	if err != nil {
+		return err
+	}
+
+	output := ctx.String(flgCertsOutput)
+	if output == "" {
+		output = sanitizedDomain(name) + "." + format
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not create the archive: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	err = writeArchive(file, format, files)
+	if err != nil {
+		_ = os.Remove(output)
+
+		return fmt.Errorf("could not write the archive: %w", err)
+	}
+
+	log.Printf("The certificate %s was exported to %s.", name, output)
+
+	return file.Close()
+}
+
+// archiveFile is a file of an exported certificate.
+type archiveFile struct {
+	Name    string
+	Content []byte
+	Mode    int64
+}
+
+// exportedFiles reads the components of a stored certificate.
+// The missing components are errors only if they are explicitly requested (ex: the chain of a certificate imported without issuer).
+func exportedFiles(certsStorage *CertificatesStorage, name string, components []string, explicit bool) ([]archiveFile, error) {
+	extensions := map[string]string{
+		componentKey:      keyExt,
+		componentCert:     certExt,
+		componentChain:    issuerExt,
+		componentPFX:      pfxExt,
+		componentMetadata: resourceExt,
+	}
+
+	baseName := sanitizedDomain(name)
+
+	var files []archiveFile
+
+	for _, component := range slices.Compact(slices.Sorted(slices.Values(components))) {
+		ext, ok := extensions[component]
+		if !ok {
+			return nil, fmt.Errorf("unsupported component: %s", component)
+		}
+
+		content, err := readComponent(certsStorage, name, component, ext)
+		if err != nil {
+			if !explicit {
+				log.Warnf("[%s] The %s is not exported: %v", name, component, err)
+				continue
+			}
+
+			return nil, fmt.Errorf("could not export the %s of the certificate %s: %w", component, name, err)
+		}
+
+		mode := int64(0o644)
+		if component == componentKey || component == componentPFX {
+			mode = 0o600
+		}
+
+		files = append(files, archiveFile{Name: filepath.ToSlash(filepath.Join(baseName, baseName+ext)), Content: content, Mode: mode})
+	}
+
+	return files, nil
+}
+
+// readComponent reads a component of a stored certificate.
+// The PFX file is stored only with --pfx: it is created from the stored files otherwise.
+func readComponent(certsStorage *CertificatesStorage, name, component, ext string) ([]byte, error) {
+	if component != componentPFX || certsStorage.ExistsFile(name, pfxExt) {
+		return certsStorage.ReadFile(name, ext)
+	}
+
+	var err error
+
+	certRes := &certificate.Resource{Domain: name}
+
+	certRes.Certificate, err = certsStorage.ReadFile(name, certExt)
+	if err != nil {
+		return nil, err
+	}
+
+	certRes.IssuerCertificate, err = certsStorage.ReadFile(name, issuerExt)
+	if err != nil {
+		return nil, err
+	}
+
+	certRes.PrivateKey, err = certsStorage.ReadFile(name, keyExt)
+	if err != nil {
+		return nil, err
+	}
+
+	return certsStorage.EncodePFX(name, certRes)
+}
+
+// writeArchive writes the files to a tar or a zip archive.
+func writeArchive(w io.Writer, format string, files []archiveFile) error {
+	now := time.Now()
+
+	switch format {
+	case archiveTar:
+		tw := tar.NewWriter(w)
+
+		for _, file := range files {
+			err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     file.Name,
+				Size:     int64(len(file.Content)),
+				Mode:     file.Mode,
+				ModTime:  now,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = tw.Write(file.Content)
+			if err != nil {
+				return err
+			}
+		}
+
+		return tw.Close()
+
+	case archiveZip:
+		zw := zip.NewWriter(w)
+
+		for _, file := range files {
+			header := &zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: now}
+			header.SetMode(os.FileMode(file.Mode))
+
+			fw, err := zw.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+
+			_, err = fw.Write(file.Content)
+			if err != nil {
+				return err
+			}
+		}
+
+		return zw.Close()
+
+	default:
+		return fmt.Errorf("unsupported archive format: %s", format)
+	}
+}
+
+// readExternalCertificate creates the resource of a certificate obtained outside of lego.
+// The certificate file may contain the issuer chain after the certificate.
+// It returns the key type of the private key, as defined by the `--key-type` flag.
+func readExternalCertificate(certPEM, issuerPEM, keyPEM []byte, name string) (*certificate.Resource, string, error) {
+	certs, err := certcrypto.ParsePEMBundle(certPEM)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not parse the certificate: %w", err)
+	}
+
+	cert := certs[0]
+
+	if len(issuerPEM) == 0 {
+		for _, issuer := range certs[1:] {
+			issuerPEM = append(issuerPEM, certcrypto.PEMEncode(certcrypto.DERCertificateBytes(issuer.Raw))...)
+		}
+	} else if _, err = certcrypto.ParsePEMBundle(issuerPEM); err != nil {
+		return nil, "", fmt.Errorf("could not parse the issuer chain: %w", err)
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(keyPEM)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not parse the private key: %w", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, "", errors.New("unsupported private key")
+	}
+
+	publicKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(cert.PublicKey) {
+		return nil, "", errors.New("the private key doesn't match the certificate")
+	}
+
+	keyType, err := privateKeyType(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if name == "" {
+		name, err = certcrypto.GetCertificateMainDomain(cert)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		log.Warnf("[%s] The certificate expired on %s.", name, cert.NotAfter.Format(time.RFC3339))
+	}
+
+	certRes := &certificate.Resource{
+		Domain:      name,
+		PrivateKey:  certcrypto.PEMEncode(privateKey),
+		Certificate: certcrypto.PEMEncode(certcrypto.DERCertificateBytes(cert.Raw)),
+	}
+
+	if len(issuerPEM) > 0 {
+		certRes.IssuerCertificate = issuerPEM
+		// The stored certificate is a bundle, as the certificates obtained by lego.
+		certRes.Certificate = bytes.Join([][]byte{certRes.Certificate, issuerPEM}, nil)
+	}
+
+	return certRes, keyType, nil
+}
+
+// privateKeyType returns the value of the `--key-type` flag matching the private key.
+func privateKeyType(privateKey crypto.PrivateKey) (string, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		switch key.N.BitLen() {
+		case 2048, 3072, 4096, 8192:
+			return fmt.Sprintf("rsa%d", key.N.BitLen()), nil
+		}
+
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return "ec256", nil
+		case elliptic.P384():
+			return "ec384", nil
+		case elliptic.P521():
+			return "ec521", nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported private key type: %T", privateKey)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readExternalCertificate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	certPEM := generateTestCertificate(t, key, "example.com")
+
+	issuerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuerPEM := generateTestCertificate(t, issuerKey, "issuer.example.com")
+
+	bundle := append(append([]byte{}, certPEM...), issuerPEM...)
+
+	certRes, keyType, err := readExternalCertificate(bundle, nil, certcrypto.PEMEncode(key), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "rsa2048", keyType)
+	assert.Equal(t, "example.com", certRes.Domain)
+	assert.Equal(t, bundle, certRes.Certificate)
+	assert.Equal(t, issuerPEM, certRes.IssuerCertificate)
+	assert.Equal(t, certcrypto.PEMEncode(key), certRes.PrivateKey)
+
+	certRes, _, err = readExternalCertificate(certPEM, issuerPEM, certcrypto.PEMEncode(key), "custom")
+	require.NoError(t, err)
+
+	assert.Equal(t, "custom", certRes.Domain)
+	assert.Equal(t, bundle, certRes.Certificate)
+	assert.Equal(t, issuerPEM, certRes.IssuerCertificate)
+}
+
+func Test_readExternalCertificate_errors(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	certPEM := generateTestCertificate(t, key, "example.com")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, _, err = readExternalCertificate(certPEM, nil, certcrypto.PEMEncode(otherKey), "")
+	require.EqualError(t, err, "the private key doesn't match the certificate")
+
+	_, _, err = readExternalCertificate([]byte("invalid"), nil, certcrypto.PEMEncode(key), "")
+	require.ErrorContains(t, err, "could not parse the certificate")
+
+	_, _, err = readExternalCertificate(certPEM, nil, []byte("invalid"), "")
+	require.ErrorContains(t, err, "could not parse the private key")
+}
+
+func Test_exportedFiles(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	certsStorage := &CertificatesStorage{
+		rootPath:  t.TempDir(),
+		backend:   storage.NewFileSystem(),
+		pfxFormat: "SHA256",
+	}
+
+	certsStorage.SaveResource(&certificate.Resource{
+		Domain:      "example.com",
+		Certificate: generateTestCertificate(t, key, "example.com"),
+		PrivateKey:  certcrypto.PEMEncode(key),
+	})
+
+	// The certificate has no chain: the missing component is skipped by default.
+	files, err := exportedFiles(certsStorage, "example.com", []string{componentKey, componentCert, componentChain, componentMetadata}, false)
+	require.NoError(t, err)
+
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+
+	assert.Equal(t, []string{"example.com/example.com.crt", "example.com/example.com.key", "example.com/example.com.json"}, names)
+
+	_, err = exportedFiles(certsStorage, "example.com", []string{componentChain}, true)
+	require.ErrorContains(t, err, "could not export the chain of the certificate example.com")
+
+	_, err = exportedFiles(certsStorage, "example.com", []string{"foo"}, true)
+	require.EqualError(t, err, "unsupported component: foo")
+}
+
+func Test_writeArchive(t *testing.T) {
+	files := []archiveFile{
+		{Name: "example.com/example.com.crt", Content: []byte("cert"), Mode: 0o644},
+		{Name: "example.com/example.com.key", Content: []byte("key"), Mode: 0o600},
+	}
+
+	t.Run(archiveTar, func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, writeArchive(buf, archiveTar, files))
+
+		tr := tar.NewReader(buf)
+
+		for _, file := range files {
+			header, err := tr.Next()
+			require.NoError(t, err)
+
+			assert.Equal(t, file.Name, header.Name)
+			assert.Equal(t, file.Mode, header.Mode)
+
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+
+			assert.Equal(t, file.Content, content)
+		}
+
+		_, err := tr.Next()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run(archiveZip, func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, writeArchive(buf, archiveZip, files))
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		require.Len(t, zr.File, len(files))
+
+		for i, file := range files {
+			assert.Equal(t, file.Name, zr.File[i].Name)
+			assert.Equal(t, filepath.Base(file.Name), zr.File[i].FileInfo().Name())
+
+			rc, err := zr.File[i].Open()
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+
+			_ = rc.Close()
+
+			assert.Equal(t, file.Content, content)
+		}
+	})
+
+	require.EqualError(t, writeArchive(io.Discard, "rar", files), "unsupported archive format: rar")
+}
+
+func generateTestCertificate(t *testing.T, key *rsa.PrivateKey, domain string) []byte {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der))
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/providers/dns"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgCleanupOlderThan = "older-than"
+	flgCleanupZones     = "zone"
+	flgCleanupDryRun    = "dry-run"
+)
+
+// cleanupProviders the DNS providers able to list the challenge records (dns01.RecordLister).
+var cleanupProviders = []string{"cloudflare", "ionoscloud", "otc"}
+
+func createDNS() *cli.Command {
+	return &cli.Command{
+		Name:  "dns",
+		Usage: "Manage the DNS records of the challenges.",
+		Subcommands: []*cli.Command{
+			{
+				Name: "cleanup",
+				Usage: "Remove the orphaned challenge records (_acme-challenge TXT records) left behind by an interrupted process." +
+					" Uses the DNS provider defined by --dns.",
+				Action: dnsCleanup,
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  flgCleanupOlderThan,
+						Usage: "The minimum age of the records to remove.",
+						Value: 24 * time.Hour,
+					},
+					&cli.StringSliceFlag{
+						Name:  flgCleanupZones,
+						Usage: "A zone to clean up. Can be specified multiple times. Defaults to the zones of the domains defined by --domains.",
+					},
+					&cli.BoolFlag{
+						Name:  flgCleanupDryRun,
+						Usage: "Only display the records to remove.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func dnsCleanup(ctx *cli.Context) error {
+	if !ctx.IsSet(flgDNS) {
+		return fmt.Errorf("a DNS provider must be defined with --%s", flgDNS)
+	}
+
+	provider, err := dns.NewDNSChallengeProviderByName(ctx.String(flgDNS))
+	if err != nil {
+		return err
+	}
+
+	if !dns01.CanListChallengeRecords(provider) {
+		return fmt.Errorf("unsupported provider %q: the DNS provider cannot list the challenge records (supported: %s)",
+			ctx.String(flgDNS), strings.Join(cleanupProviders, ", "))
+	}
+
+	zones, err := cleanupZones(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := dns01.CleanupOptions{
+		OlderThan: ctx.Duration(flgCleanupOlderThan),
+		DryRun:    ctx.Bool(flgCleanupDryRun),
+	}
+
+	removed, err := dns01.CleanupOrphanedRecords(ctx.Context, provider, zones, opts)
+
+	for _, record := range removed {
+		if opts.DryRun {
+			log.Printf("[dry-run] %s (%s, created at %s) would be removed", record.FQDN, record.ID, record.CreatedAt.Format(time.RFC3339))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%d orphaned challenge records found in %s.", len(removed), strings.Join(zones, ", "))
+
+	return nil
+}
+
+// cleanupZones returns the zones defined by --zone, or the zones of the domains defined by --domains.
+func cleanupZones(ctx *cli.Context) ([]string, error) {
+	if zones := ctx.StringSlice(flgCleanupZones); len(zones) > 0 {
+		return zones, nil
+	}
+
+	domains := ctx.StringSlice(flgDomains)
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("no zone: use --%s or --%s", flgCleanupZones, flgDomains)
+	}
+
+	nameservers := dns01.ParseNameservers(ctx.StringSlice(flgDNSResolvers))
+
+	var zones []string
+
+	for _, domain := range domains {
+		fqdn := dns01.ToFqdn(strings.TrimPrefix(domain, "*."))
+
+		var (
+			zone string
+			err  error
+		)
+
+		if len(nameservers) > 0 {
+			zone, err = dns01.FindZoneByFqdnCustom(fqdn, nameservers)
+		} else {
+			zone, err = dns01.FindZoneByFqdn(fqdn)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not find the zone of %s: %w", domain, err)
+		}
+
+		if !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+
+	return zones, nil
+}
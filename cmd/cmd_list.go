@@ -1,11 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/url"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -22,7 +22,7 @@ func createList() *cli.Command {
 	return &cli.Command{
 		Name:   "list",
 		Usage:  "Display certificates and accounts information.",
-		Action: list,
+		Action: withJSONReport(list),
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    flgAccounts,
@@ -57,22 +57,31 @@ func list(ctx *cli.Context) error {
 func listCertificates(ctx *cli.Context) error {
 	certsStorage := NewCertificatesStorage(ctx)
 
-	matches, err := filepath.Glob(filepath.Join(certsStorage.GetRootPath(), "*.crt"))
+	entries, err := certsStorage.backend.List(context.Background(), certsStorage.GetRootPath())
 	if err != nil {
 		return err
 	}
 
+	var matches []string
+
+	for _, name := range entries {
+		if filepath.Dir(name) == filepath.Clean(certsStorage.GetRootPath()) && filepath.Ext(name) == certExt {
+			matches = append(matches, name)
+		}
+	}
+
 	names := ctx.Bool(flgNames)
+	report := cliReport != nil
 
 	if len(matches) == 0 {
-		if !names {
+		if !names && !report {
 			fmt.Println("No certificates found.")
 		}
 
 		return nil
 	}
 
-	if !names {
+	if !names && !report {
 		fmt.Println("Found the following certs:")
 	}
 
@@ -81,7 +90,7 @@ func listCertificates(ctx *cli.Context) error {
 			continue
 		}
 
-		data, err := os.ReadFile(filename)
+		data, err := certsStorage.backend.ReadFile(context.Background(), filename)
 		if err != nil {
 			return err
 		}
@@ -96,9 +105,12 @@ func listCertificates(ctx *cli.Context) error {
 			return err
 		}
 
-		if names {
+		switch {
+		case report:
+			cliReport.addCertificate(name, "", pCert, map[string]string{"certificate": filename})
+		case names:
 			fmt.Println(name)
-		} else {
+		default:
 			fmt.Println("  Certificate Name:", name)
 			fmt.Println("    Domains:", strings.Join(pCert.DNSNames, ", "))
 
@@ -118,20 +130,39 @@ func listCertificates(ctx *cli.Context) error {
 func listAccount(ctx *cli.Context) error {
 	accountsStorage := NewAccountsStorage(ctx)
 
-	matches, err := filepath.Glob(filepath.Join(accountsStorage.GetRootPath(), "*", "*", "*.json"))
+	entries, err := accountsStorage.backend.List(context.Background(), accountsStorage.GetRootPath())
 	if err != nil {
 		return err
 	}
 
+	var matches []string
+
+	for _, name := range entries {
+		// <root>/<server>/<userID>/account.json
+		rel, errR := filepath.Rel(accountsStorage.GetRootPath(), name)
+		if errR != nil || filepath.Ext(name) != resourceExt || len(strings.Split(rel, string(filepath.Separator))) != 3 {
+			continue
+		}
+
+		matches = append(matches, name)
+	}
+
+	report := cliReport != nil
+
 	if len(matches) == 0 {
-		fmt.Println("No accounts found.")
+		if !report {
+			fmt.Println("No accounts found.")
+		}
+
 		return nil
 	}
 
-	fmt.Println("Found the following accounts:")
+	if !report {
+		fmt.Println("Found the following accounts:")
+	}
 
 	for _, filename := range matches {
-		data, err := os.ReadFile(filename)
+		data, err := accountsStorage.backend.ReadFile(context.Background(), filename)
 		if err != nil {
 			return err
 		}
@@ -148,6 +179,17 @@ func listAccount(ctx *cli.Context) error {
 			return err
 		}
 
+		if report {
+			cliReport.addAccount(jsonAccount{
+				Email:  account.Email,
+				Server: uri.Host,
+				URL:    account.Registration.URI,
+				Path:   filepath.Dir(filename),
+			})
+
+			continue
+		}
+
 		fmt.Println("  Email:", account.Email)
 		fmt.Println("  Server:", uri.Host)
 		fmt.Println("  Path:", filepath.Dir(filename))
@@ -4,6 +4,7 @@ import (
 	"crypto"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"math/rand"
 	"os"
 	"slices"
@@ -16,6 +17,7 @@ import (
 	"github.com/digicert/lego/v4/log"
 	"github.com/mattn/go-isatty"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/net/idna"
 )
 
 // Flag names.
@@ -28,16 +30,27 @@ const (
 	flgRenewHook              = "renew-hook"
 	flgRenewHookTimeout       = "renew-hook-timeout"
 	flgNoRandomSleep          = "no-random-sleep"
+	flgRenewJitter            = "jitter"
+	flgRenewSpread            = "spread"
 	flgForceCertDomains       = "force-cert-domains"
+	flgNotifyExpiryDays       = "notify-expiry-days"
+	flgName                   = "name"
+	flgRenewPlan              = "plan"
+	flgRenewPlanFormat        = "plan-format"
 )
 
 func createRenew() *cli.Command {
 	return &cli.Command{
 		Name:   "renew",
 		Usage:  "Renew a certificate",
-		Action: renew,
+		Action: withJSONReport(renew),
 		Before: func(ctx *cli.Context) error {
-			// we require either domains or csr, but not both
+			// the plan evaluates all the stored certificates.
+			if ctx.Bool(flgRenewPlan) {
+				return nil
+			}
+
+			// we require either domains, csr, or name, but only one of them
 			hasDomains := len(ctx.StringSlice(flgDomains)) > 0
 
 			hasCsr := ctx.String(flgCSR) != ""
@@ -45,8 +58,13 @@ func createRenew() *cli.Command {
 				log.Fatalf("Please specify either --%s/-d or --%s/-c, but not both", flgDomains, flgCSR)
 			}
 
-			if !hasDomains && !hasCsr {
-				log.Fatalf("Please specify --%s/-d (or --%s/-c if you already have a CSR)", flgDomains, flgCSR)
+			hasName := ctx.String(flgName) != ""
+			if hasName && (hasDomains || hasCsr) {
+				log.Fatalf("Please specify either --%s or --%s/-d or --%s/-c", flgName, flgDomains, flgCSR)
+			}
+
+			if !hasDomains && !hasCsr && !hasName {
+				log.Fatalf("Please specify --%s/-d (or --%s/-c if you already have a CSR, or --%s for a stored certificate)", flgDomains, flgCSR, flgName)
 			}
 
 			if ctx.Bool(flgForceCertDomains) && hasCsr {
@@ -56,6 +74,11 @@ func createRenew() *cli.Command {
 			return nil
 		},
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name: flgName,
+				Usage: "The name of a stored certificate (see 'lego list'): the domains and the options recorded when it was obtained are used," +
+					" unless they are explicitly defined.",
+			},
 			&cli.IntFlag{
 				Name:  flgRenewDays,
 				Value: 30,
@@ -79,6 +102,10 @@ func createRenew() *cli.Command {
 				Name:  flgReuseKey,
 				Usage: "Used to indicate you want to reuse your current private key for the new certificate.",
 			},
+			&cli.StringFlag{
+				Name:  flgPrivateKey,
+				Usage: privateKeyUsage("the new certificate"),
+			},
 			&cli.BoolFlag{
 				Name:  flgNoBundle,
 				Usage: "Do not create a certificate bundle by adding the issuers certificate to the new certificate.",
@@ -105,7 +132,7 @@ func createRenew() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  flgProfile,
-				Usage: "If the CA offers multiple certificate profiles (draft-ietf-acme-profiles), choose this one.",
+				Usage: "If the CA offers multiple certificate profiles (draft-ietf-acme-profiles), choose this one. Fails if the CA doesn't advertise the profile.",
 			},
 			&cli.StringFlag{
 				Name:  flgAlwaysDeactivateAuthorizations,
@@ -125,22 +152,87 @@ func createRenew() *cli.Command {
 				Usage: "Do not add a random sleep before the renewal." +
 					" We do not recommend using this flag if you are doing your renewals in an automated way.",
 			},
+			&cli.DurationFlag{
+				Name:  flgRenewJitter,
+				Usage: "The maximum duration of the random sleep before the renewal (only when lego is not run from a terminal).",
+				Value: 8 * time.Minute,
+			},
+			&cli.DurationFlag{
+				Name: flgRenewSpread,
+				Usage: "Renew each certificate earlier by a deterministic offset, derived from the certificate, within this window (ex: 72h)." +
+					" It spreads the renewals of many machines running lego on the same schedule. Not applied when ARI is used.",
+			},
 			&cli.BoolFlag{
 				Name:  flgForceCertDomains,
 				Usage: "Check and ensure that the cert's domain list matches those passed in the domains argument.",
 			},
+			&cli.IntFlag{
+				Name:  flgNotifyExpiryDays,
+				Usage: "Send a notification (see --notify) when a certificate that has not been renewed expires within this number of days. 0 disables it.",
+				Value: 7,
+			},
+			&cli.BoolFlag{
+				Name: flgRenewPlan,
+				Usage: "Display the stored certificates which would be renewed, and why, without renewing them." +
+					" The certificates are evaluated with ARI and the renewal thresholds (--days, --dynamic, --spread).",
+			},
+			&cli.StringFlag{
+				Name:  flgRenewPlanFormat,
+				Usage: "The format of the renewal plan (--plan). Supported: text, json.",
+				Value: "text",
+			},
 		},
 	}
 }
 
 func renew(ctx *cli.Context) error {
-	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
+	if ctx.Bool(flgRenewPlan) {
+		return renewPlan(ctx)
+	}
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	if ctx.IsSet(flgName) {
+		metadata, err := readCertificateName(ctx, certsStorage)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = useCertificateOptions(ctx, metadata)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// The storage records the options of the renewed certificate.
+		certsStorage = NewCertificatesStorage(ctx)
+	}
+
+	domain, err := getRenewDomain(ctx)
+	if err != nil {
+		log.Fatalf("Error while reading the CSR\n\t%v", err)
+	}
+
+	// The certificate is renewed by the CA which issued it, unless another CA is explicitly defined.
+	if !useCertificateOrigin(ctx, certsStorage, domain) {
+		log.Warnf("[%s] The certificate was not issued by %s: ARI is disabled for this renewal.", domain, ctx.String(flgServer))
+
+		err = ctx.Set(flgARIDisable, "true")
+		if err != nil {
+			return err
+		}
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	defer lockStorage(ctx, accountsStorage.backend)()
+
+	account, keyType := setupAccount(ctx, accountsStorage)
 
 	if account.Registration == nil {
 		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
 	}
 
-	certsStorage := NewCertificatesStorage(ctx)
+	cliReport.setAccount(account, ctx.String(flgServer))
 
 	bundle := !ctx.Bool(flgNoBundle)
 
@@ -157,8 +249,23 @@ func renew(ctx *cli.Context) error {
 	return renewForDomains(ctx, account, keyType, certsStorage, bundle, meta)
 }
 
+// getRenewDomain returns the main domain of the certificate to renew.
+func getRenewDomain(ctx *cli.Context) (string, error) {
+	if !ctx.IsSet(flgCSR) {
+		return ctx.StringSlice(flgDomains)[0], nil
+	}
+
+	csr, err := readCSRFile(ctx.String(flgCSR))
+	if err != nil {
+		return "", err
+	}
+
+	return certcrypto.GetCSRMainDomain(csr)
+}
+
 func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, meta map[string]string) error {
-	domains := ctx.StringSlice(flgDomains)
+	// The domains of the certificate are A-labels.
+	domains := toASCIIDomains(ctx.StringSlice(flgDomains))
 	domain := domains[0]
 
 	// load the cert resource from files.
@@ -202,8 +309,12 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 
 	certDomains := certcrypto.ExtractDomains(cert)
 
-	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) &&
+	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic), ctx.Duration(flgRenewSpread)) &&
 		(!forceDomains || slices.Equal(certDomains, domains)) {
+		cliReport.addCertificate(domain, actionNotRenewed, cert, certificateFiles(certsStorage, domain, nil))
+
+		notifyExpiryApproaching(ctx, domain, cert)
+
 		return nil
 	}
 
@@ -229,12 +340,21 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 		}
 	}
 
+	if ctx.IsSet(flgPrivateKey) {
+		var errR error
+
+		privateKey, errR = loadPrivateKey(ctx.String(flgPrivateKey))
+		if errR != nil {
+			return fmt.Errorf("load private key: %w", errR)
+		}
+	}
+
 	// https://github.com/go-acme/lego/issues/1656
 	// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L435-L440
-	if !isatty.IsTerminal(os.Stdout.Fd()) && !ctx.Bool(flgNoRandomSleep) {
-		// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L472
-		const jitter = 8 * time.Minute
+	// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L472
+	jitter := ctx.Duration(flgRenewJitter)
 
+	if !isatty.IsTerminal(os.Stdout.Fd()) && !ctx.Bool(flgNoRandomSleep) && jitter > 0 {
 		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
 		sleepTime := time.Duration(rnd.Int63n(int64(jitter)))
 
@@ -263,17 +383,37 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 		request.ReplacesCertID = replacesCertID
 	}
 
-	certRes, err := client.Certificate.Obtain(request)
+	obtainCtx, stop := withSignals(ctx.Context)
+	defer stop()
+
+	certRes, err := client.Certificate.ObtainWithContext(obtainCtx, request)
+	if err != nil && handleTOSChange(ctx, client, err) {
+		certRes, err = client.Certificate.ObtainWithContext(obtainCtx, request)
+	}
+
 	if err != nil {
-		log.Fatal(err)
+		notifyRenewalFailed(ctx, domain, cert, err)
+
+		log.Fatal(explainError(ctx, err))
 	}
 
 	certRes.Domain = domain
 
 	certsStorage.SaveResource(certRes)
 
+	cliReport.addCertificate(domain, actionRenewed, parseCertificate(certRes), certificateFiles(certsStorage, domain, certRes))
+
+	notifyObtained(ctx, certRes)
+
 	addPathToMetadata(meta, domain, certRes, certsStorage)
 
+	importToWindowsStore(ctx, certRes, meta)
+
+	err = deployCertificate(ctx, certRes)
+	if err != nil {
+		return err
+	}
+
 	return launchHook(ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
 }
 
@@ -325,7 +465,11 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 		}
 	}
 
-	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) {
+	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic), ctx.Duration(flgRenewSpread)) {
+		cliReport.addCertificate(domain, actionNotRenewed, cert, certificateFiles(certsStorage, domain, nil))
+
+		notifyExpiryApproaching(ctx, domain, cert)
+
 		return nil
 	}
 
@@ -351,32 +495,58 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 		request.ReplacesCertID = replacesCertID
 	}
 
-	certRes, err := client.Certificate.ObtainForCSR(request)
+	obtainCtx, stop := withSignals(ctx.Context)
+	defer stop()
+
+	certRes, err := client.Certificate.ObtainForCSRWithContext(obtainCtx, request)
+	if err != nil && handleTOSChange(ctx, client, err) {
+		certRes, err = client.Certificate.ObtainForCSRWithContext(obtainCtx, request)
+	}
+
 	if err != nil {
-		log.Fatal(err)
+		notifyRenewalFailed(ctx, domain, cert, err)
+
+		log.Fatal(explainError(ctx, err))
 	}
 
 	certsStorage.SaveResource(certRes)
 
+	cliReport.addCertificate(domain, actionRenewed, parseCertificate(certRes), certificateFiles(certsStorage, domain, certRes))
+
+	notifyObtained(ctx, certRes)
+
 	addPathToMetadata(meta, domain, certRes, certsStorage)
 
+	importToWindowsStore(ctx, certRes, meta)
+
+	err = deployCertificate(ctx, certRes)
+	if err != nil {
+		return err
+	}
+
 	return launchHook(ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
 }
 
-func needRenewal(x509Cert *x509.Certificate, domain string, days int, dynamic bool) bool {
+func needRenewal(x509Cert *x509.Certificate, domain string, days int, dynamic bool, spread time.Duration) bool {
 	if x509Cert.IsCA {
 		log.Fatalf("[%s] Certificate bundle starts with a CA certificate", domain)
 	}
 
+	// The renewal date is moved earlier by an offset specific to the certificate.
+	offset := certificate.RenewalSpread(x509Cert, spread)
+	if offset > 0 {
+		log.Infof("[%s] The renewal date is moved %s earlier (spread).", domain, offset.Round(time.Second))
+	}
+
 	if dynamic {
-		return needRenewalDynamic(x509Cert, domain, time.Now())
+		return needRenewalDynamic(x509Cert, domain, time.Now(), offset)
 	}
 
 	if days < 0 {
 		return true
 	}
 
-	notAfter := int(time.Until(x509Cert.NotAfter).Hours() / 24.0)
+	notAfter := int((time.Until(x509Cert.NotAfter) - offset).Hours() / 24.0)
 	if notAfter <= days {
 		return true
 	}
@@ -387,15 +557,8 @@ func needRenewal(x509Cert *x509.Certificate, domain string, days int, dynamic bo
 	return false
 }
 
-func needRenewalDynamic(x509Cert *x509.Certificate, domain string, now time.Time) bool {
-	lifetime := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
-
-	var divisor int64 = 3
-	if lifetime.Round(24*time.Hour).Hours()/24.0 <= 10 {
-		divisor = 2
-	}
-
-	dueDate := x509Cert.NotAfter.Add(-1 * time.Duration(lifetime.Nanoseconds()/divisor))
+func needRenewalDynamic(x509Cert *x509.Certificate, domain string, now time.Time, offset time.Duration) bool {
+	dueDate := dynamicRenewalDate(x509Cert, offset)
 
 	if dueDate.Before(now) {
 		return true
@@ -407,6 +570,19 @@ func needRenewalDynamic(x509Cert *x509.Certificate, domain string, now time.Time
 	return false
 }
 
+// dynamicRenewalDate returns the renewal date of the certificate when 1/3 of its lifetime remains
+// (1/2 for the certificates with a lifetime of 10 days or less), moved earlier by the offset.
+func dynamicRenewalDate(x509Cert *x509.Certificate, offset time.Duration) time.Time {
+	lifetime := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
+
+	var divisor int64 = 3
+	if lifetime.Round(24*time.Hour).Hours()/24.0 <= 10 {
+		divisor = 2
+	}
+
+	return x509Cert.NotAfter.Add(-1 * time.Duration(lifetime.Nanoseconds()/divisor)).Add(-offset)
+}
+
 // getARIRenewalTime checks if the certificate needs to be renewed using the renewalInfo endpoint.
 func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string, client *lego.Client) *time.Time {
 	if cert.IsCA {
@@ -426,6 +602,8 @@ func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string,
 		return nil
 	}
 
+	cliReport.setARIWindow(domain, renewalInfo.SuggestedWindow)
+
 	now := time.Now().UTC()
 
 	renewalTime := renewalInfo.ShouldRenewAt(now, ctx.Duration(flgARIWaitToRenewDuration))
@@ -454,3 +632,18 @@ func merge(prevDomains, nextDomains []string) []string {
 
 	return prevDomains
 }
+
+// toASCIIDomains converts the internationalized domains to A-labels (ex: `münchen.example` -> `xn--mnchen-3ya.example`).
+func toASCIIDomains(domains []string) []string {
+	result := make([]string, 0, len(domains))
+
+	for _, domain := range domains {
+		if ascii, err := idna.ToASCII(domain); err == nil {
+			domain = ascii
+		}
+
+		result = append(result, domain)
+	}
+
+	return result
+}
@@ -108,7 +108,7 @@ func Test_needRenewal(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			actual := needRenewal(test.x509Cert, "foo.com", test.days, false)
+			actual := needRenewal(test.x509Cert, "foo.com", test.days, false, 0)
 
 			assert.Equal(t, test.expected, actual)
 		})
@@ -120,6 +120,7 @@ func Test_needRenewalDynamic(t *testing.T) {
 		desc                string
 		now                 time.Time
 		notBefore, notAfter time.Time
+		offset              time.Duration
 		expected            assert.BoolAssertionFunc
 	}{
 		{
@@ -136,6 +137,14 @@ func Test_needRenewalDynamic(t *testing.T) {
 			notAfter:  time.Date(2025, 1, 30, 1, 1, 1, 1, time.UTC),
 			expected:  assert.True,
 		},
+		{
+			desc:      "higher than 1/3 of the certificate lifetime left, moved earlier by the spread offset",
+			now:       time.Date(2025, 1, 19, 1, 1, 1, 1, time.UTC),
+			notBefore: time.Date(2025, 1, 1, 1, 1, 1, 1, time.UTC),
+			notAfter:  time.Date(2025, 1, 30, 1, 1, 1, 1, time.UTC),
+			offset:    72 * time.Hour,
+			expected:  assert.True,
+		},
 		{
 			desc:      "higher than 1/2 of the certificate lifetime left (lifetime < 10 days)",
 			now:       time.Date(2025, 1, 4, 1, 1, 1, 1, time.UTC),
@@ -161,9 +170,82 @@ func Test_needRenewalDynamic(t *testing.T) {
 				NotAfter:  test.notAfter,
 			}
 
-			ok := needRenewalDynamic(x509Cert, "example.com", test.now)
+			ok := needRenewalDynamic(x509Cert, "example.com", test.now, test.offset)
 
 			test.expected(t, ok)
 		})
 	}
 }
+
+func Test_evaluateThresholds(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc           string
+		x509Cert       *x509.Certificate
+		thresholds     renewalThresholds
+		expected       bool
+		expectedReason string
+	}{
+		{
+			desc:           "30 days, NotAfter 20 days",
+			x509Cert:       &x509.Certificate{NotAfter: now.Add(20 * 24 * time.Hour)},
+			thresholds:     renewalThresholds{Days: 30},
+			expected:       true,
+			expectedReason: "expires in 20 days (threshold: 30 days)",
+		},
+		{
+			desc:           "30 days, NotAfter 60 days",
+			x509Cert:       &x509.Certificate{NotAfter: now.Add(60 * 24 * time.Hour)},
+			thresholds:     renewalThresholds{Days: 30},
+			expectedReason: "expires in 60 days (threshold: 30 days)",
+		},
+		{
+			desc:           "negative days",
+			x509Cert:       &x509.Certificate{NotAfter: now.Add(60 * 24 * time.Hour)},
+			thresholds:     renewalThresholds{Days: -1},
+			expected:       true,
+			expectedReason: "--days is negative",
+		},
+		{
+			desc: "dynamic, renewal date reached",
+			x509Cert: &x509.Certificate{
+				NotBefore: now.Add(-70 * 24 * time.Hour),
+				NotAfter:  now.Add(20 * 24 * time.Hour),
+			},
+			thresholds:     renewalThresholds{Dynamic: true},
+			expected:       true,
+			expectedReason: "the dynamic renewal date (2025-05-22T00:00:00Z) is reached",
+		},
+		{
+			desc: "dynamic, renewal date not reached",
+			x509Cert: &x509.Certificate{
+				NotBefore: now.Add(-10 * 24 * time.Hour),
+				NotAfter:  now.Add(80 * 24 * time.Hour),
+			},
+			thresholds:     renewalThresholds{Dynamic: true},
+			expectedReason: "the dynamic renewal date is 2025-07-21T00:00:00Z",
+		},
+		{
+			desc:           "CA certificate",
+			x509Cert:       &x509.Certificate{IsCA: true, NotAfter: now},
+			thresholds:     renewalThresholds{Days: 30},
+			expectedReason: "the certificate bundle starts with a CA certificate",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			renew, reason := evaluateThresholds(test.x509Cert, now, test.thresholds)
+
+			assert.Equal(t, test.expected, renew)
+			assert.Equal(t, test.expectedReason, reason)
+		})
+	}
+}
+
+func Test_toASCIIDomains(t *testing.T) {
+	domains := toASCIIDomains([]string{"münchen.example", "*.bücher.example", "example.com"})
+
+	assert.Equal(t, []string{"xn--mnchen-3ya.example", "*.xn--bcher-kva.example", "example.com"}, domains)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/certcrypto"
 	"github.com/digicert/lego/v4/log"
 	"github.com/urfave/cli/v2"
 )
@@ -16,8 +17,12 @@ func createRevoke() *cli.Command {
 	return &cli.Command{
 		Name:   "revoke",
 		Usage:  "Revoke a certificate",
-		Action: revoke,
+		Action: withJSONReport(revoke),
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  flgName,
+				Usage: "The name of a stored certificate (see 'lego list'), instead of --" + flgDomains + ".",
+			},
 			&cli.BoolFlag{
 				Name:    flgKeep,
 				Aliases: []string{"k"},
@@ -38,16 +43,45 @@ func createRevoke() *cli.Command {
 }
 
 func revoke(ctx *cli.Context) error {
-	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
+	certsStorage := NewCertificatesStorage(ctx)
+
+	if ctx.IsSet(flgName) {
+		if len(ctx.StringSlice(flgDomains)) > 0 {
+			log.Fatalf("Please specify either --%s or --%s/-d", flgName, flgDomains)
+		}
+
+		metadata, err := readCertificateName(ctx, certsStorage)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// The certificate files are named after the main domain.
+		err = ctx.Set(flgDomains, metadata.Domain)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The certificates are revoked with the CA which issued them, unless another CA is explicitly defined.
+	for _, domain := range ctx.StringSlice(flgDomains) {
+		if !useCertificateOrigin(ctx, certsStorage, domain) {
+			log.Fatalf("The certificate for domain %s was not issued by %s.", domain, ctx.String(flgServer))
+		}
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	defer lockStorage(ctx, accountsStorage.backend)()
+
+	account, keyType := setupAccount(ctx, accountsStorage)
 
 	if account.Registration == nil {
 		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
 	}
 
-	client := newClient(ctx, account, keyType)
+	cliReport.setAccount(account, ctx.String(flgServer))
 
-	certsStorage := NewCertificatesStorage(ctx)
-	certsStorage.CreateRootFolder()
+	client := newClient(ctx, account, keyType)
 
 	for _, domain := range ctx.StringSlice(flgDomains) {
 		log.Printf("Trying to revoke certificate for domain %s", domain)
@@ -66,17 +100,22 @@ func revoke(ctx *cli.Context) error {
 
 		log.Println("Certificate was revoked.")
 
+		// The certificate has already been parsed by the revocation.
+		cert, _ := certcrypto.ParsePEMCertificate(certBytes)
+
 		if ctx.Bool(flgKeep) {
+			cliReport.addCertificate(domain, actionRevoked, cert, certificateFiles(certsStorage, domain, nil))
+
 			return nil
 		}
 
-		certsStorage.CreateArchiveFolder()
-
 		err = certsStorage.MoveToArchive(domain)
 		if err != nil {
 			return err
 		}
 
+		cliReport.addCertificate(domain, actionArchived, cert, nil)
+
 		log.Println("Certificate was archived for domain:", domain)
 	}
 
@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/certificate"
 	"github.com/digicert/lego/v4/lego"
 	"github.com/digicert/lego/v4/log"
@@ -47,7 +51,7 @@ func createRun() *cli.Command {
 
 			return nil
 		},
-		Action: run,
+		Action: withJSONReport(run),
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  flgNoBundle,
@@ -70,7 +74,7 @@ func createRun() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  flgPrivateKey,
-				Usage: "Path to private key (in PEM encoding) for the certificate. By default, the private key is generated.",
+				Usage: privateKeyUsage("the certificate"),
 			},
 			&cli.StringFlag{
 				Name: flgPreferredChain,
@@ -79,7 +83,7 @@ func createRun() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  flgProfile,
-				Usage: "If the CA offers multiple certificate profiles (draft-ietf-acme-profiles), choose this one.",
+				Usage: "If the CA offers multiple certificate profiles (draft-ietf-acme-profiles), choose this one. Fails if the CA doesn't advertise the profile.",
 			},
 			&cli.StringFlag{
 				Name:  flgAlwaysDeactivateAuthorizations,
@@ -112,6 +116,8 @@ server. Making regular backups of this folder is ideal.
 func run(ctx *cli.Context) error {
 	accountsStorage := NewAccountsStorage(ctx)
 
+	defer lockStorage(ctx, accountsStorage.backend)()
+
 	account, keyType := setupAccount(ctx, accountsStorage)
 
 	client := setupClient(ctx, account, keyType)
@@ -119,7 +125,7 @@ func run(ctx *cli.Context) error {
 	if account.Registration == nil {
 		reg, err := register(ctx, client)
 		if err != nil {
-			log.Fatalf("Could not complete registration\n\t%v", err)
+			log.Fatalf("Could not complete registration\n\t%s", explainError(ctx, err))
 		}
 
 		account.Registration = reg
@@ -127,30 +133,61 @@ func run(ctx *cli.Context) error {
 			log.Fatal(err)
 		}
 
-		fmt.Printf(rootPathWarningMessage, accountsStorage.GetRootPath())
+		fmt.Fprintf(textOutput(), rootPathWarningMessage, accountsStorage.GetRootPath())
 	}
 
+	cliReport.setAccount(account, ctx.String(flgServer))
+
 	certsStorage := NewCertificatesStorage(ctx)
-	certsStorage.CreateRootFolder()
+
+	if ctx.String(flgProfile) == "" {
+		logProfiles(client)
+	}
 
 	cert, err := obtainCertificate(ctx, client)
+	if err != nil && handleTOSChange(ctx, client, err) {
+		cert, err = obtainCertificate(ctx, client)
+	}
+
 	if err != nil {
 		// Make sure to return a non-zero exit code if ObtainSANCertificate returned at least one error.
 		// Due to us not returning partial certificate we can just exit here instead of at the end.
-		log.Fatalf("Could not obtain certificates:\n\t%v", err)
+		log.Fatalf("Could not obtain certificates:\n\t%s", explainError(ctx, err))
 	}
 
 	certsStorage.SaveResource(cert)
 
+	cliReport.addCertificate(cert.Domain, actionObtained, parseCertificate(cert), certificateFiles(certsStorage, cert.Domain, cert))
+
+	notifyObtained(ctx, cert)
+
 	meta := map[string]string{
 		hookEnvAccountEmail: account.Email,
 	}
 
 	addPathToMetadata(meta, cert.Domain, cert, certsStorage)
 
+	importToWindowsStore(ctx, cert, meta)
+
+	err = deployCertificate(ctx, cert)
+	if err != nil {
+		return err
+	}
+
 	return launchHook(ctx.String(flgRunHook), ctx.Duration(flgRunHookTimeout), meta)
 }
 
+// logProfiles advertises the certificate profiles offered by the CA, if any.
+func logProfiles(client *lego.Client) {
+	profiles := client.GetProfiles()
+	if len(profiles) == 0 {
+		return
+	}
+
+	log.Printf("The CA offers certificate profiles (use --%s to choose one): %s",
+		flgProfile, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+}
+
 func handleTOS(ctx *cli.Context, client *lego.Client) bool {
 	// Check for a global accept override
 	if ctx.Bool(flgAcceptTOS) {
@@ -162,7 +199,7 @@ func handleTOS(ctx *cli.Context, client *lego.Client) bool {
 	log.Printf("Please review the TOS at %s", client.GetToSURL())
 
 	for {
-		fmt.Println("Do you accept the TOS? Y/n")
+		fmt.Fprintln(textOutput(), "Do you accept the TOS? Y/n")
 
 		text, err := reader.ReadString('\n')
 		if err != nil {
@@ -176,11 +213,37 @@ func handleTOS(ctx *cli.Context, client *lego.Client) bool {
 		case "n", "N":
 			return false
 		default:
-			fmt.Println("Your input was invalid. Please answer with one of Y/y, n/N or by pressing enter.")
+			fmt.Fprintln(textOutput(), "Your input was invalid. Please answer with one of Y/y, n/N or by pressing enter.")
 		}
 	}
 }
 
+// handleTOSChange handles the userActionRequired error returned by the CA when the terms of service have changed.
+// It returns true if the new terms of service have been accepted and the request can be retried.
+func handleTOSChange(ctx *cli.Context, client *lego.Client, err error) bool {
+	var uarErr *acme.UserActionRequiredError
+	if !errors.As(err, &uarErr) || uarErr.TermsOfServiceURL == "" {
+		return false
+	}
+
+	log.Printf("The terms of service of the CA have changed. Please review the new TOS at %s", uarErr.TermsOfServiceURL)
+
+	if !ctx.Bool(flgAgreeTOSIfChanged) {
+		log.Printf("Use --%s to accept the new TOS.", flgAgreeTOSIfChanged)
+		return false
+	}
+
+	_, err = client.Registration.AcceptTermsOfService()
+	if err != nil {
+		log.Printf("Could not accept the new TOS: %v", err)
+		return false
+	}
+
+	log.Printf("The new TOS have been accepted, retrying.")
+
+	return true
+}
+
 func register(ctx *cli.Context, client *lego.Client) (*registration.Resource, error) {
 	accepted := handleTOS(ctx, client)
 	if !accepted {
@@ -202,12 +265,19 @@ func register(ctx *cli.Context, client *lego.Client) (*registration.Resource, er
 		})
 	}
 
+	if canFetchEAB(ctx) {
+		return registerWithCAPreset(ctx, client, getCAPreset(ctx), accepted)
+	}
+
 	return client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
 }
 
 func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Resource, error) {
 	bundle := !ctx.Bool(flgNoBundle)
 
+	obtainCtx, stop := withSignals(ctx.Context)
+	defer stop()
+
 	domains := ctx.StringSlice(flgDomains)
 	if len(domains) > 0 {
 		// obtain a certificate, generating a new private key
@@ -231,7 +301,7 @@ func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Reso
 			}
 		}
 
-		return client.Certificate.Obtain(request)
+		return client.Certificate.ObtainWithContext(obtainCtx, request)
 	}
 
 	// read the CSR
@@ -260,5 +330,5 @@ func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Reso
 		}
 	}
 
-	return client.Certificate.ObtainForCSR(request)
+	return client.Certificate.ObtainForCSRWithContext(obtainCtx, request)
 }
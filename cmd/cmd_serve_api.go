@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/digicert/lego/v4/apiserver"
+	"github.com/digicert/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgAPIListen        = "api.listen"
+	flgAPITenants       = "api.tenants"
+	flgAPITLSCert       = "api.tls-cert"
+	flgAPITLSKey        = "api.tls-key"
+	flgAPIRenewInterval = "api.renew-interval"
+	flgAPIQueueSize     = "api.queue-size"
+)
+
+// baseAPIFolderName the folder of the certificates managed by the API.
+const baseAPIFolderName = "api"
+
+func createServeAPI() *cli.Command {
+	return &cli.Command{
+		Name:   "serve-api",
+		Usage:  "Serve a REST API to obtain, renew, and revoke certificates on behalf of several tenants.",
+		Action: serveAPI,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  flgAPIListen,
+				Usage: "The address of the API server.",
+				Value: ":8443",
+			},
+			&cli.PathFlag{
+				Name:     flgAPITenants,
+				Usage:    "The TOML file defining the tenants of the API (tokens and allowed domains).",
+				Required: true,
+			},
+			&cli.PathFlag{
+				Name:  flgAPITLSCert,
+				Usage: "The certificate (PEM) of the API server. Without it, the API is served over plain HTTP.",
+			},
+			&cli.PathFlag{
+				Name:  flgAPITLSKey,
+				Usage: "The private key (PEM) of the API server.",
+			},
+			&cli.DurationFlag{
+				Name:  flgAPIRenewInterval,
+				Usage: "The interval between two checks of the certificates to renew.",
+				Value: time.Hour,
+			},
+			&cli.IntFlag{
+				Name:  flgAPIQueueSize,
+				Usage: "The maximum number of pending certificate requests.",
+				Value: 100,
+			},
+			&cli.BoolFlag{
+				Name:  flgNoBundle,
+				Usage: "Do not create a certificate bundle by adding the issuers certificate to the new certificate.",
+			},
+		},
+	}
+}
+
+func serveAPI(ctx *cli.Context) error {
+	tlsCert, tlsKey := ctx.Path(flgAPITLSCert), ctx.Path(flgAPITLSKey)
+	if (tlsCert == "") != (tlsKey == "") {
+		return fmt.Errorf("--%s and --%s must be used together", flgAPITLSCert, flgAPITLSKey)
+	}
+
+	tenants, err := apiserver.LoadTenants(ctx.Path(flgAPITenants))
+	if err != nil {
+		return err
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	account, keyType := setupAccount(ctx, accountsStorage)
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	client := setupClient(ctx, account, keyType)
+
+	server, err := apiserver.New(apiserver.Config{
+		Certifier:     client.Certificate,
+		Tenants:       tenants,
+		Storage:       newStorage(ctx),
+		Dir:           filepath.Join(ctx.String(flgPath), baseAPIFolderName),
+		Bundle:        !ctx.Bool(flgNoBundle),
+		RenewInterval: ctx.Duration(flgAPIRenewInterval),
+		QueueSize:     ctx.Int(flgAPIQueueSize),
+	})
+	if err != nil {
+		return err
+	}
+
+	runCtx, stop := signal.NotifyContext(ctx.Context, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{
+		Addr:              ctx.String(flgAPIListen),
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		log.Infof("API server listening on %s", httpServer.Addr)
+
+		if tlsCert != "" {
+			errCh <- httpServer.ListenAndServeTLS(tlsCert, tlsKey)
+		} else {
+			errCh <- httpServer.ListenAndServe()
+		}
+	}()
+
+	runDone := make(chan struct{})
+
+	go func() {
+		defer close(runDone)
+
+		_ = server.Run(runCtx)
+	}()
+
+	select {
+	case <-runCtx.Done():
+	case err = <-errCh:
+		stop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	errS := httpServer.Shutdown(shutdownCtx)
+
+	<-runDone
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("API server: %w", err)
+	}
+
+	return errS
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgMigrateFrom      = "from"
+	flgMigrateTo        = "to"
+	flgMigrateDryRun    = "dry-run"
+	flgMigrateOverwrite = "overwrite"
+)
+
+// migrateBackends the storage backends supported by the migration.
+var migrateBackends = []string{storageFileSystem, storageFile, storageS3}
+
+func createStorage() *cli.Command {
+	return &cli.Command{
+		Name:  "storage",
+		Usage: "Manage the storage of the accounts and the certificates.",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "migrate",
+				Usage:  "Copy the accounts and the certificates from a storage backend to another, and verify the copies.",
+				Action: migrateStorage,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     flgMigrateFrom,
+						Usage:    "The source storage backend. Supported: filesystem (or file), s3.",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     flgMigrateTo,
+						Usage:    "The destination storage backend. Supported: filesystem (or file), s3.",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  flgMigrateDryRun,
+						Usage: "Only display the entries to copy.",
+					},
+					&cli.BoolFlag{
+						Name:  flgMigrateOverwrite,
+						Usage: "Replace the entries already existing in the destination with a different content.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func migrateStorage(ctx *cli.Context) error {
+	from, to := ctx.String(flgMigrateFrom), ctx.String(flgMigrateTo)
+
+	for _, name := range []string{from, to} {
+		if !slices.Contains(migrateBackends, name) {
+			return fmt.Errorf("the storage backend %q is not supported by the migration (supported: %s)",
+				name, strings.Join(migrateBackends, ", "))
+		}
+	}
+
+	if normalizeStorageName(from) == normalizeStorageName(to) {
+		return errors.New("the source and the destination storages must be different")
+	}
+
+	src, err := newStorageByName(ctx, from)
+	if err != nil {
+		return fmt.Errorf("source storage: %w", err)
+	}
+
+	dst, err := newStorageByName(ctx, to)
+	if err != nil {
+		return fmt.Errorf("destination storage: %w", err)
+	}
+
+	defer lockStorage(ctx, src)()
+	defer lockStorage(ctx, dst)()
+
+	root := ctx.String(flgPath)
+
+	dirs := []string{
+		filepath.Join(root, baseAccountsRootFolderName),
+		filepath.Join(root, baseCertificatesFolderName),
+		filepath.Join(root, baseArchivesFolderName),
+	}
+
+	opts := storage.MigrateOptions{
+		Overwrite: ctx.Bool(flgMigrateOverwrite),
+		DryRun:    ctx.Bool(flgMigrateDryRun),
+	}
+
+	result, err := storage.Migrate(ctx.Context, src, dst, dirs, opts)
+	if result != nil {
+		for _, name := range result.Copied {
+			if opts.DryRun {
+				log.Printf("[dry-run] %s would be copied", name)
+			} else {
+				log.Printf("%s copied", name)
+			}
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Migration from %s to %s: %d entries copied, %d entries already up-to-date.",
+		from, to, len(result.Copied), len(result.Skipped))
+
+	return nil
+}
+
+func normalizeStorageName(name string) string {
+	switch name {
+	case storageFileSystem, storageFile, "":
+		return storageFileSystem
+	default:
+		return name
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/deploy"
+	"github.com/digicert/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// deployCertificate deploys the certificate to the targets defined by the "deploy" and "deploy.config" options.
+func deployCertificate(ctx *cli.Context, certRes *certificate.Resource) error {
+	targets := ctx.StringSlice(flgDeploy)
+
+	if filename := ctx.Path(flgDeployConfig); filename != "" {
+		config, err := deploy.LoadConfig(filename)
+		if err != nil {
+			return err
+		}
+
+		targets = slices.Concat(targets, config.Targets(certRes.Domain))
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	deployers, err := deploy.OpenAll(targets)
+	if err != nil {
+		return err
+	}
+
+	err = deployers.Deploy(ctx.Context, &deploy.Certificate{
+		Domain:            certRes.Domain,
+		Certificate:       certRes.Certificate,
+		PrivateKey:        certRes.PrivateKey,
+		IssuerCertificate: certRes.IssuerCertificate,
+	})
+	if err != nil {
+		return fmt.Errorf("deploy the certificate for %s: %w", certRes.Domain, err)
+	}
+
+	log.Infof("[%s] The certificate has been deployed", certRes.Domain)
+
+	return nil
+}
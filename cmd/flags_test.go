@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_durationValue(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []string
+		expected time.Duration
+	}{
+		{
+			desc:     "default",
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "seconds (legacy)",
+			args:     []string{"--timeout", "45"},
+			expected: 45 * time.Second,
+		},
+		{
+			desc:     "duration",
+			args:     []string{"--timeout", "2m"},
+			expected: 2 * time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var actual time.Duration
+
+			app := &cli.App{
+				Flags: []cli.Flag{
+					&cli.GenericFlag{Name: "timeout", Value: newDurationValue(30 * time.Second)},
+				},
+				Action: func(ctx *cli.Context) error {
+					actual = ctx.Duration("timeout")
+					return nil
+				},
+			}
+
+			err := app.Run(append([]string{"lego"}, test.args...))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_durationValue_invalid(t *testing.T) {
+	app := &cli.App{
+		Flags: []cli.Flag{
+			&cli.GenericFlag{Name: "timeout", Value: newDurationValue(30 * time.Second)},
+		},
+		Action: func(_ *cli.Context) error { return nil },
+	}
+
+	err := app.Run([]string{"lego", "--timeout", "abc"})
+	require.Error(t, err)
+}
@@ -60,7 +60,7 @@ func launchHook(hook string, timeout time.Duration, meta map[string]string) erro
 
 	scanner := bufio.NewScanner(stdout)
 	for scanner.Scan() {
-		fmt.Println(scanner.Text())
+		fmt.Fprintln(textOutput(), scanner.Text())
 	}
 
 	err = cmd.Wait()
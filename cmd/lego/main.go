@@ -35,6 +35,7 @@ func main() {
 	app.Flags = cmd.CreateFlags(defaultPath)
 
 	app.Before = cmd.Before
+	app.After = cmd.After
 
 	app.Commands = cmd.CreateCommands()
 
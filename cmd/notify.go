@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/notify"
+	"github.com/urfave/cli/v2"
+)
+
+// sendNotification sends a notification to the targets defined by the "notify" option.
+// The errors are only logged: a notification failure must not change the result of the command.
+func sendNotification(ctx *cli.Context, notification notify.Notification) {
+	targets := ctx.StringSlice(flgNotify)
+	if len(targets) == 0 {
+		return
+	}
+
+	var notifiers notify.Notifiers
+
+	for _, target := range targets {
+		notifier, err := notify.Open(target, nil)
+		if err != nil {
+			log.Warn("Could not create the notifier.", "error", err)
+			continue
+		}
+
+		notifiers = append(notifiers, notifier)
+	}
+
+	if notification.Time.IsZero() {
+		notification.Time = time.Now().UTC()
+	}
+
+	notifyCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := notifiers.Notify(notifyCtx, notification)
+	if err != nil {
+		log.Warn("Could not send the notification.", "domain", notification.Domain, "type", notification.Type, "error", err)
+	}
+}
+
+func notifyObtained(ctx *cli.Context, certRes *certificate.Resource) {
+	notification := notify.Notification{
+		Type:   notify.CertificateObtained,
+		Domain: certRes.Domain,
+	}
+
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err == nil && len(certificates) > 0 {
+		notification.Domains = certcrypto.ExtractDomains(certificates[0])
+		notification.NotAfter = certificates[0].NotAfter
+	}
+
+	sendNotification(ctx, notification)
+}
+
+func notifyRenewalFailed(ctx *cli.Context, domain string, cert *x509.Certificate, err error) {
+	sendNotification(ctx, notify.Notification{
+		Type:     notify.RenewalFailed,
+		Domain:   domain,
+		Domains:  certcrypto.ExtractDomains(cert),
+		NotAfter: cert.NotAfter,
+		Error:    err.Error(),
+	})
+}
+
+// notifyExpiryApproaching sends a notification if a certificate, that has not been renewed, expires soon.
+func notifyExpiryApproaching(ctx *cli.Context, domain string, cert *x509.Certificate) {
+	days := ctx.Int(flgNotifyExpiryDays)
+	if days <= 0 {
+		return
+	}
+
+	if time.Until(cert.NotAfter) > time.Duration(days)*24*time.Hour {
+		return
+	}
+
+	sendNotification(ctx, notify.Notification{
+		Type:     notify.ExpiryApproaching,
+		Domain:   domain,
+		Domains:  certcrypto.ExtractDomains(cert),
+		NotAfter: cert.NotAfter,
+	})
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/urfave/cli/v2"
+)
+
+// Actions on the certificates, reported with `--json`.
+const (
+	actionObtained   = "obtained"
+	actionRenewed    = "renewed"
+	actionNotRenewed = "not-renewed"
+	actionRevoked    = "revoked"
+	actionArchived   = "archived"
+)
+
+// jsonCommands the commands supporting `--json`.
+var jsonCommands = []string{"run", "renew", "list", "revoke"}
+
+// cliReport is the report of the current command, when `--json` is used.
+var cliReport *jsonReport
+
+// jsonReport is the machine-readable result of a command (`--json`).
+// It's written to stdout when the command ends, including on failure; the logs are still written to stderr.
+type jsonReport struct {
+	Command      string             `json:"command"`
+	Success      bool               `json:"success"`
+	Account      *jsonAccount       `json:"account,omitempty"`
+	Accounts     []jsonAccount      `json:"accounts,omitempty"`
+	Certificates []*jsonCertificate `json:"certificates,omitempty"`
+	Plan         []renewalPlanEntry `json:"plan,omitempty"`
+	Errors       []string           `json:"errors,omitempty"`
+
+	w    io.Writer
+	mu   sync.Mutex
+	once sync.Once
+}
+
+type jsonAccount struct {
+	Email  string `json:"email"`
+	Server string `json:"server,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+type jsonCertificate struct {
+	Name     string     `json:"name"`
+	Action   string     `json:"action,omitempty"`
+	Domains  []string   `json:"domains,omitempty"`
+	IPs      []string   `json:"ips,omitempty"`
+	NotAfter *time.Time `json:"notAfter,omitempty"`
+
+	// Files the paths of the certificate files (certificate, key, issuer, pem, pfx).
+	Files map[string]string `json:"files,omitempty"`
+
+	// ARIWindow the renewal window suggested by the CA (ARI), if any.
+	ARIWindow *acme.Window `json:"ariWindow,omitempty"`
+}
+
+func newJSONReport(w io.Writer, command string) *jsonReport {
+	return &jsonReport{Command: command, w: w}
+}
+
+// textOutput returns the writer of the messages for the user:
+// stderr with `--json`, to keep stdout for the report.
+func textOutput() io.Writer {
+	if cliReport != nil {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// withJSONReport wraps the action of a command to write the report when the command ends.
+func withJSONReport(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if cliReport == nil {
+			return action(ctx)
+		}
+
+		err := action(ctx)
+		if err != nil {
+			cliReport.addError(err.Error())
+		}
+
+		cliReport.write()
+
+		return err
+	}
+}
+
+func (r *jsonReport) setAccount(account *Account, server string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Account = &jsonAccount{Email: account.Email, Server: server}
+
+	if account.Registration != nil {
+		r.Account.URL = account.Registration.URI
+	}
+}
+
+func (r *jsonReport) addAccount(account jsonAccount) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Accounts = append(r.Accounts, account)
+}
+
+func (r *jsonReport) setPlan(plan []renewalPlanEntry) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Plan = plan
+}
+
+// addCertificate records a certificate, and what the command did with it.
+func (r *jsonReport) addCertificate(name, action string, cert *x509.Certificate, files map[string]string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.certificate(name)
+	entry.Action = action
+	entry.Files = files
+
+	if cert != nil {
+		entry.Domains = cert.DNSNames
+		entry.NotAfter = &cert.NotAfter
+
+		for _, ip := range cert.IPAddresses {
+			entry.IPs = append(entry.IPs, ip.String())
+		}
+	}
+}
+
+func (r *jsonReport) setARIWindow(name string, window acme.Window) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.certificate(name).ARIWindow = &window
+}
+
+// certificate returns the entry of a certificate, and creates it if needed.
+func (r *jsonReport) certificate(name string) *jsonCertificate {
+	for _, entry := range r.Certificates {
+		if entry.Name == name {
+			return entry
+		}
+	}
+
+	entry := &jsonCertificate{Name: name}
+	r.Certificates = append(r.Certificates, entry)
+
+	return entry
+}
+
+func (r *jsonReport) addError(msg string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Errors = append(r.Errors, msg)
+}
+
+// write writes the report, only once: the report of a failed command can be written by the exit hook of the logger.
+func (r *jsonReport) write() {
+	if r == nil {
+		return
+	}
+
+	r.once.Do(func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.Success = len(r.Errors) == 0
+
+		encoder := json.NewEncoder(r.w)
+		encoder.SetIndent("", "\t")
+
+		_ = encoder.Encode(r)
+	})
+}
+
+// certificateFiles returns the paths of the files of a certificate.
+func certificateFiles(certsStorage *CertificatesStorage, domain string, certRes *certificate.Resource) map[string]string {
+	files := map[string]string{
+		"certificate": certsStorage.GetFileName(domain, certExt),
+	}
+
+	if certRes == nil || certRes.PrivateKey != nil {
+		files["key"] = certsStorage.GetFileName(domain, keyExt)
+	}
+
+	if certRes == nil || certRes.IssuerCertificate != nil {
+		files["issuer"] = certsStorage.GetFileName(domain, issuerExt)
+	}
+
+	if certsStorage.pem {
+		files["pem"] = certsStorage.GetFileName(domain, pemExt)
+	}
+
+	if certsStorage.pfx {
+		files["pfx"] = certsStorage.GetFileName(domain, pfxExt)
+	}
+
+	return files
+}
+
+// parseCertificate parses the certificate of a resource, for the report.
+func parseCertificate(certRes *certificate.Resource) *x509.Certificate {
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return nil
+	}
+
+	return cert
+}
+
+// reportHandler records the errors logged (including by log.Fatal) in the report.
+type reportHandler struct {
+	slog.Handler
+
+	report *jsonReport
+}
+
+// Enabled always accepts the errors: they are recorded even if the logger drops them.
+func (h reportHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelError || h.Handler.Enabled(ctx, level)
+}
+
+func (h reportHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError {
+		msg := record.Message
+
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == "error" {
+				msg = strings.TrimSuffix(msg, ".") + ": " + attr.Value.String()
+				return false
+			}
+
+			return true
+		})
+
+		h.report.addError(msg)
+	}
+
+	if !h.Handler.Enabled(ctx, record.Level) {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h reportHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return reportHandler{Handler: h.Handler.WithAttrs(attrs), report: h.report}
+}
+
+func (h reportHandler) WithGroup(name string) slog.Handler {
+	return reportHandler{Handler: h.Handler.WithGroup(name), report: h.report}
+}
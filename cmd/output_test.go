@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_jsonReport_write(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	report := newJSONReport(buf, "renew")
+
+	report.setAccount(&Account{
+		Email:        "test@example.com",
+		Registration: &registration.Resource{URI: "https://ca.example.com/acme/acct/1"},
+	}, "https://ca.example.com/dir")
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	report.setARIWindow("example.com", acme.Window{Start: start, End: start.Add(24 * time.Hour)})
+	report.addCertificate("example.com", actionRenewed, nil, map[string]string{"certificate": "/tmp/example.com.crt"})
+
+	report.write()
+
+	// The report is written only once.
+	report.addError("ignored")
+	report.write()
+
+	expected := `{
+	"command": "renew",
+	"success": true,
+	"account": {
+		"email": "test@example.com",
+		"server": "https://ca.example.com/dir",
+		"url": "https://ca.example.com/acme/acct/1"
+	},
+	"certificates": [
+		{
+			"name": "example.com",
+			"action": "renewed",
+			"files": {
+				"certificate": "/tmp/example.com.crt"
+			},
+			"ariWindow": {
+				"start": "2026-10-01T00:00:00Z",
+				"end": "2026-10-02T00:00:00Z"
+			}
+		}
+	]
+}
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func Test_reportHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	report := newJSONReport(buf, "run")
+
+	logger := slog.New(reportHandler{Handler: slog.DiscardHandler, report: report})
+
+	logger.Info("not an error")
+	logger.Error("Could not obtain certificates.", "error", errors.New("acme: error: 403"))
+
+	report.write()
+
+	expected := `{
+	"command": "run",
+	"success": false,
+	"errors": [
+		"Could not obtain certificates: acme: error: 403"
+	]
+}
+`
+
+	require.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/lego"
+	"github.com/urfave/cli/v2"
+)
+
+// renewalPlanEntry is the evaluation of a stored certificate by `renew --plan`.
+type renewalPlanEntry struct {
+	Name     string    `json:"name"`
+	Domains  []string  `json:"domains"`
+	Server   string    `json:"server,omitempty"`
+	NotAfter time.Time `json:"notAfter"`
+	Renew    bool      `json:"renew"`
+	Reason   string    `json:"reason"`
+
+	// ARIWindowStart the start of the renewal window suggested by the CA (ARI), if any.
+	ARIWindowStart *time.Time `json:"ariWindowStart,omitempty"`
+}
+
+// renewalThresholds are the renewal conditions of the certificates, without ARI.
+type renewalThresholds struct {
+	Days    int
+	Dynamic bool
+	Spread  time.Duration
+}
+
+// renewPlan evaluates every stored certificate against ARI and the renewal thresholds,
+// and displays which ones would be renewed.
+// The challenges are not solved, and no certificate is modified.
+func renewPlan(ctx *cli.Context) error {
+	format := ctx.String(flgRenewPlanFormat)
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported plan format: %s", format)
+	}
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	names, err := storedCertificateNames(certsStorage)
+	if err != nil {
+		return err
+	}
+
+	thresholds := renewalThresholds{
+		Days:    ctx.Int(flgRenewDays),
+		Dynamic: ctx.Bool(flgRenewDynamic),
+		Spread:  ctx.Duration(flgRenewSpread),
+	}
+
+	// The ARI endpoint doesn't require an account: a client by CA is enough.
+	clients := map[string]*lego.Client{}
+
+	plan := make([]renewalPlanEntry, 0, len(names))
+
+	for _, name := range names {
+		entry, cert, ari, errE := readPlanEntry(ctx, certsStorage, name)
+		if errE != nil {
+			plan = append(plan, renewalPlanEntry{Name: name, Reason: errE.Error()})
+			continue
+		}
+
+		now := time.Now().UTC()
+
+		if ari && !ctx.Bool(flgARIDisable) {
+			client, errC := planClient(ctx, clients, entry.Server)
+			if errC != nil {
+				entry.Reason = fmt.Sprintf("ARI unavailable (%v)", errC)
+			} else {
+				entry.ARIWindowStart, entry.Renew, entry.Reason = evaluateARI(client, cert, now, ctx.Duration(flgARIWaitToRenewDuration))
+			}
+		}
+
+		if !entry.Renew {
+			var reason string
+
+			entry.Renew, reason = evaluateThresholds(cert, now, thresholds)
+
+			entry.Reason = strings.Join(nonEmpty(entry.Reason, reason), "; ")
+		}
+
+		plan = append(plan, entry)
+	}
+
+	if cliReport != nil {
+		cliReport.setPlan(plan)
+
+		return nil
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "\t")
+
+		return encoder.Encode(plan)
+	}
+
+	printRenewalPlan(plan)
+
+	return nil
+}
+
+// storedCertificateNames returns the names of the stored certificates.
+func storedCertificateNames(certsStorage *CertificatesStorage) ([]string, error) {
+	entries, err := certsStorage.backend.List(context.Background(), certsStorage.GetRootPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		if filepath.Dir(entry) != filepath.Clean(certsStorage.GetRootPath()) ||
+			filepath.Ext(entry) != certExt || strings.HasSuffix(entry, issuerExt) {
+			continue
+		}
+
+		names = append(names, strings.TrimSuffix(filepath.Base(entry), certExt))
+	}
+
+	return names, nil
+}
+
+// readPlanEntry reads a stored certificate, and the CA which renews it.
+// As with `renew`, ARI is not used if the certificate was issued by another CA than the explicitly defined one.
+func readPlanEntry(ctx *cli.Context, certsStorage *CertificatesStorage, name string) (renewalPlanEntry, *x509.Certificate, bool, error) {
+	entry := renewalPlanEntry{Name: name, Server: ctx.String(flgServer)}
+
+	certificates, err := certsStorage.ReadCertificate(name, certExt)
+	if err != nil {
+		return entry, nil, false, fmt.Errorf("unable to read the certificate: %w", err)
+	}
+
+	cert := certificates[0]
+
+	entry.Domains = certcrypto.ExtractDomains(cert)
+	entry.NotAfter = cert.NotAfter
+
+	if !certsStorage.ExistsFile(name, resourceExt) {
+		return entry, cert, true, nil
+	}
+
+	metadata, err := certsStorage.ReadMetadata(name)
+	if err != nil || metadata.Server == "" {
+		return entry, cert, true, nil
+	}
+
+	if !ctx.IsSet(flgServer) {
+		entry.Server = metadata.Server
+
+		return entry, cert, true, nil
+	}
+
+	return entry, cert, sameServer(entry.Server, metadata.Server), nil
+}
+
+// planClient returns a client for the CA, used only to call the ARI endpoint.
+func planClient(ctx *cli.Context, clients map[string]*lego.Client, server string) (*lego.Client, error) {
+	if client, ok := clients[server]; ok {
+		return client, nil
+	}
+
+	privateKey, err := certcrypto.GeneratePrivateKey(certcrypto.EC256)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := lego.NewClient(newClientConfig(ctx, server, &Account{key: privateKey}, certcrypto.EC256))
+	if err != nil {
+		return nil, err
+	}
+
+	clients[server] = client
+
+	return client, nil
+}
+
+// evaluateARI evaluates the renewal of the certificate with the renewal window suggested by the CA.
+func evaluateARI(client *lego.Client, cert *x509.Certificate, now time.Time, willingToSleep time.Duration) (*time.Time, bool, string) {
+	renewalInfo, err := client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	if err != nil {
+		if errors.Is(err, api.ErrNoARI) {
+			return nil, false, ""
+		}
+
+		return nil, false, fmt.Sprintf("ARI unavailable (%v)", err)
+	}
+
+	windowStart := renewalInfo.SuggestedWindow.Start.UTC()
+
+	return &windowStart, renewalInfo.ShouldRenewAt(now, willingToSleep) != nil,
+		fmt.Sprintf("the renewal window suggested by the CA (ARI) starts at %s", windowStart.Format(time.RFC3339))
+}
+
+// evaluateThresholds evaluates the renewal of the certificate with the thresholds,
+// as the `renew` command does when ARI doesn't require the renewal.
+func evaluateThresholds(cert *x509.Certificate, now time.Time, thresholds renewalThresholds) (bool, string) {
+	if cert.IsCA {
+		return false, "the certificate bundle starts with a CA certificate"
+	}
+
+	offset := certificate.RenewalSpread(cert, thresholds.Spread)
+
+	if thresholds.Dynamic {
+		dueDate := dynamicRenewalDate(cert, offset)
+		if dueDate.Before(now) {
+			return true, fmt.Sprintf("the dynamic renewal date (%s) is reached", dueDate.Format(time.RFC3339))
+		}
+
+		return false, fmt.Sprintf("the dynamic renewal date is %s", dueDate.Format(time.RFC3339))
+	}
+
+	if thresholds.Days < 0 {
+		return true, fmt.Sprintf("--%s is negative", flgRenewDays)
+	}
+
+	days := int((cert.NotAfter.Sub(now) - offset).Hours() / 24.0)
+
+	return days <= thresholds.Days, fmt.Sprintf("expires in %d days (threshold: %d days)", days, thresholds.Days)
+}
+
+func printRenewalPlan(plan []renewalPlanEntry) {
+	if len(plan) == 0 {
+		fmt.Println("No certificates found.")
+		return
+	}
+
+	var count int
+
+	for _, entry := range plan {
+		if entry.Renew {
+			count++
+		}
+	}
+
+	fmt.Printf("Renewal plan: %d of %d certificates would be renewed.\n", count, len(plan))
+
+	for _, entry := range plan {
+		decision := "no renewal"
+		if entry.Renew {
+			decision = "renewal"
+		}
+
+		fmt.Printf("  %s: %s (%s)\n", entry.Name, decision, entry.Reason)
+
+		if len(entry.Domains) > 0 {
+			fmt.Println("    Domains:", strings.Join(entry.Domains, ", "))
+			fmt.Println("    Expiry Date:", entry.NotAfter)
+		}
+
+		if entry.Server != "" {
+			fmt.Println("    Server:", entry.Server)
+		}
+	}
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
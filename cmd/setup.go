@@ -13,7 +13,10 @@ import (
 	"time"
 
 	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/audit"
+	"github.com/digicert/lego/v4/ca"
 	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/errcatalog"
 	"github.com/digicert/lego/v4/lego"
 	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/registration"
@@ -21,8 +24,6 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
-const filePerm os.FileMode = 0o600
-
 // setupClient creates a new client with challenge settings.
 func setupClient(ctx *cli.Context, account *Account, keyType certcrypto.KeyType) *lego.Client {
 	client := newClient(ctx, account, keyType)
@@ -47,15 +48,44 @@ func setupAccount(ctx *cli.Context, accountsStorage *AccountsStorage) (*Account,
 }
 
 func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyType) *lego.Client {
+	client, err := lego.NewClient(newClientConfig(ctx, ctx.String(flgServer), acc, keyType))
+	if err != nil {
+		log.Fatalf("Could not create client: %v", err)
+	}
+
+	if client.GetExternalAccountRequired() && !ctx.IsSet(flgEAB) && !canFetchEAB(ctx) {
+		log.Fatalf("Server requires External Account Binding. Use --%s with --%s and --%s.", flgEAB, flgKID, flgHMAC)
+	}
+
+	return client
+}
+
+// newClientConfig creates the configuration of a client for the CA directory.
+func newClientConfig(ctx *cli.Context, server string, acc registration.User, keyType certcrypto.KeyType) *lego.Config {
 	config := lego.NewConfig(acc)
-	config.CADirURL = ctx.String(flgServer)
+	config.CADirURL = server
 
 	config.Certificate = lego.CertificateConfig{
 		KeyType:             keyType,
-		Timeout:             time.Duration(ctx.Int(flgCertTimeout)) * time.Second,
+		Timeout:             ctx.Duration(flgCertTimeout),
+		OrderTimeout:        ctx.Duration(flgCertOrderTimeout),
+		ChallengeTimeout:    ctx.Duration(flgCertChallengeTimeout),
+		CleanupTimeout:      ctx.Duration(flgCertCleanupTimeout),
+		CleanupRetries:      ctx.Int(flgCertCleanupRetries),
+		ConcurrentSolves:    ctx.Int(flgMaxParallelSolves),
 		OverallRequestLimit: ctx.Int(flgOverallRequestLimit),
 		DisableCommonName:   ctx.Bool(flgDisableCommonName),
 	}
+
+	if target := ctx.String(flgAuditLog); target != "" {
+		auditLogger, err := audit.Open(target)
+		if err != nil {
+			log.Fatalf("Could not open the audit log: %v", err)
+		}
+
+		config.Certificate.AuditLogger = auditLogger
+	}
+
 	config.UserAgent = getUserAgent(ctx)
 
 	if ctx.IsSet(flgHTTPTimeout) {
@@ -78,44 +108,35 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 	retryClient.Logger = nil
 
 	if _, v := os.LookupEnv("LEGO_DEBUG_ACME_HTTP_CLIENT"); v {
-		retryClient.Logger = log.Logger
+		retryClient.Logger = log.Default()
 	}
 
 	config.HTTPClient = retryClient.StandardClient()
 
-	client, err := lego.NewClient(config)
+	return config
+}
+
+// getKeyType the type from which private keys should be generated.
+// The key type is checked against the key policy of the CA, if known.
+func getKeyType(ctx *cli.Context) certcrypto.KeyType {
+	keyType, err := certcrypto.ParseKeyType(ctx.String(flgKeyType))
 	if err != nil {
-		log.Fatalf("Could not create client: %v", err)
+		log.Fatalf("Invalid --%s: %v", flgKeyType, err)
 	}
 
-	if client.GetExternalAccountRequired() && !ctx.IsSet(flgEAB) {
-		log.Fatalf("Server requires External Account Binding. Use --%s with --%s and --%s.", flgEAB, flgKID, flgHMAC)
+	preset := getCAPreset(ctx)
+	if preset == nil {
+		preset = ca.GetByDirectory(ctx.String(flgServer))
 	}
 
-	return client
-}
+	if preset != nil {
+		err = preset.KeyPolicy.Check(keyType)
+		if err != nil {
+			log.Fatalf("The key type %s cannot be used with %s: %v", ctx.String(flgKeyType), preset.Name, err)
+		}
+	}
 
-// getKeyType the type from which private keys should be generated.
-func getKeyType(ctx *cli.Context) certcrypto.KeyType {
-	keyType := ctx.String(flgKeyType)
-	switch strings.ToUpper(keyType) {
-	case "RSA2048":
-		return certcrypto.RSA2048
-	case "RSA3072":
-		return certcrypto.RSA3072
-	case "RSA4096":
-		return certcrypto.RSA4096
-	case "RSA8192":
-		return certcrypto.RSA8192
-	case "EC256":
-		return certcrypto.EC256
-	case "EC384":
-		return certcrypto.EC384
-	}
-
-	log.Fatalf("Unsupported KeyType: %s", keyType)
-
-	return ""
+	return keyType
 }
 
 func getUserAgent(ctx *cli.Context) string {
@@ -210,3 +231,13 @@ func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, erro
 
 	return rt, nil
 }
+
+// explainError formats the error with the remediations of the known failures, according to --log.error-verbosity.
+func explainError(ctx *cli.Context, err error) string {
+	verbosity, errV := errcatalog.ParseVerbosity(ctx.String(flgLogErrorVerbosity))
+	if errV != nil {
+		verbosity = errcatalog.VerbosityRaw
+	}
+
+	return errcatalog.Format(err, verbosity)
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"crypto"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -20,8 +23,8 @@ import (
 )
 
 func setupChallenges(ctx *cli.Context, client *lego.Client) {
-	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) {
-		log.Fatalf("No challenge selected. You must specify at least one challenge: `--%s`, `--%s`, `--%s`.", flgHTTP, flgTLS, flgDNS)
+	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) && !ctx.IsSet(flgOnion) {
+		log.Fatalf("No challenge selected. You must specify at least one challenge: `--%s`, `--%s`, `--%s`, `--%s`.", flgHTTP, flgTLS, flgDNS, flgOnion)
 	}
 
 	if ctx.Bool(flgHTTP) {
@@ -44,6 +47,66 @@ func setupChallenges(ctx *cli.Context, client *lego.Client) {
 			log.Fatal(err)
 		}
 	}
+
+	if ctx.IsSet(flgOnion) {
+		err := setupOnion(ctx, client)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if ctx.IsSet(flgChallengePreference) {
+		err := setupChallengePreferences(ctx, client)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// setupChallengePreferences defines the challenge types by domain pattern.
+// The values are <pattern>=<type>, the types of the same pattern are kept in order.
+func setupChallengePreferences(ctx *cli.Context, client *lego.Client) error {
+	var patterns []string
+
+	preferences := map[string][]challenge.Type{}
+
+	for _, value := range ctx.StringSlice(flgChallengePreference) {
+		pattern, chlgType, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(chlgType) == "" {
+			return fmt.Errorf("invalid challenge preference %q: the format is <pattern>=<type>", value)
+		}
+
+		pattern = strings.TrimSpace(pattern)
+
+		if _, exists := preferences[pattern]; !exists {
+			patterns = append(patterns, pattern)
+		}
+
+		preferences[pattern] = append(preferences[pattern], challenge.Type(strings.TrimSpace(chlgType)))
+	}
+
+	for _, pattern := range patterns {
+		err := client.Challenge.SetDomainChallengePreference(pattern, preferences[pattern]...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setupOnion(ctx *cli.Context, client *lego.Client) error {
+	privateKey, err := loadPrivateKey(ctx.String(flgOnion))
+	if err != nil {
+		return fmt.Errorf("load the key of the hidden service: %w", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("the key of the hidden service is not a signer: %T", privateKey)
+	}
+
+	return client.Challenge.SetOnionCSR01Key(signer)
 }
 
 //nolint:gocyclo // the complexity is expected.
@@ -57,7 +120,7 @@ func setupHTTPProvider(ctx *cli.Context) challenge.Provider {
 
 		return ps
 	case ctx.IsSet(flgHTTPMemcachedHost):
-		ps, err := memcached.NewMemcachedProvider(ctx.StringSlice(flgHTTPMemcachedHost))
+		ps, err := memcached.NewMemcachedProviderConfig(memcachedConfig(ctx))
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -81,25 +144,75 @@ func setupHTTPProvider(ctx *cli.Context) challenge.Provider {
 			log.Fatal(err)
 		}
 
-		srv := http01.NewProviderServer(host, port)
-		if header := ctx.String(flgHTTPProxyHeader); header != "" {
-			srv.SetProxyHeader(header)
+		return setupProviderServer(ctx, http01.NewProviderServer(host, port))
+	case ctx.Bool(flgHTTP):
+		return setupProviderServer(ctx, http01.NewProviderServer("", ""))
+	default:
+		log.Fatal("Invalid HTTP challenge options.")
+		return nil
+	}
+}
+
+func setupProviderServer(ctx *cli.Context, srv *http01.ProviderServer) *http01.ProviderServer {
+	if header := ctx.String(flgHTTPProxyHeader); header != "" {
+		srv.SetProxyHeader(header)
+	}
+
+	for _, address := range ctx.StringSlice(flgHTTPListen) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			log.Fatalf("The --%s switch only accepts interface:port or :port for its argument: %v", flgHTTPListen, err)
 		}
 
-		return srv
-	case ctx.Bool(flgHTTP):
-		srv := http01.NewProviderServer("", "")
-		if header := ctx.String(flgHTTPProxyHeader); header != "" {
-			srv.SetProxyHeader(header)
+		srv.AddListener(listenNetwork(host), address)
+	}
+
+	srv.SetReadyHook(func(status http01.ListenerStatus) {
+		if status.Ready() {
+			log.Info("The HTTP-01 challenge server is listening.", "network", status.Network, "address", status.BoundAddress)
 		}
+	})
+
+	return srv
+}
+
+// listenNetwork returns the network of a listened host: tcp4 or tcp6 for an IP address, tcp otherwise.
+func listenNetwork(host string) string {
+	ip := net.ParseIP(host)
 
-		return srv
+	switch {
+	case ip == nil:
+		return "tcp"
+	case ip.To4() != nil:
+		return "tcp4"
 	default:
-		log.Fatal("Invalid HTTP challenge options.")
-		return nil
+		return "tcp6"
 	}
 }
 
+func memcachedConfig(ctx *cli.Context) *memcached.Config {
+	config := memcached.NewDefaultConfig()
+	config.Hosts = ctx.StringSlice(flgHTTPMemcachedHost)
+	config.Username = ctx.String(flgHTTPMemcachedUsername)
+	config.Password = ctx.String(flgHTTPMemcachedPassword)
+	config.Quorum = ctx.Int(flgHTTPMemcachedQuorum)
+
+	if ctx.Bool(flgHTTPMemcachedTLS) || ctx.IsSet(flgHTTPMemcachedTLSCA) {
+		config.TLSConfig = &tls.Config{}
+
+		if ctx.IsSet(flgHTTPMemcachedTLSCA) {
+			pool, err := lego.CreateCertPool([]string{ctx.String(flgHTTPMemcachedTLSCA)}, false)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			config.TLSConfig.RootCAs = pool
+		}
+	}
+
+	return config
+}
+
 func setupTLSProvider(ctx *cli.Context) challenge.Provider {
 	switch {
 	case ctx.IsSet(flgTLSPort):
@@ -133,6 +246,11 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 		return fmt.Errorf("'%s' cannot be negative", flgDNSPropagationWait)
 	}
 
+	retries := ctx.Int(flgDNSProviderRetries)
+	if retries < 0 {
+		return fmt.Errorf("'%s' cannot be negative", flgDNSProviderRetries)
+	}
+
 	provider, err := dns.NewDNSChallengeProviderByName(ctx.String(flgDNS))
 	if err != nil {
 		return err
@@ -157,9 +275,25 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 
 		dns01.CondOption(ctx.IsSet(flgDNSTimeout),
 			dns01.AddDNSTimeout(time.Duration(ctx.Int(flgDNSTimeout))*time.Second)),
+
+		dns01.CondOption(retries > 0,
+			dns01.WithProviderRetry(retries+1, ctx.Duration(flgDNSProviderRetryDelay))),
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// After the setup of the provider: the check uses the resolvers defined by the options.
+	if ctx.Bool(flgDNSCheckZoneOwnership) {
+		err = dns01.CheckZoneOwnership(provider, ctx.StringSlice(flgDomains)...)
+		if errors.Is(err, dns01.ErrZoneNameserversUnsupported) {
+			log.Warnf("The zone ownership check is skipped: %v.", err)
+		} else if err != nil {
+			return fmt.Errorf("zone ownership: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func checkPropagationExclusiveOptions(ctx *cli.Context) error {
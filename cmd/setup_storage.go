@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/storage"
+	"github.com/digicert/lego/v4/storage/s3"
+	"github.com/urfave/cli/v2"
+)
+
+// Storage backend names.
+const (
+	storageFileSystem = "filesystem"
+	storageFile       = "file" // alias of filesystem.
+	storageS3         = "s3"
+)
+
+// storageLockName the name of the lock of the storage root.
+const storageLockName = "storage"
+
+// newStorage creates the storage backend selected by the "storage" option.
+func newStorage(ctx *cli.Context) storage.Storage {
+	backend, err := newStorageByName(ctx, ctx.String(flgStorage))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return backend
+}
+
+func newStorageByName(ctx *cli.Context, name string) (storage.Storage, error) {
+	switch name {
+	case storageFileSystem, storageFile, "":
+		return storage.NewFileSystem(), nil
+
+	case storageS3:
+		config := s3.NewDefaultConfig()
+		config.Bucket = ctx.String(flgStorageS3Bucket)
+		config.Prefix = ctx.String(flgStorageS3Prefix)
+		config.Endpoint = ctx.String(flgStorageS3Endpoint)
+		config.UsePathStyle = ctx.Bool(flgStorageS3PathStyle)
+		config.SSEKMSKeyID = ctx.String(flgStorageS3SSEKMSKeyID)
+
+		return s3.NewStorage(config)
+
+	default:
+		return nil, fmt.Errorf("unsupported storage: %q", name)
+	}
+}
+
+// lockStorage acquires the lock of the storage root if the storage supports locking,
+// so several lego instances cannot update the accounts and the certificates at the same time.
+// The lock held by another instance is awaited at most during the lock timeout.
+// The returned function releases the lock.
+func lockStorage(ctx *cli.Context, backend storage.Storage) func() {
+	locker, ok := backend.(storage.Locker)
+	if !ok {
+		return func() {}
+	}
+
+	name := filepath.Join(ctx.String(flgPath), storageLockName)
+
+	lockCtx, cancel := context.WithTimeout(ctx.Context, ctx.Duration(flgStorageLockTimeout))
+	defer cancel()
+
+	err := locker.Lock(lockCtx, name)
+	if err != nil {
+		log.Fatalf("Could not lock %s: %v", name, err)
+	}
+
+	return func() {
+		unlockCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx.Context), time.Minute)
+		defer cancel()
+
+		err := locker.Unlock(unlockCtx, name)
+		if err != nil {
+			log.Printf("Could not unlock %s: %v", name, err)
+		}
+	}
+}
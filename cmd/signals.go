@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/digicert/lego/v4/log"
+)
+
+// withSignals returns a context canceled on SIGINT or SIGTERM.
+// The issuance stops gracefully: the challenges already presented are cleaned up (TXT records, listeners) before the exit.
+// After the first signal, the default behavior is restored: a second signal stops the process immediately.
+func withSignals(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer signal.Stop(signals)
+
+		select {
+		case sig := <-signals:
+			log.Warnf("Received %s: stopping, the challenges already presented are cleaned up. Send the signal again to stop immediately.", sig)
+
+			cancel(fmt.Errorf("received %s", sig))
+
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+
+	return ctx, func() {
+		once.Do(func() {
+			close(done)
+			cancel(nil)
+		})
+	}
+}
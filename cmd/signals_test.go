@@ -0,0 +1,39 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_withSignals(t *testing.T) {
+	ctx, stop := withSignals(t.Context())
+	defer stop()
+
+	err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	require.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context is not canceled")
+	}
+
+	assert.EqualError(t, context.Cause(ctx), "received terminated")
+}
+
+func Test_withSignals_stop(t *testing.T) {
+	ctx, stop := withSignals(t.Context())
+
+	stop()
+	stop()
+
+	require.Error(t, ctx.Err())
+	assert.Equal(t, context.Canceled, context.Cause(ctx))
+}
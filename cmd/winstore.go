@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/certstore"
+	"github.com/digicert/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+const hookEnvCertThumbprint = "LEGO_CERT_THUMBPRINT"
+
+// importToWindowsStore imports the certificate into the Windows certificate store (LocalMachine),
+// and updates the IIS bindings, if requested.
+func importToWindowsStore(ctx *cli.Context, certRes *certificate.Resource, meta map[string]string) {
+	if !ctx.Bool(flgWinStore) {
+		return
+	}
+
+	if certRes.PrivateKey == nil {
+		log.Fatalf("Unable to import the certificate for domain %s into the Windows certificate store without private key. Are you using a CSR?", certRes.Domain)
+	}
+
+	friendlyName := ctx.String(flgWinStoreFriendlyName)
+	if friendlyName == "" {
+		friendlyName = fmt.Sprintf("%s %s", certRes.Domain, time.Now().UTC().Format(time.DateOnly))
+	}
+
+	opts := &certstore.Options{
+		FriendlyName: friendlyName,
+		StoreName:    ctx.String(flgWinStoreName),
+	}
+
+	thumbprint, err := certstore.Import(certRes.Certificate, certRes.PrivateKey, certRes.IssuerCertificate, opts)
+	if err != nil {
+		log.Fatalf("Unable to import the certificate for domain %s into the Windows certificate store\n\t%v", certRes.Domain, err)
+	}
+
+	log.Infof("[%s] The certificate has been imported into the Windows certificate store (thumbprint: %s)", certRes.Domain, thumbprint)
+
+	meta[hookEnvCertThumbprint] = thumbprint
+
+	for _, binding := range ctx.StringSlice(flgWinStoreIISBinding) {
+		err = certstore.UpdateIISBinding(ctx.Context, binding, thumbprint, opts.StoreName)
+		if err != nil {
+			log.Fatalf("Unable to update the IIS binding %s for domain %s\n\t%v", binding, certRes.Domain, err)
+		}
+
+		log.Infof("[%s] The IIS binding %s has been updated", certRes.Domain, binding)
+	}
+}
@@ -73,6 +73,7 @@ func allDNSCodes() string {
 		"edgedns",
 		"edgeone",
 		"efficientip",
+		"enom",
 		"epik",
 		"eurodns",
 		"excedo",
@@ -115,6 +116,7 @@ func allDNSCodes() string {
 		"jdcloud",
 		"joker",
 		"keyhelp",
+		"knot",
 		"leaseweb",
 		"liara",
 		"lightsail",
@@ -150,6 +152,7 @@ func allDNSCodes() string {
 		"ns1",
 		"octenium",
 		"onecloudru",
+		"openprovider",
 		"oraclecloud",
 		"otc",
 		"ovh",
@@ -159,6 +162,7 @@ func allDNSCodes() string {
 		"rackspace",
 		"rainyun",
 		"rcodezero",
+		"realtimeregister",
 		"regfish",
 		"regru",
 		"rfc2136",
@@ -176,6 +180,7 @@ func allDNSCodes() string {
 		"sonic",
 		"spaceship",
 		"stackpath",
+		"standalone",
 		"syse",
 		"technitium",
 		"tencentcloud",
@@ -199,6 +204,7 @@ func allDNSCodes() string {
 		"websupport",
 		"wedos",
 		"westcn",
+		"windowsdns",
 		"yandex",
 		"yandex360",
 		"yandexcloud",
@@ -737,7 +743,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "BUNNY_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
 		ew.writeln(`	- "BUNNY_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "BUNNY_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
-		ew.writeln(`	- "BUNNY_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 60)`)
+		ew.writeln(`	- "BUNNY_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 60, Min: 60)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/bunny`)
@@ -827,7 +833,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "CLOUDFLARE_HTTP_TIMEOUT":	API request timeout in seconds (Default: )`)
 		ew.writeln(`	- "CLOUDFLARE_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "CLOUDFLARE_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
-		ew.writeln(`	- "CLOUDFLARE_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "CLOUDFLARE_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120, Min: 120, Max: 86400)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/cloudflare`)
@@ -1159,7 +1165,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "DO_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
 		ew.writeln(`	- "DO_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 5)`)
 		ew.writeln(`	- "DO_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
-		ew.writeln(`	- "DO_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 30)`)
+		ew.writeln(`	- "DO_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 30, Min: 30)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/digitalocean`)
@@ -1493,6 +1499,9 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "AKAMAI_ACCOUNT_SWITCH_KEY":	Target account ID when the DNS zone and credentials belong to different accounts`)
 		ew.writeln(`	- "AKAMAI_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 15)`)
 		ew.writeln(`	- "AKAMAI_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 180)`)
+		ew.writeln(`	- "AKAMAI_RETRY_MAX":	Maximum number of retries of the read requests, 0 disables the retries (Default: 10)`)
+		ew.writeln(`	- "AKAMAI_RETRY_WAIT_MAX":	Maximum wait time between retries in seconds (Default: 30)`)
+		ew.writeln(`	- "AKAMAI_RETRY_WAIT_MIN":	Minimum wait time between retries in seconds (Default: 1)`)
 		ew.writeln(`	- "AKAMAI_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
 
 		ew.writeln()
@@ -1546,6 +1555,27 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/efficientip`)
 
+	case "enom":
+		// generated from: providers/dns/enom/enom.toml
+		ew.writeln(`Configuration for Enom.`)
+		ew.writeln(`Code:	'enom'`)
+		ew.writeln(`Since:	'v4.34.0'`)
+		ew.writeln()
+
+		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "ENOM_API_TOKEN":	API token`)
+		ew.writeln(`	- "ENOM_USERNAME":	Reseller account login ID`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "ENOM_HTTP_TIMEOUT":	API request timeout in seconds (Default: 60)`)
+		ew.writeln(`	- "ENOM_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 15)`)
+		ew.writeln(`	- "ENOM_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 600)`)
+		ew.writeln(`	- "ENOM_SANDBOX":	Use the sandbox API (Default: false)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/enom`)
+
 	case "epik":
 		// generated from: providers/dns/epik/epik.toml
 		ew.writeln(`Configuration for Epik.`)
@@ -1720,7 +1750,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "GANDIV5_HTTP_TIMEOUT":	API request timeout in seconds (Default: 10)`)
 		ew.writeln(`	- "GANDIV5_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 20)`)
 		ew.writeln(`	- "GANDIV5_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 1200)`)
-		ew.writeln(`	- "GANDIV5_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300)`)
+		ew.writeln(`	- "GANDIV5_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300, Min: 300, Max: 2592000)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/gandiv5`)
@@ -1827,9 +1857,9 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "GODADDY_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
-		ew.writeln(`	- "GODADDY_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
-		ew.writeln(`	- "GODADDY_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
-		ew.writeln(`	- "GODADDY_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 600)`)
+		ew.writeln(`	- "GODADDY_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
+		ew.writeln(`	- "GODADDY_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 600)`)
+		ew.writeln(`	- "GODADDY_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 600, Min: 600, Max: 604800)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/godaddy`)
@@ -1888,9 +1918,9 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "HETZNER_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
-		ew.writeln(`	- "HETZNER_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "HETZNER_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 5)`)
 		ew.writeln(`	- "HETZNER_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
-		ew.writeln(`	- "HETZNER_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "HETZNER_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120, Min: 60)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/hetzner`)
@@ -2007,7 +2037,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Credentials:`)
 		ew.writeln(`	- "HTTPREQ_ENDPOINT":	The URL of the server`)
-		ew.writeln(`	- "HTTPREQ_MODE":	'RAW', none`)
+		ew.writeln(`	- "HTTPREQ_MODE":	'RAW', 'RAW_V2', none`)
 		ew.writeln()
 
 		ew.writeln(`Additional Configuration:`)
@@ -2083,12 +2113,14 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 	case "ibmcloud":
 		// generated from: providers/dns/ibmcloud/ibmcloud.toml
-		ew.writeln(`Configuration for IBM Cloud (SoftLayer).`)
+		ew.writeln(`Configuration for IBM Cloud (SoftLayer, CIS).`)
 		ew.writeln(`Code:	'ibmcloud'`)
 		ew.writeln(`Since:	'v4.5.0'`)
 		ew.writeln()
 
 		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "IBMCLOUD_API_KEY":	IAM API key (only with CIS)`)
+		ew.writeln(`	- "IBMCLOUD_CIS_CRN":	CRN of the Cloud Internet Services instance (enables CIS)`)
 		ew.writeln(`	- "SOFTLAYER_API_KEY":	Classic Infrastructure API key`)
 		ew.writeln(`	- "SOFTLAYER_USERNAME":	Username (IBM Cloud is {accountID}_{emailAddress})`)
 		ew.writeln()
@@ -2226,7 +2258,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 
 		ew.writeln(`Additional Configuration:`)
-		ew.writeln(`	- "INWX_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "INWX_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
 		ew.writeln(`	- "INWX_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 360)`)
 		ew.writeln(`	- "INWX_SANDBOX":	Activate the sandbox (boolean)`)
 		ew.writeln(`	- "INWX_SHARED_SECRET":	shared secret related to 2FA`)
@@ -2248,7 +2280,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "IONOS_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
-		ew.writeln(`	- "IONOS_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "IONOS_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
 		ew.writeln(`	- "IONOS_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 900)`)
 		ew.writeln(`	- "IONOS_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300)`)
 
@@ -2390,7 +2422,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Credentials:`)
 		ew.writeln(`	- "JOKER_API_KEY":	API key (only with DMAPI mode)`)
-		ew.writeln(`	- "JOKER_API_MODE":	'DMAPI' or 'SVC'. DMAPI is for resellers accounts. (Default: DMAPI)`)
+		ew.writeln(`	- "JOKER_API_MODE":	'DMAPI', 'SVC' or 'AUTO'. DMAPI is for resellers accounts. (Default: DMAPI)`)
 		ew.writeln(`	- "JOKER_PASSWORD":	Joker.com password`)
 		ew.writeln(`	- "JOKER_USERNAME":	Joker.com username`)
 		ew.writeln()
@@ -2426,6 +2458,24 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/keyhelp`)
 
+	case "knot":
+		// generated from: providers/dns/knot/knot.toml
+		ew.writeln(`Configuration for Knot DNS.`)
+		ew.writeln(`Code:	'knot'`)
+		ew.writeln(`Since:	'v4.34.0'`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "KNOT_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "KNOT_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "KNOT_SOCKET":	Path of the control socket (Default: /run/knot/knot.sock)`)
+		ew.writeln(`	- "KNOT_SOCKET_TIMEOUT":	Timeout of the control socket operations in seconds (Default: 30)`)
+		ew.writeln(`	- "KNOT_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "KNOT_ZONE":	Zone name, when the zone detection must be skipped`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/knot`)
+
 	case "leaseweb":
 		// generated from: providers/dns/leaseweb/leaseweb.toml
 		ew.writeln(`Configuration for Leaseweb.`)
@@ -2462,7 +2512,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "LIARA_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "LIARA_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
 		ew.writeln(`	- "LIARA_TEAM_ID":	The team ID to access services in a team`)
-		ew.writeln(`	- "LIARA_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 3600)`)
+		ew.writeln(`	- "LIARA_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 3600, Min: 120, Max: 432000)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/liara`)
@@ -2524,7 +2574,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "LINODE_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
 		ew.writeln(`	- "LINODE_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 15)`)
 		ew.writeln(`	- "LINODE_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
-		ew.writeln(`	- "LINODE_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300)`)
+		ew.writeln(`	- "LINODE_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300, Min: 300, Max: 2419200)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/linode`)
@@ -2723,7 +2773,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "MITTWALD_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
 		ew.writeln(`	- "MITTWALD_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
 		ew.writeln(`	- "MITTWALD_SEQUENCE_INTERVAL":	Time between sequential requests in seconds (Default: 120)`)
-		ew.writeln(`	- "MITTWALD_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300)`)
+		ew.writeln(`	- "MITTWALD_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300, Min: 300)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/mittwald`)
@@ -2809,7 +2859,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "NAMECHEAP_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 15)`)
 		ew.writeln(`	- "NAMECHEAP_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 3600)`)
 		ew.writeln(`	- "NAMECHEAP_SANDBOX":	Activate the sandbox (boolean)`)
-		ew.writeln(`	- "NAMECHEAP_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "NAMECHEAP_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120, Min: 60, Max: 60000)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/namecheap`)
@@ -2934,8 +2984,9 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 
 		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "NETCUP_AUTHORITATIVE_ONLY":	Use a shorter polling interval, the propagation is checked on the authoritative name servers of the zone of the record (Default: false)`)
 		ew.writeln(`	- "NETCUP_HTTP_TIMEOUT":	API request timeout in seconds (Default: 10)`)
-		ew.writeln(`	- "NETCUP_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 30)`)
+		ew.writeln(`	- "NETCUP_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 30, 10 if NETCUP_AUTHORITATIVE_ONLY is enabled)`)
 		ew.writeln(`	- "NETCUP_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 900)`)
 
 		ew.writeln()
@@ -3001,7 +3052,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "NICMANAGER_HTTP_TIMEOUT":	API request timeout in seconds (Default: 10)`)
 		ew.writeln(`	- "NICMANAGER_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "NICMANAGER_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 300)`)
-		ew.writeln(`	- "NICMANAGER_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 900)`)
+		ew.writeln(`	- "NICMANAGER_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 900, Min: 900)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/nicmanager`)
@@ -3150,6 +3201,28 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/onecloudru`)
 
+	case "openprovider":
+		// generated from: providers/dns/openprovider/openprovider.toml
+		ew.writeln(`Configuration for Openprovider.`)
+		ew.writeln(`Code:	'openprovider'`)
+		ew.writeln(`Since:	'v4.34.0'`)
+		ew.writeln()
+
+		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "OPENPROVIDER_PASSWORD":	Password`)
+		ew.writeln(`	- "OPENPROVIDER_TOKEN":	Token (replaces the username and the password)`)
+		ew.writeln(`	- "OPENPROVIDER_USERNAME":	Username`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "OPENPROVIDER_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
+		ew.writeln(`	- "OPENPROVIDER_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
+		ew.writeln(`	- "OPENPROVIDER_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 300)`)
+		ew.writeln(`	- "OPENPROVIDER_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 900)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/openprovider`)
+
 	case "oraclecloud":
 		// generated from: providers/dns/oraclecloud/oraclecloud.toml
 		ew.writeln(`Configuration for Oracle Cloud.`)
@@ -3159,16 +3232,16 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Credentials:`)
 		ew.writeln(`	- "OCI_COMPARTMENT_OCID":	Compartment OCID`)
-		ew.writeln(`	- "OCI_FINGERPRINT":	Public key fingerprint (ignored if 'OCI_AUTH_TYPE=instance_principal')`)
-		ew.writeln(`	- "OCI_PRIVATE_KEY_PASSWORD":	Private key password (ignored if 'OCI_AUTH_TYPE=instance_principal')`)
-		ew.writeln(`	- "OCI_PRIVATE_KEY_PATH":	Private key file (ignored if 'OCI_AUTH_TYPE=instance_principal')`)
-		ew.writeln(`	- "OCI_REGION":	Region (it can be empty if 'OCI_AUTH_TYPE=instance_principal').`)
-		ew.writeln(`	- "OCI_TENANCY_OCID":	Tenancy OCID (ignored if 'OCI_AUTH_TYPE=instance_principal')`)
-		ew.writeln(`	- "OCI_USER_OCID":	User OCID (ignored if 'OCI_AUTH_TYPE=instance_principal')`)
+		ew.writeln(`	- "OCI_FINGERPRINT":	Public key fingerprint (ignored with instance or resource principal authentication)`)
+		ew.writeln(`	- "OCI_PRIVATE_KEY_PASSWORD":	Private key password (ignored with instance or resource principal authentication)`)
+		ew.writeln(`	- "OCI_PRIVATE_KEY_PATH":	Private key file (ignored with instance or resource principal authentication)`)
+		ew.writeln(`	- "OCI_REGION":	Region (it can be empty if 'OCI_AUTH_TYPE=instance_principal' or 'OCI_AUTH_TYPE=resource_principal').`)
+		ew.writeln(`	- "OCI_TENANCY_OCID":	Tenancy OCID (ignored with instance or resource principal authentication)`)
+		ew.writeln(`	- "OCI_USER_OCID":	User OCID (ignored with instance or resource principal authentication)`)
 		ew.writeln()
 
 		ew.writeln(`Additional Configuration:`)
-		ew.writeln(`	- "OCI_AUTH_TYPE":	Authorization type. Possible values: 'instance_principal', ''  (Default: '')`)
+		ew.writeln(`	- "OCI_AUTH_TYPE":	Authorization type. Possible values: 'instance_principal', 'resource_principal', ''  (Default: '')`)
 		ew.writeln(`	- "OCI_HTTP_TIMEOUT":	API request timeout in seconds (Default: 60)`)
 		ew.writeln(`	- "OCI_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "OCI_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
@@ -3227,8 +3300,8 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "OVH_HTTP_TIMEOUT":	API request timeout in seconds (Default: 180)`)
-		ew.writeln(`	- "OVH_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
-		ew.writeln(`	- "OVH_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "OVH_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 5)`)
+		ew.writeln(`	- "OVH_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 180)`)
 		ew.writeln(`	- "OVH_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
 
 		ew.writeln()
@@ -3295,7 +3368,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "PORKBUN_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
 		ew.writeln(`	- "PORKBUN_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
 		ew.writeln(`	- "PORKBUN_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 600)`)
-		ew.writeln(`	- "PORKBUN_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300)`)
+		ew.writeln(`	- "PORKBUN_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300, Min: 300)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/porkbun`)
@@ -3361,6 +3434,27 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/rcodezero`)
 
+	case "realtimeregister":
+		// generated from: providers/dns/realtimeregister/realtimeregister.toml
+		ew.writeln(`Configuration for Realtime Register.`)
+		ew.writeln(`Code:	'realtimeregister'`)
+		ew.writeln(`Since:	'v4.34.0'`)
+		ew.writeln()
+
+		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "REALTIMEREGISTER_API_KEY":	API key`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "REALTIMEREGISTER_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
+		ew.writeln(`	- "REALTIMEREGISTER_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
+		ew.writeln(`	- "REALTIMEREGISTER_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 300)`)
+		ew.writeln(`	- "REALTIMEREGISTER_SANDBOX":	Use the OT&E environment (Default: false)`)
+		ew.writeln(`	- "REALTIMEREGISTER_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 300)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/realtimeregister`)
+
 	case "regfish":
 		// generated from: providers/dns/regfish/regfish.toml
 		ew.writeln(`Configuration for Regfish.`)
@@ -3471,7 +3565,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "AWS_MAX_RETRIES":	The number of maximum returns the service will use to make an individual API request`)
 		ew.writeln(`	- "AWS_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 4)`)
-		ew.writeln(`	- "AWS_PRIVATE_ZONE":	Set to true to use private zones only (default: use public zones only)`)
+		ew.writeln(`	- "AWS_PRIVATE_ZONE":	Set to true to use private zones only (default: use public zones only). The propagation of the records is checked with the Route 53 API.`)
 		ew.writeln(`	- "AWS_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
 		ew.writeln(`	- "AWS_SHARED_CREDENTIALS_FILE":	Managed by the AWS client. Shared credentials file.`)
 		ew.writeln(`	- "AWS_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 10)`)
@@ -3537,7 +3631,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "SCW_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
 		ew.writeln(`	- "SCW_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
 		ew.writeln(`	- "SCW_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
-		ew.writeln(`	- "SCW_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 60)`)
+		ew.writeln(`	- "SCW_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 60, Min: 60)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/scaleway`)
@@ -3739,6 +3833,23 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/stackpath`)
 
+	case "standalone":
+		// generated from: providers/dns/standalone/standalone.toml
+		ew.writeln(`Configuration for Standalone (embedded DNS server).`)
+		ew.writeln(`Code:	'standalone'`)
+		ew.writeln(`Since:	'v4.34.0'`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "STANDALONE_ADDRESS":	The address (interface:port or :port) of the DNS server (Default: ':53')`)
+		ew.writeln(`	- "STANDALONE_NAMESERVER":	The name of the host running lego, used in the NS and SOA answers`)
+		ew.writeln(`	- "STANDALONE_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "STANDALONE_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "STANDALONE_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/standalone`)
+
 	case "syse":
 		// generated from: providers/dns/syse/syse.toml
 		ew.writeln(`Configuration for Syse.`)
@@ -3852,6 +3963,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 
 		ew.writeln(`Credentials:`)
 		ew.writeln(`	- "TRANSIP_ACCOUNT_NAME":	Account name`)
+		ew.writeln(`	- "TRANSIP_PRIVATE_KEY":	Private key content, can be used instead of TRANSIP_PRIVATE_KEY_PATH`)
 		ew.writeln(`	- "TRANSIP_PRIVATE_KEY_PATH":	Private key path`)
 		ew.writeln()
 
@@ -3859,6 +3971,8 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "TRANSIP_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
 		ew.writeln(`	- "TRANSIP_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 10)`)
 		ew.writeln(`	- "TRANSIP_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 600)`)
+		ew.writeln(`	- "TRANSIP_TOKEN_CACHE_PATH":	Path of the file used to cache the access tokens between runs`)
+		ew.writeln(`	- "TRANSIP_TOKEN_EXPIRATION":	Lifetime of the access tokens in seconds (Default: gotransip default)`)
 		ew.writeln(`	- "TRANSIP_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 10)`)
 
 		ew.writeln()
@@ -4227,6 +4341,31 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/westcn`)
 
+	case "windowsdns":
+		// generated from: providers/dns/windowsdns/windowsdns.toml
+		ew.writeln(`Configuration for Windows DNS Server.`)
+		ew.writeln(`Code:	'windowsdns'`)
+		ew.writeln(`Since:	'v4.34.0'`)
+		ew.writeln()
+
+		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "WINDOWSDNS_ENDPOINT":	WinRM endpoint (ex: https://dc01.example.com:5986/wsman)`)
+		ew.writeln(`	- "WINDOWSDNS_PASSWORD":	Password`)
+		ew.writeln(`	- "WINDOWSDNS_USERNAME":	Username`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "WINDOWSDNS_HTTP_TIMEOUT":	API request timeout in seconds (Default: 90)`)
+		ew.writeln(`	- "WINDOWSDNS_INSECURE_SKIP_VERIFY":	Whether or not to verify the certificate of the WinRM endpoint`)
+		ew.writeln(`	- "WINDOWSDNS_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "WINDOWSDNS_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "WINDOWSDNS_SERVER":	DNS server to manage, when different from the WinRM host`)
+		ew.writeln(`	- "WINDOWSDNS_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "WINDOWSDNS_ZONE":	Zone name, when the zone detection must be skipped`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/windowsdns`)
+
 	case "yandex":
 		// generated from: providers/dns/yandex/yandex.toml
 		ew.writeln(`Configuration for Yandex PDD.`)
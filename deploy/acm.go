@@ -0,0 +1,286 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ACM a Deployer importing the certificate into AWS Certificate Manager.
+//
+// The AWS credentials are read from the environment (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_PROFILE, ...).
+type ACM struct {
+	// CertificateARN the ARN of the certificate to re-import.
+	// If empty, the imported certificate with the same domain name is re-imported,
+	// or a new certificate is imported if there is none.
+	CertificateARN string
+
+	// Region if empty, the region of the ARN or the region of the AWS configuration is used.
+	Region string
+
+	endpoint   string
+	httpClient *http.Client
+}
+
+func openACM(value string) (*ACM, error) {
+	arn, rawQuery, _ := strings.Cut(value, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("deploy: acm: %w", err)
+	}
+
+	if arn != "" && !strings.HasPrefix(arn, "arn:") {
+		return nil, fmt.Errorf("deploy: acm: invalid certificate ARN: %q", arn)
+	}
+
+	return &ACM{CertificateARN: arn, Region: query.Get("region")}, nil
+}
+
+// Deploy imports (or re-imports) the certificate.
+func (a *ACM) Deploy(ctx context.Context, cert *Certificate) error {
+	if len(cert.PrivateKey) == 0 {
+		return errors.New("deploy: acm: the private key is required")
+	}
+
+	client, err := a.newClient(ctx)
+	if err != nil {
+		return fmt.Errorf("deploy: acm: %w", err)
+	}
+
+	arn := a.CertificateARN
+	if arn == "" {
+		arn, err = client.findImported(ctx, cert.Domain)
+		if err != nil {
+			return fmt.Errorf("deploy: acm: %w", err)
+		}
+	}
+
+	leaf, chain := splitChain(cert)
+
+	request := acmImportCertificateRequest{
+		CertificateArn:   arn,
+		Certificate:      leaf,
+		PrivateKey:       cert.PrivateKey,
+		CertificateChain: chain,
+	}
+
+	var response acmImportCertificateResponse
+
+	err = client.do(ctx, "ImportCertificate", request, &response)
+	if err != nil {
+		return fmt.Errorf("deploy: acm: import certificate: %w", err)
+	}
+
+	return nil
+}
+
+func (a *ACM) newClient(ctx context.Context) (*acmClient, error) {
+	region := a.Region
+	if region == "" {
+		// arn:aws:acm:<region>:<account>:certificate/<id>
+		if parts := strings.Split(a.CertificateARN, ":"); len(parts) > 3 {
+			region = parts[3]
+		}
+	}
+
+	var optFns []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		optFns = append(optFns, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create AWS config: %w", err)
+	}
+
+	if cfg.Region == "" {
+		return nil, errors.New("missing AWS region")
+	}
+
+	endpoint := a.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://acm.%s.amazonaws.com/", cfg.Region)
+	}
+
+	httpClient := a.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &acmClient{
+		endpoint:    endpoint,
+		region:      cfg.Region,
+		credentials: cfg.Credentials,
+		signer:      v4.NewSigner(),
+		httpClient:  httpClient,
+	}, nil
+}
+
+// acmClient a minimal client of the ACM API (JSON 1.1 protocol).
+type acmClient struct {
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// findImported returns the ARN of the imported certificate with the domain name, or an empty string.
+func (c *acmClient) findImported(ctx context.Context, domain string) (string, error) {
+	request := acmListCertificatesRequest{
+		Includes: &acmFilters{
+			KeyTypes: []string{"RSA_2048", "RSA_3072", "RSA_4096", "EC_prime256v1", "EC_secp384r1", "EC_secp521r1"},
+		},
+	}
+
+	for {
+		var response acmListCertificatesResponse
+
+		err := c.do(ctx, "ListCertificates", request, &response)
+		if err != nil {
+			return "", fmt.Errorf("list certificates: %w", err)
+		}
+
+		for _, summary := range response.CertificateSummaryList {
+			if summary.DomainName == domain && summary.Type == "IMPORTED" {
+				return summary.CertificateArn, nil
+			}
+		}
+
+		if response.NextToken == "" {
+			return "", nil
+		}
+
+		request.NextToken = response.NextToken
+	}
+}
+
+func (c *acmClient) do(ctx context.Context, action string, payload, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "CertificateManager."+action)
+
+	credentials, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(body)
+
+	err = c.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "acm", c.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &acmError{}
+
+		err = json.Unmarshal(raw, apiErr)
+		if err != nil || apiErr.Type == "" {
+			return fmt.Errorf("unexpected status code: [status code: %d] body: %s", resp.StatusCode, string(raw))
+		}
+
+		return apiErr
+	}
+
+	return json.Unmarshal(raw, result)
+}
+
+type acmError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func (e *acmError) Error() string {
+	// The type can be prefixed by a namespace: `com.amazonaws.acm#ResourceNotFoundException`.
+	_, name, found := strings.Cut(e.Type, "#")
+	if !found {
+		name = e.Type
+	}
+
+	return fmt.Sprintf("%s: %s", name, e.Message)
+}
+
+type acmImportCertificateRequest struct {
+	CertificateArn   string `json:"CertificateArn,omitempty"`
+	Certificate      []byte `json:"Certificate"`
+	PrivateKey       []byte `json:"PrivateKey"`
+	CertificateChain []byte `json:"CertificateChain,omitempty"`
+}
+
+type acmImportCertificateResponse struct {
+	CertificateArn string `json:"CertificateArn"`
+}
+
+type acmListCertificatesRequest struct {
+	Includes  *acmFilters `json:"Includes,omitempty"`
+	NextToken string      `json:"NextToken,omitempty"`
+}
+
+type acmFilters struct {
+	KeyTypes []string `json:"keyTypes,omitempty"`
+}
+
+type acmListCertificatesResponse struct {
+	CertificateSummaryList []acmCertificateSummary `json:"CertificateSummaryList"`
+	NextToken              string                  `json:"NextToken"`
+}
+
+type acmCertificateSummary struct {
+	CertificateArn string `json:"CertificateArn"`
+	DomainName     string `json:"DomainName"`
+	Type           string `json:"Type"`
+}
+
+// splitChain splits the certificate into the leaf certificate and the chain (the issuer certificates).
+func splitChain(cert *Certificate) (leaf, chain []byte) {
+	block, rest := pem.Decode(cert.Certificate)
+	if block == nil {
+		return cert.Certificate, cert.IssuerCertificate
+	}
+
+	leaf = pem.EncodeToMemory(block)
+
+	rest = bytes.TrimSpace(rest)
+	if len(rest) == 0 {
+		return leaf, cert.IssuerCertificate
+	}
+
+	return leaf, append(rest, '\n')
+}
@@ -0,0 +1,138 @@
+package deploy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockACM a fake ACM API, the responses are selected by action (X-Amz-Target).
+type mockACM struct {
+	responses map[string]any
+
+	mu       sync.Mutex
+	requests map[string]map[string]any
+}
+
+func (m *mockACM) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	action := strings.TrimPrefix(req.Header.Get("X-Amz-Target"), "CertificateManager.")
+
+	body := map[string]any{}
+
+	err := json.NewDecoder(req.Body).Decode(&body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	m.requests[action] = body
+	m.mu.Unlock()
+
+	response, ok := m.responses[action]
+	if !ok {
+		rw.WriteHeader(http.StatusBadRequest)
+		_, _ = rw.Write([]byte(`{"__type":"com.amazonaws.acm#InvalidParameterException","message":"unexpected action"}`))
+
+		return
+	}
+
+	_ = json.NewEncoder(rw).Encode(response)
+}
+
+func setupACM(t *testing.T, mock *mockACM) *ACM {
+	t.Helper()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	mock.requests = map[string]map[string]any{}
+
+	return servermock.NewBuilder[*ACM](
+		func(server *httptest.Server) (*ACM, error) {
+			return &ACM{endpoint: server.URL, httpClient: server.Client()}, nil
+		},
+		servermock.CheckHeader().
+			WithContentType("application/x-amz-json-1.1").
+			WithRegexp("Authorization", `AWS4-HMAC-SHA256 Credential=key/\d+/us-east-1/acm/aws4_request, .+`),
+	).
+		Route("POST /", mock).
+		Build(t)
+}
+
+func TestACM_Deploy(t *testing.T) {
+	mock := &mockACM{responses: map[string]any{
+		"ListCertificates": map[string]any{
+			"CertificateSummaryList": []map[string]any{
+				{"CertificateArn": "arn:aws:acm:us-east-1:123:certificate/managed", "DomainName": "example.com", "Type": "AMAZON_ISSUED"},
+				{"CertificateArn": "arn:aws:acm:us-east-1:123:certificate/imported", "DomainName": "example.com", "Type": "IMPORTED"},
+			},
+		},
+		"ImportCertificate": map[string]any{"CertificateArn": "arn:aws:acm:us-east-1:123:certificate/imported"},
+	}}
+
+	deployer := setupACM(t, mock)
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("-----BEGIN CERTIFICATE-----\nbGVhZg==\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\nY2hhaW4=\n-----END CERTIFICATE-----\n"),
+		PrivateKey:  []byte("key"),
+	})
+	require.NoError(t, err)
+
+	expected := map[string]any{
+		"CertificateArn":   "arn:aws:acm:us-east-1:123:certificate/imported",
+		"Certificate":      "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCmJHVmhaZz09Ci0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K",
+		"CertificateChain": "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tClkyaGhhVzQ9Ci0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K",
+		"PrivateKey":       "a2V5",
+	}
+
+	assert.Equal(t, expected, mock.requests["ImportCertificate"])
+}
+
+func TestACM_Deploy_arn(t *testing.T) {
+	mock := &mockACM{responses: map[string]any{
+		"ImportCertificate": map[string]any{"CertificateArn": "arn:aws:acm:us-east-1:123:certificate/abc"},
+	}}
+
+	deployer := setupACM(t, mock)
+	deployer.CertificateARN = "arn:aws:acm:us-east-1:123:certificate/abc"
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, mock.requests, "ListCertificates")
+	assert.Equal(t, "arn:aws:acm:us-east-1:123:certificate/abc", mock.requests["ImportCertificate"]["CertificateArn"])
+}
+
+func TestACM_Deploy_error(t *testing.T) {
+	mock := &mockACM{responses: map[string]any{
+		"ListCertificates": map[string]any{},
+	}}
+
+	deployer := setupACM(t, mock)
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.EqualError(t, err, "deploy: acm: import certificate: InvalidParameterException: unexpected action")
+
+	assert.NotContains(t, mock.requests["ImportCertificate"], "CertificateArn")
+}
@@ -0,0 +1,171 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/digicert/lego/v4/certcrypto"
+)
+
+const azureKeyVaultAPIVersion = "7.4"
+
+// AzureKeyVault a Deployer importing the certificate into Azure Key Vault (a new version of the certificate is created).
+//
+// The credentials are the default Azure credentials (environment, workload identity, managed identity, Azure CLI, ...).
+type AzureKeyVault struct {
+	// Vault the name of the vault, or its host (ex: `myvault.vault.azure.net`).
+	Vault string
+
+	// CertificateName the name of the certificate in the vault,
+	// if empty the name is derived from the domain (ex: `*.example.com` -> `wildcard-example-com`).
+	CertificateName string
+
+	credential azcore.TokenCredential
+	baseURL    string
+	httpClient *http.Client
+}
+
+func openAzureKeyVault(value string) (*AzureKeyVault, error) {
+	vault, name, _ := strings.Cut(value, "/")
+	if vault == "" {
+		return nil, errors.New("deploy: azure-keyvault: missing vault name")
+	}
+
+	return &AzureKeyVault{Vault: vault, CertificateName: name}, nil
+}
+
+// Deploy imports the certificate.
+func (a *AzureKeyVault) Deploy(ctx context.Context, cert *Certificate) error {
+	if len(cert.PrivateKey) == 0 {
+		return errors.New("deploy: azure-keyvault: the private key is required")
+	}
+
+	credential := a.credential
+	if credential == nil {
+		var err error
+
+		credential, err = azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return fmt.Errorf("deploy: azure-keyvault: %w", err)
+		}
+	}
+
+	// Key Vault only supports the PKCS#8 private keys.
+	privateKey, err := certcrypto.ParsePEMPrivateKey(cert.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("deploy: azure-keyvault: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("deploy: azure-keyvault: %w", err)
+	}
+
+	value := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	value = append(value, cert.Certificate...)
+
+	name := a.CertificateName
+	if name == "" {
+		name = azureCertificateName(cert.Domain)
+	}
+
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}})
+	if err != nil {
+		return fmt.Errorf("deploy: azure-keyvault: get token: %w", err)
+	}
+
+	err = a.importCertificate(ctx, token.Token, name, string(value))
+	if err != nil {
+		return fmt.Errorf("deploy: azure-keyvault: import certificate %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (a *AzureKeyVault) importCertificate(ctx context.Context, token, name, value string) error {
+	baseURL := a.baseURL
+	if baseURL == "" {
+		host := a.Vault
+		if !strings.Contains(host, ".") {
+			host += ".vault.azure.net"
+		}
+
+		baseURL = "https://" + host
+	}
+
+	endpoint, err := url.JoinPath(baseURL, "certificates", name, "import")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"value": value,
+		"policy": map[string]any{
+			"secret_props": map[string]string{"contentType": "application/x-pem-file"},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?api-version="+azureKeyVaultAPIVersion, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	httpClient := a.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	raw, _ := io.ReadAll(resp.Body)
+
+	var apiErr struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	err = json.Unmarshal(raw, &apiErr)
+	if err != nil || apiErr.Error.Code == "" {
+		return fmt.Errorf("unexpected status code: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	return fmt.Errorf("%s: %s", apiErr.Error.Code, apiErr.Error.Message)
+}
+
+// azureCertificateName returns a certificate name from a domain, the names only support alphanumeric characters and dashes.
+func azureCertificateName(domain string) string {
+	return strings.NewReplacer("*", "wildcard", ".", "-").Replace(domain)
+}
@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "secret", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func mockAzureKeyVault(name string) *servermock.Builder[*AzureKeyVault] {
+	return servermock.NewBuilder[*AzureKeyVault](
+		func(server *httptest.Server) (*AzureKeyVault, error) {
+			return &AzureKeyVault{
+				Vault:           "lego",
+				CertificateName: name,
+				credential:      fakeTokenCredential{},
+				baseURL:         server.URL,
+				httpClient:      server.Client(),
+			}, nil
+		},
+		servermock.CheckHeader().
+			WithJSONHeaders().
+			WithAuthorization("Bearer secret"),
+	)
+}
+
+func TestAzureKeyVault_Deploy(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var body map[string]any
+
+	deployer := mockAzureKeyVault("").
+		Route("POST /certificates/wildcard-example-com/import",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				errD := json.NewDecoder(req.Body).Decode(&body)
+				if errD != nil {
+					http.Error(rw, errD.Error(), http.StatusBadRequest)
+					return
+				}
+
+				_, _ = rw.Write([]byte(`{"id":"https://lego.vault.azure.net/certificates/wildcard-example-com/1"}`))
+			}),
+			servermock.CheckQueryParameter().Strict().With("api-version", "7.4")).
+		Build(t)
+
+	err = deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "*.example.com",
+		Certificate: []byte("cert\n"),
+		PrivateKey:  certcrypto.PEMEncode(privateKey),
+	})
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	expected := map[string]any{
+		"value": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})) + "cert\n",
+		"policy": map[string]any{
+			"secret_props": map[string]any{"contentType": "application/x-pem-file"},
+		},
+	}
+
+	assert.Equal(t, expected, body)
+}
+
+func TestAzureKeyVault_Deploy_error(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	deployer := mockAzureKeyVault("my-cert").
+		Route("POST /certificates/my-cert/import",
+			servermock.RawStringResponse(`{"error":{"code":"Forbidden","message":"The user does not have the certificates import permission."}}`).
+				WithStatusCode(http.StatusForbidden)).
+		Build(t)
+
+	err = deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert\n"),
+		PrivateKey:  certcrypto.PEMEncode(privateKey),
+	})
+	require.EqualError(t, err, "deploy: azure-keyvault: import certificate my-cert: Forbidden: The user does not have the certificates import permission.")
+}
@@ -0,0 +1,29 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// Command a Deployer running a command (ex: reload a web server).
+type Command struct {
+	name string
+	args []string
+}
+
+// NewCommand creates a new Command.
+func NewCommand(name string, args ...string) *Command {
+	return &Command{name: name, args: args}
+}
+
+// Deploy runs the command.
+func (c *Command) Deploy(ctx context.Context, _ *Certificate) error {
+	output, err := exec.CommandContext(ctx, c.name, c.args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("deploy: %s: %w: %s", c.name, err, bytes.TrimSpace(output))
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config the deployment targets of the certificates, by main domain:
+//
+//	[certificates."example.com"]
+//	deploy = ["copy:/etc/nginx/certs?mode=0640&group=www-data", "nginx"]
+//
+//	[certificates."*.example.org"]
+//	deploy = ["haproxy:/run/haproxy/admin.sock"]
+type Config struct {
+	Certificates map[string]CertificateConfig `toml:"certificates"`
+}
+
+// CertificateConfig the deployment targets of a certificate.
+type CertificateConfig struct {
+	Deploy []string `toml:"deploy"`
+}
+
+// LoadConfig loads a configuration file (TOML).
+func LoadConfig(filename string) (*Config, error) {
+	config := &Config{}
+
+	_, err := toml.DecodeFile(filename, config)
+	if err != nil {
+		return nil, fmt.Errorf("deploy: load configuration: %w", err)
+	}
+
+	for domain, cert := range config.Certificates {
+		for _, target := range cert.Deploy {
+			_, err = Open(target)
+			if err != nil {
+				return nil, fmt.Errorf("%w (certificate %s)", err, domain)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// Targets returns the deployment targets of a certificate.
+func (c *Config) Targets(domain string) []string {
+	if c == nil {
+		return nil
+	}
+
+	return c.Certificates[domain].Deploy
+}
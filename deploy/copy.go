@@ -0,0 +1,169 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Copy a Deployer writing the certificate files into a directory.
+//
+// The files are:
+//   - `<domain>.crt`: the certificate.
+//   - `<domain>.key`: the private key.
+//   - `<domain>.issuer.crt`: the issuer certificate (if any).
+//   - `<domain>.pem`: the certificate followed by the private key (ex: for HAProxy).
+type Copy struct {
+	Dir string
+
+	// Mode the permissions of the files (default: 0600).
+	Mode os.FileMode
+
+	// Owner and Group of the files (name or ID), unchanged if empty.
+	Owner string
+	Group string
+}
+
+func openCopy(value string) (*Copy, error) {
+	dir, rawQuery, _ := strings.Cut(value, "?")
+	if dir == "" {
+		return nil, errors.New("deploy: copy: missing directory")
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("deploy: copy: %w", err)
+	}
+
+	c := &Copy{
+		Dir:   dir,
+		Owner: query.Get("owner"),
+		Group: query.Get("group"),
+	}
+
+	if mode := query.Get("mode"); mode != "" {
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("deploy: copy: invalid mode %q: %w", mode, err)
+		}
+
+		c.Mode = os.FileMode(m)
+	}
+
+	return c, nil
+}
+
+// Deploy writes the certificate files.
+func (c *Copy) Deploy(_ context.Context, cert *Certificate) error {
+	uid, gid, err := c.ids()
+	if err != nil {
+		return fmt.Errorf("deploy: copy: %w", err)
+	}
+
+	err = os.MkdirAll(c.Dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("deploy: copy: %w", err)
+	}
+
+	base := filepath.Join(c.Dir, fileName(cert.Domain))
+
+	files := map[string][]byte{
+		base + ".crt": cert.Certificate,
+		base + ".key": cert.PrivateKey,
+		base + ".pem": slices.Concat(cert.Certificate, cert.PrivateKey),
+	}
+
+	if len(cert.IssuerCertificate) > 0 {
+		files[base+".issuer.crt"] = cert.IssuerCertificate
+	}
+
+	if len(cert.PrivateKey) == 0 {
+		delete(files, base+".key")
+		delete(files, base+".pem")
+	}
+
+	for name, data := range files {
+		err = c.writeFile(name, data, uid, gid)
+		if err != nil {
+			return fmt.Errorf("deploy: copy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeFile writes atomically a file (temporary file, then rename), with the permissions and the ownership.
+func (c *Copy) writeFile(name string, data []byte, uid, gid int) error {
+	mode := c.Mode
+	if mode == 0 {
+		mode = 0o600
+	}
+
+	tmp := name + ".tmp"
+
+	err := os.WriteFile(tmp, data, mode)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp) }()
+
+	// The permissions of WriteFile are modified by the umask.
+	err = os.Chmod(tmp, mode)
+	if err != nil {
+		return err
+	}
+
+	if uid != -1 || gid != -1 {
+		err = os.Chown(tmp, uid, gid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tmp, name)
+}
+
+// ids returns the IDs of the owner and the group, -1 if not defined.
+func (c *Copy) ids() (uid, gid int, err error) {
+	uid, gid = -1, -1
+
+	if c.Owner != "" {
+		uid, err = strconv.Atoi(c.Owner)
+		if err != nil {
+			u, errL := user.Lookup(c.Owner)
+			if errL != nil {
+				return 0, 0, errL
+			}
+
+			uid, err = strconv.Atoi(u.Uid)
+			if err != nil {
+				return 0, 0, fmt.Errorf("unsupported user ID %q: %w", u.Uid, err)
+			}
+		}
+	}
+
+	if c.Group != "" {
+		gid, err = strconv.Atoi(c.Group)
+		if err != nil {
+			g, errL := user.LookupGroup(c.Group)
+			if errL != nil {
+				return 0, 0, errL
+			}
+
+			gid, err = strconv.Atoi(g.Gid)
+			if err != nil {
+				return 0, 0, fmt.Errorf("unsupported group ID %q: %w", g.Gid, err)
+			}
+		}
+	}
+
+	return uid, gid, nil
+}
@@ -0,0 +1,116 @@
+// Package deploy deploys the certificates after their issuance (files, web servers, load balancers, services).
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Certificate the certificate to deploy.
+// The fields are PEM encoded.
+type Certificate struct {
+	// Domain the main domain of the certificate, also used to name the files.
+	Domain string
+
+	Certificate       []byte
+	PrivateKey        []byte
+	IssuerCertificate []byte
+}
+
+// Deployer deploys a certificate.
+type Deployer interface {
+	Deploy(ctx context.Context, cert *Certificate) error
+}
+
+// Deployers deploys a certificate with several deployers.
+type Deployers []Deployer
+
+// Deploy calls the deployers in order, and stops at the first error:
+// the deployers usually depend on each other (ex: copy the files, then reload the web server).
+func (d Deployers) Deploy(ctx context.Context, cert *Certificate) error {
+	for _, deployer := range d {
+		err := deployer.Deploy(ctx, cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Open creates a Deployer from a target:
+//   - "copy:/path/to/dir?mode=0640&owner=user&group=group": copies the certificate files (`.crt`, `.key`, `.issuer.crt`, `.pem`) into a directory.
+//   - "nginx": reloads nginx (`nginx -s reload`).
+//   - "apache": reloads Apache gracefully (`apachectl graceful`).
+//   - "haproxy:/path/to/admin.sock?crt=/etc/haproxy/certs/example.com.pem": updates a certificate through the HAProxy runtime API.
+//   - "systemd:unit": restarts a systemd unit (`systemctl restart unit`).
+//   - "kubernetes:namespace/name?kubeconfig=/path/to/kubeconfig&context=name": creates or updates a Kubernetes TLS Secret.
+//   - "acm:arn:aws:acm:region:account:certificate/id?region=us-east-1": imports the certificate into AWS Certificate Manager (the ARN is optional).
+//   - "gcp-certificate-manager:projects/p/locations/l/certificates/name": creates or updates a certificate of Google Cloud Certificate Manager.
+//   - "azure-keyvault:vault/name": imports the certificate into Azure Key Vault (the name is optional).
+//   - "iis:Default Web Site,0.0.0.0:8443?store=My&friendly-name=name": imports the certificate into the Windows certificate store,
+//     and binds it to IIS sites (names) and HTTP.sys bindings (`<ip>:<port>` or `<hostname>:<port>`).
+func Open(target string) (Deployer, error) {
+	kind, value, _ := strings.Cut(target, ":")
+
+	switch kind {
+	case "copy":
+		return openCopy(value)
+
+	case "nginx":
+		return NewCommand("nginx", "-s", "reload"), nil
+
+	case "apache":
+		return NewCommand("apachectl", "graceful"), nil
+
+	case "haproxy":
+		return openHAProxy(value)
+
+	case "systemd":
+		if value == "" {
+			return nil, fmt.Errorf("deploy: missing systemd unit: %q", target)
+		}
+
+		return NewCommand("systemctl", "restart", value), nil
+
+	case "kubernetes":
+		return openKubernetes(value)
+
+	case "acm":
+		return openACM(value)
+
+	case "gcp-certificate-manager":
+		return openGCPCertificateManager(value)
+
+	case "azure-keyvault":
+		return openAzureKeyVault(value)
+
+	case "iis":
+		return openIIS(value)
+
+	default:
+		return nil, fmt.Errorf("deploy: unsupported target: %q", target)
+	}
+}
+
+// OpenAll creates the deployers from targets.
+func OpenAll(targets []string) (Deployers, error) {
+	var deployers Deployers
+
+	for _, target := range targets {
+		deployer, err := Open(target)
+		if err != nil {
+			return nil, err
+		}
+
+		deployers = append(deployers, deployer)
+	}
+
+	return deployers, nil
+}
+
+// fileName returns the base name of the files of a certificate.
+func fileName(domain string) string {
+	return strings.ReplaceAll(domain, "*", "_")
+}
@@ -0,0 +1,223 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		target   string
+		expected Deployer
+	}{
+		{
+			desc:     "copy",
+			target:   "copy:/etc/nginx/certs?mode=0640&owner=root&group=www-data",
+			expected: &Copy{Dir: "/etc/nginx/certs", Mode: 0o640, Owner: "root", Group: "www-data"},
+		},
+		{
+			desc:     "nginx",
+			target:   "nginx",
+			expected: NewCommand("nginx", "-s", "reload"),
+		},
+		{
+			desc:     "apache",
+			target:   "apache",
+			expected: NewCommand("apachectl", "graceful"),
+		},
+		{
+			desc:     "haproxy",
+			target:   "haproxy:/run/haproxy/admin.sock?crt=/etc/haproxy/site.pem",
+			expected: &HAProxy{Addr: "/run/haproxy/admin.sock", CertPath: "/etc/haproxy/site.pem"},
+		},
+		{
+			desc:     "kubernetes",
+			target:   "kubernetes:ingress/example-tls?kubeconfig=/etc/lego/kubeconfig&context=prod",
+			expected: &Kubernetes{Namespace: "ingress", Name: "example-tls", Kubeconfig: "/etc/lego/kubeconfig", Context: "prod"},
+		},
+		{
+			desc:     "kubernetes without namespace",
+			target:   "kubernetes:example-tls",
+			expected: &Kubernetes{Name: "example-tls"},
+		},
+		{
+			desc:     "acm",
+			target:   "acm:arn:aws:acm:eu-west-1:123456789012:certificate/abc?region=eu-west-1",
+			expected: &ACM{CertificateARN: "arn:aws:acm:eu-west-1:123456789012:certificate/abc", Region: "eu-west-1"},
+		},
+		{
+			desc:     "acm without ARN",
+			target:   "acm:",
+			expected: &ACM{},
+		},
+		{
+			desc:     "gcp-certificate-manager",
+			target:   "gcp-certificate-manager:projects/lego/locations/global/certificates/example-com",
+			expected: &GCPCertificateManager{Name: "projects/lego/locations/global/certificates/example-com"},
+		},
+		{
+			desc:     "azure-keyvault",
+			target:   "azure-keyvault:myvault/example-com",
+			expected: &AzureKeyVault{Vault: "myvault", CertificateName: "example-com"},
+		},
+		{
+			desc:     "iis",
+			target:   "iis:Default Web Site, 0.0.0.0:443,example.com:443?store=WebHosting&friendly-name=lego",
+			expected: &IIS{Sites: []string{"Default Web Site"}, Bindings: []string{"0.0.0.0:443", "example.com:443"}, StoreName: "WebHosting", FriendlyName: "lego"},
+		},
+		{
+			desc:     "systemd",
+			target:   "systemd:caddy.service",
+			expected: NewCommand("systemctl", "restart", "caddy.service"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			deployer, err := Open(test.target)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, deployer)
+		})
+	}
+}
+
+func TestOpen_error(t *testing.T) {
+	testCases := []struct {
+		target   string
+		expected string
+	}{
+		{target: "ftp:/foo", expected: `deploy: unsupported target: "ftp:/foo"`},
+		{target: "systemd:", expected: `deploy: missing systemd unit: "systemd:"`},
+		{target: "copy:", expected: "deploy: copy: missing directory"},
+		{target: "copy:/tmp?mode=abc", expected: `deploy: copy: invalid mode "abc": strconv.ParseUint: parsing "abc": invalid syntax`},
+		{target: "haproxy:", expected: "deploy: haproxy: missing runtime API address"},
+		{target: "kubernetes:ingress/", expected: "deploy: kubernetes: missing secret name"},
+		{target: "acm:foo", expected: `deploy: acm: invalid certificate ARN: "foo"`},
+		{target: "gcp-certificate-manager:example-com", expected: `deploy: gcp-certificate-manager: invalid certificate name "example-com" (projects/<project>/locations/<location>/certificates/<name>)`},
+		{target: "azure-keyvault:", expected: "deploy: azure-keyvault: missing vault name"},
+		{target: "iis:?store=My", expected: "deploy: iis: missing sites or bindings"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.target, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Open(test.target)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDeployers_Deploy(t *testing.T) {
+	var calls []string
+
+	deployers := Deployers{
+		deployerFunc(func(context.Context, *Certificate) error {
+			calls = append(calls, "a")
+			return nil
+		}),
+		deployerFunc(func(context.Context, *Certificate) error {
+			calls = append(calls, "b")
+			return errors.New("oops")
+		}),
+		deployerFunc(func(context.Context, *Certificate) error {
+			calls = append(calls, "c")
+			return nil
+		}),
+	}
+
+	err := deployers.Deploy(t.Context(), &Certificate{Domain: "example.com"})
+	require.EqualError(t, err, "oops")
+
+	assert.Equal(t, []string{"a", "b"}, calls)
+}
+
+func TestCopy_Deploy(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "certs")
+
+	deployer := &Copy{Dir: dir, Mode: 0o640}
+
+	cert := &Certificate{
+		Domain:            "*.example.com",
+		Certificate:       []byte("cert\n"),
+		PrivateKey:        []byte("key\n"),
+		IssuerCertificate: []byte("issuer\n"),
+	}
+
+	err := deployer.Deploy(t.Context(), cert)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"_.example.com.crt":        "cert\n",
+		"_.example.com.key":        "key\n",
+		"_.example.com.issuer.crt": "issuer\n",
+		"_.example.com.pem":        "cert\nkey\n",
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, len(expected))
+
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+
+		assert.Equal(t, content, string(data))
+
+		if os.PathSeparator == '/' {
+			info, err := os.Stat(filepath.Join(dir, name))
+			require.NoError(t, err)
+
+			assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "deploy.toml")
+
+	err := os.WriteFile(filename, []byte(`
+[certificates."example.com"]
+deploy = ["copy:/etc/nginx/certs", "nginx"]
+
+[certificates."*.example.org"]
+deploy = ["systemd:haproxy.service"]
+`), 0o600)
+	require.NoError(t, err)
+
+	config, err := LoadConfig(filename)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"copy:/etc/nginx/certs", "nginx"}, config.Targets("example.com"))
+	assert.Equal(t, []string{"systemd:haproxy.service"}, config.Targets("*.example.org"))
+	assert.Empty(t, config.Targets("example.net"))
+}
+
+func TestConfig_invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "deploy.toml")
+
+	err := os.WriteFile(filename, []byte(`
+[certificates."example.com"]
+deploy = ["foo"]
+`), 0o600)
+	require.NoError(t, err)
+
+	_, err = LoadConfig(filename)
+	require.EqualError(t, err, `deploy: unsupported target: "foo" (certificate example.com)`)
+}
+
+type deployerFunc func(ctx context.Context, cert *Certificate) error
+
+func (f deployerFunc) Deploy(ctx context.Context, cert *Certificate) error {
+	return f(ctx, cert)
+}
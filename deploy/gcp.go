@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/digicert/lego/v4/platform/wait"
+	"google.golang.org/api/certificatemanager/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// GCPCertificateManager a Deployer creating or updating a self-managed certificate of Google Cloud Certificate Manager.
+//
+// The credentials are the Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS, metadata server, ...).
+type GCPCertificateManager struct {
+	// Name the resource name of the certificate: `projects/<project>/locations/<location>/certificates/<name>`.
+	Name string
+
+	options []option.ClientOption
+}
+
+func openGCPCertificateManager(value string) (*GCPCertificateManager, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 6 || parts[0] != "projects" || parts[2] != "locations" || parts[4] != "certificates" {
+		return nil, fmt.Errorf("deploy: gcp-certificate-manager: invalid certificate name %q (projects/<project>/locations/<location>/certificates/<name>)", value)
+	}
+
+	return &GCPCertificateManager{Name: value}, nil
+}
+
+// Deploy updates the certificate, or creates it if it doesn't exist.
+func (g *GCPCertificateManager) Deploy(ctx context.Context, cert *Certificate) error {
+	if len(cert.PrivateKey) == 0 {
+		return errors.New("deploy: gcp-certificate-manager: the private key is required")
+	}
+
+	service, err := certificatemanager.NewService(ctx, g.options...)
+	if err != nil {
+		return fmt.Errorf("deploy: gcp-certificate-manager: %w", err)
+	}
+
+	certificates := service.Projects.Locations.Certificates
+
+	certificate := &certificatemanager.Certificate{
+		SelfManaged: &certificatemanager.SelfManagedCertificate{
+			PemCertificate: string(cert.Certificate),
+			PemPrivateKey:  string(cert.PrivateKey),
+		},
+	}
+
+	operation, err := certificates.Patch(g.Name, certificate).UpdateMask("selfManaged").Context(ctx).Do()
+	if isNotFound(err) {
+		parent, id, _ := strings.Cut(g.Name, "/certificates/")
+
+		operation, err = certificates.Create(parent, certificate).CertificateId(id).Context(ctx).Do()
+	}
+
+	if err != nil {
+		return fmt.Errorf("deploy: gcp-certificate-manager: %s: %w", g.Name, err)
+	}
+
+	err = waitOperation(ctx, service, operation)
+	if err != nil {
+		return fmt.Errorf("deploy: gcp-certificate-manager: %s: %w", g.Name, err)
+	}
+
+	return nil
+}
+
+func waitOperation(ctx context.Context, service *certificatemanager.Service, operation *certificatemanager.Operation) error {
+	return wait.ForWithContext(ctx, "operation "+operation.Name, 2*time.Minute, backoff.NewConstantBackOff(2*time.Second), func() (bool, error) {
+		if !operation.Done {
+			var err error
+
+			operation, err = service.Projects.Locations.Operations.Get(operation.Name).Context(ctx).Do()
+			if err != nil {
+				return false, err
+			}
+
+			if !operation.Done {
+				return false, nil
+			}
+		}
+
+		if operation.Error != nil {
+			return true, fmt.Errorf("operation %s: %s", operation.Name, operation.Error.Message)
+		}
+
+		return true, nil
+	})
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,76 @@
+package deploy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+)
+
+func mockGCPCertificateManager() *servermock.Builder[*GCPCertificateManager] {
+	return servermock.NewBuilder[*GCPCertificateManager](
+		func(server *httptest.Server) (*GCPCertificateManager, error) {
+			return &GCPCertificateManager{
+				Name: "projects/lego/locations/global/certificates/example-com",
+				options: []option.ClientOption{
+					option.WithEndpoint(server.URL + "/"),
+					option.WithHTTPClient(server.Client()),
+				},
+			}, nil
+		},
+	)
+}
+
+func TestGCPCertificateManager_Deploy_update(t *testing.T) {
+	deployer := mockGCPCertificateManager().
+		Route("PATCH /v1/projects/lego/locations/global/certificates/example-com",
+			servermock.RawStringResponse(`{"name":"operations/1","done":false}`),
+			servermock.CheckQueryParameter().With("updateMask", "selfManaged"),
+			servermock.CheckRequestJSONBody(`{"selfManaged":{"pemCertificate":"cert","pemPrivateKey":"key"}}`)).
+		Route("GET /v1/operations/1",
+			servermock.RawStringResponse(`{"name":"operations/1","done":true}`)).
+		Build(t)
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.NoError(t, err)
+}
+
+func TestGCPCertificateManager_Deploy_create(t *testing.T) {
+	deployer := mockGCPCertificateManager().
+		Route("PATCH /v1/projects/lego/locations/global/certificates/example-com",
+			servermock.RawStringResponse(`{"error":{"code":404,"message":"not found"}}`).
+				WithStatusCode(http.StatusNotFound)).
+		Route("POST /v1/projects/lego/locations/global/certificates",
+			servermock.RawStringResponse(`{"name":"operations/1","done":true}`),
+			servermock.CheckQueryParameter().With("certificateId", "example-com"),
+			servermock.CheckRequestJSONBody(`{"selfManaged":{"pemCertificate":"cert","pemPrivateKey":"key"}}`)).
+		Build(t)
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.NoError(t, err)
+}
+
+func TestGCPCertificateManager_Deploy_operationError(t *testing.T) {
+	deployer := mockGCPCertificateManager().
+		Route("PATCH /v1/projects/lego/locations/global/certificates/example-com",
+			servermock.RawStringResponse(`{"name":"operations/1","done":true,"error":{"code":3,"message":"invalid certificate"}}`)).
+		Build(t)
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.EqualError(t, err, "deploy: gcp-certificate-manager: projects/lego/locations/global/certificates/example-com: operation operations/1: invalid certificate")
+}
@@ -0,0 +1,111 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HAProxy a Deployer updating a certificate through the HAProxy runtime API (`set ssl cert`, then `commit ssl cert`).
+// The certificate must already be loaded by HAProxy (ex: with a `crt` directive).
+//
+// https://docs.haproxy.org/dev/management.html#9.3-set%20ssl%20cert
+type HAProxy struct {
+	// Addr the address of the runtime API: a path of a Unix socket, or `host:port`.
+	Addr string
+
+	// CertPath the path of the certificate known by HAProxy (default: `/etc/haproxy/certs/<domain>.pem`).
+	CertPath string
+
+	dialer net.Dialer
+}
+
+func openHAProxy(value string) (*HAProxy, error) {
+	addr, rawQuery, _ := strings.Cut(value, "?")
+	if addr == "" {
+		return nil, errors.New("deploy: haproxy: missing runtime API address")
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("deploy: haproxy: %w", err)
+	}
+
+	return &HAProxy{Addr: addr, CertPath: query.Get("crt")}, nil
+}
+
+// Deploy updates the certificate.
+func (h *HAProxy) Deploy(ctx context.Context, cert *Certificate) error {
+	if len(cert.PrivateKey) == 0 {
+		return errors.New("deploy: haproxy: the private key is required")
+	}
+
+	certPath := h.CertPath
+	if certPath == "" {
+		certPath = "/etc/haproxy/certs/" + fileName(cert.Domain) + ".pem"
+	}
+
+	// An empty line ends the payload.
+	payload := slices.Concat(bytes.TrimSpace(cert.Certificate), []byte("\n"), bytes.TrimSpace(cert.PrivateKey))
+
+	response, err := h.send(ctx, fmt.Sprintf("set ssl cert %s <<\n%s\n\n", certPath, payload))
+	if err != nil {
+		return fmt.Errorf("deploy: haproxy: set ssl cert: %w", err)
+	}
+
+	if !strings.Contains(response, "Transaction") {
+		return fmt.Errorf("deploy: haproxy: set ssl cert: %s", response)
+	}
+
+	response, err = h.send(ctx, fmt.Sprintf("commit ssl cert %s\n", certPath))
+	if err != nil {
+		return fmt.Errorf("deploy: haproxy: commit ssl cert: %w", err)
+	}
+
+	if !strings.Contains(response, "Success") {
+		return fmt.Errorf("deploy: haproxy: commit ssl cert: %s", response)
+	}
+
+	return nil
+}
+
+// send sends a command, the runtime API closes the connection after the response.
+func (h *HAProxy) send(ctx context.Context, command string) (string, error) {
+	network := "tcp"
+	if strings.HasPrefix(h.Addr, "/") || strings.HasPrefix(h.Addr, "@") {
+		network = "unix"
+	}
+
+	conn, err := h.dialer.DialContext(ctx, network, h.Addr)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+
+	_ = conn.SetDeadline(deadline)
+
+	_, err = io.WriteString(conn, command)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := io.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(response)), nil
+}
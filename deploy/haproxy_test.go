@@ -0,0 +1,113 @@
+package deploy
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockHAProxy a fake runtime API: one command per connection.
+func mockHAProxy(t *testing.T, responses map[string]string) (addr string, commands func() []string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			reader := bufio.NewReader(conn)
+
+			line, _ := reader.ReadString('\n')
+
+			command := strings.TrimSpace(line)
+
+			if strings.HasSuffix(command, "<<") {
+				var payload []string
+
+				for {
+					l, err := reader.ReadString('\n')
+					if err != nil || strings.TrimSpace(l) == "" {
+						break
+					}
+
+					payload = append(payload, strings.TrimSpace(l))
+				}
+
+				command += " " + strings.Join(payload, "|")
+			}
+
+			mu.Lock()
+			received = append(received, command)
+			mu.Unlock()
+
+			verb, _, _ := strings.Cut(command, " /")
+
+			_, _ = conn.Write([]byte(responses[verb] + "\n"))
+			_ = conn.Close()
+		}
+	}()
+
+	return listener.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return received
+	}
+}
+
+func TestHAProxy_Deploy(t *testing.T) {
+	addr, commands := mockHAProxy(t, map[string]string{
+		"set ssl cert":    "Transaction created for certificate /etc/haproxy/certs/example.com.pem!",
+		"commit ssl cert": "Committing /etc/haproxy/certs/example.com.pem.\nSuccess!",
+	})
+
+	deployer := &HAProxy{Addr: addr}
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert\nchain\n"),
+		PrivateKey:  []byte("key\n"),
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		"set ssl cert /etc/haproxy/certs/example.com.pem << cert|chain|key",
+		"commit ssl cert /etc/haproxy/certs/example.com.pem",
+	}
+
+	assert.Equal(t, expected, commands())
+}
+
+func TestHAProxy_Deploy_error(t *testing.T) {
+	addr, commands := mockHAProxy(t, map[string]string{
+		"set ssl cert": "unable to load the certificate",
+	})
+
+	deployer := &HAProxy{Addr: addr, CertPath: "/site.pem"}
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert\n"),
+		PrivateKey:  []byte("key\n"),
+	})
+	require.EqualError(t, err, "deploy: haproxy: set ssl cert: unable to load the certificate")
+
+	assert.Len(t, commands(), 1)
+}
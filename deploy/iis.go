@@ -0,0 +1,103 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/certstore"
+)
+
+// IIS a Deployer importing the certificate into the Windows certificate store (LocalMachine),
+// then binding it to IIS sites and HTTP.sys bindings (Windows only).
+type IIS struct {
+	// Sites the names of the IIS sites, all their HTTPS bindings are updated.
+	Sites []string
+
+	// Bindings the HTTP.sys bindings: `<ip>:<port>` (ex: `0.0.0.0:443`) or `<hostname>:<port>` (SNI).
+	Bindings []string
+
+	// StoreName the name of the LocalMachine store (default: My).
+	StoreName string
+
+	// FriendlyName the friendly name of the certificate (default: `<domain> <date>`).
+	FriendlyName string
+}
+
+func openIIS(value string) (*IIS, error) {
+	rawTargets, rawQuery, _ := strings.Cut(value, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("deploy: iis: %w", err)
+	}
+
+	i := &IIS{
+		StoreName:    query.Get("store"),
+		FriendlyName: query.Get("friendly-name"),
+	}
+
+	for target := range strings.SplitSeq(rawTargets, ",") {
+		target = strings.TrimSpace(target)
+
+		switch {
+		case target == "":
+			continue
+
+		case strings.Contains(target, ":"):
+			i.Bindings = append(i.Bindings, target)
+
+		default:
+			i.Sites = append(i.Sites, target)
+		}
+	}
+
+	if len(i.Sites) == 0 && len(i.Bindings) == 0 {
+		return nil, errors.New("deploy: iis: missing sites or bindings")
+	}
+
+	return i, nil
+}
+
+// Deploy imports the certificate, and updates the bindings with its thumbprint.
+func (i *IIS) Deploy(ctx context.Context, cert *Certificate) error {
+	if len(cert.PrivateKey) == 0 {
+		return errors.New("deploy: iis: the private key is required")
+	}
+
+	friendlyName := i.FriendlyName
+	if friendlyName == "" {
+		friendlyName = fmt.Sprintf("%s %s", cert.Domain, time.Now().UTC().Format(time.DateOnly))
+	}
+
+	thumbprint, err := certstore.Import(cert.Certificate, cert.PrivateKey, cert.IssuerCertificate, &certstore.Options{
+		FriendlyName: friendlyName,
+		StoreName:    i.StoreName,
+	})
+	if err != nil {
+		return fmt.Errorf("deploy: iis: %w", err)
+	}
+
+	bindings := i.Bindings
+
+	for _, site := range i.Sites {
+		siteBindings, err := certstore.SiteBindings(ctx, site)
+		if err != nil {
+			return fmt.Errorf("deploy: iis: %w", err)
+		}
+
+		bindings = append(bindings, siteBindings...)
+	}
+
+	for _, binding := range bindings {
+		err = certstore.UpdateIISBinding(ctx, binding, thumbprint, i.StoreName)
+		if err != nil {
+			return fmt.Errorf("deploy: iis: %w", err)
+		}
+	}
+
+	return nil
+}
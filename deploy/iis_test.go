@@ -0,0 +1,21 @@
+//go:build !windows
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/digicert/lego/v4/certstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIIS_Deploy_notSupported(t *testing.T) {
+	deployer := &IIS{Sites: []string{"Default Web Site"}}
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.ErrorIs(t, err, certstore.ErrNotSupported)
+}
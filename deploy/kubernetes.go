@@ -0,0 +1,411 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	inClusterTokenFile     = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile        = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// Kubernetes a Deployer creating or updating a TLS Secret (`kubernetes.io/tls`).
+//
+// The credentials are read from:
+//   - the kubeconfig file (Kubeconfig), if defined.
+//   - the service account of the pod (in-cluster).
+//   - the kubeconfig file defined by the `KUBECONFIG` environment variable, or `~/.kube/config`.
+//
+// Only the token and the client certificates are supported (not the exec plugins).
+type Kubernetes struct {
+	// Namespace of the Secret, if empty the namespace of the context (or of the pod) is used.
+	Namespace string
+	Name      string
+
+	Kubeconfig string
+	// Context the context of the kubeconfig file, if empty the current context is used.
+	Context string
+}
+
+func openKubernetes(value string) (*Kubernetes, error) {
+	secret, rawQuery, _ := strings.Cut(value, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("deploy: kubernetes: %w", err)
+	}
+
+	k := &Kubernetes{
+		Name:       secret,
+		Kubeconfig: query.Get("kubeconfig"),
+		Context:    query.Get("context"),
+	}
+
+	if namespace, name, ok := strings.Cut(secret, "/"); ok {
+		k.Namespace = namespace
+		k.Name = name
+	}
+
+	if k.Name == "" {
+		return nil, errors.New("deploy: kubernetes: missing secret name")
+	}
+
+	return k, nil
+}
+
+// Deploy creates or updates the Secret (server-side apply).
+func (k *Kubernetes) Deploy(ctx context.Context, cert *Certificate) error {
+	if len(cert.PrivateKey) == 0 {
+		return errors.New("deploy: kubernetes: the private key is required")
+	}
+
+	client, err := k.newClient()
+	if err != nil {
+		return fmt.Errorf("deploy: kubernetes: %w", err)
+	}
+
+	namespace := k.Namespace
+	if namespace == "" {
+		namespace = client.namespace
+	}
+
+	secret := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":      k.Name,
+			"namespace": namespace,
+		},
+		"type": "kubernetes.io/tls",
+		"data": map[string][]byte{
+			"tls.crt": cert.Certificate,
+			"tls.key": cert.PrivateKey,
+		},
+	}
+
+	err = client.apply(ctx, namespace, k.Name, secret)
+	if err != nil {
+		return fmt.Errorf("deploy: kubernetes: secret %s/%s: %w", namespace, k.Name, err)
+	}
+
+	return nil
+}
+
+func (k *Kubernetes) newClient() (*kubeClient, error) {
+	if k.Kubeconfig != "" {
+		return newKubeClientFromConfig(k.Kubeconfig, k.Context)
+	}
+
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host != "" && port != "" {
+		if _, err := os.Stat(inClusterTokenFile); err == nil {
+			return newKubeClientInCluster(host, port)
+		}
+	}
+
+	filename, _, _ := strings.Cut(os.Getenv("KUBECONFIG"), string(os.PathListSeparator))
+	if filename == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+
+		filename = filepath.Join(home, ".kube", "config")
+	}
+
+	return newKubeClientFromConfig(filename, k.Context)
+}
+
+type kubeClient struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	namespace  string
+}
+
+func newKubeClientInCluster(host, port string) (*kubeClient, error) {
+	token, err := os.ReadFile(inClusterTokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM, err := os.ReadFile(inClusterCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := "default"
+	if data, errN := os.ReadFile(inClusterNamespaceFile); errN == nil {
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("invalid in-cluster CA certificate")
+	}
+
+	return &kubeClient{
+		baseURL:    &url.URL{Scheme: "https", Host: net.JoinHostPort(host, port)},
+		httpClient: newKubeHTTPClient(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		token:      strings.TrimSpace(string(token)),
+		namespace:  namespace,
+	}, nil
+}
+
+func newKubeHTTPClient(tlsConfig *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Timeout: 30 * time.Second, Transport: transport}
+}
+
+func (c *kubeClient) apply(ctx context.Context, namespace, name string, object any) error {
+	endpoint := c.baseURL.JoinPath("api", "v1", "namespaces", namespace, "secrets", name)
+
+	query := endpoint.Query()
+	query.Set("fieldManager", "lego")
+	query.Set("force", "true")
+	endpoint.RawQuery = query.Encode()
+
+	body, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML.
+	req.Header.Set("Content-Type", "application/apply-patch+yaml")
+	req.Header.Set("Accept", "application/json")
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+
+	raw, _ := io.ReadAll(resp.Body)
+
+	status := &kubeStatus{}
+
+	err = json.Unmarshal(raw, status)
+	if err != nil || status.Message == "" {
+		return fmt.Errorf("unexpected status code: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	return fmt.Errorf("%s (%s, code: %d)", status.Message, status.Reason, status.Code)
+}
+
+// kubeStatus the error response of the Kubernetes API.
+type kubeStatus struct {
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+	Code    int    `json:"code"`
+}
+
+// kubeconfig the subset of the kubeconfig file used by lego.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+			TLSServerName            string `yaml:"tls-server-name"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			TokenFile             string `yaml:"tokenFile"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+func newKubeClientFromConfig(filename, contextName string) (*kubeClient, error) {
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read kubeconfig: %w", err)
+	}
+
+	config := &kubeconfig{}
+
+	err = yaml.Unmarshal(raw, config)
+	if err != nil {
+		return nil, fmt.Errorf("parse kubeconfig: %w", err)
+	}
+
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+
+	dir := filepath.Dir(filename)
+
+	client := &kubeClient{namespace: "default"}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	var clusterName, userName string
+
+	found := false
+
+	for _, c := range config.Contexts {
+		if c.Name != contextName {
+			continue
+		}
+
+		found = true
+		clusterName, userName = c.Context.Cluster, c.Context.User
+
+		if c.Context.Namespace != "" {
+			client.namespace = c.Context.Namespace
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+
+	found = false
+
+	for _, c := range config.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+
+		found = true
+
+		client.baseURL, err = url.Parse(c.Cluster.Server)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %q: %w", clusterName, err)
+		}
+
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		tlsConfig.ServerName = c.Cluster.TLSServerName
+
+		caPEM, err := readKubeData(dir, c.Cluster.CertificateAuthority, c.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %q: certificate authority: %w", clusterName, err)
+		}
+
+		if len(caPEM) > 0 {
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+				return nil, fmt.Errorf("cluster %q: invalid certificate authority", clusterName)
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig", clusterName)
+	}
+
+	for _, u := range config.Users {
+		if u.Name != userName {
+			continue
+		}
+
+		client.token = u.User.Token
+
+		if client.token == "" && u.User.TokenFile != "" {
+			token, err := os.ReadFile(resolvePath(dir, u.User.TokenFile))
+			if err != nil {
+				return nil, fmt.Errorf("user %q: %w", userName, err)
+			}
+
+			client.token = strings.TrimSpace(string(token))
+		}
+
+		certPEM, err := readKubeData(dir, u.User.ClientCertificate, u.User.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("user %q: client certificate: %w", userName, err)
+		}
+
+		keyPEM, err := readKubeData(dir, u.User.ClientKey, u.User.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("user %q: client key: %w", userName, err)
+		}
+
+		if len(certPEM) > 0 {
+			clientCert, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				return nil, fmt.Errorf("user %q: %w", userName, err)
+			}
+
+			tlsConfig.Certificates = []tls.Certificate{clientCert}
+		}
+	}
+
+	client.httpClient = newKubeHTTPClient(tlsConfig)
+
+	return client, nil
+}
+
+// readKubeData reads a value of the kubeconfig file: from a file (path relative to the kubeconfig file), or base64 encoded data.
+func readKubeData(dir, filename, data string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+
+	if filename != "" {
+		return os.ReadFile(resolvePath(dir, filename))
+	}
+
+	return nil, nil
+}
+
+func resolvePath(dir, filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+
+	return filepath.Join(dir, filename)
+}
@@ -0,0 +1,101 @@
+package deploy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/require"
+)
+
+func mockKubernetes() *servermock.Builder[*httptest.Server] {
+	return servermock.NewBuilder[*httptest.Server](
+		func(server *httptest.Server) (*httptest.Server, error) {
+			return server, nil
+		},
+		servermock.CheckHeader().
+			WithAuthorization("Bearer secret").
+			WithContentType("application/apply-patch+yaml"),
+	)
+}
+
+// writeKubeconfig writes a kubeconfig file to access the mock server.
+func writeKubeconfig(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	caPEM := certcrypto.PEMEncode(certcrypto.DERCertificateBytes(server.Certificate().Raw))
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+users:
+- name: test
+  user:
+    token: secret
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+    namespace: lego
+`, server.URL, base64.StdEncoding.EncodeToString(caPEM))
+
+	filename := filepath.Join(t.TempDir(), "kubeconfig")
+
+	err := os.WriteFile(filename, []byte(kubeconfig), 0o600)
+	require.NoError(t, err)
+
+	return filename
+}
+
+func TestKubernetes_Deploy(t *testing.T) {
+	server := mockKubernetes().
+		Route("PATCH /api/v1/namespaces/lego/secrets/example-tls",
+			servermock.JSONEncode(map[string]any{"kind": "Secret"}),
+			servermock.CheckQueryParameter().Strict().
+				With("fieldManager", "lego").
+				With("force", "true"),
+			servermock.CheckRequestJSONBody(`{"apiVersion":"v1","data":{"tls.crt":"Y2VydA==","tls.key":"a2V5"},"kind":"Secret","metadata":{"name":"example-tls","namespace":"lego"},"type":"kubernetes.io/tls"}`)).
+		BuildHTTPS(t)
+
+	deployer := &Kubernetes{Name: "example-tls", Kubeconfig: writeKubeconfig(t, server)}
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.NoError(t, err)
+}
+
+func TestKubernetes_Deploy_error(t *testing.T) {
+	server := mockKubernetes().
+		Route("PATCH /api/v1/namespaces/other/secrets/example-tls",
+			servermock.JSONEncode(map[string]any{
+				"kind":    "Status",
+				"message": `secrets "example-tls" is forbidden: User "lego" cannot patch resource "secrets"`,
+				"reason":  "Forbidden",
+				"code":    http.StatusForbidden,
+			}).WithStatusCode(http.StatusForbidden)).
+		BuildHTTPS(t)
+
+	deployer := &Kubernetes{Namespace: "other", Name: "example-tls", Kubeconfig: writeKubeconfig(t, server)}
+
+	err := deployer.Deploy(t.Context(), &Certificate{
+		Domain:      "example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+	require.EqualError(t, err, `deploy: kubernetes: secret other/example-tls: secrets "example-tls" is forbidden: User "lego" cannot patch resource "secrets" (Forbidden, code: 403)`)
+}
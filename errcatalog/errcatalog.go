@@ -0,0 +1,247 @@
+// Package errcatalog maps the common failures (ACME problems, DNS provider errors) to actionable remediation messages.
+package errcatalog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/digicert/lego/v4/acme"
+)
+
+const docURL = "https://go-acme.github.io/lego/usage/cli/errors/"
+
+// Verbosity defines the content of the formatted errors.
+type Verbosity int
+
+// Verbosity levels.
+const (
+	// VerbosityRaw the error only.
+	VerbosityRaw Verbosity = iota
+
+	// VerbosityHint the error, and the remediation of the known failures.
+	VerbosityHint
+
+	// VerbosityFull the error, and the explanation, the remediation, and the documentation link of the known failures.
+	VerbosityFull
+)
+
+// ParseVerbosity parses a verbosity level: raw, hint, or full.
+func ParseVerbosity(value string) (Verbosity, error) {
+	switch strings.ToLower(value) {
+	case "raw":
+		return VerbosityRaw, nil
+	case "hint", "":
+		return VerbosityHint, nil
+	case "full":
+		return VerbosityFull, nil
+	default:
+		return VerbosityRaw, fmt.Errorf("unsupported error verbosity: %s", value)
+	}
+}
+
+// Entry is a known failure.
+type Entry struct {
+	// ID identifies the failure (ex: `caa`), it's also the anchor of the documentation.
+	ID string
+
+	// Summary explains the failure.
+	Summary string
+
+	// Remediation describes the actions to fix the failure.
+	Remediation string
+
+	match func(err error) bool
+}
+
+// DocURL returns the link to the documentation of the failure.
+func (e Entry) DocURL() string {
+	return docURL + "#" + e.ID
+}
+
+// The entries are sorted from the most specific to the most generic.
+var entries = []Entry{
+	{
+		ID:      "caa",
+		Summary: "A CAA record of the domain doesn't allow the CA to issue certificates for the domain.",
+		Remediation: "Add a CAA record allowing the CA (ex: `example.com. CAA 0 issue \"digicert.com\"`), " +
+			"or remove the restrictive CAA records of the domain and its parents.",
+		match: problemType(acme.CAAErr),
+	},
+	{
+		ID:      "rate-limited",
+		Summary: "The CA rejected the request because a rate limit is reached.",
+		Remediation: "Wait before retrying (the error may define when), avoid obtaining the same certificate several times, " +
+			"and use a test server (--server) to try your configuration.",
+		match: problemType(acme.RateLimitedErr),
+	},
+	{
+		ID:      "nxdomain",
+		Summary: "The domain, or the challenge record, doesn't exist in the public DNS (NXDOMAIN).",
+		Remediation: "Check that the domain is delegated to the name servers of your DNS provider, and that the zone is published. " +
+			"For a private zone, use --dns.resolvers with a name server which knows the zone.",
+		match: anyOf(problemType(acme.DNSErr), containsText("NXDOMAIN")),
+	},
+	{
+		ID:      "unauthorized-token",
+		Summary: "The DNS provider rejected the credentials.",
+		Remediation: "Check the credentials of the DNS provider (see `lego dnshelp -c <provider>`), " +
+			"their permissions on the zone, and their expiration.",
+		match: containsText("401", "unauthorized", "invalid token", "invalid api token", "invalid api key", "invalid credentials", "authentication failed"),
+	},
+	{
+		ID:      "propagation-timeout",
+		Summary: "The challenge record was not found on the name servers before the propagation timeout.",
+		Remediation: "Increase the propagation timeout of the DNS provider (`<PROVIDER>_PROPAGATION_TIMEOUT`), " +
+			"or check that the record is published on the authoritative name servers of the zone.",
+		match: messageContains("propagation: time limit exceeded"),
+	},
+	{
+		ID:      "validation",
+		Summary: "The CA could not validate the challenge.",
+		Remediation: "Check that the challenge is reachable from the internet: " +
+			"the port 80 (HTTP-01), the port 443 (TLS-ALPN-01), or the TXT record `_acme-challenge` (DNS-01).",
+		match: problemType(acme.UnauthorizedErr, acme.ConnectionErr),
+	},
+}
+
+// Lookup returns the known failures of the error, in the order of the catalog.
+// All the errors of the tree are inspected (ex: the errors of several domains joined together).
+func Lookup(err error) []Entry {
+	if err == nil {
+		return nil
+	}
+
+	var nodes []error
+
+	walk(err, func(node error) {
+		nodes = append(nodes, node)
+	})
+
+	var found []Entry
+
+	for _, entry := range entries {
+		for _, node := range nodes {
+			if entry.match(node) {
+				found = append(found, entry)
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// Format formats the error with the known failures, according to the verbosity.
+func Format(err error, verbosity Verbosity) string {
+	if err == nil {
+		return ""
+	}
+
+	msg := new(strings.Builder)
+	msg.WriteString(err.Error())
+
+	if verbosity == VerbosityRaw {
+		return msg.String()
+	}
+
+	for _, entry := range Lookup(err) {
+		_, _ = fmt.Fprintf(msg, "\n\nHint (%s): %s", entry.ID, entry.Remediation)
+
+		if verbosity == VerbosityFull {
+			_, _ = fmt.Fprintf(msg, "\n\t%s\n\tSee %s", entry.Summary, entry.DocURL())
+		}
+	}
+
+	return msg.String()
+}
+
+// walk calls fn for each error of the tree.
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+
+	fn(err)
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(e.Unwrap(), fn)
+
+	case interface{ Unwrap() []error }:
+		for _, child := range e.Unwrap() {
+			walk(child, fn)
+		}
+	}
+}
+
+// problemType matches the ACME problems of the types (or with subproblems of the types).
+func problemType(types ...string) func(err error) bool {
+	return func(err error) bool {
+		problem, ok := err.(*acme.ProblemDetails)
+		if !ok {
+			return false
+		}
+
+		for _, typ := range types {
+			if problem.Type == typ {
+				return true
+			}
+
+			for _, sub := range problem.SubProblems {
+				if sub.Type == typ {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// containsText matches the errors without wrapped errors (except the ACME problems) containing one of the texts.
+// The texts are compared case-insensitively.
+func containsText(texts ...string) func(err error) bool {
+	return func(err error) bool {
+		if _, ok := err.(*acme.ProblemDetails); ok {
+			return false
+		}
+
+		if errors.Unwrap(err) != nil {
+			return false
+		}
+
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return false
+		}
+
+		msg := strings.ToLower(err.Error())
+
+		for _, text := range texts {
+			if strings.Contains(msg, strings.ToLower(text)) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// messageContains matches the errors containing the text, including the errors wrapping other errors.
+func messageContains(text string) func(err error) bool {
+	return func(err error) bool {
+		return strings.Contains(err.Error(), text)
+	}
+}
+
+func anyOf(matchers ...func(err error) bool) func(err error) bool {
+	return func(err error) bool {
+		for _, match := range matchers {
+			if match(err) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
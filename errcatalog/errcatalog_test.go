@@ -0,0 +1,118 @@
+package errcatalog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected []string
+	}{
+		{
+			desc: "CAA",
+			err: fmt.Errorf("invalid authorization: %w", &acme.ProblemDetails{
+				Type:   acme.CAAErr,
+				Detail: "CAA record for example.com prevents issuance",
+			}),
+			expected: []string{"caa"},
+		},
+		{
+			desc: "rate limited",
+			err: &acme.RateLimitedError{ProblemDetails: &acme.ProblemDetails{
+				Type:       acme.RateLimitedErr,
+				HTTPStatus: 429,
+			}},
+			expected: []string{"rate-limited"},
+		},
+		{
+			desc: "CAA subproblem",
+			err: &acme.ProblemDetails{
+				Type:        "urn:ietf:params:acme:error:compound",
+				SubProblems: []acme.SubProblem{{Type: acme.CAAErr}},
+			},
+			expected: []string{"caa"},
+		},
+		{
+			desc:     "NXDOMAIN",
+			err:      fmt.Errorf("ovh: could not find zone for domain %q: %w", "example.com", errors.New("unexpected response code 'NXDOMAIN' for example.com.")),
+			expected: []string{"nxdomain"},
+		},
+		{
+			desc:     "unauthorized token",
+			err:      fmt.Errorf("cloudflare: failed to find zone: %w", errors.New("Invalid API Token (1000)")),
+			expected: []string{"unauthorized-token"},
+		},
+		{
+			desc:     "ACME unauthorized",
+			err:      &acme.ProblemDetails{Type: acme.UnauthorizedErr, Detail: "Incorrect TXT record"},
+			expected: []string{"validation"},
+		},
+		{
+			desc:     "propagation timeout",
+			err:      fmt.Errorf("propagation: time limit exceeded: last error: %w", errors.New("NS ns1.example.com. did not return the expected TXT record")),
+			expected: []string{"propagation-timeout"},
+		},
+		{
+			desc: "several domains",
+			err: errors.Join(
+				fmt.Errorf("a.example.com: %w", &acme.ProblemDetails{Type: acme.CAAErr}),
+				fmt.Errorf("b.example.com: %w", &acme.ProblemDetails{Type: acme.DNSErr}),
+			),
+			expected: []string{"caa", "nxdomain"},
+		},
+		{
+			desc: "unknown",
+			err:  errors.New("oops"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var ids []string
+			for _, entry := range Lookup(test.err) {
+				ids = append(ids, entry.ID)
+			}
+
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	err := fmt.Errorf("invalid authorization: %w", &acme.ProblemDetails{Type: acme.CAAErr, Detail: "CAA record prevents issuance"})
+
+	assert.Equal(t, err.Error(), Format(err, VerbosityRaw))
+
+	assert.Equal(t, err.Error()+"\n\nHint (caa): "+entries[0].Remediation, Format(err, VerbosityHint))
+
+	assert.Equal(t, err.Error()+"\n\nHint (caa): "+entries[0].Remediation+
+		"\n\t"+entries[0].Summary+
+		"\n\tSee https://go-acme.github.io/lego/usage/cli/errors/#caa", Format(err, VerbosityFull))
+
+	assert.Empty(t, Format(nil, VerbosityFull))
+}
+
+func TestParseVerbosity(t *testing.T) {
+	verbosity, err := ParseVerbosity("full")
+	require.NoError(t, err)
+
+	assert.Equal(t, VerbosityFull, verbosity)
+
+	verbosity, err = ParseVerbosity("")
+	require.NoError(t, err)
+
+	assert.Equal(t, VerbosityHint, verbosity)
+
+	_, err = ParseVerbosity("foo")
+	require.EqualError(t, err, "unsupported error verbosity: foo")
+}
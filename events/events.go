@@ -0,0 +1,159 @@
+// Package events provides the typed events emitted by the lego client during the certificate lifecycle.
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Event is an event emitted by the lego client.
+// The concrete types are: OrderCreated, ChallengePresented, PropagationConfirmed, CertificateObtained, and CleanupFailed.
+type Event interface {
+	isEvent()
+}
+
+// OrderCreated is emitted when an order has been created by the ACME server.
+type OrderCreated struct {
+	Domains  []string
+	OrderURL string
+}
+
+// ChallengePresented is emitted when a challenge is ready to be validated
+// (ex: the TXT record has been created, the HTTP server has been started).
+type ChallengePresented struct {
+	Domain string
+	Type   string
+}
+
+// PropagationConfirmed is emitted when the DNS-01 TXT record has been found on the name servers.
+type PropagationConfirmed struct {
+	Domain   string
+	FQDN     string
+	Duration time.Duration
+}
+
+// CertificateObtained is emitted when a certificate has been obtained (or renewed).
+type CertificateObtained struct {
+	Domain   string
+	Domains  []string
+	OrderURL string
+	CertURL  string
+}
+
+// CleanupFailed is emitted when the cleanup of a challenge failed.
+type CleanupFailed struct {
+	Domain string
+	Type   string
+	Err    error
+}
+
+func (OrderCreated) isEvent()         {}
+func (ChallengePresented) isEvent()   {}
+func (PropagationConfirmed) isEvent() {}
+func (CertificateObtained) isEvent()  {}
+func (CleanupFailed) isEvent()        {}
+
+// Handler handles an event.
+type Handler func(event Event)
+
+// Bus dispatches the events to the subscribers.
+// A nil Bus is valid and discards all the events.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []subscription
+	nextID   int
+}
+
+type subscription struct {
+	id      int
+	handler Handler
+}
+
+// NewBus creates a new Bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler, and returns a function to unregister it.
+// The handlers are called synchronously, in the goroutine of the publisher:
+// a handler must not block and must be safe for concurrent use.
+func (b *Bus) Subscribe(handler Handler) (unsubscribe func()) {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	b.handlers = append(b.handlers, subscription{id: id, handler: handler})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.handlers = slices.DeleteFunc(b.handlers, func(s subscription) bool { return s.id == id })
+	}
+}
+
+// Channel returns a channel receiving the events until the context is done.
+// The events are dropped when the buffer of the channel is full, the issuance is never blocked by a slow consumer.
+func (b *Bus) Channel(ctx context.Context, size int) <-chan Event {
+	ch := make(chan Event, size)
+
+	// the mutex protects the channel against a send after close.
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+
+	unsubscribe := b.Subscribe(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if closed {
+			return
+		}
+
+		select {
+		case ch <- event:
+		default:
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+
+		unsubscribe()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		closed = true
+
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Publish sends an event to all the subscribers.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+
+	handlers := slices.Clone(b.handlers)
+
+	b.mu.RUnlock()
+
+	for _, s := range handlers {
+		s.handler(event)
+	}
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus_Subscribe(t *testing.T) {
+	bus := NewBus()
+
+	var got []string
+
+	unsubscribeA := bus.Subscribe(func(event Event) {
+		got = append(got, "a:"+event.(ChallengePresented).Domain)
+	})
+
+	bus.Subscribe(func(event Event) {
+		got = append(got, "b:"+event.(ChallengePresented).Domain)
+	})
+
+	bus.Publish(ChallengePresented{Domain: "example.com", Type: "dns-01"})
+
+	unsubscribeA()
+
+	bus.Publish(ChallengePresented{Domain: "example.org", Type: "dns-01"})
+
+	assert.Equal(t, []string{"a:example.com", "b:example.com", "b:example.org"}, got)
+}
+
+func TestBus_Channel(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	ch := bus.Channel(ctx, 2)
+
+	bus.Publish(OrderCreated{OrderURL: "https://example.com/order/1"})
+	bus.Publish(CertificateObtained{Domain: "example.com"})
+	// dropped: the buffer is full.
+	bus.Publish(CertificateObtained{Domain: "example.org"})
+
+	assert.Equal(t, OrderCreated{OrderURL: "https://example.com/order/1"}, <-ch)
+	assert.Equal(t, CertificateObtained{Domain: "example.com"}, <-ch)
+
+	cancel()
+
+	_, ok := <-ch
+	require.False(t, ok)
+
+	// no panic after the channel is closed.
+	bus.Publish(CertificateObtained{Domain: "example.net"})
+}
+
+func TestBus_nil(t *testing.T) {
+	var bus *Bus
+
+	unsubscribe := bus.Subscribe(func(Event) {
+		t.Fatal("unexpected call")
+	})
+
+	bus.Publish(CertificateObtained{Domain: "example.com"})
+
+	unsubscribe()
+}
@@ -0,0 +1,45 @@
+// Package useragent holds the application identifier appended to the User-Agent of the HTTP requests (ACME and providers).
+package useragent
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+var application atomic.Pointer[string]
+
+// SetApplication sets the application identifier (ex: `myapp/1.2.3`) appended to the User-Agents.
+// An empty identifier removes it.
+func SetApplication(app string) error {
+	app = strings.TrimSpace(app)
+
+	if strings.ContainsFunc(app, unicode.IsControl) {
+		return errors.New("the application identifier of the User-Agent contains control characters")
+	}
+
+	application.Store(&app)
+
+	return nil
+}
+
+// Application returns the application identifier.
+func Application() string {
+	app := application.Load()
+	if app == nil {
+		return ""
+	}
+
+	return *app
+}
+
+// Append appends the application identifier to a User-Agent.
+func Append(ua string) string {
+	app := Application()
+	if app == "" {
+		return ua
+	}
+
+	return ua + " " + app
+}
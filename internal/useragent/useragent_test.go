@@ -0,0 +1,34 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppend(t *testing.T) {
+	t.Cleanup(func() { _ = SetApplication("") })
+
+	assert.Equal(t, "lego/4.0.0", Append("lego/4.0.0"))
+
+	err := SetApplication(" myapp/1.2.3 ")
+	require.NoError(t, err)
+
+	assert.Equal(t, "myapp/1.2.3", Application())
+	assert.Equal(t, "lego/4.0.0 myapp/1.2.3", Append("lego/4.0.0"))
+
+	err = SetApplication("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "lego/4.0.0", Append("lego/4.0.0"))
+}
+
+func TestSetApplication_invalid(t *testing.T) {
+	t.Cleanup(func() { _ = SetApplication("") })
+
+	err := SetApplication("myapp/1.2.3\r\nX-Injected: true")
+	require.EqualError(t, err, "the application identifier of the User-Agent contains control characters")
+
+	assert.Empty(t, Application())
+}
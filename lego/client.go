@@ -7,6 +7,9 @@ import (
 	"github.com/digicert/lego/v4/acme/api"
 	"github.com/digicert/lego/v4/certificate"
 	"github.com/digicert/lego/v4/challenge/resolver"
+	"github.com/digicert/lego/v4/events"
+	"github.com/digicert/lego/v4/internal/useragent"
+	"github.com/digicert/lego/v4/platform/debugcapture"
 	"github.com/digicert/lego/v4/registration"
 )
 
@@ -35,6 +38,13 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, errors.New("the HTTP client cannot be nil")
 	}
 
+	if config.Application != "" {
+		err = useragent.SetApplication(config.Application)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	privateKey := config.User.GetPrivateKey()
 	if privateKey == nil {
 		return nil, errors.New("private key was nil")
@@ -45,20 +55,33 @@ func NewClient(config *Config) (*Client, error) {
 		kid = reg.URI
 	}
 
-	core, err := api.New(config.HTTPClient, config.UserAgent, config.CADirURL, kid, privateKey)
+	httpClient := debugcapture.WrapClient(config.HTTPClient, debugcapture.ACME)
+
+	core, err := api.New(httpClient, config.UserAgent, config.CADirURL, kid, privateKey)
 	if err != nil {
 		return nil, err
 	}
 
+	core.SetLogger(config.Logger)
+	core.SetProgressReporter(config.ProgressReporter)
+
 	solversManager := resolver.NewSolversManager(core)
 
 	prober := resolver.NewProber(solversManager)
+	prober.SetChallengeTimeout(config.Certificate.ChallengeTimeout)
+	prober.SetCleanupPolicy(resolver.CleanupPolicy{
+		Timeout: config.Certificate.CleanupTimeout,
+		Retries: config.Certificate.CleanupRetries,
+	})
+	prober.SetConcurrentSolves(config.Certificate.ConcurrentSolves)
 
 	options := certificate.CertifierOptions{
 		KeyType:             config.Certificate.KeyType,
 		Timeout:             config.Certificate.Timeout,
+		OrderTimeout:        config.Certificate.OrderTimeout,
 		OverallRequestLimit: config.Certificate.OverallRequestLimit,
 		DisableCommonName:   config.Certificate.DisableCommonName,
+		AuditLogger:         config.Certificate.AuditLogger,
 	}
 
 	certifier := certificate.NewCertifier(core, prober, options)
@@ -76,7 +99,20 @@ func (c *Client) GetToSURL() string {
 	return c.core.GetDirectory().Meta.TermsOfService
 }
 
+// GetProfiles returns the certificate profiles advertised by the CA (name and description), if any.
+// - https://www.ietf.org/id/draft-ietf-acme-profiles-00.html#section-3
+func (c *Client) GetProfiles() map[string]string {
+	return c.core.GetDirectory().Meta.Profiles
+}
+
 // GetExternalAccountRequired returns the External Account Binding requirement of the Directory.
 func (c *Client) GetExternalAccountRequired() bool {
 	return c.core.GetDirectory().Meta.ExternalAccountRequired
 }
+
+// Events returns the event bus of the client.
+// The events (OrderCreated, ChallengePresented, PropagationConfirmed, CertificateObtained, CleanupFailed)
+// can be received with a callback (Subscribe) or a channel (Channel).
+func (c *Client) Events() *events.Bus {
+	return c.core.Events()
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -11,7 +12,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/digicert/lego/v4/audit"
 	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/progress"
 	"github.com/digicert/lego/v4/registration"
 )
 
@@ -46,6 +50,20 @@ type Config struct {
 	UserAgent   string
 	HTTPClient  *http.Client
 	Certificate CertificateConfig
+
+	// Logger the logger used by the client (certifier, solvers, registrar).
+	// If nil, the default logger (log.Default) is used.
+	// The DNS providers always use the default logger, see log.SetDefault.
+	Logger *slog.Logger
+
+	// ProgressReporter receives the progress (phases, percentages, attempts) of the certificate operations (obtain, renew), if not nil.
+	ProgressReporter progress.Reporter
+
+	// Application identifies the application embedding lego (ex: `myapp/1.2.3`),
+	// so the CAs and the DNS providers can attribute the traffic.
+	// It's appended to the User-Agent of the ACME requests and of the requests of the DNS providers.
+	// The identifier is process-wide: the last client created with a non-empty Application defines it.
+	Application string
 }
 
 func NewConfig(user registration.User) *Config {
@@ -55,16 +73,39 @@ func NewConfig(user registration.User) *Config {
 		HTTPClient: createDefaultHTTPClient(),
 		Certificate: CertificateConfig{
 			KeyType: certcrypto.RSA2048,
-			Timeout: 30 * time.Second,
+			Timeout: certificate.DefaultTimeout,
 		},
 	}
 }
 
 type CertificateConfig struct {
-	KeyType             certcrypto.KeyType
-	Timeout             time.Duration
+	KeyType certcrypto.KeyType
+
+	// Timeout the maximum duration of the wait for the certificate (finalization and download).
+	Timeout time.Duration
+
+	// OrderTimeout the maximum duration of an obtain or a renew, if positive.
+	OrderTimeout time.Duration
+
+	// ChallengeTimeout the maximum duration of the solving of each authorization
+	// (presentation, propagation, and validation of the challenge), if positive.
+	ChallengeTimeout time.Duration
+
+	// CleanupTimeout the maximum duration of each attempt of cleanup of a challenge, if positive.
+	CleanupTimeout time.Duration
+
+	// CleanupRetries the number of additional attempts of cleanup of a challenge after a failure.
+	CleanupRetries int
+
+	// ConcurrentSolves the maximum number of authorizations solved concurrently (see resolver.Prober.SetConcurrentSolves).
+	// The authorizations are solved one after the other if lower than 2.
+	ConcurrentSolves int
+
 	OverallRequestLimit int
 	DisableCommonName   bool
+
+	// AuditLogger records the certificate lifecycle events (obtain, renew, revoke), if not nil.
+	AuditLogger audit.Logger
 }
 
 // createDefaultHTTPClient Creates an HTTP client with a reasonable timeout value
@@ -0,0 +1,63 @@
+package legotls
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	"github.com/digicert/lego/v4/storage"
+)
+
+// ErrCacheMiss is returned (wrapped) by a Cache when an entry doesn't exist.
+var ErrCacheMiss = storage.ErrNotExist
+
+// Cache is used by the Manager to store and retrieve the certificates.
+//
+// An entry contains the PEM encoded private key followed by the PEM encoded certificate chain.
+type Cache interface {
+	// Get returns the content of an entry.
+	// The error wraps ErrCacheMiss if the entry doesn't exist.
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Put stores the content of an entry, the entry is replaced if it already exists.
+	Put(ctx context.Context, name string, data []byte) error
+
+	// Delete removes an entry, it's not an error if the entry doesn't exist.
+	Delete(ctx context.Context, name string) error
+}
+
+// StorageCache a Cache using a storage backend (filesystem, S3, SQL).
+type StorageCache struct {
+	storage storage.Storage
+	dir     string
+}
+
+// NewStorageCache creates a Cache storing the entries in the directory dir of a storage backend.
+func NewStorageCache(backend storage.Storage, dir string) *StorageCache {
+	return &StorageCache{storage: backend, dir: dir}
+}
+
+// DirCache creates a Cache storing the entries in a directory of the filesystem.
+func DirCache(dir string) *StorageCache {
+	return NewStorageCache(storage.NewFileSystem(), dir)
+}
+
+// Get returns the content of an entry.
+func (c *StorageCache) Get(ctx context.Context, name string) ([]byte, error) {
+	return c.storage.ReadFile(ctx, path.Join(c.dir, name))
+}
+
+// Put stores the content of an entry.
+func (c *StorageCache) Put(ctx context.Context, name string, data []byte) error {
+	return c.storage.WriteFile(ctx, path.Join(c.dir, name), data)
+}
+
+// Delete removes an entry.
+func (c *StorageCache) Delete(ctx context.Context, name string) error {
+	err := c.storage.Remove(ctx, path.Join(c.dir, name))
+	if err != nil && !errors.Is(err, storage.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package legotls
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirCache(t *testing.T) {
+	dir := t.TempDir()
+
+	cache := DirCache(dir)
+
+	_, err := cache.Get(t.Context(), "example.com.pem")
+	require.ErrorIs(t, err, ErrCacheMiss)
+
+	err = cache.Put(t.Context(), "example.com.pem", []byte("data"))
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dir, "example.com.pem"))
+
+	data, err := cache.Get(t.Context(), "example.com.pem")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("data"), data)
+
+	err = cache.Delete(t.Context(), "example.com.pem")
+	require.NoError(t, err)
+
+	err = cache.Delete(t.Context(), "example.com.pem")
+	require.NoError(t, err)
+
+	_, err = cache.Get(t.Context(), "example.com.pem")
+	require.ErrorIs(t, err, ErrCacheMiss)
+}
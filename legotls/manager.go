@@ -0,0 +1,415 @@
+// Package legotls provides a Manager obtaining and renewing the certificates on demand,
+// to be used with the GetCertificate callback of a tls.Config.
+package legotls
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/challenge/tlsalpn01"
+	"github.com/digicert/lego/v4/lego"
+	"github.com/digicert/lego/v4/log"
+)
+
+// DefaultRenewBefore the default duration before the expiration of a certificate when it's renewed.
+const DefaultRenewBefore = 30 * 24 * time.Hour
+
+// DefaultFailureBackoff the default duration during which a failed obtain is not retried.
+const DefaultFailureBackoff = time.Minute
+
+// maxFailureBackoff the maximum duration during which a failed obtain is not retried.
+const maxFailureBackoff = time.Hour
+
+// HostPolicy decides if a certificate can be obtained for a host.
+// An error is returned to reject the host.
+type HostPolicy func(ctx context.Context, host string) error
+
+// HostWhitelist returns a HostPolicy allowing only the given hosts.
+func HostWhitelist(hosts ...string) HostPolicy {
+	allowed := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		allowed[strings.ToLower(h)] = struct{}{}
+	}
+
+	return func(_ context.Context, host string) error {
+		if _, ok := allowed[host]; !ok {
+			return fmt.Errorf("legotls: host %q not configured in HostWhitelist", host)
+		}
+
+		return nil
+	}
+}
+
+type obtainer interface {
+	Obtain(request certificate.ObtainRequest) (*certificate.Resource, error)
+}
+
+// Manager obtains the certificates on demand, during the TLS handshakes, and renews them before their expiration.
+//
+// The challenges are solved with the providers of the client:
+//   - TLS-ALPN-01: the provider returned by TLSALPN01Provider, the challenge certificates are served by GetCertificate.
+//   - HTTP-01: the provider returned by HTTP01Provider, the tokens are served by HTTPHandler.
+//   - DNS-01: any DNS provider, it's required by the wildcard certificates (Wildcards).
+type Manager struct {
+	// Client the ACME client used to obtain the certificates (required).
+	// The account must be registered.
+	Client *lego.Client
+
+	// HostPolicy controls which hosts are allowed.
+	// If nil, all the hosts are denied: anybody could trigger the issuance of certificates otherwise.
+	HostPolicy HostPolicy
+
+	// Cache stores the certificates.
+	// If nil, the certificates are only kept in memory and obtained again after a restart.
+	Cache Cache
+
+	// RenewBefore the duration before the expiration of a certificate when it's renewed.
+	// If zero, DefaultRenewBefore is used.
+	RenewBefore time.Duration
+
+	// Wildcards the wildcard domains (ex: `*.example.com`).
+	// A host matching a wildcard domain uses the wildcard certificate instead of its own certificate.
+	Wildcards []string
+
+	// Bundle if true, the certificates are bundled with the issuer certificates.
+	Bundle bool
+
+	// FailureBackoff the duration during which a failed obtain is not retried for the same name,
+	// the error is returned instead. The duration is doubled after each consecutive failure, up to one hour.
+	// If zero, DefaultFailureBackoff is used.
+	FailureBackoff time.Duration
+
+	obtainer obtainer
+
+	mu       sync.Mutex
+	certs    map[string]*tls.Certificate
+	pending  map[string]*call
+	renewing map[string]struct{}
+	failures map[string]*failure
+
+	challengesMu sync.RWMutex
+	tlsALPN      map[string]*tls.Certificate
+	http         map[string]string
+}
+
+// call an obtain in progress, shared by the concurrent handshakes.
+type call struct {
+	done chan struct{}
+	cert *tls.Certificate
+	err  error
+}
+
+// failure the last failed obtain of a name.
+type failure struct {
+	err   error
+	count int
+	until time.Time
+}
+
+// TLSConfig returns a tls.Config using the Manager to get the certificates.
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: m.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1", tlsalpn01.ACMETLS1Protocol},
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// GetCertificate implements the tls.Config.GetCertificate callback.
+//
+// The certificate is looked up in memory, then in the cache,
+// and obtained from the CA if it doesn't exist yet.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	host := strings.TrimSuffix(strings.ToLower(hello.ServerName), ".")
+	if host == "" {
+		return nil, errors.New("legotls: missing server name")
+	}
+
+	if strings.ContainsAny(host, `/\`) {
+		return nil, fmt.Errorf("legotls: invalid server name %q", host)
+	}
+
+	if slices.Contains(hello.SupportedProtos, tlsalpn01.ACMETLS1Protocol) {
+		m.challengesMu.RLock()
+		cert, ok := m.tlsALPN[host]
+		m.challengesMu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("legotls: no TLS-ALPN-01 challenge for %q", host)
+		}
+
+		return cert, nil
+	}
+
+	ctx := hello.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	name := m.certName(host)
+
+	cert, err := m.cachedCert(ctx, name)
+	if err == nil {
+		m.renewIfNeeded(name, cert)
+
+		return cert, nil
+	}
+
+	if !errors.Is(err, ErrCacheMiss) {
+		return nil, err
+	}
+
+	if m.HostPolicy == nil {
+		return nil, fmt.Errorf("legotls: host %q denied: no HostPolicy", host)
+	}
+
+	err = m.HostPolicy(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.obtain(ctx, name)
+}
+
+// certName returns the name of the certificate used for a host:
+// the matching wildcard domain, or the host itself.
+func (m *Manager) certName(host string) string {
+	for _, wildcard := range m.Wildcards {
+		base, ok := strings.CutPrefix(strings.ToLower(wildcard), "*.")
+		if !ok {
+			continue
+		}
+
+		prefix, ok := strings.CutSuffix(host, "."+base)
+		if ok && prefix != "" && !strings.Contains(prefix, ".") {
+			return "*." + base
+		}
+	}
+
+	return host
+}
+
+// cachedCert returns a certificate from the memory or from the Cache.
+func (m *Manager) cachedCert(ctx context.Context, name string) (*tls.Certificate, error) {
+	m.mu.Lock()
+	cert, ok := m.certs[name]
+	m.mu.Unlock()
+
+	if ok {
+		return cert, nil
+	}
+
+	if m.Cache == nil {
+		return nil, ErrCacheMiss
+	}
+
+	data, err := m.Cache.Get(ctx, cacheKey(name))
+	if err != nil {
+		if errors.Is(err, ErrCacheMiss) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("legotls: read cache: %w", err)
+	}
+
+	cert, err = parseCert(data)
+	if err != nil {
+		return nil, fmt.Errorf("legotls: %s: invalid cache entry: %w", name, err)
+	}
+
+	if time.Now().After(cert.Leaf.NotAfter) {
+		return nil, ErrCacheMiss
+	}
+
+	m.store(name, cert)
+
+	return cert, nil
+}
+
+// obtain obtains a certificate, the concurrent calls for the same name share the same obtain.
+// After a failure, the error is returned without a new obtain until the end of the backoff.
+func (m *Manager) obtain(ctx context.Context, name string) (*tls.Certificate, error) {
+	m.mu.Lock()
+
+	if f, ok := m.failures[name]; ok && time.Now().Before(f.until) {
+		m.mu.Unlock()
+
+		return nil, fmt.Errorf("%w (next attempt after %s)", f.err, f.until.Format(time.RFC3339))
+	}
+
+	if m.pending == nil {
+		m.pending = make(map[string]*call)
+	}
+
+	c, ok := m.pending[name]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		m.pending[name] = c
+
+		go func() {
+			c.cert, c.err = m.obtainAndStore(context.WithoutCancel(ctx), name)
+
+			m.mu.Lock()
+			delete(m.pending, name)
+			m.recordResult(name, c.err)
+			m.mu.Unlock()
+
+			close(c.done)
+		}()
+	}
+
+	m.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.cert, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *Manager) obtainAndStore(ctx context.Context, name string) (*tls.Certificate, error) {
+	res, err := m.getObtainer().Obtain(certificate.ObtainRequest{
+		Domains: []string{name},
+		Bundle:  m.Bundle,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("legotls: obtain certificate for %s: %w", name, err)
+	}
+
+	data := slices.Concat(res.PrivateKey, res.Certificate)
+
+	cert, err := parseCert(data)
+	if err != nil {
+		return nil, fmt.Errorf("legotls: %s: %w", name, err)
+	}
+
+	if m.Cache != nil {
+		err = m.Cache.Put(ctx, cacheKey(name), data)
+		if err != nil {
+			log.Warn("legotls: could not store the certificate in the cache.", "domain", name, "error", err)
+		}
+	}
+
+	m.store(name, cert)
+
+	return cert, nil
+}
+
+// renewIfNeeded starts the renewal of a certificate, in background, if it expires soon.
+func (m *Manager) renewIfNeeded(name string, cert *tls.Certificate) {
+	renewBefore := m.RenewBefore
+	if renewBefore <= 0 {
+		renewBefore = DefaultRenewBefore
+	}
+
+	if time.Until(cert.Leaf.NotAfter) > renewBefore {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.renewing[name]; ok {
+		return
+	}
+
+	if f, ok := m.failures[name]; ok && time.Now().Before(f.until) {
+		return
+	}
+
+	if m.renewing == nil {
+		m.renewing = make(map[string]struct{})
+	}
+
+	m.renewing[name] = struct{}{}
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			delete(m.renewing, name)
+			m.mu.Unlock()
+		}()
+
+		_, err := m.obtain(context.Background(), name)
+		if err != nil {
+			log.Warn("legotls: could not renew the certificate.", "domain", name, "error", err)
+		}
+	}()
+}
+
+// recordResult records the result of an obtain, the caller must hold m.mu.
+func (m *Manager) recordResult(name string, err error) {
+	if err == nil {
+		delete(m.failures, name)
+		return
+	}
+
+	if m.failures == nil {
+		m.failures = make(map[string]*failure)
+	}
+
+	f, ok := m.failures[name]
+	if !ok {
+		f = &failure{}
+		m.failures[name] = f
+	}
+
+	backoff := m.FailureBackoff
+	if backoff <= 0 {
+		backoff = DefaultFailureBackoff
+	}
+
+	for range f.count {
+		backoff *= 2
+		if backoff >= maxFailureBackoff {
+			backoff = maxFailureBackoff
+			break
+		}
+	}
+
+	f.err = err
+	f.count++
+	f.until = time.Now().Add(backoff)
+}
+
+func (m *Manager) store(name string, cert *tls.Certificate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.certs == nil {
+		m.certs = make(map[string]*tls.Certificate)
+	}
+
+	m.certs[name] = cert
+}
+
+func (m *Manager) getObtainer() obtainer {
+	if m.obtainer != nil {
+		return m.obtainer
+	}
+
+	return m.Client.Certificate
+}
+
+// cacheKey returns the key of a certificate in the Cache.
+func cacheKey(name string) string {
+	return strings.ReplaceAll(name, "*", "_") + ".pem"
+}
+
+// parseCert parses a cache entry: the PEM encoded private key followed by the PEM encoded certificate chain.
+func parseCert(data []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
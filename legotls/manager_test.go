@@ -0,0 +1,365 @@
+package legotls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/challenge/http01"
+	"github.com/digicert/lego/v4/challenge/tlsalpn01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeObtainer struct {
+	validity time.Duration
+
+	mu       sync.Mutex
+	requests []certificate.ObtainRequest
+	calls    atomic.Int32
+}
+
+func (f *fakeObtainer) Obtain(request certificate.ObtainRequest) (*certificate.Resource, error) {
+	f.calls.Add(1)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, request)
+	f.mu.Unlock()
+
+	validity := f.validity
+	if validity == 0 {
+		validity = 90 * 24 * time.Hour
+	}
+
+	key, cert, err := generateCert(request.Domains[0], validity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificate.Resource{
+		Domain:      request.Domains[0],
+		PrivateKey:  key,
+		Certificate: cert,
+	}, nil
+}
+
+func (f *fakeObtainer) domains() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var domains []string
+	for _, r := range f.requests {
+		domains = append(domains, r.Domains...)
+	}
+
+	return domains
+}
+
+func generateCert(domain string, validity time.Duration) (keyPEM, certPEM []byte, err error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validity),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func hello(t *testing.T, serverName string, protos ...string) *tls.ClientHelloInfo {
+	t.Helper()
+
+	return &tls.ClientHelloInfo{ServerName: serverName, SupportedProtos: protos}
+}
+
+func TestManager_GetCertificate(t *testing.T) {
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		Cache:      DirCache(t.TempDir()),
+		obtainer:   obtainer,
+	}
+
+	cert, err := m.GetCertificate(hello(t, "Example.com."))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, cert.Leaf.DNSNames)
+
+	again, err := m.GetCertificate(hello(t, "example.com"))
+	require.NoError(t, err)
+
+	assert.Same(t, cert, again)
+	assert.EqualValues(t, 1, obtainer.calls.Load())
+}
+
+func TestManager_GetCertificate_concurrent(t *testing.T) {
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		obtainer:   obtainer,
+	}
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			_, err := m.GetCertificate(hello(t, "example.com"))
+			assert.NoError(t, err)
+		})
+	}
+
+	wg.Wait()
+
+	assert.EqualValues(t, 1, obtainer.calls.Load())
+}
+
+func TestManager_GetCertificate_cache(t *testing.T) {
+	cache := DirCache(t.TempDir())
+
+	key, cert, err := generateCert("example.com", 90*24*time.Hour)
+	require.NoError(t, err)
+
+	err = cache.Put(t.Context(), "example.com.pem", append(key, cert...))
+	require.NoError(t, err)
+
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		Cache:      cache,
+		obtainer:   obtainer,
+	}
+
+	tlsCert, err := m.GetCertificate(hello(t, "example.com"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, tlsCert.Leaf.DNSNames)
+	assert.Zero(t, obtainer.calls.Load())
+}
+
+func TestManager_GetCertificate_hostPolicy(t *testing.T) {
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		obtainer:   obtainer,
+	}
+
+	_, err := m.GetCertificate(hello(t, "example.org"))
+	require.EqualError(t, err, `legotls: host "example.org" not configured in HostWhitelist`)
+
+	_, err = m.GetCertificate(hello(t, "example.com"))
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, obtainer.calls.Load())
+}
+
+func TestManager_GetCertificate_noHostPolicy(t *testing.T) {
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{obtainer: obtainer}
+
+	_, err := m.GetCertificate(hello(t, "example.com"))
+	require.EqualError(t, err, `legotls: host "example.com" denied: no HostPolicy`)
+
+	assert.Zero(t, obtainer.calls.Load())
+}
+
+func TestManager_GetCertificate_wildcard(t *testing.T) {
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("a.example.com", "b.example.com", "example.com", "a.b.example.com"),
+		Cache:      DirCache(t.TempDir()),
+		Wildcards:  []string{"*.example.com"},
+		obtainer:   obtainer,
+	}
+
+	testCases := []string{"a.example.com", "b.example.com", "example.com", "a.b.example.com"}
+
+	for _, serverName := range testCases {
+		_, err := m.GetCertificate(hello(t, serverName))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"*.example.com", "example.com", "a.b.example.com"}, obtainer.domains())
+}
+
+func TestManager_GetCertificate_renew(t *testing.T) {
+	obtainer := &fakeObtainer{validity: 24 * time.Hour}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		obtainer:   obtainer,
+	}
+
+	_, err := m.GetCertificate(hello(t, "example.com"))
+	require.NoError(t, err)
+
+	_, err = m.GetCertificate(hello(t, "example.com"))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return obtainer.calls.Load() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_GetCertificate_error(t *testing.T) {
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		obtainer: obtainerFunc(func(certificate.ObtainRequest) (*certificate.Resource, error) {
+			return nil, errors.New("oops")
+		}),
+	}
+
+	_, err := m.GetCertificate(hello(t, "example.com"))
+	require.EqualError(t, err, "legotls: obtain certificate for example.com: oops")
+
+	_, err = m.GetCertificate(hello(t, ""))
+	require.EqualError(t, err, "legotls: missing server name")
+}
+
+func TestManager_GetCertificate_failureBackoff(t *testing.T) {
+	var calls atomic.Int32
+
+	m := &Manager{
+		HostPolicy:     HostWhitelist("example.com"),
+		FailureBackoff: 50 * time.Millisecond,
+		obtainer: obtainerFunc(func(certificate.ObtainRequest) (*certificate.Resource, error) {
+			calls.Add(1)
+
+			return nil, errors.New("oops")
+		}),
+	}
+
+	_, err := m.GetCertificate(hello(t, "example.com"))
+	require.EqualError(t, err, "legotls: obtain certificate for example.com: oops")
+
+	// During the backoff, the previous error is returned without a new obtain.
+	_, err = m.GetCertificate(hello(t, "example.com"))
+	require.ErrorContains(t, err, "legotls: obtain certificate for example.com: oops (next attempt after ")
+
+	assert.EqualValues(t, 1, calls.Load())
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = m.GetCertificate(hello(t, "example.com"))
+	require.EqualError(t, err, "legotls: obtain certificate for example.com: oops")
+
+	assert.EqualValues(t, 2, calls.Load())
+
+	// The backoff is doubled after the second consecutive failure.
+	m.mu.Lock()
+	until := m.failures["example.com"].until
+	m.mu.Unlock()
+
+	assert.WithinDuration(t, time.Now().Add(100*time.Millisecond), until, 50*time.Millisecond)
+}
+
+func TestManager_GetCertificate_failureBackoff_reset(t *testing.T) {
+	obtainer := &fakeObtainer{}
+
+	m := &Manager{
+		HostPolicy: HostWhitelist("example.com"),
+		obtainer:   obtainer,
+	}
+
+	m.mu.Lock()
+	m.recordResult("example.com", errors.New("oops"))
+	m.failures["example.com"].until = time.Now()
+	m.mu.Unlock()
+
+	_, err := m.GetCertificate(hello(t, "example.com"))
+	require.NoError(t, err)
+
+	assert.Empty(t, m.failures)
+}
+
+func TestManager_TLSALPN01Provider(t *testing.T) {
+	m := &Manager{obtainer: &fakeObtainer{}}
+
+	provider := m.TLSALPN01Provider()
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	cert, err := m.GetCertificate(hello(t, "example.com", tlsalpn01.ACMETLS1Protocol))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, cert.Leaf.DNSNames)
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	_, err = m.GetCertificate(hello(t, "example.com", tlsalpn01.ACMETLS1Protocol))
+	require.EqualError(t, err, `legotls: no TLS-ALPN-01 challenge for "example.com"`)
+}
+
+func TestManager_HTTPHandler(t *testing.T) {
+	m := &Manager{}
+
+	provider := m.HTTP01Provider()
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	handler := m.HTTPHandler(nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com"+http01.ChallengePath("token"), nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "keyAuth", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com:80/foo?bar=1", nil))
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://example.com/foo?bar=1", rec.Header().Get("Location"))
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com"+http01.ChallengePath("token"), nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+type obtainerFunc func(request certificate.ObtainRequest) (*certificate.Resource, error)
+
+func (f obtainerFunc) Obtain(request certificate.ObtainRequest) (*certificate.Resource, error) {
+	return f(request)
+}
@@ -0,0 +1,122 @@
+package legotls
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/http01"
+	"github.com/digicert/lego/v4/challenge/tlsalpn01"
+)
+
+var (
+	_ challenge.Provider = (*tlsALPN01Provider)(nil)
+	_ challenge.Provider = (*http01Provider)(nil)
+)
+
+// TLSALPN01Provider returns a TLS-ALPN-01 provider serving the challenge certificates with GetCertificate.
+// It must be set on the client (Challenge.SetTLSALPN01Provider).
+func (m *Manager) TLSALPN01Provider() challenge.Provider {
+	return &tlsALPN01Provider{manager: m}
+}
+
+// HTTP01Provider returns an HTTP-01 provider serving the challenge tokens with HTTPHandler.
+// It must be set on the client (Challenge.SetHTTP01Provider).
+func (m *Manager) HTTP01Provider() challenge.Provider {
+	return &http01Provider{manager: m}
+}
+
+// HTTPHandler returns a handler serving the HTTP-01 challenge tokens.
+// The other requests are handled by fallback,
+// if fallback is nil, the requests are redirected to HTTPS.
+func (m *Manager) HTTPHandler(fallback http.Handler) http.Handler {
+	if fallback == nil {
+		fallback = http.HandlerFunc(redirectHTTPS)
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, http01.ChallengePath("")) {
+			fallback.ServeHTTP(rw, req)
+			return
+		}
+
+		m.challengesMu.RLock()
+		keyAuth, ok := m.http[req.URL.Path]
+		m.challengesMu.RUnlock()
+
+		if !ok {
+			http.NotFound(rw, req)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "text/plain")
+		_, _ = rw.Write([]byte(keyAuth))
+	})
+}
+
+type tlsALPN01Provider struct {
+	manager *Manager
+}
+
+func (p *tlsALPN01Provider) Present(domain, _, keyAuth string) error {
+	cert, err := tlsalpn01.ChallengeCert(domain, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	p.manager.challengesMu.Lock()
+	defer p.manager.challengesMu.Unlock()
+
+	if p.manager.tlsALPN == nil {
+		p.manager.tlsALPN = make(map[string]*tls.Certificate)
+	}
+
+	p.manager.tlsALPN[domain] = cert
+
+	return nil
+}
+
+func (p *tlsALPN01Provider) CleanUp(domain, _, _ string) error {
+	p.manager.challengesMu.Lock()
+	delete(p.manager.tlsALPN, domain)
+	p.manager.challengesMu.Unlock()
+
+	return nil
+}
+
+type http01Provider struct {
+	manager *Manager
+}
+
+func (p *http01Provider) Present(_, token, keyAuth string) error {
+	p.manager.challengesMu.Lock()
+	defer p.manager.challengesMu.Unlock()
+
+	if p.manager.http == nil {
+		p.manager.http = make(map[string]string)
+	}
+
+	p.manager.http[http01.ChallengePath(token)] = keyAuth
+
+	return nil
+}
+
+func (p *http01Provider) CleanUp(_, token, _ string) error {
+	p.manager.challengesMu.Lock()
+	delete(p.manager.http, http01.ChallengePath(token))
+	p.manager.challengesMu.Unlock()
+
+	return nil
+}
+
+func redirectHTTPS(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(rw, "Use HTTPS", http.StatusBadRequest)
+		return
+	}
+
+	host, _, _ := strings.Cut(req.Host, ":")
+
+	http.Redirect(rw, req, "https://"+host+req.URL.RequestURI(), http.StatusFound)
+}
@@ -1,14 +1,40 @@
+// Package log provides the logger used by lego, based on log/slog.
 package log
 
 import (
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var (
+	defaultLogger atomic.Pointer[slog.Logger]
+
+	stderrLogger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	exitHooksMu sync.Mutex
+	exitHooks   []func()
 )
 
 // Logger is an optional custom logger.
-var Logger StdLogger = log.New(os.Stderr, "", log.LstdFlags)
+// When it's replaced, the log entries are written, in the text format, through its Print method.
+//
+// Deprecated: use SetDefault instead. Logger will be removed in v5.
+var Logger StdLogger = defaultStdLogger{}
 
 // StdLogger interface for Standard Logger.
+//
+// Deprecated: use SetDefault with a *slog.Logger instead. StdLogger will be removed in v5.
 type StdLogger interface {
 	Fatal(args ...any)
 	Fatalln(args ...any)
@@ -18,42 +44,190 @@ type StdLogger interface {
 	Printf(format string, args ...any)
 }
 
-// Fatal writes a log entry.
-// It uses Logger if not nil, otherwise it uses the default log.Logger.
+// Default returns the default logger (text format on stderr, unless replaced with SetDefault).
+func Default() *slog.Logger {
+	if _, ok := Logger.(defaultStdLogger); !ok && Logger != nil {
+		return slog.New(slog.NewTextHandler(stdWriter{logger: Logger}, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+	}
+
+	if logger := defaultLogger.Load(); logger != nil {
+		return logger
+	}
+
+	return stderrLogger
+}
+
+// SetDefault replaces the default logger.
+func SetDefault(logger *slog.Logger) {
+	if logger == nil {
+		return
+	}
+
+	defaultLogger.Store(logger)
+}
+
+// OrDefault returns the logger, or the default logger if the logger is nil.
+// It's used by the components accepting an optional logger (ex: the Logger field of the configuration of a DNS provider).
+func OrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return Default()
+	}
+
+	return logger
+}
+
+// New creates a logger writing to w, using a format (text or JSON) and a minimum level.
+func New(w io.Writer, format string, level slog.Leveler) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("log: unsupported format: %q", format)
+	}
+}
+
+// ParseLevel parses a level name (debug, info, warn, error).
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+
+	err := level.UnmarshalText([]byte(value))
+	if err != nil {
+		return 0, fmt.Errorf("log: %w", err)
+	}
+
+	return level, nil
+}
+
+// AtExit registers a function called by Fatal and Fatalf before exiting.
+func AtExit(fn func()) {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+
+	exitHooks = append(exitHooks, fn)
+}
+
+func exit() {
+	exitHooksMu.Lock()
+	hooks := exitHooks
+	exitHooksMu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+
+	os.Exit(1)
+}
+
+// Debug writes a log entry at the debug level.
+func Debug(msg string, args ...any) {
+	Default().Debug(msg, args...)
+}
+
+// Info writes a log entry at the info level.
+func Info(msg string, args ...any) {
+	Default().Info(msg, args...)
+}
+
+// Warn writes a log entry at the warning level.
+func Warn(msg string, args ...any) {
+	Default().Warn(msg, args...)
+}
+
+// Error writes a log entry at the error level.
+func Error(msg string, args ...any) {
+	Default().Error(msg, args...)
+}
+
+// Fatal writes a log entry at the error level, then exits.
 func Fatal(args ...any) {
-	Logger.Fatal(args...)
+	Default().Error(fmt.Sprint(args...))
+	exit()
 }
 
-// Fatalf writes a log entry.
-// It uses Logger if not nil, otherwise it uses the default log.Logger.
+// Fatalf writes a log entry at the error level, then exits.
 func Fatalf(format string, args ...any) {
-	Logger.Fatalf(format, args...)
+	Default().Error(fmt.Sprintf(format, args...))
+	exit()
 }
 
-// Print writes a log entry.
-// It uses Logger if not nil, otherwise it uses the default log.Logger.
+// Print writes a log entry at the info level.
 func Print(args ...any) {
-	Logger.Print(args...)
+	Default().Info(fmt.Sprint(args...))
 }
 
-// Println writes a log entry.
-// It uses Logger if not nil, otherwise it uses the default log.Logger.
+// Println writes a log entry at the info level.
 func Println(args ...any) {
-	Logger.Println(args...)
+	Default().Info(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
 }
 
-// Printf writes a log entry.
-// It uses Logger if not nil, otherwise it uses the default log.Logger.
+// Printf writes a log entry at the info level.
 func Printf(format string, args ...any) {
-	Logger.Printf(format, args...)
+	Default().Info(fmt.Sprintf(format, args...))
+}
+
+// Infof writes a log entry at the info level.
+func Infof(format string, args ...any) {
+	Default().Info(fmt.Sprintf(format, args...))
 }
 
-// Warnf writes a log entry.
+// Warnf writes a log entry at the warning level.
 func Warnf(format string, args ...any) {
-	Printf("[WARN] "+format, args...)
+	Default().Warn(fmt.Sprintf(format, args...))
 }
 
-// Infof writes a log entry.
-func Infof(format string, args ...any) {
-	Printf("[INFO] "+format, args...)
+// Debugf writes a log entry at the debug level.
+func Debugf(format string, args ...any) {
+	Default().Debug(fmt.Sprintf(format, args...))
+}
+
+// defaultStdLogger the default value of Logger: it writes the log entries with the default logger.
+type defaultStdLogger struct{}
+
+func (defaultStdLogger) Fatal(args ...any) {
+	Fatal(args...)
+}
+
+func (defaultStdLogger) Fatalln(args ...any) {
+	Default().Error(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	exit()
+}
+
+func (defaultStdLogger) Fatalf(format string, args ...any) {
+	Fatalf(format, args...)
+}
+
+func (defaultStdLogger) Print(args ...any) {
+	Print(args...)
+}
+
+func (defaultStdLogger) Println(args ...any) {
+	Println(args...)
+}
+
+func (defaultStdLogger) Printf(format string, args ...any) {
+	Printf(format, args...)
+}
+
+// stdWriter writes the log entries formatted by a slog.Handler through a StdLogger.
+type stdWriter struct {
+	logger StdLogger
+}
+
+func (w stdWriter) Write(p []byte) (int, error) {
+	w.logger.Print(strings.TrimSuffix(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// removeTime removes the time of the log entries: the StdLogger adds its own.
+func removeTime(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && attr.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+
+	return attr
 }
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStdLogger struct {
+	entries []string
+}
+
+func (f *fakeStdLogger) Fatal(args ...any) {}
+
+func (f *fakeStdLogger) Fatalln(args ...any) {}
+
+func (f *fakeStdLogger) Fatalf(format string, args ...any) {}
+
+func (f *fakeStdLogger) Print(args ...any) {
+	f.entries = append(f.entries, fmt.Sprint(args...))
+}
+
+func (f *fakeStdLogger) Println(args ...any) {}
+
+func (f *fakeStdLogger) Printf(format string, args ...any) {}
+
+func TestLogger_deprecated(t *testing.T) {
+	logger := &fakeStdLogger{}
+
+	previous := Logger
+	Logger = logger
+
+	t.Cleanup(func() { Logger = previous })
+
+	Infof("obtain %s", "example.com")
+	Warn("no renewal", "domain", "example.com")
+	Debug("ignored")
+
+	expected := []string{
+		`level=INFO msg="obtain example.com"`,
+		`level=WARN msg="no renewal" domain=example.com`,
+	}
+
+	assert.Equal(t, expected, logger.entries)
+}
@@ -0,0 +1,139 @@
+// Package metrics provides the counters and histograms recorded by lego.
+//
+// By default, the metrics are discarded.
+// A [Sink] (ex: the Prometheus adapter from the metrics/prometheus package) can be set with [SetDefault].
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+)
+
+// Metric names.
+const (
+	// Issuances counts the certificate requests (labels: "result").
+	Issuances = "lego_certificate_issuances_total"
+
+	// ACMEErrors counts the errors returned by the ACME server (labels: "type").
+	ACMEErrors = "lego_acme_errors_total"
+
+	// PropagationDuration observes the time spent waiting for the DNS propagation, in seconds (labels: "result").
+	PropagationDuration = "lego_dns_propagation_duration_seconds"
+
+	// ChallengeDuration observes the time spent to solve a challenge, in seconds (labels: "type", "result").
+	ChallengeDuration = "lego_challenge_duration_seconds"
+
+	// ProviderRequestDuration observes the latency of the DNS provider API calls, in seconds (labels: "host", "method", "code").
+	ProviderRequestDuration = "lego_dns_provider_request_duration_seconds"
+)
+
+// Result label values.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Labels are the dimensions of a metric.
+type Labels map[string]string
+
+// Sink receives the metrics.
+// The implementations must be safe for concurrent use.
+type Sink interface {
+	// IncCounter increments a counter.
+	IncCounter(name string, labels Labels)
+
+	// ObserveHistogram adds an observation to a histogram.
+	ObserveHistogram(name string, value float64, labels Labels)
+}
+
+type sinkHolder struct {
+	Sink
+}
+
+var defaultSink atomic.Pointer[sinkHolder]
+
+// Default returns the default sink (a no-op sink, unless replaced with SetDefault).
+func Default() Sink {
+	if h := defaultSink.Load(); h != nil {
+		return h.Sink
+	}
+
+	return Noop{}
+}
+
+// SetDefault replaces the default sink.
+// A nil sink restores the no-op sink.
+func SetDefault(sink Sink) {
+	if sink == nil {
+		defaultSink.Store(nil)
+		return
+	}
+
+	defaultSink.Store(&sinkHolder{Sink: sink})
+}
+
+// Enabled reports whether a sink has been set.
+func Enabled() bool {
+	return defaultSink.Load() != nil
+}
+
+// IncCounter increments a counter of the default sink.
+func IncCounter(name string, labels Labels) {
+	Default().IncCounter(name, labels)
+}
+
+// ObserveHistogram adds an observation to a histogram of the default sink.
+func ObserveHistogram(name string, value float64, labels Labels) {
+	Default().ObserveHistogram(name, value, labels)
+}
+
+// ObserveDuration adds the time elapsed since start, in seconds, to a histogram of the default sink.
+func ObserveDuration(name string, start time.Time, labels Labels) {
+	Default().ObserveHistogram(name, time.Since(start).Seconds(), labels)
+}
+
+// Result returns the value of the "result" label for an error.
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+
+	return ResultSuccess
+}
+
+// RecordIssuance records the result of a certificate request.
+// When the request failed because of the ACME server, the error type is also recorded.
+func RecordIssuance(err error) {
+	IncCounter(Issuances, Labels{"result": Result(err)})
+
+	if err == nil {
+		return
+	}
+
+	var problem *acme.ProblemDetails
+	if errors.As(err, &problem) {
+		IncCounter(ACMEErrors, Labels{"type": ErrorType(problem)})
+	}
+}
+
+// ErrorType returns the short form of an ACME error type (ex: "rateLimited").
+func ErrorType(problem *acme.ProblemDetails) string {
+	if problem == nil || problem.Type == "" {
+		return "unknown"
+	}
+
+	return strings.TrimPrefix(problem.Type, "urn:ietf:params:acme:error:")
+}
+
+// Noop is a sink that discards all the metrics.
+type Noop struct{}
+
+// IncCounter implements [Sink].
+func (Noop) IncCounter(string, Labels) {}
+
+// ObserveHistogram implements [Sink].
+func (Noop) ObserveHistogram(string, float64, Labels) {}
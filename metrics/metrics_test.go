@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Name   string
+	Value  float64
+	Labels Labels
+}
+
+type recorder struct {
+	mu      sync.Mutex
+	records []record
+}
+
+func (r *recorder) IncCounter(name string, labels Labels) {
+	r.ObserveHistogram(name, 1, labels)
+}
+
+func (r *recorder) ObserveHistogram(name string, value float64, labels Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record{Name: name, Value: value, Labels: labels})
+}
+
+func setupRecorder(t *testing.T) *recorder {
+	t.Helper()
+
+	rec := &recorder{}
+
+	SetDefault(rec)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	return rec
+}
+
+func TestDefault(t *testing.T) {
+	assert.False(t, Enabled())
+	assert.Equal(t, Noop{}, Default())
+
+	rec := setupRecorder(t)
+
+	assert.True(t, Enabled())
+	assert.Same(t, rec, Default())
+}
+
+func TestRecordIssuance(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected []record
+	}{
+		{
+			desc: "success",
+			expected: []record{
+				{Name: Issuances, Value: 1, Labels: Labels{"result": ResultSuccess}},
+			},
+		},
+		{
+			desc: "other error",
+			err:  errors.New("boom"),
+			expected: []record{
+				{Name: Issuances, Value: 1, Labels: Labels{"result": ResultFailure}},
+			},
+		},
+		{
+			desc: "ACME error",
+			err:  fmt.Errorf("example.com: %w", &acme.ProblemDetails{Type: acme.RateLimitedErr}),
+			expected: []record{
+				{Name: Issuances, Value: 1, Labels: Labels{"result": ResultFailure}},
+				{Name: ACMEErrors, Value: 1, Labels: Labels{"type": "rateLimited"}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			rec := setupRecorder(t)
+
+			RecordIssuance(test.err)
+
+			assert.Equal(t, test.expected, rec.records)
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	rec := setupRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	require.Len(t, rec.records, 1)
+
+	assert.Equal(t, ProviderRequestDuration, rec.records[0].Name)
+	assert.Equal(t, Labels{"host": "127.0.0.1", "method": http.MethodGet, "code": "418"}, rec.records[0].Labels)
+}
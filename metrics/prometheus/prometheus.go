@@ -0,0 +1,256 @@
+// Package prometheus implements a metrics sink using the Prometheus text exposition format.
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/digicert/lego/v4/metrics"
+)
+
+// DefaultBuckets are the default histogram buckets, in seconds.
+// They cover the provider API latencies, and the DNS propagation durations.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+var descriptions = map[string]string{
+	metrics.Issuances:               "Number of certificate requests.",
+	metrics.ACMEErrors:              "Number of errors returned by the ACME server, by type.",
+	metrics.PropagationDuration:     "Time spent waiting for the DNS propagation.",
+	metrics.ChallengeDuration:       "Time spent to solve a challenge.",
+	metrics.ProviderRequestDuration: "Latency of the DNS provider API calls.",
+}
+
+var _ metrics.Sink = (*Sink)(nil)
+
+// Sink stores the metrics in memory, and exposes them using the Prometheus text format.
+type Sink struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	counters   map[string]map[string]*counter
+	histograms map[string]map[string]*histogram
+}
+
+// New creates a new Sink.
+// If no buckets are provided, DefaultBuckets is used.
+func New(buckets ...float64) *Sink {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &Sink{
+		buckets:    buckets,
+		counters:   make(map[string]map[string]*counter),
+		histograms: make(map[string]map[string]*histogram),
+	}
+}
+
+// IncCounter implements [metrics.Sink].
+func (s *Sink) IncCounter(name string, labels metrics.Labels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.counters[name]
+	if !ok {
+		series = make(map[string]*counter)
+		s.counters[name] = series
+	}
+
+	key := formatLabels(labels)
+
+	c, ok := series[key]
+	if !ok {
+		c = &counter{}
+		series[key] = c
+	}
+
+	c.value++
+}
+
+// ObserveHistogram implements [metrics.Sink].
+func (s *Sink) ObserveHistogram(name string, value float64, labels metrics.Labels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, ok := s.histograms[name]
+	if !ok {
+		series = make(map[string]*histogram)
+		s.histograms[name] = series
+	}
+
+	key := formatLabels(labels)
+
+	h, ok := series[key]
+	if !ok {
+		h = &histogram{labels: labels, counts: make([]uint64, len(s.buckets))}
+		series[key] = h
+	}
+
+	for i, upper := range s.buckets {
+		if value <= upper {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += value
+}
+
+// WriteTo writes the metrics using the Prometheus text format.
+func (s *Sink) WriteTo(w io.Writer) (int64, error) {
+	buf := new(bytes.Buffer)
+
+	s.mu.Lock()
+
+	for _, name := range sortedKeys(s.counters) {
+		writeHeader(buf, name, "counter")
+
+		series := s.counters[name]
+		for _, key := range sortedKeys(series) {
+			_, _ = fmt.Fprintf(buf, "%s%s %s\n", name, key, formatFloat(series[key].value))
+		}
+	}
+
+	for _, name := range sortedKeys(s.histograms) {
+		writeHeader(buf, name, "histogram")
+
+		series := s.histograms[name]
+		for _, key := range sortedKeys(series) {
+			h := series[key]
+
+			for i, upper := range s.buckets {
+				_, _ = fmt.Fprintf(buf, "%s_bucket%s %d\n", name, withLabel(h.labels, "le", formatFloat(upper)), h.counts[i])
+			}
+
+			_, _ = fmt.Fprintf(buf, "%s_bucket%s %d\n", name, withLabel(h.labels, "le", "+Inf"), h.count)
+			_, _ = fmt.Fprintf(buf, "%s_sum%s %s\n", name, key, formatFloat(h.sum))
+			_, _ = fmt.Fprintf(buf, "%s_count%s %d\n", name, key, h.count)
+		}
+	}
+
+	s.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// WriteFile writes the metrics to a file (ex: for the textfile collector of the node exporter).
+// The file is replaced atomically.
+func (s *Sink) WriteFile(filename string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("prometheus: create temporary file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = s.WriteTo(tmp)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("prometheus: write metrics: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("prometheus: close temporary file: %w", err)
+	}
+
+	err = os.Chmod(tmp.Name(), 0o644)
+	if err != nil {
+		return fmt.Errorf("prometheus: chmod: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), filename)
+	if err != nil {
+		return fmt.Errorf("prometheus: rename: %w", err)
+	}
+
+	return nil
+}
+
+// Handler returns an HTTP handler exposing the metrics.
+func (s *Sink) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		_, _ = s.WriteTo(rw)
+	})
+}
+
+type counter struct {
+	value float64
+}
+
+type histogram struct {
+	labels metrics.Labels
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func writeHeader(w io.Writer, name, kind string) {
+	if desc, ok := descriptions[name]; ok {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n", name, desc)
+	}
+
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func withLabel(labels metrics.Labels, key, value string) string {
+	all := make(metrics.Labels, len(labels)+1)
+	for k, v := range labels {
+		all[k] = v
+	}
+
+	all[key] = value
+
+	return formatLabels(all)
+}
+
+func formatLabels(labels metrics.Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, key := range sortedKeys(labels) {
+		parts = append(parts, key+`="`+labelValueEscaper.Replace(labels[key])+`"`)
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
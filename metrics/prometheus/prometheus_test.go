@@ -0,0 +1,90 @@
+package prometheus
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/digicert/lego/v4/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const expectedOutput = `# HELP lego_certificate_issuances_total Number of certificate requests.
+# TYPE lego_certificate_issuances_total counter
+lego_certificate_issuances_total{result="failure"} 1
+lego_certificate_issuances_total{result="success"} 2
+# HELP lego_dns_propagation_duration_seconds Time spent waiting for the DNS propagation.
+# TYPE lego_dns_propagation_duration_seconds histogram
+lego_dns_propagation_duration_seconds_bucket{le="1",result="success"} 1
+lego_dns_propagation_duration_seconds_bucket{le="10",result="success"} 2
+lego_dns_propagation_duration_seconds_bucket{le="+Inf",result="success"} 3
+lego_dns_propagation_duration_seconds_sum{result="success"} 65.5
+lego_dns_propagation_duration_seconds_count{result="success"} 3
+`
+
+func newTestSink() *Sink {
+	sink := New(10, 1)
+
+	sink.IncCounter(metrics.Issuances, metrics.Labels{"result": metrics.ResultSuccess})
+	sink.IncCounter(metrics.Issuances, metrics.Labels{"result": metrics.ResultFailure})
+	sink.IncCounter(metrics.Issuances, metrics.Labels{"result": metrics.ResultSuccess})
+
+	sink.ObserveHistogram(metrics.PropagationDuration, 0.5, metrics.Labels{"result": metrics.ResultSuccess})
+	sink.ObserveHistogram(metrics.PropagationDuration, 5, metrics.Labels{"result": metrics.ResultSuccess})
+	sink.ObserveHistogram(metrics.PropagationDuration, 60, metrics.Labels{"result": metrics.ResultSuccess})
+
+	return sink
+}
+
+func TestSink_WriteTo(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	_, err := newTestSink().WriteTo(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedOutput, buf.String())
+}
+
+func TestSink_WriteTo_escape(t *testing.T) {
+	sink := New()
+
+	sink.IncCounter("test_total", metrics.Labels{"value": "a\"b\\c\nd"})
+
+	buf := new(bytes.Buffer)
+
+	_, err := sink.WriteTo(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "# TYPE test_total counter\ntest_total{value=\"a\\\"b\\\\c\\nd\"} 1\n", buf.String())
+}
+
+func TestSink_WriteFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "lego.prom")
+
+	err := newTestSink().WriteFile(filename)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedOutput, string(data))
+
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 1)
+}
+
+func TestSink_Handler(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newTestSink().Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, expectedOutput, rec.Body.String())
+}
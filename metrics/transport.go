@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Transport is an HTTP transport recording the latency of the requests (ProviderRequestDuration).
+type Transport struct {
+	rt http.RoundTripper
+}
+
+// NewTransport creates a new Transport.
+// If rt is nil, http.DefaultTransport is used.
+func NewTransport(rt http.RoundTripper) *Transport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &Transport{rt: rt}
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := t.rt.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	ObserveDuration(ProviderRequestDuration, start, Labels{"host": req.URL.Hostname(), "method": req.Method, "code": code})
+
+	return resp, err
+}
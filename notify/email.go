@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailConfig is the configuration of the Email notifier.
+type EmailConfig struct {
+	// Addr the address of the SMTP server (host:port).
+	Addr string
+
+	// Username and Password are used for the PLAIN authentication, if Username is not empty.
+	Username string
+	Password string
+
+	From string
+	To   []string
+}
+
+// Email sends the notifications by email.
+type Email struct {
+	config EmailConfig
+	host   string
+
+	// sendMail is the function used to send the email (smtp.SendMail).
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmail creates a new Email notifier.
+func NewEmail(config EmailConfig) (*Email, error) {
+	if config.Addr == "" {
+		return nil, errors.New("notify: email: missing SMTP server address")
+	}
+
+	host, _, err := net.SplitHostPort(config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("notify: email: %w", err)
+	}
+
+	if config.From == "" {
+		return nil, errors.New("notify: email: missing sender")
+	}
+
+	if len(config.To) == 0 {
+		return nil, errors.New("notify: email: missing recipients")
+	}
+
+	return &Email{config: config, host: host, sendMail: smtp.SendMail}, nil
+}
+
+// Notify sends a notification.
+// The SMTP client doesn't support the context: only a canceled context is taken into account.
+func (e *Email) Notify(ctx context.Context, notification Notification) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("notify: email: %w", err)
+	}
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.host)
+	}
+
+	err := e.sendMail(e.config.Addr, auth, e.config.From, e.config.To, e.message(notification))
+	if err != nil {
+		return fmt.Errorf("notify: email: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Email) message(notification Notification) []byte {
+	date := notification.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	msg := new(strings.Builder)
+
+	_, _ = fmt.Fprintf(msg, "From: %s\r\n", e.config.From)
+	_, _ = fmt.Fprintf(msg, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	_, _ = fmt.Fprintf(msg, "Subject: [lego] %s: %s\r\n", notification.Type, notification.Domain)
+	_, _ = fmt.Fprintf(msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(notification.Message())
+	msg.WriteString("\r\n")
+
+	return []byte(msg.String())
+}
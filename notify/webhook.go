@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Webhook sends the notifications, as JSON, to a URL.
+type Webhook struct {
+	endpoint   *url.URL
+	httpClient *http.Client
+}
+
+// NewWebhook creates a new Webhook.
+func NewWebhook(endpoint string, client *http.Client) (*Webhook, error) {
+	u, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Webhook{endpoint: u, httpClient: client}, nil
+}
+
+// Notify sends a notification.
+func (w *Webhook) Notify(ctx context.Context, notification Notification) error {
+	return postJSON(ctx, w.httpClient, w.endpoint, notification)
+}
+
+// Slack sends the notifications to a Slack incoming webhook.
+type Slack struct {
+	endpoint   *url.URL
+	httpClient *http.Client
+}
+
+// NewSlack creates a new Slack notifier.
+func NewSlack(webhookURL string, client *http.Client) (*Slack, error) {
+	u, err := parseEndpoint(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Slack{endpoint: u, httpClient: client}, nil
+}
+
+// Notify sends a notification.
+func (s *Slack) Notify(ctx context.Context, notification Notification) error {
+	return postJSON(ctx, s.httpClient, s.endpoint, map[string]string{"text": notification.Message()})
+}
+
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	if endpoint == "" {
+		return nil, errors.New("notify: missing URL")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("notify: %w", err)
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("notify: unsupported URL scheme: %q", u.Scheme)
+	}
+
+	return u, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint *url.URL, payload any) error {
+	buf := new(bytes.Buffer)
+
+	err := json.NewEncoder(buf).Encode(payload)
+	if err != nil {
+		return fmt.Errorf("notify: failed to create request JSON body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), buf)
+	if err != nil {
+		return fmt.Errorf("notify: unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: %s %s: %w", req.Method, endpoint.Redacted(), err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+		return fmt.Errorf("notify: %s %s: unexpected status code: [status code: %d] body: %s",
+			req.Method, endpoint.Redacted(), resp.StatusCode, string(raw))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
@@ -15,19 +15,19 @@ import (
 func Get(names ...string) (map[string]string, error) {
 	values := map[string]string{}
 
-	var missingEnvVars []string
+	cfgErr := &ConfigError{}
 
 	for _, envVar := range names {
 		value := GetOrFile(envVar)
 		if value == "" {
-			missingEnvVars = append(missingEnvVars, envVar)
+			cfgErr.addMissing(envVar)
 		}
 
 		values[envVar] = value
 	}
 
-	if len(missingEnvVars) > 0 {
-		return nil, fmt.Errorf("some credentials information are missing: %s", strings.Join(missingEnvVars, ","))
+	if err := cfgErr.orNil(); err != nil {
+		return nil, err
 	}
 
 	return values, nil
@@ -59,7 +59,7 @@ func Get(names ...string) (map[string]string, error) {
 func GetWithFallback(groups ...[]string) (map[string]string, error) {
 	values := map[string]string{}
 
-	var missingEnvVars []string
+	cfgErr := &ConfigError{}
 
 	for _, names := range groups {
 		if len(names) == 0 {
@@ -68,15 +68,15 @@ func GetWithFallback(groups ...[]string) (map[string]string, error) {
 
 		value, envVar := getOneWithFallback(names[0], names[1:]...)
 		if value == "" {
-			missingEnvVars = append(missingEnvVars, envVar)
+			cfgErr.addMissing(envVar)
 			continue
 		}
 
 		values[envVar] = value
 	}
 
-	if len(missingEnvVars) > 0 {
-		return nil, fmt.Errorf("some credentials information are missing: %s", strings.Join(missingEnvVars, ","))
+	if err := cfgErr.orNil(); err != nil {
+		return nil, err
 	}
 
 	return values, nil
@@ -127,12 +127,43 @@ func GetOrDefaultInt(envVar string, defaultValue int) int {
 	return getOrDefault(envVar, defaultValue, strconv.Atoi)
 }
 
+// GetOrDefaultDuration returns the given environment variable value as a time.Duration.
+// The value is a Go duration (ex: "90s", "2m"), or a number of seconds (legacy).
+// Returns the default if the env var cannot be coopered to a duration, or is not found.
+func GetOrDefaultDuration(envVar string, defaultValue time.Duration) time.Duration {
+	return getOrDefault(envVar, defaultValue, ParseDuration)
+}
+
 // GetOrDefaultSecond returns the given environment variable value as a time.Duration (second).
 // Returns the default if the env var cannot be coopered to an int, or is not found.
+//
+// Deprecated: use GetOrDefaultDuration instead.
 func GetOrDefaultSecond(envVar string, defaultValue time.Duration) time.Duration {
 	return getOrDefault(envVar, defaultValue, ParseSecond)
 }
 
+// ClampInt returns the value inside the range [minValue, maxValue] (a maxValue of 0 means no ceiling).
+// A warning is logged, with the name of the environment variable, when the value is changed.
+// It is used to keep a value (ex: a TTL) read from an environment variable inside the limits of an API,
+// instead of letting the API reject it.
+func ClampInt(envVar string, value, minValue, maxValue int) int {
+	clamped := value
+
+	switch {
+	case value < minValue:
+		clamped = minValue
+	case maxValue > 0 && value > maxValue:
+		clamped = maxValue
+	}
+
+	if clamped != value {
+		log.Warn("The value is outside the limits, the closest limit is used.",
+			"envVar", envVar, "value", value, "used", clamped, "min", minValue, "max", maxValue)
+	}
+
+	return clamped
+}
+
 func getOrDefault[T any](envVar string, defaultValue T, fn func(string) (T, error)) T {
 	v, err := fn(GetOrFile(envVar))
 	if err != nil {
@@ -160,14 +191,35 @@ func GetOrFile(envVar string) string {
 
 	fileContents, err := os.ReadFile(fileVarValue)
 	if err != nil {
-		log.Printf("Failed to read the file %s (defined by env var %s): %s", fileVarValue, fileVar, err)
+		log.Warn("Failed to read the file.", "file", fileVarValue, "envVar", fileVar, "error", err)
 		return ""
 	}
 
 	return strings.TrimSuffix(string(fileContents), "\n")
 }
 
+// ParseDuration parses env var value (string) to a time.Duration.
+// The value is a Go duration (ex: "90s", "2m"), or a number of seconds (legacy).
+func ParseDuration(s string) (time.Duration, error) {
+	if _, err := strconv.Atoi(s); err == nil {
+		return ParseSecond(s)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if v < 0 {
+		return 0, fmt.Errorf("unsupported value: %s", v)
+	}
+
+	return v, nil
+}
+
 // ParseSecond parses env var value (string) to a second (time.Duration).
+//
+// Deprecated: use ParseDuration instead.
 func ParseSecond(s string) (time.Duration, error) {
 	v, err := strconv.Atoi(s)
 	if err != nil {
@@ -2,6 +2,7 @@ package env
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -219,6 +220,63 @@ func TestGetOrDefaultInt(t *testing.T) {
 	}
 }
 
+func TestClampInt(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    int
+		min      int
+		max      int
+		expected int
+	}{
+		{
+			desc:     "inside the limits",
+			value:    600,
+			min:      300,
+			max:      3600,
+			expected: 600,
+		},
+		{
+			desc:     "lower than the minimum",
+			value:    120,
+			min:      300,
+			max:      3600,
+			expected: 300,
+		},
+		{
+			desc:     "greater than the maximum",
+			value:    7200,
+			min:      300,
+			max:      3600,
+			expected: 3600,
+		},
+		{
+			desc:     "no maximum",
+			value:    7200,
+			min:      300,
+			expected: 7200,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			result := ClampInt("LEGO_ENV_TC", test.value, test.min, test.max)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestGetOrDefaultInt_file_clamp(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ttl")
+
+	err := os.WriteFile(file, []byte("60\n"), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("LEGO_ENV_TC_FILE", file)
+
+	result := ClampInt("LEGO_ENV_TC", GetOrDefaultInt("LEGO_ENV_TC", 600), 600, 0)
+	assert.Equal(t, 600, result)
+}
+
 func TestGetOrDefaultSecond(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -264,6 +322,75 @@ func TestGetOrDefaultSecond(t *testing.T) {
 	}
 }
 
+func TestGetOrDefaultDuration(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		envValue     string
+		defaultValue time.Duration
+		expected     time.Duration
+	}{
+		{
+			desc:         "duration",
+			envValue:     "90s",
+			defaultValue: 2 * time.Second,
+			expected:     90 * time.Second,
+		},
+		{
+			desc:         "duration with several units",
+			envValue:     "1m30s",
+			defaultValue: 2 * time.Second,
+			expected:     90 * time.Second,
+		},
+		{
+			desc:         "seconds (legacy)",
+			envValue:     "100",
+			defaultValue: 2 * time.Second,
+			expected:     100 * time.Second,
+		},
+		{
+			desc:         "empty value, use default value",
+			envValue:     "",
+			defaultValue: 2 * time.Second,
+			expected:     2 * time.Second,
+		},
+		{
+			desc:         "invalid content, use default value",
+			envValue:     "abc123",
+			defaultValue: 2 * time.Second,
+			expected:     2 * time.Second,
+		},
+		{
+			desc:         "invalid content, negative duration",
+			envValue:     "-2m",
+			defaultValue: 2 * time.Second,
+			expected:     2 * time.Second,
+		},
+		{
+			desc:         "invalid content, negative value",
+			envValue:     "-111",
+			defaultValue: 2 * time.Second,
+			expected:     2 * time.Second,
+		},
+		{
+			desc:         "float without unit: invalid type, use default value",
+			envValue:     "1.11",
+			defaultValue: 2 * time.Second,
+			expected:     2 * time.Second,
+		},
+	}
+
+	key := "LEGO_ENV_TC"
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv(key, test.envValue)
+
+			result := GetOrDefaultDuration(key, test.defaultValue)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
 func TestGetOrDefaultString(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -409,6 +536,57 @@ func TestGetOrFile_PrefersEnvVars(t *testing.T) {
 	assert.Equal(t, "lego_env", value)
 }
 
+func TestGet_file(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "secret")
+
+	err := os.WriteFile(filename, []byte("lego_file\n"), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("TEST_LEGO_ENV_VAR_A", "lego_env")
+	t.Setenv("TEST_LEGO_ENV_VAR_B", "")
+	t.Setenv("TEST_LEGO_ENV_VAR_B_FILE", filename)
+
+	values, err := Get("TEST_LEGO_ENV_VAR_A", "TEST_LEGO_ENV_VAR_B")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"TEST_LEGO_ENV_VAR_A": "lego_env",
+		"TEST_LEGO_ENV_VAR_B": "lego_file",
+	}
+
+	assert.Equal(t, expected, values)
+}
+
+func TestGet_missing(t *testing.T) {
+	t.Setenv("TEST_LEGO_ENV_VAR_A", "")
+	t.Setenv("TEST_LEGO_ENV_VAR_B", "lego_env")
+	t.Setenv("TEST_LEGO_ENV_VAR_C", "")
+
+	_, err := Get("TEST_LEGO_ENV_VAR_A", "TEST_LEGO_ENV_VAR_B", "TEST_LEGO_ENV_VAR_C")
+	require.EqualError(t, err, "some credentials information are missing: TEST_LEGO_ENV_VAR_A,TEST_LEGO_ENV_VAR_C")
+
+	var cfgErr *ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+
+	assert.Equal(t, []string{"TEST_LEGO_ENV_VAR_A", "TEST_LEGO_ENV_VAR_C"}, cfgErr.Missing)
+}
+
+func TestGetWithFallback_file(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "secret")
+
+	err := os.WriteFile(filename, []byte("lego_file"), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("TEST_LEGO_ENV_VAR_MAIN", "")
+	t.Setenv("TEST_LEGO_ENV_VAR_ALT", "")
+	t.Setenv("TEST_LEGO_ENV_VAR_ALT_FILE", filename)
+
+	values, err := GetWithFallback([]string{"TEST_LEGO_ENV_VAR_MAIN", "TEST_LEGO_ENV_VAR_ALT"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"TEST_LEGO_ENV_VAR_MAIN": "lego_file"}, values)
+}
+
 func TestParsePairs(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -0,0 +1,80 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidDefinition is returned when the definition of a configuration (struct and tags) is invalid.
+var ErrInvalidDefinition = errors.New("env: invalid configuration definition")
+
+// ConfigError reports all the problems of a configuration at once:
+// the missing environment variables, and the environment variables with an invalid value.
+type ConfigError struct {
+	// Missing the names of the missing environment variables.
+	Missing []string
+
+	// Invalid the environment variables with an invalid value.
+	Invalid []InvalidValueError
+}
+
+func (e *ConfigError) Error() string {
+	var parts []string
+
+	if len(e.Missing) > 0 {
+		parts = append(parts, "some credentials information are missing: "+strings.Join(e.Missing, ","))
+	}
+
+	if len(e.Invalid) > 0 {
+		var invalid []string
+		for _, v := range e.Invalid {
+			invalid = append(invalid, v.Error())
+		}
+
+		parts = append(parts, "some values are invalid: "+strings.Join(invalid, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// Unwrap returns the errors of the invalid values.
+func (e *ConfigError) Unwrap() []error {
+	var errs []error
+	for _, v := range e.Invalid {
+		errs = append(errs, v)
+	}
+
+	return errs
+}
+
+func (e *ConfigError) addMissing(envVar string) {
+	e.Missing = append(e.Missing, envVar)
+}
+
+func (e *ConfigError) addInvalid(envVar string, err error) {
+	e.Invalid = append(e.Invalid, InvalidValueError{EnvVar: envVar, Err: err})
+}
+
+// orNil returns the error if there is at least one problem, nil otherwise.
+func (e *ConfigError) orNil() error {
+	if len(e.Missing) == 0 && len(e.Invalid) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// InvalidValueError reports an environment variable with an invalid value.
+type InvalidValueError struct {
+	EnvVar string
+	Err    error
+}
+
+func (e InvalidValueError) Error() string {
+	return fmt.Sprintf("%s (%v)", e.EnvVar, e.Err)
+}
+
+func (e InvalidValueError) Unwrap() error {
+	return e.Err
+}
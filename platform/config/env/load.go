@@ -0,0 +1,239 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Load populates the fields of the struct pointed to by cfg from the environment variables,
+// based on the struct tags:
+//
+//	type Config struct {
+//		APIKey             string        `env:"API_KEY,required"`
+//		TTL                int           `env:"TTL" default:"120"`
+//		PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"60s"`
+//		HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
+//	}
+//
+// The name of the environment variable is the namespace followed by the name from the `env` tag.
+// The options of the `env` tag are:
+//   - required: the value must be defined, otherwise an error is returned.
+//   - nofile: the value cannot be read from a file (`_FILE` suffix).
+//
+// The `default` tag defines the value used when the environment variable is not defined.
+// A field already set before the call keeps its value in this case,
+// this allows to use a default value that is not a constant (e.g. dns01.DefaultPropagationTimeout).
+//
+// All the problems (missing or invalid values) are reported at once with a *ConfigError.
+// A problem in the definition of the configuration (ex: an unsupported type, an invalid default value)
+// is reported with an error wrapping ErrInvalidDefinition.
+//
+// The supported types are: string, bool, int, time.Duration (Go duration or seconds), []string (comma-separated),
+// and *http.Client (the value is the timeout of the client).
+//
+// Only a part of the DNS providers use Load (ex: vultr, technitium),
+// the others read their environment variables with the GetOrDefault* functions.
+// The new DNS providers should use Load.
+func Load(namespace string, cfg any) error {
+	return load(namespace, cfg, true)
+}
+
+// LoadDefaults is like Load, but only populates the fields without the `required` option,
+// and uses the default values instead of the invalid values.
+// It's intended to create the default configurations, before the credentials are set.
+// The errors are ignored: a problem in the definition of the configuration is reported by Load.
+func LoadDefaults(namespace string, cfg any) {
+	_ = load(namespace, cfg, false)
+}
+
+type fieldTag struct {
+	name     string
+	required bool
+	noFile   bool
+}
+
+func parseFieldTag(field reflect.StructField) (fieldTag, bool) {
+	raw, ok := field.Tag.Lookup("env")
+	if !ok || raw == "" || raw == "-" {
+		return fieldTag{}, false
+	}
+
+	name, options, _ := strings.Cut(raw, ",")
+
+	tag := fieldTag{name: name}
+
+	for option := range strings.SplitSeq(options, ",") {
+		switch option {
+		case "required":
+			tag.required = true
+		case "nofile":
+			tag.noFile = true
+		}
+	}
+
+	return tag, true
+}
+
+func load(namespace string, cfg any, withRequired bool) error {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: the configuration must be a pointer to a struct: %T", ErrInvalidDefinition, cfg)
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	cfgErr := &ConfigError{}
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+
+		tag, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+
+		if !field.IsExported() {
+			return fmt.Errorf("%w: the field %s.%s must be exported", ErrInvalidDefinition, rt.Name(), field.Name)
+		}
+
+		err := checkFieldType(field.Type)
+		if err != nil {
+			return fmt.Errorf("%w (field %s.%s)", err, rt.Name(), field.Name)
+		}
+
+		if tag.required && !withRequired {
+			continue
+		}
+
+		envVar := namespace + tag.name
+
+		value := lookup(envVar, tag.noFile)
+
+		if value == "" && tag.required {
+			cfgErr.addMissing(envVar)
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if value != "" {
+			err = setField(fv, value)
+			if err == nil {
+				continue
+			}
+
+			if withRequired {
+				cfgErr.addInvalid(envVar, err)
+				continue
+			}
+		}
+
+		if !fv.IsZero() {
+			continue
+		}
+
+		defaultValue, ok := field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+
+		err = setField(fv, defaultValue)
+		if err != nil {
+			return fmt.Errorf("%w: invalid default value for the field %s.%s: %w", ErrInvalidDefinition, rt.Name(), field.Name, err)
+		}
+	}
+
+	return cfgErr.orNil()
+}
+
+func lookup(envVar string, noFile bool) string {
+	if noFile {
+		return os.Getenv(envVar)
+	}
+
+	return GetOrFile(envVar)
+}
+
+func checkFieldType(t reflect.Type) error {
+	switch t {
+	case reflect.TypeFor[time.Duration](), reflect.TypeFor[*http.Client](), reflect.TypeFor[[]string]():
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64:
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported field type: %s", ErrInvalidDefinition, t)
+	}
+}
+
+func setField(fv reflect.Value, value string) error {
+	switch fv.Interface().(type) {
+	case time.Duration:
+		d, err := ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(int64(d))
+
+		return nil
+
+	case *http.Client:
+		d, err := ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		fv.Set(reflect.ValueOf(&http.Client{Timeout: d}))
+
+		return nil
+
+	case []string:
+		if value == "" {
+			return errors.New("empty value")
+		}
+
+		fv.Set(reflect.ValueOf(strings.Split(value, ",")))
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+
+		return nil
+
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		fv.SetBool(v)
+
+		return nil
+
+	case reflect.Int, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(v)
+
+		return nil
+
+	default:
+		return fmt.Errorf("%w: unsupported field type: %s", ErrInvalidDefinition, fv.Type())
+	}
+}
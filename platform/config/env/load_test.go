@@ -0,0 +1,162 @@
+package env
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loadConfig struct {
+	APIKey             string        `env:"API_KEY,required"`
+	Secret             string        `env:"SECRET,required,nofile"`
+	Endpoint           string        `env:"ENDPOINT" default:"https://example.com"`
+	Sandbox            bool          `env:"SANDBOX"`
+	TTL                int           `env:"TTL" default:"120"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"60s"`
+	Nameservers        []string      `env:"NAMESERVERS"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
+
+	ignored string
+}
+
+func TestLoad(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "key")
+
+	err := os.WriteFile(filename, []byte("lego_file\n"), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("TEST_LEGO_API_KEY_FILE", filename)
+	t.Setenv("TEST_LEGO_SECRET", "secret")
+	t.Setenv("TEST_LEGO_SANDBOX", "true")
+	t.Setenv("TEST_LEGO_TTL", "")
+	t.Setenv("TEST_LEGO_PROPAGATION_TIMEOUT", "2m")
+	t.Setenv("TEST_LEGO_NAMESERVERS", "ns1.example.com,ns2.example.com")
+	t.Setenv("TEST_LEGO_HTTP_TIMEOUT", "10")
+
+	config := &loadConfig{}
+
+	err = Load("TEST_LEGO_", config)
+	require.NoError(t, err)
+
+	expected := &loadConfig{
+		APIKey:             "lego_file",
+		Secret:             "secret",
+		Endpoint:           "https://example.com",
+		Sandbox:            true,
+		TTL:                120,
+		PropagationTimeout: 2 * time.Minute,
+		Nameservers:        []string{"ns1.example.com", "ns2.example.com"},
+		HTTPClient:         &http.Client{Timeout: 10 * time.Second},
+	}
+
+	assert.Equal(t, expected, config)
+}
+
+func TestLoad_missing(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "secret")
+
+	err := os.WriteFile(filename, []byte("secret"), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("TEST_LEGO_API_KEY", "")
+	t.Setenv("TEST_LEGO_SECRET", "")
+	t.Setenv("TEST_LEGO_SECRET_FILE", filename)
+
+	err = Load("TEST_LEGO_", &loadConfig{})
+	require.EqualError(t, err, "some credentials information are missing: TEST_LEGO_API_KEY,TEST_LEGO_SECRET")
+}
+
+func TestLoad_invalid(t *testing.T) {
+	t.Setenv("TEST_LEGO_API_KEY", "")
+	t.Setenv("TEST_LEGO_SECRET", "secret")
+	t.Setenv("TEST_LEGO_SANDBOX", "yes")
+	t.Setenv("TEST_LEGO_TTL", "abc")
+	t.Setenv("TEST_LEGO_PROPAGATION_TIMEOUT", "2 minutes")
+
+	err := Load("TEST_LEGO_", &loadConfig{})
+	require.EqualError(t, err, "some credentials information are missing: TEST_LEGO_API_KEY; "+
+		"some values are invalid: "+
+		`TEST_LEGO_SANDBOX (strconv.ParseBool: parsing "yes": invalid syntax), `+
+		`TEST_LEGO_TTL (strconv.ParseInt: parsing "abc": invalid syntax), `+
+		`TEST_LEGO_PROPAGATION_TIMEOUT (time: unknown unit " minutes" in duration "2 minutes")`)
+
+	var cfgErr *ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+
+	assert.Equal(t, []string{"TEST_LEGO_API_KEY"}, cfgErr.Missing)
+	assert.Len(t, cfgErr.Invalid, 3)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("TEST_LEGO_API_KEY", "key")
+	t.Setenv("TEST_LEGO_TTL", "300")
+	t.Setenv("TEST_LEGO_PROPAGATION_TIMEOUT", "abc")
+
+	config := &loadConfig{}
+
+	LoadDefaults("TEST_LEGO_", config)
+
+	expected := &loadConfig{
+		Endpoint:           "https://example.com",
+		TTL:                300,
+		PropagationTimeout: 60 * time.Second,
+		HTTPClient:         &http.Client{Timeout: 30 * time.Second},
+	}
+
+	assert.Equal(t, expected, config)
+}
+
+func TestLoadDefaults_preset(t *testing.T) {
+	t.Setenv("TEST_LEGO_TTL", "300")
+
+	config := &loadConfig{
+		TTL:                60,
+		PropagationTimeout: 2 * time.Minute,
+	}
+
+	LoadDefaults("TEST_LEGO_", config)
+
+	expected := &loadConfig{
+		Endpoint:           "https://example.com",
+		TTL:                300,
+		PropagationTimeout: 2 * time.Minute,
+		HTTPClient:         &http.Client{Timeout: 30 * time.Second},
+	}
+
+	assert.Equal(t, expected, config)
+}
+
+func TestLoad_invalidDefinition(t *testing.T) {
+	type unsupported struct {
+		Value float64 `env:"VALUE"`
+	}
+
+	err := Load("TEST_LEGO_", &unsupported{})
+	require.ErrorIs(t, err, ErrInvalidDefinition)
+
+	err = Load("TEST_LEGO_", unsupported{})
+	require.ErrorIs(t, err, ErrInvalidDefinition)
+
+	type unexported struct {
+		value string `env:"VALUE"`
+	}
+
+	err = Load("TEST_LEGO_", &unexported{})
+	require.ErrorIs(t, err, ErrInvalidDefinition)
+
+	type invalidDefault struct {
+		Value int `env:"VALUE" default:"abc"`
+	}
+
+	err = Load("TEST_LEGO_", &invalidDefault{})
+	require.ErrorIs(t, err, ErrInvalidDefinition)
+
+	assert.NotPanics(t, func() { LoadDefaults("TEST_LEGO_", &unsupported{}) })
+}
@@ -0,0 +1,158 @@
+// Package debugcapture writes sanitized transcripts of the HTTP traffic (ACME server, DNS provider APIs) to rotating files.
+package debugcapture
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/platform/config/env"
+)
+
+// Subsystem identifies the source of the HTTP traffic.
+type Subsystem string
+
+// Subsystems.
+const (
+	// ACME the traffic with the ACME server.
+	ACME Subsystem = "ACME"
+
+	// DNSAPI the traffic with the DNS provider APIs.
+	DNSAPI Subsystem = "DNS_API"
+)
+
+// Environment variables names.
+const (
+	// EnvMaxSize the maximum size, in megabytes, of a capture file before it gets rotated.
+	EnvMaxSize = "LEGO_DEBUG_HTTP_CLIENT_FILE_MAX_SIZE"
+
+	// EnvMaxAge the maximum number of days to keep the rotated capture files.
+	EnvMaxAge = "LEGO_DEBUG_HTTP_CLIENT_FILE_MAX_AGE"
+
+	// EnvMaxBackups the maximum number of rotated capture files to keep.
+	EnvMaxBackups = "LEGO_DEBUG_HTTP_CLIENT_FILE_MAX_BACKUPS"
+)
+
+const replacement = "***"
+
+var (
+	writersMu sync.Mutex
+	writers   = map[string]*RotatingFile{}
+)
+
+// EnvFile returns the name of the environment variable defining the capture file of a subsystem
+// (ex: LEGO_DEBUG_ACME_HTTP_CLIENT_FILE).
+func EnvFile(subsystem Subsystem) string {
+	return fmt.Sprintf("LEGO_DEBUG_%s_HTTP_CLIENT_FILE", subsystem)
+}
+
+// Writer returns the capture file of a subsystem, or nil if the capture is disabled for this subsystem.
+// The files are shared: the same file name always returns the same writer.
+func Writer(subsystem Subsystem) io.Writer {
+	filename := os.Getenv(EnvFile(subsystem))
+	if filename == "" {
+		return nil
+	}
+
+	writersMu.Lock()
+	defer writersMu.Unlock()
+
+	if w, ok := writers[filename]; ok {
+		return w
+	}
+
+	w := NewRotatingFile(filename, RotateOptions{
+		MaxSize:    int64(env.GetOrDefaultInt(EnvMaxSize, 10)) * 1024 * 1024,
+		MaxAge:     time.Duration(env.GetOrDefaultInt(EnvMaxAge, 7)) * 24 * time.Hour,
+		MaxBackups: env.GetOrDefaultInt(EnvMaxBackups, 5),
+	})
+
+	writers[filename] = w
+
+	return w
+}
+
+// WrapClient returns a copy of the HTTP client capturing the traffic, if the capture is enabled for the subsystem.
+// Otherwise, the client is returned unchanged.
+func WrapClient(client *http.Client, subsystem Subsystem) *http.Client {
+	w := Writer(subsystem)
+	if w == nil || client == nil {
+		return client
+	}
+
+	clone := *client
+	clone.Transport = NewTransport(client.Transport, w)
+
+	return &clone
+}
+
+// Transport is an HTTP transport writing a sanitized transcript of each request and response.
+type Transport struct {
+	rt      http.RoundTripper
+	writer  io.Writer
+	regexps []*regexp.Regexp
+	now     func() time.Time
+}
+
+// NewTransport creates a new Transport.
+// If rt is nil, http.DefaultTransport is used.
+func NewTransport(rt http.RoundTripper, w io.Writer) *Transport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	return &Transport{
+		rt:     rt,
+		writer: w,
+		regexps: []*regexp.Regexp{
+			regexp.MustCompile(`(?im)^(Authorization|Proxy-Authorization):.+$`),
+			regexp.MustCompile(`(?im)^(Cookie|Set-Cookie):.+$`),
+		},
+		now: time.Now,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	buf := new(bytes.Buffer)
+
+	_, _ = fmt.Fprintf(buf, "--- %s [HTTP Request]\n", t.now().UTC().Format(time.RFC3339Nano))
+
+	data, _ := httputil.DumpRequestOut(req, true)
+	buf.WriteString(t.redact(data))
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		_, _ = fmt.Fprintf(buf, "\n--- %s [HTTP Error]\n%v\n\n", t.now().UTC().Format(time.RFC3339Nano), err)
+		_, _ = t.writer.Write(buf.Bytes())
+
+		return nil, err
+	}
+
+	_, _ = fmt.Fprintf(buf, "\n--- %s [HTTP Response]\n", t.now().UTC().Format(time.RFC3339Nano))
+
+	data, _ = httputil.DumpResponse(resp, true)
+	buf.WriteString(t.redact(data))
+	buf.WriteString("\n\n")
+
+	// The transcript is written with a single call to avoid the interleaving of concurrent requests.
+	_, _ = t.writer.Write(buf.Bytes())
+
+	return resp, nil
+}
+
+func (t *Transport) redact(data []byte) string {
+	content := string(data)
+
+	for _, r := range t.regexps {
+		content = r.ReplaceAllString(content, "$1: "+replacement)
+	}
+
+	return content
+}
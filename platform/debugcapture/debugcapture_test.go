@@ -0,0 +1,60 @@
+package debugcapture
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(rw, &http.Cookie{Name: "session", Value: "secret-session"})
+		_, _ = rw.Write([]byte(`{"status":"valid"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	buf := new(bytes.Buffer)
+
+	client := &http.Client{Transport: NewTransport(nil, buf)}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	transcript := buf.String()
+
+	assert.Contains(t, transcript, "[HTTP Request]")
+	assert.Contains(t, transcript, "Authorization: ***")
+	assert.Contains(t, transcript, "[HTTP Response]")
+	assert.Contains(t, transcript, "Set-Cookie: ***")
+	assert.Contains(t, transcript, `{"status":"valid"}`)
+	assert.NotContains(t, transcript, "secret-token")
+	assert.NotContains(t, transcript, "secret-session")
+}
+
+func TestWrapClient(t *testing.T) {
+	client := &http.Client{}
+
+	t.Setenv(EnvFile(ACME), "")
+
+	assert.Same(t, client, WrapClient(client, ACME))
+
+	t.Setenv(EnvFile(ACME), filepath.Join(t.TempDir(), "acme.log"))
+
+	wrapped := WrapClient(client, ACME)
+
+	assert.NotSame(t, client, wrapped)
+	assert.IsType(t, &Transport{}, wrapped.Transport)
+	assert.Nil(t, client.Transport)
+}
@@ -0,0 +1,191 @@
+package debugcapture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000"
+
+// RotateOptions defines the limits of a RotatingFile.
+type RotateOptions struct {
+	// MaxSize the maximum size of the file, in bytes, before it gets rotated.
+	// 0 means no limit.
+	MaxSize int64
+
+	// MaxAge the maximum age of the rotated files.
+	// 0 means no limit.
+	MaxAge time.Duration
+
+	// MaxBackups the maximum number of rotated files to keep.
+	// 0 means no limit.
+	MaxBackups int
+}
+
+// RotatingFile is an io.Writer that writes to a file,
+// and rotates it when it reaches a maximum size.
+// The rotated files are named <name>.<timestamp>.
+type RotatingFile struct {
+	filename string
+	options  RotateOptions
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	now func() time.Time
+}
+
+// NewRotatingFile creates a new RotatingFile.
+// The file is opened on the first write.
+func NewRotatingFile(filename string, options RotateOptions) *RotatingFile {
+	return &RotatingFile{
+		filename: filename,
+		options:  options,
+		now:      time.Now,
+	}
+}
+
+// Write implements io.Writer.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		err := r.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if r.options.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.options.MaxSize {
+		err := r.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the current file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(r.filename), 0o700)
+	if err != nil {
+		return fmt.Errorf("debugcapture: %w", err)
+	}
+
+	file, err := os.OpenFile(r.filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("debugcapture: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("debugcapture: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	err := r.file.Close()
+	if err != nil {
+		return fmt.Errorf("debugcapture: %w", err)
+	}
+
+	r.file = nil
+
+	backup := r.filename + "." + r.now().UTC().Format(backupTimeFormat)
+
+	err = os.Rename(r.filename, backup)
+	if err != nil {
+		return fmt.Errorf("debugcapture: %w", err)
+	}
+
+	r.prune()
+
+	return r.open()
+}
+
+// prune removes the rotated files exceeding MaxBackups or MaxAge.
+func (r *RotatingFile) prune() {
+	if r.options.MaxBackups <= 0 && r.options.MaxAge <= 0 {
+		return
+	}
+
+	backups := r.backups()
+
+	// The names contain the rotation time: the newest files are the last ones.
+	slices.Sort(backups)
+
+	cutoff := r.now().UTC().Add(-r.options.MaxAge)
+
+	for i, backup := range backups {
+		if r.options.MaxBackups > 0 && len(backups)-i > r.options.MaxBackups {
+			_ = os.Remove(backup)
+			continue
+		}
+
+		if r.options.MaxAge <= 0 {
+			continue
+		}
+
+		rotatedAt, err := time.Parse(backupTimeFormat, strings.TrimPrefix(filepath.Base(backup), filepath.Base(r.filename)+"."))
+		if err == nil && rotatedAt.Before(cutoff) {
+			_ = os.Remove(backup)
+		}
+	}
+}
+
+func (r *RotatingFile) backups() []string {
+	entries, err := os.ReadDir(filepath.Dir(r.filename))
+	if err != nil {
+		return nil
+	}
+
+	prefix := filepath.Base(r.filename) + "."
+
+	var backups []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+
+		_, err := time.Parse(backupTimeFormat, strings.TrimPrefix(entry.Name(), prefix))
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, filepath.Join(filepath.Dir(r.filename), entry.Name()))
+	}
+
+	return backups
+}
@@ -0,0 +1,87 @@
+package debugcapture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_Write(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "capture.log")
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	w := NewRotatingFile(filename, RotateOptions{MaxSize: 10})
+	w.now = func() time.Time { return now }
+
+	t.Cleanup(func() { _ = w.Close() })
+
+	_, err := w.Write([]byte("12345678"))
+	require.NoError(t, err)
+
+	now = now.Add(time.Second)
+
+	_, err = w.Write([]byte("abcdef"))
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abcdef", string(content))
+
+	backup, err := os.ReadFile(filename + ".20250101T120001.000")
+	require.NoError(t, err)
+
+	assert.Equal(t, "12345678", string(backup))
+}
+
+func TestRotatingFile_Write_maxBackups(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "capture.log")
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	w := NewRotatingFile(filename, RotateOptions{MaxSize: 1, MaxBackups: 2})
+	w.now = func() time.Time { return now }
+
+	t.Cleanup(func() { _ = w.Close() })
+
+	for range 5 {
+		now = now.Add(time.Second)
+
+		_, err := w.Write([]byte("a"))
+		require.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []string{
+		filename + ".20250101T120004.000",
+		filename + ".20250101T120005.000",
+	}, w.backups())
+}
+
+func TestRotatingFile_Write_maxAge(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "capture.log")
+
+	old := filename + ".20240101T120000.000"
+	require.NoError(t, os.WriteFile(old, []byte("old"), 0o600))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	w := NewRotatingFile(filename, RotateOptions{MaxSize: 1, MaxAge: 7 * 24 * time.Hour})
+	w.now = func() time.Time { return now }
+
+	t.Cleanup(func() { _ = w.Close() })
+
+	for range 2 {
+		_, err := w.Write([]byte("a"))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{filename + ".20250101T120000.000"}, w.backups())
+}
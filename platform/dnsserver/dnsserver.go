@@ -0,0 +1,145 @@
+// Package dnsserver provides the DNS server (UDP and TCP) and the in-memory store of TXT records
+// shared by the DNS providers serving the challenge records themselves (ex: the standalone provider, the tester fakedns).
+package dnsserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// Server a DNS server listening on the same address with UDP and TCP.
+type Server struct {
+	udp *dns.Server
+	tcp *dns.Server
+}
+
+// Start starts a DNS server on the address (interface:port or :port), and waits until it's ready to serve.
+// When the port is chosen by the system (`:0`), the TCP server uses the port of the UDP server.
+func Start(addr string, handler dns.Handler) (*Server, error) {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not start the DNS server (UDP): %w", err)
+	}
+
+	host, _, _ := net.SplitHostPort(addr)
+	_, port, _ := net.SplitHostPort(pc.LocalAddr().String())
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		_ = pc.Close()
+
+		return nil, fmt.Errorf("could not start the DNS server (TCP): %w", err)
+	}
+
+	s := &Server{
+		udp: &dns.Server{PacketConn: pc, Handler: handler},
+		tcp: &dns.Server{Listener: listener, Handler: handler},
+	}
+
+	err = errors.Join(serve(s.udp), serve(s.tcp))
+	if err != nil {
+		_ = pc.Close()
+		_ = listener.Close()
+
+		return nil, fmt.Errorf("could not start the DNS server: %w", err)
+	}
+
+	return s, nil
+}
+
+// Addr returns the address of the server.
+func (s *Server) Addr() net.Addr {
+	return s.udp.PacketConn.LocalAddr()
+}
+
+// Shutdown stops the server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := errors.Join(s.udp.ShutdownContext(ctx), s.tcp.ShutdownContext(ctx))
+	if err != nil {
+		return fmt.Errorf("could not stop the DNS server: %w", err)
+	}
+
+	return nil
+}
+
+// serve starts serving in background, and waits until the server is started:
+// the shutdown of a server not started yet fails.
+func serve(server *dns.Server) error {
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- server.ActivateAndServe() }()
+
+	select {
+	case <-started:
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Records the values of the TXT records, by FQDN.
+// The names are case-insensitive.
+// The zero value is ready to use, and it's safe for concurrent use.
+type Records struct {
+	mu     sync.RWMutex
+	values map[string][]string
+}
+
+// Add adds a value to the TXT records of the FQDN, if it doesn't exist yet.
+func (r *Records) Add(fqdn, value string) {
+	name := dns.CanonicalName(fqdn)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.values == nil {
+		r.values = make(map[string][]string)
+	}
+
+	if !slices.Contains(r.values[name], value) {
+		r.values[name] = append(r.values[name], value)
+	}
+}
+
+// Remove removes a value from the TXT records of the FQDN.
+func (r *Records) Remove(fqdn, value string) {
+	name := dns.CanonicalName(fqdn)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := slices.DeleteFunc(r.values[name], func(v string) bool { return v == value })
+	if len(values) == 0 {
+		delete(r.values, name)
+	} else {
+		r.values[name] = values
+	}
+}
+
+// Get returns the values of the TXT records of the FQDN,
+// and false if the FQDN has no records.
+func (r *Records) Get(fqdn string) ([]string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values, ok := r.values[dns.CanonicalName(fqdn)]
+
+	return slices.Clone(values), ok
+}
+
+// Len returns the number of FQDNs with records.
+func (r *Records) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.values)
+}
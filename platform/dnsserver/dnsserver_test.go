@@ -0,0 +1,88 @@
+package dnsserver
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart(t *testing.T) {
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		m.Answer = append(m.Answer, &dns.TXT{
+			Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+			Txt: []string{"value"},
+		})
+
+		_ = w.WriteMsg(m)
+	})
+
+	server, err := Start("127.0.0.1:0", handler)
+	require.NoError(t, err)
+
+	for _, network := range []string{"udp", "tcp"} {
+		t.Run(network, func(t *testing.T) {
+			m := new(dns.Msg)
+			m.SetQuestion("_acme-challenge.example.com.", dns.TypeTXT)
+
+			client := &dns.Client{Net: network}
+
+			resp, _, err := client.Exchange(m, server.Addr().String())
+			require.NoError(t, err)
+
+			require.Len(t, resp.Answer, 1)
+			assert.Equal(t, []string{"value"}, resp.Answer[0].(*dns.TXT).Txt)
+		})
+	}
+
+	err = server.Shutdown(t.Context())
+	require.NoError(t, err)
+}
+
+func TestStart_shutdownImmediately(t *testing.T) {
+	server, err := Start("127.0.0.1:0", dns.HandlerFunc(func(dns.ResponseWriter, *dns.Msg) {}))
+	require.NoError(t, err)
+
+	// The server is started when Start returns: the shutdown cannot fail with "server not started".
+	err = server.Shutdown(t.Context())
+	require.NoError(t, err)
+}
+
+func TestStart_error(t *testing.T) {
+	server, err := Start("127.0.0.1:0", dns.HandlerFunc(func(dns.ResponseWriter, *dns.Msg) {}))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = server.Shutdown(t.Context()) })
+
+	_, err = Start(server.Addr().String(), dns.HandlerFunc(func(dns.ResponseWriter, *dns.Msg) {}))
+	require.ErrorContains(t, err, "could not start the DNS server (UDP)")
+}
+
+func TestRecords(t *testing.T) {
+	var records Records
+
+	records.Add("_acme-challenge.example.com", "a")
+	records.Add("_ACME-Challenge.example.com.", "b")
+	records.Add("_acme-challenge.example.com.", "a")
+
+	values, ok := records.Get("_acme-challenge.example.com.")
+	require.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, values)
+	assert.Equal(t, 1, records.Len())
+
+	records.Remove("_acme-challenge.example.com.", "a")
+
+	values, ok = records.Get("_acme-challenge.example.com")
+	require.True(t, ok)
+	assert.Equal(t, []string{"b"}, values)
+
+	records.Remove("_acme-challenge.example.com.", "b")
+
+	_, ok = records.Get("_acme-challenge.example.com.")
+	assert.False(t, ok)
+	assert.Zero(t, records.Len())
+}
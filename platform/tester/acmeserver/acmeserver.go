@@ -0,0 +1,1001 @@
+// Package acmeserver provides a minimal in-process ACME server (RFC 8555) for the tests.
+//
+// The server implements the flow used by lego (accounts, orders, authorizations, challenges, finalization,
+// certificates, revocation, and renewal information) with the same paths as Pebble,
+// so the tests of the certifier, the registrar, and the solvers can run without network access or external binaries.
+// The challenges are really validated, against the test doubles (ex: the DNS server of `fakedns`):
+//
+//	provider, err := fakedns.NewProvider("127.0.0.1:0")
+//	if err != nil {
+//		return err
+//	}
+//	defer func() { _ = provider.Close() }()
+//
+//	server := acmeserver.New(t, acmeserver.WithDNSResolver(provider.Addr()))
+//
+//	config := lego.NewConfig(user)
+//	config.CADirURL = server.DirectoryURL()
+//	config.HTTPClient = server.Client()
+//
+// The server is not a CA: the states are kept in memory, and the certificates are signed by a throwaway root.
+package acmeserver
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+// Paths of the endpoints (the same as Pebble).
+const (
+	pathDirectory     = "/dir"
+	pathNonce         = "/nonce-plz"
+	pathNewAccount    = "/sign-me-up"
+	pathAccount       = "/my-account/"
+	pathNewOrder      = "/order-plz"
+	pathOrder         = "/my-order/"
+	pathAuthorization = "/authZ/"
+	pathChallenge     = "/chalZ/"
+	pathFinalize      = "/finalize-order/"
+	pathCertificate   = "/certZ/"
+	pathRevoke        = "/revoke-cert"
+	pathRenewalInfo   = "/renewal-info"
+)
+
+// DefaultCertificateValidity the default validity of the issued certificates.
+const DefaultCertificateValidity = 90 * 24 * time.Hour
+
+var signatureAlgorithms = []jose.SignatureAlgorithm{jose.RS256, jose.ES256, jose.ES384, jose.ES512}
+
+// Option configures the server.
+type Option func(*Server)
+
+// WithDNSResolver defines the DNS server queried to validate the DNS-01 challenges (ex: `fakedns.Provider.Addr()`).
+func WithDNSResolver(addr string) Option {
+	return func(s *Server) {
+		s.dnsResolver = addr
+	}
+}
+
+// WithHTTPAddress defines the address (`host:port`) requested to validate the HTTP-01 challenges,
+// instead of the port 80 of the domain.
+func WithHTTPAddress(addr string) Option {
+	return func(s *Server) {
+		s.httpAddress = addr
+	}
+}
+
+// WithTLSAddress defines the address (`host:port`) requested to validate the TLS-ALPN-01 challenges,
+// instead of the port 443 of the domain.
+func WithTLSAddress(addr string) Option {
+	return func(s *Server) {
+		s.tlsAddress = addr
+	}
+}
+
+// SkipValidation considers every challenge as valid (as `PEBBLE_VA_ALWAYS_VALID=1`).
+func SkipValidation() Option {
+	return func(s *Server) {
+		s.skipValidation = true
+	}
+}
+
+// WithCertificateValidity defines the validity of the issued certificates.
+func WithCertificateValidity(validity time.Duration) Option {
+	return func(s *Server) {
+		s.validity = validity
+	}
+}
+
+// Server is a minimal ACME server.
+type Server struct {
+	*httptest.Server
+
+	dnsResolver    string
+	httpAddress    string
+	tlsAddress     string
+	skipValidation bool
+	validity       time.Duration
+
+	ca *certificateAuthority
+
+	mu           sync.Mutex
+	lastID       int
+	nonces       map[string]struct{}
+	accounts     map[string]*account
+	orders       map[string]*order
+	authzs       map[string]*authorization
+	challenges   map[string]*challengeRef
+	certificates map[string]*issuedCertificate
+}
+
+type account struct {
+	acme.Account
+
+	id  string
+	key *jose.JSONWebKey
+}
+
+type order struct {
+	acme.Order
+
+	id        string
+	accountID string
+	authzIDs  []string
+}
+
+type authorization struct {
+	acme.Authorization
+
+	id        string
+	accountID string
+}
+
+type challengeRef struct {
+	authzID string
+	index   int
+}
+
+type issuedCertificate struct {
+	accountID string
+	cert      *x509.Certificate
+	chain     []byte
+	revoked   bool
+}
+
+// request is a verified JWS request.
+type request struct {
+	payload []byte
+
+	// account the account of the `kid`, nil if the request is signed by an embedded key (`jwk`).
+	account *account
+	jwk     *jose.JSONWebKey
+}
+
+// New starts a server, stopped at the end of the test.
+func New(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	ca, err := newCertificateAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		validity:     DefaultCertificateValidity,
+		ca:           ca,
+		nonces:       make(map[string]struct{}),
+		accounts:     make(map[string]*account),
+		orders:       make(map[string]*order),
+		authzs:       make(map[string]*authorization),
+		challenges:   make(map[string]*challengeRef),
+		certificates: make(map[string]*issuedCertificate),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+pathDirectory, s.handleDirectory)
+	mux.HandleFunc("HEAD "+pathNonce, s.handleNonce)
+	mux.HandleFunc("GET "+pathNonce, s.handleNonce)
+	mux.HandleFunc("POST "+pathNewAccount, s.handleNewAccount)
+	mux.HandleFunc("POST "+pathAccount+"{id}", s.handleAccount)
+	mux.HandleFunc("POST "+pathNewOrder, s.handleNewOrder)
+	mux.HandleFunc("POST "+pathOrder+"{id}", s.handleOrder)
+	mux.HandleFunc("POST "+pathAuthorization+"{id}", s.handleAuthorization)
+	mux.HandleFunc("POST "+pathChallenge+"{id}", s.handleChallenge)
+	mux.HandleFunc("POST "+pathFinalize+"{id}", s.handleFinalize)
+	mux.HandleFunc("POST "+pathCertificate+"{id}", s.handleCertificate)
+	mux.HandleFunc("POST "+pathRevoke, s.handleRevoke)
+	mux.HandleFunc("GET "+pathRenewalInfo+"/{id}", s.handleRenewalInfo)
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Replay-Nonce", s.newNonce())
+		rw.Header().Set("Cache-Control", "no-store")
+
+		mux.ServeHTTP(rw, req)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// DirectoryURL returns the URL of the directory.
+func (s *Server) DirectoryURL() string {
+	return s.URL + pathDirectory
+}
+
+// Root returns the root certificate of the issued certificates.
+func (s *Server) Root() *x509.Certificate {
+	return s.ca.root
+}
+
+// Revoked returns true if the certificate has been revoked.
+func (s *Server) Revoked(cert *x509.Certificate) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.certificates[cert.SerialNumber.String()]
+
+	return ok && issued.revoked
+}
+
+func (s *Server) handleDirectory(rw http.ResponseWriter, _ *http.Request) {
+	writeJSON(rw, http.StatusOK, acme.Directory{
+		NewNonceURL:   s.URL + pathNonce,
+		NewAccountURL: s.URL + pathNewAccount,
+		NewOrderURL:   s.URL + pathNewOrder,
+		RevokeCertURL: s.URL + pathRevoke,
+		RenewalInfo:   s.URL + pathRenewalInfo,
+	})
+}
+
+func (s *Server) handleNonce(rw http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleNewAccount(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verify(rw, req)
+	if !ok {
+		return
+	}
+
+	if r.jwk == nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", "the new account requests must be signed with an embedded key (jwk)")
+		return
+	}
+
+	var payload acme.Account
+
+	if !decodePayload(rw, r, &payload) {
+		return
+	}
+
+	thumbprint, err := keyThumbprint(r.jwk)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "badPublicKey", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if acc := s.accountByThumbprint(thumbprint); acc != nil {
+		rw.Header().Set("Location", s.URL+pathAccount+acc.id)
+		writeJSON(rw, http.StatusOK, acc.Account)
+
+		return
+	}
+
+	if payload.OnlyReturnExisting {
+		writeProblem(rw, http.StatusBadRequest, "accountDoesNotExist", "no account for the key")
+		return
+	}
+
+	acc := &account{
+		id:  s.nextID(),
+		key: r.jwk,
+		Account: acme.Account{
+			Status:               acme.StatusValid,
+			Contact:              payload.Contact,
+			TermsOfServiceAgreed: payload.TermsOfServiceAgreed,
+		},
+	}
+
+	acc.Orders = s.URL + pathAccount + acc.id + "/orders"
+
+	s.accounts[acc.id] = acc
+
+	rw.Header().Set("Location", s.URL+pathAccount+acc.id)
+	writeJSON(rw, http.StatusCreated, acc.Account)
+}
+
+func (s *Server) handleAccount(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	if r.account.id != req.PathValue("id") {
+		writeProblem(rw, http.StatusForbidden, "unauthorized", "the account doesn't match the key")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(r.payload) > 0 {
+		var payload acme.Account
+
+		if !decodePayload(rw, r, &payload) {
+			return
+		}
+
+		if payload.Contact != nil {
+			r.account.Contact = payload.Contact
+		}
+
+		if payload.TermsOfServiceAgreed {
+			r.account.TermsOfServiceAgreed = true
+		}
+
+		if payload.Status == acme.StatusDeactivated {
+			r.account.Status = acme.StatusDeactivated
+		}
+	}
+
+	writeJSON(rw, http.StatusOK, r.account.Account)
+}
+
+func (s *Server) handleNewOrder(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	var payload acme.Order
+
+	if !decodePayload(rw, r, &payload) {
+		return
+	}
+
+	if len(payload.Identifiers) == 0 {
+		writeProblem(rw, http.StatusBadRequest, "malformed", "the order has no identifiers")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := &order{
+		id:        s.nextID(),
+		accountID: r.account.id,
+		Order: acme.Order{
+			Status:      acme.StatusPending,
+			Expires:     time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			Identifiers: payload.Identifiers,
+			Profile:     payload.Profile,
+			NotBefore:   payload.NotBefore,
+			NotAfter:    payload.NotAfter,
+			Replaces:    payload.Replaces,
+		},
+	}
+
+	o.Finalize = s.URL + pathFinalize + o.id
+
+	for _, identifier := range payload.Identifiers {
+		authz, err := s.newAuthorization(r.account.id, identifier)
+		if err != nil {
+			writeProblem(rw, http.StatusBadRequest, "rejectedIdentifier", err.Error())
+			return
+		}
+
+		o.authzIDs = append(o.authzIDs, authz.id)
+		o.Authorizations = append(o.Authorizations, s.URL+pathAuthorization+authz.id)
+	}
+
+	s.orders[o.id] = o
+
+	rw.Header().Set("Location", s.URL+pathOrder+o.id)
+	writeJSON(rw, http.StatusCreated, o.Order)
+}
+
+// newAuthorization creates an authorization with the challenges supported by the identifier.
+func (s *Server) newAuthorization(accountID string, identifier acme.Identifier) (*authorization, error) {
+	authz := &authorization{
+		id:        s.nextID(),
+		accountID: accountID,
+		Authorization: acme.Authorization{
+			Status:     acme.StatusPending,
+			Expires:    time.Now().Add(time.Hour).UTC(),
+			Identifier: identifier,
+		},
+	}
+
+	var types []string
+
+	switch identifier.Type {
+	case "dns":
+		if value, ok := strings.CutPrefix(identifier.Value, "*."); ok {
+			authz.Identifier.Value = value
+			authz.Wildcard = true
+			types = []string{challengeDNS01}
+		} else {
+			types = []string{challengeHTTP01, challengeDNS01, challengeTLSALPN01}
+		}
+
+	case "ip":
+		types = []string{challengeHTTP01, challengeTLSALPN01}
+
+	default:
+		return nil, fmt.Errorf("unsupported identifier type: %s", identifier.Type)
+	}
+
+	for i, typ := range types {
+		token, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		id := s.nextID()
+
+		authz.Challenges = append(authz.Challenges, acme.Challenge{
+			Type:   typ,
+			URL:    s.URL + pathChallenge + id,
+			Status: acme.StatusPending,
+			Token:  token,
+		})
+
+		s.challenges[id] = &challengeRef{authzID: authz.id, index: i}
+	}
+
+	s.authzs[authz.id] = authz
+
+	return authz, nil
+}
+
+func (s *Server) handleOrder(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[req.PathValue("id")]
+	if !ok || o.accountID != r.account.id {
+		writeProblem(rw, http.StatusNotFound, "malformed", "order not found")
+		return
+	}
+
+	s.updateOrderStatus(o)
+
+	writeJSON(rw, http.StatusOK, o.Order)
+}
+
+// updateOrderStatus moves a pending order to ready when all its authorizations are valid,
+// or to invalid when one of its authorizations is not valid.
+func (s *Server) updateOrderStatus(o *order) {
+	if o.Status != acme.StatusPending {
+		return
+	}
+
+	ready := true
+
+	for _, id := range o.authzIDs {
+		switch s.authzs[id].Status {
+		case acme.StatusValid:
+		case acme.StatusPending:
+			ready = false
+		default:
+			o.Status = acme.StatusInvalid
+			o.Error = &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:unauthorized", Detail: "an authorization is not valid"}
+
+			return
+		}
+	}
+
+	if ready {
+		o.Status = acme.StatusReady
+	}
+}
+
+func (s *Server) handleAuthorization(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authz, ok := s.authzs[req.PathValue("id")]
+	if !ok || authz.accountID != r.account.id {
+		writeProblem(rw, http.StatusNotFound, "malformed", "authorization not found")
+		return
+	}
+
+	if len(r.payload) > 0 {
+		var payload acme.Authorization
+
+		if !decodePayload(rw, r, &payload) {
+			return
+		}
+
+		if payload.Status == acme.StatusDeactivated {
+			authz.Status = acme.StatusDeactivated
+		}
+	}
+
+	writeJSON(rw, http.StatusOK, authz.Authorization)
+}
+
+func (s *Server) handleChallenge(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+
+	ref, ok := s.challenges[req.PathValue("id")]
+	if !ok || s.authzs[ref.authzID].accountID != r.account.id {
+		s.mu.Unlock()
+		writeProblem(rw, http.StatusNotFound, "malformed", "challenge not found")
+
+		return
+	}
+
+	authz := s.authzs[ref.authzID]
+	chlg := authz.Challenges[ref.index]
+
+	s.mu.Unlock()
+
+	// An empty payload is a POST-as-GET: the validation is requested by a JSON object.
+	if len(r.payload) > 0 && chlg.Status == acme.StatusPending && authz.Status == acme.StatusPending {
+		keyAuth, err := keyAuthorization(chlg.Token, r.account.key)
+		if err != nil {
+			writeProblem(rw, http.StatusInternalServerError, "serverInternal", err.Error())
+			return
+		}
+
+		// The validation is done without lock: it may request the test doubles, which may call the server.
+		err = s.validate(chlg.Type, authz.Identifier.Value, chlg.Token, keyAuth)
+
+		s.mu.Lock()
+
+		if err != nil {
+			chlg.Status = acme.StatusInvalid
+			chlg.Error = &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:unauthorized", Detail: err.Error(), HTTPStatus: http.StatusForbidden}
+			authz.Status = acme.StatusInvalid
+		} else {
+			chlg.Status = acme.StatusValid
+			chlg.Validated = time.Now().UTC()
+			authz.Status = acme.StatusValid
+		}
+
+		authz.Challenges[ref.index] = chlg
+
+		s.mu.Unlock()
+	}
+
+	rw.Header().Set("Link", fmt.Sprintf(`<%s>;rel="up"`, s.URL+pathAuthorization+authz.id))
+	writeJSON(rw, http.StatusOK, chlg)
+}
+
+func (s *Server) handleFinalize(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	var payload acme.CSRMessage
+
+	if !decodePayload(rw, r, &payload) {
+		return
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(payload.Csr)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+
+	csr, err := x509.ParseCertificateRequest(der)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[req.PathValue("id")]
+	if !ok || o.accountID != r.account.id {
+		writeProblem(rw, http.StatusNotFound, "malformed", "order not found")
+		return
+	}
+
+	s.updateOrderStatus(o)
+
+	if o.Status != acme.StatusReady {
+		writeProblem(rw, http.StatusForbidden, "orderNotReady", "the order is "+o.Status)
+		return
+	}
+
+	err = checkCSR(csr, o.Identifiers)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+
+	cert, chain, err := s.ca.issue(csr, s.validity)
+	if err != nil {
+		writeProblem(rw, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	id := cert.SerialNumber.String()
+
+	s.certificates[id] = &issuedCertificate{accountID: r.account.id, cert: cert, chain: chain}
+
+	o.Status = acme.StatusValid
+	o.Certificate = s.URL + pathCertificate + id
+
+	rw.Header().Set("Location", s.URL+pathOrder+o.id)
+	writeJSON(rw, http.StatusOK, o.Order)
+}
+
+func (s *Server) handleCertificate(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verifyAccount(rw, req)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.certificates[req.PathValue("id")]
+	if !ok || issued.accountID != r.account.id {
+		writeProblem(rw, http.StatusNotFound, "malformed", "certificate not found")
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/pem-certificate-chain")
+	_, _ = rw.Write(issued.chain)
+}
+
+func (s *Server) handleRevoke(rw http.ResponseWriter, req *http.Request) {
+	r, ok := s.verify(rw, req)
+	if !ok {
+		return
+	}
+
+	var payload acme.RevokeCertMessage
+
+	if !decodePayload(rw, r, &payload) {
+		return
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(payload.Certificate)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.certificates[cert.SerialNumber.String()]
+	if !ok {
+		writeProblem(rw, http.StatusNotFound, "malformed", "certificate not found")
+		return
+	}
+
+	// The certificate is revoked by the account which obtained it, or with the key of the certificate.
+	authorized := r.account != nil && r.account.id == issued.accountID
+	if r.jwk != nil {
+		if key, okK := r.jwk.Key.(interface{ Equal(crypto.PublicKey) bool }); okK && key.Equal(issued.cert.PublicKey) {
+			authorized = true
+		}
+	}
+
+	if !authorized {
+		writeProblem(rw, http.StatusForbidden, "unauthorized", "the requester is not allowed to revoke the certificate")
+		return
+	}
+
+	if issued.revoked {
+		writeProblem(rw, http.StatusBadRequest, "alreadyRevoked", "the certificate is already revoked")
+		return
+	}
+
+	issued.revoked = true
+
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRenewalInfo(rw http.ResponseWriter, req *http.Request) {
+	_, serial, ok := strings.Cut(req.PathValue("id"), ".")
+	if !ok {
+		writeProblem(rw, http.StatusBadRequest, "malformed", "invalid certificate ID")
+		return
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(serial)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var issued *issuedCertificate
+
+	for _, candidate := range s.certificates {
+		if string(candidate.cert.SerialNumber.Bytes()) == string(trimSerial(raw)) {
+			issued = candidate
+			break
+		}
+	}
+
+	if issued == nil {
+		writeProblem(rw, http.StatusNotFound, "malformed", "certificate not found")
+		return
+	}
+
+	// The window starts when 1/3 of the lifetime remains, the revoked certificates must be renewed now.
+	lifetime := issued.cert.NotAfter.Sub(issued.cert.NotBefore)
+	start := issued.cert.NotAfter.Add(-lifetime / 3)
+	end := start.Add(lifetime / 6)
+
+	if issued.revoked {
+		start, end = time.Now().Add(-time.Hour), time.Now()
+	}
+
+	rw.Header().Set("Retry-After", strconv.Itoa(int((6 * time.Hour).Seconds())))
+	writeJSON(rw, http.StatusOK, acme.RenewalInfoResponse{
+		SuggestedWindow: acme.Window{Start: start.UTC(), End: end.UTC()},
+	})
+}
+
+// verify verifies the JWS of the request: the nonce, the URL, and the signature.
+// The problem is written to the response if the request is invalid.
+func (s *Server) verify(rw http.ResponseWriter, req *http.Request) (*request, bool) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", err.Error())
+		return nil, false
+	}
+
+	jws, err := jose.ParseSigned(string(body), signatureAlgorithms)
+	if err != nil || len(jws.Signatures) != 1 {
+		writeProblem(rw, http.StatusBadRequest, "malformed", fmt.Sprintf("invalid JWS: %v", err))
+		return nil, false
+	}
+
+	header := jws.Signatures[0].Protected
+
+	if !s.useNonce(header.Nonce) {
+		writeProblem(rw, http.StatusBadRequest, "badNonce", "invalid nonce")
+		return nil, false
+	}
+
+	if url, _ := header.ExtraHeaders["url"].(string); url != s.URL+req.URL.Path {
+		writeProblem(rw, http.StatusUnauthorized, "unauthorized", fmt.Sprintf("the URL of the JWS (%s) doesn't match the request", url))
+		return nil, false
+	}
+
+	r := &request{}
+
+	var key any
+
+	switch {
+	case header.KeyID != "" && header.JSONWebKey == nil:
+		s.mu.Lock()
+		r.account = s.accountByKID(header.KeyID)
+		s.mu.Unlock()
+
+		if r.account == nil {
+			writeProblem(rw, http.StatusBadRequest, "accountDoesNotExist", "unknown account: "+header.KeyID)
+			return nil, false
+		}
+
+		key = r.account.key.Key
+
+	case header.KeyID == "" && header.JSONWebKey != nil:
+		r.jwk = header.JSONWebKey
+		key = r.jwk.Key
+
+	default:
+		writeProblem(rw, http.StatusBadRequest, "malformed", "the JWS must have either a kid or a jwk")
+		return nil, false
+	}
+
+	r.payload, err = jws.Verify(key)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", "invalid JWS signature")
+		return nil, false
+	}
+
+	return r, true
+}
+
+// verifyAccount verifies the JWS of the request, which must be signed by a valid account.
+func (s *Server) verifyAccount(rw http.ResponseWriter, req *http.Request) (*request, bool) {
+	r, ok := s.verify(rw, req)
+	if !ok {
+		return nil, false
+	}
+
+	if r.account == nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", "the request must be signed by an account (kid)")
+		return nil, false
+	}
+
+	if r.account.Status != acme.StatusValid {
+		writeProblem(rw, http.StatusUnauthorized, "unauthorized", "the account is "+r.account.Status)
+		return nil, false
+	}
+
+	return r, true
+}
+
+func (s *Server) newNonce() string {
+	nonce, err := randomToken()
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	s.nonces[nonce] = struct{}{}
+	s.mu.Unlock()
+
+	return nonce
+}
+
+func (s *Server) useNonce(nonce string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.nonces[nonce]
+	delete(s.nonces, nonce)
+
+	return ok
+}
+
+func (s *Server) nextID() string {
+	s.lastID++
+
+	return strconv.Itoa(s.lastID)
+}
+
+func (s *Server) accountByKID(kid string) *account {
+	id, ok := strings.CutPrefix(kid, s.URL+pathAccount)
+	if !ok {
+		return nil
+	}
+
+	return s.accounts[id]
+}
+
+func (s *Server) accountByThumbprint(thumbprint string) *account {
+	for _, acc := range s.accounts {
+		if t, err := keyThumbprint(acc.key); err == nil && t == thumbprint {
+			return acc
+		}
+	}
+
+	return nil
+}
+
+func decodePayload(rw http.ResponseWriter, r *request, v any) bool {
+	if len(r.payload) == 0 {
+		return true
+	}
+
+	err := json.Unmarshal(r.payload, v)
+	if err != nil {
+		writeProblem(rw, http.StatusBadRequest, "malformed", fmt.Sprintf("invalid payload: %v", err))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(rw http.ResponseWriter, status int, body any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(body)
+}
+
+func writeProblem(rw http.ResponseWriter, status int, typ, detail string) {
+	rw.Header().Set("Content-Type", "application/problem+json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(acme.ProblemDetails{
+		Type:       "urn:ietf:params:acme:error:" + typ,
+		Detail:     detail,
+		HTTPStatus: status,
+	})
+}
+
+func keyThumbprint(key *jose.JSONWebKey) (string, error) {
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+func keyAuthorization(token string, key *jose.JSONWebKey) (string, error) {
+	thumbprint, err := keyThumbprint(key)
+	if err != nil {
+		return "", err
+	}
+
+	return token + "." + thumbprint, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// trimSerial removes the leading zero of the DER encoding of a positive serial number.
+func trimSerial(raw []byte) []byte {
+	for len(raw) > 1 && raw[0] == 0 {
+		raw = raw[1:]
+	}
+
+	return raw
+}
+
+// checkCSR checks that the CSR requests the identifiers of the order.
+func checkCSR(csr *x509.CertificateRequest, identifiers []acme.Identifier) error {
+	requested := make(map[string]bool)
+
+	for _, name := range csr.DNSNames {
+		requested["dns:"+strings.ToLower(name)] = true
+	}
+
+	for _, ip := range csr.IPAddresses {
+		requested["ip:"+ip.String()] = true
+	}
+
+	if csr.Subject.CommonName != "" && !requested["dns:"+strings.ToLower(csr.Subject.CommonName)] && !requested["ip:"+csr.Subject.CommonName] {
+		return errors.New("the common name is not a requested identifier")
+	}
+
+	if len(requested) != len(identifiers) {
+		return fmt.Errorf("the CSR requests %d identifiers, the order has %d identifiers", len(requested), len(identifiers))
+	}
+
+	for _, identifier := range identifiers {
+		if !requested[identifier.Type+":"+strings.ToLower(identifier.Value)] {
+			return fmt.Errorf("the CSR doesn't request the identifier %s", identifier.Value)
+		}
+	}
+
+	return nil
+}
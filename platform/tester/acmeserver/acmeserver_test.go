@@ -0,0 +1,228 @@
+package acmeserver
+
+import (
+	"crypto"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/challenge/http01"
+	"github.com/digicert/lego/v4/challenge/tlsalpn01"
+	"github.com/digicert/lego/v4/lego"
+	"github.com/digicert/lego/v4/platform/tester/fakedns"
+	"github.com/digicert/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	key          crypto.PrivateKey
+	registration *registration.Resource
+}
+
+func (u *user) GetEmail() string                        { return "test@example.com" }
+func (u *user) GetRegistration() *registration.Resource { return u.registration }
+func (u *user) GetPrivateKey() crypto.PrivateKey        { return u.key }
+
+type noopProvider struct{}
+
+func (noopProvider) Present(_, _, _ string) error { return nil }
+func (noopProvider) CleanUp(_, _, _ string) error { return nil }
+
+func setupClient(t *testing.T, server *Server) *lego.Client {
+	t.Helper()
+
+	privateKey, err := certcrypto.GeneratePrivateKey(certcrypto.EC256)
+	require.NoError(t, err)
+
+	u := &user{key: privateKey}
+
+	config := lego.NewConfig(u)
+	config.CADirURL = server.DirectoryURL()
+	config.HTTPClient = server.Client()
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	u.registration, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	return client
+}
+
+func verifyCertificate(t *testing.T, server *Server, resource *certificate.Resource, domains ...string) *x509.Certificate {
+	t.Helper()
+
+	certificates, err := certcrypto.ParsePEMBundle(resource.Certificate)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Root())
+
+	for _, domain := range domains {
+		_, err = certificates[0].Verify(x509.VerifyOptions{DNSName: domain, Roots: roots})
+		require.NoError(t, err)
+	}
+
+	return certificates[0]
+}
+
+// freeAddress reserves a free port for a challenge server.
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+
+	require.NoError(t, listener.Close())
+
+	return addr
+}
+
+func TestServer_dns01(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := fakedns.NewProvider("127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	server := New(t, WithDNSResolver(provider.Addr()))
+
+	client := setupClient(t, server)
+
+	err = client.Challenge.SetDNS01Provider(provider,
+		dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+		dns01.DisableAuthoritativeNssPropagationRequirement(),
+	)
+	require.NoError(t, err)
+
+	resource, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com", "*.example.com"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	verifyCertificate(t, server, resource, "example.com", "foo.example.com")
+
+	assert.Empty(t, provider.Records("_acme-challenge.example.com."))
+}
+
+func TestServer_dns01_invalid(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := fakedns.NewProvider("127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	server := New(t, WithDNSResolver(provider.Addr()))
+
+	client := setupClient(t, server)
+
+	// The record is never presented: the propagation check is skipped to reach the validation.
+	err = client.Challenge.SetDNS01Provider(noopProvider{},
+		dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+		dns01.WrapPreCheck(func(_, _, _ string, _ dns01.PreCheckFunc) (bool, error) {
+			return true, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.ErrorContains(t, err, "incorrect TXT record")
+}
+
+func TestServer_http01(t *testing.T) {
+	addr := freeAddress(t)
+
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	server := New(t, WithHTTPAddress(addr))
+
+	client := setupClient(t, server)
+
+	err = client.Challenge.SetHTTP01Provider(http01.NewProviderServer(host, port))
+	require.NoError(t, err)
+
+	resource, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	verifyCertificate(t, server, resource, "example.com")
+}
+
+func TestServer_tlsalpn01(t *testing.T) {
+	addr := freeAddress(t)
+
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	server := New(t, WithTLSAddress(addr))
+
+	client := setupClient(t, server)
+
+	err = client.Challenge.SetTLSALPN01Provider(tlsalpn01.NewProviderServer(host, port))
+	require.NoError(t, err)
+
+	resource, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	verifyCertificate(t, server, resource, "example.com")
+}
+
+func TestServer_renewalInfo_revoke(t *testing.T) {
+	server := New(t, SkipValidation(), WithCertificateValidity(24*time.Hour))
+
+	client := setupClient(t, server)
+
+	err := client.Challenge.SetDNS01Provider(noopProvider{},
+		dns01.WrapPreCheck(func(_, _, _ string, _ dns01.PreCheckFunc) (bool, error) {
+			return true, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	resource, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	cert := verifyCertificate(t, server, resource, "example.com")
+
+	assert.Equal(t, 24*time.Hour, cert.NotAfter.Sub(cert.NotBefore).Round(time.Hour))
+
+	renewalInfo, err := client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	require.NoError(t, err)
+
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+
+	assert.WithinDuration(t, cert.NotAfter.Add(-lifetime/3), renewalInfo.SuggestedWindow.Start, time.Second)
+	assert.Equal(t, 6*time.Hour, renewalInfo.RetryAfter)
+
+	err = client.Certificate.Revoke(resource.Certificate)
+	require.NoError(t, err)
+
+	assert.True(t, server.Revoked(cert))
+
+	err = client.Certificate.Revoke(resource.Certificate)
+	require.ErrorContains(t, err, "already revoked")
+
+	renewalInfo, err = client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	require.NoError(t, err)
+
+	assert.True(t, renewalInfo.SuggestedWindow.Start.Before(time.Now()))
+}
@@ -0,0 +1,191 @@
+package acmeserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// The challenge types.
+// The constants of the challenge packages are not used: their tests may use the server.
+const (
+	challengeHTTP01    = "http-01"
+	challengeDNS01     = "dns-01"
+	challengeTLSALPN01 = "tls-alpn-01"
+)
+
+const validationTimeout = 10 * time.Second
+
+// idPeAcmeIdentifierV1 is the OID of the ACME extension of the TLS-ALPN-01 certificates (RFC 8737).
+var idPeAcmeIdentifierV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+// validate validates a challenge of the identifier.
+func (s *Server) validate(typ, identifier, token, keyAuth string) error {
+	if s.skipValidation {
+		return nil
+	}
+
+	switch typ {
+	case challengeDNS01:
+		return validateDNS01(s.dnsResolver, identifier, keyAuth)
+
+	case challengeHTTP01:
+		return validateHTTP01(validationAddress(s.httpAddress, identifier, "80"), identifier, token, keyAuth)
+
+	case challengeTLSALPN01:
+		return validateTLSALPN01(validationAddress(s.tlsAddress, identifier, "443"), identifier, keyAuth)
+
+	default:
+		return fmt.Errorf("unsupported challenge type: %s", typ)
+	}
+}
+
+func validateDNS01(resolver, domain, keyAuth string) error {
+	if resolver == "" {
+		return errors.New("no DNS resolver to validate the DNS-01 challenge")
+	}
+
+	fqdn := dns.Fqdn("_acme-challenge." + domain)
+
+	m := new(dns.Msg)
+	m.SetQuestion(fqdn, dns.TypeTXT)
+
+	client := &dns.Client{Timeout: validationTimeout}
+
+	in, _, err := client.Exchange(m, resolver)
+	if err != nil {
+		return fmt.Errorf("DNS query for %s: %w", fqdn, err)
+	}
+
+	if in.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("DNS query for %s: %s", fqdn, dns.RcodeToString[in.Rcode])
+	}
+
+	var found []string
+
+	for _, rr := range in.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			found = append(found, strings.Join(txt.Txt, ""))
+		}
+	}
+
+	// The DNS-01 solver publishes the key authorization itself (see `dns01.GetChallengeInfo`).
+	if !slices.Contains(found, keyAuth) {
+		return fmt.Errorf("incorrect TXT record for %s: expected %q, found %q", fqdn, keyAuth, found)
+	}
+
+	return nil
+}
+
+func validateHTTP01(addr, domain, token, keyAuth string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), validationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/.well-known/acme-challenge/"+token, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	req.Host = domain
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP-01 request to %s: %w", addr, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP-01 request to %s: unexpected status code: %d", addr, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(body)) != keyAuth {
+		return fmt.Errorf("incorrect HTTP-01 response: expected %q, got %q", keyAuth, body)
+	}
+
+	return nil
+}
+
+func validateTLSALPN01(addr, domain, keyAuth string) error {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: validationTimeout},
+		Config: &tls.Config{
+			ServerName: domain,
+			NextProtos: []string{"acme-tls/1"},
+			// The challenge certificate is self-signed.
+			InsecureSkipVerify: true, //nolint:gosec // The certificate is checked below.
+		},
+	}
+
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("TLS-ALPN-01 connection to %s: %w", addr, err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	state := conn.(*tls.Conn).ConnectionState()
+
+	if state.NegotiatedProtocol != "acme-tls/1" {
+		return fmt.Errorf("TLS-ALPN-01: unexpected protocol: %q", state.NegotiatedProtocol)
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("TLS-ALPN-01: no certificate")
+	}
+
+	cert := state.PeerCertificates[0]
+
+	if !slices.Contains(cert.DNSNames, domain) && !slices.ContainsFunc(cert.IPAddresses, func(ip net.IP) bool { return ip.String() == domain }) {
+		return fmt.Errorf("TLS-ALPN-01: the certificate is not for %s", domain)
+	}
+
+	hash := sha256.Sum256([]byte(keyAuth))
+
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(idPeAcmeIdentifierV1) {
+			continue
+		}
+
+		var value []byte
+
+		_, err = asn1.Unmarshal(ext.Value, &value)
+		if err != nil {
+			return fmt.Errorf("TLS-ALPN-01: invalid ACME extension: %w", err)
+		}
+
+		if !bytes.Equal(value, hash[:]) {
+			return errors.New("TLS-ALPN-01: incorrect key authorization")
+		}
+
+		return nil
+	}
+
+	return errors.New("TLS-ALPN-01: no ACME extension in the certificate")
+}
+
+// validationAddress returns the address to request: the defined address, or the identifier with the default port.
+func validationAddress(addr, identifier, port string) string {
+	if addr != "" {
+		return addr
+	}
+
+	return net.JoinHostPort(identifier, port)
+}
@@ -0,0 +1,44 @@
+package tester
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// DefaultConcurrency the default number of goroutines used by RunConcurrently.
+const DefaultConcurrency = 8
+
+// RunConcurrently calls fn from several goroutines at the same time, and fails the test if a call fails.
+// The index of the goroutine is passed to fn (ex: to use a different token by challenge).
+// Run with `go test -race` to detect the unsynchronized state of the DNS providers:
+//
+//	tester.RunConcurrently(t, tester.DefaultConcurrency, func(i int) error {
+//		return provider.Present("example.com", fmt.Sprintf("token%d", i), "123d==")
+//	})
+func RunConcurrently(t *testing.T, n int, fn func(i int) error) {
+	t.Helper()
+
+	start := make(chan struct{})
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+
+	for i := range n {
+		wg.Go(func() {
+			// All the goroutines start together to maximize the overlap of the calls.
+			<-start
+
+			errs[i] = fn(i)
+		})
+	}
+
+	close(start)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Error(fmt.Errorf("goroutine %d: %w", i, err))
+		}
+	}
+}
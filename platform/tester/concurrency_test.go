@@ -0,0 +1,26 @@
+package tester_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	var calls atomic.Int32
+
+	seen := make([]bool, tester.DefaultConcurrency)
+
+	tester.RunConcurrently(t, tester.DefaultConcurrency, func(i int) error {
+		calls.Add(1)
+
+		seen[i] = true
+
+		return nil
+	})
+
+	assert.EqualValues(t, tester.DefaultConcurrency, calls.Load())
+	assert.NotContains(t, seen, false)
+}
@@ -0,0 +1,175 @@
+// Package fakedns provides an in-memory DNS provider for the integration tests.
+//
+// The provider stores the challenge records in memory,
+// and a real DNS server (UDP and TCP) answers the TXT queries of these records.
+// The server can be used as the DNS resolver of an ACME test server (ex: `pebble -dnsserver <addr>`),
+// and as the recursive nameserver of the DNS-01 challenge:
+//
+//	provider, err := fakedns.NewProvider("127.0.0.1:0")
+//	if err != nil {
+//		return err
+//	}
+//	defer func() { _ = provider.Close() }()
+//
+//	err = client.Challenge.SetDNS01Provider(provider,
+//		dns01.AddRecursiveNameservers([]string{provider.Addr()}),
+//		dns01.DisableAuthoritativeNssPropagationRequirement(),
+//	)
+package fakedns
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/dnsserver"
+	"github.com/miekg/dns"
+)
+
+// Default values of the provider.
+const (
+	DefaultTTL                = 60
+	DefaultPropagationTimeout = 10 * time.Second
+	DefaultPollingInterval    = 100 * time.Millisecond
+)
+
+// Provider is an in-memory DNS provider.
+type Provider struct {
+	server  *dnsserver.Server
+	records dnsserver.Records
+	zones   []string
+}
+
+// NewProvider creates a provider, and starts a DNS server on the address (ex: `127.0.0.1:0`).
+// The zones are the zones served by the server,
+// if there are no zones, every domain without challenge record is considered as the apex of a zone.
+func NewProvider(addr string, zones ...string) (*Provider, error) {
+	p := &Provider{}
+
+	for _, zone := range zones {
+		p.zones = append(p.zones, dns.CanonicalName(zone))
+	}
+
+	server, err := dnsserver.Start(addr, dns.HandlerFunc(p.serveDNS))
+	if err != nil {
+		return nil, fmt.Errorf("fakedns: %w", err)
+	}
+
+	p.server = server
+
+	return p, nil
+}
+
+// Addr returns the address of the DNS server.
+func (p *Provider) Addr() string {
+	return p.server.Addr().String()
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (p *Provider) Present(domain, _, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	p.records.Add(info.EffectiveFQDN, info.Value)
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (p *Provider) CleanUp(domain, _, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	p.records.Remove(info.EffectiveFQDN, info.Value)
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (p *Provider) Timeout() (timeout, interval time.Duration) {
+	return DefaultPropagationTimeout, DefaultPollingInterval
+}
+
+// Records returns the values of the TXT records of the FQDN.
+func (p *Provider) Records(fqdn string) []string {
+	values, _ := p.records.Get(fqdn)
+
+	return values
+}
+
+// Close stops the DNS server.
+func (p *Provider) Close() error {
+	return p.server.Shutdown(context.Background())
+}
+
+func (p *Provider) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+
+	if len(req.Question) != 1 || req.Question[0].Qclass != dns.ClassINET {
+		_ = w.WriteMsg(m.SetRcode(req, dns.RcodeRefused))
+		return
+	}
+
+	question := req.Question[0]
+	name := dns.CanonicalName(question.Name)
+
+	values, found := p.records.Get(name)
+
+	switch question.Qtype {
+	case dns.TypeTXT:
+		for _, value := range values {
+			m.Answer = append(m.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: DefaultTTL},
+				Txt: []string{value},
+			})
+		}
+
+	case dns.TypeSOA:
+		if !found && p.isZone(name) {
+			m.Answer = append(m.Answer, p.soa(name))
+		}
+	}
+
+	if len(m.Answer) == 0 {
+		if zone := p.zoneOf(name); zone != "" {
+			m.Ns = append(m.Ns, p.soa(zone))
+		}
+	}
+
+	_ = w.WriteMsg(m)
+}
+
+func (p *Provider) isZone(name string) bool {
+	if len(p.zones) == 0 {
+		return !strings.HasPrefix(name, "_acme-challenge.")
+	}
+
+	return slices.Contains(p.zones, name)
+}
+
+// zoneOf returns the zone of a name, or an empty string if the name is not served.
+func (p *Provider) zoneOf(name string) string {
+	for domain := range dns01.DomainsSeq(name) {
+		if p.isZone(domain) {
+			return domain
+		}
+	}
+
+	return ""
+}
+
+func (p *Provider) soa(zone string) *dns.SOA {
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: DefaultTTL},
+		Ns:      "ns.fakedns.test.",
+		Mbox:    "hostmaster.fakedns.test.",
+		Serial:  1,
+		Refresh: DefaultTTL,
+		Retry:   DefaultTTL,
+		Expire:  DefaultTTL,
+		Minttl:  DefaultTTL,
+	}
+}
@@ -0,0 +1,102 @@
+package fakedns
+
+import (
+	"testing"
+
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProvider(t *testing.T, zones ...string) *Provider {
+	t.Helper()
+
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider, err := NewProvider("127.0.0.1:0", zones...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = provider.Close() })
+
+	return provider
+}
+
+func query(t *testing.T, network, addr, name string, qType uint16) *dns.Msg {
+	t.Helper()
+
+	m := new(dns.Msg)
+	m.SetQuestion(name, qType)
+
+	client := &dns.Client{Net: network}
+
+	r, _, err := client.Exchange(m, addr)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestProvider(t *testing.T) {
+	provider := setupProvider(t)
+
+	require.NoError(t, provider.Present("example.com", "token", "a"))
+	require.NoError(t, provider.Present("example.com", "token", "b"))
+
+	assert.Equal(t, []string{"a", "b"}, provider.Records("_acme-challenge.example.com"))
+
+	for _, network := range []string{"udp", "tcp"} {
+		r := query(t, network, provider.Addr(), "_acme-challenge.example.com.", dns.TypeTXT)
+
+		require.Len(t, r.Answer, 2)
+		assert.Equal(t, []string{"a"}, r.Answer[0].(*dns.TXT).Txt)
+		assert.Equal(t, []string{"b"}, r.Answer[1].(*dns.TXT).Txt)
+	}
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "a"))
+
+	r := query(t, "udp", provider.Addr(), "_acme-challenge.example.com.", dns.TypeTXT)
+	require.Len(t, r.Answer, 1)
+
+	require.NoError(t, provider.CleanUp("example.com", "token", "b"))
+
+	assert.Empty(t, provider.Records("_acme-challenge.example.com."))
+}
+
+func TestProvider_zone(t *testing.T) {
+	dns01.ClearFqdnCache()
+	t.Cleanup(dns01.ClearFqdnCache)
+
+	provider := setupProvider(t, "example.com")
+
+	zone, err := dns01.FindZoneByFqdnCustom("_acme-challenge.www.example.com.", []string{provider.Addr()})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", zone)
+
+	r := query(t, "udp", provider.Addr(), "example.org.", dns.TypeSOA)
+	assert.Empty(t, r.Answer)
+	assert.Empty(t, r.Ns)
+}
+
+func TestProvider_defaultZones(t *testing.T) {
+	dns01.ClearFqdnCache()
+	t.Cleanup(dns01.ClearFqdnCache)
+
+	provider := setupProvider(t)
+
+	zone, err := dns01.FindZoneByFqdnCustom("_acme-challenge.www.example.org.", []string{provider.Addr()})
+	require.NoError(t, err)
+
+	assert.Equal(t, "www.example.org.", zone)
+}
+
+func TestProvider_IDN(t *testing.T) {
+	provider := setupProvider(t)
+
+	require.NoError(t, provider.Present("münchen.example", "token", "a"))
+
+	assert.Equal(t, []string{"a"}, provider.Records("_acme-challenge.xn--mnchen-3ya.example."))
+
+	r := query(t, "udp", provider.Addr(), "_acme-challenge.xn--mnchen-3ya.example.", dns.TypeTXT)
+	require.Len(t, r.Answer, 1)
+}
@@ -11,20 +11,28 @@ import (
 
 // For polls the given function 'f', once every 'interval', up to 'timeout'.
 func For(msg string, timeout, interval time.Duration, f func() (bool, error)) error {
-	log.Infof("Wait for %s [timeout: %s, interval: %s]", msg, timeout, interval)
+	log.Info("Wait for "+msg+".", "timeout", timeout, "interval", interval)
 
+	return ForWithContext(context.Background(), msg, timeout, backoff.NewConstantBackOff(interval), f)
+}
+
+// ForWithContext polls the given function 'f' up to 'timeout', or until the context is done.
+// The delays between the calls are defined by the backoff strategy.
+// The pending delay is interrupted as soon as the context is done.
+func ForWithContext(ctx context.Context, msg string, timeout time.Duration, bo backoff.BackOff, f func() (bool, error)) error {
 	var lastErr error
 
-	timeUp := time.After(timeout)
+	timeUp := time.NewTimer(timeout)
+	defer timeUp.Stop()
+
+	bo.Reset()
 
 	for {
 		select {
-		case <-timeUp:
-			if lastErr == nil {
-				return fmt.Errorf("%s: time limit exceeded", msg)
-			}
-
-			return fmt.Errorf("%s: time limit exceeded: last error: %w", msg, lastErr)
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", msg, context.Cause(ctx))
+		case <-timeUp.C:
+			return timeLimitExceeded(msg, lastErr)
 		default:
 		}
 
@@ -37,10 +45,44 @@ func For(msg string, timeout, interval time.Duration, f func() (bool, error)) er
 			lastErr = err
 		}
 
-		time.Sleep(interval)
+		next := bo.NextBackOff()
+		if next == backoff.Stop {
+			return timeLimitExceeded(msg, lastErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", msg, context.Cause(ctx))
+		case <-time.After(next):
+		}
+	}
+}
+
+// Sleep pauses the current goroutine for at least the duration d, or until the context is done.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
 	}
 }
 
+func timeLimitExceeded(msg string, lastErr error) error {
+	if lastErr == nil {
+		return fmt.Errorf("%s: time limit exceeded", msg)
+	}
+
+	return fmt.Errorf("%s: time limit exceeded: last error: %w", msg, lastErr)
+}
+
 // Retry retries the given operation until it succeeds or the context is canceled.
 // Similar to [backoff.Retry] but with a different signature.
 func Retry(ctx context.Context, operation func() error, opts ...backoff.RetryOption) error {
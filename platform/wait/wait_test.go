@@ -1,11 +1,14 @@
 package wait
 
 import (
+	"context"
 	"errors"
 	"sync/atomic"
 	"testing"
+	"testing/synctest"
 	"time"
 
+	"github.com/cenkalti/backoff/v5"
 	"github.com/stretchr/testify/require"
 )
 
@@ -119,3 +122,94 @@ func TestFor_stop_with_error(t *testing.T) {
 
 	require.EqualValues(t, 1, io.Load())
 }
+
+func TestForWithContext_canceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+
+		var io atomic.Int64
+
+		c := make(chan error)
+
+		go func() {
+			c <- ForWithContext(ctx, "test", time.Hour, backoff.NewConstantBackOff(time.Minute), func() (bool, error) {
+				io.Add(1)
+
+				return false, nil
+			})
+		}()
+
+		synctest.Wait()
+
+		cancel()
+
+		err := <-c
+		require.ErrorIs(t, err, context.Canceled)
+		require.EqualError(t, err, "test: context canceled")
+
+		require.EqualValues(t, 1, io.Load())
+	})
+}
+
+func TestForWithContext_backOff(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls []time.Duration
+
+		start := time.Now()
+
+		bo := backoff.NewExponentialBackOff()
+		bo.InitialInterval = time.Second
+		bo.Multiplier = 2
+		bo.RandomizationFactor = 0
+
+		err := ForWithContext(t.Context(), "test", 10*time.Second, bo, func() (bool, error) {
+			calls = append(calls, time.Since(start))
+
+			return false, errors.New("oops")
+		})
+		require.EqualError(t, err, "test: time limit exceeded: last error: oops")
+
+		// 0, 1, 1+2, 1+2+4, (the next call at 1+2+4+8 is after the timeout).
+		expected := []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second}
+
+		require.Equal(t, expected, calls)
+	})
+}
+
+func TestForWithContext_backOffStop(t *testing.T) {
+	var io atomic.Int64
+
+	err := ForWithContext(t.Context(), "test", time.Hour, &backoff.StopBackOff{}, func() (bool, error) {
+		io.Add(1)
+
+		return false, nil
+	})
+	require.EqualError(t, err, "test: time limit exceeded")
+
+	require.EqualValues(t, 1, io.Load())
+}
+
+func TestSleep(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		start := time.Now()
+
+		err := Sleep(t.Context(), time.Minute)
+		require.NoError(t, err)
+
+		require.Equal(t, time.Minute, time.Since(start))
+	})
+}
+
+func TestSleep_canceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		err := Sleep(ctx, time.Hour)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.Equal(t, time.Second, time.Since(start))
+	})
+}
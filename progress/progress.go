@@ -0,0 +1,136 @@
+// Package progress reports the progress of the long operations (obtaining or renewing a certificate).
+package progress
+
+import "sync"
+
+// Phase is a step of an operation.
+type Phase string
+
+// Phases.
+const (
+	// PhaseOrder the order is created.
+	PhaseOrder Phase = "order"
+
+	// PhaseAuthorization the challenges are solved.
+	PhaseAuthorization Phase = "authorization"
+
+	// PhasePropagation waiting for the propagation of a DNS record (DNS-01).
+	PhasePropagation Phase = "propagation"
+
+	// PhaseValidation waiting for the validation of a challenge by the CA.
+	PhaseValidation Phase = "validation"
+
+	// PhaseFinalization the CSR is sent and the certificate is downloaded.
+	PhaseFinalization Phase = "finalization"
+
+	// PhaseDone the certificate has been obtained.
+	PhaseDone Phase = "done"
+)
+
+// The percentages reached at the beginning of the phases.
+// The authorization phase goes from percentAuthorization to percentFinalization, according to the number of solved authorizations.
+const (
+	percentOrder         = 0
+	percentAuthorization = 10
+	percentFinalization  = 80
+	percentDone          = 100
+)
+
+// Progress describes the state of an operation.
+type Progress struct {
+	Phase Phase
+
+	// Percent the overall progress of the operation, from 0 to 100.
+	Percent int
+
+	// Domain the domain concerned by the phase, if any.
+	Domain string
+
+	// Attempt the number of the attempt when the phase is a polling (propagation, validation, finalization).
+	// 0 when not applicable.
+	Attempt int
+}
+
+// Reporter receives the progress of the operations.
+// The calls are synchronous: a Reporter must not block.
+type Reporter interface {
+	Report(p Progress)
+}
+
+// ReporterFunc is an adapter to allow the use of ordinary functions as Reporter.
+type ReporterFunc func(p Progress)
+
+// Report implements Reporter.
+func (f ReporterFunc) Report(p Progress) {
+	f(p)
+}
+
+// Tracker computes the overall progress of an operation and forwards it to a Reporter.
+// A nil Tracker does nothing.
+type Tracker struct {
+	reporter Reporter
+
+	mu      sync.Mutex
+	percent int
+}
+
+// NewTracker creates a new Tracker.
+// Returns nil if the reporter is nil.
+func NewTracker(reporter Reporter) *Tracker {
+	if reporter == nil {
+		return nil
+	}
+
+	return &Tracker{reporter: reporter}
+}
+
+// Start reports the beginning of a phase.
+func (t *Tracker) Start(phase Phase, domain string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch phase {
+	case PhaseOrder:
+		t.percent = percentOrder
+	case PhaseAuthorization:
+		t.percent = percentAuthorization
+	case PhaseFinalization:
+		t.percent = percentFinalization
+	case PhaseDone:
+		t.percent = percentDone
+	default:
+		// The other phases are part of the authorization phase.
+	}
+
+	t.reporter.Report(Progress{Phase: phase, Percent: t.percent, Domain: domain})
+}
+
+// Authorized reports that an authorization has been solved (or has failed), among the total number of authorizations.
+func (t *Tracker) Authorized(domain string, done, total int) {
+	if t == nil || total <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.percent = percentAuthorization + (percentFinalization-percentAuthorization)*min(done, total)/total
+
+	t.reporter.Report(Progress{Phase: PhaseAuthorization, Percent: t.percent, Domain: domain})
+}
+
+// Attempt reports an attempt of a polling phase (propagation, validation, finalization).
+func (t *Tracker) Attempt(phase Phase, domain string, attempt int) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.reporter.Report(Progress{Phase: phase, Percent: t.percent, Domain: domain, Attempt: attempt})
+}
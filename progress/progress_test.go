@@ -0,0 +1,50 @@
+package progress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	var reports []Progress
+
+	tracker := NewTracker(ReporterFunc(func(p Progress) {
+		reports = append(reports, p)
+	}))
+
+	tracker.Start(PhaseOrder, "")
+	tracker.Start(PhaseAuthorization, "")
+	tracker.Attempt(PhasePropagation, "a.example.com", 1)
+	tracker.Authorized("a.example.com", 1, 2)
+	tracker.Attempt(PhaseValidation, "b.example.com", 2)
+	tracker.Authorized("b.example.com", 2, 2)
+	tracker.Start(PhaseFinalization, "")
+	tracker.Attempt(PhaseFinalization, "", 1)
+	tracker.Start(PhaseDone, "")
+
+	expected := []Progress{
+		{Phase: PhaseOrder, Percent: 0},
+		{Phase: PhaseAuthorization, Percent: 10},
+		{Phase: PhasePropagation, Percent: 10, Domain: "a.example.com", Attempt: 1},
+		{Phase: PhaseAuthorization, Percent: 45, Domain: "a.example.com"},
+		{Phase: PhaseValidation, Percent: 45, Domain: "b.example.com", Attempt: 2},
+		{Phase: PhaseAuthorization, Percent: 80, Domain: "b.example.com"},
+		{Phase: PhaseFinalization, Percent: 80},
+		{Phase: PhaseFinalization, Percent: 80, Attempt: 1},
+		{Phase: PhaseDone, Percent: 100},
+	}
+
+	assert.Equal(t, expected, reports)
+}
+
+func TestTracker_nil(t *testing.T) {
+	tracker := NewTracker(nil)
+
+	assert.Nil(t, tracker)
+
+	// Must not panic.
+	tracker.Start(PhaseOrder, "")
+	tracker.Authorized("example.com", 1, 1)
+	tracker.Attempt(PhaseValidation, "example.com", 1)
+}
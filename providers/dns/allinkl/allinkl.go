@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
@@ -33,23 +34,27 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Login              string
-	Password           string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
+	Login              string        `env:"LOGIN,required"`
+	Password           string        `env:"PASSWORD,required"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
 	TTL                int
-	HTTPClient         *http.Client
+	HTTPClient         *http.Client `env:"HTTP_TIMEOUT" default:"30s"`
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -66,15 +71,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for all-inkl.
 // Credentials must be passed in the environment variable: ALL_INKL_LOGIN, ALL_INKL_PASSWORD.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvLogin, EnvPassword)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("allinkl: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.Login = values[EnvLogin]
-	config.Password = values[EnvPassword]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -198,7 +201,7 @@ func (d *DNSProvider) findZone(ctx context.Context, fqdn string) (string, error)
 	for z := range dns01.DomainsSeq(fqdn) {
 		_, errG := d.client.GetDNSSettings(ctx, z, "")
 		if errG != nil {
-			log.Infof("get DNS settings zone[%q] %v", z, errG)
+			log.OrDefault(d.config.Logger).Info("allinkl: get DNS settings.", "zone", z, "error", errG)
 			continue
 		}
 
@@ -146,9 +146,13 @@ func (c *Client) doRequest(ctx context.Context, action string, requestParams, re
 
 func (c *Client) do(req *http.Request, result any) error {
 	c.muFloodTime.Lock()
-	time.Sleep(time.Until(c.floodTime))
+	err := wait.Sleep(req.Context(), time.Until(c.floodTime))
 	c.muFloodTime.Unlock()
 
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return backoff.Permanent(errutils.NewHTTPDoError(req, err))
@@ -30,23 +30,23 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Username string
-	Password string
+	Username string `env:"USERNAME,required"`
+	Password string `env:"PASSWORD,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"6m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 6*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PollingInterval: dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -57,15 +57,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for ArtFiles.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvUsername, EnvPassword)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("artfiles: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.Username = values[EnvUsername]
-	config.Password = values[EnvPassword]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -45,10 +45,10 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 120*time.Second),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 2*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
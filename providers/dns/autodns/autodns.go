@@ -53,10 +53,10 @@ func NewDefaultConfig() *Config {
 		Endpoint:           endpoint,
 		Context:            env.GetOrDefaultInt(EnvAPIEndpointContext, internal.DefaultEndpointContext),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 600),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 2*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
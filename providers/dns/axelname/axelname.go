@@ -29,25 +29,26 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Nickname string
-	Token    string
+	Nickname string `env:"NICKNAME,required"`
+	Token    string `env:"TOKEN,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -58,15 +59,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for Axelname.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvNickname, EnvToken)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("axelname: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.Nickname = values[EnvNickname]
-	config.Token = values[EnvToken]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -29,26 +29,26 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	PersonalToken string
-	PageSize      int
+	PersonalToken string `env:"PERSONAL_TOKEN,required"`
+	PageSize      int    `env:"PAGE_SIZE" default:"50"`
 
-	PollingInterval    time.Duration
-	PropagationTimeout time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	TTL                int           `env:"TTL"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		PageSize:           env.GetOrDefaultInt(EnvPageSize, 50),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PollingInterval:    dns01.DefaultPollingInterval,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		TTL:                dns01.DefaultTTL,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -60,14 +60,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for Azion.
 // Credentials must be passed in the environment variable: AZION_PERSONAL_TOKEN.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvPersonalToken)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("azion: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.PersonalToken = values[EnvPersonalToken]
-
 	return NewDNSProviderConfig(config)
 }
 
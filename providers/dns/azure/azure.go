@@ -72,8 +72,8 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		ZoneName:                env.GetOrFile(EnvZoneName),
 		TTL:                     env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout:      env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:         env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
+		PropagationTimeout:      env.GetOrDefaultDuration(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:         env.GetOrDefaultDuration(EnvPollingInterval, 2*time.Second),
 		MetadataEndpoint:        env.GetOrFile(EnvMetadataEndpoint),
 		ResourceManagerEndpoint: aazure.PublicCloud.ResourceManagerEndpoint,
 		ActiveDirectoryEndpoint: aazure.PublicCloud.ActiveDirectoryEndpoint,
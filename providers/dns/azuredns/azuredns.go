@@ -95,8 +95,8 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		ZoneName:           env.GetOrFile(EnvZoneName),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 2*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 2*time.Second),
 		Environment:        cloud.AzurePublic,
 	}
 }
@@ -157,7 +157,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 	config.SystemAccessToken = pipelineValues[EnvSystemAccessToken]
 
 	config.AuthMethod = env.GetOrFile(EnvAuthMethod)
-	config.AuthMSITimeout = env.GetOrDefaultSecond(EnvAuthMSITimeout, 2*time.Second)
+	config.AuthMSITimeout = env.GetOrDefaultDuration(EnvAuthMSITimeout, 2*time.Second)
 
 	return NewDNSProviderConfig(config)
 }
@@ -32,25 +32,22 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Username string
-	Password string
+	Username string `env:"USERNAME,required"`
+	Password string `env:"PASSWORD,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"5m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"30s"`
+	TTL                int           `env:"TTL" default:"300"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 5*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 30*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -63,15 +60,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // BEGET_USERNAME and BEGET_PASSWORD.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvUsername, EnvPassword)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("beget: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.Username = values[EnvUsername]
-	config.Password = values[EnvPassword]
-
 	return NewDNSProviderConfig(config)
 }
 
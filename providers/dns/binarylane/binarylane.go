@@ -29,24 +29,24 @@ const (
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIToken string
+	APIToken string `env:"API_TOKEN,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL" default:"3600"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 3600),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -60,14 +60,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for Binary Lane.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIToken)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("binarylane: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIToken = values[EnvAPIToken]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -29,21 +29,22 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	BaseURL            string
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	BaseURL            string        `env:"MANAGER_ADDRESS,required"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"1m"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, time.Minute),
-		},
+	config := &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -55,14 +56,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for Bindman.
 // BINDMAN_MANAGER_ADDRESS should have the scheme, hostname, and port (if required) of the authoritative Bindman Manager server.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvManagerAddress)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("bindman: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.BaseURL = values[EnvManagerAddress]
-
 	return NewDNSProviderConfig(config)
 }
 
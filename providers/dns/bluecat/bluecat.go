@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -49,16 +50,19 @@ type Config struct {
 	HTTPClient         *http.Client
 	Debug              bool
 	SkipDeploy         bool
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 		Debug:      env.GetOrDefaultBool(EnvDebug, false),
 		SkipDeploy: env.GetOrDefaultBool(EnvSkipDeploy, false),
@@ -138,7 +142,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	if d.config.Debug {
-		log.Infof("fqdn: %s; viewID: %d; ZoneID: %d; zone: %s", info.EffectiveFQDN, viewID, parentZoneID, name)
+		log.OrDefault(d.config.Logger).Info("bluecat: zone found.", "fqdn", info.EffectiveFQDN, "viewID", viewID, "zoneID", parentZoneID, "zone", name)
 	}
 
 	txtRecord := internal.Entity{
@@ -33,25 +33,24 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey      string
-	APIUsername string
+	APIKey      string `env:"API_KEY,required"`
+	APIUsername string `env:"API_USERNAME,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"10m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL" default:"600"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 600),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 10*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PollingInterval: dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -66,15 +65,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for BrandIT.
 // Credentials must be passed in the environment variables: BRANDIT_API_KEY, BRANDIT_API_USERNAME.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIKey, EnvAPIUsername)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("brandit: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIKey = values[EnvAPIKey]
-	config.APIUsername = values[EnvAPIUsername]
-
 	return NewDNSProviderConfig(config)
 }
 
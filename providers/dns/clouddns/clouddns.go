@@ -33,26 +33,23 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the DNSProvider.
 type Config struct {
-	ClientID string
-	Email    string
-	Password string
-
-	TTL                int
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	HTTPClient         *http.Client
+	ClientID string `env:"CLIENT_ID,required"`
+	Email    string `env:"EMAIL,required"`
+	Password string `env:"PASSWORD,required"`
+
+	TTL                int           `env:"TTL" default:"300"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"120s"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"5s"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -65,16 +62,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // CLOUDDNS_CLIENT_ID, CLOUDDNS_EMAIL, CLOUDDNS_PASSWORD.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvClientID, EnvEmail, EnvPassword)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("clouddns: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.ClientID = values[EnvClientID]
-	config.Email = values[EnvEmail]
-	config.Password = values[EnvPassword]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/cloudflare/internal"
+	"github.com/digicert/lego/v4/providers/dns/internal/zonecache"
 )
 
 // Environment variables names.
@@ -44,9 +46,13 @@ const (
 
 const (
 	minTTL = 120
+	maxTTL = 86400
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ dns01.RecordLister        = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -62,16 +68,19 @@ type Config struct {
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		TTL:                env.GetOneWithFallback(EnvTTL, minTTL, strconv.Atoi, altEnvName(EnvTTL)),
-		PropagationTimeout: env.GetOneWithFallback(EnvPropagationTimeout, 2*time.Minute, env.ParseSecond, altEnvName(EnvPropagationTimeout)),
-		PollingInterval:    env.GetOneWithFallback(EnvPollingInterval, dns01.DefaultPollingInterval, env.ParseSecond, altEnvName(EnvPollingInterval)),
+		TTL:                env.ClampInt(EnvTTL, env.GetOneWithFallback(EnvTTL, minTTL, strconv.Atoi, altEnvName(EnvTTL)), minTTL, maxTTL),
+		PropagationTimeout: env.GetOneWithFallback(EnvPropagationTimeout, 2*time.Minute, env.ParseDuration, altEnvName(EnvPropagationTimeout)),
+		PollingInterval:    env.GetOneWithFallback(EnvPollingInterval, dns01.DefaultPollingInterval, env.ParseDuration, altEnvName(EnvPollingInterval)),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOneWithFallback(EnvHTTPTimeout, 30*time.Second, env.ParseSecond, altEnvName(EnvHTTPTimeout)),
+			Timeout: env.GetOneWithFallback(EnvHTTPTimeout, 30*time.Second, env.ParseDuration, altEnvName(EnvHTTPTimeout)),
 		},
 	}
 }
@@ -140,6 +149,8 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("cloudflare: %w", err)
 	}
 
+	client.zoneCache = zonecache.Get("cloudflare", config.BaseURL, config.AuthEmail, config.AuthKey, config.AuthToken, config.ZoneToken)
+
 	return &DNSProvider{
 		client:    client,
 		config:    config,
@@ -185,7 +196,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	d.recordIDs[token] = response.ID
 	d.recordIDsMu.Unlock()
 
-	log.Infof("cloudflare: new record for %s, ID %s", domain, response.ID)
+	log.OrDefault(d.config.Logger).Info("cloudflare: new record.", "domain", domain, "recordID", response.ID)
 
 	return nil
 }
@@ -217,7 +228,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	err = d.client.DeleteDNSRecord(ctx, zoneID, recordID)
 	if err != nil {
-		log.Printf("cloudflare: failed to delete TXT record: %v", err)
+		log.OrDefault(d.config.Logger).Warn("cloudflare: failed to delete TXT record.", "error", err)
 	}
 
 	// Delete record ID from map
@@ -228,6 +239,63 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
+// ListChallengeRecords returns the challenge TXT records of the zone.
+func (d *DNSProvider) ListChallengeRecords(ctx context.Context, zone string) ([]dns01.ChallengeRecord, error) {
+	zoneID, err := d.client.ZoneIDByName(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to find zone %s: %w", zone, err)
+	}
+
+	records, err := d.client.ListTXTRecords(ctx, zoneID, "_acme-challenge")
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to list TXT records: %w", err)
+	}
+
+	var results []dns01.ChallengeRecord
+
+	for _, record := range records {
+		result := dns01.ChallengeRecord{
+			ID:    record.ID,
+			FQDN:  dns01.ToFqdn(record.Name),
+			Value: strings.Trim(record.Content, `"`),
+		}
+
+		if record.CreatedOn != nil {
+			result.CreatedAt = *record.CreatedOn
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// DeleteChallengeRecord removes a challenge TXT record of the zone.
+func (d *DNSProvider) DeleteChallengeRecord(ctx context.Context, zone string, record dns01.ChallengeRecord) error {
+	zoneID, err := d.client.ZoneIDByName(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("cloudflare: failed to find zone %s: %w", zone, err)
+	}
+
+	err = d.client.DeleteDNSRecord(ctx, zoneID, record.ID)
+	if err != nil {
+		return fmt.Errorf("cloudflare: failed to delete TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// ZoneNameservers returns the name servers assigned by Cloudflare to the zone,
+// used to verify that the zone is served by Cloudflare.
+func (d *DNSProvider) ZoneNameservers(zone string) ([]string, error) {
+	nameservers, err := d.client.ZoneNameservers(context.Background(), zone)
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to find zone %s: %w", zone, err)
+	}
+
+	return nameservers, nil
+}
+
 func altEnvName(v string) string {
 	return strings.ReplaceAll(v, envNamespace, altEnvNamespace)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -364,3 +365,42 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 	err := provider.CleanUp("example.com", token, "123d==")
 	require.NoError(t, err)
 }
+
+func TestDNSProvider_ZoneNameservers(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com").
+				With("per_page", "50")).
+		Build(t)
+
+	nameservers, err := provider.ZoneNameservers("example.com.")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"bob.ns.cloudflare.com", "lola.ns.cloudflare.com"}, nameservers)
+}
+
+func TestDNSProvider_ListChallengeRecords(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com").
+				With("per_page", "50")).
+		Route("GET /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+			servermock.ResponseFromInternal("list_records.json")).
+		Build(t)
+
+	records, err := provider.ListChallengeRecords(t.Context(), "example.com.")
+	require.NoError(t, err)
+
+	expected := []dns01.ChallengeRecord{{
+		ID:        "372e67954025e0ba6aaa6d586b9e0b59",
+		FQDN:      "_acme-challenge.example.com.",
+		Value:     "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+		CreatedAt: time.Date(2014, time.January, 1, 5, 20, 0, 123450000, time.UTC),
+	}}
+
+	assert.Equal(t, expected, records)
+}
@@ -15,6 +15,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
@@ -100,6 +101,41 @@ func (c *Client) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) e
 	return c.do(req, nil)
 }
 
+// ListTXTRecords returns the TXT records of a zone whose name starts with the given prefix.
+// https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/list/
+func (c *Client) ListTXTRecords(ctx context.Context, zoneID, prefix string) ([]Record, error) {
+	var records []Record
+
+	for page := 1; ; page++ {
+		endpoint := c.baseURL.JoinPath("zones", zoneID, "dns_records")
+
+		query := endpoint.Query()
+		query.Set("type", "TXT")
+		query.Set("name.startswith", prefix)
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", "100")
+		endpoint.RawQuery = query.Encode()
+
+		req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result APIResponse[[]Record]
+
+		err = c.do(req, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, result.Result...)
+
+		if result.ResultInfo == nil || page >= result.ResultInfo.TotalPages {
+			return records, nil
+		}
+	}
+}
+
 // ZonesByName returns a list of zones matching the given name.
 // https://developers.cloudflare.com/api/resources/zones/methods/list/
 func (c *Client) ZonesByName(ctx context.Context, name string) ([]Zone, error) {
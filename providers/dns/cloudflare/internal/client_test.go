@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -103,6 +104,34 @@ func TestClient_DeleteDNSRecord_error(t *testing.T) {
 	require.EqualError(t, err, "[status code 400] 6003: Invalid request headers; 6103: Invalid format for X-Auth-Key header")
 }
 
+func TestClient_ListTXTRecords(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+			servermock.ResponseFromFixture("list_records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("type", "TXT").
+				With("name.startswith", "_acme-challenge").
+				With("page", "1").
+				With("per_page", "100")).
+		Build(t)
+
+	records, err := client.ListTXTRecords(t.Context(), "023e105f4ecef8ad9ca31a8372d0c353", "_acme-challenge")
+	require.NoError(t, err)
+
+	createdOn := time.Date(2014, time.January, 1, 5, 20, 0, 123450000, time.UTC)
+
+	expected := []Record{{
+		ID:        "372e67954025e0ba6aaa6d586b9e0b59",
+		Name:      "_acme-challenge.example.com",
+		TTL:       120,
+		Type:      "TXT",
+		Content:   `"ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY"`,
+		CreatedOn: &createdOn,
+	}}
+
+	assert.Equal(t, expected, records)
+}
+
 func TestClient_ZonesByName(t *testing.T) {
 	client := mockBuilder().
 		Route("GET /zones",
@@ -157,6 +186,7 @@ func TestClient_ZonesByName(t *testing.T) {
 				ID: "023e105f4ecef8ad9ca31a8372d0c353",
 			},
 			Type:              "full",
+			NameServers:       []string{"bob.ns.cloudflare.com", "lola.ns.cloudflare.com"},
 			VanityNameServers: []string{"ns1.example.com", "ns2.example.com"},
 		},
 	}
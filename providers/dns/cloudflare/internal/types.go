@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Record struct {
@@ -12,6 +13,8 @@ type Record struct {
 	Type    string `json:"type,omitempty"`
 	Comment string `json:"comment,omitempty"`
 	Content string `json:"content,omitempty"`
+
+	CreatedOn *time.Time `json:"created_on,omitempty"`
 }
 
 type APIResponse[T any] struct {
@@ -76,6 +79,7 @@ type Zone struct {
 	Tenant            Tenant     `json:"tenant"`
 	TenantUnit        TenantUnit `json:"tenant_unit"`
 	Type              string     `json:"type"`
+	NameServers       []string   `json:"name_servers"`
 	VanityNameServers []string   `json:"vanity_name_servers"`
 }
 
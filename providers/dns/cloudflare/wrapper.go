@@ -7,6 +7,7 @@ import (
 
 	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/providers/dns/cloudflare/internal"
+	"github.com/digicert/lego/v4/providers/dns/internal/zonecache"
 )
 
 type metaClient struct {
@@ -15,6 +16,8 @@ type metaClient struct {
 
 	zones   map[string]string // caches calls to ZoneIDByName, see lookupZoneID()
 	zonesMu *sync.RWMutex
+
+	zoneCache *zonecache.Cache // shared cache of the zone IDs (opt-in)
 }
 
 func newClient(config *Config) (*metaClient, error) {
@@ -77,6 +80,10 @@ func (m *metaClient) DeleteDNSRecord(ctx context.Context, zoneID, recordID strin
 	return m.clientEdit.DeleteDNSRecord(ctx, zoneID, recordID)
 }
 
+func (m *metaClient) ListTXTRecords(ctx context.Context, zoneID, prefix string) ([]internal.Record, error) {
+	return m.clientEdit.ListTXTRecords(ctx, zoneID, prefix)
+}
+
 func (m *metaClient) ZoneIDByName(ctx context.Context, fdqn string) (string, error) {
 	m.zonesMu.RLock()
 	id := m.zones[fdqn]
@@ -86,12 +93,14 @@ func (m *metaClient) ZoneIDByName(ctx context.Context, fdqn string) (string, err
 		return id, nil
 	}
 
-	zones, err := m.clientRead.ZonesByName(ctx, dns01.UnFqdn(fdqn))
-	if err != nil {
-		return "", err
-	}
+	id, err := m.zoneCache.Lookup(ctx, fdqn, func(ctx context.Context) (string, error) {
+		zones, err := m.clientRead.ZonesByName(ctx, dns01.UnFqdn(fdqn))
+		if err != nil {
+			return "", err
+		}
 
-	id, err = extractZoneID(zones)
+		return extractZoneID(zones)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -103,6 +112,21 @@ func (m *metaClient) ZoneIDByName(ctx context.Context, fdqn string) (string, err
 	return id, nil
 }
 
+func (m *metaClient) ZoneNameservers(ctx context.Context, fdqn string) ([]string, error) {
+	zones, err := m.clientRead.ZonesByName(ctx, dns01.UnFqdn(fdqn))
+	if err != nil {
+		return nil, err
+	}
+
+	// The same checks as for the zone ID.
+	_, err = extractZoneID(zones)
+	if err != nil {
+		return nil, err
+	}
+
+	return zones[0].NameServers, nil
+}
+
 func extractZoneID(res []internal.Zone) (string, error) {
 	switch len(res) {
 	case 0:
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -43,16 +44,19 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 180*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 180*time.Second),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -176,7 +180,7 @@ func (d *DNSProvider) waitNameservers(ctx context.Context, domain string, zone *
 				return fmt.Errorf("nameserver sync on %s: %w", domain, err)
 			}
 
-			log.Infof("[%s] Sync %d/%d complete", domain, syncProgress.Updated, syncProgress.Total)
+			log.OrDefault(d.config.Logger).Info("cloudns: Sync progress.", "domain", domain, "updated", syncProgress.Updated, "total", syncProgress.Total)
 
 			if !syncProgress.Complete {
 				return fmt.Errorf("nameserver sync on %s not complete", domain)
@@ -36,28 +36,27 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	ServiceInstanceID string
-	KeyID             string
-	Secret            string
-
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	SequenceInterval   time.Duration
-	HTTPClient         *http.Client
-	TTL                int
+	ServiceInstanceID string `env:"SERVICE_INSTANCE_ID,required"`
+	KeyID             string `env:"KEY_ID,required"`
+	Secret            string `env:"SECRET,required"`
+
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"5m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"5s"`
+	SequenceInterval   time.Duration `env:"SEQUENCE_INTERVAL"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
+	TTL                int           `env:"TTL"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 5*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
-		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		TTL:              dns01.DefaultTTL,
+		SequenceInterval: dns01.DefaultPropagationTimeout,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 type DNSProvider struct {
@@ -72,16 +71,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // CLOUDRU_SERVICE_INSTANCE_ID, CLOUDRU_KEY_ID, and CLOUDRU_SECRET.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvServiceInstanceID, EnvKeyID, EnvSecret)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("cloudru: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.ServiceInstanceID = values[EnvServiceInstanceID]
-	config.KeyID = values[EnvKeyID]
-	config.Secret = values[EnvSecret]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -31,49 +32,55 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+// pageSize is the number of items requested by page, the API paginates the lists of domains and records.
+const pageSize = 100
+
+var (
+	_ challenge.ProviderTimeout    = (*DNSProvider)(nil)
+	_ challenge.ProviderConcurrent = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey             string
-	SecretKey          string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	APIKey             string        `env:"API_KEY,required"`
+	SecretKey          string        `env:"SECRET_KEY,required"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"10s"`
+	TTL                int           `env:"TTL" default:"60"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+
+	domains   []internal.Domain
+	domainsMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Constellix.
 // Credentials must be passed in the environment variables:
 // CONSTELLIX_API_KEY and CONSTELLIX_SECRET_KEY.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIKey, EnvSecretKey)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("constellix: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIKey = values[EnvAPIKey]
-	config.SecretKey = values[EnvSecretKey]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -108,39 +115,40 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// Concurrent reports that the provider is safe for concurrent use (challenge.ProviderConcurrent).
+func (d *DNSProvider) Concurrent() bool {
+	return true
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
-	if err != nil {
-		return fmt.Errorf("constellix: could not find zone for domain %q: %w", domain, err)
-	}
-
 	ctx := context.Background()
 
-	dom, err := d.client.Domains.GetByName(ctx, dns01.UnFqdn(authZone))
+	dom, err := d.getDomain(ctx, info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("constellix: failed to get domain (%s): %w", authZone, err)
+		return fmt.Errorf("constellix: %w", err)
 	}
 
-	recordName, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	recordName, err := dns01.ExtractSubDomain(info.EffectiveFQDN, dom.Name)
 	if err != nil {
 		return fmt.Errorf("constellix: %w", err)
 	}
 
-	records, err := d.client.TxtRecords.Search(ctx, dom.ID, internal.Exact, recordName)
+	record, err := d.findRecord(ctx, dom.ID, recordName)
 	if err != nil {
-		return fmt.Errorf("constellix: failed to search TXT records: %w", err)
-	}
-
-	if len(records) > 1 {
-		return errors.New("constellix: failed to get TXT records")
+		return fmt.Errorf("constellix: %w", err)
 	}
 
 	// TXT record entry already existing
-	if len(records) == 1 {
-		return d.appendRecordValue(ctx, dom, records[0].ID, info.Value)
+	if record != nil {
+		err = d.appendRecordValue(ctx, dom, record, info.Value)
+		if err != nil {
+			return fmt.Errorf("constellix: %w", err)
+		}
+
+		return nil
 	}
 
 	err = d.createRecord(ctx, dom, info.EffectiveFQDN, recordName, info.Value)
@@ -155,42 +163,24 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
-	if err != nil {
-		return fmt.Errorf("constellix: could not find zone for domain %q: %w", domain, err)
-	}
-
 	ctx := context.Background()
 
-	dom, err := d.client.Domains.GetByName(ctx, dns01.UnFqdn(authZone))
-	if err != nil {
-		return fmt.Errorf("constellix: failed to get domain (%s): %w", authZone, err)
-	}
-
-	recordName, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	dom, err := d.getDomain(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("constellix: %w", err)
 	}
 
-	records, err := d.client.TxtRecords.Search(ctx, dom.ID, internal.Exact, recordName)
+	recordName, err := dns01.ExtractSubDomain(info.EffectiveFQDN, dom.Name)
 	if err != nil {
-		return fmt.Errorf("constellix: failed to search TXT records: %w", err)
-	}
-
-	if len(records) > 1 {
-		return errors.New("constellix: failed to get TXT records")
-	}
-
-	if len(records) == 0 {
-		return nil
+		return fmt.Errorf("constellix: %w", err)
 	}
 
-	record, err := d.client.TxtRecords.Get(ctx, dom.ID, records[0].ID)
+	record, err := d.findRecord(ctx, dom.ID, recordName)
 	if err != nil {
-		return fmt.Errorf("constellix: failed to get TXT records: %w", err)
+		return fmt.Errorf("constellix: %w", err)
 	}
 
-	if !containsValue(record, info.Value) {
+	if record == nil || !containsValue(record, info.Value) {
 		return nil
 	}
 
@@ -212,6 +202,72 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
+// getDomain finds the most specific domain matching the FQDN.
+// The domains are listed only once and cached, the cache is refreshed when no domain matches.
+func (d *DNSProvider) getDomain(ctx context.Context, fqdn string) (internal.Domain, error) {
+	d.domainsMu.Lock()
+	defer d.domainsMu.Unlock()
+
+	if d.domains != nil {
+		if dom, ok := matchDomain(d.domains, fqdn); ok {
+			return dom, nil
+		}
+	}
+
+	domains, err := getAllPages(func(params *internal.PaginationParameters) ([]internal.Domain, error) {
+		return d.client.Domains.GetAll(ctx, params)
+	})
+	if err != nil {
+		return internal.Domain{}, fmt.Errorf("failed to get domains: %w", err)
+	}
+
+	d.domains = domains
+
+	dom, ok := matchDomain(d.domains, fqdn)
+	if !ok {
+		return internal.Domain{}, fmt.Errorf("domain not found for %q", fqdn)
+	}
+
+	return dom, nil
+}
+
+// findRecord lists the TXT records of the domain (the values are included),
+// instead of a search followed by a get.
+func (d *DNSProvider) findRecord(ctx context.Context, domainID int64, recordName string) (*internal.Record, error) {
+	records, err := getAllPages(func(params *internal.PaginationParameters) ([]internal.Record, error) {
+		return d.client.TxtRecords.GetAll(ctx, domainID, params)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TXT records: %w", err)
+	}
+
+	for _, record := range records {
+		if record.Name == recordName {
+			return &record, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getAllPages requests the pages until a page is not full.
+func getAllPages[T any](fn func(params *internal.PaginationParameters) ([]T, error)) ([]T, error) {
+	var all []T
+
+	for offset := 0; ; offset += pageSize {
+		page, err := fn(&internal.PaginationParameters{Offset: offset, Max: pageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, page...)
+
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
+
 func (d *DNSProvider) createRecord(ctx context.Context, dom internal.Domain, fqdn, recordName, value string) error {
 	request := internal.RecordRequest{
 		Name: recordName,
@@ -229,12 +285,7 @@ func (d *DNSProvider) createRecord(ctx context.Context, dom internal.Domain, fqd
 	return nil
 }
 
-func (d *DNSProvider) appendRecordValue(ctx context.Context, dom internal.Domain, recordID int64, value string) error {
-	record, err := d.client.TxtRecords.Get(ctx, dom.ID, recordID)
-	if err != nil {
-		return fmt.Errorf("failed to get TXT records: %w", err)
-	}
-
+func (d *DNSProvider) appendRecordValue(ctx context.Context, dom internal.Domain, record *internal.Record, value string) error {
 	if containsValue(record, value) {
 		return nil
 	}
@@ -245,7 +296,7 @@ func (d *DNSProvider) appendRecordValue(ctx context.Context, dom internal.Domain
 		RoundRobin: append(record.RoundRobin, internal.RecordValue{Value: fmt.Sprintf(`%q`, value)}),
 	}
 
-	_, err = d.client.TxtRecords.Update(ctx, dom.ID, record.ID, request)
+	_, err := d.client.TxtRecords.Update(ctx, dom.ID, record.ID, request)
 	if err != nil {
 		return fmt.Errorf("failed to update TXT records: %w", err)
 	}
@@ -285,6 +336,18 @@ func containsValue(record *internal.Record, value string) bool {
 	})
 }
 
+func matchDomain(domains []internal.Domain, fqdn string) (internal.Domain, bool) {
+	for name := range dns01.UnFqdnDomainsSeq(fqdn) {
+		for _, dom := range domains {
+			if dom.Name == name {
+				return dom, true
+			}
+		}
+	}
+
+	return internal.Domain{}, false
+}
+
 func backoff(minimum, maximum time.Duration, attemptNum int, resp *http.Response) time.Duration {
 	if resp != nil {
 		// https://api.dns.constellix.com/v4/docs#section/Using-the-API/Rate-Limiting
@@ -1,10 +1,18 @@
 package constellix
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/digicert/lego/v4/providers/dns/constellix/internal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -124,6 +132,148 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_Present(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json")).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.RawStringResponse("[]")).
+		Route("POST /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("create_record.json"),
+			servermock.CheckRequestJSONBody(`{"name":"_acme-challenge","ttl":60,"roundRobin":[{"value":"\"123d==\""}]}`)).
+		Build(t)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_Present_append(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json")).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("records.json")).
+		Route("PUT /v1/domains/273302/records/txt/3557067",
+			servermock.RawStringResponse(`{"success":"Record updated successfully"}`),
+			servermock.CheckRequestJSONBody(`{"name":"_acme-challenge","ttl":60,"roundRobin":[{"value":"\"xxx\""},{"value":"\"123d==\""},{"value":"\"456e==\""}]}`)).
+		Build(t)
+
+	err := provider.Present("example.com", "abc", "456e==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_Present_domainCache(t *testing.T) {
+	var domainCalls atomic.Int32
+
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json"),
+			servermock.LinkFunc(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					domainCalls.Add(1)
+					next.ServeHTTP(rw, req)
+				})
+			})).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("records_single.json")).
+		Build(t)
+
+	// The value already exists: no record changes.
+	err := provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	err = provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, domainCalls.Load())
+}
+
+func TestDNSProvider_Present_concurrent(t *testing.T) {
+	var domainCalls atomic.Int32
+
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json"),
+			servermock.LinkFunc(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					domainCalls.Add(1)
+					next.ServeHTTP(rw, req)
+				})
+			})).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("records_single.json")).
+		Build(t)
+
+	tester.RunConcurrently(t, tester.DefaultConcurrency, func(i int) error {
+		return provider.Present("example.com", fmt.Sprintf("abc%d", i), "123d==")
+	})
+
+	assert.EqualValues(t, 1, domainCalls.Load())
+}
+
+func TestDNSProvider_Present_pagination(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/domains", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.Query().Get("offset") != "0" {
+				http.ServeFile(rw, req, "fixtures/domains.json")
+				return
+			}
+
+			var domains []internal.Domain
+			for i := range pageSize {
+				domains = append(domains, internal.Domain{ID: int64(i + 1), Name: fmt.Sprintf("example%d.net", i)})
+			}
+
+			_ = json.NewEncoder(rw).Encode(domains)
+		}),
+			servermock.CheckQueryParameter().With("max", "100")).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.RawStringResponse("[]"),
+			servermock.CheckQueryParameter().Strict().
+				With("offset", "0").
+				With("max", "100")).
+		Route("POST /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("create_record.json")).
+		Build(t)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Len(t, provider.domains, pageSize+2)
+}
+
+func TestDNSProvider_Present_domainNotFound(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json")).
+		Build(t)
+
+	err := provider.Present("example.net", "abc", "123d==")
+	require.EqualError(t, err, `constellix: domain not found for "_acme-challenge.example.net."`)
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json")).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("records_single.json")).
+		Route("DELETE /v1/domains/273302/records/txt/3557067",
+			servermock.RawStringResponse(`{"success":"Record deleted successfully"}`)).
+		Build(t)
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUp_removeValue(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/domains", servermock.ResponseFromFixture("domains.json")).
+		Route("GET /v1/domains/273302/records/txt",
+			servermock.ResponseFromFixture("records.json")).
+		Route("PUT /v1/domains/273302/records/txt/3557067",
+			servermock.RawStringResponse(`{"success":"Record updated successfully"}`),
+			servermock.CheckRequestJSONBody(`{"name":"_acme-challenge","ttl":60,"roundRobin":[{"value":"\"xxx\""}]}`)).
+		Build(t)
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -153,3 +303,25 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func mockBuilder() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			config := NewDefaultConfig()
+			config.APIKey = "key"
+			config.SecretKey = "secret"
+			config.HTTPClient = server.Client()
+
+			p, err := NewDNSProviderConfig(config)
+			if err != nil {
+				return nil, err
+			}
+
+			p.client.BaseURL = server.URL
+
+			return p, nil
+		},
+		servermock.CheckHeader().
+			WithRegexp("x-cns-security-token", `^key:[^:]+:\d+$`),
+	)
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+
+	querystring "github.com/google/go-querystring/query"
 )
 
 // TxtRecordService API access to Record.
@@ -43,7 +45,7 @@ func (s *TxtRecordService) Create(ctx context.Context, domainID int64, record Re
 
 // GetAll TXT records.
 // https://api-docs.constellix.com/?version=latest#e7103c53-2ad8-4bc8-b5b3-4c22c4b571b2
-func (s *TxtRecordService) GetAll(ctx context.Context, domainID int64) ([]Record, error) {
+func (s *TxtRecordService) GetAll(ctx context.Context, domainID int64, params *PaginationParameters) ([]Record, error) {
 	endpoint, err := s.client.createEndpoint(defaultVersion, "domains", strconv.FormatInt(domainID, 10), "records", "txt")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create endpoint: %w", err)
@@ -54,6 +56,15 @@ func (s *TxtRecordService) GetAll(ctx context.Context, domainID int64) ([]Record
 		return nil, fmt.Errorf("unable to create request: %w", err)
 	}
 
+	if params != nil {
+		v, errQ := querystring.Values(params)
+		if errQ != nil {
+			return nil, errQ
+		}
+
+		req.URL.RawQuery = v.Encode()
+	}
+
 	var records []Record
 
 	err = s.client.do(req, &records)
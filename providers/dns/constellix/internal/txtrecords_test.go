@@ -33,7 +33,7 @@ func TestTxtRecordService_GetAll(t *testing.T) {
 		Route("GET /v1/domains/12345/records/txt", servermock.ResponseFromFixture("records-GetAll.json")).
 		Build(t)
 
-	records, err := client.TxtRecords.GetAll(t.Context(), 12345)
+	records, err := client.TxtRecords.GetAll(t.Context(), 12345, nil)
 	require.NoError(t, err)
 
 	recordsJSON, err := json.Marshal(records)
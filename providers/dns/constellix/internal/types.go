@@ -96,7 +96,7 @@ type PaginationParameters struct {
 	// Max retrieves maximum number of dataset.
 	Max int `url:"max"`
 	// Sort on the basis of given property name.
-	Sort string `url:"sort"`
+	Sort string `url:"sort,omitempty"`
 	// Order Sort order. Possible values are asc / desc.
-	Order string `url:"order"`
+	Order string `url:"order,omitempty"`
 }
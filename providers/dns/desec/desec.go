@@ -5,12 +5,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
 	"github.com/nrdcg/desec"
@@ -41,16 +42,19 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, defaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 4*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 120*time.Second),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 4*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -92,7 +96,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	opts.HTTPClient = clientdebug.Wrap(opts.HTTPClient)
 
-	opts.Logger = log.Default()
+	opts.Logger = log.OrDefault(config.Logger)
 
 	client := desec.New(config.Token, opts)
 
@@ -4,14 +4,14 @@ package designate
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"slices"
 	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
@@ -54,6 +54,9 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	opts               gophercloud.AuthOptions
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -61,8 +64,8 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		ZoneName:           env.GetOrFile(EnvZoneName),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 10),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 10*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 10*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
 	}
 }
 
@@ -92,7 +95,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 		config.opts = *opts
 	} else {
-		opts, err := openstack.AuthOptionsFromEnv()
+		opts, err := authOptionsFromEnv()
 		if err != nil {
 			return nil, fmt.Errorf("designate: %w", err)
 		}
@@ -115,7 +118,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	dnsClient, err := openstack.NewDNSV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
+		Region: env.GetOrFile(EnvRegionName),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("designate: failed to get DNS provider: %w", err)
@@ -155,7 +158,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	if existingRecord != nil {
 		if slices.Contains(existingRecord.Records, info.Value) {
-			log.Printf("designate: the record already exists: %s", info.Value)
+			log.OrDefault(d.config.Logger).Info("designate: the record already exists.", "value", info.Value)
 			return nil
 		}
 
@@ -229,7 +232,7 @@ func (d *DNSProvider) createRecord(zoneID, fqdn, value string) error {
 
 func (d *DNSProvider) updateRecord(record *recordsets.RecordSet, value string) error {
 	if slices.Contains(record.Records, value) {
-		log.Printf("skip: the record already exists: %s", value)
+		log.OrDefault(d.config.Logger).Info("designate: skip: the record already exists.", "value", value)
 		return nil
 	}
 
@@ -311,3 +314,78 @@ func (d *DNSProvider) getZoneName(fqdn string) (string, error) {
 
 	return authZone, nil
 }
+
+// authOptionsFromEnv is a copy of [openstack.AuthOptionsFromEnv] where the values can also be read from files (`_FILE` suffix).
+func authOptionsFromEnv() (gophercloud.AuthOptions, error) {
+	authURL := env.GetOrFile(EnvAuthURL)
+	username := env.GetOrFile(EnvUsername)
+	userID := env.GetOrFile("OS_USERID")
+	password := env.GetOrFile(EnvPassword)
+	passcode := env.GetOrFile("OS_PASSCODE")
+	tenantID := env.GetOrFile("OS_TENANT_ID")
+	tenantName := env.GetOrFile(EnvTenantName)
+	domainID := env.GetOrFile("OS_DOMAIN_ID")
+	domainName := env.GetOrFile("OS_DOMAIN_NAME")
+	appCredID := env.GetOrFile(EnvAppCredID)
+	appCredName := env.GetOrFile(EnvAppCredName)
+	appCredSecret := env.GetOrFile(EnvAppCredSecret)
+
+	if v := env.GetOrFile(EnvProjectID); v != "" {
+		tenantID = v
+	}
+
+	if v := env.GetOrFile("OS_PROJECT_NAME"); v != "" {
+		tenantName = v
+	}
+
+	if authURL == "" {
+		return gophercloud.AuthOptions{}, gophercloud.ErrMissingEnvironmentVariable{EnvironmentVariable: EnvAuthURL}
+	}
+
+	if userID == "" && username == "" && appCredID == "" && appCredSecret == "" {
+		return gophercloud.AuthOptions{}, gophercloud.ErrMissingAnyoneOfEnvironmentVariables{EnvironmentVariables: []string{"OS_USERID", EnvUsername}}
+	}
+
+	if password == "" && passcode == "" && appCredID == "" && appCredName == "" {
+		return gophercloud.AuthOptions{}, gophercloud.ErrMissingEnvironmentVariable{EnvironmentVariable: EnvPassword}
+	}
+
+	if (appCredID != "" || appCredName != "") && appCredSecret == "" {
+		return gophercloud.AuthOptions{}, gophercloud.ErrMissingEnvironmentVariable{EnvironmentVariable: EnvAppCredSecret}
+	}
+
+	if domainID == "" && domainName == "" && tenantID == "" && tenantName != "" {
+		return gophercloud.AuthOptions{}, gophercloud.ErrMissingEnvironmentVariable{EnvironmentVariable: EnvProjectID}
+	}
+
+	if appCredID == "" && appCredName != "" && appCredSecret != "" {
+		if userID == "" && username == "" {
+			return gophercloud.AuthOptions{}, gophercloud.ErrMissingAnyoneOfEnvironmentVariables{EnvironmentVariables: []string{"OS_USERID", EnvUsername}}
+		}
+
+		if username != "" && domainID == "" && domainName == "" {
+			return gophercloud.AuthOptions{}, gophercloud.ErrMissingAnyoneOfEnvironmentVariables{EnvironmentVariables: []string{"OS_DOMAIN_ID", "OS_DOMAIN_NAME"}}
+		}
+	}
+
+	var scope *gophercloud.AuthScope
+	if env.GetOrFile("OS_SYSTEM_SCOPE") == "all" {
+		scope = &gophercloud.AuthScope{System: true}
+	}
+
+	return gophercloud.AuthOptions{
+		IdentityEndpoint:            authURL,
+		UserID:                      userID,
+		Username:                    username,
+		Password:                    password,
+		Passcode:                    passcode,
+		TenantID:                    tenantID,
+		TenantName:                  tenantName,
+		DomainID:                    domainID,
+		DomainName:                  domainName,
+		ApplicationCredentialID:     appCredID,
+		ApplicationCredentialName:   appCredName,
+		ApplicationCredentialSecret: appCredSecret,
+		Scope:                       scope,
+	}, nil
+}
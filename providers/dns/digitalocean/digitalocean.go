@@ -5,13 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/digitalocean/internal"
 	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
@@ -30,7 +33,12 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+const minTTL = 30
+
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderBatch   = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -40,17 +48,20 @@ type Config struct {
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
 		BaseURL:            env.GetOrDefaultString(EnvAPIUrl, internal.DefaultBaseURL),
-		TTL:                env.GetOrDefaultInt(EnvTTL, 30),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+		TTL:                env.ClampInt(EnvTTL, env.GetOrDefaultInt(EnvTTL, minTTL), minTTL, 0),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 5*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -140,6 +151,18 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	return nil
 }
 
+// PresentAll creates the TXT records of several challenges.
+func (d *DNSProvider) PresentAll(challenges []challenge.ChallengeInfo) error {
+	for _, chlg := range challenges {
+		err := d.Present(chlg.Domain, chlg.Token, chlg.KeyAuth)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
@@ -148,43 +171,107 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	if err != nil {
 		return fmt.Errorf("digitalocean: could not find zone for domain %q: %w", domain, err)
 	}
-	fmt.Printf("digitalocean: cleaning up TXT records for domain %s, zone %s\n", domain, authZone)
-	// First try from our record ID map
+
+	log.OrDefault(d.config.Logger).Debug("digitalocean: cleaning up TXT records.", "domain", domain, "zone", authZone)
+
+	err = d.removeRecordByToken(authZone, token)
+	if err != nil {
+		return err
+	}
+
+	return d.removeRecordsByName(authZone, []string{info.EffectiveFQDN})
+}
+
+// CleanUpAll removes the TXT records of several challenges, the records of each zone are listed once.
+// A failed removal doesn't prevent the removal of the other records.
+func (d *DNSProvider) CleanUpAll(challenges []challenge.ChallengeInfo) error {
+	var (
+		zones []string
+		fqdns = make(map[string][]string)
+		errs  []error
+	)
+
+	for _, chlg := range challenges {
+		info := dns01.GetChallengeInfo(chlg.Domain, chlg.KeyAuth)
+
+		authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digitalocean: could not find zone for domain %q: %w", chlg.Domain, err))
+			continue
+		}
+
+		err = d.removeRecordByToken(authZone, chlg.Token)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if _, ok := fqdns[authZone]; !ok {
+			zones = append(zones, authZone)
+		}
+
+		fqdns[authZone] = append(fqdns[authZone], info.EffectiveFQDN)
+	}
+
+	for _, authZone := range zones {
+		err := d.removeRecordsByName(authZone, fqdns[authZone])
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeRecordByToken removes the TXT record created by Present for the token, if any.
+func (d *DNSProvider) removeRecordByToken(authZone, token string) error {
+	logger := log.OrDefault(d.config.Logger)
+
 	d.recordIDsMu.Lock()
 	recordID, ok := d.recordIDs[token]
 	d.recordIDsMu.Unlock()
 
-	if ok {
-		fmt.Printf("digitalocean: found record ID %d in map for token %s\n", recordID, token)
-		err = d.client.RemoveTxtRecord(context.Background(), authZone, recordID)
-		if err != nil {
-			return fmt.Errorf("digitalocean: failed to remove TXT record with ID %d: %w", recordID, err)
-		}
+	if !ok {
+		return nil
+	}
 
-		fmt.Printf("digitalocean: successfully deleted TXT record with ID %d\n", recordID)
+	logger.Debug("digitalocean: record ID found for the token.", "recordID", recordID)
 
-		// Delete record ID from map
-		d.recordIDsMu.Lock()
-		delete(d.recordIDs, token)
-		d.recordIDsMu.Unlock()
+	err := d.client.RemoveTxtRecord(context.Background(), authZone, recordID)
+	if err != nil {
+		return fmt.Errorf("digitalocean: failed to remove TXT record with ID %d: %w", recordID, err)
 	}
 
+	logger.Debug("digitalocean: TXT record deleted.", "recordID", recordID)
+
+	// Delete record ID from map
+	d.recordIDsMu.Lock()
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
+	return nil
+}
+
+// removeRecordsByName removes the remaining TXT records of the FQDNs, the records of the zone are listed once.
+func (d *DNSProvider) removeRecordsByName(authZone string, fqdns []string) error {
+	logger := log.OrDefault(d.config.Logger)
+
 	records, err := d.client.ListRecords(context.Background(), authZone)
 	if err != nil {
 		return fmt.Errorf("digitalocean: failed to list records for zone %s: %w", authZone, err)
 	}
 
 	for _, record := range records {
-		if record.Type == "TXT" && record.Name == info.EffectiveFQDN {
-			fmt.Printf("digitalocean: found matching TXT record with ID %d for %s\n", record.ID, info.EffectiveFQDN)
+		if record.Type == "TXT" && slices.Contains(fqdns, record.Name) {
+			logger.Debug("digitalocean: matching TXT record found.", "recordID", record.ID, "fqdn", record.Name)
 
 			err = d.client.RemoveTxtRecord(context.Background(), authZone, record.ID)
 			if err != nil {
 				return fmt.Errorf("digitalocean: failed to remove TXT record with ID %d: %w", record.ID, err)
 			}
 
-			fmt.Printf("digitalocean: successfully deleted TXT record with ID %d\n", record.ID)
+			logger.Debug("digitalocean: TXT record deleted.", "recordID", record.ID)
 		}
 	}
+
 	return nil
 }
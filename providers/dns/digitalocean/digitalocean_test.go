@@ -3,10 +3,14 @@ package digitalocean
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/digicert/lego/v4/providers/dns/digitalocean/internal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -132,6 +136,8 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 		Route("DELETE /v2/domains/example.com/records/1234567",
 			servermock.Noop().
 				WithStatusCode(http.StatusNoContent)).
+		Route("GET /v2/domains/example.com/records",
+			servermock.JSONEncode(internal.ListRecordsResponse{})).
 		Build(t)
 
 	provider.recordIDsMu.Lock()
@@ -141,3 +147,64 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 	err := provider.CleanUp("example.com", "token", "")
 	require.NoError(t, err)
 }
+
+func TestDNSProvider_PresentAll(t *testing.T) {
+	var recordID atomic.Int32
+
+	provider := mockProvider().
+		Route("POST /v2/domains/example.com/records",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				servermock.JSONEncode(internal.TxtRecordResponse{
+					DomainRecord: internal.Record{ID: int(recordID.Add(1)), Type: "TXT"},
+				}).
+					WithStatusCode(http.StatusCreated).
+					ServeHTTP(rw, req)
+			})).
+		Build(t)
+
+	err := provider.PresentAll([]challenge.ChallengeInfo{
+		{Domain: "example.com", Token: "a", KeyAuth: "foo"},
+		{Domain: "example.com", Token: "b", KeyAuth: "bar"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, provider.recordIDs)
+}
+
+func TestDNSProvider_CleanUpAll(t *testing.T) {
+	var listCalls atomic.Int32
+
+	provider := mockProvider().
+		Route("DELETE /v2/domains/example.com/records/1",
+			servermock.Noop().
+				WithStatusCode(http.StatusNoContent)).
+		Route("DELETE /v2/domains/example.com/records/3",
+			servermock.Noop().
+				WithStatusCode(http.StatusNoContent)).
+		Route("GET /v2/domains/example.com/records",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				listCalls.Add(1)
+
+				servermock.JSONEncode(internal.ListRecordsResponse{
+					DomainRecords: []internal.Record{
+						{ID: 3, Type: "TXT", Name: "_acme-challenge.b.example.com."},
+						{ID: 4, Type: "TXT", Name: "_acme-challenge.c.example.com."},
+						{ID: 5, Type: "A", Name: "_acme-challenge.b.example.com."},
+					},
+				}).ServeHTTP(rw, req)
+			})).
+		Build(t)
+
+	provider.recordIDsMu.Lock()
+	provider.recordIDs["a"] = 1
+	provider.recordIDsMu.Unlock()
+
+	err := provider.CleanUpAll([]challenge.ChallengeInfo{
+		{Domain: "a.example.com", Token: "a", KeyAuth: "foo"},
+		{Domain: "b.example.com", Token: "b", KeyAuth: "bar"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), listCalls.Load())
+	assert.Empty(t, provider.recordIDs)
+}
@@ -1,6 +1,12 @@
 package dns
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/digicert/lego/v4/platform/tester"
@@ -40,3 +46,59 @@ func TestUnknownDNSProvider(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, provider)
 }
+
+// TestProviders_envFile ensures that the providers read the environment variables through the env package,
+// which supports the `_FILE` suffix (the value is read from the file).
+func TestProviders_envFile(t *testing.T) {
+	// The packages allowed to read the environment directly.
+	allowed := map[string]struct{}{
+		// The private key variables already reference files (OCI_PRIVKEY_FILE, OCI_PRIVATE_KEY_PATH).
+		"oraclecloud": {},
+		// Debug options, not credentials.
+		"internal/clientdebug": {},
+		"internal/errutils":    {},
+	}
+
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		if _, ok := allowed[filepath.ToSlash(filepath.Dir(path))]; ok {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok || pkg.Name != "os" {
+				return true
+			}
+
+			switch sel.Sel.Name {
+			case "Getenv", "LookupEnv", "Environ":
+				t.Errorf("%s: os.%s must be replaced by the env package (ex: env.GetOrFile) to support the _FILE suffix", fset.Position(sel.Pos()), sel.Sel.Name)
+			}
+
+			return true
+		})
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -31,22 +31,20 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIToken           string
-	APISecret          string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	HTTPClient         *http.Client
+	APIToken           string        `env:"API_TOKEN,required"`
+	APISecret          string        `env:"API_SECRET,required"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"5m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"20s"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 5*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 20*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -59,15 +57,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // DOMENESHOP_API_TOKEN, DOMENESHOP_API_SECRET.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIToken, EnvAPISecret)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("domeneshop: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIToken = values[EnvAPIToken]
-	config.APISecret = values[EnvAPISecret]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -33,25 +33,26 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	CustomerName       string
-	UserName           string
-	Password           string
-	HTTPClient         *http.Client
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
+	CustomerName       string        `env:"CUSTOMER_NAME,required"`
+	UserName           string        `env:"USER_NAME,required"`
+	Password           string        `env:"PASSWORD,required"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"10s"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
-		},
+	config := &Config{
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -64,16 +65,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // DYN_CUSTOMER_NAME, DYN_USER_NAME and DYN_PASSWORD.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvCustomerName, EnvUserName, EnvPassword)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("dyn: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.CustomerName = values[EnvCustomerName]
-	config.UserName = values[EnvUserName]
-	config.Password = values[EnvPassword]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -31,24 +32,24 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey string
+	APIKey string `env:"API_KEY,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"3m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"10s"`
+	TTL                int           `env:"TTL" default:"300"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 3*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -60,14 +61,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for Dynu.
 // Credentials must be passed in the environment variables.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIKey)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("dynu: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIKey = values[EnvAPIKey]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -87,6 +87,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	client := internal.NewClient()
+	client.Logger = config.Logger
 
 	client.HTTPClient = clientdebug.Wrap(tr.Wrap(config.HTTPClient))
 
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -23,6 +24,9 @@ const defaultBaseURL = "https://api.dynu.com/v2"
 type Client struct {
 	baseURL    *url.URL
 	HTTPClient *http.Client
+
+	// Logger is used to log the messages of the client (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 func NewClient() *Client {
@@ -122,7 +126,7 @@ func (c *Client) doRetry(ctx context.Context, method, uri string, body []byte, r
 	}
 
 	notify := func(err error, duration time.Duration) {
-		log.Printf("client retries because of %v", err)
+		log.OrDefault(c.Logger).Info("dynu: client retries.", "error", err)
 	}
 
 	bo := backoff.NewExponentialBackOff()
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"slices"
 	"strings"
@@ -27,6 +28,10 @@ const (
 	EnvEdgeRcSection    = envNamespace + "EDGERC_SECTION"
 	EnvAccountSwitchKey = envNamespace + "ACCOUNT_SWITCH_KEY"
 
+	EnvRetryMax     = envNamespace + "RETRY_MAX"
+	EnvRetryWaitMin = envNamespace + "RETRY_WAIT_MIN"
+	EnvRetryWaitMax = envNamespace + "RETRY_WAIT_MAX"
+
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
@@ -54,17 +59,31 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 type Config struct {
 	*edgegrid.Config
 
+	// RetryMax is the maximum number of retries of the GET requests (0 disables the retries).
+	// The wait between retries follows the Akamai rate-limit headers (`X-RateLimit-Next`) when available.
+	RetryMax     int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
+	retryConfig := session.NewRetryConfig()
+
 	return &Config{
+		RetryMax:           env.GetOrDefaultInt(EnvRetryMax, retryConfig.RetryMax),
+		RetryWaitMin:       env.GetOrDefaultDuration(EnvRetryWaitMin, retryConfig.RetryWaitMin),
+		RetryWaitMax:       env.GetOrDefaultDuration(EnvRetryWaitMax, retryConfig.RetryWaitMax),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, defaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, defaultPollInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, defaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, defaultPollInterval),
 		Config:             &edgegrid.Config{MaxBody: maxBody},
 	}
 }
@@ -72,6 +91,7 @@ func NewDefaultConfig() *Config {
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+	client edgegriddns.DNS
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Akamai EdgeDNS:
@@ -117,7 +137,23 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("edgedns: %w", err)
 	}
 
-	return &DNSProvider{config: config}, nil
+	opts := []session.Option{session.WithSigner(config)}
+
+	if config.RetryMax > 0 {
+		retryConfig := session.NewRetryConfig()
+		retryConfig.RetryMax = config.RetryMax
+		retryConfig.RetryWaitMin = config.RetryWaitMin
+		retryConfig.RetryWaitMax = config.RetryWaitMax
+
+		opts = append(opts, session.WithRetries(retryConfig))
+	}
+
+	sess, err := session.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("edgedns: %w", err)
+	}
+
+	return &DNSProvider{config: config, client: edgegriddns.Client(sess)}, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -132,19 +168,12 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	sess, err := session.New(session.WithSigner(d.config))
-	if err != nil {
-		return fmt.Errorf("edgedns: %w", err)
-	}
-
-	client := edgegriddns.Client(sess)
-
 	zone, err := getZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("edgedns: %w", err)
 	}
 
-	record, err := client.GetRecord(ctx, edgegriddns.GetRecordRequest{
+	record, err := d.client.GetRecord(ctx, edgegriddns.GetRecordRequest{
 		Zone:       zone,
 		Name:       info.EffectiveFQDN,
 		RecordType: "TXT",
@@ -158,7 +187,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	if record != nil {
-		log.Infof("TXT record already exists. Updating target")
+		log.OrDefault(d.config.Logger).Info("edgedns: TXT record already exists. Updating target.")
 
 		if containsValue(record.Target, info.Value) {
 			// have a record and have entry already
@@ -168,7 +197,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		record.Target = append(record.Target, `"`+info.Value+`"`)
 		record.TTL = d.config.TTL
 
-		err = client.UpdateRecord(ctx, edgegriddns.UpdateRecordRequest{
+		err = d.client.UpdateRecord(ctx, edgegriddns.UpdateRecordRequest{
 			Record: &edgegriddns.RecordBody{
 				Name:       record.Name,
 				RecordType: record.RecordType,
@@ -185,7 +214,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return nil
 	}
 
-	err = client.CreateRecord(ctx, edgegriddns.CreateRecordRequest{
+	err = d.client.CreateRecord(ctx, edgegriddns.CreateRecordRequest{
 		Record: &edgegriddns.RecordBody{
 			Name:       info.EffectiveFQDN,
 			RecordType: "TXT",
@@ -208,19 +237,12 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	sess, err := session.New(session.WithSigner(d.config))
-	if err != nil {
-		return fmt.Errorf("edgedns: %w", err)
-	}
-
-	client := edgegriddns.Client(sess)
-
 	zone, err := getZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("edgedns: %w", err)
 	}
 
-	existingRec, err := client.GetRecord(ctx, edgegriddns.GetRecordRequest{
+	existingRec, err := d.client.GetRecord(ctx, edgegriddns.GetRecordRequest{
 		Zone:       zone,
 		Name:       info.EffectiveFQDN,
 		RecordType: "TXT",
@@ -250,7 +272,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	if len(newRData) > 0 {
 		existingRec.Target = newRData
 
-		err = client.UpdateRecord(ctx, edgegriddns.UpdateRecordRequest{
+		err = d.client.UpdateRecord(ctx, edgegriddns.UpdateRecordRequest{
 			Record: &edgegriddns.RecordBody{
 				Name:       existingRec.Name,
 				RecordType: existingRec.RecordType,
@@ -267,7 +289,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return nil
 	}
 
-	err = client.DeleteRecord(ctx, edgegriddns.DeleteRecordRequest{
+	err = d.client.DeleteRecord(ctx, edgegriddns.DeleteRecordRequest{
 		Zone:       zone,
 		Name:       existingRec.Name,
 		RecordType: "TXT",
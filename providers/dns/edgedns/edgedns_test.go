@@ -27,6 +27,9 @@ var envTest = tester.NewEnvTest(
 	EnvClientSecret,
 	EnvAccessToken,
 	EnvAccountSwitchKey,
+	EnvRetryMax,
+	EnvRetryWaitMin,
+	EnvRetryWaitMax,
 	EnvEdgeRc,
 	EnvEdgeRcSection,
 	envTestHost,
@@ -172,6 +175,58 @@ func TestNewDNSProvider(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		retryMax     int
+		retryWaitMin time.Duration
+		retryWaitMax time.Duration
+		expected     string
+	}{
+		{
+			desc:         "success",
+			retryMax:     10,
+			retryWaitMin: 1 * time.Second,
+			retryWaitMax: 30 * time.Second,
+		},
+		{
+			desc: "success without retries",
+		},
+		{
+			desc:         "invalid retry wait",
+			retryMax:     10,
+			retryWaitMin: 30 * time.Second,
+			retryWaitMax: 1 * time.Second,
+			expected:     "edgedns: retry configuration failed: maximum retry wait time cannot be shorter than minimum retry wait time",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Host = "akaa-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net"
+			config.ClientToken = "akab-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx"
+			config.ClientSecret = "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"
+			config.AccessToken = "akac-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx"
+			config.RetryMax = test.retryMax
+			config.RetryWaitMin = test.retryWaitMin
+			config.RetryWaitMax = test.retryWaitMax
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected != "" {
+				require.EqualError(t, err, test.expected)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, p)
+			require.NotNil(t, p.config)
+			require.NotNil(t, p.client)
+		})
+	}
+}
+
 func TestNewDefaultConfig(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -181,6 +236,9 @@ func TestNewDefaultConfig(t *testing.T) {
 		{
 			desc: "default configuration",
 			expected: &Config{
+				RetryMax:           10,
+				RetryWaitMin:       1 * time.Second,
+				RetryWaitMax:       30 * time.Second,
 				TTL:                dns01.DefaultTTL,
 				PropagationTimeout: 3 * time.Minute,
 				PollingInterval:    15 * time.Second,
@@ -195,8 +253,14 @@ func TestNewDefaultConfig(t *testing.T) {
 				EnvTTL:                "99",
 				EnvPropagationTimeout: "60",
 				EnvPollingInterval:    "60",
+				EnvRetryMax:           "3",
+				EnvRetryWaitMin:       "2",
+				EnvRetryWaitMax:       "20",
 			},
 			expected: &Config{
+				RetryMax:           3,
+				RetryWaitMin:       2 * time.Second,
+				RetryWaitMax:       20 * time.Second,
 				TTL:                99,
 				PropagationTimeout: 60 * time.Second,
 				PollingInterval:    60 * time.Second,
@@ -53,9 +53,9 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 20*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 30*time.Second),
-		HTTPTimeout:        env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 20*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 30*time.Second),
+		HTTPTimeout:        env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 	}
 }
 
@@ -0,0 +1,207 @@
+// Package enom implements a DNS provider for solving the DNS-01 challenge using Enom.
+package enom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/providers/dns/enom/internal"
+	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "ENOM_"
+
+	EnvUsername = envNamespace + "USERNAME"
+	EnvAPIToken = envNamespace + "API_TOKEN"
+	EnvSandbox  = envNamespace + "SANDBOX"
+
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Username string
+	APIToken string
+	Sandbox  bool
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Sandbox:            env.GetOrDefaultBool(EnvSandbox, false),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 10*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 15*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 60*time.Second),
+		},
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	// The host records of a domain are replaced as a whole by SetHosts.
+	hostsMu sync.Mutex
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Enom.
+// Credentials must be passed in the environment variables:
+// ENOM_USERNAME and ENOM_API_TOKEN.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvUsername, EnvAPIToken)
+	if err != nil {
+		return nil, fmt.Errorf("enom: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Username = values[EnvUsername]
+	config.APIToken = values[EnvAPIToken]
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Enom.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("enom: the configuration of the DNS provider is nil")
+	}
+
+	client, err := internal.NewClient(config.Username, config.APIToken)
+	if err != nil {
+		return nil, fmt.Errorf("enom: %w", err)
+	}
+
+	if config.Sandbox {
+		client.BaseURL = internal.SandboxBaseURL
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+
+	return &DNSProvider{config: config, client: client}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	sld, tld, record, err := newRecord(info)
+	if err != nil {
+		return fmt.Errorf("enom: %w", err)
+	}
+
+	d.hostsMu.Lock()
+	defer d.hostsMu.Unlock()
+
+	hosts, err := d.client.GetHosts(ctx, sld, tld)
+	if err != nil {
+		return fmt.Errorf("enom: get hosts: %w", err)
+	}
+
+	for _, h := range hosts {
+		if isSameRecord(h, record) {
+			return nil
+		}
+	}
+
+	err = d.client.SetHosts(ctx, sld, tld, append(hosts, record))
+	if err != nil {
+		return fmt.Errorf("enom: set hosts: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	sld, tld, record, err := newRecord(info)
+	if err != nil {
+		return fmt.Errorf("enom: %w", err)
+	}
+
+	d.hostsMu.Lock()
+	defer d.hostsMu.Unlock()
+
+	hosts, err := d.client.GetHosts(ctx, sld, tld)
+	if err != nil {
+		return fmt.Errorf("enom: get hosts: %w", err)
+	}
+
+	var newHosts []internal.Host
+
+	for _, h := range hosts {
+		if !isSameRecord(h, record) {
+			newHosts = append(newHosts, h)
+		}
+	}
+
+	if len(newHosts) == len(hosts) {
+		return nil
+	}
+
+	err = d.client.SetHosts(ctx, sld, tld, newHosts)
+	if err != nil {
+		return fmt.Errorf("enom: set hosts: %w", err)
+	}
+
+	return nil
+}
+
+// newRecord builds the host record, and splits the zone into the second-level domain and the TLD used by the API.
+func newRecord(info dns01.ChallengeInfo) (sld, tld string, record internal.Host, err error) {
+	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return "", "", internal.Host{}, fmt.Errorf("could not find zone for domain %q: %w", info.EffectiveFQDN, err)
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	if err != nil {
+		return "", "", internal.Host{}, err
+	}
+
+	sld, tld, found := strings.Cut(dns01.UnFqdn(authZone), ".")
+	if !found {
+		return "", "", internal.Host{}, fmt.Errorf("invalid zone %q", authZone)
+	}
+
+	return sld, tld, internal.Host{Name: subDomain, Type: "TXT", Address: info.Value}, nil
+}
+
+func isSameRecord(a, b internal.Host) bool {
+	return strings.EqualFold(a.Name, b.Name) && strings.EqualFold(a.Type, b.Type) && a.Address == b.Address
+}
@@ -0,0 +1,154 @@
+package enom
+
+import (
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/providers/dns/enom/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvUsername, EnvAPIToken, EnvSandbox).WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvUsername: "user",
+				EnvAPIToken: "secret",
+			},
+		},
+		{
+			desc: "missing username",
+			envVars: map[string]string{
+				EnvAPIToken: "secret",
+			},
+			expected: "enom: some credentials information are missing: ENOM_USERNAME",
+		},
+		{
+			desc: "missing API token",
+			envVars: map[string]string{
+				EnvUsername: "user",
+			},
+			expected: "enom: some credentials information are missing: ENOM_API_TOKEN",
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "enom: some credentials information are missing: ENOM_USERNAME,ENOM_API_TOKEN",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		username string
+		apiToken string
+		expected string
+	}{
+		{
+			desc:     "success",
+			username: "user",
+			apiToken: "secret",
+		},
+		{
+			desc:     "missing username",
+			apiToken: "secret",
+			expected: "enom: credentials missing",
+		},
+		{
+			desc:     "missing API token",
+			username: "user",
+			expected: "enom: credentials missing",
+		},
+		{
+			desc:     "missing credentials",
+			expected: "enom: credentials missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Username = test.username
+			config.APIToken = test.apiToken
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_isSameRecord(t *testing.T) {
+	record := internal.Host{Name: "_acme-challenge", Type: "TXT", Address: "123d=="}
+
+	assert.True(t, isSameRecord(internal.Host{HostID: "1", Name: "_ACME-challenge", Type: "txt", Address: "123d==", MXPref: "10"}, record))
+	assert.False(t, isSameRecord(internal.Host{Name: "_acme-challenge", Type: "TXT", Address: "456d=="}, record))
+	assert.False(t, isSameRecord(internal.Host{Name: "_acme-challenge.www", Type: "TXT", Address: "123d=="}, record))
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
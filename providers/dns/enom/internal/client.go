@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
+	"github.com/digicert/lego/v4/providers/dns/internal/useragent"
+)
+
+// Default API endpoints.
+const (
+	DefaultBaseURL = "https://reseller.enom.com/interface.asp"
+	SandboxBaseURL = "https://resellertest.enom.com/interface.asp"
+)
+
+// Client the API client for Enom.
+type Client struct {
+	username string
+	apiToken string
+
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(username, apiToken string) (*Client, error) {
+	if username == "" || apiToken == "" {
+		return nil, errors.New("credentials missing")
+	}
+
+	return &Client{
+		username:   username,
+		apiToken:   apiToken,
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// GetHosts reads the full list of host records of a domain.
+// https://api.enom.com/docs/gethosts
+func (c *Client) GetHosts(ctx context.Context, sld, tld string) ([]Host, error) {
+	query := c.newQuery("GetHosts", sld, tld)
+
+	req, err := c.newRequest(ctx, http.MethodGet, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result getHostsResponse
+
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = result.err()
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Hosts, nil
+}
+
+// SetHosts writes the full list of host records of a domain:
+// the existing records not in the list are removed.
+// https://api.enom.com/docs/sethosts
+func (c *Client) SetHosts(ctx context.Context, sld, tld string, hosts []Host) error {
+	query := c.newQuery("SetHosts", sld, tld)
+
+	for i, h := range hosts {
+		ind := strconv.Itoa(i + 1)
+
+		query.Set("HostName"+ind, h.Name)
+		query.Set("RecordType"+ind, h.Type)
+		query.Set("Address"+ind, h.Address)
+
+		if h.MXPref != "" {
+			query.Set("MXPref"+ind, h.MXPref)
+		}
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, query)
+	if err != nil {
+		return err
+	}
+
+	var result setHostsResponse
+
+	err = c.do(req, &result)
+	if err != nil {
+		return err
+	}
+
+	return result.err()
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	useragent.SetHeader(req.Header)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = xml.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func (c *Client) newQuery(cmd, sld, tld string) url.Values {
+	query := make(url.Values)
+	query.Set("command", cmd)
+	query.Set("uid", c.username)
+	query.Set("pw", c.apiToken)
+	query.Set("sld", sld)
+	query.Set("tld", tld)
+	query.Set("responsetype", "xml")
+
+	return query
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, query url.Values) (*http.Request, error) {
+	if method == http.MethodGet {
+		endpoint, err := url.Parse(c.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create request: %w", err)
+		}
+
+		return req, nil
+	}
+
+	// The host records are sent in the body: the list can be longer than the maximum length of a URL.
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL, strings.NewReader(query.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req, nil
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient("user", "secret")
+			if err != nil {
+				return nil, err
+			}
+
+			client.HTTPClient = server.Client()
+			client.BaseURL = server.URL + "/interface.asp"
+
+			return client, nil
+		},
+	)
+}
+
+func TestClient_GetHosts(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /interface.asp",
+			servermock.ResponseFromFixture("getHosts.xml"),
+			servermock.CheckQueryParameter().Strict().
+				With("command", "GetHosts").
+				With("uid", "user").
+				With("pw", "secret").
+				With("sld", "example").
+				With("tld", "com").
+				With("responsetype", "xml"),
+		).
+		Build(t)
+
+	hosts, err := client.GetHosts(t.Context(), "example", "com")
+	require.NoError(t, err)
+
+	expected := []Host{
+		{HostID: "61478390", Name: "@", Type: "A", Address: "192.0.2.1", MXPref: "10"},
+		{HostID: "61478391", Name: "www", Type: "CNAME", Address: "example.com.", MXPref: "10"},
+		{HostID: "61478392", Name: "@", Type: "MX", Address: "mail.example.com.", MXPref: "5"},
+	}
+
+	assert.Equal(t, expected, hosts)
+}
+
+func TestClient_GetHosts_error(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /interface.asp",
+			servermock.ResponseFromFixture("error.xml")).
+		Build(t)
+
+	_, err := client.GetHosts(t.Context(), "example", "com")
+	require.EqualError(t, err, "Domain name not found")
+}
+
+func TestClient_SetHosts(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /interface.asp",
+			servermock.ResponseFromFixture("setHosts.xml"),
+			servermock.CheckHeader().WithContentTypeFromURLEncoded(),
+			servermock.CheckForm().Strict().
+				With("command", "SetHosts").
+				With("uid", "user").
+				With("pw", "secret").
+				With("sld", "example").
+				With("tld", "com").
+				With("responsetype", "xml").
+				// entry 1
+				With("HostName1", "@").
+				With("RecordType1", "MX").
+				With("Address1", "mail.example.com.").
+				With("MXPref1", "5").
+				// entry 2
+				With("HostName2", "_acme-challenge").
+				With("RecordType2", "TXT").
+				With("Address2", "123d=="),
+		).
+		Build(t)
+
+	hosts := []Host{
+		{Name: "@", Type: "MX", Address: "mail.example.com.", MXPref: "5"},
+		{Name: "_acme-challenge", Type: "TXT", Address: "123d=="},
+	}
+
+	err := client.SetHosts(t.Context(), "example", "com", hosts)
+	require.NoError(t, err)
+}
+
+func TestClient_SetHosts_error(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /interface.asp",
+			servermock.ResponseFromFixture("error.xml")).
+		Build(t)
+
+	err := client.SetHosts(t.Context(), "example", "com", nil)
+	require.EqualError(t, err, "Domain name not found")
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Host describes a host record of the Enom "GetHosts" command.
+type Host struct {
+	HostID  string `xml:"hostid"`
+	Name    string `xml:"name"`
+	Type    string `xml:"type"`
+	Address string `xml:"address"`
+	MXPref  string `xml:"mxpref"`
+}
+
+type getHostsResponse struct {
+	XMLName xml.Name `xml:"interface-response"`
+	Hosts   []Host   `xml:"host"`
+	Errors
+}
+
+type setHostsResponse struct {
+	XMLName xml.Name `xml:"interface-response"`
+	Errors
+}
+
+// Errors the error part of the API responses.
+type Errors struct {
+	ErrCount int        `xml:"ErrCount"`
+	Messages ErrorsList `xml:"errors"`
+}
+
+// ErrorsList the error messages (Err1, Err2, ...).
+type ErrorsList struct {
+	Items []ErrorItem `xml:",any"`
+}
+
+type ErrorItem struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// APIError an error returned by the API.
+type APIError struct {
+	Messages []string
+}
+
+func (a *APIError) Error() string {
+	return strings.Join(a.Messages, ", ")
+}
+
+func (e Errors) err() error {
+	if e.ErrCount == 0 {
+		return nil
+	}
+
+	apiErr := &APIError{}
+
+	for _, item := range e.Messages.Items {
+		if msg := strings.TrimSpace(item.Value); msg != "" {
+			apiErr.Messages = append(apiErr.Messages, msg)
+		}
+	}
+
+	if len(apiErr.Messages) == 0 {
+		apiErr.Messages = append(apiErr.Messages, "unknown error")
+	}
+
+	return apiErr
+}
@@ -34,25 +34,22 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIURL string
-	APIKey string
+	APIURL string `env:"API_URL,required"`
+	APIKey string `env:"API_KEY,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"5m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"10s"`
+	TTL                int           `env:"TTL" default:"60"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 5*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -66,15 +63,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for Excedo.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIURL, EnvAPIKey)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("excedo: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIURL = values[EnvAPIURL]
-	config.APIKey = values[EnvAPIKey]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -6,7 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"log/slog"
 	"os/exec"
 	"time"
 
@@ -28,6 +28,14 @@ const (
 	EnvSequenceInterval   = envNamespace + "SEQUENCE_INTERVAL"
 )
 
+// Modes.
+const (
+	modeRaw = "RAW"
+
+	// modeServer starts the program once, and calls it over stdin/stdout with JSON-RPC requests.
+	modeServer = "SERVER"
+)
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config Provider configuration.
@@ -37,20 +45,26 @@ type Config struct {
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	SequenceInterval   time.Duration
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+		SequenceInterval:   env.GetOrDefaultDuration(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
 	}
 }
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	// server is the long-running program (SERVER mode only).
+	server *server
 }
 
 // NewDNSProvider returns a new DNS provider which runs the program in the
@@ -63,7 +77,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 	config := NewDefaultConfig()
 	config.Program = values[EnvPath]
-	config.Mode = os.Getenv(EnvMode)
+	config.Mode = env.GetOrFile(EnvMode)
 
 	return NewDNSProviderConfig(config)
 }
@@ -75,7 +89,13 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("exec: the configuration is nil")
 	}
 
-	return &DNSProvider{config: config}, nil
+	d := &DNSProvider{config: config}
+
+	if config.Mode == modeServer {
+		d.server = &server{program: config.Program, logger: log.OrDefault(config.Logger)}
+	}
+
+	return d, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
@@ -110,9 +130,35 @@ func (d *DNSProvider) Sequential() time.Duration {
 	return d.config.SequenceInterval
 }
 
+// Close stops the long-running program (SERVER mode only).
+func (d *DNSProvider) Close() error {
+	if d.server == nil {
+		return nil
+	}
+
+	err := d.server.close()
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
 func (d *DNSProvider) run(ctx context.Context, command, domain, token, keyAuth string) error {
+	if d.server != nil {
+		info := dns01.GetChallengeInfo(domain, keyAuth)
+
+		return d.server.call(command, rpcParams{
+			FQDN:    info.EffectiveFQDN,
+			Value:   info.Value,
+			Domain:  domain,
+			Token:   token,
+			KeyAuth: keyAuth,
+		})
+	}
+
 	var args []string
-	if d.config.Mode == "RAW" {
+	if d.config.Mode == modeRaw {
 		args = []string{command, "--", domain, token, keyAuth}
 	} else {
 		info := dns01.GetChallengeInfo(domain, keyAuth)
@@ -135,7 +181,7 @@ func (d *DNSProvider) run(ctx context.Context, command, domain, token, keyAuth s
 
 	scanner := bufio.NewScanner(stdout)
 	for scanner.Scan() {
-		log.Println(scanner.Text())
+		log.OrDefault(d.config.Logger).Info(scanner.Text(), "provider", "exec")
 	}
 
 	err = cmd.Wait()
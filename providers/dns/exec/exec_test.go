@@ -1,26 +1,24 @@
 package exec
 
 import (
-	"fmt"
-	"os"
+	"log/slog"
 	"strings"
 	"testing"
 
 	"github.com/digicert/lego/v4/log"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestDNSProvider_Present(t *testing.T) {
-	backupLogger := log.Logger
+	backupLogger := log.Default()
 
 	defer func() {
-		log.Logger = backupLogger
+		log.SetDefault(backupLogger)
 	}()
 
 	logRecorder := &LogRecorder{}
-	log.Logger = logRecorder
+	log.SetDefault(slog.New(logRecorder))
 
 	type expected struct {
 		args  string
@@ -62,16 +60,9 @@ func TestDNSProvider_Present(t *testing.T) {
 		},
 	}
 
-	var message string
-
-	logRecorder.On("Println", mock.Anything).Run(func(args mock.Arguments) {
-		message = args.String(0)
-		fmt.Fprintln(os.Stdout, "XXX", message)
-	})
-
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			message = ""
+			logRecorder.Reset()
 
 			provider, err := NewDNSProviderConfig(test.config)
 			require.NoError(t, err)
@@ -81,21 +72,21 @@ func TestDNSProvider_Present(t *testing.T) {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, test.expected.args, strings.TrimSpace(message))
+				assert.Equal(t, test.expected.args, strings.TrimSpace(logRecorder.Last()))
 			}
 		})
 	}
 }
 
 func TestDNSProvider_CleanUp(t *testing.T) {
-	backupLogger := log.Logger
+	backupLogger := log.Default()
 
 	defer func() {
-		log.Logger = backupLogger
+		log.SetDefault(backupLogger)
 	}()
 
 	logRecorder := &LogRecorder{}
-	log.Logger = logRecorder
+	log.SetDefault(slog.New(logRecorder))
 
 	type expected struct {
 		args  string
@@ -137,16 +128,9 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 		},
 	}
 
-	var message string
-
-	logRecorder.On("Println", mock.Anything).Run(func(args mock.Arguments) {
-		message = args.String(0)
-		fmt.Fprintln(os.Stdout, "XXX", message)
-	})
-
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			message = ""
+			logRecorder.Reset()
 
 			provider, err := NewDNSProviderConfig(test.config)
 			require.NoError(t, err)
@@ -156,7 +140,7 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, test.expected.args, strings.TrimSpace(message))
+				assert.Equal(t, test.expected.args, strings.TrimSpace(logRecorder.Last()))
 			}
 		})
 	}
@@ -1,31 +1,63 @@
 package exec
 
-import "github.com/stretchr/testify/mock"
-
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// LogRecorder a slog.Handler recording the messages.
 type LogRecorder struct {
-	mock.Mock
+	mu       sync.Mutex
+	messages []string
 }
 
-func (*LogRecorder) Fatal(args ...any) {
-	panic("implement me")
+func (*LogRecorder) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
 }
 
-func (*LogRecorder) Fatalln(args ...any) {
-	panic("implement me")
+func (l *LogRecorder) Handle(_ context.Context, record slog.Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = append(l.messages, record.Message)
+
+	return nil
 }
 
-func (*LogRecorder) Fatalf(format string, args ...any) {
-	panic("implement me")
+func (l *LogRecorder) WithAttrs(_ []slog.Attr) slog.Handler {
+	return l
 }
 
-func (*LogRecorder) Print(args ...any) {
-	panic("implement me")
+func (l *LogRecorder) WithGroup(_ string) slog.Handler {
+	return l
 }
 
-func (l *LogRecorder) Println(args ...any) {
-	l.Called(args...)
+// Last returns the last recorded message.
+func (l *LogRecorder) Last() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.messages) == 0 {
+		return ""
+	}
+
+	return l.messages[len(l.messages)-1]
 }
 
-func (*LogRecorder) Printf(format string, args ...any) {
-	panic("implement me")
+// Reset removes the recorded messages.
+func (l *LogRecorder) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = nil
+}
+
+// Messages returns the recorded messages.
+func (l *LogRecorder) Messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Clone(l.messages)
 }
@@ -0,0 +1,168 @@
+package exec
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"sync"
+)
+
+const jsonRPCVersion = "2.0"
+
+type rpcRequest struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      int64     `json:"id"`
+	Method  string    `json:"method"`
+	Params  rpcParams `json:"params"`
+}
+
+type rpcParams struct {
+	FQDN    string `json:"fqdn"`
+	Value   string `json:"value"`
+	Domain  string `json:"domain"`
+	Token   string `json:"token"`
+	KeyAuth string `json:"keyAuth"`
+}
+
+type rpcResponse struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      int64     `json:"id"`
+	Error   *rpcError `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code: %d)", e.Message, e.Code)
+}
+
+// server is the program started once (`<program> serve`) and called over stdin/stdout with JSON-RPC 2.0 requests,
+// one request and one response per line.
+// The program must exit when its stdin is closed.
+type server struct {
+	program string
+	logger  *slog.Logger
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *json.Decoder
+	stderr *io.PipeWriter
+	lastID int64
+}
+
+func (s *server) call(method string, params rpcParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cmd == nil {
+		err := s.start()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.lastID++
+
+	err := json.NewEncoder(s.stdin).Encode(rpcRequest{
+		JSONRPC: jsonRPCVersion,
+		ID:      s.lastID,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		// The program is restarted by the next call.
+		return errors.Join(fmt.Errorf("send request: %w", err), s.stop())
+	}
+
+	var resp rpcResponse
+
+	err = s.stdout.Decode(&resp)
+	if err != nil {
+		return errors.Join(fmt.Errorf("read response: %w", err), s.stop())
+	}
+
+	if resp.ID != s.lastID {
+		return errors.Join(fmt.Errorf("unexpected response ID: got %d, want %d", resp.ID, s.lastID), s.stop())
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	return nil
+}
+
+func (s *server) start() error {
+	cmd := exec.Command(s.program, "serve")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("create pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("create pipe: %w", err)
+	}
+
+	// The output of the program (stderr) is logged.
+	stderr, stderrWriter := io.Pipe()
+	cmd.Stderr = stderrWriter
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("start command: %w", err)
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			s.logger.Info(scanner.Text(), "provider", "exec")
+		}
+	}()
+
+	s.cmd = cmd
+	s.stdin = stdin
+	s.stdout = json.NewDecoder(stdout)
+	s.stderr = stderrWriter
+
+	return nil
+}
+
+// stop closes the stdin of the program and waits for its end.
+// The caller must hold the lock.
+func (s *server) stop() error {
+	if s.cmd == nil {
+		return nil
+	}
+
+	cmd := s.cmd
+
+	s.cmd = nil
+	_ = s.stdin.Close()
+
+	err := cmd.Wait()
+
+	_ = s.stderr.Close()
+
+	if err != nil {
+		return fmt.Errorf("wait command: %w", err)
+	}
+
+	return nil
+}
+
+func (s *server) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stop()
+}
@@ -0,0 +1,95 @@
+package exec
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envFakeServer runs the test binary as a fake long-running program.
+const envFakeServer = "LEGO_EXEC_FAKE_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envFakeServer) != "" {
+		fakeServer()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// fakeServer answers the requests with the PID of the process, to check that the process is started only once.
+// A request for the domain "fail" returns an error.
+func fakeServer() {
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+
+	for scanner.Scan() {
+		var req rpcRequest
+
+		err := json.Unmarshal(scanner.Bytes(), &req)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		resp := rpcResponse{JSONRPC: jsonRPCVersion, ID: req.ID}
+
+		if req.Params.Domain == "fail" {
+			resp.Error = &rpcError{Code: 1, Message: "boom"}
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "%d %s %s %s\n", os.Getpid(), req.Method, req.Params.FQDN, req.Params.Token)
+
+		_ = encoder.Encode(resp)
+	}
+}
+
+func TestDNSProvider_server(t *testing.T) {
+	backupLogger := log.Default()
+
+	defer func() {
+		log.SetDefault(backupLogger)
+	}()
+
+	logRecorder := &LogRecorder{}
+	log.SetDefault(slog.New(logRecorder))
+
+	t.Setenv(envFakeServer, "true")
+
+	provider, err := NewDNSProviderConfig(&Config{Program: os.Args[0], Mode: "SERVER"})
+	require.NoError(t, err)
+
+	err = provider.Present("domain", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = provider.CleanUp("domain", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = provider.Present("fail", "token", "keyAuth")
+	require.EqualError(t, err, "exec: boom (code: 1)")
+
+	err = provider.Close()
+	require.NoError(t, err)
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Len(c, logRecorder.Messages(), 3)
+	}, time.Second, 10*time.Millisecond)
+
+	messages := logRecorder.Messages()
+
+	// The program is started once for all the calls.
+	pid, _, _ := strings.Cut(messages[0], " ")
+
+	assert.Equal(t, pid+" present _acme-challenge.domain. token", messages[0])
+	assert.Equal(t, pid+" cleanup _acme-challenge.domain. token", messages[1])
+	assert.Equal(t, pid+" present _acme-challenge.fail. token", messages[2])
+}
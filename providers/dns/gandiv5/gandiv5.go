@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"sync"
@@ -31,7 +32,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-const minTTL = 300
+const (
+	minTTL = 300
+	maxTTL = 2592000
+)
 
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
@@ -50,16 +54,19 @@ type Config struct {
 	PollingInterval     time.Duration
 	TTL                 int
 	HTTPClient          *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 20*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 20*time.Second),
+		TTL:                env.ClampInt(EnvTTL, env.GetOrDefaultInt(EnvTTL, minTTL), minTTL, maxTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 20*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 20*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 10*time.Second),
 		},
 	}
 }
@@ -96,7 +103,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.APIKey != "" {
-		log.Print("gandiv5: API Key is deprecated, use Personal Access Token instead")
+		log.OrDefault(config.Logger).Warn("gandiv5: API Key is deprecated, use Personal Access Token instead.")
 	}
 
 	if config.APIKey == "" && config.PersonalAccessToken == "" {
@@ -108,6 +115,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	client := internal.NewClient(config.APIKey, config.PersonalAccessToken)
+	client.Logger = config.Logger
 
 	if config.BaseURL != "" {
 		baseURL, err := url.Parse(config.BaseURL)
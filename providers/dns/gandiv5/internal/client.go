@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
@@ -27,6 +28,9 @@ type Client struct {
 
 	BaseURL    *url.URL
 	HTTPClient *http.Client
+
+	// Logger is used to log the messages of the client (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewClient Creates a new Client.
@@ -100,7 +104,7 @@ func (c *Client) addTXTRecord(ctx context.Context, domain, name string, newRecor
 	}
 
 	if message.Message != "" {
-		log.Infof("API response: %s", message.Message)
+		log.OrDefault(c.Logger).Info("gandiv5: API response.", "message", message.Message)
 	}
 
 	return nil
@@ -122,7 +126,7 @@ func (c *Client) DeleteTXTRecord(ctx context.Context, domain, name string) error
 	}
 
 	if message.Message != "" {
-		log.Infof("API response: %s", message.Message)
+		log.OrDefault(c.Logger).Info("gandiv5: API response.", "message", message.Message)
 	}
 
 	return nil
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
@@ -46,7 +48,10 @@ const (
 
 const changeStatusDone = "done"
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout    = (*DNSProvider)(nil)
+	_ challenge.ProviderPropagated = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -59,6 +64,9 @@ type Config struct {
 	PollingInterval           time.Duration
 	TTL                       int
 	HTTPClient                *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -69,8 +77,8 @@ func NewDefaultConfig() *Config {
 		AllowPrivateZone:          env.GetOrDefaultBool(EnvAllowPrivateZone, false),
 		ImpersonateServiceAccount: env.GetOrDefaultString(EnvImpersonateServiceAccount, ""),
 		TTL:                       env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout:        env.GetOrDefaultSecond(EnvPropagationTimeout, 180*time.Second),
-		PollingInterval:           env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+		PropagationTimeout:        env.GetOrDefaultDuration(EnvPropagationTimeout, 180*time.Second),
+		PollingInterval:           env.GetOrDefaultDuration(EnvPollingInterval, 5*time.Second),
 	}
 }
 
@@ -78,6 +86,16 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *gdns.Service
+
+	// changes the changes of the created records (by token).
+	changes   map[string]changeRef
+	changesMu sync.Mutex
+}
+
+// changeRef identifies a change of a zone.
+type changeRef struct {
+	zone string
+	id   string
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Google Cloud DNS.
@@ -184,7 +202,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("googlecloud: unable to create Google Cloud DNS service: %w", err)
 	}
 
-	return &DNSProvider{config: config, client: svc}, nil
+	return &DNSProvider{config: config, client: svc, changes: make(map[string]changeRef)}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
@@ -212,7 +230,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 			rrd = append(rrd, data)
 
 			if data == info.Value {
-				log.Printf("skip: the record already exists: %s", info.Value)
+				log.OrDefault(d.config.Logger).Info("gcloud: skip: the record already exists.", "value", info.Value)
 				return nil
 			}
 		}
@@ -222,7 +240,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	// Attempt to delete the existing records before adding the new one.
 	if len(existingRrSet) > 0 {
-		if err = d.applyChanges(ctx, zone, &gdns.Change{Deletions: existingRrSet}); err != nil {
+		if _, err = d.applyChanges(ctx, zone, &gdns.Change{Deletions: existingRrSet}); err != nil {
 			return fmt.Errorf("googlecloud: %w", err)
 		}
 	}
@@ -247,43 +265,70 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Additions: []*gdns.ResourceRecordSet{rec},
 	}
 
-	if err = d.applyChanges(ctx, zone, change); err != nil {
+	chg, err := d.applyChanges(ctx, zone, change)
+	if err != nil {
 		return fmt.Errorf("googlecloud: %w", err)
 	}
 
+	if chg != nil {
+		d.changesMu.Lock()
+		d.changes[token] = changeRef{zone: zone, id: chg.Id}
+		d.changesMu.Unlock()
+	}
+
 	return nil
 }
 
-func (d *DNSProvider) applyChanges(ctx context.Context, zone string, change *gdns.Change) error {
+// Propagated reports the TXT record as propagated when its change is applied to the Cloud DNS name servers (`done`).
+func (d *DNSProvider) Propagated(_, token string) (bool, error) {
+	d.changesMu.Lock()
+	ref, ok := d.changes[token]
+	d.changesMu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	chg, err := d.client.Changes.Get(d.config.Project, ref.zone, ref.id).Do()
+	if err != nil {
+		return false, fmt.Errorf("googlecloud: failed to get the change %s of the zone %s: %w", ref.id, ref.zone, err)
+	}
+
+	return chg.Status == changeStatusDone, nil
+}
+
+// applyChanges creates the change, and waits until it's done.
+// The returned change is nil if the zone doesn't exist.
+func (d *DNSProvider) applyChanges(ctx context.Context, zone string, change *gdns.Change) (*gdns.Change, error) {
 	if d.config.Debug {
 		data, _ := json.Marshal(change)
-		log.Printf("change (Create): %s", string(data))
+		log.OrDefault(d.config.Logger).Info("gcloud: change (Create).", "change", string(data))
 	}
 
 	chg, err := d.client.Changes.Create(d.config.Project, zone, change).Do()
 	if err != nil {
 		var v *googleapi.Error
 		if errors.As(err, &v) && v.Code == http.StatusNotFound {
-			return nil
+			return nil, nil
 		}
 
 		data, _ := json.Marshal(change)
 
-		return fmt.Errorf("failed to perform changes [zone %s, change %s]: %w", zone, string(data), err)
+		return nil, fmt.Errorf("failed to perform changes [zone %s, change %s]: %w", zone, string(data), err)
 	}
 
 	if chg.Status == changeStatusDone {
-		return nil
+		return chg, nil
 	}
 
 	chgID := chg.Id
 
 	// wait for change to be acknowledged
-	return wait.Retry(ctx,
+	err = wait.Retry(ctx,
 		func() error {
 			if d.config.Debug {
 				data, _ := json.Marshal(change)
-				log.Printf("change (Get): %s", string(data))
+				log.OrDefault(d.config.Logger).Info("gcloud: change (Get).", "change", string(data))
 			}
 
 			chg, err = d.client.Changes.Get(d.config.Project, zone, chgID).Do()
@@ -301,12 +346,21 @@ func (d *DNSProvider) applyChanges(ctx context.Context, zone string, change *gdn
 		backoff.WithBackOff(backoff.NewConstantBackOff(3*time.Second)),
 		backoff.WithMaxElapsedTime(30*time.Second),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return chg, nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
+	d.changesMu.Lock()
+	delete(d.changes, token)
+	d.changesMu.Unlock()
+
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("googlecloud: %w", err)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/dns/v1"
@@ -197,6 +198,37 @@ func TestPresentNoExistingRR(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDNSProvider_Propagated(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /dns/v1/projects/manhattan/managedZones",
+			servermock.JSONEncode(&dns.ManagedZonesListResponse{
+				ManagedZones: []*dns.ManagedZone{
+					{Name: "test", Visibility: "public"},
+				},
+			})).
+		Route("GET /dns/v1/projects/manhattan/managedZones/test/rrsets",
+			servermock.JSONEncode(&dns.ResourceRecordSetsListResponse{})).
+		Route("POST /dns/v1/projects/manhattan/managedZones/test/changes",
+			servermock.JSONEncode(&dns.Change{Id: "42", Status: changeStatusDone})).
+		Route("GET /dns/v1/projects/manhattan/managedZones/test/changes/42",
+			servermock.JSONEncode(&dns.Change{Id: "42", Status: changeStatusDone})).
+		Build(t)
+
+	propagated, err := provider.Propagated("example.com", "abc")
+	require.NoError(t, err)
+
+	// The record is unknown.
+	assert.False(t, propagated)
+
+	err = provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	propagated, err = provider.Propagated("example.com", "abc")
+	require.NoError(t, err)
+
+	assert.True(t, propagated)
+}
+
 func TestPresentWithExistingRR(t *testing.T) {
 	provider := mockBuilder().
 		// getHostedZone
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/gigahostno/internal"
@@ -29,6 +30,11 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+var (
+	_ challenge.ProviderTimeout    = (*DNSProvider)(nil)
+	_ challenge.ProviderConcurrent = (*DNSProvider)(nil)
+)
+
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
 	Username string
@@ -45,10 +51,10 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -117,12 +123,12 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	err := d.authenticate(ctx)
+	tok, err := d.authenticate(ctx)
 	if err != nil {
 		return fmt.Errorf("gigahostno: %w", err)
 	}
 
-	ctx = internal.WithContext(ctx, d.token.Token)
+	ctx = internal.WithContext(ctx, tok)
 
 	zone, err := d.findZone(ctx, info.EffectiveFQDN)
 	if err != nil {
@@ -155,12 +161,12 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	err := d.authenticate(ctx)
+	tok, err := d.authenticate(ctx)
 	if err != nil {
 		return fmt.Errorf("gigahostno: %w", err)
 	}
 
-	ctx = internal.WithContext(ctx, d.token.Token)
+	ctx = internal.WithContext(ctx, tok)
 
 	zone, err := d.findZone(ctx, info.EffectiveFQDN)
 	if err != nil {
@@ -197,22 +203,29 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
-func (d *DNSProvider) authenticate(ctx context.Context) error {
+// Concurrent reports that the provider is safe for concurrent use (challenge.ProviderConcurrent).
+func (d *DNSProvider) Concurrent() bool {
+	return true
+}
+
+// authenticate returns the current token, and renews it if it's expired.
+// The token is only read under the lock: the provider can be used concurrently.
+func (d *DNSProvider) authenticate(ctx context.Context) (string, error) {
 	d.tokenMu.Lock()
 	defer d.tokenMu.Unlock()
 
 	if !d.token.IsExpired() {
-		return nil
+		return d.token.Token, nil
 	}
 
 	tok, err := d.identifier.Authenticate(ctx)
 	if err != nil {
-		return fmt.Errorf("authenticate: %w", err)
+		return "", fmt.Errorf("authenticate: %w", err)
 	}
 
 	d.token = tok
 
-	return nil
+	return d.token.Token, nil
 }
 
 func (d *DNSProvider) findZone(ctx context.Context, fqdn string) (*internal.Zone, error) {
@@ -1,6 +1,7 @@
 package gigahostno
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"net/url"
 	"testing"
@@ -275,3 +276,22 @@ func TestDNSProvider_CleanUp_token_not_expired(t *testing.T) {
 	err := provider.CleanUp("example.com", "abc", "123d==")
 	require.NoError(t, err)
 }
+
+func TestDNSProvider_Present_concurrent(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /authenticate",
+			servermock.ResponseFromInternal("authenticate.json")).
+		Route("GET /dns/zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckHeader().
+				WithAuthorization("Bearer secrettoken")).
+		Route("POST /dns/zones/123/records",
+			servermock.ResponseFromInternal("create_record.json"),
+			servermock.CheckHeader().
+				WithAuthorization("Bearer secrettoken")).
+		Build(t)
+
+	tester.RunConcurrently(t, tester.DefaultConcurrency, func(i int) error {
+		return provider.Present("example.com", fmt.Sprintf("abc%d", i), "123d==")
+	})
+}
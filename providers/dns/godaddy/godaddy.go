@@ -5,11 +5,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/godaddy/internal"
 	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
@@ -28,7 +30,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-const minTTL = 600
+const (
+	minTTL = 600
+	maxTTL = 604800
+)
 
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
@@ -40,16 +45,19 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		TTL:                env.ClampInt(EnvTTL, env.GetOrDefaultInt(EnvTTL, minTTL), minTTL, maxTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 10*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -178,7 +186,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	for _, record := range existingRecords {
-		fmt.Println("Deleting record: ", record)
+		log.OrDefault(d.config.Logger).Debug("godaddy: deleting TXT record.", "name", record.Name, "data", record.Data)
 	}
 
 	err = d.client.DeleteTxtRecords(ctx, authZone, subDomain)
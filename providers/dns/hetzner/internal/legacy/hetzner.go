@@ -13,6 +13,7 @@ import (
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/hetzner/internal/legacy/internal"
 	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/digicert/lego/v4/providers/dns/internal/zonecache"
 )
 
 // Environment variables names.
@@ -44,10 +45,10 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 120*time.Second),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -56,6 +57,8 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+
+	zoneCache *zonecache.Cache
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for hetzner.
@@ -94,7 +97,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
 
-	return &DNSProvider{config: config, client: client}, nil
+	return &DNSProvider{
+		config:    config,
+		client:    client,
+		zoneCache: zonecache.Get("hetzner", config.APIKey),
+	}, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -116,7 +123,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	zoneID, err := d.client.GetZoneID(ctx, zone)
+	zoneID, err := d.zoneCache.Lookup(ctx, zone, func(ctx context.Context) (string, error) {
+		return d.client.GetZoneID(ctx, zone)
+	})
 	if err != nil {
 		return fmt.Errorf("hetzner (legacy): %w", err)
 	}
@@ -154,7 +163,9 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	ctx := context.Background()
 
-	zoneID, err := d.client.GetZoneID(ctx, zone)
+	zoneID, err := d.zoneCache.Lookup(ctx, zone, func(ctx context.Context) (string, error) {
+		return d.client.GetZoneID(ctx, zone)
+	})
 	if err != nil {
 		return fmt.Errorf("hetzner (legacy): %w", err)
 	}
@@ -33,23 +33,23 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey             string
-	HTTPClient         *http.Client
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
+	APIKey             string        `env:"API_KEY,required"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"10s"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"120s"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
-		},
+	config := &Config{
+		TTL:             dns01.DefaultTTL,
+		PollingInterval: dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -65,14 +65,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // HOSTINGNL_APIKEY.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIKey)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("hostingnl: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIKey = values[EnvAPIKey]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -32,6 +34,14 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+// Modes.
+const (
+	modeRaw = "RAW"
+
+	// modeRawV2 is the RAW mode where the server can answer with propagation hints and a cleanup token.
+	modeRawV2 = "RAW_V2"
+)
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 type message struct {
@@ -43,6 +53,19 @@ type messageRaw struct {
 	Domain  string `json:"domain"`
 	Token   string `json:"token"`
 	KeyAuth string `json:"keyAuth"`
+
+	// CleanupToken is the token returned by the server when the record was created (RAW_V2 mode only).
+	CleanupToken string `json:"cleanupToken,omitempty"`
+}
+
+// presentResponse is the optional response of the server to a present request (RAW_V2 mode only).
+type presentResponse struct {
+	// Wait the time to wait (in seconds) before checking the propagation of the record.
+	Wait int `json:"wait,omitempty"`
+	// Nameservers the name servers to query to check the propagation of the record.
+	Nameservers []string `json:"nameservers,omitempty"`
+	// CleanupToken the token to send with the cleanup request of the record.
+	CleanupToken string `json:"cleanupToken,omitempty"`
 }
 
 // Config is used to configure the creation of the DNSProvider.
@@ -59,10 +82,10 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -70,6 +93,11 @@ func NewDefaultConfig() *Config {
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
+
+	// hints and cleanup tokens returned by the server (RAW_V2 mode only).
+	responsesMu sync.Mutex
+	hints       map[string]presentResponse // by FQDN
+	cleanup     map[string]string          // by challenge token
 }
 
 // NewDNSProvider returns a DNSProvider instance.
@@ -105,7 +133,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	config.HTTPClient = clientdebug.Wrap(config.HTTPClient)
 
-	return &DNSProvider{config: config}, nil
+	return &DNSProvider{
+		config:  config,
+		hints:   make(map[string]presentResponse),
+		cleanup: make(map[string]string),
+	}, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -114,22 +146,56 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// PropagationHints returns the propagation hints sent by the server when the record was created (RAW_V2 mode only).
+func (d *DNSProvider) PropagationHints(fqdn string) (time.Duration, []string) {
+	d.responsesMu.Lock()
+	defer d.responsesMu.Unlock()
+
+	hints := d.hints[fqdn]
+
+	return time.Duration(hints.Wait) * time.Second, hints.Nameservers
+}
+
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	if d.config.Mode == "RAW" {
+	switch d.config.Mode {
+	case modeRaw:
 		msg := &messageRaw{
 			Domain:  domain,
 			Token:   token,
 			KeyAuth: keyAuth,
 		}
 
-		err := d.doPost(ctx, "/present", msg)
+		err := d.doPost(ctx, "/present", msg, nil)
+		if err != nil {
+			return fmt.Errorf("httpreq: %w", err)
+		}
+
+		return nil
+
+	case modeRawV2:
+		msg := &messageRaw{
+			Domain:  domain,
+			Token:   token,
+			KeyAuth: keyAuth,
+		}
+
+		var result presentResponse
+
+		err := d.doPost(ctx, "/present", msg, &result)
 		if err != nil {
 			return fmt.Errorf("httpreq: %w", err)
 		}
 
+		info := dns01.GetChallengeInfo(domain, keyAuth)
+
+		d.responsesMu.Lock()
+		d.hints[info.EffectiveFQDN] = result
+		d.cleanup[token] = result.CleanupToken
+		d.responsesMu.Unlock()
+
 		return nil
 	}
 
@@ -139,7 +205,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Value: info.Value,
 	}
 
-	err := d.doPost(ctx, "/present", msg)
+	err := d.doPost(ctx, "/present", msg, nil)
 	if err != nil {
 		return fmt.Errorf("httpreq: %w", err)
 	}
@@ -151,14 +217,38 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	if d.config.Mode == "RAW" {
+	switch d.config.Mode {
+	case modeRaw:
 		msg := &messageRaw{
 			Domain:  domain,
 			Token:   token,
 			KeyAuth: keyAuth,
 		}
 
-		err := d.doPost(ctx, "/cleanup", msg)
+		err := d.doPost(ctx, "/cleanup", msg, nil)
+		if err != nil {
+			return fmt.Errorf("httpreq: %w", err)
+		}
+
+		return nil
+
+	case modeRawV2:
+		info := dns01.GetChallengeInfo(domain, keyAuth)
+
+		d.responsesMu.Lock()
+		cleanupToken := d.cleanup[token]
+		delete(d.cleanup, token)
+		delete(d.hints, info.EffectiveFQDN)
+		d.responsesMu.Unlock()
+
+		msg := &messageRaw{
+			Domain:       domain,
+			Token:        token,
+			KeyAuth:      keyAuth,
+			CleanupToken: cleanupToken,
+		}
+
+		err := d.doPost(ctx, "/cleanup", msg, nil)
 		if err != nil {
 			return fmt.Errorf("httpreq: %w", err)
 		}
@@ -172,7 +262,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		Value: info.Value,
 	}
 
-	err := d.doPost(ctx, "/cleanup", msg)
+	err := d.doPost(ctx, "/cleanup", msg, nil)
 	if err != nil {
 		return fmt.Errorf("httpreq: %w", err)
 	}
@@ -180,7 +270,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
-func (d *DNSProvider) doPost(ctx context.Context, uri string, msg any) error {
+func (d *DNSProvider) doPost(ctx context.Context, uri string, msg, result any) error {
 	reqBody := new(bytes.Buffer)
 
 	err := json.NewEncoder(reqBody).Encode(msg)
@@ -213,5 +303,24 @@ func (d *DNSProvider) doPost(ctx context.Context, uri string, msg any) error {
 		return errutils.NewUnexpectedResponseStatusCodeError(req, resp)
 	}
 
+	if result == nil {
+		return nil
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	// The response is optional.
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
 	return nil
 }
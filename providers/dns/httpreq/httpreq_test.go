@@ -4,9 +4,11 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -134,6 +136,26 @@ func TestNewDNSProvider_Present(t *testing.T) {
 			builder:       mockBuilder("RAW"),
 			expectedError: "httpreq: unexpected status code: [status code: 404] body: 404 page not found",
 		},
+		{
+			desc: "success raw v2 mode",
+			builder: mockBuilder("RAW_V2").
+				Route("/present",
+					servermock.RawStringResponse(`{"wait":30,"nameservers":["ns1.example.com"],"cleanupToken":"abc"}`),
+					servermock.CheckRequestBody(`{"domain":"domain","token":"token","keyAuth":"key"}`)),
+		},
+		{
+			desc: "success raw v2 mode without response",
+			builder: mockBuilder("RAW_V2").
+				Route("/present",
+					servermock.Noop(),
+					servermock.CheckRequestBody(`{"domain":"domain","token":"token","keyAuth":"key"}`)),
+		},
+		{
+			desc: "error raw v2 mode invalid response",
+			builder: mockBuilder("RAW_V2").
+				Route("/present", servermock.RawStringResponse("lego")),
+			expectedError: "httpreq: unable to unmarshal response: [status code: 200] body: lego error: invalid character 'l' looking for beginning of value",
+		},
 		{
 			desc: "basic auth fail",
 			builder: mockBuilderWithBasicAuth("nope", "nope").
@@ -224,6 +246,32 @@ func TestNewDNSProvider_Cleanup(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_rawV2(t *testing.T) {
+	envTest.RestoreEnv()
+
+	p := mockBuilder("RAW_V2").
+		Route("/present",
+			servermock.RawStringResponse(`{"wait":30,"nameservers":["ns1.example.com"],"cleanupToken":"abc"}`)).
+		Route("/cleanup",
+			servermock.Noop(),
+			servermock.CheckRequestBody(`{"domain":"domain","token":"token","keyAuth":"key","cleanupToken":"abc"}`)).
+		Build(t)
+
+	err := p.Present("domain", "token", "key")
+	require.NoError(t, err)
+
+	wait, nameservers := p.PropagationHints("_acme-challenge.domain.")
+	assert.Equal(t, 30*time.Second, wait)
+	assert.Equal(t, []string{"ns1.example.com"}, nameservers)
+
+	err = p.CleanUp("domain", "token", "key")
+	require.NoError(t, err)
+
+	wait, nameservers = p.PropagationHints("_acme-challenge.domain.")
+	assert.Zero(t, wait)
+	assert.Empty(t, nameservers)
+}
+
 func mockBuilder(mode string) *servermock.Builder[*DNSProvider] {
 	return servermock.NewBuilder(
 		func(server *httptest.Server) (*DNSProvider, error) {
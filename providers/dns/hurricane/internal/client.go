@@ -5,13 +5,14 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
 	"golang.org/x/time/rate"
 )
@@ -36,6 +37,9 @@ type Client struct {
 	HTTPClient   *http.Client
 	rateLimiters sync.Map
 
+	// Logger is used to log the messages of the client (optional: the default logger is used if nil).
+	Logger *slog.Logger
+
 	baseURL string
 
 	credentials map[string]string
@@ -98,17 +102,17 @@ func (c *Client) UpdateTxtRecord(ctx context.Context, hostname, txt string) erro
 		return errutils.NewReadResponseError(req, resp.StatusCode, err)
 	}
 
-	return evaluateBody(string(bytes.TrimSpace(raw)), hostname)
+	return c.evaluateBody(string(bytes.TrimSpace(raw)), hostname)
 }
 
-func evaluateBody(body, hostname string) error {
+func (c *Client) evaluateBody(body, hostname string) error {
 	code, _, _ := strings.Cut(body, " ")
 
 	switch code {
 	case codeGood:
 		return nil
 	case codeNoChg:
-		log.Printf("%s: unchanged content written to TXT record %s", body, hostname)
+		log.OrDefault(c.Logger).Info("hurricane: unchanged content written to TXT record.", "response", body, "hostname", hostname)
 		return nil
 	case codeAbuse:
 		return fmt.Errorf("%s: blocked hostname for abuse: %s", body, hostname)
@@ -1,15 +1,20 @@
-// Package ibmcloud implements a DNS provider for solving the DNS-01 challenge using IBM Cloud (SoftLayer).
+// Package ibmcloud implements a DNS provider for solving the DNS-01 challenge using IBM Cloud (SoftLayer or Cloud Internet Services).
 package ibmcloud
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/ibmcloud/internal"
+	"github.com/digicert/lego/v4/providers/dns/ibmcloud/internal/cis"
+	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
 	"github.com/softlayer/softlayer-go/session"
 )
 
@@ -33,12 +38,27 @@ const (
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 )
 
+// Environment variables names for Cloud Internet Services (CIS).
+const (
+	envCISNamespace = "IBMCLOUD_"
+
+	// EnvCISAPIKey the IAM API key, the name is the same as the one used by the IBM Cloud CLI.
+	EnvCISAPIKey = envCISNamespace + "API_KEY"
+	EnvCISCRN    = envCISNamespace + "CIS_CRN"
+)
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Username           string
-	APIKey             string
+	// Classic Infrastructure (SoftLayer).
+	Username string
+	APIKey   string
+
+	// Cloud Internet Services (CIS): used when CISCRN is defined.
+	CISAPIKey string
+	CISCRN    string
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
@@ -50,9 +70,9 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPTimeout:        env.GetOrDefaultSecond(EnvHTTPTimeout, session.DefaultTimeout),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+		HTTPTimeout:        env.GetOrDefaultDuration(EnvHTTPTimeout, session.DefaultTimeout),
 	}
 }
 
@@ -60,12 +80,26 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config  *Config
 	wrapper *internal.Wrapper
+
+	cisClient   *cis.Client
+	recordIDs   map[string]cisRecordID
+	recordIDsMu sync.Mutex
+}
+
+type cisRecordID struct {
+	zoneID   string
+	recordID string
 }
 
-// NewDNSProvider returns a DNSProvider instance configured for IBM Cloud (SoftLayer).
+// NewDNSProvider returns a DNSProvider instance configured for IBM Cloud (SoftLayer or CIS).
 // Credentials must be passed in the environment variables:
-// SOFTLAYER_USERNAME, SOFTLAYER_API_KEY.
+// SOFTLAYER_USERNAME, SOFTLAYER_API_KEY for the Classic Infrastructure (SoftLayer),
+// or IBMCLOUD_API_KEY, IBMCLOUD_CIS_CRN for Cloud Internet Services (CIS).
 func NewDNSProvider() (*DNSProvider, error) {
+	if env.GetOrFile(EnvCISCRN) != "" {
+		return newCISDNSProvider()
+	}
+
 	values, err := env.Get(EnvUsername, EnvAPIKey)
 	if err != nil {
 		return nil, fmt.Errorf("ibmcloud: %w", err)
@@ -79,12 +113,29 @@ func NewDNSProvider() (*DNSProvider, error) {
 	return NewDNSProviderConfig(config)
 }
 
-// NewDNSProviderConfig return a DNSProvider instance configured for IBM Cloud (SoftLayer).
+func newCISDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvCISAPIKey, EnvCISCRN)
+	if err != nil {
+		return nil, fmt.Errorf("ibmcloud: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.CISAPIKey = values[EnvCISAPIKey]
+	config.CISCRN = values[EnvCISCRN]
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for IBM Cloud (SoftLayer or CIS).
 func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	if config == nil {
 		return nil, errors.New("ibmcloud: the configuration of the DNS provider is nil")
 	}
 
+	if config.CISCRN != "" {
+		return newCISDNSProviderConfig(config)
+	}
+
 	if config.Username == "" {
 		return nil, errors.New("ibmcloud: username is missing")
 	}
@@ -101,6 +152,21 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	return &DNSProvider{wrapper: internal.NewWrapper(sess), config: config}, nil
 }
 
+func newCISDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	client, err := cis.NewClient(config.CISAPIKey, config.CISCRN)
+	if err != nil {
+		return nil, fmt.Errorf("ibmcloud: %w", err)
+	}
+
+	client.HTTPClient = clientdebug.Wrap(&http.Client{Timeout: config.HTTPTimeout})
+
+	return &DNSProvider{
+		config:    config,
+		cisClient: client,
+		recordIDs: make(map[string]cisRecordID),
+	}, nil
+}
+
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
 // Adjusting here to cope with spikes in propagation times.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
@@ -109,6 +175,10 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	if d.cisClient != nil {
+		return d.presentCIS(domain, token, keyAuth)
+	}
+
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
@@ -122,6 +192,10 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	if d.cisClient != nil {
+		return d.cleanUpCIS(domain, token, keyAuth)
+	}
+
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
@@ -132,3 +206,81 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+func (d *DNSProvider) presentCIS(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	ctx, err := d.cisClient.CreateAuthenticatedContext(context.Background())
+	if err != nil {
+		return fmt.Errorf("ibmcloud: CIS: %w", err)
+	}
+
+	zone, err := d.findCISZone(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("ibmcloud: CIS: %w", err)
+	}
+
+	record := cis.Record{
+		Name:    dns01.UnFqdn(info.EffectiveFQDN),
+		Type:    "TXT",
+		Content: info.Value,
+		TTL:     d.config.TTL,
+	}
+
+	newRecord, err := d.cisClient.CreateRecord(ctx, zone.ID, record)
+	if err != nil {
+		return fmt.Errorf("ibmcloud: CIS: create record: %w", err)
+	}
+
+	d.recordIDsMu.Lock()
+	d.recordIDs[token] = cisRecordID{zoneID: zone.ID, recordID: newRecord.ID}
+	d.recordIDsMu.Unlock()
+
+	return nil
+}
+
+func (d *DNSProvider) cleanUpCIS(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	d.recordIDsMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("ibmcloud: CIS: unknown record ID for '%s'", info.EffectiveFQDN)
+	}
+
+	ctx, err := d.cisClient.CreateAuthenticatedContext(context.Background())
+	if err != nil {
+		return fmt.Errorf("ibmcloud: CIS: %w", err)
+	}
+
+	err = d.cisClient.DeleteRecord(ctx, recordID.zoneID, recordID.recordID)
+	if err != nil {
+		return fmt.Errorf("ibmcloud: CIS: delete record: %w", err)
+	}
+
+	d.recordIDsMu.Lock()
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
+	return nil
+}
+
+// findCISZone finds the most specific zone of the CIS instance matching the FQDN.
+func (d *DNSProvider) findCISZone(ctx context.Context, fqdn string) (*cis.Zone, error) {
+	zones, err := d.cisClient.GetZones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get zones: %w", err)
+	}
+
+	for domain := range dns01.UnFqdnDomainsSeq(fqdn) {
+		for _, zone := range zones {
+			if zone.Name == domain {
+				return &zone, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("zone not found for %q", fqdn)
+}
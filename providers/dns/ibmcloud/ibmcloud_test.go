@@ -1,16 +1,22 @@
 package ibmcloud
 
 import (
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/require"
 )
 
 const envDomain = envNamespace + "DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvUsername, EnvAPIKey).
+const testCRN = "crn:v1:bluemix:public:internet-svcs:global:a/0123456789abcdef:abcd-1234::"
+
+var envTest = tester.NewEnvTest(EnvUsername, EnvAPIKey, EnvCISAPIKey, EnvCISCRN).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -50,6 +56,22 @@ func TestNewDNSProvider(t *testing.T) {
 			},
 			expected: "ibmcloud: some credentials information are missing: SOFTLAYER_API_KEY",
 		},
+		{
+			desc: "success CIS",
+			envVars: map[string]string{
+				EnvCISAPIKey: "456",
+				EnvCISCRN:    testCRN,
+			},
+		},
+		{
+			desc: "CIS missing API key",
+			envVars: map[string]string{
+				EnvUsername: "123",
+				EnvAPIKey:   "456",
+				EnvCISCRN:   testCRN,
+			},
+			expected: "ibmcloud: some credentials information are missing: IBMCLOUD_API_KEY",
+		},
 	}
 
 	for _, test := range testCases {
@@ -66,7 +88,7 @@ func TestNewDNSProvider(t *testing.T) {
 				require.NoError(t, err)
 				require.NotNil(t, p)
 				require.NotNil(t, p.config)
-				require.NotNil(t, p.wrapper)
+				require.True(t, p.wrapper != nil || p.cisClient != nil)
 			} else {
 				require.EqualError(t, err, test.expected)
 			}
@@ -76,10 +98,12 @@ func TestNewDNSProvider(t *testing.T) {
 
 func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
-		desc     string
-		username string
-		apiKey   string
-		expected string
+		desc      string
+		username  string
+		apiKey    string
+		cisAPIKey string
+		cisCRN    string
+		expected  string
 	}{
 		{
 			desc:     "success",
@@ -100,6 +124,18 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			username: "123",
 			expected: "ibmcloud: API key is missing",
 		},
+		{
+			desc:      "success CIS",
+			cisAPIKey: "456",
+			cisCRN:    testCRN,
+		},
+		{
+			desc:     "CIS missing API key",
+			username: "123",
+			apiKey:   "456",
+			cisCRN:   testCRN,
+			expected: "ibmcloud: credentials missing",
+		},
 	}
 
 	for _, test := range testCases {
@@ -107,6 +143,8 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			config := NewDefaultConfig()
 			config.Username = test.username
 			config.APIKey = test.apiKey
+			config.CISAPIKey = test.cisAPIKey
+			config.CISCRN = test.cisCRN
 
 			p, err := NewDNSProviderConfig(config)
 
@@ -114,7 +152,7 @@ func TestNewDNSProviderConfig(t *testing.T) {
 				require.NoError(t, err)
 				require.NotNil(t, p)
 				require.NotNil(t, p.config)
-				require.NotNil(t, p.wrapper)
+				require.True(t, p.wrapper != nil || p.cisClient != nil)
 			} else {
 				require.EqualError(t, err, test.expected)
 			}
@@ -122,6 +160,39 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_Present_CIS(t *testing.T) {
+	provider := mockCISBuilder().
+		Route("POST /token",
+			servermock.ResponseFromFile(filepath.Join("internal", "cis", "fixtures", "token.json"))).
+		Route("GET /api/{crn}/zones",
+			servermock.ResponseFromFile(filepath.Join("internal", "cis", "fixtures", "zones.json"))).
+		Route("POST /api/{crn}/zones/9a7806061c88ada191ed06f989cc3dac/dns_records",
+			servermock.ResponseFromFile(filepath.Join("internal", "cis", "fixtures", "create_record.json")),
+			servermock.CheckRequestJSONBody(`{"name":"_acme-challenge.example.com","type":"TXT","content":"123d==","ttl":120}`)).
+		Build(t)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Equal(t, cisRecordID{zoneID: "9a7806061c88ada191ed06f989cc3dac", recordID: "f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c"}, provider.recordIDs["abc"])
+}
+
+func TestDNSProvider_CleanUp_CIS(t *testing.T) {
+	provider := mockCISBuilder().
+		Route("POST /token",
+			servermock.ResponseFromFile(filepath.Join("internal", "cis", "fixtures", "token.json"))).
+		Route("DELETE /api/{crn}/zones/9a7806061c88ada191ed06f989cc3dac/dns_records/f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c",
+			servermock.ResponseFromFile(filepath.Join("internal", "cis", "fixtures", "delete_record.json"))).
+		Build(t)
+
+	provider.recordIDs["abc"] = cisRecordID{zoneID: "9a7806061c88ada191ed06f989cc3dac", recordID: "f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c"}
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Empty(t, provider.recordIDs)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -151,3 +222,24 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func mockCISBuilder() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			config := NewDefaultConfig()
+			config.CISAPIKey = "secret"
+			config.CISCRN = testCRN
+
+			p, err := NewDNSProviderConfig(config)
+			if err != nil {
+				return nil, err
+			}
+
+			p.cisClient.HTTPClient = server.Client()
+			p.cisClient.APIEndpoint, _ = url.Parse(server.URL + "/api")
+			p.cisClient.AuthEndpoint, _ = url.Parse(server.URL + "/token")
+
+			return p, nil
+		},
+	)
+}
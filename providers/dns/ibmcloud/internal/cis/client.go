@@ -0,0 +1,202 @@
+// Package cis contains a client for the DNS API of IBM Cloud Internet Services (CIS).
+package cis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
+)
+
+// Default API endpoints.
+const (
+	APIBaseURL  = "https://api.cis.cloud.ibm.com/v1"
+	AuthBaseURL = "https://iam.cloud.ibm.com/identity/token"
+)
+
+const perPage = 50
+
+// Client the CIS API client.
+type Client struct {
+	apiKey string
+	crn    string
+
+	APIEndpoint  *url.URL
+	AuthEndpoint *url.URL
+	HTTPClient   *http.Client
+
+	token   *Token
+	muToken sync.Mutex
+}
+
+// NewClient creates a new Client.
+// The crn is the CRN of the CIS instance.
+func NewClient(apiKey, crn string) (*Client, error) {
+	if apiKey == "" {
+		return nil, errors.New("credentials missing")
+	}
+
+	if crn == "" {
+		return nil, errors.New("instance CRN missing")
+	}
+
+	apiEndpoint, _ := url.Parse(APIBaseURL)
+	authEndpoint, _ := url.Parse(AuthBaseURL)
+
+	return &Client{
+		apiKey:       apiKey,
+		crn:          crn,
+		APIEndpoint:  apiEndpoint,
+		AuthEndpoint: authEndpoint,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// GetZones lists the zones of the CIS instance.
+// https://cloud.ibm.com/apidocs/cis#list-zones
+func (c *Client) GetZones(ctx context.Context) ([]Zone, error) {
+	var zones []Zone
+
+	for page := 1; ; page++ {
+		endpoint := c.baseEndpoint().JoinPath("zones")
+
+		query := endpoint.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(perPage))
+		endpoint.RawQuery = query.Encode()
+
+		req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result APIResponse[[]Zone]
+
+		err = c.do(req, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		zones = append(zones, result.Result...)
+
+		if result.ResultInfo == nil || len(result.Result) < perPage || len(zones) >= result.ResultInfo.TotalCount {
+			return zones, nil
+		}
+	}
+}
+
+// CreateRecord creates a DNS record.
+// https://cloud.ibm.com/apidocs/cis#create-dns-record
+func (c *Client) CreateRecord(ctx context.Context, zoneID string, record Record) (*Record, error) {
+	endpoint := c.baseEndpoint().JoinPath("zones", zoneID, "dns_records")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, record)
+	if err != nil {
+		return nil, err
+	}
+
+	var result APIResponse[*Record]
+
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
+
+// DeleteRecord deletes a DNS record.
+// https://cloud.ibm.com/apidocs/cis#delete-dns-record
+func (c *Client) DeleteRecord(ctx context.Context, zoneID, recordID string) error {
+	endpoint := c.baseEndpoint().JoinPath("zones", zoneID, "dns_records", recordID)
+
+	req, err := newJSONRequest(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, &APIResponse[json.RawMessage]{})
+}
+
+// baseEndpoint the CRN contains slashes, so it must be escaped to be a single path segment.
+func (c *Client) baseEndpoint() *url.URL {
+	return c.APIEndpoint.JoinPath(url.PathEscape(c.crn))
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	tok := getToken(req.Context())
+	if tok == nil {
+		return errors.New("not logged in")
+	}
+
+	req.Header.Set("X-Auth-User-Token", "Bearer "+tok.AccessToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseError(req, resp)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(buf).Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func parseError(req *http.Request, resp *http.Response) error {
+	raw, _ := io.ReadAll(resp.Body)
+
+	errAPI := &APIError{}
+
+	err := json.Unmarshal(raw, errAPI)
+	if err != nil || len(errAPI.Errors) == 0 {
+		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+	}
+
+	return fmt.Errorf("[status code %d] %w", resp.StatusCode, errAPI)
+}
@@ -0,0 +1,137 @@
+package cis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCRN = "crn:v1:bluemix:public:internet-svcs:global:a/0123456789abcdef:abcd-1234::"
+
+func mockContext(t *testing.T) context.Context {
+	t.Helper()
+
+	return context.WithValue(t.Context(), tokenKey, &Token{AccessToken: "xxx"})
+}
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient("secret", testCRN)
+			if err != nil {
+				return nil, err
+			}
+
+			client.HTTPClient = server.Client()
+			client.APIEndpoint, _ = url.Parse(server.URL)
+
+			return client, nil
+		},
+		servermock.CheckHeader().
+			WithAccept("application/json").
+			With("X-Auth-User-Token", "Bearer xxx"))
+}
+
+func TestClient_GetZones(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /{crn}/zones",
+			servermock.ResponseFromFixture("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("page", "1").
+				With("per_page", "50"),
+			checkCRN()).
+		Build(t)
+
+	zones, err := client.GetZones(mockContext(t))
+	require.NoError(t, err)
+
+	expected := []Zone{
+		{ID: "9a7806061c88ada191ed06f989cc3dac", Name: "example.com", Status: "active"},
+		{ID: "023e105f4ecef8ad9ca31a8372d0c353", Name: "example.org", Status: "active"},
+	}
+
+	assert.Equal(t, expected, zones)
+}
+
+func TestClient_GetZones_error(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /{crn}/zones",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	_, err := client.GetZones(mockContext(t))
+	require.EqualError(t, err, "[status code 400] 1001: Invalid zone identifier")
+}
+
+func TestClient_CreateRecord(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /{crn}/zones/9a7806061c88ada191ed06f989cc3dac/dns_records",
+			servermock.ResponseFromFixture("create_record.json"),
+			servermock.CheckRequestJSONBody(`{"name":"_acme-challenge.example.com","type":"TXT","content":"txtTXTtxt","ttl":120}`),
+			checkCRN()).
+		Build(t)
+
+	record := Record{
+		Name:    "_acme-challenge.example.com",
+		Type:    "TXT",
+		Content: "txtTXTtxt",
+		TTL:     120,
+	}
+
+	result, err := client.CreateRecord(mockContext(t), "9a7806061c88ada191ed06f989cc3dac", record)
+	require.NoError(t, err)
+
+	expected := &Record{
+		ID:       "f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c",
+		ZoneID:   "9a7806061c88ada191ed06f989cc3dac",
+		ZoneName: "example.com",
+		Name:     "_acme-challenge.example.com",
+		Type:     "TXT",
+		Content:  "txtTXTtxt",
+		TTL:      120,
+	}
+
+	assert.Equal(t, expected, result)
+}
+
+func TestClient_DeleteRecord(t *testing.T) {
+	client := mockBuilder().
+		Route("DELETE /{crn}/zones/9a7806061c88ada191ed06f989cc3dac/dns_records/f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c",
+			servermock.ResponseFromFixture("delete_record.json"),
+			checkCRN()).
+		Build(t)
+
+	err := client.DeleteRecord(mockContext(t), "9a7806061c88ada191ed06f989cc3dac", "f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c")
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteRecord_error(t *testing.T) {
+	client := mockBuilder().
+		Route("DELETE /{crn}/zones/9a7806061c88ada191ed06f989cc3dac/dns_records/f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusNotFound)).
+		Build(t)
+
+	err := client.DeleteRecord(mockContext(t), "9a7806061c88ada191ed06f989cc3dac", "f1ac2a45e8a4d1d7c13f2d6a5d1b8e2c")
+	require.EqualError(t, err, "[status code 404] 1001: Invalid zone identifier")
+}
+
+func checkCRN() servermock.LinkFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.PathValue("crn") != testCRN {
+				http.Error(rw, "invalid CRN: "+req.PathValue("crn"), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
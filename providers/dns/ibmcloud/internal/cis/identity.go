@@ -0,0 +1,108 @@
+package cis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
+)
+
+type token string
+
+const tokenKey token = "token"
+
+// obtainToken exchanges the API key for an IAM access token.
+// https://cloud.ibm.com/docs/account?topic=account-iamtoken_from_apikey
+func (c *Client) obtainToken(ctx context.Context) (*Token, error) {
+	data := make(url.Values)
+	data.Set("grant_type", "urn:ibm:params:oauth:grant-type:apikey")
+	data.Set("apikey", c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.AuthEndpoint.String(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseIAMError(req, resp)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	tok := Token{}
+
+	err = json.Unmarshal(raw, &tok)
+	if err != nil {
+		return nil, errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	if tok.AccessToken == "" {
+		return nil, errors.New("empty access token")
+	}
+
+	// Renews the token 5 minutes before its expiration.
+	tok.Deadline = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - 5*time.Minute)
+
+	return &tok, nil
+}
+
+func (c *Client) CreateAuthenticatedContext(ctx context.Context) (context.Context, error) {
+	c.muToken.Lock()
+	defer c.muToken.Unlock()
+
+	if c.token != nil && time.Now().Before(c.token.Deadline) {
+		// Already authenticated, stop now
+		return context.WithValue(ctx, tokenKey, c.token), nil
+	}
+
+	tok, err := c.obtainToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.token = tok
+
+	return context.WithValue(ctx, tokenKey, tok), nil
+}
+
+func getToken(ctx context.Context) *Token {
+	tok, ok := ctx.Value(tokenKey).(*Token)
+	if !ok {
+		return nil
+	}
+
+	return tok
+}
+
+func parseIAMError(req *http.Request, resp *http.Response) error {
+	raw, _ := io.ReadAll(resp.Body)
+
+	errResp := &IAMError{}
+
+	err := json.Unmarshal(raw, errResp)
+	if err != nil || errResp.ErrorCode == "" {
+		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+	}
+
+	return fmt.Errorf("%d: %w", resp.StatusCode, errResp)
+}
@@ -0,0 +1,69 @@
+package cis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupIdentityClient(server *httptest.Server) (*Client, error) {
+	client, err := NewClient("secret", testCRN)
+	if err != nil {
+		return nil, err
+	}
+
+	client.HTTPClient = server.Client()
+	client.AuthEndpoint, _ = url.Parse(server.URL)
+
+	return client, nil
+}
+
+func TestClient_obtainToken(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupIdentityClient,
+		servermock.CheckHeader().
+			WithContentTypeFromURLEncoded(),
+	).
+		Route("POST /", servermock.ResponseFromFixture("token.json"),
+			servermock.CheckForm().Strict().
+				With("grant_type", "urn:ibm:params:oauth:grant-type:apikey").
+				With("apikey", "secret"),
+		).
+		Build(t)
+
+	tok, err := client.obtainToken(t.Context())
+	require.NoError(t, err)
+
+	assert.NotZero(t, tok.Deadline)
+	assert.Equal(t, "secret-token", tok.AccessToken)
+}
+
+func TestClient_obtainToken_error(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupIdentityClient).
+		Route("POST /", servermock.ResponseFromFixture("token_error.json").
+			WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	_, err := client.obtainToken(t.Context())
+	require.EqualError(t, err, "400: BXNIM0415E: Provided API key could not be found.")
+}
+
+func TestClient_CreateAuthenticatedContext(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupIdentityClient).
+		Route("POST /", servermock.ResponseFromFixture("token.json")).
+		Build(t)
+
+	ctx, err := client.CreateAuthenticatedContext(t.Context())
+	require.NoError(t, err)
+
+	tok := getToken(ctx)
+	require.NotNil(t, tok)
+	assert.Equal(t, "secret-token", tok.AccessToken)
+
+	// the token is cached.
+	assert.Same(t, tok, client.token)
+}
@@ -0,0 +1,81 @@
+package cis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Token an IAM access token.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// Number in seconds before the expiration
+	ExpiresIn int `json:"expires_in"`
+	// Expiration time (Unix timestamp)
+	Expiration int64 `json:"expiration"`
+
+	Deadline time.Time `json:"-"`
+}
+
+type IAMError struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+func (e *IAMError) Error() string {
+	return fmt.Sprintf("%s: %s", e.ErrorCode, e.ErrorMessage)
+}
+
+type APIResponse[T any] struct {
+	Result     T           `json:"result"`
+	ResultInfo *ResultInfo `json:"result_info,omitempty"`
+	Success    bool        `json:"success"`
+	Errors     []Message   `json:"errors,omitempty"`
+	Messages   []Message   `json:"messages,omitempty"`
+}
+
+type ResultInfo struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Count      int `json:"count"`
+	TotalCount int `json:"total_count"`
+}
+
+type Message struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (m Message) String() string {
+	return fmt.Sprintf("%d: %s", m.Code, m.Message)
+}
+
+type APIError struct {
+	Errors []Message `json:"errors"`
+}
+
+func (a *APIError) Error() string {
+	var msg []string
+	for _, e := range a.Errors {
+		msg = append(msg, e.String())
+	}
+
+	return strings.Join(msg, ", ")
+}
+
+type Zone struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+type Record struct {
+	ID       string `json:"id,omitempty"`
+	ZoneID   string `json:"zone_id,omitempty"`
+	ZoneName string `json:"zone_name,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Content  string `json:"content,omitempty"`
+	TTL      int    `json:"ttl,omitempty"`
+}
@@ -33,21 +33,21 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	AccessKey          string
-	SecretKey          string
-	DoServiceCode      string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
+	AccessKey          string        `env:"API_ACCESS_KEY,required"`
+	SecretKey          string        `env:"API_SECRET_KEY,required"`
+	DoServiceCode      string        `env:"DO_SERVICE_CODE,required"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"2m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"4s"`
+	TTL                int           `env:"TTL" default:"300"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 4*time.Second),
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -58,16 +58,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for IIJ DNS.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIAccessKey, EnvAPISecretKey, EnvDoServiceCode)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("iij: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.AccessKey = values[EnvAPIAccessKey]
-	config.SecretKey = values[EnvAPISecretKey]
-	config.DoServiceCode = values[EnvDoServiceCode]
-
 	return NewDNSProviderConfig(config)
 }
 
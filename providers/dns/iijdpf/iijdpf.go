@@ -45,8 +45,8 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		Endpoint:           env.GetOrDefaultString(EnvAPIEndpoint, dpfapi.DefaultEndpoint),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 660*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 660*time.Second),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 5*time.Second),
 		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -25,6 +26,9 @@ const DefaultBaseURL = "https://api.infomaniak.com"
 type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
+
+	// Logger is used to log the messages of the client (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // New Creates a new Infomaniak client.
@@ -91,7 +95,7 @@ func (c *Client) GetDomainByName(ctx context.Context, name string) (*DNSDomain,
 			return domain, nil
 		}
 
-		log.Infof("domain %q not found, trying with %q", name, name[i+1:])
+		log.OrDefault(c.Logger).Info("infomaniak: domain not found, trying with the parent.", "domain", name, "parent", name[i+1:])
 
 		name = name[i+1:]
 	}
@@ -7,10 +7,14 @@ import (
 	"net/http/httputil"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/digicert/lego/v4/metrics"
 	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/platform/debugcapture"
 )
 
 const replacement = "***"
@@ -45,6 +49,21 @@ func WithHeaders(keys ...string) Option {
 	}
 }
 
+// withTimestamp adds the time to the section headers.
+func withTimestamp() Option {
+	return func(d *DumpTransport) {
+		d.timestamp = true
+	}
+}
+
+func withWriter(w io.Writer) Option {
+	return func(d *DumpTransport) {
+		if w != nil {
+			d.writer = w
+		}
+	}
+}
+
 type DumpTransport struct {
 	rt http.RoundTripper
 
@@ -54,6 +73,8 @@ type DumpTransport struct {
 	regexps []*regexp.Regexp
 
 	writer io.Writer
+
+	timestamp bool
 }
 
 func NewDumpTransport(rt http.RoundTripper, opts ...Option) *DumpTransport {
@@ -87,22 +108,33 @@ func NewDumpTransport(rt http.RoundTripper, opts ...Option) *DumpTransport {
 func (d *DumpTransport) RoundTrip(h *http.Request) (*http.Response, error) {
 	data, _ := httputil.DumpRequestOut(h, true)
 
-	_, _ = fmt.Fprintln(d.writer, "[HTTP Request]")
-	_, _ = fmt.Fprintln(d.writer, d.redact(data))
+	d.write("[HTTP Request]", d.redact(data))
 
 	resp, err := d.rt.RoundTrip(h)
 	if err != nil {
+		if d.timestamp {
+			d.write("[HTTP Error]", err.Error())
+		}
+
 		return nil, err
 	}
 
 	data, _ = httputil.DumpResponse(resp, true)
 
-	_, _ = fmt.Fprintln(d.writer, "[HTTP Response]")
-	_, _ = fmt.Fprintln(d.writer, d.redact(data))
+	d.write("[HTTP Response]", d.redact(data))
 
 	return resp, err
 }
 
+// write writes a section with a single call, to limit the interleaving of concurrent requests.
+func (d *DumpTransport) write(header, content string) {
+	if d.timestamp {
+		header = time.Now().UTC().Format(time.RFC3339Nano) + " " + header
+	}
+
+	_, _ = fmt.Fprintln(d.writer, header+"\n"+content)
+}
+
 func (d *DumpTransport) redact(content []byte) string {
 	data := string(content)
 
@@ -118,7 +150,18 @@ func (d *DumpTransport) redact(content []byte) string {
 }
 
 // Wrap wraps an HTTP client Transport with the [DumpTransport].
+// When a metrics sink is set, the Transport is also wrapped with the [metrics.Transport].
+// When the capture file of the DNS API traffic is defined (LEGO_DEBUG_DNS_API_HTTP_CLIENT_FILE),
+// the Transport is also wrapped with a [DumpTransport] writing to this file.
 func Wrap(client *http.Client, opts ...Option) *http.Client {
+	if client != nil && metrics.Enabled() {
+		client.Transport = metrics.NewTransport(client.Transport)
+	}
+
+	if w := debugcapture.Writer(debugcapture.DNSAPI); client != nil && w != nil {
+		client.Transport = NewDumpTransport(client.Transport, append(slices.Clone(opts), withTimestamp(), withWriter(w))...)
+	}
+
 	val, found := os.LookupEnv("LEGO_DEBUG_DNS_API_HTTP_CLIENT")
 	if !found {
 		return client
@@ -128,14 +128,6 @@ func fakeResponse() http.HandlerFunc {
 	}
 }
 
-func withWriter(w io.Writer) Option {
-	return func(d *DumpTransport) {
-		if w != nil {
-			d.writer = w
-		}
-	}
-}
-
 func setupTest(t *testing.T, buf io.Writer, opts ...Option) (*httptest.Server, *http.Client, *http.Request) {
 	t.Helper()
 
@@ -131,3 +131,18 @@ func (u UnexpectedStatusCodeError) Error() string {
 
 	return msg + fmt.Sprintf(" [status code: %d] body: %s", u.StatusCode, string(u.Body))
 }
+
+// HTTPStatusCode returns the status code of the response (ex: to detect the transient server errors).
+func (r ReadResponseError) HTTPStatusCode() int {
+	return r.StatusCode
+}
+
+// HTTPStatusCode returns the status code of the response (ex: to detect the transient server errors).
+func (u UnmarshalError) HTTPStatusCode() int {
+	return u.StatusCode
+}
+
+// HTTPStatusCode returns the status code of the response (ex: to detect the transient server errors).
+func (u UnexpectedStatusCodeError) HTTPStatusCode() int {
+	return u.StatusCode
+}
@@ -77,6 +77,26 @@ func (c *Client) ReplaceRecords(ctx context.Context, zoneID string, records []Re
 	return nil
 }
 
+// CreateRecords creates records in a zone.
+// All the records are created in a single request.
+func (c *Client) CreateRecords(ctx context.Context, zoneID string, records []Record) ([]Record, error) {
+	endpoint := c.BaseURL.JoinPath("v1", "zones", zoneID, "records")
+
+	req, err := makeJSONRequest(ctx, http.MethodPost, endpoint, records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var created []Record
+
+	err = c.do(req, &created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call API: %w", err)
+	}
+
+	return created, nil
+}
+
 // GetRecords gets the records of a zones.
 func (c *Client) GetRecords(ctx context.Context, zoneID string, filter *RecordsFilter) ([]Record, error) {
 	endpoint := c.BaseURL.JoinPath("v1", "zones", zoneID)
@@ -132,7 +152,7 @@ func (c *Client) do(req *http.Request, result any) error {
 
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return parseError(req, resp)
 	}
 
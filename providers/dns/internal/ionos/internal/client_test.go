@@ -160,3 +160,56 @@ func TestClient_ReplaceRecords_error(t *testing.T) {
 	assert.ErrorAs(t, err, &cErr)
 	assert.Equal(t, http.StatusBadRequest, cErr.StatusCode)
 }
+
+func TestClient_CreateRecords(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /v1/zones/azone01/records",
+			servermock.ResponseFromFixture("create_records.json").
+				WithStatusCode(http.StatusCreated),
+			servermock.CheckRequestJSONBody(`[{"name":"_acme-challenge.example.com","content":"w6uP8Tcg6K2QR905Rms8iXTlksL6OD1KOWBxTK7wxPI","ttl":300,"type":"TXT"}]`)).
+		Build(t)
+
+	records := []Record{{
+		Name:    "_acme-challenge.example.com",
+		Content: "w6uP8Tcg6K2QR905Rms8iXTlksL6OD1KOWBxTK7wxPI",
+		TTL:     300,
+		Type:    "TXT",
+	}}
+
+	created, err := client.CreateRecords(t.Context(), "azone01", records)
+	require.NoError(t, err)
+
+	expected := []Record{{
+		ID:      "22af3414-abbe-9e11-5df5-66fbe8e334b4",
+		Name:    "_acme-challenge.example.com",
+		Content: `"w6uP8Tcg6K2QR905Rms8iXTlksL6OD1KOWBxTK7wxPI"`,
+		TTL:     300,
+		Type:    "TXT",
+	}}
+
+	assert.Equal(t, expected, created)
+}
+
+func TestClient_CreateRecords_error(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /v1/zones/azone01/records",
+			servermock.ResponseFromFixture("create_records_error.json").
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	records := []Record{{
+		Name:    "_acme-challenge.example.com",
+		Content: "w6uP8Tcg6K2QR905Rms8iXTlksL6OD1KOWBxTK7wxPI",
+		TTL:     300,
+		Type:    "TXT",
+	}}
+
+	created, err := client.CreateRecords(t.Context(), "azone01", records)
+	require.Error(t, err)
+
+	assert.Nil(t, created)
+
+	var cErr *ClientError
+	assert.ErrorAs(t, err, &cErr)
+	assert.Equal(t, http.StatusBadRequest, cErr.StatusCode)
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -18,7 +19,10 @@ import (
 
 const MinTTL = 300
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderBatch   = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -29,10 +33,21 @@ type Config struct {
 	HTTPClient         *http.Client
 }
 
+type recordRef struct {
+	zoneID   string
+	recordID string
+}
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *ionos.Client
+
+	zones   []ionos.Zone
+	zonesMu sync.Mutex
+
+	recordIDs   map[string]recordRef
+	recordIDsMu sync.Mutex
 }
 
 // NewDNSProviderConfig return a DNSProvider instance configured for Ionos.
@@ -64,7 +79,11 @@ func NewDNSProviderConfig(config *Config, baseURL string) (*DNSProvider, error)
 
 	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
 
-	return &DNSProvider{config: config, client: client}, nil
+	return &DNSProvider{
+		config:    config,
+		client:    client,
+		recordIDs: make(map[string]recordRef),
+	}, nil
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -74,64 +93,122 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 }
 
 // Present creates a TXT record using the specified parameters.
-func (d *DNSProvider) Present(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentAll([]challenge.ChallengeInfo{{Domain: domain, Token: token, KeyAuth: keyAuth}})
+}
 
+// PresentAll creates the TXT records of several challenges,
+// the records of each zone are created with a single request.
+func (d *DNSProvider) PresentAll(challenges []challenge.ChallengeInfo) error {
 	ctx := context.Background()
 
-	zones, err := d.client.ListZones(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get zones: %w", err)
-	}
+	var zones []string
 
-	name := dns01.UnFqdn(info.EffectiveFQDN)
+	records := make(map[string][]ionos.Record)
+	tokens := make(map[string][]string)
 
-	zone := findZone(zones, name)
-	if zone == nil {
-		return errors.New("no matching zone found for domain")
-	}
+	for _, chlg := range challenges {
+		info := dns01.GetChallengeInfo(chlg.Domain, chlg.KeyAuth)
 
-	filter := &ionos.RecordsFilter{
-		Suffix:     name,
-		RecordType: "TXT",
-	}
+		name := dns01.UnFqdn(info.EffectiveFQDN)
 
-	records, err := d.client.GetRecords(ctx, zone.ID, filter)
-	if err != nil {
-		return fmt.Errorf("failed to get records (zone=%s): %w", zone.ID, err)
+		zone, err := d.getZone(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := records[zone.ID]; !ok {
+			zones = append(zones, zone.ID)
+		}
+
+		records[zone.ID] = append(records[zone.ID], ionos.Record{
+			Name:    name,
+			Content: info.Value,
+			TTL:     d.config.TTL,
+			Type:    "TXT",
+		})
+
+		tokens[zone.ID] = append(tokens[zone.ID], chlg.Token)
 	}
 
-	records = append(records, ionos.Record{
-		Name:    name,
-		Content: info.Value,
-		TTL:     d.config.TTL,
-		Type:    "TXT",
-	})
+	for _, zoneID := range zones {
+		created, err := d.client.CreateRecords(ctx, zoneID, records[zoneID])
+		if err != nil {
+			return fmt.Errorf("failed to create records (zone=%s): %w", zoneID, err)
+		}
 
-	err = d.client.ReplaceRecords(ctx, zone.ID, records)
-	if err != nil {
-		return fmt.Errorf("failed to create/update records (zone=%s): %w", zone.ID, err)
+		d.storeRecordIDs(zoneID, records[zoneID], tokens[zoneID], created)
 	}
 
 	return nil
 }
 
+// storeRecordIDs keeps the IDs of the created records by token.
+func (d *DNSProvider) storeRecordIDs(zoneID string, records []ionos.Record, tokens []string, created []ionos.Record) {
+	d.recordIDsMu.Lock()
+	defer d.recordIDsMu.Unlock()
+
+	for i, record := range records {
+		for _, c := range created {
+			if c.Name == record.Name && c.Content == strconv.Quote(record.Content) {
+				d.recordIDs[tokens[i]] = recordRef{zoneID: zoneID, recordID: c.ID}
+
+				break
+			}
+		}
+	}
+}
+
 // CleanUp removes the TXT record matching the specified parameters.
-func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpAll([]challenge.ChallengeInfo{{Domain: domain, Token: token, KeyAuth: keyAuth}})
+}
 
+// CleanUpAll removes the TXT records of several challenges.
+// The API doesn't provide a bulk deletion: the records are removed one by one,
+// and a failed removal doesn't prevent the removal of the other records.
+func (d *DNSProvider) CleanUpAll(challenges []challenge.ChallengeInfo) error {
 	ctx := context.Background()
 
-	zones, err := d.client.ListZones(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get zones: %w", err)
+	var errs []error
+
+	for _, chlg := range challenges {
+		d.recordIDsMu.Lock()
+		ref, ok := d.recordIDs[chlg.Token]
+		d.recordIDsMu.Unlock()
+
+		if !ok {
+			var err error
+
+			ref, err = d.findRecord(ctx, dns01.GetChallengeInfo(chlg.Domain, chlg.KeyAuth))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		err := d.client.RemoveRecord(ctx, ref.zoneID, ref.recordID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove record (zone=%s, record=%s): %w", ref.zoneID, ref.recordID, err))
+			continue
+		}
+
+		d.recordIDsMu.Lock()
+		delete(d.recordIDs, chlg.Token)
+		d.recordIDsMu.Unlock()
 	}
 
+	return errors.Join(errs...)
+}
+
+// findRecord looks up the record through the API,
+// it is used when the record has not been created by this provider instance.
+func (d *DNSProvider) findRecord(ctx context.Context, info dns01.ChallengeInfo) (recordRef, error) {
 	name := dns01.UnFqdn(info.EffectiveFQDN)
 
-	zone := findZone(zones, name)
-	if zone == nil {
-		return errors.New("no matching zone found for domain")
+	zone, err := d.getZone(ctx, name)
+	if err != nil {
+		return recordRef{}, err
 	}
 
 	filter := &ionos.RecordsFilter{
@@ -141,21 +218,45 @@ func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 
 	records, err := d.client.GetRecords(ctx, zone.ID, filter)
 	if err != nil {
-		return fmt.Errorf("failed to get records (zone=%s): %w", zone.ID, err)
+		return recordRef{}, fmt.Errorf("failed to get records (zone=%s): %w", zone.ID, err)
 	}
 
 	for _, record := range records {
 		if record.Name == name && record.Content == strconv.Quote(info.Value) {
-			err = d.client.RemoveRecord(ctx, zone.ID, record.ID)
-			if err != nil {
-				return fmt.Errorf("failed to remove record (zone=%s, record=%s): %w", zone.ID, record.ID, err)
-			}
+			return recordRef{zoneID: zone.ID, recordID: record.ID}, nil
+		}
+	}
+
+	return recordRef{}, fmt.Errorf("failed to remove record, record not found (zone=%s, fqdn=%s, value=%s)", zone.ID, info.EffectiveFQDN, info.Value)
+}
 
-			return nil
+// getZone returns the zone matching the domain.
+// The list of zones is fetched once and cached for the lifetime of the provider,
+// the cache is refreshed only when no zone matches the domain.
+func (d *DNSProvider) getZone(ctx context.Context, domain string) (*ionos.Zone, error) {
+	d.zonesMu.Lock()
+	defer d.zonesMu.Unlock()
+
+	if d.zones != nil {
+		zone := findZone(d.zones, domain)
+		if zone != nil {
+			return zone, nil
 		}
 	}
 
-	return fmt.Errorf("failed to remove record, record not found (zone=%s, domain=%s, fqdn=%s, value=%s)", zone.ID, domain, info.EffectiveFQDN, info.Value)
+	zones, err := d.client.ListZones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zones: %w", err)
+	}
+
+	d.zones = zones
+
+	zone := findZone(zones, domain)
+	if zone == nil {
+		return nil, errors.New("no matching zone found for domain")
+	}
+
+	return zone, nil
 }
 
 func findZone(zones []ionos.Zone, domain string) *ionos.Zone {
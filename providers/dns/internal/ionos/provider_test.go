@@ -1,8 +1,15 @@
 package ionos
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	ionos "github.com/digicert/lego/v4/providers/dns/internal/ionos/internal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,3 +57,120 @@ func TestNewDNSProviderConfig(t *testing.T) {
 		})
 	}
 }
+
+func mockBuilder() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			config := &Config{
+				APIKey:     "secret",
+				TTL:        MinTTL,
+				HTTPClient: server.Client(),
+			}
+
+			return NewDNSProviderConfig(config, server.URL)
+		},
+		servermock.CheckHeader().
+			WithJSONHeaders().
+			With(ionos.APIKeyHeader, "secret"),
+	)
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	var zoneCalls atomic.Int32
+
+	provider := mockBuilder().
+		Route("GET /v1/zones", countCalls(&zoneCalls,
+			servermock.ResponseFromInternal("list_zones.json"))).
+		Route("POST /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records",
+			servermock.ResponseFromInternal("create_records_example.json").
+				WithStatusCode(http.StatusCreated),
+			servermock.CheckRequestJSONBody(`[{"name":"_acme-challenge.test.com","content":"123d==","ttl":300,"type":"TXT"}]`)).
+		Build(t)
+
+	err := provider.Present("test.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	err = provider.Present("test.com", "def", "123d==")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, zoneCalls.Load())
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/zones",
+			servermock.ResponseFromInternal("list_zones.json")).
+		Route("POST /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records",
+			servermock.ResponseFromInternal("create_records_example.json").
+				WithStatusCode(http.StatusCreated)).
+		Route("DELETE /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records/22af3414-abbe-9e11-5df5-66fbe8e334b4",
+			servermock.Noop()).
+		Build(t)
+
+	err := provider.Present("test.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	err = provider.CleanUp("test.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUp_unknownRecord(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /v1/zones",
+			servermock.ResponseFromInternal("list_zones.json")).
+		Route("GET /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4",
+			servermock.ResponseFromInternal("get_records_example.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("suffix", "_acme-challenge.test.com").
+				With("recordType", "TXT")).
+		Route("DELETE /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records/22af3414-abbe-9e11-5df5-66fbe8e334b4",
+			servermock.Noop()).
+		Build(t)
+
+	err := provider.CleanUp("test.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_PresentAll(t *testing.T) {
+	var createCalls atomic.Int32
+
+	provider := mockBuilder().
+		Route("GET /v1/zones",
+			servermock.ResponseFromInternal("list_zones.json")).
+		Route("POST /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records", countCalls(&createCalls,
+			servermock.ResponseFromInternal("create_records_batch.json").
+				WithStatusCode(http.StatusCreated)),
+			servermock.CheckRequestJSONBody(`[{"name":"_acme-challenge.test.com","content":"123d==","ttl":300,"type":"TXT"},{"name":"_acme-challenge.test.com","content":"456e==","ttl":300,"type":"TXT"}]`)).
+		Route("DELETE /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records/22af3414-abbe-9e11-5df5-66fbe8e334b4",
+			servermock.Noop()).
+		Route("DELETE /v1/zones/11af3414-ebba-11e9-8df5-66fbe8a334b4/records/33af3414-abbe-9e11-5df5-66fbe8e334b4",
+			servermock.Noop()).
+		Build(t)
+
+	challenges := []challenge.ChallengeInfo{
+		{Domain: "test.com", Token: "abc", KeyAuth: "123d=="},
+		{Domain: "test.com", Token: "def", KeyAuth: "456e=="},
+	}
+
+	err := provider.PresentAll(challenges)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, createCalls.Load())
+
+	assert.Equal(t, map[string]recordRef{
+		"abc": {zoneID: "11af3414-ebba-11e9-8df5-66fbe8a334b4", recordID: "22af3414-abbe-9e11-5df5-66fbe8e334b4"},
+		"def": {zoneID: "11af3414-ebba-11e9-8df5-66fbe8a334b4", recordID: "33af3414-abbe-9e11-5df5-66fbe8e334b4"},
+	}, provider.recordIDs)
+
+	err = provider.CleanUpAll(challenges)
+	require.NoError(t, err)
+
+	assert.Empty(t, provider.recordIDs)
+}
+
+func countCalls(counter *atomic.Int32, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		counter.Add(1)
+		next.ServeHTTP(rw, req)
+	})
+}
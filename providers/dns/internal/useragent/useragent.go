@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+
+	"github.com/digicert/lego/v4/internal/useragent"
 )
 
 const (
@@ -19,8 +21,9 @@ const (
 )
 
 // Get builds and returns the User-Agent string.
+// The application identifier (see lego.Config) is appended.
 func Get() string {
-	return fmt.Sprintf("%s (%s; %s; %s)", ourUserAgent, ourUserAgentComment, runtime.GOOS, runtime.GOARCH)
+	return useragent.Append(fmt.Sprintf("%s (%s; %s; %s)", ourUserAgent, ourUserAgentComment, runtime.GOOS, runtime.GOARCH))
 }
 
 // SetHeader sets the User-Agent header.
@@ -0,0 +1,209 @@
+// Package zonecache caches the zone lookups of the DNS providers (ex: zone name to zone ID).
+//
+// The cache is opt-in (LEGO_ZONE_CACHE_TTL).
+// The entries are kept in memory, shared by all the providers of the process using the same credentials,
+// and optionally persisted on disk (LEGO_ZONE_CACHE_DIR) to be shared across invocations.
+package zonecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/platform/config/env"
+)
+
+// Environment variables names.
+const (
+	// EnvTTL the lifetime of the cached entries. The cache is disabled when it is not defined.
+	EnvTTL = "LEGO_ZONE_CACHE_TTL"
+
+	// EnvDir the directory where the cached entries are persisted. Without it, the entries are only kept in memory.
+	EnvDir = "LEGO_ZONE_CACHE_DIR"
+)
+
+var (
+	cachesMu sync.Mutex
+	caches   = map[string]*Cache{}
+)
+
+type entry struct {
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// Cache is the cache of the zone lookups of a provider, for a set of credentials.
+// A nil Cache is valid: the lookups are not cached.
+type Cache struct {
+	ttl  time.Duration
+	path string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// Get returns the cache of a provider for a set of credentials, or nil if the cache is disabled.
+// The credentials are only used to compute the key of the cache: the caches of different accounts are isolated.
+func Get(provider string, credentials ...string) *Cache {
+	ttl := env.GetOrDefaultDuration(EnvTTL, 0)
+	if ttl <= 0 {
+		return nil
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(provider))
+
+	for _, credential := range credentials {
+		hash.Write([]byte{0})
+		hash.Write([]byte(credential))
+	}
+
+	key := provider + "-" + hex.EncodeToString(hash.Sum(nil))[:16]
+
+	dir := os.Getenv(EnvDir)
+	id := filepath.Join(dir, key)
+
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
+
+	if c, ok := caches[id]; ok {
+		c.ttl = ttl
+
+		return c
+	}
+
+	c := &Cache{ttl: ttl, entries: map[string]entry{}}
+
+	if dir != "" {
+		c.path = filepath.Join(dir, key+".json")
+		c.load()
+	}
+
+	caches[id] = c
+
+	return c
+}
+
+// Lookup returns the cached value of the key, or calls the fetch function and caches its result.
+// The errors are not cached.
+func (c *Cache) Lookup(ctx context.Context, key string, fetch func(ctx context.Context) (string, error)) (string, error) {
+	if c == nil {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(e.Expires) {
+		return e.Value, nil
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry{Value: value, Expires: time.Now().Add(c.ttl)}
+
+	c.save()
+
+	return value, nil
+}
+
+// Invalidate removes the cached value of the key (ex: the zone has been deleted).
+func (c *Cache) Invalidate(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		return
+	}
+
+	delete(c.entries, key)
+
+	c.save()
+}
+
+// load reads the persisted entries. The expired entries are ignored.
+func (c *Cache) load() {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("zone cache: could not read the cache file.", "path", c.path, "error", err)
+		}
+
+		return
+	}
+
+	var entries map[string]entry
+
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		log.Warn("zone cache: invalid cache file.", "path", c.path, "error", err)
+		return
+	}
+
+	now := time.Now()
+
+	for key, e := range entries {
+		if now.Before(e.Expires) {
+			c.entries[key] = e
+		}
+	}
+}
+
+// save persists the entries, if the cache has a file.
+// The file is replaced atomically, so a concurrent invocation never reads a partial file.
+// The caller must hold the lock.
+func (c *Cache) save() {
+	if c.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		log.Warn("zone cache: could not encode the entries.", "error", err)
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0o700)
+	if err != nil {
+		log.Warn("zone cache: could not create the cache directory.", "path", c.path, "error", err)
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		log.Warn("zone cache: could not write the cache file.", "path", c.path, "error", err)
+		return
+	}
+
+	_, err = tmp.Write(data)
+
+	err = errors.Join(err, tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		log.Warn("zone cache: could not write the cache file.", "path", c.path, "error", err)
+	}
+}
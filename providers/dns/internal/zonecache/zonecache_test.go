@@ -0,0 +1,154 @@
+package zonecache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetCaches(t *testing.T) {
+	t.Helper()
+
+	cachesMu.Lock()
+	caches = map[string]*Cache{}
+	cachesMu.Unlock()
+}
+
+type fetcher struct {
+	calls int
+	value string
+	err   error
+}
+
+func (f *fetcher) fetch(_ context.Context) (string, error) {
+	f.calls++
+
+	return f.value, f.err
+}
+
+func TestGet_disabled(t *testing.T) {
+	resetCaches(t)
+
+	t.Setenv(EnvTTL, "")
+
+	c := Get("example", "secret")
+	assert.Nil(t, c)
+
+	f := &fetcher{value: "zone-id"}
+
+	for range 2 {
+		value, err := c.Lookup(t.Context(), "example.com.", f.fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, "zone-id", value)
+	}
+
+	assert.Equal(t, 2, f.calls)
+
+	c.Invalidate("example.com.")
+}
+
+func TestCache_Lookup(t *testing.T) {
+	resetCaches(t)
+
+	t.Setenv(EnvTTL, "1h")
+
+	f := &fetcher{value: "zone-id"}
+
+	for range 2 {
+		// The providers with the same credentials share the cache.
+		value, err := Get("example", "secret").Lookup(t.Context(), "example.com.", f.fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, "zone-id", value)
+	}
+
+	assert.Equal(t, 1, f.calls)
+
+	// Other credentials: other cache.
+	_, err := Get("example", "other").Lookup(t.Context(), "example.com.", f.fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.calls)
+
+	Get("example", "secret").Invalidate("example.com.")
+
+	_, err = Get("example", "secret").Lookup(t.Context(), "example.com.", f.fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, f.calls)
+}
+
+func TestCache_Lookup_error(t *testing.T) {
+	resetCaches(t)
+
+	t.Setenv(EnvTTL, "1h")
+
+	c := Get("example", "secret")
+
+	f := &fetcher{err: errors.New("boom")}
+
+	_, err := c.Lookup(t.Context(), "example.com.", f.fetch)
+	require.EqualError(t, err, "boom")
+
+	// The errors are not cached.
+	f.value, f.err = "zone-id", nil
+
+	value, err := c.Lookup(t.Context(), "example.com.", f.fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, "zone-id", value)
+	assert.Equal(t, 2, f.calls)
+}
+
+func TestCache_Lookup_expired(t *testing.T) {
+	resetCaches(t)
+
+	t.Setenv(EnvTTL, "1h")
+
+	c := Get("example", "secret")
+	c.ttl = time.Nanosecond
+
+	f := &fetcher{value: "zone-id"}
+
+	for range 2 {
+		_, err := c.Lookup(t.Context(), "example.com.", f.fetch)
+		require.NoError(t, err)
+
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, 2, f.calls)
+}
+
+func TestCache_disk(t *testing.T) {
+	resetCaches(t)
+
+	dir := t.TempDir()
+
+	t.Setenv(EnvTTL, "1h")
+	t.Setenv(EnvDir, dir)
+
+	f := &fetcher{value: "zone-id"}
+
+	_, err := Get("example", "secret").Lookup(t.Context(), "example.com.", f.fetch)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "example-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// Another invocation reads the persisted entries.
+	resetCaches(t)
+
+	value, err := Get("example", "secret").Lookup(t.Context(), "example.com.", f.fetch)
+	require.NoError(t, err)
+
+	assert.Equal(t, "zone-id", value)
+	assert.Equal(t, 1, f.calls)
+}
@@ -4,6 +4,8 @@ package inwx
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -28,7 +30,13 @@ const (
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+// errCodeAuthentication the error code returned by INWX when the TAN is invalid or has already been used.
+const errCodeAuthentication = 2200
+
+var (
+	_ challenge.ProviderTimeout    = (*DNSProvider)(nil)
+	_ challenge.ProviderConcurrent = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -39,6 +47,9 @@ type Config struct {
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -46,17 +57,24 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		TTL: env.GetOrDefaultInt(EnvTTL, 300),
 		// INWX has rather unstable propagation delays, thus using a larger default value
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 6*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 6*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
 		Sandbox:            env.GetOrDefaultBool(EnvSandbox, false),
 	}
 }
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
-	config         *Config
-	client         *goinwx.Client
+	config *Config
+	client *goinwx.Client
+
+	// the API session is held by the client,
+	// so the calls are serialized from the login to the logout (including the TOTP unlock).
+	sessionMu      sync.Mutex
 	previousUnlock time.Time
+
+	recordIDs   map[string]string
+	recordIDsMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Dyn DNS.
@@ -87,12 +105,16 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.Sandbox {
-		log.Infof("inwx: sandbox mode is enabled")
+		log.OrDefault(config.Logger).Info("inwx: sandbox mode is enabled.")
 	}
 
 	client := goinwx.NewClient(config.Username, config.Password, &goinwx.ClientOptions{Sandbox: config.Sandbox})
 
-	return &DNSProvider{config: config, client: client}, nil
+	return &DNSProvider{
+		config:    config,
+		client:    client,
+		recordIDs: make(map[string]string),
+	}, nil
 }
 
 // Present creates a TXT record using the specified parameters.
@@ -104,6 +126,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("inwx: could not find zone for domain %q (%s): %w", domain, info.EffectiveFQDN, err)
 	}
 
+	d.sessionMu.Lock()
+	defer d.sessionMu.Unlock()
+
 	login, err := d.client.Account.Login()
 	if err != nil {
 		return fmt.Errorf("inwx: %w", err)
@@ -112,7 +137,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	defer func() {
 		errL := d.client.Account.Logout()
 		if errL != nil {
-			log.Infof("inwx: failed to log out: %v", errL)
+			log.OrDefault(d.config.Logger).Warn("inwx: failed to log out.", "error", errL)
 		}
 	}()
 
@@ -129,7 +154,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		TTL:     d.config.TTL,
 	}
 
-	_, err = d.client.Nameservers.CreateRecord(request)
+	recordID, err := d.client.Nameservers.CreateRecord(request)
 	if err != nil {
 		var er *goinwx.ErrorResponse
 		if errors.As(err, &er) && er.Message == "Object exists" {
@@ -139,6 +164,10 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("inwx: %w", err)
 	}
 
+	d.recordIDsMu.Lock()
+	d.recordIDs[token] = recordID
+	d.recordIDsMu.Unlock()
+
 	return nil
 }
 
@@ -151,6 +180,9 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("inwx: could not find zone for domain %q (%s): %w", domain, info.EffectiveFQDN, err)
 	}
 
+	d.sessionMu.Lock()
+	defer d.sessionMu.Unlock()
+
 	login, err := d.client.Account.Login()
 	if err != nil {
 		return fmt.Errorf("inwx: %w", err)
@@ -159,7 +191,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	defer func() {
 		errL := d.client.Account.Logout()
 		if errL != nil {
-			log.Infof("inwx: failed to log out: %v", errL)
+			log.OrDefault(d.config.Logger).Warn("inwx: failed to log out.", "error", errL)
 		}
 	}()
 
@@ -168,29 +200,17 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("inwx: %w", err)
 	}
 
-	response, err := d.client.Nameservers.Info(&goinwx.NameserverInfoRequest{
-		Domain: dns01.UnFqdn(authZone),
-		Name:   dns01.UnFqdn(info.EffectiveFQDN),
-		Type:   "TXT",
-	})
-	if err != nil {
-		return fmt.Errorf("inwx: %w", err)
-	}
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	d.recordIDsMu.Unlock()
 
-	var recordID string
-
-	for _, record := range response.Records {
-		if record.Content != info.Value {
-			continue
+	if !ok {
+		// The record has not been created by this provider instance (ex: "Object exists"),
+		// only a record with the exact challenge value can be removed.
+		recordID, err = d.findRecordID(dns01.UnFqdn(authZone), info)
+		if err != nil {
+			return fmt.Errorf("inwx: %w", err)
 		}
-
-		recordID = record.ID
-
-		break
-	}
-
-	if recordID == "" {
-		return errors.New("inwx: TXT record not found")
 	}
 
 	err = d.client.Nameservers.DeleteRecord(recordID)
@@ -198,6 +218,10 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("inwx: %w", err)
 	}
 
+	d.recordIDsMu.Lock()
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
 	return nil
 }
 
@@ -207,6 +231,30 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// Concurrent reports that the provider is safe for concurrent use (challenge.ProviderConcurrent).
+func (d *DNSProvider) Concurrent() bool {
+	return true
+}
+
+func (d *DNSProvider) findRecordID(zone string, info dns01.ChallengeInfo) (string, error) {
+	response, err := d.client.Nameservers.Info(&goinwx.NameserverInfoRequest{
+		Domain: zone,
+		Name:   dns01.UnFqdn(info.EffectiveFQDN),
+		Type:   "TXT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, record := range response.Records {
+		if record.Type == "TXT" && record.Content == info.Value {
+			return record.ID, nil
+		}
+	}
+
+	return "", errors.New("TXT record not found")
+}
+
 func (d *DNSProvider) twoFactorAuth(info *goinwx.LoginResponse) error {
 	if info.TFA != "GOOGLE-AUTH" {
 		return nil
@@ -220,12 +268,25 @@ func (d *DNSProvider) twoFactorAuth(info *goinwx.LoginResponse) error {
 	// To avoid using the same TAN twice, we wait until the next TOTP period.
 	sleep := d.computeSleep(time.Now())
 	if sleep != 0 {
-		log.Infof("inwx: waiting %s for next TOTP token", sleep)
+		log.OrDefault(d.config.Logger).Info("inwx: waiting for next TOTP token.", "delay", sleep)
 		time.Sleep(sleep)
 	}
 
-	now := time.Now()
+	err := d.unlock(time.Now())
+	if err == nil || !isTANAlreadyUsed(err) {
+		return err
+	}
+
+	// The TAN of the current period has already been used (ex: by another client),
+	// so the next TAN is used.
+	sleep = d.computeSleep(time.Now())
+	log.OrDefault(d.config.Logger).Info("inwx: TOTP token already used, waiting for next TOTP token.", "delay", sleep)
+	time.Sleep(sleep)
 
+	return d.unlock(time.Now())
+}
+
+func (d *DNSProvider) unlock(now time.Time) error {
 	tan, err := totp.GenerateCode(d.config.SharedSecret, now)
 	if err != nil {
 		return err
@@ -236,6 +297,12 @@ func (d *DNSProvider) twoFactorAuth(info *goinwx.LoginResponse) error {
 	return d.client.Account.Unlock(tan)
 }
 
+func isTANAlreadyUsed(err error) bool {
+	var er *goinwx.ErrorResponse
+
+	return errors.As(err, &er) && er.Code == errCodeAuthentication
+}
+
 func (d *DNSProvider) computeSleep(now time.Time) time.Duration {
 	if d.previousUnlock.IsZero() {
 		return 0
@@ -1,10 +1,13 @@
 package inwx
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/nrdcg/goinwx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -186,3 +189,35 @@ func Test_computeSleep(t *testing.T) {
 		})
 	}
 }
+
+func Test_isTANAlreadyUsed(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		err    error
+		assert assert.BoolAssertionFunc
+	}{
+		{
+			desc:   "authentication error",
+			err:    fmt.Errorf("unlock: %w", &goinwx.ErrorResponse{Code: errCodeAuthentication, Message: "Authentication error"}),
+			assert: assert.True,
+		},
+		{
+			desc:   "other API error",
+			err:    &goinwx.ErrorResponse{Code: 2303, Message: "Object does not exist"},
+			assert: assert.False,
+		},
+		{
+			desc:   "other error",
+			err:    errors.New("oops"),
+			assert: assert.False,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.assert(t, isTANAlreadyUsed(test.err))
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
-	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/config/env"
 	"github.com/digicert/lego/v4/providers/dns/internal/ionos"
 )
@@ -27,7 +26,10 @@ const (
 
 const minTTL = 300
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderBatch   = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config = ionos.Config
@@ -36,17 +38,17 @@ type Config = ionos.Config
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, ionos.MinTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 15*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 15*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
 
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
-	prv challenge.ProviderTimeout
+	prv *ionos.DNSProvider
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Ionos.
@@ -102,3 +104,23 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+// PresentAll creates the TXT records of several challenges, the records of each zone are created with a single request.
+func (d *DNSProvider) PresentAll(challenges []challenge.ChallengeInfo) error {
+	err := d.prv.PresentAll(challenges)
+	if err != nil {
+		return fmt.Errorf("ionos: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUpAll removes the TXT records of several challenges.
+func (d *DNSProvider) CleanUpAll(challenges []challenge.ChallengeInfo) error {
+	err := d.prv.CleanUpAll(challenges)
+	if err != nil {
+		return fmt.Errorf("ionos: %w", err)
+	}
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
 	"github.com/digicert/lego/v4/providers/dns/internal/useragent"
+	querystring "github.com/google/go-querystring/query"
 )
 
 const defaultBaseURL = "https://dns.de-fra.ionos.com"
@@ -88,6 +89,34 @@ func (c *Client) CreateRecord(ctx context.Context, zoneID string, record RecordP
 	return result, nil
 }
 
+// RetrieveRecords returns a page of the records of the DNS zone.
+// https://api.ionos.com/docs/dns/v1/#tag/Records/operation/zonesRecordsGet
+func (c *Client) RetrieveRecords(ctx context.Context, zoneID string, filter *RecordsFilter) (*RecordsResponse, error) {
+	endpoint := c.BaseURL.JoinPath("zones", zoneID, "records")
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		v, errQ := querystring.Values(filter)
+		if errQ != nil {
+			return nil, errQ
+		}
+
+		req.URL.RawQuery = v.Encode()
+	}
+
+	result := &RecordsResponse{}
+
+	if err := c.do(req, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // DeleteRecord deletes a specified record from the DNS zone.
 // https://api.ionos.com/docs/dns/v1/#tag/Records/operation/zonesRecordsDelete
 func (c *Client) DeleteRecord(ctx context.Context, zoneID, recordID string) error {
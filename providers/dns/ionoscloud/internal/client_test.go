@@ -122,6 +122,29 @@ func TestClient_CreateRecord(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestClient_RetrieveRecords(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /zones/abc/records",
+			servermock.ResponseFromFixture("records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("filter.name", "_acme-challenge").
+				With("filter.recordType", "TXT").
+				With("limit", "1000")).
+		Build(t)
+
+	result, err := client.RetrieveRecords(t.Context(), "abc", &RecordsFilter{Name: "_acme-challenge", RecordType: "TXT", Limit: 1000})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1000, result.Limit)
+	require.Len(t, result.Items, 1)
+
+	record := result.Items[0]
+	assert.Equal(t, "90d81ac0-3a30-44d4-95a5-12959effa6ee", record.ID)
+	assert.Equal(t, time.Date(2022, time.August, 21, 15, 52, 53, 0, time.UTC), record.Metadata.CreatedDate)
+	assert.Equal(t, "_acme-challenge.example.com", record.Metadata.Fqdn)
+	assert.Equal(t, "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY", record.Properties.Content)
+}
+
 func TestClient_DeleteRecord(t *testing.T) {
 	client := mockBuilder().
 		Route("DELETE /zones/abc/records/def",
@@ -67,6 +67,21 @@ type ZoneProperties struct {
 	Enabled     bool   `json:"enabled"`
 }
 
+type RecordsFilter struct {
+	Name       string `url:"filter.name,omitempty"`
+	RecordType string `url:"filter.recordType,omitempty"`
+	Offset     int    `url:"offset,omitempty"`
+	Limit      int    `url:"limit,omitempty"`
+}
+
+type RecordsResponse struct {
+	ID     string           `json:"id"`
+	Type   string           `json:"type"`
+	Offset int              `json:"offset"`
+	Limit  int              `json:"limit"`
+	Items  []RecordResponse `json:"items"`
+}
+
 type RecordResponse struct {
 	ID         string           `json:"id"`
 	Type       string           `json:"type"`
@@ -27,26 +27,31 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+// recordsPageSize the maximum number of records returned by a call to the API.
+const recordsPageSize = 1000
+
+var _ dns01.RecordLister = (*DNSProvider)(nil)
+
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIToken string
+	APIToken string `env:"API_TOKEN,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
-	HTTPClient         *http.Client
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"120s"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		TTL:             dns01.DefaultTTL,
+		PollingInterval: dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -61,14 +66,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for Ionos Cloud.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIToken)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("ionoscloud: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIToken = values[EnvAPIToken]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -108,13 +112,9 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("ionoscloud: could not find zone for domain %q: %w", domain, err)
 	}
 
-	zones, err := d.client.RetrieveZones(ctx, dns01.UnFqdn(authZone))
+	zoneID, err := d.findZoneID(ctx, authZone)
 	if err != nil {
-		return fmt.Errorf("ionoscloud: retrieve zones: %w", err)
-	}
-
-	if len(zones) != 1 {
-		return fmt.Errorf("ionoscloud: zone ID not found for domain %q", domain)
+		return fmt.Errorf("ionoscloud: domain %q: %w", domain, err)
 	}
 
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
@@ -122,8 +122,6 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("ionoscloud: %w", err)
 	}
 
-	zoneID := zones[0].ID
-
 	request := internal.RecordProperties{
 		Name:    subDomain,
 		Type:    "TXT",
@@ -182,3 +180,69 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
+
+// ListChallengeRecords returns the challenge TXT records of the zone.
+func (d *DNSProvider) ListChallengeRecords(ctx context.Context, zone string) ([]dns01.ChallengeRecord, error) {
+	zoneID, err := d.findZoneID(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("ionoscloud: zone %s: %w", zone, err)
+	}
+
+	filter := &internal.RecordsFilter{
+		Name:       "_acme-challenge",
+		RecordType: "TXT",
+		Limit:      recordsPageSize,
+	}
+
+	var results []dns01.ChallengeRecord
+
+	for {
+		page, err := d.client.RetrieveRecords(ctx, zoneID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("ionoscloud: retrieve records: %w", err)
+		}
+
+		for _, record := range page.Items {
+			results = append(results, dns01.ChallengeRecord{
+				ID:        record.ID,
+				FQDN:      dns01.ToFqdn(record.Metadata.Fqdn),
+				Value:     record.Properties.Content,
+				CreatedAt: record.Metadata.CreatedDate,
+			})
+		}
+
+		if len(page.Items) < recordsPageSize {
+			return results, nil
+		}
+
+		filter.Offset += len(page.Items)
+	}
+}
+
+// DeleteChallengeRecord removes a challenge TXT record of the zone.
+func (d *DNSProvider) DeleteChallengeRecord(ctx context.Context, zone string, record dns01.ChallengeRecord) error {
+	zoneID, err := d.findZoneID(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("ionoscloud: zone %s: %w", zone, err)
+	}
+
+	err = d.client.DeleteRecord(ctx, zoneID, record.ID)
+	if err != nil {
+		return fmt.Errorf("ionoscloud: delete record: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) findZoneID(ctx context.Context, zone string) (string, error) {
+	zones, err := d.client.RetrieveZones(ctx, dns01.UnFqdn(zone))
+	if err != nil {
+		return "", fmt.Errorf("retrieve zones: %w", err)
+	}
+
+	if len(zones) != 1 {
+		return "", errors.New("zone ID not found")
+	}
+
+	return zones[0].ID, nil
+}
@@ -5,9 +5,12 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -171,3 +174,45 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 	err := provider.CleanUp("example.com", token, "123d==")
 	require.NoError(t, err)
 }
+
+func TestDNSProvider_ListChallengeRecords(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("filter.zoneName", "example.com")).
+		Route("GET /zones/e74d0d15-f567-4b7b-9069-26ee1f93bae3/records",
+			servermock.ResponseFromInternal("records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("filter.name", "_acme-challenge").
+				With("filter.recordType", "TXT").
+				With("limit", "1000")).
+		Build(t)
+
+	records, err := provider.ListChallengeRecords(t.Context(), "example.com.")
+	require.NoError(t, err)
+
+	expected := []dns01.ChallengeRecord{{
+		ID:        "90d81ac0-3a30-44d4-95a5-12959effa6ee",
+		FQDN:      "_acme-challenge.example.com.",
+		Value:     "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+		CreatedAt: time.Date(2022, time.August, 21, 15, 52, 53, 0, time.UTC),
+	}}
+
+	assert.Equal(t, expected, records)
+}
+
+func TestDNSProvider_DeleteChallengeRecord(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("filter.zoneName", "example.com")).
+		Route("DELETE /zones/e74d0d15-f567-4b7b-9069-26ee1f93bae3/records/90d81ac0-3a30-44d4-95a5-12959effa6ee",
+			servermock.Noop().
+				WithStatusCode(http.StatusAccepted)).
+		Build(t)
+
+	err := provider.DeleteChallengeRecord(t.Context(), "example.com.", dns01.ChallengeRecord{ID: "90d81ac0-3a30-44d4-95a5-12959effa6ee"})
+	require.NoError(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -48,6 +49,9 @@ type Client struct {
 	Debug      bool
 	BaseURL    string
 	HTTPClient *http.Client
+
+	// Logger is used to log the messages of the client (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewClient creates a new DMAPI Client.
@@ -91,7 +95,7 @@ func (c *Client) postRequest(ctx context.Context, cmd string, data url.Values) (
 	}
 
 	if c.Debug {
-		log.Infof("postRequest:\n\tURL: %q\n\tData: %v", endpoint, data)
+		log.OrDefault(c.Logger).Debug("joker: postRequest.", "url", endpoint, "data", data)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
@@ -12,6 +12,9 @@ type token string
 
 const sessionIDKey token = "session-id"
 
+// ErrInvalidCredentials is returned when the login is rejected by the DMAPI.
+var ErrInvalidCredentials = errors.New("login did not return valid Auth-Sid")
+
 // Token session ID.
 // > Every request (except "login") requires the presence of the Auth-Sid variable ("Session ID"),
 // > which is returned by the "login" request (login). An active session will expire after some inactivity period (default: 1 hour).
@@ -47,7 +50,7 @@ func (c *Client) login(ctx context.Context) (*Response, error) {
 	}
 
 	if response.AuthSid == "" {
-		return response, errors.New("login did not return valid Auth-Sid")
+		return response, ErrInvalidCredentials
 	}
 
 	return response, nil
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -257,3 +258,13 @@ func TestClient_CreateAuthenticatedContext(t *testing.T) {
 
 	assert.Equal(t, "200", getSessionID(ctx))
 }
+
+func TestClient_CreateAuthenticatedContext_invalidCredentials(t *testing.T) {
+	client := mockBuilder(AuthInfo{Username: "invalid", Password: "secret"}).
+		Route("POST /login",
+			servermock.RawStringResponse("Status-Code: 2200\nStatus-Text: Authentication error")).
+		Build(t)
+
+	_, err := client.CreateAuthenticatedContext(t.Context())
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
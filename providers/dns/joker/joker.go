@@ -2,8 +2,9 @@
 package joker
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -29,6 +30,7 @@ const (
 )
 
 const (
+	modeAuto  = "AUTO"
 	modeDMAPI = "DMAPI"
 	modeSVC   = "SVC"
 )
@@ -45,6 +47,9 @@ type Config struct {
 	SequenceInterval   time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -53,30 +58,41 @@ func NewDefaultConfig() *Config {
 		APIMode:            env.GetOrDefaultString(EnvMode, modeDMAPI),
 		Debug:              env.GetOrDefaultBool(EnvDebug, false),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+		SequenceInterval:   env.GetOrDefaultDuration(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 60*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 60*time.Second),
 		},
 	}
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Joker.
-// Credentials must be passed in the environment variable JOKER_API_KEY.
+// Credentials must be passed in the environment variables: JOKER_USERNAME, JOKER_PASSWORD or JOKER_API_KEY.
+// With JOKER_API_MODE=AUTO, the API (DMAPI or SVC) is detected from the credentials.
 func NewDNSProvider() (challenge.ProviderTimeout, error) {
-	if os.Getenv(EnvMode) == modeSVC {
+	switch env.GetOrFile(EnvMode) {
+	case modeSVC:
 		return newSvcProvider()
+	case modeAuto:
+		return newAutoProvider()
+	default:
+		return newDmapiProvider()
 	}
-
-	return newDmapiProvider()
 }
 
 // NewDNSProviderConfig return a DNSProvider instance configured for Joker.
 func NewDNSProviderConfig(config *Config) (challenge.ProviderTimeout, error) {
-	if config.APIMode == modeSVC {
-		return newSvcProviderConfig(config)
+	if config == nil {
+		return nil, errors.New("joker: the configuration of the DNS provider is nil")
 	}
 
-	return newDmapiProviderConfig(config)
+	switch config.APIMode {
+	case modeSVC:
+		return newSvcProviderConfig(config)
+	case modeAuto:
+		return newAutoProviderConfig(config)
+	default:
+		return newDmapiProviderConfig(config)
+	}
 }
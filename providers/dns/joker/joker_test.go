@@ -30,6 +30,14 @@ func TestNewDNSProvider(t *testing.T) {
 			},
 			expected: &dmapiProvider{},
 		},
+		{
+			desc: "mode automatic with API key",
+			envVars: map[string]string{
+				EnvMode:   modeAuto,
+				EnvAPIKey: "123",
+			},
+			expected: &dmapiProvider{},
+		},
 		{
 			desc: "mode DMAPI",
 			envVars: map[string]string{
@@ -0,0 +1,100 @@
+package joker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/log"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/providers/dns/joker/internal/dmapi"
+)
+
+// newAutoProvider returns a DNSProvider instance configured for Joker.
+// Credentials must be passed in the environment variable: JOKER_USERNAME, JOKER_PASSWORD or JOKER_API_KEY.
+func newAutoProvider() (challenge.ProviderTimeout, error) {
+	values, err := env.Get(EnvUsername, EnvPassword)
+	if err != nil || env.GetOrFile(EnvAPIKey) != "" {
+		// The API key is only supported by the DMAPI.
+		return newDmapiProvider()
+	}
+
+	config := NewDefaultConfig()
+	config.Username = values[EnvUsername]
+	config.Password = values[EnvPassword]
+
+	return newAutoProviderConfig(config)
+}
+
+// newAutoProviderConfig return a DNSProvider instance configured for Joker.
+// The API (DMAPI or SVC) is selected with a DMAPI login:
+// the DMAPI rejects the credentials of the SVC API (Dynamic DNS credentials).
+// The selected provider is returned, so only the SVC API is solved sequentially.
+func newAutoProviderConfig(config *Config) (challenge.ProviderTimeout, error) {
+	if config == nil {
+		return nil, errors.New("joker: the configuration of the DNS provider is nil")
+	}
+
+	dmapiProvider, err := newDmapiProviderConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.APIKey != "" {
+		// The API key is only supported by the DMAPI.
+		return dmapiProvider, nil
+	}
+
+	return selectAPI(config, dmapiProvider)
+}
+
+func selectAPI(config *Config, dmapiProvider *dmapiProvider) (challenge.ProviderTimeout, error) {
+	// The session is kept by the DMAPI client, so the login is not lost when DMAPI is selected.
+	_, err := dmapiProvider.client.CreateAuthenticatedContext(context.Background())
+	if err == nil {
+		return dmapiProvider, nil
+	}
+
+	if !errors.Is(err, dmapi.ErrInvalidCredentials) {
+		return nil, fmt.Errorf("joker: DMAPI login: %w", err)
+	}
+
+	svcProvider, errSvc := newSvcProviderConfig(config)
+	if errSvc != nil {
+		return nil, errors.Join(fmt.Errorf("joker: DMAPI login: %w", err), errSvc)
+	}
+
+	log.OrDefault(config.Logger).Warn("joker: the credentials are rejected by the DMAPI, using the SVC API.", "error", err)
+
+	return &svcFallbackProvider{svcProvider: svcProvider, dmapiErr: err}, nil
+}
+
+// svcFallbackProvider is the SVC provider selected after the rejection of the credentials by the DMAPI.
+// The DMAPI error is reported with the errors of the SVC API:
+// the credentials can be invalid DMAPI credentials instead of SVC credentials.
+type svcFallbackProvider struct {
+	*svcProvider
+
+	dmapiErr error
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *svcFallbackProvider) Present(domain, token, keyAuth string) error {
+	err := d.svcProvider.Present(domain, token, keyAuth)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("joker: DMAPI login: %w", d.dmapiErr))
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *svcFallbackProvider) CleanUp(domain, token, keyAuth string) error {
+	err := d.svcProvider.CleanUp(domain, token, keyAuth)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("joker: DMAPI login: %w", d.dmapiErr))
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package joker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/digicert/lego/v4/providers/dns/joker/internal/dmapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockAutoBuilder() *servermock.Builder[*dmapiProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*dmapiProvider, error) {
+			config := NewDefaultConfig()
+			config.Username = "user"
+			config.Password = "secret"
+			config.HTTPClient = server.Client()
+
+			provider, err := newDmapiProviderConfig(config)
+			if err != nil {
+				return nil, err
+			}
+
+			provider.client.BaseURL = server.URL
+
+			return provider, nil
+		},
+	)
+}
+
+func Test_selectAPI_dmapi(t *testing.T) {
+	provider := mockAutoBuilder().
+		Route("POST /login",
+			servermock.RawStringResponse("Status-Code: 0\nStatus-Text: OK\nAuth-Sid: 123\n\ncom\nnet")).
+		Build(t)
+
+	p, err := selectAPI(provider.config, provider)
+	require.NoError(t, err)
+
+	assert.IsType(t, &dmapiProvider{}, p)
+
+	_, ok := p.(interface{ Sequential() time.Duration })
+	assert.False(t, ok)
+}
+
+func Test_selectAPI_svc(t *testing.T) {
+	provider := mockAutoBuilder().
+		Route("POST /login",
+			servermock.RawStringResponse("Status-Code: 2200\nStatus-Text: Authentication error")).
+		Build(t)
+
+	p, err := selectAPI(provider.config, provider)
+	require.NoError(t, err)
+
+	require.IsType(t, &svcFallbackProvider{}, p)
+
+	svc := p.(*svcFallbackProvider)
+	assert.Equal(t, provider.config.SequenceInterval, svc.Sequential())
+	assert.ErrorIs(t, svc.dmapiErr, dmapi.ErrInvalidCredentials)
+}
+
+func Test_selectAPI_error(t *testing.T) {
+	provider := mockAutoBuilder().
+		Route("POST /login",
+			servermock.Noop().WithStatusCode(http.StatusInternalServerError)).
+		Build(t)
+
+	_, err := selectAPI(provider.config, provider)
+	require.Error(t, err)
+}
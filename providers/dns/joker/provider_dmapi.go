@@ -63,6 +63,7 @@ func newDmapiProviderConfig(config *Config) (*dmapiProvider, error) {
 	})
 
 	client.Debug = config.Debug
+	client.Logger = config.Logger
 
 	if config.HTTPClient != nil {
 		client.HTTPClient = config.HTTPClient
@@ -94,7 +95,7 @@ func (d *dmapiProvider) Present(domain, token, keyAuth string) error {
 	}
 
 	if d.config.Debug {
-		log.Infof("[%s] joker: adding TXT record %q to zone %q with value %q", domain, subDomain, zone, info.Value)
+		log.OrDefault(d.config.Logger).Info("joker: adding TXT record.", "domain", domain, "subdomain", subDomain, "zone", zone, "value", info.Value)
 	}
 
 	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
@@ -132,7 +133,7 @@ func (d *dmapiProvider) CleanUp(domain, token, keyAuth string) error {
 	}
 
 	if d.config.Debug {
-		log.Infof("[%s] joker: removing entry %q from zone %q", domain, subDomain, zone)
+		log.OrDefault(d.config.Logger).Info("joker: removing entry.", "domain", domain, "subdomain", subDomain, "zone", zone)
 	}
 
 	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
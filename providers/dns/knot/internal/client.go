@@ -0,0 +1,289 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Client a client for the Knot DNS control interface (the protocol used by knotc).
+// https://www.knot-dns.cz/docs/latest/html/reference.html#control-section
+type Client struct {
+	socket string
+
+	Timeout time.Duration
+}
+
+// NewClient creates a new Client.
+func NewClient(socket string) (*Client, error) {
+	if socket == "" {
+		return nil, errors.New("socket missing")
+	}
+
+	return &Client{socket: socket, Timeout: 30 * time.Second}, nil
+}
+
+// ListZones lists the zones of the server.
+func (c *Client) ListZones(ctx context.Context) ([]string, error) {
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	messages, err := conn.Do(Message{Cmd: "zone-status"})
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []string
+
+	for _, msg := range messages {
+		if msg.Zone != "" && (len(zones) == 0 || zones[len(zones)-1] != msg.Zone) {
+			zones = append(zones, msg.Zone)
+		}
+	}
+
+	return zones, nil
+}
+
+// AddRecord adds a record inside a zone transaction.
+func (c *Client) AddRecord(ctx context.Context, zone, owner string, ttl int, rrType, data string) error {
+	return c.transaction(ctx, zone, Message{
+		Cmd:   "zone-set",
+		Zone:  zone,
+		Owner: owner,
+		TTL:   strconv.Itoa(ttl),
+		Type:  rrType,
+		Data:  data,
+	})
+}
+
+// DeleteRecord removes a record inside a zone transaction.
+func (c *Client) DeleteRecord(ctx context.Context, zone, owner, rrType, data string) error {
+	return c.transaction(ctx, zone, Message{
+		Cmd:   "zone-unset",
+		Zone:  zone,
+		Owner: owner,
+		Type:  rrType,
+		Data:  data,
+	})
+}
+
+// transaction runs a command between a zone-begin and a zone-commit.
+// The transaction is aborted if the command fails.
+func (c *Client) transaction(ctx context.Context, zone string, msg Message) error {
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.Do(Message{Cmd: "zone-begin", Zone: zone})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Do(msg)
+	if err != nil {
+		_, _ = conn.Do(Message{Cmd: "zone-abort", Zone: zone})
+
+		return err
+	}
+
+	_, err = conn.Do(Message{Cmd: "zone-commit", Zone: zone})
+	if err != nil {
+		_, _ = conn.Do(Message{Cmd: "zone-abort", Zone: zone})
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) connect(ctx context.Context) (*Conn, error) {
+	dialer := &net.Dialer{Timeout: c.Timeout}
+
+	conn, err := dialer.DialContext(ctx, "unix", c.socket)
+	if err != nil {
+		return nil, fmt.Errorf("connect to the control socket: %w", err)
+	}
+
+	if c.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(c.Timeout))
+	}
+
+	return &Conn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}, nil
+}
+
+// Conn a connection to the control socket.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// Do sends a command and reads the response messages.
+func (c *Conn) Do(msg Message) ([]Message, error) {
+	err := writeMessage(c.writer, typeData, &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writer.WriteByte(typeBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writer.Flush()
+	if err != nil {
+		return nil, fmt.Errorf("%s: send: %w", msg.Cmd, err)
+	}
+
+	var messages []Message
+
+	var errCmd error
+
+	// The response is read until the end of the block, even after an error,
+	// to keep the connection usable for the next command.
+	for {
+		msgType, resp, err := readMessage(c.reader)
+		if err != nil {
+			return nil, fmt.Errorf("%s: read response: %w", msg.Cmd, err)
+		}
+
+		switch msgType {
+		case typeBlock:
+			if errCmd != nil {
+				return nil, errCmd
+			}
+
+			return messages, nil
+
+		case typeEnd:
+			return nil, fmt.Errorf("%s: connection closed by the server", msg.Cmd)
+
+		default:
+			if resp.Error != "" && errCmd == nil {
+				errCmd = &CommandError{Cmd: msg.Cmd, Zone: msg.Zone, Message: resp.Error}
+			}
+
+			messages = append(messages, *resp)
+		}
+	}
+}
+
+// Close ends the session and closes the connection.
+func (c *Conn) Close() error {
+	_ = c.writer.WriteByte(typeEnd)
+	_ = c.writer.Flush()
+
+	return c.conn.Close()
+}
+
+func writeMessage(w *bufio.Writer, msgType byte, msg *Message) error {
+	err := w.WriteByte(msgType)
+	if err != nil {
+		return err
+	}
+
+	for i, value := range msg.items() {
+		if *value == "" {
+			continue
+		}
+
+		if len(*value) > math.MaxUint16 {
+			return fmt.Errorf("%s: value too long", msg.Cmd)
+		}
+
+		err = w.WriteByte(dataCodeOffset + byte(i))
+		if err != nil {
+			return err
+		}
+
+		err = binary.Write(w, binary.BigEndian, uint16(len(*value)))
+		if err != nil {
+			return err
+		}
+
+		_, err = w.WriteString(*value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readMessage(r *bufio.Reader) (byte, *Message, error) {
+	msgType, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch msgType {
+	case typeEnd, typeBlock:
+		return msgType, nil, nil
+
+	case typeData, typeExtra:
+		// the data items follow.
+
+	default:
+		return 0, nil, fmt.Errorf("unknown message type: %d", msgType)
+	}
+
+	msg := &Message{}
+	items := msg.items()
+
+	for {
+		peek, err := r.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return msgType, msg, nil
+		}
+
+		if err != nil {
+			return 0, nil, err
+		}
+
+		code := peek[0]
+
+		// The codes lower than the offset are message types: the message is complete.
+		if code < dataCodeOffset {
+			return msgType, msg, nil
+		}
+
+		_, _ = r.ReadByte()
+
+		var length uint16
+
+		err = binary.Read(r, binary.BigEndian, &length)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		value := make([]byte, length)
+
+		_, err = io.ReadFull(r, value)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		// The unknown items (from newer versions of the protocol) are ignored.
+		if idx := int(code - dataCodeOffset); idx < len(items) {
+			*items[idx] = string(value)
+		}
+	}
+}
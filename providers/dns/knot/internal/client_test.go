@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListZones(t *testing.T) {
+	server := newFakeServer(t, func(msg Message) []Message {
+		return []Message{
+			{Zone: "example.com.", Type: "serial", Data: "2024010101"},
+			{Zone: "example.com.", Type: "role", Data: "master"},
+			{Zone: "example.org.", Type: "serial", Data: "2024010102"},
+		}
+	})
+
+	client := setupClient(t, server)
+
+	zones, err := client.ListZones(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com.", "example.org."}, zones)
+
+	assert.Equal(t, []Message{{Cmd: "zone-status"}}, server.Requests())
+}
+
+func TestClient_AddRecord(t *testing.T) {
+	server := newFakeServer(t, func(msg Message) []Message {
+		return nil
+	})
+
+	client := setupClient(t, server)
+
+	err := client.AddRecord(t.Context(), "example.com.", "_acme-challenge", 120, "TXT", `"123d=="`)
+	require.NoError(t, err)
+
+	expected := []Message{
+		{Cmd: "zone-begin", Zone: "example.com."},
+		{Cmd: "zone-set", Zone: "example.com.", Owner: "_acme-challenge", TTL: "120", Type: "TXT", Data: `"123d=="`},
+		{Cmd: "zone-commit", Zone: "example.com."},
+	}
+
+	assert.Equal(t, expected, server.Requests())
+}
+
+func TestClient_AddRecord_error(t *testing.T) {
+	server := newFakeServer(t, func(msg Message) []Message {
+		if msg.Cmd == "zone-set" {
+			return []Message{{Zone: msg.Zone, Error: "invalid TTL"}}
+		}
+
+		return nil
+	})
+
+	client := setupClient(t, server)
+
+	err := client.AddRecord(t.Context(), "example.com.", "_acme-challenge", 120, "TXT", `"123d=="`)
+	require.EqualError(t, err, "zone-set (example.com.): invalid TTL")
+
+	expected := []Message{
+		{Cmd: "zone-begin", Zone: "example.com."},
+		{Cmd: "zone-set", Zone: "example.com.", Owner: "_acme-challenge", TTL: "120", Type: "TXT", Data: `"123d=="`},
+		{Cmd: "zone-abort", Zone: "example.com."},
+	}
+
+	assert.Equal(t, expected, server.Requests())
+}
+
+func TestClient_DeleteRecord(t *testing.T) {
+	server := newFakeServer(t, func(msg Message) []Message {
+		return nil
+	})
+
+	client := setupClient(t, server)
+
+	err := client.DeleteRecord(t.Context(), "example.com.", "_acme-challenge", "TXT", `"123d=="`)
+	require.NoError(t, err)
+
+	expected := []Message{
+		{Cmd: "zone-begin", Zone: "example.com."},
+		{Cmd: "zone-unset", Zone: "example.com.", Owner: "_acme-challenge", Type: "TXT", Data: `"123d=="`},
+		{Cmd: "zone-commit", Zone: "example.com."},
+	}
+
+	assert.Equal(t, expected, server.Requests())
+}
+
+func Test_readMessage_unknownItem(t *testing.T) {
+	// DATA, ZONE "a.", unknown item 30 "x", BLOCK
+	raw := []byte{typeData, 16 + 6, 0, 2, 'a', '.', 30, 0, 1, 'x', typeBlock}
+
+	r := bufio.NewReader(bytes.NewReader(raw))
+
+	msgType, msg, err := readMessage(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, typeData, msgType)
+	assert.Equal(t, &Message{Zone: "a."}, msg)
+
+	msgType, msg, err = readMessage(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, typeBlock, msgType)
+	assert.Nil(t, msg)
+}
+
+func setupClient(t *testing.T, server *fakeServer) *Client {
+	t.Helper()
+
+	client, err := NewClient(server.socket)
+	require.NoError(t, err)
+
+	return client
+}
+
+// fakeServer a fake Knot DNS control socket.
+type fakeServer struct {
+	socket  string
+	handler func(msg Message) []Message
+
+	mu       sync.Mutex
+	requests []Message
+}
+
+func newFakeServer(t *testing.T, handler func(msg Message) []Message) *fakeServer {
+	t.Helper()
+
+	server := &fakeServer{
+		socket:  filepath.Join(t.TempDir(), "knot.sock"),
+		handler: handler,
+	}
+
+	listener, err := net.Listen("unix", server.socket)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, errA := listener.Accept()
+			if errA != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	for {
+		msgType, msg, err := readMessage(reader)
+		if err != nil || msgType == typeEnd {
+			return
+		}
+
+		if msgType == typeBlock {
+			continue
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, *msg)
+		s.mu.Unlock()
+
+		// Reads the end of the request.
+		msgType, _, err = readMessage(reader)
+		if err != nil || msgType != typeBlock {
+			return
+		}
+
+		for _, resp := range s.handler(*msg) {
+			if writeMessage(writer, typeData, &resp) != nil {
+				return
+			}
+		}
+
+		if writer.WriteByte(typeBlock) != nil || writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) Requests() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
@@ -0,0 +1,54 @@
+package internal
+
+import "fmt"
+
+// Control block types.
+// https://gitlab.nic.cz/knot/knot-dns/-/blob/master/src/libknot/control/control.h
+const (
+	typeEnd   byte = 0
+	typeData  byte = 1
+	typeExtra byte = 2
+	typeBlock byte = 3
+)
+
+// dataCodeOffset is the offset of the data item codes.
+const dataCodeOffset byte = 16
+
+// Message is a control message.
+// The order of the fields is the order of the data item indexes.
+type Message struct {
+	Cmd     string
+	Flags   string
+	Error   string
+	Section string
+	Item    string
+	ID      string
+	Zone    string
+	Owner   string
+	TTL     string
+	Type    string
+	Data    string
+	Filter  string
+}
+
+func (m *Message) items() []*string {
+	return []*string{
+		&m.Cmd, &m.Flags, &m.Error, &m.Section, &m.Item, &m.ID,
+		&m.Zone, &m.Owner, &m.TTL, &m.Type, &m.Data, &m.Filter,
+	}
+}
+
+// CommandError is an error returned by the server.
+type CommandError struct {
+	Cmd     string
+	Zone    string
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	if e.Zone == "" {
+		return fmt.Sprintf("%s: %s", e.Cmd, e.Message)
+	}
+
+	return fmt.Sprintf("%s (%s): %s", e.Cmd, e.Zone, e.Message)
+}
@@ -0,0 +1,168 @@
+// Package knot implements a DNS provider for solving the DNS-01 challenge using Knot DNS.
+package knot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/providers/dns/knot/internal"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "KNOT_"
+
+	EnvSocket        = envNamespace + "SOCKET"
+	EnvSocketTimeout = envNamespace + "SOCKET_TIMEOUT"
+	EnvZone          = envNamespace + "ZONE"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+const defaultSocket = "/run/knot/knot.sock"
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Socket        string
+	SocketTimeout time.Duration
+	Zone          string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Socket:             env.GetOrDefaultString(EnvSocket, defaultSocket),
+		SocketTimeout:      env.GetOrDefaultDuration(EnvSocketTimeout, 30*time.Second),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+	}
+}
+
+type knotClient interface {
+	ListZones(ctx context.Context) ([]string, error)
+	AddRecord(ctx context.Context, zone, owner string, ttl int, rrType, data string) error
+	DeleteRecord(ctx context.Context, zone, owner, rrType, data string) error
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client knotClient
+
+	// Knot DNS allows only one transaction by zone.
+	txMu sync.Mutex
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Knot DNS.
+func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+	config.Zone = env.GetOrDefaultString(EnvZone, "")
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Knot DNS.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("knot: the configuration of the DNS provider is nil")
+	}
+
+	client, err := internal.NewClient(config.Socket)
+	if err != nil {
+		return nil, fmt.Errorf("knot: %w", err)
+	}
+
+	if config.SocketTimeout > 0 {
+		client.Timeout = config.SocketTimeout
+	}
+
+	return &DNSProvider{config: config, client: client}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.txMu.Lock()
+	defer d.txMu.Unlock()
+
+	zone, err := d.findZone(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("knot: %w", err)
+	}
+
+	err = d.client.AddRecord(ctx, zone, info.EffectiveFQDN, d.config.TTL, "TXT", strconv.Quote(info.Value))
+	if err != nil {
+		return fmt.Errorf("knot: add TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.txMu.Lock()
+	defer d.txMu.Unlock()
+
+	zone, err := d.findZone(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("knot: %w", err)
+	}
+
+	err = d.client.DeleteRecord(ctx, zone, info.EffectiveFQDN, "TXT", strconv.Quote(info.Value))
+	if err != nil {
+		return fmt.Errorf("knot: delete TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// findZone finds the most specific zone served by Knot DNS.
+func (d *DNSProvider) findZone(ctx context.Context, fqdn string) (string, error) {
+	if d.config.Zone != "" {
+		return dns01.ToFqdn(d.config.Zone), nil
+	}
+
+	zones, err := d.client.ListZones(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list zones: %w", err)
+	}
+
+	for name := range dns01.DomainsSeq(fqdn) {
+		for _, zone := range zones {
+			if dns01.ToFqdn(zone) == name {
+				return dns01.ToFqdn(zone), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("zone not found for %q", fqdn)
+}
@@ -0,0 +1,222 @@
+package knot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(
+	EnvSocket,
+	EnvSocketTimeout,
+	EnvZone).
+	WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc:    "success",
+			envVars: map[string]string{},
+		},
+		{
+			desc: "success with a socket",
+			envVars: map[string]string{
+				EnvSocket: "/var/run/knot/knot.sock",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		socket   string
+		expected string
+	}{
+		{
+			desc:   "success",
+			socket: "/run/knot/knot.sock",
+		},
+		{
+			desc:     "missing socket",
+			expected: "knot: socket missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Socket = test.socket
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	client := &fakeClient{zones: []string{"example.org.", "example.com.", "sub.example.com."}}
+
+	provider := setupProvider(t, client)
+
+	err := provider.Present("www.example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`add example.com. _acme-challenge.www.example.com. 120 TXT "123d=="`}, client.calls)
+}
+
+func TestDNSProvider_Present_subZone(t *testing.T) {
+	client := &fakeClient{zones: []string{"example.com.", "sub.example.com."}}
+
+	provider := setupProvider(t, client)
+
+	err := provider.Present("www.sub.example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`add sub.example.com. _acme-challenge.www.sub.example.com. 120 TXT "123d=="`}, client.calls)
+}
+
+func TestDNSProvider_Present_zone(t *testing.T) {
+	client := &fakeClient{}
+
+	provider := setupProvider(t, client)
+	provider.config.Zone = "example.com"
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`add example.com. _acme-challenge.example.com. 120 TXT "123d=="`}, client.calls)
+}
+
+func TestDNSProvider_Present_zoneNotFound(t *testing.T) {
+	client := &fakeClient{zones: []string{"example.org."}}
+
+	provider := setupProvider(t, client)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.EqualError(t, err, `knot: zone not found for "_acme-challenge.example.com."`)
+}
+
+func TestDNSProvider_Present_error(t *testing.T) {
+	client := &fakeClient{zones: []string{"example.com."}, err: errors.New("zone-set (example.com.): invalid TTL")}
+
+	provider := setupProvider(t, client)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.EqualError(t, err, "knot: add TXT record: zone-set (example.com.): invalid TTL")
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	client := &fakeClient{zones: []string{"example.com."}}
+
+	provider := setupProvider(t, client)
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`delete example.com. _acme-challenge.example.com. TXT "123d=="`}, client.calls)
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func setupProvider(t *testing.T, client knotClient) *DNSProvider {
+	t.Helper()
+
+	config := NewDefaultConfig()
+	config.TTL = 120
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	p.client = client
+
+	return p
+}
+
+type fakeClient struct {
+	zones []string
+	err   error
+	calls []string
+}
+
+func (f *fakeClient) ListZones(_ context.Context) ([]string, error) {
+	return f.zones, nil
+}
+
+func (f *fakeClient) AddRecord(_ context.Context, zone, owner string, ttl int, rrType, data string) error {
+	f.calls = append(f.calls, "add "+zone+" "+owner+" "+strconv.Itoa(ttl)+" "+rrType+" "+data)
+
+	return f.err
+}
+
+func (f *fakeClient) DeleteRecord(_ context.Context, zone, owner, rrType, data string) error {
+	f.calls = append(f.calls, "delete "+zone+" "+owner+" "+rrType+" "+data)
+
+	return f.err
+}
@@ -0,0 +1,181 @@
+// Package libdns implements a DNS provider for solving the DNS-01 challenge
+// with an implementation of the libdns interfaces (https://github.com/libdns/libdns), used by Caddy and certmagic.
+//
+// The provider is only available as a library: the libdns implementation is wrapped by a thin Adapter
+// converting the records between the types of this package and the types of libdns.
+//
+//	type adapter struct {
+//		provider *cloudflare.Provider // github.com/libdns/cloudflare
+//	}
+//
+//	func (a adapter) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+//		// convert the records, call a.provider.AppendRecords, and convert the result.
+//	}
+package libdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "LIBDNS_"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Record is a DNS record, with the fields of the libdns records.
+type Record struct {
+	// ID the provider-specific ID of the record, if the libdns implementation uses it.
+	ID string
+	// Type the type of the record (always TXT for the challenges).
+	Type string
+	// Name the name of the record, relative to the zone.
+	Name string
+	// Value the value of the record.
+	Value string
+	// TTL the TTL of the record.
+	TTL time.Duration
+}
+
+// Adapter is the thin adapter of a libdns implementation.
+// The methods have the semantics of libdns.RecordAppender and libdns.RecordDeleter:
+// the zone is a FQDN (with a trailing dot), and the names of the records are relative to the zone.
+type Adapter interface {
+	// AppendRecords creates the records, and returns the created records.
+	AppendRecords(ctx context.Context, zone string, records []Record) ([]Record, error)
+	// DeleteRecords deletes the records, and returns the deleted records.
+	DeleteRecords(ctx context.Context, zone string, records []Record) ([]Record, error)
+}
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Adapter Adapter
+
+	TTL                int
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	// findZoneByFqdn determines the DNS zone of a FQDN.
+	// It is overridden during tests.
+	findZoneByFqdn func(fqdn string) (string, error)
+
+	// the records created by the adapter, by challenge token.
+	recordsMu sync.Mutex
+	records   map[string][]Record
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for a libdns implementation.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("libdns: the configuration of the DNS provider is nil")
+	}
+
+	if config.Adapter == nil {
+		return nil, errors.New("libdns: the adapter is missing")
+	}
+
+	return &DNSProvider{
+		config:         config,
+		findZoneByFqdn: dns01.FindZoneByFqdn,
+		records:        make(map[string][]Record),
+	}, nil
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	zone, record, err := d.newRecord(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("libdns: %w", err)
+	}
+
+	created, err := d.config.Adapter.AppendRecords(context.Background(), zone, []Record{record})
+	if err != nil {
+		return fmt.Errorf("libdns: append records: %w", err)
+	}
+
+	d.recordsMu.Lock()
+	d.records[token] = created
+	d.recordsMu.Unlock()
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	zone, record, err := d.newRecord(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("libdns: %w", err)
+	}
+
+	d.recordsMu.Lock()
+	records, ok := d.records[token]
+	delete(d.records, token)
+	d.recordsMu.Unlock()
+
+	// The created records contain the provider-specific data (ex: the ID),
+	// but some implementations don't return them.
+	if !ok || len(records) == 0 {
+		records = []Record{record}
+	}
+
+	_, err = d.config.Adapter.DeleteRecords(context.Background(), zone, records)
+	if err != nil {
+		return fmt.Errorf("libdns: delete records: %w", err)
+	}
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+func (d *DNSProvider) newRecord(domain, keyAuth string) (string, Record, error) {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, err := d.findZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return "", Record{}, fmt.Errorf("could not find zone for domain %q: %w", domain, err)
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, zone)
+	if err != nil {
+		return "", Record{}, err
+	}
+
+	return dns01.ToFqdn(zone), Record{
+		Type:  "TXT",
+		Name:  subDomain,
+		Value: info.Value,
+		TTL:   time.Duration(d.config.TTL) * time.Second,
+	}, nil
+}
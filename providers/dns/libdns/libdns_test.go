@@ -0,0 +1,130 @@
+package libdns
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var challengeValue = dns01.GetChallengeInfo("sub.example.com", "keyAuth").Value
+
+type adapterMock struct {
+	appended []Record
+	deleted  []Record
+	zone     string
+
+	// withID returns the created records with an ID.
+	withID bool
+	err    error
+}
+
+func (a *adapterMock) AppendRecords(_ context.Context, zone string, records []Record) ([]Record, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	a.zone = zone
+	a.appended = append(a.appended, records...)
+
+	if !a.withID {
+		return nil, nil
+	}
+
+	var created []Record
+
+	for _, record := range records {
+		record.ID = "123"
+		created = append(created, record)
+	}
+
+	return created, nil
+}
+
+func (a *adapterMock) DeleteRecords(_ context.Context, zone string, records []Record) ([]Record, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	a.zone = zone
+	a.deleted = append(a.deleted, records...)
+
+	return records, nil
+}
+
+func setupProvider(t *testing.T, adapter Adapter) *DNSProvider {
+	t.Helper()
+
+	config := NewDefaultConfig()
+	config.Adapter = adapter
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	p.findZoneByFqdn = func(_ string) (string, error) {
+		return "example.com.", nil
+	}
+
+	return p
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	_, err := NewDNSProviderConfig(nil)
+	require.EqualError(t, err, "libdns: the configuration of the DNS provider is nil")
+
+	_, err = NewDNSProviderConfig(NewDefaultConfig())
+	require.EqualError(t, err, "libdns: the adapter is missing")
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	adapter := &adapterMock{}
+
+	p := setupProvider(t, adapter)
+
+	err := p.Present("sub.example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com.", adapter.zone)
+
+	expected := []Record{{Type: "TXT", Name: "_acme-challenge.sub", Value: challengeValue, TTL: 120 * time.Second}}
+	assert.Equal(t, expected, adapter.appended)
+}
+
+func TestDNSProvider_Present_error(t *testing.T) {
+	p := setupProvider(t, &adapterMock{err: errors.New("boom")})
+
+	err := p.Present("sub.example.com", "token", "keyAuth")
+	require.EqualError(t, err, "libdns: append records: boom")
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	adapter := &adapterMock{}
+
+	p := setupProvider(t, adapter)
+
+	err := p.CleanUp("sub.example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	expected := []Record{{Type: "TXT", Name: "_acme-challenge.sub", Value: challengeValue, TTL: 120 * time.Second}}
+	assert.Equal(t, expected, adapter.deleted)
+}
+
+func TestDNSProvider_CleanUp_createdRecords(t *testing.T) {
+	adapter := &adapterMock{withID: true}
+
+	p := setupProvider(t, adapter)
+
+	err := p.Present("sub.example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = p.CleanUp("sub.example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	// The records returned by the adapter are deleted.
+	expected := []Record{{ID: "123", Type: "TXT", Name: "_acme-challenge.sub", Value: challengeValue, TTL: 120 * time.Second}}
+	assert.Equal(t, expected, adapter.deleted)
+}
@@ -34,25 +34,21 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	APIKey             string
-	TTL                int
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	SequenceInterval   time.Duration
-	HTTPClient         *http.Client
+	APIKey             string        `env:"API_KEY,required"`
+	TTL                int           `env:"TTL" default:"60"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"8m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"80s"`
+	SequenceInterval   time.Duration `env:"SEQUENCE_INTERVAL" default:"90s"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 8*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 80*time.Second),
-		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, 90*time.Second),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -67,14 +63,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for Lima-City DNS.
 // LIMACITY_API_KEY must be passed in the environment variables.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAPIKey)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("limacity: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.APIKey = values[EnvAPIKey]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -32,6 +32,7 @@ const (
 
 const (
 	minTTL             = 300
+	maxTTL             = 2419200
 	dnsUpdateFreqMins  = 15
 	dnsUpdateFudgeSecs = 120
 )
@@ -50,10 +51,10 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 120*time.Second),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 15*time.Second),
-		HTTPTimeout:        env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		TTL:                env.ClampInt(EnvTTL, env.GetOrDefaultInt(EnvTTL, minTTL), minTTL, maxTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 120*time.Second),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 15*time.Second),
+		HTTPTimeout:        env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 	}
 }
 
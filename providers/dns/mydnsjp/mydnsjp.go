@@ -31,22 +31,22 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	MasterID           string
-	Password           string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	HTTPClient         *http.Client
+	MasterID           string        `env:"MASTER_ID,required"`
+	Password           string        `env:"PASSWORD,required"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"2m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	HTTPClient         *http.Client  `env:"HTTP_TIMEOUT" default:"30s"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
-		},
+	config := &Config{
+		PollingInterval: dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -58,15 +58,13 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for MyDNS.jp.
 // Credentials must be passed in the environment variables: MYDNSJP_MASTER_ID and MYDNSJP_PASSWORD.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvMasterID, EnvPassword)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("mydnsjp: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.MasterID = values[EnvMasterID]
-	config.Password = values[EnvPassword]
-
 	return NewDNSProviderConfig(config)
 }
 
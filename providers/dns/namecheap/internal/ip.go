@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -15,7 +16,8 @@ const getIPURL = "https://dynamicdns.park-your-domain.com/getip"
 
 // GetClientIP returns the client's public IP address.
 // It uses namecheap's IP discovery service to perform the lookup.
-func GetClientIP(ctx context.Context, client *http.Client, debug bool) (addr string, err error) {
+// The IP is logged with the logger (optional: the default logger is used if nil) in debug mode.
+func GetClientIP(ctx context.Context, client *http.Client, debug bool, logger *slog.Logger) (addr string, err error) {
 	if client == nil {
 		client = &http.Client{Timeout: 5 * time.Second}
 	}
@@ -38,7 +40,7 @@ func GetClientIP(ctx context.Context, client *http.Client, debug bool) (addr str
 	}
 
 	if debug {
-		log.Println("Client IP:", string(clientIP))
+		log.OrDefault(logger).Debug("namecheap: client IP.", "ip", string(clientIP))
 	}
 
 	return string(clientIP), nil
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -47,6 +48,11 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
+const (
+	minTTL = 60
+	maxTTL = 60000
+)
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
@@ -60,6 +66,9 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -72,11 +81,11 @@ func NewDefaultConfig() *Config {
 	return &Config{
 		BaseURL:            baseURL,
 		Debug:              env.GetOrDefaultBool(EnvDebug, false),
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, time.Hour),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 15*time.Second),
+		TTL:                env.ClampInt(EnvTTL, env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL), minTTL, maxTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, time.Hour),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 15*time.Second),
 		HTTPClient: &http.Client{
-			Timeout:   env.GetOrDefaultSecond(EnvHTTPTimeout, time.Minute),
+			Timeout:   env.GetOrDefaultDuration(EnvHTTPTimeout, time.Minute),
 			Transport: defaultTransport(envNamespace),
 		},
 	}
@@ -115,7 +124,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.ClientIP == "" {
-		clientIP, err := internal.GetClientIP(context.Background(), config.HTTPClient, config.Debug)
+		clientIP, err := internal.GetClientIP(context.Background(), config.HTTPClient, config.Debug, config.Logger)
 		if err != nil {
 			return nil, fmt.Errorf("namecheap: %w", err)
 		}
@@ -168,7 +177,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	if d.config.Debug {
 		for _, h := range records {
-			log.Printf("%-5.5s %-30.30s %-6s %-70.70s", h.Type, h.Name, h.TTL, h.Address)
+			log.OrDefault(d.config.Logger).Debug("namecheap: host record.", "type", h.Type, "name", h.Name, "ttl", h.TTL, "address", h.Address)
 		}
 	}
 
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
@@ -9,6 +10,9 @@ type sessionKey string
 
 const sessionIDKey sessionKey = "sessionID"
 
+// invalidSessionStatusCode the status code returned when the session is invalid or expired.
+const invalidSessionStatusCode = 4001
+
 // login performs the login as specified by the netcup WSDL
 // returns sessionID needed to perform remaining actions.
 // https://ccp.netcup.net/run/webservice/servers/endpoint.php
@@ -60,7 +64,24 @@ func (c *Client) CreateSessionContext(ctx context.Context) (context.Context, err
 		return nil, err
 	}
 
-	return context.WithValue(ctx, sessionIDKey, sessID), nil
+	return WithSessionID(ctx, sessID), nil
+}
+
+// WithSessionID returns a copy of the context which holds the session ID.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// SessionID returns the session ID stored in the context.
+func SessionID(ctx context.Context) string {
+	return getSessionID(ctx)
+}
+
+// IsSessionError reports whether the error is caused by an invalid or expired session.
+func IsSessionError(err error) bool {
+	var respMsg *ResponseMsg
+
+	return errors.As(err, &respMsg) && respMsg.StatusCode == invalidSessionStatusCode
 }
 
 func getSessionID(ctx context.Context) string {
@@ -105,3 +105,25 @@ func TestClient_Logout_errors(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSessionError(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /", servermock.ResponseFromFixture("get_dns_records_error_session.json")).
+		Build(t)
+
+	_, err := client.GetDNSRecords(mockContext(t), "example.com")
+	require.Error(t, err)
+
+	assert.True(t, IsSessionError(err))
+}
+
+func TestIsSessionError_otherError(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /", servermock.ResponseFromFixture("get_dns_records_error.json")).
+		Build(t)
+
+	_, err := client.GetDNSRecords(mockContext(t), "example.com")
+	require.Error(t, err)
+
+	assert.False(t, IsSessionError(err))
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -28,6 +30,8 @@ const (
 	// Deprecated: the TTL is not configurable on record.
 	EnvTTL = envNamespace + "TTL"
 
+	EnvAuthoritativeOnly = envNamespace + "AUTHORITATIVE_ONLY"
+
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
@@ -37,24 +41,41 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Key                string
-	Password           string
-	Customer           string
+	Key      string
+	Password string
+	Customer string
+
+	// AuthoritativeOnly uses a shorter polling interval:
+	// the propagation is checked on the authoritative name servers of the zone of the record
+	// (the zone of the effective FQDN, after the CNAMEs resolution).
+	AuthoritativeOnly bool
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	HTTPClient         *http.Client
 
 	// Deprecated: the TTL is not configurable on record.
 	TTL int
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
+	authoritativeOnly := env.GetOrDefaultBool(EnvAuthoritativeOnly, false)
+
+	pollingInterval := 30 * time.Second
+	if authoritativeOnly {
+		pollingInterval = 10 * time.Second
+	}
+
 	return &Config{
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 15*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 30*time.Second),
+		AuthoritativeOnly:  authoritativeOnly,
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 15*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, pollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 10*time.Second),
 		},
 	}
 }
@@ -63,6 +84,12 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	client *internal.Client
 	config *Config
+
+	// the API session is shared by all the calls,
+	// and closed when all the records have been cleaned.
+	sessionID string
+	pending   map[string]struct{} // the tokens of the records created and not cleaned yet.
+	sessionMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for netcup.
@@ -99,7 +126,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
 
-	return &DNSProvider{client: client, config: config}, nil
+	return &DNSProvider{client: client, config: config, pending: make(map[string]struct{})}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
@@ -111,18 +138,6 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("netcup: could not find zone for domain %q: %w", domain, err)
 	}
 
-	ctx, err := d.client.CreateSessionContext(context.Background())
-	if err != nil {
-		return fmt.Errorf("netcup: %w", err)
-	}
-
-	defer func() {
-		err = d.client.Logout(ctx)
-		if err != nil {
-			log.Printf("netcup: %v", err)
-		}
-	}()
-
 	hostname := strings.Replace(info.EffectiveFQDN, "."+zone, "", 1)
 	record := internal.DNSRecord{
 		Hostname:    hostname,
@@ -132,19 +147,34 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	zone = dns01.UnFqdn(zone)
 
-	records, err := d.client.GetDNSRecords(ctx, zone)
-	if err != nil {
-		// skip no existing records
-		log.Infof("no existing records, error ignored: %v", err)
-	}
+	err = d.withSession(context.Background(), func(ctx context.Context) error {
+		records, errR := d.client.GetDNSRecords(ctx, zone)
+		if errR != nil {
+			if internal.IsSessionError(errR) {
+				return errR
+			}
+
+			// skip no existing records
+			log.OrDefault(d.config.Logger).Info("netcup: no existing records, error ignored.", "error", errR)
+		}
 
-	records = append(records, record)
+		records = append(records, record)
 
-	err = d.client.UpdateDNSRecord(ctx, zone, records)
+		errU := d.client.UpdateDNSRecord(ctx, zone, records)
+		if errU != nil {
+			return fmt.Errorf("failed to add TXT-Record: %w", errU)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("netcup: failed to add TXT-Record: %w", err)
+		return fmt.Errorf("netcup: %w", err)
 	}
 
+	d.sessionMu.Lock()
+	d.pending[token] = struct{}{}
+	d.sessionMu.Unlock()
+
 	return nil
 }
 
@@ -157,41 +187,33 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("netcup: could not find zone for domain %q: %w", domain, err)
 	}
 
-	ctx, err := d.client.CreateSessionContext(context.Background())
-	if err != nil {
-		return fmt.Errorf("netcup: %w", err)
-	}
-
-	defer func() {
-		err = d.client.Logout(ctx)
-		if err != nil {
-			log.Printf("netcup: %v", err)
-		}
-	}()
+	defer d.release(token)
 
 	hostname := strings.Replace(info.EffectiveFQDN, "."+zone, "", 1)
 
 	zone = dns01.UnFqdn(zone)
 
-	records, err := d.client.GetDNSRecords(ctx, zone)
-	if err != nil {
-		return fmt.Errorf("netcup: %w", err)
-	}
-
 	record := internal.DNSRecord{
 		Hostname:    hostname,
 		RecordType:  "TXT",
 		Destination: info.Value,
 	}
 
-	idx, err := internal.GetDNSRecordIdx(records, record)
-	if err != nil {
-		return fmt.Errorf("netcup: %w", err)
-	}
+	err = d.withSession(context.Background(), func(ctx context.Context) error {
+		records, errR := d.client.GetDNSRecords(ctx, zone)
+		if errR != nil {
+			return errR
+		}
+
+		idx, errR := internal.GetDNSRecordIdx(records, record)
+		if errR != nil {
+			return errR
+		}
 
-	records[idx].DeleteRecord = true
+		records[idx].DeleteRecord = true
 
-	err = d.client.UpdateDNSRecord(ctx, zone, []internal.DNSRecord{records[idx]})
+		return d.client.UpdateDNSRecord(ctx, zone, []internal.DNSRecord{records[idx]})
+	})
 	if err != nil {
 		return fmt.Errorf("netcup: %w", err)
 	}
@@ -204,3 +226,52 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
+
+// withSession calls fn with a context holding an API session.
+// The session is created on the first call and reused by the next calls,
+// a new session is created once if the API reports the session as invalid or expired.
+func (d *DNSProvider) withSession(ctx context.Context, fn func(ctx context.Context) error) error {
+	d.sessionMu.Lock()
+	defer d.sessionMu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if d.sessionID == "" {
+			sessionCtx, err := d.client.CreateSessionContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			d.sessionID = internal.SessionID(sessionCtx)
+		}
+
+		err := fn(internal.WithSessionID(ctx, d.sessionID))
+		if err == nil || attempt > 0 || !internal.IsSessionError(err) {
+			return err
+		}
+
+		log.OrDefault(d.config.Logger).Info("netcup: the session has expired, creating a new session.")
+
+		d.sessionID = ""
+	}
+}
+
+// release removes the record of the token from the pending records,
+// and closes the API session when there are no more pending records.
+// A token without a pending record (ex: a failed Present) doesn't change the other pending records.
+func (d *DNSProvider) release(token string) {
+	d.sessionMu.Lock()
+	defer d.sessionMu.Unlock()
+
+	delete(d.pending, token)
+
+	if len(d.pending) > 0 || d.sessionID == "" {
+		return
+	}
+
+	err := d.client.Logout(internal.WithSessionID(context.Background(), d.sessionID))
+	if err != nil {
+		log.OrDefault(d.config.Logger).Warn("netcup: logout failed.", "error", err)
+	}
+
+	d.sessionID = ""
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -153,6 +154,25 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_release_notPending(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Customer = "A"
+	config.Key = "B"
+	config.Password = "C"
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	provider.sessionID = "session"
+	provider.pending["abc"] = struct{}{}
+
+	// The cleanup of a record not created (ex: after a failed Present) keeps the record of the other token, and the session.
+	provider.release("def")
+
+	assert.Contains(t, provider.pending, "abc")
+	assert.Equal(t, "session", provider.sessionID)
+}
+
 func TestLivePresentAndCleanup(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
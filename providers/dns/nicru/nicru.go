@@ -33,22 +33,22 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	TTL                int
-	Username           string
-	Password           string
-	ServiceID          string
-	Secret             string
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
+	TTL                int           `env:"TTL" default:"30"`
+	Username           string        `env:"USER,required"`
+	Password           string        `env:"PASSWORD,required"`
+	ServiceID          string        `env:"SERVICE_ID,required"`
+	Secret             string        `env:"SECRET,required"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT" default:"10m"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL" default:"1m"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 30),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 10*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 1*time.Minute),
-	}
+	config := &Config{}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -59,17 +59,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for RU Center.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvUsername, EnvPassword, EnvServiceID, EnvSecret)
+	config := &Config{}
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("nicru: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.Username = values[EnvUsername]
-	config.Password = values[EnvPassword]
-	config.ServiceID = values[EnvServiceID]
-	config.Secret = values[EnvSecret]
-
 	return NewDNSProviderConfig(config)
 }
 
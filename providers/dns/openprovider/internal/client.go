@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
+	"github.com/digicert/lego/v4/providers/dns/internal/useragent"
+)
+
+const defaultBaseURL = "https://api.openprovider.eu/v1beta"
+
+const authorizationHeader = "Authorization"
+
+// Client the Openprovider API client.
+type Client struct {
+	username string
+	password string
+	token    string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+
+	loginToken string
+	muLogin    sync.Mutex
+}
+
+// NewClient creates a new Client.
+// The credentials are a username and a password, or a token.
+func NewClient(username, password, token string) (*Client, error) {
+	if token == "" && (username == "" || password == "") {
+		return nil, errors.New("credentials missing")
+	}
+
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	return &Client{
+		username:   username,
+		password:   password,
+		token:      token,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// UpdateZoneRecords adds and removes records of a zone.
+// https://docs.openprovider.com/doc/all#operation/UpdateZone
+func (c *Client) UpdateZoneRecords(ctx context.Context, zone string, records RecordsUpdate) error {
+	endpoint := c.baseURL.JoinPath("dns", "zones", zone)
+
+	payload := ZoneUpdateRequest{Name: zone, Records: records}
+
+	req, err := newJSONRequest(ctx, http.MethodPut, endpoint, payload)
+	if err != nil {
+		return err
+	}
+
+	var result ZoneUpdateResponse
+
+	err = c.do(req, &result)
+	if err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return errors.New("the zone update was not successful")
+	}
+
+	return nil
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	useragent.SetHeader(req.Header)
+
+	tok := getToken(req.Context())
+	if tok != "" {
+		req.Header.Set(authorizationHeader, "Bearer "+tok)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	var response APIResponse
+
+	err = json.Unmarshal(raw, &response)
+	if err != nil {
+		if resp.StatusCode/100 != 2 {
+			return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+		}
+
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	if response.Code != 0 {
+		return &APIError{Code: response.Code, Desc: response.Desc}
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+	}
+
+	if result == nil || len(response.Data) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(response.Data, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(buf).Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient("user", "secret", "")
+			if err != nil {
+				return nil, err
+			}
+
+			client.baseURL, _ = url.Parse(server.URL)
+			client.HTTPClient = server.Client()
+
+			return client, nil
+		},
+		servermock.CheckHeader().WithJSONHeaders(),
+	)
+}
+
+func mockContext(t *testing.T) context.Context {
+	t.Helper()
+
+	return context.WithValue(t.Context(), tokenKey, "tok")
+}
+
+func TestClient_UpdateZoneRecords_add(t *testing.T) {
+	client := mockBuilder().
+		Route("PUT /dns/zones/example.com",
+			servermock.ResponseFromFixture("update_zone.json"),
+			servermock.CheckHeader().WithAuthorization("Bearer tok"),
+			servermock.CheckRequestJSONBodyFromFixture("update_zone_add-request.json")).
+		Build(t)
+
+	records := RecordsUpdate{
+		Add: []Record{{Name: "_acme-challenge", Type: "TXT", Value: "123d==", TTL: 900}},
+	}
+
+	err := client.UpdateZoneRecords(mockContext(t), "example.com", records)
+	require.NoError(t, err)
+}
+
+func TestClient_UpdateZoneRecords_remove(t *testing.T) {
+	client := mockBuilder().
+		Route("PUT /dns/zones/example.com",
+			servermock.ResponseFromFixture("update_zone.json"),
+			servermock.CheckHeader().WithAuthorization("Bearer tok"),
+			servermock.CheckRequestJSONBodyFromFixture("update_zone_remove-request.json")).
+		Build(t)
+
+	records := RecordsUpdate{
+		Remove: []Record{{Name: "_acme-challenge", Type: "TXT", Value: "123d==", TTL: 900}},
+	}
+
+	err := client.UpdateZoneRecords(mockContext(t), "example.com", records)
+	require.NoError(t, err)
+}
+
+func TestClient_UpdateZoneRecords_error(t *testing.T) {
+	client := mockBuilder().
+		Route("PUT /dns/zones/example.com",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusNotFound)).
+		Build(t)
+
+	records := RecordsUpdate{
+		Add: []Record{{Name: "_acme-challenge", Type: "TXT", Value: "123d==", TTL: 900}},
+	}
+
+	err := client.UpdateZoneRecords(mockContext(t), "example.com", records)
+	require.EqualError(t, err, "817: Zone example.com does not exist.")
+}
+
+func TestClient_Login(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /auth/login",
+			servermock.ResponseFromFixture("login.json"),
+			servermock.CheckRequestJSONBody(`{"username":"user","password":"secret","ip":"0.0.0.0"}`)).
+		Build(t)
+
+	tok, err := client.Login(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, "c8b3a2e1d0f94e7b8a6c5d4e3f2a1b0c", tok)
+}
+
+func TestClient_CreateAuthenticatedContext(t *testing.T) {
+	var calls int
+
+	client := mockBuilder().
+		Route("POST /auth/login",
+			servermock.ResponseFromFixture("login.json"),
+			servermock.LinkFunc(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					calls++
+
+					next.ServeHTTP(rw, req)
+				})
+			})).
+		Build(t)
+
+	for range 2 {
+		ctx, err := client.CreateAuthenticatedContext(t.Context())
+		require.NoError(t, err)
+
+		assert.Equal(t, "c8b3a2e1d0f94e7b8a6c5d4e3f2a1b0c", getToken(ctx))
+	}
+
+	// The token is reused.
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_CreateAuthenticatedContext_token(t *testing.T) {
+	client, err := NewClient("", "", "secret-token")
+	require.NoError(t, err)
+
+	ctx, err := client.CreateAuthenticatedContext(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret-token", getToken(ctx))
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type token string
+
+const tokenKey token = "token"
+
+// Login gets an authentication token.
+// https://docs.openprovider.com/doc/all#operation/Login
+func (c *Client) Login(ctx context.Context) (string, error) {
+	endpoint := c.baseURL.JoinPath("auth", "login")
+
+	// The IP "0.0.0.0" allows the login from any IP address allowed by the account settings.
+	payload := LoginRequest{Username: c.username, Password: c.password, IP: "0.0.0.0"}
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		return "", err
+	}
+
+	var result LoginResponse
+
+	err = c.do(req, &result)
+	if err != nil {
+		return "", err
+	}
+
+	if result.Token == "" {
+		return "", errors.New("empty token")
+	}
+
+	return result.Token, nil
+}
+
+// CreateAuthenticatedContext adds the token to the context.
+// The token obtained with the login is reused (the token is valid during 48 hours).
+func (c *Client) CreateAuthenticatedContext(ctx context.Context) (context.Context, error) {
+	if c.token != "" {
+		return context.WithValue(ctx, tokenKey, c.token), nil
+	}
+
+	c.muLogin.Lock()
+	defer c.muLogin.Unlock()
+
+	if c.loginToken == "" {
+		tok, err := c.Login(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.loginToken = tok
+	}
+
+	return context.WithValue(ctx, tokenKey, c.loginToken), nil
+}
+
+func getToken(ctx context.Context) string {
+	tok, ok := ctx.Value(tokenKey).(string)
+	if !ok {
+		return ""
+	}
+
+	return tok
+}
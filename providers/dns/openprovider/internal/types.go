@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type APIResponse struct {
+	Code int             `json:"code"`
+	Desc string          `json:"desc,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type APIError struct {
+	Code int    `json:"code"`
+	Desc string `json:"desc"`
+}
+
+func (a *APIError) Error() string {
+	return fmt.Sprintf("%d: %s", a.Code, a.Desc)
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	IP       string `json:"ip"`
+}
+
+type LoginResponse struct {
+	Token      string `json:"token"`
+	ResellerID int    `json:"reseller_id"`
+}
+
+type ZoneUpdateRequest struct {
+	Name    string        `json:"name"`
+	Records RecordsUpdate `json:"records"`
+}
+
+type RecordsUpdate struct {
+	Add    []Record `json:"add,omitempty"`
+	Remove []Record `json:"remove,omitempty"`
+}
+
+type Record struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	TTL   int    `json:"ttl,omitempty"`
+}
+
+type ZoneUpdateResponse struct {
+	Success bool `json:"success"`
+}
@@ -0,0 +1,181 @@
+// Package openprovider implements a DNS provider for solving the DNS-01 challenge using Openprovider.
+package openprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/digicert/lego/v4/providers/dns/openprovider/internal"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "OPENPROVIDER_"
+
+	EnvUsername = envNamespace + "USERNAME"
+	EnvPassword = envNamespace + "PASSWORD"
+	EnvToken    = envNamespace + "TOKEN"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// minTTL the minimal TTL allowed by Openprovider.
+const minTTL = 900
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Username string
+	Password string
+	Token    string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 5*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Openprovider.
+// Credentials must be passed in the environment variables:
+// OPENPROVIDER_USERNAME and OPENPROVIDER_PASSWORD, or OPENPROVIDER_TOKEN.
+func NewDNSProvider() (*DNSProvider, error) {
+	config := NewDefaultConfig()
+
+	config.Token = env.GetOrFile(EnvToken)
+
+	if config.Token == "" {
+		values, err := env.Get(EnvUsername, EnvPassword)
+		if err != nil {
+			return nil, fmt.Errorf("openprovider: %w", err)
+		}
+
+		config.Username = values[EnvUsername]
+		config.Password = values[EnvPassword]
+	}
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Openprovider.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("openprovider: the configuration of the DNS provider is nil")
+	}
+
+	if config.TTL < minTTL {
+		return nil, fmt.Errorf("openprovider: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
+	}
+
+	client, err := internal.NewClient(config.Username, config.Password, config.Token)
+	if err != nil {
+		return nil, fmt.Errorf("openprovider: %w", err)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+
+	return &DNSProvider{config: config, client: client}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, record, err := d.newRecord(info)
+	if err != nil {
+		return fmt.Errorf("openprovider: %w", err)
+	}
+
+	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
+	if err != nil {
+		return fmt.Errorf("openprovider: login: %w", err)
+	}
+
+	err = d.client.UpdateZoneRecords(ctx, zone, internal.RecordsUpdate{Add: []internal.Record{record}})
+	if err != nil {
+		return fmt.Errorf("openprovider: add record: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, record, err := d.newRecord(info)
+	if err != nil {
+		return fmt.Errorf("openprovider: %w", err)
+	}
+
+	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
+	if err != nil {
+		return fmt.Errorf("openprovider: login: %w", err)
+	}
+
+	err = d.client.UpdateZoneRecords(ctx, zone, internal.RecordsUpdate{Remove: []internal.Record{record}})
+	if err != nil {
+		return fmt.Errorf("openprovider: remove record: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) newRecord(info dns01.ChallengeInfo) (string, internal.Record, error) {
+	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return "", internal.Record{}, fmt.Errorf("could not find zone for domain %q: %w", info.EffectiveFQDN, err)
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	if err != nil {
+		return "", internal.Record{}, err
+	}
+
+	record := internal.Record{
+		Name:  subDomain,
+		Type:  "TXT",
+		Value: info.Value,
+		TTL:   d.config.TTL,
+	}
+
+	return dns01.UnFqdn(authZone), record, nil
+}
@@ -0,0 +1,177 @@
+package openprovider
+
+import (
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvUsername, EnvPassword, EnvToken, EnvTTL).WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvUsername: "user",
+				EnvPassword: "secret",
+			},
+		},
+		{
+			desc: "success with a token",
+			envVars: map[string]string{
+				EnvToken: "token",
+			},
+		},
+		{
+			desc: "missing username",
+			envVars: map[string]string{
+				EnvPassword: "secret",
+			},
+			expected: "openprovider: some credentials information are missing: OPENPROVIDER_USERNAME",
+		},
+		{
+			desc: "missing password",
+			envVars: map[string]string{
+				EnvUsername: "user",
+			},
+			expected: "openprovider: some credentials information are missing: OPENPROVIDER_PASSWORD",
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "openprovider: some credentials information are missing: OPENPROVIDER_USERNAME,OPENPROVIDER_PASSWORD",
+		},
+		{
+			desc: "invalid TTL",
+			envVars: map[string]string{
+				EnvToken: "token",
+				EnvTTL:   "120",
+			},
+			expected: "openprovider: invalid TTL, TTL (120) must be greater than 900",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		username string
+		password string
+		token    string
+		ttl      int
+		expected string
+	}{
+		{
+			desc:     "success",
+			username: "user",
+			password: "secret",
+			ttl:      minTTL,
+		},
+		{
+			desc:  "success with a token",
+			token: "token",
+			ttl:   minTTL,
+		},
+		{
+			desc:     "missing username",
+			password: "secret",
+			ttl:      minTTL,
+			expected: "openprovider: credentials missing",
+		},
+		{
+			desc:     "missing password",
+			username: "user",
+			ttl:      minTTL,
+			expected: "openprovider: credentials missing",
+		},
+		{
+			desc:     "missing credentials",
+			ttl:      minTTL,
+			expected: "openprovider: credentials missing",
+		},
+		{
+			desc:     "invalid TTL",
+			token:    "token",
+			ttl:      120,
+			expected: "openprovider: invalid TTL, TTL (120) must be greater than 900",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Username = test.username
+			config.Password = test.password
+			config.Token = test.token
+			config.TTL = test.ttl
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"slices"
 	"strings"
@@ -16,9 +17,11 @@ import (
 
 type environmentConfigurationProvider struct {
 	values map[string]string
+
+	logger *slog.Logger
 }
 
-func newEnvironmentConfigurationProvider() (*environmentConfigurationProvider, error) {
+func newEnvironmentConfigurationProvider(logger *slog.Logger) (*environmentConfigurationProvider, error) {
 	values, err := env.GetWithFallback(
 		[]string{EnvRegion, altEnvTFVarRegion},
 		[]string{EnvUserOCID, altEnvTFVarUserOCID},
@@ -31,11 +34,12 @@ func newEnvironmentConfigurationProvider() (*environmentConfigurationProvider, e
 
 	return &environmentConfigurationProvider{
 		values: values,
+		logger: logger,
 	}, nil
 }
 
 func (p *environmentConfigurationProvider) PrivateRSAKey() (*rsa.PrivateKey, error) {
-	privateKey, err := getPrivateKey()
+	privateKey, err := getPrivateKey(p.logger)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +91,7 @@ func (p *environmentConfigurationProvider) privateKeyPassword() string {
 	return env.GetOneWithFallback(EnvPrivKeyPass, "", env.ParseString, altEnvPrivateKeyPassword, altEnvTFVarPrivateKeyPassword)
 }
 
-func getPrivateKey() ([]byte, error) {
+func getPrivateKey(logger *slog.Logger) ([]byte, error) {
 	base64EnvKeys := []string{envPrivKey, altEnvPrivateKey}
 
 	envVarValue := getEnvWithStrictFallback(base64EnvKeys...)
@@ -103,7 +107,7 @@ func getPrivateKey() ([]byte, error) {
 
 	fileEnvKeys := []string{EnvPrivKeyFile, altEnvPrivateKeyPath, altEnvTFVarPrivateKeyPath}
 
-	fileVarValue := getEnvFileWithStrictFallback(fileEnvKeys...)
+	fileVarValue := getEnvFileWithStrictFallback(logger, fileEnvKeys...)
 	if len(fileVarValue) == 0 {
 		return nil, fmt.Errorf("no value provided for: %s",
 			strings.Join(slices.Concat(base64EnvKeys, fileEnvKeys), " or "),
@@ -124,7 +128,7 @@ func getEnvWithStrictFallback(keys ...string) string {
 	return ""
 }
 
-func getEnvFileWithStrictFallback(keys ...string) []byte {
+func getEnvFileWithStrictFallback(logger *slog.Logger, keys ...string) []byte {
 	for _, key := range keys {
 		fileVarValue := os.Getenv(key)
 		if fileVarValue == "" {
@@ -133,7 +137,7 @@ func getEnvFileWithStrictFallback(keys ...string) []byte {
 
 		fileContents, err := os.ReadFile(fileVarValue)
 		if err != nil {
-			log.Printf("Failed to read the file %s (defined by env var %s): %s", fileVarValue, key, err)
+			log.OrDefault(logger).Warn("oraclecloud: Failed to read the file.", "file", fileVarValue, "envVar", key, "error", err)
 			return nil
 		}
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -56,6 +57,10 @@ const (
 	altEnvTFVarPrivateKeyPassword = altEnvTFVarNamespace + "private_key_password" // alias on OCI_PRIVKEY_PASS
 )
 
+// authTypeResourcePrincipal the authentication type used by the OCI Functions and other OCI resources.
+// https://docs.oracle.com/en-us/iaas/Content/Functions/Tasks/functionsaccessingociresources.htm
+const authTypeResourcePrincipal = "resource_principal"
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
@@ -67,16 +72,19 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, time.Minute),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, time.Minute),
 		},
 	}
 }
@@ -109,6 +117,21 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 		config.OCIConfigProvider = configurationProvider
 
+	case authTypeResourcePrincipal:
+		values, err := env.Get(EnvCompartmentOCID)
+		if err != nil {
+			return nil, fmt.Errorf("oraclecloud: %w", err)
+		}
+
+		config.CompartmentID = values[EnvCompartmentOCID]
+
+		configurationProvider, err := newResourcePrincipalConfigurationProvider()
+		if err != nil {
+			return nil, fmt.Errorf("oraclecloud: %w", err)
+		}
+
+		config.OCIConfigProvider = configurationProvider
+
 	default:
 		values, err := env.Get(EnvCompartmentOCID)
 		if err != nil {
@@ -117,7 +140,7 @@ func NewDNSProvider() (*DNSProvider, error) {
 
 		config.CompartmentID = values[EnvCompartmentOCID]
 
-		ecp, err := newEnvironmentConfigurationProvider()
+		ecp, err := newEnvironmentConfigurationProvider(config.Logger)
 		if err != nil {
 			return nil, fmt.Errorf("oraclecloud: %w", err)
 		}
@@ -257,3 +280,15 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
+
+// newResourcePrincipalConfigurationProvider creates a configuration provider based on the resource principal.
+// The resource principal information are provided by the OCI runtime through the `OCI_RESOURCE_PRINCIPAL_*` environment variables.
+// The region defined by `OCI_REGION` overrides the region of the resource principal.
+func newResourcePrincipalConfigurationProvider() (common.ConfigurationProvider, error) {
+	region := env.GetOneWithFallback(EnvRegion, "", env.ParseString, altEnvTFVarRegion)
+	if region == "" {
+		return auth.ResourcePrincipalConfigurationProvider()
+	}
+
+	return auth.ResourcePrincipalConfigurationProviderForRegion(common.Region(region))
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -92,7 +93,7 @@ func (c *Client) getZones(ctx context.Context, zone string, privateZone bool) (*
 }
 
 func (c *Client) GetRecordSetID(ctx context.Context, zoneID, fqdn string) (string, error) {
-	recordSetsRes, err := c.getRecordSet(ctx, zoneID, fqdn)
+	recordSetsRes, err := c.ListRecordSets(ctx, zoneID, fqdn, nil)
 	if err != nil {
 		return "", err
 	}
@@ -112,15 +113,26 @@ func (c *Client) GetRecordSetID(ctx context.Context, zoneID, fqdn string) (strin
 	return recordSetsRes.RecordSets[0].ID, nil
 }
 
+// ListRecordSets returns the TXT record sets of the zone whose name contains the given name (fuzzy search).
+// The pagination is optional.
 // https://docs.otc.t-systems.com/domain-name-service/api-ref/apis/record_set_management/querying_all_record_sets.html
-func (c *Client) getRecordSet(ctx context.Context, zoneID, fqdn string) (*RecordSetsResponse, error) {
+func (c *Client) ListRecordSets(ctx context.Context, zoneID, name string, page *Pagination) (*RecordSetsResponse, error) {
 	c.muBaseURL.Lock()
 	endpoint := c.baseURL.JoinPath("zones", zoneID, "recordsets")
 	c.muBaseURL.Unlock()
 
 	query := endpoint.Query()
 	query.Set("type", "TXT")
-	query.Set("name", fqdn)
+	query.Set("name", name)
+
+	if page != nil {
+		query.Set("limit", strconv.Itoa(page.Limit))
+
+		if page.Marker != "" {
+			query.Set("marker", page.Marker)
+		}
+	}
+
 	endpoint.RawQuery = query.Encode()
 
 	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
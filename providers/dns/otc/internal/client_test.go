@@ -82,6 +82,26 @@ func TestClient_GetRecordSetID(t *testing.T) {
 	assert.Equal(t, "321321", recordSetID)
 }
 
+func TestClient_ListRecordSets(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /zones/123123/recordsets",
+			servermock.ResponseFromFixture("zones-recordsets_GET.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "_acme-challenge").
+				With("type", "TXT").
+				With("limit", "500").
+				With("marker", "123"),
+		).
+		Build(t)
+
+	recordSetsRes, err := client.ListRecordSets(context.Background(), "123123", "_acme-challenge", &Pagination{Limit: 500, Marker: "123"})
+	require.NoError(t, err)
+
+	require.Len(t, recordSetsRes.RecordSets, 1)
+	assert.Equal(t, "321321", recordSetsRes.RecordSets[0].ID)
+	assert.Equal(t, "2016-11-17T11:56:03.439", recordSetsRes.RecordSets[0].CreateAt)
+}
+
 func TestClient_GetRecordSetID_error(t *testing.T) {
 	client := mockBuilder().
 		Route("GET /zones/123123/recordsets",
@@ -77,6 +77,12 @@ type Role struct {
 	Name string `json:"name,omitempty"`
 }
 
+// Pagination the pagination of the lists: the page starts after the resource with the Marker ID.
+type Pagination struct {
+	Limit  int
+	Marker string
+}
+
 // RecordSetsResponse
 
 type RecordSetsResponse struct {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -38,7 +39,16 @@ const defaultIdentityEndpoint = "https://iam.eu-de.otc.t-systems.com:443/v3/auth
 // minTTL 300 is otc minimum value for TTL.
 const minTTL = 300
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+// recordSetsPageSize the maximum number of record sets returned by a call to the API.
+const recordSetsPageSize = 500
+
+// createdAtLayout the layout of the creation date of the record sets (UTC).
+const createdAtLayout = "2006-01-02T15:04:05.999999999"
+
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ dns01.RecordLister        = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -73,11 +83,11 @@ func NewDefaultConfig() *Config {
 		IdentityEndpoint: env.GetOrDefaultString(EnvIdentityEndpoint, defaultIdentityEndpoint),
 
 		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
-		SequenceInterval:   env.GetOrDefaultSecond(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+		SequenceInterval:   env.GetOrDefaultDuration(EnvSequenceInterval, dns01.DefaultPropagationTimeout),
 		HTTPClient: &http.Client{
-			Timeout:   env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
+			Timeout:   env.GetOrDefaultDuration(EnvHTTPTimeout, 10*time.Second),
 			Transport: tr,
 		},
 	}
@@ -218,3 +228,78 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 func (d *DNSProvider) Sequential() time.Duration {
 	return d.config.SequenceInterval
 }
+
+// ListChallengeRecords returns the challenge TXT record sets of the zone.
+func (d *DNSProvider) ListChallengeRecords(ctx context.Context, zone string) ([]dns01.ChallengeRecord, error) {
+	zoneID, err := d.findZoneID(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &internal.Pagination{Limit: recordSetsPageSize}
+
+	var results []dns01.ChallengeRecord
+
+	for {
+		recordSetsRes, err := d.client.ListRecordSets(ctx, zoneID, "_acme-challenge", page)
+		if err != nil {
+			return nil, fmt.Errorf("otc: list record sets: %w", err)
+		}
+
+		for _, recordSet := range recordSetsRes.RecordSets {
+			var values []string
+			for _, value := range recordSet.Records {
+				values = append(values, strings.Trim(value, `"`))
+			}
+
+			result := dns01.ChallengeRecord{
+				ID:    recordSet.ID,
+				FQDN:  dns01.ToFqdn(recordSet.Name),
+				Value: strings.Join(values, ", "),
+			}
+
+			// An unknown creation date keeps the record.
+			createdAt, err := time.Parse(createdAtLayout, recordSet.CreateAt)
+			if err == nil {
+				result.CreatedAt = createdAt
+			}
+
+			results = append(results, result)
+		}
+
+		if len(recordSetsRes.RecordSets) < recordSetsPageSize {
+			return results, nil
+		}
+
+		page.Marker = recordSetsRes.RecordSets[len(recordSetsRes.RecordSets)-1].ID
+	}
+}
+
+// DeleteChallengeRecord removes a challenge TXT record set of the zone.
+func (d *DNSProvider) DeleteChallengeRecord(ctx context.Context, zone string, record dns01.ChallengeRecord) error {
+	zoneID, err := d.findZoneID(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	err = d.client.DeleteRecordSet(ctx, zoneID, record.ID)
+	if err != nil {
+		return fmt.Errorf("otc: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) findZoneID(ctx context.Context, zone string) (string, error) {
+	err := d.client.Login(ctx)
+	if err != nil {
+		return "", fmt.Errorf("otc: %w", err)
+	}
+
+	zoneID, err := d.client.GetZoneID(ctx, dns01.ToFqdn(zone), d.config.PrivateZone)
+	if err != nil {
+		return "", fmt.Errorf("otc: unable to get zone: %w", err)
+	}
+
+	return zoneID, nil
+}
@@ -6,9 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/digicert/lego/v4/providers/dns/otc/internal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -315,6 +317,47 @@ func TestDNSProvider_Cleanup_emptyRecordset(t *testing.T) {
 	require.EqualError(t, err, "otc: unable to get record _acme-challenge.example.com. for zone example.com: record not found")
 }
 
+func TestDNSProvider_ListChallengeRecords(t *testing.T) {
+	provider := mockBuilder(false).
+		Route("GET /v2/zones",
+			servermock.ResponseFromInternal("zones_GET.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com.")).
+		Route("GET /v2/zones/123123/recordsets",
+			servermock.ResponseFromInternal("zones-recordsets_GET.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "_acme-challenge").
+				With("type", "TXT").
+				With("limit", "500")).
+		Build(t)
+
+	records, err := provider.ListChallengeRecords(t.Context(), "example.com.")
+	require.NoError(t, err)
+
+	expected := []dns01.ChallengeRecord{{
+		ID:        "321321",
+		FQDN:      "_acme-challenge.example.com.",
+		Value:     "ns1.hotrot.de. xx.example.com. (1 7200 900 1209600 300)",
+		CreatedAt: time.Date(2016, time.November, 17, 11, 56, 3, 439000000, time.UTC),
+	}}
+
+	assert.Equal(t, expected, records)
+}
+
+func TestDNSProvider_DeleteChallengeRecord(t *testing.T) {
+	provider := mockBuilder(false).
+		Route("GET /v2/zones",
+			servermock.ResponseFromInternal("zones_GET.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com.")).
+		Route("DELETE /v2/zones/123123/recordsets/321321",
+			servermock.ResponseFromInternal("zones-recordsets_DELETE.json")).
+		Build(t)
+
+	err := provider.DeleteChallengeRecord(t.Context(), "example.com.", dns01.ChallengeRecord{ID: "321321"})
+	require.NoError(t, err)
+}
+
 func mockBuilder(private bool) *servermock.Builder[*DNSProvider] {
 	return servermock.NewBuilder(
 		func(server *httptest.Server) (*DNSProvider, error) {
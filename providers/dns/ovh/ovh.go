@@ -2,15 +2,23 @@
 package ovh
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v5"
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/log"
 	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/platform/wait"
 	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
 	"github.com/digicert/lego/v4/providers/dns/internal/useragent"
 	"github.com/ovh/go-ovh/ovh"
@@ -48,7 +56,14 @@ const (
 // EnvAccessToken Authenticate using Access Token client.
 const EnvAccessToken = envNamespace + "ACCESS_TOKEN"
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+// refreshMaxTries the maximum number of calls to refresh a zone.
+const refreshMaxTries = 5
+
+var (
+	_ challenge.ProviderTimeout    = (*DNSProvider)(nil)
+	_ challenge.ProviderBatch      = (*DNSProvider)(nil)
+	_ challenge.ProviderPropagated = (*DNSProvider)(nil)
+)
 
 // Record a DNS record.
 type Record struct {
@@ -60,6 +75,11 @@ type Record struct {
 	Zone      string `json:"zone,omitempty"`
 }
 
+// ZoneStatus the status of a zone.
+type ZoneStatus struct {
+	IsDeployed bool `json:"isDeployed"`
+}
+
 // OAuth2Config the OAuth2 specific configuration.
 type OAuth2Config struct {
 	ClientID     string
@@ -82,16 +102,19 @@ type Config struct {
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 3*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 5*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, ovh.DefaultTimeout),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, ovh.DefaultTimeout),
 		},
 	}
 }
@@ -106,6 +129,7 @@ type DNSProvider struct {
 	client *ovh.Client
 
 	recordIDs   map[string]int64
+	recordZones map[string]string
 	recordIDsMu sync.Mutex
 }
 
@@ -165,26 +189,62 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	return &DNSProvider{
-		config:    config,
-		client:    client,
-		recordIDs: make(map[string]int64),
+		config:      config,
+		client:      client,
+		recordIDs:   make(map[string]int64),
+		recordZones: make(map[string]string),
 	}, nil
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	authZone, err := d.addRecord(domain, token, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	return d.refreshZone(authZone)
+}
+
+// PresentAll creates the TXT records of several challenges, and refreshes each zone once.
+func (d *DNSProvider) PresentAll(challenges []challenge.ChallengeInfo) error {
+	var zones []string
+
+	for _, chlg := range challenges {
+		authZone, err := d.addRecord(chlg.Domain, chlg.Token, chlg.KeyAuth)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(zones, authZone) {
+			zones = append(zones, authZone)
+		}
+	}
+
+	for _, authZone := range zones {
+		err := d.refreshZone(authZone)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addRecord creates the TXT record of a challenge (without refreshing the zone), and returns the zone.
+func (d *DNSProvider) addRecord(domain, token, keyAuth string) (string, error) {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("ovh: could not find zone for domain %q: %w", domain, err)
+		return "", fmt.Errorf("ovh: could not find zone for domain %q: %w", domain, err)
 	}
 
 	authZone = dns01.UnFqdn(authZone)
 
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
 	if err != nil {
-		return fmt.Errorf("ovh: %w", err)
+		return "", fmt.Errorf("ovh: %w", err)
 	}
 
 	reqURL := fmt.Sprintf("/domain/zone/%s/record", authZone)
@@ -195,112 +255,184 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	err = d.client.Post(reqURL, reqData, &respData)
 	if err != nil {
-		return fmt.Errorf("ovh: error when call api to add record (%s): %w", reqURL, err)
+		return "", fmt.Errorf("ovh: error when call api to add record (%s): %w", reqURL, err)
 	}
 
-	// Apply the change
-	reqURL = fmt.Sprintf("/domain/zone/%s/refresh", authZone)
+	d.recordIDsMu.Lock()
+	d.recordIDs[token] = respData.ID
+	d.recordZones[token] = authZone
+	d.recordIDsMu.Unlock()
+
+	return authZone, nil
+}
+
+// refreshZone applies the changes of the zone.
+// The refresh is retried, with an exponential backoff, on transient errors.
+func (d *DNSProvider) refreshZone(authZone string) error {
+	reqURL := fmt.Sprintf("/domain/zone/%s/refresh", authZone)
+
+	err := wait.Retry(context.Background(),
+		func() error {
+			errR := d.client.Post(reqURL, nil, nil)
+			if errR != nil && !isTransientError(errR) {
+				return backoff.Permanent(errR)
+			}
 
-	err = d.client.Post(reqURL, nil, nil)
+			return errR
+		},
+		backoff.WithBackOff(backoff.NewExponentialBackOff()),
+		backoff.WithMaxTries(refreshMaxTries),
+	)
 	if err != nil {
 		return fmt.Errorf("ovh: error when call api to refresh zone (%s): %w", reqURL, err)
 	}
 
+	return nil
+}
+
+// Propagated reports the TXT record as propagated when the refreshed zone is deployed on the name servers of OVH.
+func (d *DNSProvider) Propagated(_, token string) (bool, error) {
 	d.recordIDsMu.Lock()
-	d.recordIDs[token] = respData.ID
+	authZone, ok := d.recordZones[token]
 	d.recordIDsMu.Unlock()
 
-	return nil
+	if !ok {
+		return false, nil
+	}
+
+	reqURL := fmt.Sprintf("/domain/zone/%s/status", authZone)
+
+	var status ZoneStatus
+
+	err := d.client.Get(reqURL, &status)
+	if err != nil {
+		return false, fmt.Errorf("ovh: error when call api to get the zone status (%s): %w", reqURL, err)
+	}
+
+	return status.IsDeployed, nil
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
+// Only the record created by Present is removed:
+// the record is found by its ID, or by its value if the ID is unknown (ex: the record was created by another instance of the provider).
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	authZone, err := d.deleteRecord(domain, token, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	return d.refreshZone(authZone)
+}
+
+// CleanUpAll removes the TXT records of several challenges, and refreshes each zone once.
+// A failed removal doesn't prevent the removal of the other records.
+func (d *DNSProvider) CleanUpAll(challenges []challenge.ChallengeInfo) error {
+	var (
+		zones []string
+		errs  []error
+	)
+
+	for _, chlg := range challenges {
+		authZone, err := d.deleteRecord(chlg.Domain, chlg.Token, chlg.KeyAuth)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !slices.Contains(zones, authZone) {
+			zones = append(zones, authZone)
+		}
+	}
+
+	for _, authZone := range zones {
+		err := d.refreshZone(authZone)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deleteRecord removes the TXT record of a challenge (without refreshing the zone), and returns the zone.
+func (d *DNSProvider) deleteRecord(domain, token, keyAuth string) (string, error) {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
-		return fmt.Errorf("ovh: could not find zone for domain %q: %w", domain, err)
+		return "", fmt.Errorf("ovh: could not find zone for domain %q: %w", domain, err)
 	}
 
 	authZone = dns01.UnFqdn(authZone)
 
 	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
 	if err != nil {
-		return fmt.Errorf("ovh: %w", err)
+		return "", fmt.Errorf("ovh: %w", err)
 	}
 
-	// Get all records for the zone
-	records, err := d.listTXTRecords(authZone)
-	if err != nil {
-		return fmt.Errorf("ovh: error listing TXT records: %w", err)
-	}
-
-	fmt.Printf("ovh: found %d TXT records for zone %s\n", len(records), authZone)
-
-	deletionCount := 0
-	// Delete records matching the FQDN
-	for _, record := range records {
-		if record.SubDomain == subDomain && record.FieldType == "TXT" {
-			reqURL := fmt.Sprintf("/domain/zone/%s/record/%d", authZone, record.ID)
-
-			fmt.Printf("ovh: deleting TXT record ID %d with subdomain %s and value %s\n",
-				record.ID, record.SubDomain, record.Target)
-
-			err = d.client.Delete(reqURL, nil)
-			if err != nil {
-				return fmt.Errorf("ovh: error when call OVH api to delete challenge record (%s): %w", reqURL, err)
-			}
+	// gets the record's unique ID from when we created it
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	d.recordIDsMu.Unlock()
 
-			deletionCount++
-			fmt.Printf("ovh: successfully deleted TXT record ID %d\n", record.ID)
+	if !ok {
+		recordID, err = d.findRecordID(authZone, subDomain, info.Value)
+		if err != nil {
+			return "", fmt.Errorf("ovh: %w", err)
 		}
 	}
 
-	fmt.Printf("ovh: deleted %d TXT records for subdomain %s in zone %s\n", deletionCount, subDomain, authZone)
+	reqURL := fmt.Sprintf("/domain/zone/%s/record/%d", authZone, recordID)
 
-	reqURL := fmt.Sprintf("/domain/zone/%s/refresh", authZone)
-	fmt.Printf("ovh: refreshing zone %s\n", authZone)
+	log.OrDefault(d.config.Logger).Debug("ovh: deleting TXT record.", "zone", authZone, "recordID", recordID, "subDomain", subDomain)
 
-	err = d.client.Post(reqURL, nil, nil)
+	err = d.client.Delete(reqURL, nil)
 	if err != nil {
-		return fmt.Errorf("ovh: error when call api to refresh zone (%s): %w", reqURL, err)
+		return "", fmt.Errorf("ovh: error when call OVH api to delete challenge record (%s): %w", reqURL, err)
 	}
 
-	fmt.Printf("ovh: zone %s refreshed successfully\n", authZone)
-	return nil
+	// deletes record ID from map
+	d.recordIDsMu.Lock()
+	delete(d.recordIDs, token)
+	delete(d.recordZones, token)
+	d.recordIDsMu.Unlock()
+
+	return authZone, nil
 }
 
-// listTXTRecords lists all TXT records for the specified zone
-func (d *DNSProvider) listTXTRecords(zone string) ([]Record, error) {
-	// Get all record IDs for the zone
+// findRecordID finds the ID of the TXT record of a subdomain with a specific value.
+func (d *DNSProvider) findRecordID(zone, subDomain, value string) (int64, error) {
+	query := url.Values{}
+	query.Set("fieldType", "TXT")
+	query.Set("subDomain", subDomain)
+
+	reqURL := fmt.Sprintf("/domain/zone/%s/record?%s", zone, query.Encode())
+
+	// The API filters the records, only the IDs of the TXT records of the subdomain are returned.
 	var recordIDs []int64
-	reqURL := fmt.Sprintf("/domain/zone/%s/record", zone)
 
-	// Using fieldType parameter for filtering directly in the API call
 	err := d.client.Get(reqURL, &recordIDs)
 	if err != nil {
-		return nil, fmt.Errorf("ovh: error getting record IDs: %w", err)
+		return 0, fmt.Errorf("error getting record IDs (%s): %w", reqURL, err)
 	}
 
-	records := make([]Record, 0, len(recordIDs))
-
-	// Then get details for each record and filter by TXT type
 	for _, id := range recordIDs {
 		var record Record
+
 		reqURL := fmt.Sprintf("/domain/zone/%s/record/%d", zone, id)
 
 		err := d.client.Get(reqURL, &record)
 		if err != nil {
-			return nil, fmt.Errorf("ovh: error getting record details for ID %d: %w", id, err)
+			return 0, fmt.Errorf("error getting record details for ID %d: %w", id, err)
 		}
 
-		// Only include TXT records
-		if record.FieldType == "TXT" {
-			records = append(records, record)
+		// The API can return the value of a TXT record between quotes.
+		if record.FieldType == "TXT" && strings.Trim(record.Target, `"`) == value {
+			return record.ID, nil
 		}
 	}
 
-	return records, nil
+	return 0, fmt.Errorf("no TXT record found for %q in the zone %s", subDomain, zone)
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -340,3 +472,13 @@ func newClient(config *Config) (*ovh.Client, error) {
 
 	return client, nil
 }
+
+// isTransientError returns true if the error is likely to be transient (ex: a server error of the API).
+func isTransientError(err error) bool {
+	var apiErr *ovh.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= http.StatusInternalServerError
+	}
+
+	return dns01.IsTransientError(err)
+}
@@ -1,10 +1,20 @@
 package ovh
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/ovh/go-ovh/ovh"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -379,3 +389,167 @@ func TestLiveCleanUp(t *testing.T) {
 	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
 	require.NoError(t, err)
 }
+
+func mockBuilder() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			config := NewDefaultConfig()
+			config.APIEndpoint = server.URL
+			config.AccessToken = "secret"
+			config.HTTPClient = server.Client()
+
+			return NewDNSProviderConfig(config)
+		},
+		servermock.CheckHeader().
+			WithAuthorization("Bearer secret"),
+	)
+}
+
+// unexpectedCall fails the test if the route is called.
+func unexpectedCall(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		t.Errorf("unexpected call: %s %s", req.Method, req.URL.Path)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /domain/zone/example.com/record",
+			servermock.JSONEncode(Record{ID: 2, FieldType: "TXT", SubDomain: "_acme-challenge", Target: "123d==", Zone: "example.com"}),
+			servermock.CheckRequestJSONBody(`{"fieldType":"TXT","subDomain":"_acme-challenge","target":"123d==","ttl":120}`)).
+		Route("POST /domain/zone/example.com/refresh", servermock.Noop()).
+		// Another TXT record of the same subdomain (ex: another ACME client): it must not be removed.
+		Route("DELETE /domain/zone/example.com/record/1", unexpectedCall(t)).
+		Route("DELETE /domain/zone/example.com/record/2", servermock.Noop()).
+		Build(t)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	err = provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Empty(t, provider.recordIDs)
+}
+
+func TestDNSProvider_CleanUp_unknownRecordID(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /domain/zone/example.com/record",
+			servermock.JSONEncode([]int64{1, 2}),
+			servermock.CheckQueryParameter().
+				With("fieldType", "TXT").
+				With("subDomain", "_acme-challenge")).
+		Route("GET /domain/zone/example.com/record/1",
+			servermock.JSONEncode(Record{ID: 1, FieldType: "TXT", SubDomain: "_acme-challenge", Target: `"xyz=="`, Zone: "example.com"})).
+		Route("GET /domain/zone/example.com/record/2",
+			servermock.JSONEncode(Record{ID: 2, FieldType: "TXT", SubDomain: "_acme-challenge", Target: `"123d=="`, Zone: "example.com"})).
+		Route("DELETE /domain/zone/example.com/record/1", unexpectedCall(t)).
+		Route("DELETE /domain/zone/example.com/record/2", servermock.Noop()).
+		Route("POST /domain/zone/example.com/refresh", servermock.Noop()).
+		Build(t)
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUp_refreshRetry(t *testing.T) {
+	var refreshCalls atomic.Int32
+
+	provider := mockBuilder().
+		Route("DELETE /domain/zone/example.com/record/2", servermock.Noop()).
+		Route("POST /domain/zone/example.com/refresh", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if refreshCalls.Add(1) == 1 {
+				servermock.JSONEncode(map[string]string{"message": "Service unavailable"}).
+					WithStatusCode(http.StatusServiceUnavailable).
+					ServeHTTP(rw, req)
+
+				return
+			}
+
+			servermock.Noop().ServeHTTP(rw, req)
+		})).
+		Build(t)
+
+	provider.recordIDs["abc"] = 2
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), refreshCalls.Load())
+}
+
+func TestDNSProvider_CleanUpAll(t *testing.T) {
+	var refreshCalls atomic.Int32
+
+	provider := mockBuilder().
+		Route("DELETE /domain/zone/example.com/record/2", servermock.Noop()).
+		Route("DELETE /domain/zone/example.com/record/3", servermock.Noop()).
+		Route("POST /domain/zone/example.com/refresh", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			refreshCalls.Add(1)
+
+			servermock.Noop().ServeHTTP(rw, req)
+		})).
+		Build(t)
+
+	provider.recordIDs["abc"] = 2
+	provider.recordIDs["def"] = 3
+
+	err := provider.CleanUpAll([]challenge.ChallengeInfo{
+		{Domain: "example.com", Token: "abc", KeyAuth: "123d=="},
+		{Domain: "example.com", Token: "def", KeyAuth: "456d=="},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), refreshCalls.Load())
+	assert.Empty(t, provider.recordIDs)
+}
+
+func TestDNSProvider_Propagated(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /domain/zone/example.com/record",
+			servermock.JSONEncode(Record{ID: 2, FieldType: "TXT", SubDomain: "_acme-challenge", Target: "123d==", Zone: "example.com"})).
+		Route("POST /domain/zone/example.com/refresh", servermock.Noop()).
+		Route("GET /domain/zone/example.com/status",
+			servermock.JSONEncode(ZoneStatus{IsDeployed: true})).
+		Build(t)
+
+	propagated, err := provider.Propagated("example.com", "abc")
+	require.NoError(t, err)
+
+	// The record is unknown.
+	assert.False(t, propagated)
+
+	err = provider.Present("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	propagated, err = provider.Propagated("example.com", "abc")
+	require.NoError(t, err)
+
+	assert.True(t, propagated)
+}
+
+func Test_isTransientError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{desc: "server error", err: &ovh.APIError{Code: http.StatusServiceUnavailable}, expected: true},
+		{desc: "wrapped server error", err: fmt.Errorf("refresh: %w", &ovh.APIError{Code: http.StatusBadGateway}), expected: true},
+		{desc: "client error", err: &ovh.APIError{Code: http.StatusForbidden}},
+		{desc: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, expected: true},
+		{desc: "other error", err: errors.New("oops")},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, isTransientError(test.err))
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
+	"github.com/digicert/lego/v4/providers/dns/internal/useragent"
+)
+
+// Default API endpoints.
+const (
+	DefaultBaseURL = "https://api.yoursrs.com/v2"
+	SandboxBaseURL = "https://api.yoursrs-ote.com/v2"
+)
+
+const authorizationHeader = "Authorization"
+
+// Client the Realtime Register API client.
+type Client struct {
+	apiKey string
+
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(apiKey string) (*Client, error) {
+	if apiKey == "" {
+		return nil, errors.New("credentials missing")
+	}
+
+	baseURL, _ := url.Parse(DefaultBaseURL)
+
+	return &Client{
+		apiKey:     apiKey,
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// GetZoneByName finds a zone by its name.
+// https://dm.realtimeregister.com/docs/api/dns/zones/list
+func (c *Client) GetZoneByName(ctx context.Context, name string) (*Zone, error) {
+	endpoint := c.BaseURL.JoinPath("dns", "zones")
+
+	query := endpoint.Query()
+	query.Set("name", name)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ZonesResponse
+
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, zone := range result.Entities {
+		if zone.Name == name {
+			return &zone, nil
+		}
+	}
+
+	return nil, fmt.Errorf("zone %q not found", name)
+}
+
+// GetZone gets a zone and its records.
+// https://dm.realtimeregister.com/docs/api/dns/zones/get
+func (c *Client) GetZone(ctx context.Context, zoneID int) (*Zone, error) {
+	endpoint := c.BaseURL.JoinPath("dns", "zones", strconv.Itoa(zoneID))
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Zone
+
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateZone replaces the records of a zone.
+// https://dm.realtimeregister.com/docs/api/dns/zones/update
+func (c *Client) UpdateZone(ctx context.Context, zoneID int, records []Record) error {
+	endpoint := c.BaseURL.JoinPath("dns", "zones", strconv.Itoa(zoneID), "update")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, ZoneUpdateRequest{Records: records})
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, nil)
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	useragent.SetHeader(req.Header)
+
+	req.Header.Set(authorizationHeader, "ApiKey "+c.apiKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		return parseError(req, resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(buf).Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func parseError(req *http.Request, resp *http.Response) error {
+	raw, _ := io.ReadAll(resp.Body)
+
+	var errAPI APIError
+
+	err := json.Unmarshal(raw, &errAPI)
+	if err != nil || errAPI.Message == "" {
+		return errutils.NewUnexpectedStatusCodeError(req, resp.StatusCode, raw)
+	}
+
+	return &errAPI
+}
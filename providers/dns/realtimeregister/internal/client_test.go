@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient("secret")
+			if err != nil {
+				return nil, err
+			}
+
+			client.HTTPClient = server.Client()
+			client.BaseURL, _ = url.Parse(server.URL)
+
+			return client, nil
+		},
+		servermock.CheckHeader().
+			WithJSONHeaders().
+			WithAuthorization("ApiKey secret"),
+	)
+}
+
+func TestClient_GetZoneByName(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /dns/zones",
+			servermock.ResponseFromFixture("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com")).
+		Build(t)
+
+	zone, err := client.GetZoneByName(t.Context(), "example.com")
+	require.NoError(t, err)
+
+	expected := &Zone{ID: 1234, Name: "example.com", Service: "BASIC"}
+
+	assert.Equal(t, expected, zone)
+}
+
+func TestClient_GetZoneByName_notFound(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /dns/zones",
+			servermock.ResponseFromFixture("zones_empty.json")).
+		Build(t)
+
+	_, err := client.GetZoneByName(t.Context(), "example.com")
+	require.EqualError(t, err, `zone "example.com" not found`)
+}
+
+func TestClient_GetZone(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /dns/zones/1234",
+			servermock.ResponseFromFixture("zone.json")).
+		Build(t)
+
+	zone, err := client.GetZone(t.Context(), 1234)
+	require.NoError(t, err)
+
+	expected := &Zone{
+		ID:      1234,
+		Name:    "example.com",
+		Service: "BASIC",
+		Records: []Record{
+			{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: 3600},
+			{Name: "example.com", Type: "MX", Content: "mail.example.com", TTL: 3600, Priority: 10},
+		},
+	}
+
+	assert.Equal(t, expected, zone)
+}
+
+func TestClient_GetZone_error(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /dns/zones/1234",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusNotFound)).
+		Build(t)
+
+	_, err := client.GetZone(t.Context(), 1234)
+	require.EqualError(t, err, "ObjectDoesNotExist: Zone 1234 does not exist")
+}
+
+func TestClient_UpdateZone(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /dns/zones/1234/update", nil,
+			servermock.CheckRequestJSONBodyFromFixture("update_zone-request.json")).
+		Build(t)
+
+	records := []Record{
+		{Name: "example.com", Type: "A", Content: "192.0.2.1", TTL: 3600},
+		{Name: "_acme-challenge.example.com", Type: "TXT", Content: "123d==", TTL: 300},
+	}
+
+	err := client.UpdateZone(t.Context(), 1234, records)
+	require.NoError(t, err)
+}
+
+func TestClient_UpdateZone_error(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /dns/zones/1234/update",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusNotFound)).
+		Build(t)
+
+	err := client.UpdateZone(t.Context(), 1234, nil)
+	require.EqualError(t, err, "ObjectDoesNotExist: Zone 1234 does not exist")
+}
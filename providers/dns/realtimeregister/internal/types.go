@@ -0,0 +1,46 @@
+package internal
+
+import "fmt"
+
+// APIError an error returned by the API.
+type APIError struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (a *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", a.Type, a.Message)
+}
+
+// ZonesResponse the response of the zone list.
+type ZonesResponse struct {
+	Entities   []Zone     `json:"entities"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type Zone struct {
+	ID      int      `json:"id,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	Service string   `json:"service,omitempty"`
+	Records []Record `json:"records,omitempty"`
+}
+
+type Record struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	TTL      int    `json:"ttl"`
+	Priority int    `json:"prio,omitempty"`
+}
+
+// ZoneUpdateRequest the records of the zone.
+// The records replace all the existing records of the zone.
+type ZoneUpdateRequest struct {
+	Records []Record `json:"records"`
+}
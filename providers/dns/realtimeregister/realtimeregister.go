@@ -0,0 +1,210 @@
+// Package realtimeregister implements a DNS provider for solving the DNS-01 challenge using Realtime Register.
+package realtimeregister
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/digicert/lego/v4/providers/dns/realtimeregister/internal"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "REALTIMEREGISTER_"
+
+	EnvAPIKey  = envNamespace + "API_KEY"
+	EnvSandbox = envNamespace + "SANDBOX"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// minTTL the minimal TTL allowed by Realtime Register.
+const minTTL = 300
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	APIKey  string
+	Sandbox bool
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Sandbox:            env.GetOrDefaultBool(EnvSandbox, false),
+		TTL:                env.GetOrDefaultInt(EnvTTL, minTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 5*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	// The update of a zone replaces all its records.
+	zoneMu sync.Mutex
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Realtime Register.
+// Credentials must be passed in the environment variable: REALTIMEREGISTER_API_KEY.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvAPIKey)
+	if err != nil {
+		return nil, fmt.Errorf("realtimeregister: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.APIKey = values[EnvAPIKey]
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Realtime Register.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("realtimeregister: the configuration of the DNS provider is nil")
+	}
+
+	if config.TTL < minTTL {
+		return nil, fmt.Errorf("realtimeregister: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
+	}
+
+	client, err := internal.NewClient(config.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("realtimeregister: %w", err)
+	}
+
+	if config.Sandbox {
+		client.BaseURL, _ = url.Parse(internal.SandboxBaseURL)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+
+	return &DNSProvider{config: config, client: client}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.zoneMu.Lock()
+	defer d.zoneMu.Unlock()
+
+	zone, err := d.getZone(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("realtimeregister: %w", err)
+	}
+
+	record := internal.Record{
+		Name:    dns01.UnFqdn(info.EffectiveFQDN),
+		Type:    "TXT",
+		Content: info.Value,
+		TTL:     d.config.TTL,
+	}
+
+	if slices.ContainsFunc(zone.Records, func(r internal.Record) bool { return isSameRecord(r, record) }) {
+		return nil
+	}
+
+	err = d.client.UpdateZone(ctx, zone.ID, append(zone.Records, record))
+	if err != nil {
+		return fmt.Errorf("realtimeregister: update zone: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.zoneMu.Lock()
+	defer d.zoneMu.Unlock()
+
+	zone, err := d.getZone(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("realtimeregister: %w", err)
+	}
+
+	record := internal.Record{
+		Name:    dns01.UnFqdn(info.EffectiveFQDN),
+		Type:    "TXT",
+		Content: info.Value,
+	}
+
+	records := slices.DeleteFunc(slices.Clone(zone.Records), func(r internal.Record) bool { return isSameRecord(r, record) })
+
+	if len(records) == len(zone.Records) {
+		return nil
+	}
+
+	err = d.client.UpdateZone(ctx, zone.ID, records)
+	if err != nil {
+		return fmt.Errorf("realtimeregister: update zone: %w", err)
+	}
+
+	return nil
+}
+
+// getZone gets the zone of the FQDN with its records.
+func (d *DNSProvider) getZone(ctx context.Context, fqdn string) (*internal.Zone, error) {
+	authZone, err := dns01.FindZoneByFqdn(fqdn)
+	if err != nil {
+		return nil, fmt.Errorf("could not find zone for domain %q: %w", fqdn, err)
+	}
+
+	zone, err := d.client.GetZoneByName(ctx, dns01.UnFqdn(authZone))
+	if err != nil {
+		return nil, fmt.Errorf("get zone: %w", err)
+	}
+
+	zone, err = d.client.GetZone(ctx, zone.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get zone records: %w", err)
+	}
+
+	return zone, nil
+}
+
+func isSameRecord(a, b internal.Record) bool {
+	return strings.EqualFold(a.Name, b.Name) && a.Type == b.Type && a.Content == b.Content
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -45,7 +46,10 @@ const (
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout    = (*DNSProvider)(nil)
+	_ challenge.ProviderPropagated = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -83,8 +87,8 @@ func NewDefaultConfig() *Config {
 		WaitForRecordSetsChanged: env.GetOrDefaultBool(EnvWaitForRecordSetsChanged, true),
 
 		TTL:                env.GetOrDefaultInt(EnvTTL, 10),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 4*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 4*time.Second),
 	}
 }
 
@@ -92,6 +96,10 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	client *route53.Client
 	config *Config
+
+	// changeIDs the IDs of the changes of the created records (by token).
+	changeIDs   map[string]*string
+	changeIDsMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for the AWS Route 53 service.
@@ -116,7 +124,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.Client != nil {
-		return &DNSProvider{client: config.Client, config: config}, nil
+		return &DNSProvider{client: config.Client, config: config, changeIDs: make(map[string]*string)}, nil
 	}
 
 	ctx := context.Background()
@@ -127,8 +135,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	return &DNSProvider{
-		client: route53.NewFromConfig(cfg),
-		config: config,
+		client:    route53.NewFromConfig(cfg),
+		config:    config,
+		changeIDs: make(map[string]*string),
 	}, nil
 }
 
@@ -137,6 +146,22 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 	return d.config.PropagationTimeout, d.config.PollingInterval
 }
 
+// IdempotentWrites reports that the calls can be repeated: the record set is upserted.
+func (d *DNSProvider) IdempotentWrites() bool {
+	return true
+}
+
+// PropagationCheck returns the propagation check of the private zones:
+// the private zones are not reachable by the public DNS, the records are read back with the Route 53 API.
+// For the public zones, the propagation is checked with the DNS.
+func (d *DNSProvider) PropagationCheck() dns01.PreCheckFunc {
+	if !d.config.PrivateZone {
+		return nil
+	}
+
+	return d.checkRecord
+}
+
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
@@ -173,19 +198,45 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		ResourceRecords: records,
 	}
 
-	err = d.changeRecord(ctx, awstypes.ChangeActionUpsert, hostedZoneID, recordSet)
+	changeID, err := d.changeRecord(ctx, awstypes.ChangeActionUpsert, hostedZoneID, recordSet)
 	if err != nil {
 		return fmt.Errorf("route53: %w", err)
 	}
 
+	d.changeIDsMu.Lock()
+	d.changeIDs[token] = changeID
+	d.changeIDsMu.Unlock()
+
 	return nil
 }
 
+// Propagated reports the TXT record as propagated when its change is applied to all the Route 53 name servers (`INSYNC`).
+func (d *DNSProvider) Propagated(_, token string) (bool, error) {
+	d.changeIDsMu.Lock()
+	changeID, ok := d.changeIDs[token]
+	d.changeIDsMu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	resp, err := d.client.GetChange(context.Background(), &route53.GetChangeInput{Id: changeID})
+	if err != nil {
+		return false, fmt.Errorf("route53: failed to query change status: %w", err)
+	}
+
+	return resp.ChangeInfo.Status == awstypes.ChangeStatusInsync, nil
+}
+
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	ctx := context.Background()
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
+	d.changeIDsMu.Lock()
+	delete(d.changeIDs, token)
+	d.changeIDsMu.Unlock()
+
 	hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("failed to determine Route 53 hosted zone ID: %w", err)
@@ -224,7 +275,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		recordSet.ResourceRecords = existingRecords
 	}
 
-	err = d.changeRecord(ctx, action, hostedZoneID, recordSet)
+	_, err = d.changeRecord(ctx, action, hostedZoneID, recordSet)
 	if err != nil {
 		return fmt.Errorf("route53: %w", err)
 	}
@@ -232,7 +283,8 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
-func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAction, hostedZoneID string, recordSet *awstypes.ResourceRecordSet) error {
+// changeRecord applies the change of the record set, and returns the ID of the change.
+func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAction, hostedZoneID string, recordSet *awstypes.ResourceRecordSet) (*string, error) {
 	recordSetInput := &route53.ChangeResourceRecordSetsInput{
 		HostedZoneId: aws.String(hostedZoneID),
 		ChangeBatch: &awstypes.ChangeBatch{
@@ -246,13 +298,13 @@ func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAc
 
 	resp, err := d.client.ChangeResourceRecordSets(ctx, recordSetInput)
 	if err != nil {
-		return fmt.Errorf("failed to change record set: %w", err)
+		return nil, fmt.Errorf("failed to change record set: %w", err)
 	}
 
 	changeID := resp.ChangeInfo.Id
 
 	if d.config.WaitForRecordSetsChanged {
-		return wait.Retry(ctx,
+		err = wait.Retry(ctx,
 			func() error {
 				resp, err := d.client.GetChange(ctx, &route53.GetChangeInput{Id: changeID})
 				if err != nil {
@@ -268,9 +320,36 @@ func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAc
 			backoff.WithBackOff(backoff.NewConstantBackOff(d.config.PollingInterval)),
 			backoff.WithMaxElapsedTime(d.config.PropagationTimeout),
 		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return changeID, nil
+}
+
+// checkRecord checks that the TXT record exists in the hosted zone.
+// The record not listed yet is not an error: the check is retried until the propagation timeout.
+func (d *DNSProvider) checkRecord(fqdn, value string) (bool, error) {
+	ctx := context.Background()
+
+	hostedZoneID, err := d.getHostedZoneID(ctx, fqdn)
+	if err != nil {
+		return false, fmt.Errorf("route53: failed to determine hosted zone ID: %w", err)
+	}
+
+	records, err := d.getExistingRecordSets(ctx, hostedZoneID, fqdn)
+	if err != nil {
+		return false, fmt.Errorf("route53: %w", err)
+	}
+
+	for _, record := range records {
+		if ptr.Deref(record.Value) == `"`+value+`"` {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 func (d *DNSProvider) getExistingRecordSets(ctx context.Context, hostedZoneID, fqdn string) ([]awstypes.ResourceRecord, error) {
@@ -305,6 +384,10 @@ func (d *DNSProvider) getHostedZoneID(ctx context.Context, fqdn string) (string,
 		return d.config.HostedZoneID, nil
 	}
 
+	if d.config.PrivateZone {
+		return d.getPrivateHostedZoneID(ctx, fqdn)
+	}
+
 	authZone, err := dns01.FindZoneByFqdn(fqdn)
 	if err != nil {
 		return "", fmt.Errorf("could not find zone for FQDN %q: %w", fqdn, err)
@@ -405,3 +488,26 @@ func createAWSConfigCheckParams(config *Config) error {
 
 	return nil
 }
+
+// getPrivateHostedZoneID finds the private hosted zone of the FQDN with the Route 53 API:
+// the private zones are not reachable by the public DNS (SOA lookup).
+func (d *DNSProvider) getPrivateHostedZoneID(ctx context.Context, fqdn string) (string, error) {
+	for domain := range dns01.DomainsSeq(dns01.ToFqdn(fqdn)) {
+		// .DNSName should not have a trailing dot
+		resp, err := d.client.ListHostedZonesByName(ctx, &route53.ListHostedZonesByNameInput{
+			DNSName: aws.String(dns01.UnFqdn(domain)),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		for _, hostedZone := range resp.HostedZones {
+			// .Name has a trailing dot
+			if ptr.Deref(hostedZone.Name) == domain && hostedZone.Config != nil && hostedZone.Config.PrivateZone {
+				return strings.TrimPrefix(ptr.Deref(hostedZone.Id), "/hostedzone/"), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("private zone not found for domain %s", fqdn)
+}
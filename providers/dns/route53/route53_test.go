@@ -1,8 +1,10 @@
 package route53
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -159,8 +161,9 @@ func TestDNSProvider_Present(t *testing.T) {
 			}
 
 			return &DNSProvider{
-				client: route53.NewFromConfig(cfg),
-				config: NewDefaultConfig(),
+				client:    route53.NewFromConfig(cfg),
+				config:    NewDefaultConfig(),
+				changeIDs: make(map[string]*string),
 			}, nil
 		},
 	).
@@ -186,8 +189,135 @@ func TestDNSProvider_Present(t *testing.T) {
 	domain := "example.com"
 	keyAuth := "123456d=="
 
-	err := provider.Present(domain, "", keyAuth)
+	propagated, err := provider.Propagated(domain, "abc")
+	require.NoError(t, err)
+
+	// The record is unknown.
+	assert.False(t, propagated)
+
+	err = provider.Present(domain, "abc", keyAuth)
+	require.NoError(t, err)
+
+	propagated, err = provider.Propagated(domain, "abc")
+	require.NoError(t, err)
+
+	assert.True(t, propagated)
+}
+
+func mockBuilderPrivateZone() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			cfg := aws.Config{
+				HTTPClient:       server.Client(),
+				Credentials:      credentials.NewStaticCredentialsProvider("abc", "123", " "),
+				Region:           "mock-region",
+				BaseEndpoint:     aws.String(server.URL),
+				RetryMaxAttempts: 1,
+			}
+
+			config := NewDefaultConfig()
+			config.PrivateZone = true
+
+			return &DNSProvider{
+				client: route53.NewFromConfig(cfg),
+				config: config,
+			}, nil
+		},
+	).
+		// The first lookup (_acme-challenge.example.com) doesn't match, the second (example.com) matches.
+		Route("GET /2013-04-01/hostedzonesbyname",
+			servermock.ResponseFromFixture("listHostedZonesByNameResponse_private.xml").
+				WithHeader("Content-Type", "application/xml"))
+}
+
+func TestDNSProvider_PropagationCheck(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	provider := mockBuilderPrivateZone().
+		Route("GET /2013-04-01/hostedzone/PRIVATE1/rrset",
+			servermock.ResponseFromFixture("listResourceRecordSetsResponse.xml").
+				WithHeader("Content-Type", "application/xml"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "_acme-challenge.example.com.").
+				With("type", "TXT")).
+		Build(t)
+
+	check := provider.PropagationCheck()
+	require.NotNil(t, check)
+
+	ok, err := check("_acme-challenge.example.com.", "O2UTPYgIzRNt5N27EVcNKDxv6goSF7ru3zi3chZXKUw")
+	require.NoError(t, err)
+
+	assert.True(t, ok)
+
+	ok, err = check("_acme-challenge.example.com.", "other")
+	require.NoError(t, err)
+
+	assert.False(t, ok)
+}
+
+func TestDNSProvider_PropagationCheck_notListedYet(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	var listCalls atomic.Int32
+
+	provider := mockBuilderPrivateZone().
+		Route("GET /2013-04-01/hostedzone/PRIVATE1/rrset",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The record is not listed by the first response.
+				fixture := "listResourceRecordSetsResponse_empty.xml"
+				if listCalls.Add(1) > 1 {
+					fixture = "listResourceRecordSetsResponse.xml"
+				}
+
+				servermock.ResponseFromFixture(fixture).
+					WithHeader("Content-Type", "application/xml").
+					ServeHTTP(rw, req)
+			})).
+		Build(t)
+
+	check := provider.PropagationCheck()
+	require.NotNil(t, check)
+
+	ok, err := check("_acme-challenge.example.com.", "O2UTPYgIzRNt5N27EVcNKDxv6goSF7ru3zi3chZXKUw")
 	require.NoError(t, err)
+
+	assert.False(t, ok)
+
+	ok, err = check("_acme-challenge.example.com.", "O2UTPYgIzRNt5N27EVcNKDxv6goSF7ru3zi3chZXKUw")
+	require.NoError(t, err)
+
+	assert.True(t, ok)
+}
+
+func TestDNSProvider_PropagationCheck_apiError(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	provider := mockBuilderPrivateZone().
+		Route("GET /2013-04-01/hostedzone/PRIVATE1/rrset",
+			servermock.Noop().
+				WithStatusCode(http.StatusForbidden)).
+		Build(t)
+
+	check := provider.PropagationCheck()
+	require.NotNil(t, check)
+
+	ok, err := check("_acme-challenge.example.com.", "O2UTPYgIzRNt5N27EVcNKDxv6goSF7ru3zi3chZXKUw")
+	require.Error(t, err)
+
+	assert.False(t, ok)
+}
+
+func TestDNSProvider_PropagationCheck_publicZone(t *testing.T) {
+	provider := &DNSProvider{config: &Config{}}
+
+	assert.Nil(t, provider.PropagationCheck())
 }
 
 func Test_createAWSConfig(t *testing.T) {
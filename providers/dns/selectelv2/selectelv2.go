@@ -79,10 +79,10 @@ func NewDefaultConfig() *Config {
 		AuthURL:    env.GetOrDefaultString(EnvAuthURL, defaultAuthURL),
 
 		TTL:                env.GetOrDefaultInt(EnvTTL, defaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, defaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, defaultPollingInterval),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, defaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, defaultPollingInterval),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, defaultHTTPTimeout),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, defaultHTTPTimeout),
 		},
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
@@ -33,21 +34,28 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	ClientID           string
-	ClientSecret       string
-	StackID            string
-	TTL                int
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
+	ClientID           string        `env:"CLIENT_ID,required"`
+	ClientSecret       string        `env:"CLIENT_SECRET,required"`
+	StackID            string        `env:"STACK_ID,required"`
+	TTL                int           `env:"TTL"`
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+
+	// Logger is used to log the messages of the DNS provider (optional: the default logger is used if nil).
+	Logger *slog.Logger
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+	config := &Config{
+		TTL:                dns01.DefaultTTL,
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -60,16 +68,13 @@ type DNSProvider struct {
 // Credentials must be passed in the environment variables:
 // STACKPATH_CLIENT_ID, STACKPATH_CLIENT_SECRET, and STACKPATH_STACK_ID.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvClientID, EnvClientSecret, EnvStackID)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("stackpath: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.ClientID = values[EnvClientID]
-	config.ClientSecret = values[EnvClientSecret]
-	config.StackID = values[EnvStackID]
-
 	return NewDNSProviderConfig(config)
 }
 
@@ -147,7 +152,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	for _, record := range records {
 		err = d.client.DeleteZoneRecord(ctx, zone, record)
 		if err != nil {
-			log.Printf("stackpath: failed to delete TXT record: %v", err)
+			log.OrDefault(d.config.Logger).Warn("stackpath: failed to delete TXT record.", "error", err)
 		}
 	}
 
@@ -0,0 +1,215 @@
+// Package standalone implements a DNS provider which solves the DNS-01 challenge with an embedded authoritative DNS server.
+package standalone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/platform/dnsserver"
+	"github.com/miekg/dns"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "STANDALONE_"
+
+	EnvAddress    = envNamespace + "ADDRESS"
+	EnvNameserver = envNamespace + "NAMESERVER"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+const defaultAddress = ":53"
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// Address the address (interface:port or :port) of the DNS server (UDP and TCP).
+	Address string
+	// Nameserver the name of the host running lego, used in the NS and SOA records.
+	Nameserver string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Address:            env.GetOrDefaultString(EnvAddress, defaultAddress),
+		Nameserver:         env.GetOrDefaultString(EnvNameserver, ""),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+// The DNS server is started by the first call to Present,
+// and stopped when the last record is removed by CleanUp.
+type DNSProvider struct {
+	config *Config
+
+	records dnsserver.Records
+
+	mu     sync.Mutex
+	server *dnsserver.Server
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for the embedded DNS server.
+func NewDNSProvider() (*DNSProvider, error) {
+	return NewDNSProviderConfig(NewDefaultConfig())
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for the embedded DNS server.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("standalone: the configuration of the DNS provider is nil")
+	}
+
+	if config.Address == "" {
+		return nil, errors.New("standalone: address missing")
+	}
+
+	if !strings.Contains(config.Address, ":") {
+		return nil, fmt.Errorf("standalone: the address only accepts interface:port or :port: %q", config.Address)
+	}
+
+	return &DNSProvider{config: config}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present serves a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.server == nil {
+		server, err := dnsserver.Start(d.config.Address, d)
+		if err != nil {
+			return fmt.Errorf("standalone: %w", err)
+		}
+
+		d.server = server
+	}
+
+	d.records.Add(info.EffectiveFQDN, info.Value)
+
+	return nil
+}
+
+// CleanUp stops serving the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.records.Remove(info.EffectiveFQDN, info.Value)
+
+	if d.records.Len() > 0 || d.server == nil {
+		return nil
+	}
+
+	err := d.stop()
+	if err != nil {
+		return fmt.Errorf("standalone: %w", err)
+	}
+
+	return nil
+}
+
+// ServeDNS answers the queries about the challenge records.
+func (d *DNSProvider) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+
+	if len(req.Question) != 1 || req.Question[0].Qclass != dns.ClassINET {
+		_ = w.WriteMsg(m.SetRcode(req, dns.RcodeRefused))
+		return
+	}
+
+	question := req.Question[0]
+
+	values, ok := d.records.Get(question.Name)
+	if !ok {
+		_ = w.WriteMsg(m.SetRcode(req, dns.RcodeRefused))
+		return
+	}
+
+	m.SetReply(req)
+	m.Authoritative = true
+
+	switch question.Qtype {
+	case dns.TypeTXT:
+		for _, value := range values {
+			m.Answer = append(m.Answer, &dns.TXT{Hdr: d.header(question.Name, dns.TypeTXT), Txt: []string{value}})
+		}
+
+	case dns.TypeSOA:
+		m.Answer = append(m.Answer, d.soa(question.Name))
+
+	case dns.TypeNS:
+		if d.config.Nameserver == "" {
+			m.Ns = append(m.Ns, d.soa(question.Name))
+			break
+		}
+
+		m.Answer = append(m.Answer, &dns.NS{Hdr: d.header(question.Name, dns.TypeNS), Ns: dns.Fqdn(d.config.Nameserver)})
+
+	default:
+		// NODATA
+		m.Ns = append(m.Ns, d.soa(question.Name))
+	}
+
+	_ = w.WriteMsg(m)
+}
+
+func (d *DNSProvider) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := d.server
+	d.server = nil
+
+	return server.Shutdown(ctx)
+}
+
+func (d *DNSProvider) header(name string, rrType uint16) dns.RR_Header {
+	return dns.RR_Header{Name: name, Rrtype: rrType, Class: dns.ClassINET, Ttl: uint32(d.config.TTL)}
+}
+
+func (d *DNSProvider) soa(name string) *dns.SOA {
+	mname := name
+	if d.config.Nameserver != "" {
+		mname = dns.Fqdn(d.config.Nameserver)
+	}
+
+	return &dns.SOA{
+		Hdr:     d.header(name, dns.TypeSOA),
+		Ns:      mname,
+		Mbox:    "hostmaster." + name,
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  uint32(d.config.TTL),
+	}
+}
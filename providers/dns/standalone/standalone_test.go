@@ -0,0 +1,240 @@
+package standalone
+
+import (
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(EnvAddress, EnvNameserver)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc:    "success",
+			envVars: map[string]string{},
+		},
+		{
+			desc: "success with a custom address",
+			envVars: map[string]string{
+				EnvAddress: "127.0.0.1:5353",
+			},
+		},
+		{
+			desc: "invalid address",
+			envVars: map[string]string{
+				EnvAddress: "5353",
+			},
+			expected: `standalone: the address only accepts interface:port or :port: "5353"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		address  string
+		expected string
+	}{
+		{
+			desc:    "success",
+			address: ":53",
+		},
+		{
+			desc:     "missing address",
+			expected: "standalone: address missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Address = test.address
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	provider := setupProvider(t)
+
+	err := provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	err = provider.Present("example.com", "", "456d==")
+	require.NoError(t, err)
+
+	for _, network := range []string{"udp", "tcp"} {
+		t.Run(network, func(t *testing.T) {
+			resp := query(t, provider, network, "_ACME-challenge.example.com.", dns.TypeTXT)
+
+			assert.Equal(t, dns.RcodeSuccess, resp.Rcode)
+			assert.True(t, resp.Authoritative)
+
+			require.Len(t, resp.Answer, 2)
+
+			assert.Equal(t, []string{"123d=="}, resp.Answer[0].(*dns.TXT).Txt)
+			assert.Equal(t, []string{"456d=="}, resp.Answer[1].(*dns.TXT).Txt)
+			assert.EqualValues(t, 120, resp.Answer[0].Header().Ttl)
+		})
+	}
+}
+
+func TestDNSProvider_ServeDNS_SOA(t *testing.T) {
+	provider := setupProvider(t)
+
+	err := provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	resp := query(t, provider, "udp", "_acme-challenge.example.com.", dns.TypeSOA)
+
+	assert.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	require.Len(t, resp.Answer, 1)
+
+	soa, ok := resp.Answer[0].(*dns.SOA)
+	require.True(t, ok)
+
+	assert.Equal(t, "ns.example.org.", soa.Ns)
+	assert.Equal(t, "_acme-challenge.example.com.", soa.Hdr.Name)
+}
+
+func TestDNSProvider_ServeDNS_NS(t *testing.T) {
+	provider := setupProvider(t)
+
+	err := provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	resp := query(t, provider, "udp", "_acme-challenge.example.com.", dns.TypeNS)
+
+	require.Len(t, resp.Answer, 1)
+
+	assert.Equal(t, "ns.example.org.", resp.Answer[0].(*dns.NS).Ns)
+}
+
+func TestDNSProvider_ServeDNS_noData(t *testing.T) {
+	provider := setupProvider(t)
+
+	err := provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	resp := query(t, provider, "udp", "_acme-challenge.example.com.", dns.TypeA)
+
+	assert.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	assert.Empty(t, resp.Answer)
+	require.Len(t, resp.Ns, 1)
+	assert.IsType(t, &dns.SOA{}, resp.Ns[0])
+}
+
+func TestDNSProvider_ServeDNS_unknownName(t *testing.T) {
+	provider := setupProvider(t)
+
+	err := provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	resp := query(t, provider, "udp", "example.com.", dns.TypeTXT)
+
+	assert.Equal(t, dns.RcodeRefused, resp.Rcode)
+	assert.False(t, resp.Authoritative)
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	provider := setupProvider(t)
+
+	err := provider.Present("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	err = provider.Present("example.com", "", "456d==")
+	require.NoError(t, err)
+
+	err = provider.CleanUp("example.com", "", "123d==")
+	require.NoError(t, err)
+
+	resp := query(t, provider, "udp", "_acme-challenge.example.com.", dns.TypeTXT)
+
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, []string{"456d=="}, resp.Answer[0].(*dns.TXT).Txt)
+
+	err = provider.CleanUp("example.com", "", "456d==")
+	require.NoError(t, err)
+
+	// The server is stopped when there are no more records.
+	assert.Nil(t, provider.server)
+	assert.Zero(t, provider.records.Len())
+}
+
+func setupProvider(t *testing.T) *DNSProvider {
+	t.Helper()
+
+	config := NewDefaultConfig()
+	config.Address = "127.0.0.1:0"
+	config.Nameserver = "ns.example.org"
+	config.TTL = 120
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+
+		if provider.server != nil {
+			_ = provider.stop()
+		}
+	})
+
+	return provider
+}
+
+func query(t *testing.T, provider *DNSProvider, network, name string, qType uint16) *dns.Msg {
+	t.Helper()
+
+	provider.mu.Lock()
+	addr := provider.server.Addr().String()
+	provider.mu.Unlock()
+
+	m := new(dns.Msg)
+	m.SetQuestion(name, qType)
+
+	client := &dns.Client{Net: network}
+
+	resp, _, err := client.Exchange(m, addr)
+	require.NoError(t, err)
+
+	return resp
+}
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/digicert/lego/v4/challenge"
 	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/miekg/dns"
 	"github.com/transip/gotransip/v6"
+	"github.com/transip/gotransip/v6/authenticator"
 	transipdomain "github.com/transip/gotransip/v6/domain"
 )
 
@@ -20,6 +24,10 @@ const (
 
 	EnvAccountName    = envNamespace + "ACCOUNT_NAME"
 	EnvPrivateKeyPath = envNamespace + "PRIVATE_KEY_PATH"
+	EnvPrivateKey     = envNamespace + "PRIVATE_KEY"
+
+	EnvTokenCachePath  = envNamespace + "TOKEN_CACHE_PATH"
+	EnvTokenExpiration = envNamespace + "TOKEN_EXPIRATION"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
@@ -31,8 +39,15 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	AccountName        string
-	PrivateKeyPath     string
+	AccountName    string
+	PrivateKeyPath string
+	// PrivateKey the content of the private key, takes precedence over PrivateKeyPath.
+	PrivateKey string
+
+	// TokenCachePath the path of the file used to cache the access tokens between runs.
+	TokenCachePath  string
+	TokenExpiration time.Duration
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int64
@@ -42,11 +57,13 @@ type Config struct {
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		TokenCachePath:     env.GetOrFile(EnvTokenCachePath),
+		TokenExpiration:    env.GetOrDefaultDuration(EnvTokenExpiration, 0),
 		TTL:                int64(env.GetOrDefaultInt(EnvTTL, 10)),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 10*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 10*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
 		HTTPClient: &http.Client{
-			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 30*time.Second),
 		},
 	}
 }
@@ -55,18 +72,28 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config     *Config
 	repository transipdomain.Repository
+
+	domains   []string
+	domainsMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for TransIP.
 // Credentials must be passed in the environment variables:
-// TRANSIP_ACCOUNTNAME, TRANSIP_PRIVATEKEYPATH.
+// TRANSIP_ACCOUNT_NAME, and TRANSIP_PRIVATE_KEY_PATH or TRANSIP_PRIVATE_KEY.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvAccountName, EnvPrivateKeyPath)
+	config := NewDefaultConfig()
+	config.PrivateKey = env.GetOrFile(EnvPrivateKey)
+
+	names := []string{EnvAccountName}
+	if config.PrivateKey == "" {
+		names = append(names, EnvPrivateKeyPath)
+	}
+
+	values, err := env.Get(names...)
 	if err != nil {
 		return nil, fmt.Errorf("transip: %w", err)
 	}
 
-	config := NewDefaultConfig()
 	config.AccountName = values[EnvAccountName]
 	config.PrivateKeyPath = values[EnvPrivateKeyPath]
 
@@ -80,8 +107,23 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	cfg := gotransip.ClientConfiguration{
-		AccountName:    config.AccountName,
-		PrivateKeyPath: config.PrivateKeyPath,
+		AccountName:     config.AccountName,
+		PrivateKeyPath:  config.PrivateKeyPath,
+		TokenExpiration: config.TokenExpiration,
+	}
+
+	if config.PrivateKey != "" {
+		cfg.PrivateKeyPath = ""
+		cfg.PrivateKeyReader = strings.NewReader(config.PrivateKey)
+	}
+
+	if config.TokenCachePath != "" {
+		cache, err := authenticator.NewFileTokenCache(config.TokenCachePath)
+		if err != nil {
+			return nil, fmt.Errorf("transip: token cache: %w", err)
+		}
+
+		cfg.TokenCache = cache
 	}
 
 	if config.HTTPClient != nil {
@@ -111,7 +153,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("transip: could not find zone for domain %q: %w", domain, err)
 	}
@@ -143,7 +185,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZone(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("transip: could not find zone for domain %q: %w", domain, err)
 	}
@@ -175,3 +217,48 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+// findZone returns the most specific domain of the account matching the FQDN,
+// so an account hosting both example.com and sub.example.com uses the right zone.
+// It falls back to the SOA lookup when no domain of the account matches.
+func (d *DNSProvider) findZone(fqdn string) (string, error) {
+	domains, err := d.getDomains()
+	if err != nil {
+		return "", err
+	}
+
+	for name := range dns01.UnFqdnDomainsSeq(fqdn) {
+		for _, domain := range domains {
+			if strings.EqualFold(name, domain) {
+				return dns.Fqdn(domain), nil
+			}
+		}
+	}
+
+	return dns01.FindZoneByFqdn(fqdn)
+}
+
+// getDomains returns the names of the domains of the account.
+// The list is fetched once and cached for the lifetime of the provider.
+func (d *DNSProvider) getDomains() ([]string, error) {
+	d.domainsMu.Lock()
+	defer d.domainsMu.Unlock()
+
+	if d.domains != nil {
+		return d.domains, nil
+	}
+
+	all, err := d.repository.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("get domains: %w", err)
+	}
+
+	domains := make([]string, 0, len(all))
+	for _, domain := range all {
+		domains = append(domains, domain.Name)
+	}
+
+	d.domains = domains
+
+	return domains, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
@@ -15,6 +16,7 @@ type Client struct {
 	openstack     *gophercloud.ProviderClient
 	authOpts      gophercloud.AuthOptions
 	authenticated bool
+	authMu        sync.Mutex
 	baseURL       *url.URL
 }
 
@@ -103,6 +105,9 @@ func (c *Client) request(method string, endpoint *url.URL, options *gophercloud.
 }
 
 func (c *Client) lazyAuth() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
 	if c.authenticated {
 		return nil
 	}
@@ -62,9 +62,9 @@ func NewDefaultConfig() *Config {
 		Region: env.GetOrDefaultString(EnvRegion, volc.DefaultRegion),
 
 		TTL:                env.GetOrDefaultInt(EnvTTL, defaultTTL),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 4*time.Minute),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 10*time.Second),
-		HTTPTimeout:        env.GetOrDefaultSecond(EnvHTTPTimeout, volc.Timeout*time.Second),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, 4*time.Minute),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, 10*time.Second),
+		HTTPTimeout:        env.GetOrDefaultDuration(EnvHTTPTimeout, volc.Timeout*time.Second),
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/digicert/lego/v4/challenge/dns01"
 	"github.com/digicert/lego/v4/platform/tester"
 	"github.com/digicert/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ import (
 
 const envDomain = envNamespace + "TEST_DOMAIN"
 
-var envTest = tester.NewEnvTest(EnvAPIKey).
+var envTest = tester.NewEnvTest(EnvAPIKey, EnvHTTPTimeout).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -64,6 +65,48 @@ func TestNewDNSProvider(t *testing.T) {
 	}
 }
 
+func TestNewDNSProvider_defaults(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected time.Duration
+	}{
+		{
+			desc: "default HTTP timeout",
+			envVars: map[string]string{
+				EnvAPIKey: "123",
+			},
+			expected: 30 * time.Second,
+		},
+		{
+			desc: "HTTP timeout",
+			envVars: map[string]string{
+				EnvAPIKey:      "123",
+				EnvHTTPTimeout: "10s",
+			},
+			expected: 10 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, p.config.HTTPTimeout)
+			assert.Equal(t, dns01.DefaultTTL, p.config.TTL)
+			assert.Equal(t, dns01.DefaultPropagationTimeout, p.config.PropagationTimeout)
+			assert.Equal(t, dns01.DefaultPollingInterval, p.config.PollingInterval)
+		})
+	}
+}
+
 func TestNewDNSProviderConfig(t *testing.T) {
 	testCases := []struct {
 		desc     string
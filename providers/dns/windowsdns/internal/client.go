@@ -0,0 +1,281 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/digicert/lego/v4/providers/dns/internal/errutils"
+	"github.com/digicert/lego/v4/providers/dns/internal/useragent"
+	"github.com/google/uuid"
+)
+
+const (
+	maxEnvelopeSize  = "153600"
+	operationTimeout = "PT60S"
+)
+
+// Client a minimal WinRM (WS-Management) client, able to run PowerShell scripts on a remote host.
+// https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-wsmv/
+type Client struct {
+	endpoint string
+	username string
+	password string
+
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+// The endpoint is the URL of the WinRM service (ex: https://dns.example.com:5986/wsman).
+func NewClient(endpoint, username, password string) (*Client, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint missing")
+	}
+
+	if username == "" || password == "" {
+		return nil, errors.New("credentials missing")
+	}
+
+	return &Client{
+		endpoint:   endpoint,
+		username:   username,
+		password:   password,
+		HTTPClient: &http.Client{Timeout: 90 * time.Second},
+	}, nil
+}
+
+// RunPowerShell runs a PowerShell script on the remote host.
+// An error is returned if the script exits with a non-zero code.
+func (c *Client) RunPowerShell(ctx context.Context, script string) (string, error) {
+	result, err := c.Run(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-EncodedCommand", encodePowerShell(script))
+	if err != nil {
+		return "", err
+	}
+
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("PowerShell exit code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+
+	return result.Stdout, nil
+}
+
+// Run runs a command inside a new remote shell.
+func (c *Client) Run(ctx context.Context, command string, args ...string) (*CommandResult, error) {
+	shellID, err := c.createShell(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create shell: %w", err)
+	}
+
+	defer func() { _ = c.deleteShell(context.WithoutCancel(ctx), shellID) }()
+
+	commandID, err := c.command(ctx, shellID, command, args)
+	if err != nil {
+		return nil, fmt.Errorf("run command: %w", err)
+	}
+
+	result, err := c.receive(ctx, shellID, commandID)
+	if err != nil {
+		return nil, fmt.Errorf("receive output: %w", err)
+	}
+
+	return result, nil
+}
+
+func (c *Client) createShell(ctx context.Context) (string, error) {
+	envelope := c.newEnvelope(actionCreate, "")
+	envelope.Header.OptionSet = &OptionSet{Options: []Option{
+		{Name: "WINRS_NOPROFILE", Value: "TRUE"},
+		{Name: "WINRS_CODEPAGE", Value: "65001"},
+	}}
+	envelope.Body.Shell = &Shell{InputStreams: "stdin", OutputStreams: "stdout stderr"}
+
+	response, err := c.do(ctx, envelope)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Body.Shell == nil || response.Body.Shell.ShellID == "" {
+		return "", errors.New("missing shell ID")
+	}
+
+	return response.Body.Shell.ShellID, nil
+}
+
+func (c *Client) command(ctx context.Context, shellID, command string, args []string) (string, error) {
+	envelope := c.newEnvelope(actionCommand, shellID)
+	envelope.Header.OptionSet = &OptionSet{Options: []Option{
+		{Name: "WINRS_CONSOLEMODE_STDIN", Value: "TRUE"},
+		{Name: "WINRS_SKIP_CMD_SHELL", Value: "FALSE"},
+	}}
+	envelope.Body.CommandLine = &CommandLine{Command: command, Arguments: strings.Join(args, " ")}
+
+	response, err := c.do(ctx, envelope)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Body.CommandResponse == nil || response.Body.CommandResponse.CommandID == "" {
+		return "", errors.New("missing command ID")
+	}
+
+	return response.Body.CommandResponse.CommandID, nil
+}
+
+func (c *Client) receive(ctx context.Context, shellID, commandID string) (*CommandResult, error) {
+	var stdout, stderr bytes.Buffer
+
+	for {
+		envelope := c.newEnvelope(actionReceive, shellID)
+		envelope.Body.Receive = &Receive{DesiredStream: DesiredStream{CommandID: commandID, Value: "stdout stderr"}}
+
+		response, err := c.do(ctx, envelope)
+		if err != nil {
+			var fault *Fault
+			if errors.As(err, &fault) && fault.isTimeout() {
+				// No output during the operation timeout, the command is still running.
+				continue
+			}
+
+			return nil, err
+		}
+
+		if response.Body.ReceiveResponse == nil {
+			return nil, errors.New("missing receive response")
+		}
+
+		for _, stream := range response.Body.ReceiveResponse.Streams {
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stream.Value))
+			if err != nil {
+				return nil, fmt.Errorf("decode stream %s: %w", stream.Name, err)
+			}
+
+			switch stream.Name {
+			case "stdout":
+				stdout.Write(data)
+			case "stderr":
+				stderr.Write(data)
+			}
+		}
+
+		state := response.Body.ReceiveResponse.CommandState
+		if state.State == commandStateDone {
+			return &CommandResult{
+				Stdout:   stdout.String(),
+				Stderr:   stderr.String(),
+				ExitCode: state.ExitCode,
+			}, nil
+		}
+	}
+}
+
+func (c *Client) deleteShell(ctx context.Context, shellID string) error {
+	_, err := c.do(ctx, c.newEnvelope(actionDelete, shellID))
+
+	return err
+}
+
+func (c *Client) newEnvelope(action, shellID string) *Envelope {
+	envelope := &Envelope{
+		NsS:   nsSOAP,
+		NsA:   nsAddressing,
+		NsW:   nsWSMan,
+		NsRsp: nsShell,
+		Header: Header{
+			To:               c.endpoint,
+			ReplyTo:          ReplyTo{Address: MustUnderstand{MustUnderstand: true, Value: addressAnonymous}},
+			ResourceURI:      MustUnderstand{MustUnderstand: true, Value: resourceURICmd},
+			Action:           MustUnderstand{MustUnderstand: true, Value: action},
+			MaxEnvelopeSize:  MustUnderstand{MustUnderstand: true, Value: maxEnvelopeSize},
+			MessageID:        "uuid:" + uuid.NewString(),
+			Locale:           Locale{Lang: "en-US"},
+			OperationTimeout: operationTimeout,
+		},
+	}
+
+	if shellID != "" {
+		envelope.Header.SelectorSet = &SelectorSet{Selectors: []Selector{{Name: "ShellId", Value: shellID}}}
+	}
+
+	return envelope
+}
+
+func (c *Client) do(ctx context.Context, envelope *Envelope) (*ResponseEnvelope, error) {
+	body := new(bytes.Buffer)
+	body.WriteString(xml.Header)
+
+	err := xml.NewEncoder(body).Encode(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request XML body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	useragent.SetHeader(req.Header)
+
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errutils.NewHTTPDoError(req, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errutils.NewReadResponseError(req, resp.StatusCode, err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return nil, parseError(req, resp.StatusCode, raw)
+	}
+
+	var response ResponseEnvelope
+
+	err = xml.Unmarshal(raw, &response)
+	if err != nil {
+		return nil, errutils.NewUnmarshalError(req, resp.StatusCode, raw, err)
+	}
+
+	if response.Body.Fault != nil {
+		return nil, response.Body.Fault
+	}
+
+	return &response, nil
+}
+
+func parseError(req *http.Request, statusCode int, raw []byte) error {
+	var response ResponseEnvelope
+
+	err := xml.Unmarshal(raw, &response)
+	if err != nil || response.Body.Fault == nil {
+		return errutils.NewUnexpectedStatusCodeError(req, statusCode, raw)
+	}
+
+	return response.Body.Fault
+}
+
+// encodePowerShell encodes a script for the PowerShell "-EncodedCommand" argument (base64 of the UTF-16LE script).
+func encodePowerShell(script string) string {
+	codes := utf16.Encode([]rune(script))
+
+	data := make([]byte, 0, len(codes)*2)
+	for _, code := range codes {
+		data = binary.LittleEndian.AppendUint16(data, code)
+	}
+
+	return base64.StdEncoding.EncodeToString(data)
+}
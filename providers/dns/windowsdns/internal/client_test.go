@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient(server.URL+"/wsman", "user", "secret")
+			if err != nil {
+				return nil, err
+			}
+
+			client.HTTPClient = server.Client()
+
+			return client, nil
+		},
+		servermock.CheckHeader().
+			WithContentType("application/soap+xml;charset=UTF-8").
+			WithBasicAuth("user", "secret"),
+	)
+}
+
+func TestClient_RunPowerShell(t *testing.T) {
+	handler := newWSManHandler(map[string][]http.Handler{
+		actionCreate:  {servermock.ResponseFromFixture("create_shell.xml")},
+		actionCommand: {servermock.ResponseFromFixture("command.xml")},
+		actionReceive: {servermock.ResponseFromFixture("receive.xml")},
+		actionDelete:  {servermock.ResponseFromFixture("delete_shell.xml")},
+	})
+
+	client := mockBuilder().
+		Route("POST /wsman", handler).
+		Build(t)
+
+	output, err := client.RunPowerShell(t.Context(), "Write-Output 'example.com'")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com\r\n", output)
+
+	assert.Equal(t, []string{actionCreate, actionCommand, actionReceive, actionDelete}, handler.Actions())
+	assert.Equal(t, []string{"Write-Output 'example.com'"}, handler.Scripts())
+}
+
+func TestClient_RunPowerShell_receiveTimeout(t *testing.T) {
+	handler := newWSManHandler(map[string][]http.Handler{
+		actionCreate:  {servermock.ResponseFromFixture("create_shell.xml")},
+		actionCommand: {servermock.ResponseFromFixture("command.xml")},
+		actionReceive: {
+			servermock.ResponseFromFixture("fault_timeout.xml").WithStatusCode(http.StatusInternalServerError),
+			servermock.ResponseFromFixture("receive.xml"),
+		},
+		actionDelete: {servermock.ResponseFromFixture("delete_shell.xml")},
+	})
+
+	client := mockBuilder().
+		Route("POST /wsman", handler).
+		Build(t)
+
+	output, err := client.RunPowerShell(t.Context(), "Write-Output 'example.com'")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com\r\n", output)
+
+	assert.Equal(t, []string{actionCreate, actionCommand, actionReceive, actionReceive, actionDelete}, handler.Actions())
+}
+
+func TestClient_RunPowerShell_exitCode(t *testing.T) {
+	handler := newWSManHandler(map[string][]http.Handler{
+		actionCreate:  {servermock.ResponseFromFixture("create_shell.xml")},
+		actionCommand: {servermock.ResponseFromFixture("command.xml")},
+		actionReceive: {servermock.ResponseFromFixture("receive_error.xml")},
+		actionDelete:  {servermock.ResponseFromFixture("delete_shell.xml")},
+	})
+
+	client := mockBuilder().
+		Route("POST /wsman", handler).
+		Build(t)
+
+	_, err := client.RunPowerShell(t.Context(), "Get-DnsServerZone -Name 'example.com'")
+	require.EqualError(t, err, "PowerShell exit code 1: Failed to find zone example.com on server DC01.")
+
+	// The shell is always deleted.
+	assert.Equal(t, []string{actionCreate, actionCommand, actionReceive, actionDelete}, handler.Actions())
+}
+
+func TestClient_RunPowerShell_fault(t *testing.T) {
+	handler := newWSManHandler(map[string][]http.Handler{
+		actionCreate: {servermock.ResponseFromFixture("fault.xml").WithStatusCode(http.StatusInternalServerError)},
+	})
+
+	client := mockBuilder().
+		Route("POST /wsman", handler).
+		Build(t)
+
+	_, err := client.RunPowerShell(t.Context(), "Write-Output 'example.com'")
+	require.EqualError(t, err, "create shell: WS-Management fault 5: Access is denied.")
+}
+
+func Test_encodePowerShell(t *testing.T) {
+	encoded := encodePowerShell("Write-Output 'é'")
+
+	assert.Equal(t, "VwByAGkAdABlAC0ATwB1AHQAcAB1AHQAIAAnAOkAJwA=", encoded)
+	assert.Equal(t, "Write-Output 'é'", decodePowerShell(encoded))
+}
+
+// wsManHandler dispatches the WS-Management requests by action.
+// The responses of an action are used in order, the last one is reused.
+type wsManHandler struct {
+	mu        sync.Mutex
+	responses map[string][]http.Handler
+	actions   []string
+	scripts   []string
+}
+
+func newWSManHandler(responses map[string][]http.Handler) *wsManHandler {
+	return &wsManHandler{responses: responses}
+}
+
+func (h *wsManHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	raw, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var envelope struct {
+		Action    string `xml:"Header>Action"`
+		Arguments string `xml:"Body>CommandLine>Arguments"`
+	}
+
+	err = xml.Unmarshal(raw, &envelope)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+
+	h.actions = append(h.actions, envelope.Action)
+
+	if envelope.Arguments != "" {
+		fields := strings.Fields(envelope.Arguments)
+		h.scripts = append(h.scripts, fields[len(fields)-1])
+	}
+
+	handlers := h.responses[envelope.Action]
+
+	var handler http.Handler
+
+	if len(handlers) > 0 {
+		handler = handlers[0]
+
+		if len(handlers) > 1 {
+			h.responses[envelope.Action] = handlers[1:]
+		}
+	}
+
+	h.mu.Unlock()
+
+	if handler == nil {
+		http.Error(rw, "unexpected action: "+envelope.Action, http.StatusBadRequest)
+		return
+	}
+
+	handler.ServeHTTP(rw, req)
+}
+
+func (h *wsManHandler) Actions() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.actions
+}
+
+func (h *wsManHandler) Scripts() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var scripts []string
+
+	for _, s := range h.scripts {
+		scripts = append(scripts, decodePowerShell(s))
+	}
+
+	return scripts
+}
+
+func decodePowerShell(encoded string) string {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+
+	codes := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		codes = append(codes, binary.LittleEndian.Uint16(data[i:]))
+	}
+
+	return string(utf16.Decode(codes))
+}
@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// WS-Management namespaces.
+const (
+	nsSOAP       = "http://www.w3.org/2003/05/soap-envelope"
+	nsAddressing = "http://schemas.xmlsoap.org/ws/2004/08/addressing"
+	nsWSMan      = "http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd"
+	nsShell      = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell"
+)
+
+// WS-Management actions.
+const (
+	actionCreate  = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Create"
+	actionDelete  = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Delete"
+	actionCommand = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Command"
+	actionReceive = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Receive"
+)
+
+const (
+	resourceURICmd   = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/cmd"
+	addressAnonymous = "http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous"
+	commandStateDone = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/Done"
+)
+
+// faultCodeTimedOut is the WS-Management fault code returned when a Receive operation times out without any output.
+const faultCodeTimedOut = "2150858793"
+
+// Request.
+
+type Envelope struct {
+	XMLName xml.Name `xml:"s:Envelope"`
+
+	NsS   string `xml:"xmlns:s,attr"`
+	NsA   string `xml:"xmlns:a,attr"`
+	NsW   string `xml:"xmlns:w,attr"`
+	NsRsp string `xml:"xmlns:rsp,attr"`
+
+	Header Header `xml:"s:Header"`
+	Body   Body   `xml:"s:Body"`
+}
+
+type Header struct {
+	To               string         `xml:"a:To"`
+	ReplyTo          ReplyTo        `xml:"a:ReplyTo"`
+	ResourceURI      MustUnderstand `xml:"w:ResourceURI"`
+	Action           MustUnderstand `xml:"a:Action"`
+	MaxEnvelopeSize  MustUnderstand `xml:"w:MaxEnvelopeSize"`
+	MessageID        string         `xml:"a:MessageID"`
+	Locale           Locale         `xml:"w:Locale"`
+	OperationTimeout string         `xml:"w:OperationTimeout"`
+	SelectorSet      *SelectorSet   `xml:"w:SelectorSet,omitempty"`
+	OptionSet        *OptionSet     `xml:"w:OptionSet,omitempty"`
+}
+
+type ReplyTo struct {
+	Address MustUnderstand `xml:"a:Address"`
+}
+
+type MustUnderstand struct {
+	MustUnderstand bool   `xml:"s:mustUnderstand,attr"`
+	Value          string `xml:",chardata"`
+}
+
+type Locale struct {
+	MustUnderstand bool   `xml:"s:mustUnderstand,attr"`
+	Lang           string `xml:"xml:lang,attr"`
+}
+
+type SelectorSet struct {
+	Selectors []Selector `xml:"w:Selector"`
+}
+
+type Selector struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type OptionSet struct {
+	Options []Option `xml:"w:Option"`
+}
+
+type Option struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Body struct {
+	Shell       *Shell       `xml:"rsp:Shell,omitempty"`
+	CommandLine *CommandLine `xml:"rsp:CommandLine,omitempty"`
+	Receive     *Receive     `xml:"rsp:Receive,omitempty"`
+}
+
+type Shell struct {
+	InputStreams  string `xml:"rsp:InputStreams"`
+	OutputStreams string `xml:"rsp:OutputStreams"`
+}
+
+type CommandLine struct {
+	Command   string `xml:"rsp:Command"`
+	Arguments string `xml:"rsp:Arguments,omitempty"`
+}
+
+type Receive struct {
+	DesiredStream DesiredStream `xml:"rsp:DesiredStream"`
+}
+
+type DesiredStream struct {
+	CommandID string `xml:"CommandId,attr"`
+	Value     string `xml:",chardata"`
+}
+
+// Response.
+
+type ResponseEnvelope struct {
+	Body ResponseBody `xml:"Body"`
+}
+
+type ResponseBody struct {
+	Shell           *ShellResponse   `xml:"Shell"`
+	CommandResponse *CommandResponse `xml:"CommandResponse"`
+	ReceiveResponse *ReceiveResponse `xml:"ReceiveResponse"`
+	Fault           *Fault           `xml:"Fault"`
+}
+
+type ShellResponse struct {
+	ShellID string `xml:"ShellId"`
+}
+
+type CommandResponse struct {
+	CommandID string `xml:"CommandId"`
+}
+
+type ReceiveResponse struct {
+	Streams      []Stream     `xml:"Stream"`
+	CommandState CommandState `xml:"CommandState"`
+}
+
+type Stream struct {
+	Name      string `xml:"Name,attr"`
+	CommandID string `xml:"CommandId,attr"`
+	End       bool   `xml:"End,attr"`
+	Value     string `xml:",chardata"`
+}
+
+type CommandState struct {
+	CommandID string `xml:"CommandId,attr"`
+	State     string `xml:"State,attr"`
+	ExitCode  int    `xml:"ExitCode"`
+}
+
+// Fault is a SOAP fault returned by the WS-Management service.
+type Fault struct {
+	Code struct {
+		Value   string `xml:"Value"`
+		Subcode struct {
+			Value string `xml:"Value"`
+		} `xml:"Subcode"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+	Detail struct {
+		WSManFault struct {
+			Code    string `xml:"Code,attr"`
+			Message string `xml:"Message"`
+		} `xml:"WSManFault"`
+	} `xml:"Detail"`
+}
+
+func (f *Fault) Error() string {
+	msg := strings.TrimSpace(f.Detail.WSManFault.Message)
+	if msg == "" {
+		msg = strings.TrimSpace(f.Reason.Text)
+	}
+
+	if f.Detail.WSManFault.Code != "" {
+		return fmt.Sprintf("WS-Management fault %s: %s", f.Detail.WSManFault.Code, msg)
+	}
+
+	return fmt.Sprintf("WS-Management fault %s: %s", f.Code.Subcode.Value, msg)
+}
+
+func (f *Fault) isTimeout() bool {
+	return f.Detail.WSManFault.Code == faultCodeTimedOut || strings.HasSuffix(f.Code.Subcode.Value, ":TimedOut")
+}
+
+// CommandResult is the result of a remote command.
+type CommandResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
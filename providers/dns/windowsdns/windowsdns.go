@@ -0,0 +1,256 @@
+// Package windowsdns implements a DNS provider for solving the DNS-01 challenge using Microsoft Windows DNS Server.
+package windowsdns
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digicert/lego/v4/challenge"
+	"github.com/digicert/lego/v4/challenge/dns01"
+	"github.com/digicert/lego/v4/platform/config/env"
+	"github.com/digicert/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/digicert/lego/v4/providers/dns/windowsdns/internal"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "WINDOWSDNS_"
+
+	EnvEndpoint = envNamespace + "ENDPOINT"
+	EnvUsername = envNamespace + "USERNAME"
+	EnvPassword = envNamespace + "PASSWORD"
+
+	EnvServer             = envNamespace + "SERVER"
+	EnvZone               = envNamespace + "ZONE"
+	EnvInsecureSkipVerify = envNamespace + "INSECURE_SKIP_VERIFY"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	Endpoint string
+	Username string
+	Password string
+
+	Server             string
+	Zone               string
+	InsecureSkipVerify bool
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultDuration(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultDuration(EnvPollingInterval, dns01.DefaultPollingInterval),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultDuration(EnvHTTPTimeout, 90*time.Second),
+		},
+	}
+}
+
+type powerShellRunner interface {
+	RunPowerShell(ctx context.Context, script string) (string, error)
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client powerShellRunner
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for Windows DNS Server.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvEndpoint, EnvUsername, EnvPassword)
+	if err != nil {
+		return nil, fmt.Errorf("windowsdns: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Endpoint = values[EnvEndpoint]
+	config.Username = values[EnvUsername]
+	config.Password = values[EnvPassword]
+	config.Server = env.GetOrDefaultString(EnvServer, "")
+	config.Zone = env.GetOrDefaultString(EnvZone, "")
+	config.InsecureSkipVerify = env.GetOrDefaultBool(EnvInsecureSkipVerify, false)
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for Windows DNS Server.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("windowsdns: the configuration of the DNS provider is nil")
+	}
+
+	client, err := internal.NewClient(config.Endpoint, config.Username, config.Password)
+	if err != nil {
+		return nil, fmt.Errorf("windowsdns: %w", err)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	if config.InsecureSkipVerify {
+		client.HTTPClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+
+	return &DNSProvider{config: config, client: client}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, subDomain, err := d.splitFQDN(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("windowsdns: %w", err)
+	}
+
+	cmd := "Add-DnsServerResourceRecord" + d.computerName() +
+		" -ZoneName " + quote(zone) +
+		" -Name " + quote(subDomain) +
+		" -Txt -DescriptiveText " + quote(info.Value) +
+		" -TimeToLive (New-TimeSpan -Seconds " + strconv.Itoa(d.config.TTL) + ")"
+
+	_, err = d.client.RunPowerShell(ctx, newScript(cmd))
+	if err != nil {
+		return fmt.Errorf("windowsdns: add TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	zone, subDomain, err := d.splitFQDN(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("windowsdns: %w", err)
+	}
+
+	// Only the record with the challenge value is removed,
+	// the other TXT records with the same name (ex: concurrent challenges) are kept.
+	cmd := "Get-DnsServerResourceRecord" + d.computerName() +
+		" -ZoneName " + quote(zone) +
+		" -Name " + quote(subDomain) +
+		" -RRType Txt -ErrorAction SilentlyContinue" +
+		" | Where-Object { $_.RecordData.DescriptiveText -eq " + quote(info.Value) + " }" +
+		" | Remove-DnsServerResourceRecord" + d.computerName() +
+		" -ZoneName " + quote(zone) + " -Force"
+
+	_, err = d.client.RunPowerShell(ctx, newScript(cmd))
+	if err != nil {
+		return fmt.Errorf("windowsdns: remove TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// splitFQDN returns the zone and the record name (relative to the zone) of the FQDN.
+func (d *DNSProvider) splitFQDN(ctx context.Context, fqdn string) (string, string, error) {
+	zone := d.config.Zone
+	if zone == "" {
+		var err error
+
+		zone, err = d.findZone(ctx, fqdn)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(fqdn, zone)
+	if err != nil {
+		return "", "", err
+	}
+
+	return dns01.UnFqdn(zone), subDomain, nil
+}
+
+// findZone finds the most specific zone hosted by the DNS server.
+// The zones are listed from the server instead of using DNS lookups:
+// the zones of an Active Directory are often not resolvable from the outside.
+func (d *DNSProvider) findZone(ctx context.Context, fqdn string) (string, error) {
+	cmd := "Get-DnsServerZone" + d.computerName() +
+		" | Where-Object { -not $_.IsReverseLookupZone -and -not $_.IsAutoCreated }" +
+		" | ForEach-Object { $_.ZoneName }"
+
+	output, err := d.client.RunPowerShell(ctx, newScript(cmd))
+	if err != nil {
+		return "", fmt.Errorf("list zones: %w", err)
+	}
+
+	zones := make(map[string]struct{})
+
+	for line := range strings.Lines(output) {
+		name := strings.ToLower(strings.TrimSpace(line))
+		if name != "" {
+			zones[name] = struct{}{}
+		}
+	}
+
+	for name := range dns01.UnFqdnDomainsSeq(strings.ToLower(fqdn)) {
+		if _, ok := zones[name]; ok {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("zone not found on the DNS server for %q", fqdn)
+}
+
+func (d *DNSProvider) computerName() string {
+	if d.config.Server == "" {
+		return ""
+	}
+
+	return " -ComputerName " + quote(d.config.Server)
+}
+
+// newScript wraps a command to stop on the first error, and to return the error message on stderr with a non-zero exit code.
+func newScript(cmd string) string {
+	return "$ErrorActionPreference = 'Stop'\n" +
+		"try {\n" +
+		"  " + cmd + "\n" +
+		"} catch {\n" +
+		"  [Console]::Error.WriteLine($_.Exception.Message)\n" +
+		"  exit 1\n" +
+		"}\n"
+}
+
+// quote returns a PowerShell single-quoted string.
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,278 @@
+package windowsdns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/digicert/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(
+	EnvEndpoint,
+	EnvUsername,
+	EnvPassword,
+	EnvServer,
+	EnvZone,
+	EnvInsecureSkipVerify).
+	WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvEndpoint: "https://dns.example.com:5986/wsman",
+				EnvUsername: "user",
+				EnvPassword: "secret",
+			},
+		},
+		{
+			desc: "missing endpoint",
+			envVars: map[string]string{
+				EnvUsername: "user",
+				EnvPassword: "secret",
+			},
+			expected: "windowsdns: some credentials information are missing: WINDOWSDNS_ENDPOINT",
+		},
+		{
+			desc: "missing username",
+			envVars: map[string]string{
+				EnvEndpoint: "https://dns.example.com:5986/wsman",
+				EnvPassword: "secret",
+			},
+			expected: "windowsdns: some credentials information are missing: WINDOWSDNS_USERNAME",
+		},
+		{
+			desc: "missing password",
+			envVars: map[string]string{
+				EnvEndpoint: "https://dns.example.com:5986/wsman",
+				EnvUsername: "user",
+			},
+			expected: "windowsdns: some credentials information are missing: WINDOWSDNS_PASSWORD",
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "windowsdns: some credentials information are missing: WINDOWSDNS_ENDPOINT,WINDOWSDNS_USERNAME,WINDOWSDNS_PASSWORD",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		endpoint string
+		username string
+		password string
+		expected string
+	}{
+		{
+			desc:     "success",
+			endpoint: "https://dns.example.com:5986/wsman",
+			username: "user",
+			password: "secret",
+		},
+		{
+			desc:     "missing endpoint",
+			username: "user",
+			password: "secret",
+			expected: "windowsdns: endpoint missing",
+		},
+		{
+			desc:     "missing username",
+			endpoint: "https://dns.example.com:5986/wsman",
+			password: "secret",
+			expected: "windowsdns: credentials missing",
+		},
+		{
+			desc:     "missing password",
+			endpoint: "https://dns.example.com:5986/wsman",
+			username: "user",
+			expected: "windowsdns: credentials missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.Endpoint = test.endpoint
+			config.Username = test.username
+			config.Password = test.password
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	runner := &fakeRunner{outputs: []string{"0.in-addr.arpa\r\nexample.com\r\nsub.example.com\r\nexample.org\r\n", ""}}
+
+	provider := setupProvider(t, runner)
+
+	err := provider.Present("www.example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Len(t, runner.scripts, 2)
+	assert.Contains(t, runner.scripts[0], "Get-DnsServerZone -ComputerName 'dc01.example.com' | Where-Object")
+	assert.Contains(t, runner.scripts[1],
+		"Add-DnsServerResourceRecord -ComputerName 'dc01.example.com' -ZoneName 'example.com' -Name '_acme-challenge.www' -Txt -DescriptiveText '123d==' -TimeToLive (New-TimeSpan -Seconds 120)")
+}
+
+func TestDNSProvider_Present_zone(t *testing.T) {
+	runner := &fakeRunner{outputs: []string{""}}
+
+	provider := setupProvider(t, runner)
+	provider.config.Zone = "example.com"
+	provider.config.Server = ""
+
+	err := provider.Present("sub.example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Len(t, runner.scripts, 1)
+	assert.Contains(t, runner.scripts[0],
+		"Add-DnsServerResourceRecord -ZoneName 'example.com' -Name '_acme-challenge.sub' -Txt -DescriptiveText '123d==' -TimeToLive (New-TimeSpan -Seconds 120)")
+}
+
+func TestDNSProvider_Present_zoneNotFound(t *testing.T) {
+	runner := &fakeRunner{outputs: []string{"example.org\r\n"}}
+
+	provider := setupProvider(t, runner)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.EqualError(t, err, `windowsdns: zone not found on the DNS server for "_acme-challenge.example.com."`)
+}
+
+func TestDNSProvider_Present_error(t *testing.T) {
+	runner := &fakeRunner{outputs: []string{"example.com\r\n", ""}, err: errors.New("PowerShell exit code 1: Access denied.")}
+
+	provider := setupProvider(t, runner)
+
+	err := provider.Present("example.com", "abc", "123d==")
+	require.EqualError(t, err, "windowsdns: add TXT record: PowerShell exit code 1: Access denied.")
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	runner := &fakeRunner{outputs: []string{"example.com\r\n", ""}}
+
+	provider := setupProvider(t, runner)
+
+	err := provider.CleanUp("example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Len(t, runner.scripts, 2)
+	assert.Contains(t, runner.scripts[1],
+		"Get-DnsServerResourceRecord -ComputerName 'dc01.example.com' -ZoneName 'example.com' -Name '_acme-challenge' -RRType Txt -ErrorAction SilentlyContinue"+
+			" | Where-Object { $_.RecordData.DescriptiveText -eq '123d==' }"+
+			" | Remove-DnsServerResourceRecord -ComputerName 'dc01.example.com' -ZoneName 'example.com' -Force")
+}
+
+func Test_quote(t *testing.T) {
+	assert.Equal(t, `'it''s'`, quote("it's"))
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func setupProvider(t *testing.T, runner powerShellRunner) *DNSProvider {
+	t.Helper()
+
+	config := NewDefaultConfig()
+	config.Endpoint = "https://dns.example.com:5986/wsman"
+	config.Username = "user"
+	config.Password = "secret"
+	config.Server = "dc01.example.com"
+	config.TTL = 120
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	p.client = runner
+
+	return p
+}
+
+// fakeRunner records the scripts and returns the outputs in order.
+// The error is returned by the last call.
+type fakeRunner struct {
+	outputs []string
+	err     error
+	scripts []string
+}
+
+func (f *fakeRunner) RunPowerShell(_ context.Context, script string) (string, error) {
+	f.scripts = append(f.scripts, script)
+
+	if len(f.scripts) > len(f.outputs) {
+		return "", errors.New("unexpected call")
+	}
+
+	if f.err != nil && len(f.scripts) == len(f.outputs) {
+		return "", f.err
+	}
+
+	return f.outputs[len(f.scripts)-1], nil
+}
@@ -38,21 +38,24 @@ var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	IamToken string
-	FolderID string
+	IamToken string `env:"IAM_TOKEN,required"`
+	FolderID string `env:"FOLDER_ID,required"`
 
-	PropagationTimeout time.Duration
-	PollingInterval    time.Duration
-	TTL                int
+	PropagationTimeout time.Duration `env:"PROPAGATION_TIMEOUT"`
+	PollingInterval    time.Duration `env:"POLLING_INTERVAL"`
+	TTL                int           `env:"TTL" default:"60"`
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
-	return &Config{
-		TTL:                env.GetOrDefaultInt(EnvTTL, 60),
-		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
-		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+	config := &Config{
+		PropagationTimeout: dns01.DefaultPropagationTimeout,
+		PollingInterval:    dns01.DefaultPollingInterval,
 	}
+
+	env.LoadDefaults(envNamespace, config)
+
+	return config
 }
 
 // DNSProvider implements the challenge.Provider interface.
@@ -63,15 +66,13 @@ type DNSProvider struct {
 
 // NewDNSProvider returns a DNSProvider instance configured for Yandex Cloud.
 func NewDNSProvider() (*DNSProvider, error) {
-	values, err := env.Get(EnvIamToken, EnvFolderID)
+	config := NewDefaultConfig()
+
+	err := env.Load(envNamespace, config)
 	if err != nil {
 		return nil, fmt.Errorf("yandexcloud: %w", err)
 	}
 
-	config := NewDefaultConfig()
-	config.IamToken = values[EnvIamToken]
-	config.FolderID = values[EnvFolderID]
-
 	return NewDNSProviderConfig(config)
 }
 
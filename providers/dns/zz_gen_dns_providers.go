@@ -68,6 +68,7 @@ import (
 	"github.com/digicert/lego/v4/providers/dns/edgedns"
 	"github.com/digicert/lego/v4/providers/dns/edgeone"
 	"github.com/digicert/lego/v4/providers/dns/efficientip"
+	"github.com/digicert/lego/v4/providers/dns/enom"
 	"github.com/digicert/lego/v4/providers/dns/epik"
 	"github.com/digicert/lego/v4/providers/dns/eurodns"
 	"github.com/digicert/lego/v4/providers/dns/excedo"
@@ -110,6 +111,7 @@ import (
 	"github.com/digicert/lego/v4/providers/dns/jdcloud"
 	"github.com/digicert/lego/v4/providers/dns/joker"
 	"github.com/digicert/lego/v4/providers/dns/keyhelp"
+	"github.com/digicert/lego/v4/providers/dns/knot"
 	"github.com/digicert/lego/v4/providers/dns/leaseweb"
 	"github.com/digicert/lego/v4/providers/dns/liara"
 	"github.com/digicert/lego/v4/providers/dns/lightsail"
@@ -145,6 +147,7 @@ import (
 	"github.com/digicert/lego/v4/providers/dns/ns1"
 	"github.com/digicert/lego/v4/providers/dns/octenium"
 	"github.com/digicert/lego/v4/providers/dns/onecloudru"
+	"github.com/digicert/lego/v4/providers/dns/openprovider"
 	"github.com/digicert/lego/v4/providers/dns/oraclecloud"
 	"github.com/digicert/lego/v4/providers/dns/otc"
 	"github.com/digicert/lego/v4/providers/dns/ovh"
@@ -154,6 +157,7 @@ import (
 	"github.com/digicert/lego/v4/providers/dns/rackspace"
 	"github.com/digicert/lego/v4/providers/dns/rainyun"
 	"github.com/digicert/lego/v4/providers/dns/rcodezero"
+	"github.com/digicert/lego/v4/providers/dns/realtimeregister"
 	"github.com/digicert/lego/v4/providers/dns/regfish"
 	"github.com/digicert/lego/v4/providers/dns/regru"
 	"github.com/digicert/lego/v4/providers/dns/rfc2136"
@@ -171,6 +175,7 @@ import (
 	"github.com/digicert/lego/v4/providers/dns/sonic"
 	"github.com/digicert/lego/v4/providers/dns/spaceship"
 	"github.com/digicert/lego/v4/providers/dns/stackpath"
+	"github.com/digicert/lego/v4/providers/dns/standalone"
 	"github.com/digicert/lego/v4/providers/dns/syse"
 	"github.com/digicert/lego/v4/providers/dns/technitium"
 	"github.com/digicert/lego/v4/providers/dns/tencentcloud"
@@ -194,6 +199,7 @@ import (
 	"github.com/digicert/lego/v4/providers/dns/websupport"
 	"github.com/digicert/lego/v4/providers/dns/wedos"
 	"github.com/digicert/lego/v4/providers/dns/westcn"
+	"github.com/digicert/lego/v4/providers/dns/windowsdns"
 	"github.com/digicert/lego/v4/providers/dns/yandex"
 	"github.com/digicert/lego/v4/providers/dns/yandex360"
 	"github.com/digicert/lego/v4/providers/dns/yandexcloud"
@@ -327,6 +333,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return edgeone.NewDNSProvider()
 	case "efficientip":
 		return efficientip.NewDNSProvider()
+	case "enom":
+		return enom.NewDNSProvider()
 	case "epik":
 		return epik.NewDNSProvider()
 	case "eurodns":
@@ -411,6 +419,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return joker.NewDNSProvider()
 	case "keyhelp":
 		return keyhelp.NewDNSProvider()
+	case "knot":
+		return knot.NewDNSProvider()
 	case "leaseweb":
 		return leaseweb.NewDNSProvider()
 	case "liara":
@@ -483,6 +493,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return octenium.NewDNSProvider()
 	case "onecloudru":
 		return onecloudru.NewDNSProvider()
+	case "openprovider":
+		return openprovider.NewDNSProvider()
 	case "oraclecloud":
 		return oraclecloud.NewDNSProvider()
 	case "otc":
@@ -501,6 +513,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return rainyun.NewDNSProvider()
 	case "rcodezero":
 		return rcodezero.NewDNSProvider()
+	case "realtimeregister":
+		return realtimeregister.NewDNSProvider()
 	case "regfish":
 		return regfish.NewDNSProvider()
 	case "regru":
@@ -535,6 +549,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return spaceship.NewDNSProvider()
 	case "stackpath":
 		return stackpath.NewDNSProvider()
+	case "standalone":
+		return standalone.NewDNSProvider()
 	case "syse":
 		return syse.NewDNSProvider()
 	case "technitium":
@@ -581,6 +597,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return wedos.NewDNSProvider()
 	case "westcn":
 		return westcn.NewDNSProvider()
+	case "windowsdns":
+		return windowsdns.NewDNSProvider()
 	case "yandex":
 		return yandex.NewDNSProvider()
 	case "yandex360":
@@ -0,0 +1,154 @@
+package memcached
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Binary protocol.
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped
+const (
+	magicRequest  = 0x80
+	magicResponse = 0x81
+
+	opSet      = 0x01
+	opDelete   = 0x04
+	opSASLAuth = 0x21
+
+	headerLen = 24
+)
+
+// Response statuses.
+const (
+	statusOK          = 0x00
+	statusKeyNotFound = 0x01
+	statusAuthError   = 0x20
+)
+
+// errKeyNotFound is returned when a key doesn't exist.
+var errKeyNotFound = errors.New("key not found")
+
+// client is a minimal client of the memcached binary protocol.
+// The binary protocol is the only one supporting the SASL authentication.
+type client struct {
+	conn net.Conn
+}
+
+// dial connects to a memcached server, and authenticates if credentials are defined.
+// The timeout applies to the whole session.
+func dial(host string, config *Config) (*client, error) {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if config.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", host)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(config.Timeout))
+
+	c := &client{conn: conn}
+
+	if config.Username != "" {
+		err = c.authenticate(config.Username, config.Password)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// authenticate uses the SASL PLAIN mechanism.
+func (c *client) authenticate(username, password string) error {
+	value := []byte("\x00" + username + "\x00" + password)
+
+	err := c.do(opSASLAuth, "PLAIN", nil, value)
+	if err != nil {
+		return fmt.Errorf("SASL authentication: %w", err)
+	}
+
+	return nil
+}
+
+func (c *client) set(key string, value []byte, expiration uint32) error {
+	extras := make([]byte, 8)
+	// The first 4 bytes are the flags (unused).
+	binary.BigEndian.PutUint32(extras[4:], expiration)
+
+	return c.do(opSet, key, extras, value)
+}
+
+func (c *client) delete(key string) error {
+	return c.do(opDelete, key, nil, nil)
+}
+
+func (c *client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *client) do(opcode byte, key string, extras, value []byte) error {
+	bodyLen := len(extras) + len(key) + len(value)
+
+	req := make([]byte, headerLen, headerLen+bodyLen)
+	req[0] = magicRequest
+	req[1] = opcode
+	binary.BigEndian.PutUint16(req[2:4], uint16(len(key)))
+	req[4] = byte(len(extras))
+	binary.BigEndian.PutUint32(req[8:12], uint32(bodyLen))
+
+	req = append(req, extras...)
+	req = append(req, key...)
+	req = append(req, value...)
+
+	_, err := c.conn.Write(req)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, headerLen)
+
+	_, err = io.ReadFull(c.conn, header)
+	if err != nil {
+		return err
+	}
+
+	if header[0] != magicResponse {
+		return fmt.Errorf("invalid response magic: %#x", header[0])
+	}
+
+	body := make([]byte, binary.BigEndian.Uint32(header[8:12]))
+
+	_, err = io.ReadFull(c.conn, body)
+	if err != nil {
+		return err
+	}
+
+	switch status := binary.BigEndian.Uint16(header[6:8]); status {
+	case statusOK:
+		return nil
+	case statusKeyNotFound:
+		return errKeyNotFound
+	case statusAuthError:
+		return errors.New("authentication error")
+	default:
+		// The body of an error response contains the message.
+		return fmt.Errorf("status %#x: %s", status, bytes.TrimSpace(body))
+	}
+}
@@ -2,61 +2,157 @@
 package memcached
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"path"
+	"time"
 
 	"github.com/digicert/lego/v4/challenge/http01"
-	"github.com/rainycape/memcache"
 )
 
+// expiration of the challenge keys, in seconds.
+const expiration = 60
+
+// Config is used to configure the creation of the HTTPProvider.
+type Config struct {
+	// Hosts are the memcached servers (`host:port`), the challenges are written to all of them.
+	Hosts []string
+
+	// Username and Password enable the SASL authentication (PLAIN mechanism).
+	Username string
+	Password string
+
+	// TLSConfig enables TLS connections, if not nil.
+	TLSConfig *tls.Config
+
+	// Quorum is the number of hosts that must store a challenge for Present to succeed.
+	// The default (0) is 1 host.
+	Quorum int
+
+	// Timeout of the session with each host.
+	Timeout time.Duration
+}
+
+// NewDefaultConfig returns a default configuration for the HTTPProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Quorum:  1,
+		Timeout: 5 * time.Second,
+	}
+}
+
 // HTTPProvider implements HTTPProvider for `http-01` challenge.
 type HTTPProvider struct {
-	hosts []string
+	config *Config
 }
 
 // NewMemcachedProvider returns a HTTPProvider instance with a configured webroot path.
 func NewMemcachedProvider(hosts []string) (*HTTPProvider, error) {
-	if len(hosts) == 0 {
+	config := NewDefaultConfig()
+	config.Hosts = hosts
+
+	return NewMemcachedProviderConfig(config)
+}
+
+// NewMemcachedProviderConfig returns a HTTPProvider instance configured for memcached.
+func NewMemcachedProviderConfig(config *Config) (*HTTPProvider, error) {
+	if config == nil {
+		return nil, errors.New("the memcached configuration is nil")
+	}
+
+	if len(config.Hosts) == 0 {
 		return nil, errors.New("no memcached hosts provided")
 	}
 
-	c := &HTTPProvider{
-		hosts: hosts,
+	cfg := *config
+
+	if cfg.Quorum <= 0 {
+		cfg.Quorum = 1
+	}
+
+	if cfg.Quorum > len(cfg.Hosts) {
+		return nil, fmt.Errorf("the memcached quorum (%d) is higher than the number of hosts (%d)", cfg.Quorum, len(cfg.Hosts))
 	}
 
-	return c, nil
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = NewDefaultConfig().Timeout
+	}
+
+	return &HTTPProvider{config: &cfg}, nil
 }
 
-// Present makes the token available at `HTTP01ChallengePath(token)` by creating a file in the given webroot path.
+// Present makes the token available at `HTTP01ChallengePath(token)` by storing it in the memcached hosts.
 func (w *HTTPProvider) Present(domain, token, keyAuth string) error {
-	var errs []error
-
 	challengePath := path.Join("/", http01.ChallengePath(token))
 
-	for _, host := range w.hosts {
-		mc, err := memcache.New(host)
+	var (
+		errs   []error
+		stored int
+	)
+
+	for _, host := range w.config.Hosts {
+		err := w.store(host, challengePath, []byte(keyAuth))
 		if err != nil {
-			errs = append(errs, err)
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
 			continue
 		}
 
-		_ = mc.Add(&memcache.Item{
-			Key:        challengePath,
-			Value:      []byte(keyAuth),
-			Expiration: 60,
-		})
+		stored++
 	}
 
-	if len(errs) == len(w.hosts) {
-		return fmt.Errorf("unable to store key in any of the memcache hosts: %v", errs)
+	if stored < w.config.Quorum {
+		return fmt.Errorf("unable to store key in enough memcache hosts (quorum: %d, stored: %d): %w",
+			w.config.Quorum, stored, errors.Join(errs...))
 	}
 
 	return nil
 }
 
-// CleanUp removes the file created for the challenge.
+// CleanUp removes the key created for the challenge.
+// Memcached will clean up itself anyway, that's what expiration is for.
 func (w *HTTPProvider) CleanUp(domain, token, keyAuth string) error {
-	// Memcached will clean up itself, that's what expiration is for.
+	challengePath := path.Join("/", http01.ChallengePath(token))
+
+	var errs []error
+
+	for _, host := range w.config.Hosts {
+		err := w.remove(host, challengePath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to remove key from the memcache hosts: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (w *HTTPProvider) store(host, key string, value []byte) error {
+	c, err := dial(host, w.config)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = c.Close() }()
+
+	return c.set(key, value, expiration)
+}
+
+func (w *HTTPProvider) remove(host, key string) error {
+	c, err := dial(host, w.config)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = c.Close() }()
+
+	err = c.delete(key)
+	if err != nil && !errors.Is(err, errKeyNotFound) {
+		return err
+	}
+
 	return nil
 }
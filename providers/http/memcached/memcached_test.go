@@ -1,10 +1,22 @@
 package memcached
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/digicert/lego/v4/challenge/http01"
 	"github.com/rainycape/memcache"
@@ -117,3 +129,234 @@ func TestMemcachedCleanup(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, p.CleanUp(domain, token, keyAuth))
 }
+
+func TestNewMemcachedProviderConfig_quorum(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Hosts = []string{"127.0.0.1:11211", "127.0.0.2:11211"}
+	config.Quorum = 3
+
+	_, err := NewMemcachedProviderConfig(config)
+	require.EqualError(t, err, "the memcached quorum (3) is higher than the number of hosts (2)")
+}
+
+func TestHTTPProvider_Present(t *testing.T) {
+	servers := []*fakeServer{newFakeServer(t, nil, ""), newFakeServer(t, nil, "")}
+
+	config := NewDefaultConfig()
+	config.Hosts = []string{servers[0].addr, servers[1].addr}
+
+	p, err := NewMemcachedProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present(domain, token, keyAuth)
+	require.NoError(t, err)
+
+	challengePath := path.Join("/", http01.ChallengePath(token))
+
+	for _, server := range servers {
+		assert.Equal(t, keyAuth, server.get(challengePath))
+	}
+
+	err = p.CleanUp(domain, token, keyAuth)
+	require.NoError(t, err)
+
+	for _, server := range servers {
+		assert.Empty(t, server.get(challengePath))
+	}
+}
+
+func TestHTTPProvider_Present_SASL_TLS(t *testing.T) {
+	cert := generateCertificate(t)
+
+	server := newFakeServer(t, &tls.Config{Certificates: []tls.Certificate{cert}}, "\x00user\x00secret")
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+
+	config := NewDefaultConfig()
+	config.Hosts = []string{server.addr}
+	config.TLSConfig = &tls.Config{RootCAs: pool}
+	config.Username = "user"
+	config.Password = "secret"
+
+	p, err := NewMemcachedProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present(domain, token, keyAuth)
+	require.NoError(t, err)
+
+	assert.Equal(t, keyAuth, server.get(path.Join("/", http01.ChallengePath(token))))
+
+	config.Password = "invalid"
+
+	p, err = NewMemcachedProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present(domain, token, keyAuth)
+	require.ErrorContains(t, err, "SASL authentication: authentication error")
+}
+
+func TestHTTPProvider_Present_quorum(t *testing.T) {
+	server := newFakeServer(t, nil, "")
+
+	// Nothing listens on this address.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	unreachable := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	config := NewDefaultConfig()
+	config.Hosts = []string{server.addr, unreachable}
+	config.Timeout = time.Second
+
+	p, err := NewMemcachedProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present(domain, token, keyAuth)
+	require.NoError(t, err)
+
+	config.Quorum = 2
+
+	p, err = NewMemcachedProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present(domain, token, keyAuth)
+	require.ErrorContains(t, err, "unable to store key in enough memcache hosts (quorum: 2, stored: 1)")
+}
+
+// fakeServer is a memcached server supporting the commands used by the provider (binary protocol).
+type fakeServer struct {
+	addr        string
+	credentials string
+
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func newFakeServer(t *testing.T, tlsConfig *tls.Config, credentials string) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &fakeServer{
+		addr:        listener.Addr().String(),
+		credentials: credentials,
+		items:       make(map[string]string),
+	}
+
+	go func() {
+		for {
+			conn, errA := listener.Accept()
+			if errA != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeServer) get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.items[key]
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	authenticated := s.credentials == ""
+
+	for {
+		header := make([]byte, headerLen)
+
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+
+		body := make([]byte, binary.BigEndian.Uint32(header[8:12]))
+
+		_, err = io.ReadFull(conn, body)
+		if err != nil {
+			return
+		}
+
+		extrasLen := int(header[4])
+		keyLen := int(binary.BigEndian.Uint16(header[2:4]))
+
+		key := string(body[extrasLen : extrasLen+keyLen])
+		value := string(body[extrasLen+keyLen:])
+
+		var status uint16
+
+		s.mu.Lock()
+
+		switch {
+		case header[1] == opSASLAuth:
+			authenticated = key == "PLAIN" && value == s.credentials
+			if !authenticated {
+				status = statusAuthError
+			}
+
+		case !authenticated:
+			status = statusAuthError
+
+		case header[1] == opSet:
+			s.items[key] = value
+
+		case header[1] == opDelete:
+			if _, ok := s.items[key]; !ok {
+				status = statusKeyNotFound
+			}
+
+			delete(s.items, key)
+		}
+
+		s.mu.Unlock()
+
+		resp := make([]byte, headerLen)
+		resp[0] = magicResponse
+		resp[1] = header[1]
+		binary.BigEndian.PutUint16(resp[6:8], status)
+
+		_, err = conn.Write(resp)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func generateCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "memcached"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: privateKey, Leaf: leaf}
+}
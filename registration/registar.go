@@ -2,11 +2,12 @@ package registration
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
-	"github.com/digicert/lego/v4/log"
 )
 
 const mailTo = "mailto:"
@@ -53,7 +54,7 @@ func (r *Registrar) Register(options RegisterOptions) (*Resource, error) {
 	}
 
 	if r.user.GetEmail() != "" {
-		log.Infof("acme: Registering account for %s", r.user.GetEmail())
+		r.core.Logger().Info("acme: Registering account.", "email", r.user.GetEmail())
 		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
 	}
 
@@ -77,7 +78,7 @@ func (r *Registrar) RegisterWithExternalAccountBinding(options RegisterEABOption
 	}
 
 	if r.user.GetEmail() != "" {
-		log.Infof("acme: Registering account for %s", r.user.GetEmail())
+		r.core.Logger().Info("acme: Registering account.", "email", r.user.GetEmail())
 		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
 	}
 
@@ -103,7 +104,7 @@ func (r *Registrar) QueryRegistration() (*Resource, error) {
 	}
 
 	// Log the URL here instead of the email as the email may not be set
-	log.Infof("acme: Querying account for %s", r.user.GetRegistration().URI)
+	r.core.Logger().Info("acme: Querying account.", "url", r.user.GetRegistration().URI)
 
 	account, err := r.core.Accounts.Get(r.user.GetRegistration().URI)
 	if err != nil {
@@ -129,7 +130,7 @@ func (r *Registrar) UpdateRegistration(options RegisterOptions) (*Resource, erro
 	}
 
 	if r.user.GetEmail() != "" {
-		log.Infof("acme: Registering account for %s", r.user.GetEmail())
+		r.core.Logger().Info("acme: Registering account.", "email", r.user.GetEmail())
 		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
 	}
 
@@ -143,13 +144,64 @@ func (r *Registrar) UpdateRegistration(options RegisterOptions) (*Resource, erro
 	return &Resource{URI: accountURL, Body: account}, nil
 }
 
+// UpdateContacts replaces the contacts of the user registration on the ACME server
+// (the email addresses used by the CA to send the expiration notices).
+// Without email, the contacts are removed.
+func (r *Registrar) UpdateContacts(emails ...string) (*Resource, error) {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return nil, errors.New("acme: cannot update the contacts of a nil client or user")
+	}
+
+	contacts := []string{}
+
+	for _, email := range emails {
+		email = strings.TrimPrefix(strings.TrimSpace(email), mailTo)
+		if !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("acme: invalid email address: %q", email)
+		}
+
+		contacts = append(contacts, mailTo+email)
+	}
+
+	accountURL := r.user.GetRegistration().URI
+
+	r.core.Logger().Info("acme: Updating account contacts.", "url", accountURL, "contacts", strings.Join(contacts, ", "))
+
+	account, err := r.core.Accounts.UpdateContacts(accountURL, contacts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resource{URI: accountURL, Body: account}, nil
+}
+
+// AcceptTermsOfService agrees to the current terms of service of the ACME server for the user registration.
+// It is used when the server returns a userActionRequired error (acme.UserActionRequiredError) after a change of the terms of service.
+// The contacts of the account are not modified.
+func (r *Registrar) AcceptTermsOfService() (*Resource, error) {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return nil, errors.New("acme: cannot accept the terms of service for a nil client or user")
+	}
+
+	accountURL := r.user.GetRegistration().URI
+
+	r.core.Logger().Info("acme: Accepting the terms of service.", "url", accountURL)
+
+	account, err := r.core.Accounts.Update(accountURL, acme.Account{TermsOfServiceAgreed: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resource{URI: accountURL, Body: account}, nil
+}
+
 // DeleteRegistration deletes the client's user registration from the ACME server.
 func (r *Registrar) DeleteRegistration() error {
 	if r == nil || r.user == nil {
 		return errors.New("acme: cannot unregister a nil client or user")
 	}
 
-	log.Infof("acme: Deleting account for %s", r.user.GetEmail())
+	r.core.Logger().Info("acme: Deleting account.", "email", r.user.GetEmail())
 
 	return r.core.Accounts.Deactivate(r.user.GetRegistration().URI)
 }
@@ -157,7 +209,7 @@ func (r *Registrar) DeleteRegistration() error {
 // ResolveAccountByKey will attempt to look up an account using the given account key
 // and return its registration resource.
 func (r *Registrar) ResolveAccountByKey() (*Resource, error) {
-	log.Infof("acme: Trying to resolve account by key")
+	r.core.Logger().Info("acme: Trying to resolve account by key.")
 
 	accMsg := acme.Account{OnlyReturnExisting: true}
 
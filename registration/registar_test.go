@@ -3,45 +3,176 @@ package registration
 import (
 	"crypto/rand"
 	"crypto/rsa"
-	"fmt"
-	"net/http"
 	"testing"
 
 	"github.com/digicert/lego/v4/acme"
 	"github.com/digicert/lego/v4/acme/api"
-	"github.com/digicert/lego/v4/platform/tester"
-	"github.com/digicert/lego/v4/platform/tester/servermock"
+	"github.com/digicert/lego/v4/platform/tester/acmeserver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestRegistrar_Register(t *testing.T) {
+	server := acmeserver.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{email: "test@example.com", privatekey: key})
+
+	res, err := registrar.Register(RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, res.URI)
+	assert.Equal(t, acme.StatusValid, res.Body.Status)
+	assert.Equal(t, []string{"mailto:test@example.com"}, res.Body.Contact)
+	assert.True(t, res.Body.TermsOfServiceAgreed)
+}
+
 func TestRegistrar_ResolveAccountByKey(t *testing.T) {
-	server := tester.MockACMEServer().
-		Route("/account",
-			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-				rw.Header().Set("Location",
-					fmt.Sprintf("http://%s/account", req.Context().Value(http.LocalAddrContextKey)))
+	server := acmeserver.New(t)
 
-				servermock.JSONEncode(acme.Account{Status: "valid"}).ServeHTTP(rw, req)
-			})).
-		BuildHTTPS(t)
+	_, user := setupRegisteredUser(t, server, "test@example.com")
 
-	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", user.privatekey)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{privatekey: user.privatekey})
+
+	res, err := registrar.ResolveAccountByKey()
+	require.NoError(t, err, "Unexpected error resolving account by key")
+
+	assert.Equal(t, user.regres.URI, res.URI)
+	assert.Equal(t, acme.StatusValid, res.Body.Status, "Unexpected account status")
+	assert.Equal(t, []string{"mailto:test@example.com"}, res.Body.Contact)
+}
+
+func TestRegistrar_ResolveAccountByKey_unknownKey(t *testing.T) {
+	server := acmeserver.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	require.NoError(t, err, "Could not generate test key")
 
-	user := mockUser{
-		email:      "test@test.com",
-		regres:     &Resource{},
-		privatekey: key,
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{privatekey: key})
+
+	_, err = registrar.ResolveAccountByKey()
+	require.ErrorContains(t, err, "no account for the key")
+}
+
+func TestRegistrar_UpdateContacts(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		emails   []string
+		expected []string
+	}{
+		{
+			desc:     "new contacts",
+			emails:   []string{"new@example.com", "mailto:ops@example.com"},
+			expected: []string{"mailto:new@example.com", "mailto:ops@example.com"},
+		},
+		{
+			desc: "remove contacts",
+		},
 	}
 
-	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			server := acmeserver.New(t)
+
+			core, user := setupRegisteredUser(t, server, "test@example.com")
+
+			registrar := NewRegistrar(core, user)
+
+			res, err := registrar.UpdateContacts(test.emails...)
+			require.NoError(t, err)
+
+			assert.Equal(t, user.regres.URI, res.URI)
+			assert.Equal(t, acme.StatusValid, res.Body.Status)
+			assert.Equal(t, test.expected, res.Body.Contact)
+
+			// The contacts are updated on the server.
+			account, err := registrar.QueryRegistration()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, account.Body.Contact)
+		})
+	}
+}
+
+func TestRegistrar_AcceptTermsOfService(t *testing.T) {
+	server := acmeserver.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", key)
+	require.NoError(t, err)
+
+	user := mockUser{email: "test@example.com", privatekey: key}
+
+	user.regres, err = NewRegistrar(core, user).Register(RegisterOptions{})
 	require.NoError(t, err)
 
+	require.False(t, user.regres.Body.TermsOfServiceAgreed)
+
 	registrar := NewRegistrar(core, user)
 
-	res, err := registrar.ResolveAccountByKey()
-	require.NoError(t, err, "Unexpected error resolving account by key")
+	res, err := registrar.AcceptTermsOfService()
+	require.NoError(t, err)
+
+	assert.Equal(t, user.regres.URI, res.URI)
+	assert.True(t, res.Body.TermsOfServiceAgreed)
+
+	// The contacts of the account are not modified.
+	assert.Equal(t, []string{"mailto:test@example.com"}, res.Body.Contact)
+}
+
+func TestRegistrar_UpdateContacts_invalidEmail(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	registrar := NewRegistrar(nil, mockUser{regres: &Resource{URI: "https://example.com/account/1"}, privatekey: key})
+
+	_, err = registrar.UpdateContacts("example.com")
+	require.EqualError(t, err, `acme: invalid email address: "example.com"`)
+}
+
+func TestRegistrar_DeleteRegistration(t *testing.T) {
+	server := acmeserver.New(t)
+
+	core, user := setupRegisteredUser(t, server, "test@example.com")
+
+	registrar := NewRegistrar(core, user)
+
+	err := registrar.DeleteRegistration()
+	require.NoError(t, err)
+
+	// The requests of a deactivated account are rejected.
+	_, err = registrar.QueryRegistration()
+	require.ErrorContains(t, err, "the account is deactivated")
+}
+
+// setupRegisteredUser registers an account on the ACME test server,
+// and returns the user of the account with the client signing the requests with the account URL.
+func setupRegisteredUser(t *testing.T, server *acmeserver.Server, email string) (*api.Core, mockUser) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.DirectoryURL(), "", key)
+	require.NoError(t, err)
+
+	user := mockUser{email: email, privatekey: key}
+
+	user.regres, err = NewRegistrar(core, user).Register(RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
 
-	assert.Equal(t, "valid", res.Body.Status, "Unexpected account status")
+	return core, user
 }
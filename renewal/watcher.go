@@ -0,0 +1,295 @@
+// Package renewal watches the certificates used by a server,
+// and reports when they should be renewed or reissued, using the ACME Renewal Information (ARI) and OCSP.
+package renewal
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/digicert/lego/v4/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+// Default values of the Watcher.
+const (
+	DefaultInterval    = 6 * time.Hour
+	DefaultRenewBefore = 30 * 24 * time.Hour
+)
+
+// Reason is the reason of a renewal.
+type Reason string
+
+const (
+	// ReasonARI the suggested renewal window of the CA (ARI) is reached.
+	ReasonARI Reason = "ari"
+
+	// ReasonExpiring the certificate expires in less than RenewBefore (the CA doesn't support ARI).
+	ReasonExpiring Reason = "expiring"
+
+	// ReasonRevoked the certificate is revoked (OCSP): it must be reissued immediately.
+	ReasonRevoked Reason = "revoked"
+)
+
+// Event is sent to the callback when a certificate should be renewed.
+type Event struct {
+	// Name the name of the certificate given to Watcher.Add.
+	Name string
+
+	// Certificate the leaf certificate.
+	Certificate *x509.Certificate
+
+	// Reason the reason of the renewal.
+	Reason Reason
+
+	// ExplanationURL the page explaining the suggested renewal window, if provided by the CA.
+	ExplanationURL string
+}
+
+// Checker retrieves the renewal information and the revocation status of the certificates.
+// It's implemented by certificate.Certifier (lego.Client.Certificate).
+type Checker interface {
+	GetRenewalInfo(req certificate.RenewalInfoRequest) (*certificate.RenewalInfoResponse, error)
+	GetOCSP(bundle []byte) ([]byte, *ocsp.Response, error)
+}
+
+// Watcher polls periodically the renewal information (ARI) and the OCSP status of the certificates,
+// and calls OnRenew when a certificate should be renewed, or reissued because it's revoked.
+//
+// The callback is called once for a certificate:
+// the renewed certificate must be given to Add (with the same name) to be watched.
+type Watcher struct {
+	// Checker retrieves the renewal information and the revocation status (required).
+	Checker Checker
+
+	// OnRenew is called when a certificate should be renewed (required).
+	OnRenew func(ctx context.Context, event Event)
+
+	// Interval the duration between two checks of a certificate.
+	// The Retry-After of the ARI responses can shorten it.
+	// If zero, DefaultInterval is used.
+	Interval time.Duration
+
+	// RenewBefore the duration before the expiration of a certificate when it's renewed,
+	// if the CA doesn't support ARI.
+	// If zero, DefaultRenewBefore is used.
+	RenewBefore time.Duration
+
+	// DisableOCSP disables the detection of the revocations with OCSP.
+	DisableOCSP bool
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	wake    chan struct{}
+}
+
+type entry struct {
+	bundle    []byte
+	leaf      *x509.Certificate
+	nextCheck time.Time
+	renewAt   time.Time
+	notified  bool
+}
+
+// Add watches a certificate (a PEM bundle, the leaf certificate first).
+// A certificate with the same name is replaced.
+func (w *Watcher) Add(name string, bundle []byte) error {
+	leaf, err := certcrypto.ParsePEMCertificate(bundle)
+	if err != nil {
+		return fmt.Errorf("renewal: %s: %w", name, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.entries == nil {
+		w.entries = make(map[string]*entry)
+	}
+
+	w.entries[name] = &entry{bundle: bundle, leaf: leaf}
+
+	w.notify()
+
+	return nil
+}
+
+// Remove stops watching a certificate.
+func (w *Watcher) Remove(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.entries, name)
+}
+
+// Run checks the certificates until the context is canceled.
+// It's meant to be run in its own goroutine, only once at a time.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.Checker == nil {
+		return errors.New("renewal: the checker is nil")
+	}
+
+	if w.OnRenew == nil {
+		return errors.New("renewal: the callback is nil")
+	}
+
+	w.mu.Lock()
+	if w.wake == nil {
+		w.wake = make(chan struct{}, 1)
+	}
+	wake := w.wake
+	w.mu.Unlock()
+
+	for {
+		next := w.checkAll(ctx, time.Now())
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return context.Cause(ctx)
+
+		case <-wake:
+			timer.Stop()
+
+		case <-timer.C:
+		}
+	}
+}
+
+// notify wakes up Run, the caller must hold the lock.
+func (w *Watcher) notify() {
+	if w.wake == nil {
+		w.wake = make(chan struct{}, 1)
+	}
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// checkAll checks the certificates to check at this time, and returns the time of the next check.
+func (w *Watcher) checkAll(ctx context.Context, now time.Time) time.Time {
+	next := now.Add(w.interval())
+
+	for name, e := range w.due(now) {
+		if ctx.Err() != nil {
+			return next
+		}
+
+		event, nextCheck := w.check(name, e, now)
+
+		w.mu.Lock()
+		current, ok := w.entries[name]
+		if ok && current == e {
+			e.nextCheck = nextCheck
+			e.notified = event != nil
+		}
+		w.mu.Unlock()
+
+		if ok && current == e && event != nil {
+			w.OnRenew(ctx, *event)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, e := range w.entries {
+		if !e.notified && e.nextCheck.Before(next) {
+			next = e.nextCheck
+		}
+	}
+
+	return next
+}
+
+// due returns the certificates to check at this time.
+func (w *Watcher) due(now time.Time) map[string]*entry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	due := make(map[string]*entry)
+
+	for name, e := range w.entries {
+		if !e.notified && !e.nextCheck.After(now) {
+			due[name] = e
+		}
+	}
+
+	return due
+}
+
+// check checks a certificate.
+// It returns an event if the certificate should be renewed, and the time of the next check.
+func (w *Watcher) check(name string, e *entry, now time.Time) (*Event, time.Time) {
+	next := now.Add(w.interval())
+
+	// The renewal time selected during the previous check is reached.
+	if !e.renewAt.IsZero() && !e.renewAt.After(now) {
+		return &Event{Name: name, Certificate: e.leaf, Reason: ReasonARI}, next
+	}
+
+	if !w.DisableOCSP && len(e.leaf.OCSPServer) > 0 {
+		_, resp, err := w.Checker.GetOCSP(e.bundle)
+		if err != nil {
+			log.Warn("renewal: could not get the OCSP status.", "name", name, "error", err)
+		} else if resp != nil && resp.Status == ocsp.Revoked {
+			return &Event{Name: name, Certificate: e.leaf, Reason: ReasonRevoked}, next
+		}
+	}
+
+	info, err := w.Checker.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: e.leaf})
+	if err != nil {
+		if !errors.Is(err, api.ErrNoARI) {
+			log.Warn("renewal: could not get the renewal information.", "name", name, "error", err)
+		}
+
+		if !e.leaf.NotAfter.Add(-w.renewBefore()).After(now) {
+			return &Event{Name: name, Certificate: e.leaf, Reason: ReasonExpiring}, next
+		}
+
+		return nil, next
+	}
+
+	if info.RetryAfter > 0 && now.Add(info.RetryAfter).Before(next) {
+		next = now.Add(info.RetryAfter)
+	}
+
+	renewAt := info.ShouldRenewAt(now, w.interval())
+	if renewAt == nil {
+		return nil, next
+	}
+
+	if renewAt.After(now) {
+		// The renewal time is before the next check: wake up at this time.
+		e.renewAt = *renewAt
+
+		return nil, *renewAt
+	}
+
+	return &Event{Name: name, Certificate: e.leaf, Reason: ReasonARI, ExplanationURL: info.ExplanationURL}, next
+}
+
+func (w *Watcher) interval() time.Duration {
+	if w.Interval > 0 {
+		return w.Interval
+	}
+
+	return DefaultInterval
+}
+
+func (w *Watcher) renewBefore() time.Duration {
+	if w.RenewBefore > 0 {
+		return w.RenewBefore
+	}
+
+	return DefaultRenewBefore
+}
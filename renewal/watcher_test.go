@@ -0,0 +1,210 @@
+package renewal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/acme"
+	"github.com/digicert/lego/v4/acme/api"
+	"github.com/digicert/lego/v4/certcrypto"
+	"github.com/digicert/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type checkerMock struct {
+	info       *certificate.RenewalInfoResponse
+	infoErr    error
+	ocspStatus int
+	ocspCalls  int
+}
+
+func (m *checkerMock) GetRenewalInfo(_ certificate.RenewalInfoRequest) (*certificate.RenewalInfoResponse, error) {
+	return m.info, m.infoErr
+}
+
+func (m *checkerMock) GetOCSP(_ []byte) ([]byte, *ocsp.Response, error) {
+	m.ocspCalls++
+
+	return nil, &ocsp.Response{Status: m.ocspStatus}, nil
+}
+
+func TestWatcher_checkAll(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc       string
+		checker    *checkerMock
+		notAfter   time.Time
+		ocspServer bool
+		expected   []Event
+	}{
+		{
+			desc: "ARI window reached",
+			checker: &checkerMock{
+				info: &certificate.RenewalInfoResponse{RenewalInfoResponse: acme.RenewalInfoResponse{
+					SuggestedWindow: acme.Window{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+					ExplanationURL:  "https://ca.example.com/incident",
+				}},
+			},
+			notAfter: now.Add(60 * 24 * time.Hour),
+			expected: []Event{{Name: "example", Reason: ReasonARI, ExplanationURL: "https://ca.example.com/incident"}},
+		},
+		{
+			desc: "ARI window not reached",
+			checker: &checkerMock{
+				info: &certificate.RenewalInfoResponse{RenewalInfoResponse: acme.RenewalInfoResponse{
+					SuggestedWindow: acme.Window{Start: now.Add(30 * 24 * time.Hour), End: now.Add(31 * 24 * time.Hour)},
+				}},
+			},
+			notAfter: now.Add(60 * 24 * time.Hour),
+		},
+		{
+			desc:     "no ARI, expiring",
+			checker:  &checkerMock{infoErr: api.ErrNoARI},
+			notAfter: now.Add(10 * 24 * time.Hour),
+			expected: []Event{{Name: "example", Reason: ReasonExpiring}},
+		},
+		{
+			desc:     "ARI error, not expiring",
+			checker:  &checkerMock{infoErr: errors.New("boom")},
+			notAfter: now.Add(60 * 24 * time.Hour),
+		},
+		{
+			desc:       "revoked",
+			checker:    &checkerMock{infoErr: api.ErrNoARI, ocspStatus: ocsp.Revoked},
+			notAfter:   now.Add(60 * 24 * time.Hour),
+			ocspServer: true,
+			expected:   []Event{{Name: "example", Reason: ReasonRevoked}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var events []Event
+
+			w := &Watcher{
+				Checker: test.checker,
+				OnRenew: func(_ context.Context, event Event) {
+					event.Certificate = nil
+					events = append(events, event)
+				},
+			}
+
+			err := w.Add("example", generateCertificate(t, test.notAfter, test.ocspServer))
+			require.NoError(t, err)
+
+			w.checkAll(t.Context(), now)
+
+			assert.Equal(t, test.expected, events)
+
+			// The callback is called only once.
+			w.checkAll(t.Context(), now.Add(DefaultInterval))
+
+			assert.Equal(t, test.expected, events)
+		})
+	}
+}
+
+func TestWatcher_checkAll_nextCheck(t *testing.T) {
+	now := time.Now()
+
+	w := &Watcher{
+		Checker: &checkerMock{
+			info: &certificate.RenewalInfoResponse{
+				RenewalInfoResponse: acme.RenewalInfoResponse{
+					SuggestedWindow: acme.Window{Start: now.Add(30 * 24 * time.Hour), End: now.Add(31 * 24 * time.Hour)},
+				},
+				RetryAfter: time.Hour,
+			},
+		},
+		OnRenew: func(_ context.Context, _ Event) {},
+	}
+
+	err := w.Add("example", generateCertificate(t, now.Add(60*24*time.Hour), false))
+	require.NoError(t, err)
+
+	next := w.checkAll(t.Context(), now)
+
+	assert.Equal(t, now.Add(time.Hour), next)
+}
+
+func TestWatcher_Run(t *testing.T) {
+	now := time.Now()
+
+	events := make(chan Event, 1)
+
+	w := &Watcher{
+		Checker: &checkerMock{infoErr: api.ErrNoARI},
+		OnRenew: func(_ context.Context, event Event) {
+			events <- event
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	done := make(chan error)
+
+	go func() { done <- w.Run(ctx) }()
+
+	err := w.Add("example", generateCertificate(t, now.Add(time.Hour), false))
+	require.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, ReasonExpiring, event.Reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+
+	cancel()
+
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestWatcher_Run_errors(t *testing.T) {
+	w := &Watcher{}
+
+	err := w.Run(t.Context())
+	require.EqualError(t, err, "renewal: the checker is nil")
+
+	w.Checker = &checkerMock{}
+
+	err = w.Run(t.Context())
+	require.EqualError(t, err, "renewal: the callback is nil")
+}
+
+func generateCertificate(t *testing.T, notAfter time.Time, ocspServer bool) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	if ocspServer {
+		template.OCSPServer = []string{"http://ocsp.example.com"}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der))
+}
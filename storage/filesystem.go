@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	filePerm os.FileMode = 0o600
+	dirPerm  os.FileMode = 0o700
+)
+
+const (
+	lockExt          = ".lock"
+	lockPollInterval = 100 * time.Millisecond
+)
+
+// errLocked is returned when a lock file is already locked by another process.
+var errLocked = errors.New("already locked")
+
+// heldLocks the lock files locked by this process.
+// The POSIX record locks are owned by the process, so they cannot exclude the goroutines of the same process.
+var heldLocks = struct {
+	sync.Mutex
+	files map[string]*os.File
+}{files: map[string]*os.File{}}
+
+var (
+	_ Storage = (*FileSystem)(nil)
+	_ Locker  = (*FileSystem)(nil)
+)
+
+// FileSystem a storage using the local filesystem.
+// The names are file paths.
+//
+// The files are written atomically (temporary file, then rename),
+// and the locks are advisory locks on lock files (`<name>.lock`) shared between processes.
+type FileSystem struct{}
+
+// NewFileSystem creates a new FileSystem.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+// ReadFile reads the content of a file.
+func (f *FileSystem) ReadFile(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// WriteFile writes atomically the content of a file:
+// the content is written to a temporary file which replaces the file.
+// The parent directories are created if needed.
+func (f *FileSystem) WriteFile(_ context.Context, name string, data []byte) error {
+	dir := filepath.Dir(name)
+
+	err := os.MkdirAll(dir, dirPerm)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), filePerm)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// Exists checks if a file exists.
+func (f *FileSystem) Exists(_ context.Context, name string) (bool, error) {
+	_, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// List lists recursively the files under a directory.
+// The lock files are ignored.
+// The result is empty if the directory doesn't exist.
+func (f *FileSystem) List(_ context.Context, dir string) ([]string, error) {
+	var names []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if !d.IsDir() && !strings.HasSuffix(path, lockExt) {
+			names = append(names, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Rename moves a file.
+// The parent directories of the new file are created if needed.
+func (f *FileSystem) Rename(_ context.Context, oldName, newName string) error {
+	err := os.MkdirAll(filepath.Dir(newName), dirPerm)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(oldName, newName)
+}
+
+// Remove removes a file.
+func (f *FileSystem) Remove(_ context.Context, name string) error {
+	return os.Remove(name)
+}
+
+// Lock acquires the advisory lock of a file, waiting until the lock is released by its owner.
+// The lock file (`<name>.lock`) is created if needed.
+func (f *FileSystem) Lock(ctx context.Context, name string) error {
+	lockName, err := filepath.Abs(name + lockExt)
+	if err != nil {
+		return fmt.Errorf("lock %s: %w", name, err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(lockName), dirPerm)
+	if err != nil {
+		return fmt.Errorf("lock %s: %w", name, err)
+	}
+
+	for {
+		locked, err := tryLock(lockName)
+		if err != nil {
+			return fmt.Errorf("lock %s: %w", name, err)
+		}
+
+		if locked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("lock %s: %w", name, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Unlock releases the advisory lock of a file.
+// The lock file is kept: removing it would allow two processes to lock different files.
+func (f *FileSystem) Unlock(_ context.Context, name string) error {
+	lockName, err := filepath.Abs(name + lockExt)
+	if err != nil {
+		return fmt.Errorf("unlock %s: %w", name, err)
+	}
+
+	heldLocks.Lock()
+	defer heldLocks.Unlock()
+
+	file, ok := heldLocks.files[lockName]
+	if !ok {
+		return fmt.Errorf("unlock %s: not locked", name)
+	}
+
+	delete(heldLocks.files, lockName)
+
+	err = unlockFile(file)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("unlock %s: %w", name, err)
+	}
+
+	return file.Close()
+}
+
+func tryLock(lockName string) (bool, error) {
+	heldLocks.Lock()
+	defer heldLocks.Unlock()
+
+	// The lock is held by this process.
+	if _, ok := heldLocks.files[lockName]; ok {
+		return false, nil
+	}
+
+	file, err := os.OpenFile(lockName, os.O_CREATE|os.O_RDWR, filePerm)
+	if err != nil {
+		return false, err
+	}
+
+	err = lockFile(file)
+	if errors.Is(err, errLocked) {
+		_ = file.Close()
+		return false, nil
+	}
+
+	if err != nil {
+		_ = file.Close()
+		return false, err
+	}
+
+	heldLocks.files[lockName] = file
+
+	return true, nil
+}
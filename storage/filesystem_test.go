@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystem_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	name := filepath.Join(dir, "certificates", "example.com.crt")
+
+	err := s.WriteFile(t.Context(), name, []byte("data"))
+	require.NoError(t, err)
+
+	info, err := os.Stat(name)
+	require.NoError(t, err)
+
+	assert.Equal(t, filePerm, info.Mode().Perm())
+
+	data, err := s.ReadFile(t.Context(), name)
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("data"), data)
+}
+
+func TestFileSystem_ReadFile_notExist(t *testing.T) {
+	s := NewFileSystem()
+
+	_, err := s.ReadFile(t.Context(), filepath.Join(t.TempDir(), "missing.crt"))
+	require.ErrorIs(t, err, ErrNotExist)
+}
+
+func TestFileSystem_Exists(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	name := filepath.Join(dir, "example.com.crt")
+
+	exists, err := s.Exists(t.Context(), name)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, os.WriteFile(name, []byte("data"), filePerm))
+
+	exists, err = s.Exists(t.Context(), name)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestFileSystem_List(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	files := []string{
+		filepath.Join(dir, "b", "c", "account.json"),
+		filepath.Join(dir, "a.crt"),
+		filepath.Join(dir, "b", "keys", "a.key"),
+	}
+
+	for _, file := range files {
+		require.NoError(t, s.WriteFile(t.Context(), file, []byte("data")))
+	}
+
+	names, err := s.List(t.Context(), dir)
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(dir, "a.crt"),
+		filepath.Join(dir, "b", "c", "account.json"),
+		filepath.Join(dir, "b", "keys", "a.key"),
+	}
+
+	assert.Equal(t, expected, names)
+}
+
+func TestFileSystem_List_notExist(t *testing.T) {
+	s := NewFileSystem()
+
+	names, err := s.List(t.Context(), filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+
+	assert.Empty(t, names)
+}
+
+func TestFileSystem_Rename(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	oldName := filepath.Join(dir, "certificates", "example.com.crt")
+	newName := filepath.Join(dir, "archives", "1.example.com.crt")
+
+	require.NoError(t, s.WriteFile(t.Context(), oldName, []byte("data")))
+
+	err := s.Rename(t.Context(), oldName, newName)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, oldName)
+	assert.FileExists(t, newName)
+}
+
+func TestFileSystem_Remove(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	name := filepath.Join(dir, "example.com.crt")
+
+	require.NoError(t, s.WriteFile(t.Context(), name, []byte("data")))
+
+	err := s.Remove(t.Context(), name)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, name)
+}
+
+func TestFileSystem_WriteFile_replace(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	name := filepath.Join(dir, "example.com.crt")
+
+	require.NoError(t, s.WriteFile(t.Context(), name, []byte("old")))
+	require.NoError(t, s.WriteFile(t.Context(), name, []byte("new")))
+
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("new"), data)
+
+	// No temporary files are left.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 1)
+}
+
+func TestFileSystem_Lock(t *testing.T) {
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "accounts", "example@example.com")
+
+	err := NewFileSystem().Lock(t.Context(), name)
+	require.NoError(t, err)
+
+	assert.FileExists(t, name+lockExt)
+
+	// The lock is held: the second attempt waits until the deadline.
+	ctx, cancel := context.WithTimeout(t.Context(), 300*time.Millisecond)
+	defer cancel()
+
+	err = NewFileSystem().Lock(ctx, name)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = NewFileSystem().Unlock(t.Context(), name)
+	require.NoError(t, err)
+
+	err = NewFileSystem().Lock(t.Context(), name)
+	require.NoError(t, err)
+
+	err = NewFileSystem().Unlock(t.Context(), name)
+	require.NoError(t, err)
+}
+
+func TestFileSystem_Unlock_notLocked(t *testing.T) {
+	err := NewFileSystem().Unlock(t.Context(), filepath.Join(t.TempDir(), "example@example.com"))
+	require.ErrorContains(t, err, "not locked")
+}
+
+func TestFileSystem_List_ignoreLocks(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewFileSystem()
+
+	require.NoError(t, s.WriteFile(t.Context(), filepath.Join(dir, "a.crt"), []byte("data")))
+	require.NoError(t, s.Lock(t.Context(), filepath.Join(dir, "a")))
+
+	t.Cleanup(func() { _ = s.Unlock(context.Background(), filepath.Join(dir, "a")) })
+
+	names, err := s.List(t.Context(), dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(dir, "a.crt")}, names)
+}
@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package storage
+
+import "os"
+
+// lockFile only relies on the in-process locks: the file locking is not supported on this platform.
+func lockFile(_ *os.File) error {
+	return nil
+}
+
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrConflict is returned (wrapped) when an entry already exists in the destination with a different content.
+var ErrConflict = errors.New("the entry already exists with a different content")
+
+// MigrateOptions the options of Migrate.
+type MigrateOptions struct {
+	// Overwrite replaces the entries already existing in the destination with a different content.
+	Overwrite bool
+	// DryRun only reports the entries to copy.
+	DryRun bool
+}
+
+// MigrateResult the result of Migrate.
+type MigrateResult struct {
+	// Copied the names of the copied entries.
+	Copied []string
+	// Skipped the names of the entries already existing in the destination with the same content.
+	Skipped []string
+}
+
+// Migrate copies the entries under the directories from a storage to another.
+// Each copy is verified by reading it back from the destination.
+// The entries of the source are not removed.
+func Migrate(ctx context.Context, src, dst Storage, dirs []string, opts MigrateOptions) (*MigrateResult, error) {
+	var names []string
+
+	for _, dir := range dirs {
+		entries, err := src.List(ctx, dir)
+		if err != nil {
+			return nil, fmt.Errorf("storage: migrate: list %s: %w", dir, err)
+		}
+
+		names = append(names, entries...)
+	}
+
+	slices.Sort(names)
+
+	result := &MigrateResult{}
+
+	for _, name := range slices.Compact(names) {
+		copied, err := migrateEntry(ctx, src, dst, name, opts)
+		if err != nil {
+			return result, fmt.Errorf("storage: migrate: %s: %w", name, err)
+		}
+
+		if copied {
+			result.Copied = append(result.Copied, name)
+		} else {
+			result.Skipped = append(result.Skipped, name)
+		}
+	}
+
+	return result, nil
+}
+
+func migrateEntry(ctx context.Context, src, dst Storage, name string, opts MigrateOptions) (bool, error) {
+	data, err := src.ReadFile(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("read: %w", err)
+	}
+
+	existing, err := dst.ReadFile(ctx, name)
+
+	switch {
+	case err == nil:
+		if bytes.Equal(existing, data) {
+			return false, nil
+		}
+
+		if !opts.Overwrite {
+			return false, ErrConflict
+		}
+
+	case !errors.Is(err, ErrNotExist):
+		return false, fmt.Errorf("read destination: %w", err)
+	}
+
+	if opts.DryRun {
+		return true, nil
+	}
+
+	err = dst.WriteFile(ctx, name, data)
+	if err != nil {
+		return false, fmt.Errorf("write: %w", err)
+	}
+
+	written, err := dst.ReadFile(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("verify: %w", err)
+	}
+
+	if !bytes.Equal(written, data) {
+		return false, errors.New("verify: the content of the copy is different")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage a Storage backed by a map.
+type memoryStorage struct {
+	entries map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{entries: map[string][]byte{}}
+}
+
+func (m *memoryStorage) ReadFile(_ context.Context, name string) ([]byte, error) {
+	data, ok := m.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrNotExist)
+	}
+
+	return data, nil
+}
+
+func (m *memoryStorage) WriteFile(_ context.Context, name string, data []byte) error {
+	m.entries[name] = data
+	return nil
+}
+
+func (m *memoryStorage) Exists(_ context.Context, name string) (bool, error) {
+	_, ok := m.entries[name]
+	return ok, nil
+}
+
+func (m *memoryStorage) List(_ context.Context, dir string) ([]string, error) {
+	var names []string
+
+	for name := range m.entries {
+		if strings.HasPrefix(name, dir+string(filepath.Separator)) {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func (m *memoryStorage) Rename(_ context.Context, oldName, newName string) error {
+	m.entries[newName] = m.entries[oldName]
+	delete(m.entries, oldName)
+
+	return nil
+}
+
+func (m *memoryStorage) Remove(_ context.Context, name string) error {
+	delete(m.entries, name)
+	return nil
+}
+
+func setupMigrateSource(t *testing.T) (Storage, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	src := NewFileSystem()
+
+	for name, data := range map[string]string{
+		"accounts/acme-v02.api.letsencrypt.org/you@example.com/account.json":             "account",
+		"accounts/acme-v02.api.letsencrypt.org/you@example.com/keys/you@example.com.key": "key",
+		"certificates/example.com.crt":                                                   "crt",
+		"certificates/example.com.key":                                                   "cert key",
+		"certificates/example.com.json":                                                  "json",
+	} {
+		require.NoError(t, src.WriteFile(t.Context(), filepath.Join(dir, filepath.FromSlash(name)), []byte(data)))
+	}
+
+	return src, dir
+}
+
+func TestMigrate(t *testing.T) {
+	src, dir := setupMigrateSource(t)
+
+	dst := newMemoryStorage()
+	dst.entries[filepath.Join(dir, "certificates", "example.com.json")] = []byte("json")
+
+	dirs := []string{filepath.Join(dir, "accounts"), filepath.Join(dir, "certificates")}
+
+	result, err := Migrate(t.Context(), src, dst, dirs, MigrateOptions{})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Copied, 4)
+	assert.Equal(t, []string{filepath.Join(dir, "certificates", "example.com.json")}, result.Skipped)
+
+	assert.Equal(t, []byte("crt"), dst.entries[filepath.Join(dir, "certificates", "example.com.crt")])
+	assert.Len(t, dst.entries, 5)
+}
+
+func TestMigrate_dryRun(t *testing.T) {
+	src, dir := setupMigrateSource(t)
+
+	dst := newMemoryStorage()
+
+	result, err := Migrate(t.Context(), src, dst, []string{filepath.Join(dir, "certificates")}, MigrateOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Copied, 3)
+	assert.Empty(t, dst.entries)
+}
+
+func TestMigrate_conflict(t *testing.T) {
+	src, dir := setupMigrateSource(t)
+
+	name := filepath.Join(dir, "certificates", "example.com.crt")
+
+	dst := newMemoryStorage()
+	dst.entries[name] = []byte("other")
+
+	dirs := []string{filepath.Join(dir, "certificates")}
+
+	_, err := Migrate(t.Context(), src, dst, dirs, MigrateOptions{})
+	require.ErrorIs(t, err, ErrConflict)
+
+	result, err := Migrate(t.Context(), src, dst, dirs, MigrateOptions{Overwrite: true})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Copied, 3)
+	assert.Equal(t, []byte("crt"), dst.entries[name])
+	assert.Equal(t, slices.Sorted(maps.Keys(dst.entries)), result.Copied)
+}
+
+// truncatingStorage a Storage that loses the last byte of the written entries.
+type truncatingStorage struct {
+	*memoryStorage
+}
+
+func (m *truncatingStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	return m.memoryStorage.WriteFile(ctx, name, data[:len(data)-1])
+}
+
+func TestMigrate_verify(t *testing.T) {
+	src, dir := setupMigrateSource(t)
+
+	dst := &truncatingStorage{memoryStorage: newMemoryStorage()}
+
+	result, err := Migrate(t.Context(), src, dst, []string{filepath.Join(dir, "certificates")}, MigrateOptions{})
+	require.ErrorContains(t, err, "verify: the content of the copy is different")
+
+	assert.Empty(t, result.Copied)
+}
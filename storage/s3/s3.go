@@ -0,0 +1,439 @@
+// Package s3 implements a storage using an S3-compatible object storage (AWS S3, MinIO, ...).
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/digicert/lego/v4/storage"
+)
+
+const lockExt = ".lock"
+
+var (
+	_ storage.Storage = (*Storage)(nil)
+	_ storage.Locker  = (*Storage)(nil)
+)
+
+// Config the configuration of the storage.
+type Config struct {
+	Bucket string
+	// Prefix the prefix of the object keys.
+	Prefix string
+
+	// Endpoint a custom endpoint (ex: MinIO).
+	Endpoint string
+	// UsePathStyle uses the path-style addressing (`https://endpoint/bucket/key`).
+	UsePathStyle bool
+
+	// SSEKMSKeyID enables the server-side encryption with this AWS KMS key.
+	SSEKMSKeyID string
+
+	// LockTTL the duration after which a lock is considered as abandoned.
+	LockTTL time.Duration
+	// LockPollInterval the time between two attempts to acquire a lock.
+	LockPollInterval time.Duration
+}
+
+// NewDefaultConfig returns a default configuration.
+func NewDefaultConfig() *Config {
+	return &Config{
+		LockTTL:          10 * time.Minute,
+		LockPollInterval: 2 * time.Second,
+	}
+}
+
+// Storage a storage using an S3 bucket.
+// The names are converted to object keys (slash-separated, relative, with the optional prefix).
+//
+// The locks are objects created with a conditional write (`If-None-Match: *`),
+// so only one instance can hold a lock at a time.
+// A held lock is refreshed periodically (every third of the LockTTL),
+// and is only removed if it has not been replaced by another instance (`If-Match`).
+type Storage struct {
+	config *Config
+	client *s3.Client
+
+	mu    sync.Mutex
+	locks map[string]*heldLock
+}
+
+// heldLock a lock held by this instance.
+type heldLock struct {
+	// etag is only updated by the refresh, and read after the end of the refresh.
+	etag *string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewStorage creates a new Storage.
+// The AWS credentials are read from the environment (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_PROFILE, ...).
+func NewStorage(cfg *Config) (*Storage, error) {
+	if cfg == nil {
+		return nil, errors.New("s3: the configuration is nil")
+	}
+
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3: bucket name missing")
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("s3: unable to create AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	return &Storage{config: cfg, client: client, locks: map[string]*heldLock{}}, nil
+}
+
+// ReadFile reads the content of an object.
+func (s *Storage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		return nil, s.wrapError(name, err)
+	}
+
+	defer func() { _ = out.Body.Close() }()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("s3: read %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// WriteFile writes the content of an object.
+func (s *Storage) WriteFile(ctx context.Context, name string, data []byte) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.key(name)),
+		Body:   bytes.NewReader(data),
+	}
+
+	if s.config.SSEKMSKeyID != "" {
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		input.SSEKMSKeyId = aws.String(s.config.SSEKMSKeyID)
+	}
+
+	_, err := s.client.PutObject(ctx, input)
+	if err != nil {
+		return s.wrapError(name, err)
+	}
+
+	return nil
+}
+
+// Exists checks if an object exists.
+func (s *Storage) Exists(ctx context.Context, name string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		err = s.wrapError(name, err)
+		if errors.Is(err, storage.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// List lists recursively the objects under a directory.
+// The locks are ignored.
+func (s *Storage) List(ctx context.Context, dir string) ([]string, error) {
+	prefix := s.key(dir) + "/"
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.config.Bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var names []string
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, s.wrapError(dir, err)
+		}
+
+		for _, object := range page.Contents {
+			rel := strings.TrimPrefix(aws.ToString(object.Key), prefix)
+			if strings.HasSuffix(rel, lockExt) {
+				continue
+			}
+
+			names = append(names, filepath.Join(dir, filepath.FromSlash(rel)))
+		}
+	}
+
+	return names, nil
+}
+
+// Rename copies an object to a new key, then removes the old object.
+func (s *Storage) Rename(ctx context.Context, oldName, newName string) error {
+	source := &url.URL{Path: s.config.Bucket + "/" + s.key(oldName)}
+
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(s.config.Bucket),
+		Key:        aws.String(s.key(newName)),
+		CopySource: aws.String(source.EscapedPath()),
+	}
+
+	if s.config.SSEKMSKeyID != "" {
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		input.SSEKMSKeyId = aws.String(s.config.SSEKMSKeyID)
+	}
+
+	_, err := s.client.CopyObject(ctx, input)
+	if err != nil {
+		return s.wrapError(oldName, err)
+	}
+
+	return s.Remove(ctx, oldName)
+}
+
+// Remove removes an object.
+func (s *Storage) Remove(ctx context.Context, name string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		return s.wrapError(name, err)
+	}
+
+	return nil
+}
+
+// Lock creates the lock object of an entry.
+// An abandoned lock (older than the LockTTL) is removed.
+// The lock is refreshed until Unlock is called.
+func (s *Storage) Lock(ctx context.Context, name string) error {
+	key := s.key(name) + lockExt
+
+	for {
+		out, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(s.config.Bucket),
+			Key:         aws.String(key),
+			Body:        strings.NewReader(s.lockExpiration()),
+			IfNoneMatch: aws.String("*"),
+		})
+		if err == nil {
+			s.hold(key, out.ETag)
+
+			return nil
+		}
+
+		if !isConditionFailed(err) {
+			return fmt.Errorf("s3: lock %s: %w", name, err)
+		}
+
+		err = s.removeExpiredLock(ctx, key)
+		if err != nil {
+			return fmt.Errorf("s3: lock %s: %w", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("s3: lock %s: %w", name, ctx.Err())
+		case <-time.After(s.config.LockPollInterval):
+		}
+	}
+}
+
+// Unlock removes the lock object of an entry.
+// The lock object is only removed if it is still the one created by Lock.
+func (s *Storage) Unlock(ctx context.Context, name string) error {
+	key := s.key(name) + lockExt
+
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	delete(s.locks, key)
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("s3: unlock %s: the lock is not held", name)
+	}
+
+	close(lock.stop)
+	<-lock.done
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(s.config.Bucket),
+		Key:     aws.String(key),
+		IfMatch: lock.etag,
+	})
+	if isConditionFailed(err) {
+		return fmt.Errorf("s3: unlock %s: the lock has been taken by another instance", name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("s3: unlock %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// hold registers a held lock and starts its refresh.
+func (s *Storage) hold(key string, etag *string) {
+	lock := &heldLock{
+		etag: etag,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.locks[key] = lock
+	s.mu.Unlock()
+
+	go s.refresh(key, lock)
+}
+
+// refresh extends the expiration of a held lock until the lock is released.
+// The refresh stops if the lock has been replaced by another instance.
+func (s *Storage) refresh(key string, lock *heldLock) {
+	defer close(lock.done)
+
+	interval := max(s.config.LockTTL/3, time.Millisecond)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lock.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+
+		out, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:  aws.String(s.config.Bucket),
+			Key:     aws.String(key),
+			Body:    strings.NewReader(s.lockExpiration()),
+			IfMatch: lock.etag,
+		})
+
+		cancel()
+
+		if isConditionFailed(err) {
+			return
+		}
+
+		if err != nil {
+			// Transient error: the next tick retries before the expiration.
+			continue
+		}
+
+		lock.etag = out.ETag
+	}
+}
+
+func (s *Storage) lockExpiration() string {
+	return time.Now().Add(s.config.LockTTL).UTC().Format(time.RFC3339)
+}
+
+func (s *Storage) removeExpiredLock(ctx context.Context, key string) error {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var errNoSuchKey *types.NoSuchKey
+		if errors.As(err, &errNoSuchKey) {
+			// The lock has been released in the meantime.
+			return nil
+		}
+
+		return err
+	}
+
+	defer func() { _ = out.Body.Close() }()
+
+	raw, err := io.ReadAll(out.Body)
+	if err != nil {
+		return err
+	}
+
+	expiration, err := time.Parse(time.RFC3339, strings.TrimSpace(string(raw)))
+	if err == nil && time.Now().Before(expiration) {
+		return nil
+	}
+
+	// Only removes the lock if it has not been replaced in the meantime.
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(s.config.Bucket),
+		Key:     aws.String(key),
+		IfMatch: out.ETag,
+	})
+	if err != nil && !isConditionFailed(err) {
+		return err
+	}
+
+	return nil
+}
+
+// key converts a name to an object key.
+func (s *Storage) key(name string) string {
+	key := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+
+	if s.config.Prefix == "" {
+		return key
+	}
+
+	return path.Join(s.config.Prefix, key)
+}
+
+func (s *Storage) wrapError(name string, err error) error {
+	var (
+		errNoSuchKey *types.NoSuchKey
+		errNotFound  *types.NotFound
+	)
+
+	if errors.As(err, &errNoSuchKey) || errors.As(err, &errNotFound) {
+		return fmt.Errorf("s3: %s: %w", name, storage.ErrNotExist)
+	}
+
+	return fmt.Errorf("s3: %s: %w", name, err)
+}
+
+// isConditionFailed checks if a conditional request has failed.
+func isConditionFailed(err error) bool {
+	var errResp *awshttp.ResponseError
+	if !errors.As(err, &errResp) {
+		return false
+	}
+
+	return errResp.HTTPStatusCode() == http.StatusPreconditionFailed || errResp.HTTPStatusCode() == http.StatusConflict
+}
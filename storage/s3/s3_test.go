@@ -0,0 +1,369 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_WriteFile(t *testing.T) {
+	server, s := setupStorage(t)
+
+	s.config.Prefix = "prod"
+	s.config.SSEKMSKeyID = "arn:aws:kms:us-east-1:123456789012:key/abc"
+
+	err := s.WriteFile(t.Context(), "./.lego/certificates/example.com.crt", []byte("data"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("data"), server.get("prod/.lego/certificates/example.com.crt"))
+	assert.Equal(t, "aws:kms", server.headers["prod/.lego/certificates/example.com.crt"].Get("X-Amz-Server-Side-Encryption"))
+}
+
+func TestStorage_ReadFile(t *testing.T) {
+	server, s := setupStorage(t)
+
+	server.put(".lego/certificates/example.com.crt", []byte("data"))
+
+	data, err := s.ReadFile(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("data"), data)
+}
+
+func TestStorage_ReadFile_notExist(t *testing.T) {
+	_, s := setupStorage(t)
+
+	_, err := s.ReadFile(t.Context(), ".lego/certificates/example.com.crt")
+	require.ErrorIs(t, err, storage.ErrNotExist)
+}
+
+func TestStorage_Exists(t *testing.T) {
+	server, s := setupStorage(t)
+
+	exists, err := s.Exists(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	server.put(".lego/certificates/example.com.crt", []byte("data"))
+
+	exists, err = s.Exists(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestStorage_List(t *testing.T) {
+	server, s := setupStorage(t)
+
+	server.put(".lego/certificates/example.com.crt", []byte("data"))
+	server.put(".lego/certificates/example.com.key", []byte("data"))
+	server.put(".lego/certificates.lock", []byte("data"))
+	server.put(".lego/accounts/example/foo@example.com/account.json", []byte("data"))
+	server.put(".lego/accounts/example/foo@example.com.lock", []byte("data"))
+
+	names, err := s.List(t.Context(), "./.lego/accounts")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(".lego", "accounts", "example", "foo@example.com", "account.json")}, names)
+
+	names, err = s.List(t.Context(), ".lego/certificates")
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(".lego", "certificates", "example.com.crt"),
+		filepath.Join(".lego", "certificates", "example.com.key"),
+	}
+
+	assert.Equal(t, expected, names)
+}
+
+func TestStorage_Rename(t *testing.T) {
+	server, s := setupStorage(t)
+
+	server.put(".lego/certificates/*.example.com.crt", []byte("data"))
+
+	err := s.Rename(t.Context(), ".lego/certificates/*.example.com.crt", ".lego/archives/1.*.example.com.crt")
+	require.NoError(t, err)
+
+	assert.Nil(t, server.get(".lego/certificates/*.example.com.crt"))
+	assert.Equal(t, []byte("data"), server.get(".lego/archives/1.*.example.com.crt"))
+}
+
+func TestStorage_Lock(t *testing.T) {
+	server, s := setupStorage(t)
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	assert.NotNil(t, server.get(".lego/accounts/example/foo@example.com.lock"))
+
+	// The lock is held: the second attempt waits until the deadline.
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	err = s.Lock(ctx, ".lego/accounts/example/foo@example.com")
+	require.ErrorContains(t, err, "context deadline exceeded")
+
+	err = s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	assert.Nil(t, server.get(".lego/accounts/example/foo@example.com.lock"))
+}
+
+func TestStorage_Lock_expired(t *testing.T) {
+	server, s := setupStorage(t)
+
+	server.put(".lego/accounts/example/foo@example.com.lock", []byte(time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)))
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	lock := server.get(".lego/accounts/example/foo@example.com.lock")
+
+	expiration, err := time.Parse(time.RFC3339, string(lock))
+	require.NoError(t, err)
+
+	assert.True(t, expiration.After(time.Now()))
+}
+
+func TestStorage_Lock_refresh(t *testing.T) {
+	server, s := setupStorage(t)
+
+	s.config.LockTTL = 3 * time.Second
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	initial := server.get(".lego/accounts/example/foo@example.com.lock")
+
+	assert.Eventually(t, func() bool {
+		return !bytes.Equal(initial, server.get(".lego/accounts/example/foo@example.com.lock"))
+	}, 5*time.Second, 50*time.Millisecond)
+
+	err = s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	assert.Nil(t, server.get(".lego/accounts/example/foo@example.com.lock"))
+}
+
+func TestStorage_Unlock_taken(t *testing.T) {
+	server, s := setupStorage(t)
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	// The lock has expired and has been taken by another instance.
+	other := []byte(time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + "\n")
+	server.put(".lego/accounts/example/foo@example.com.lock", other)
+
+	err = s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.EqualError(t, err, "s3: unlock .lego/accounts/example/foo@example.com: the lock has been taken by another instance")
+
+	assert.Equal(t, other, server.get(".lego/accounts/example/foo@example.com.lock"))
+}
+
+func TestStorage_Unlock_notHeld(t *testing.T) {
+	server, s := setupStorage(t)
+
+	server.put(".lego/accounts/example/foo@example.com.lock", []byte("data"))
+
+	err := s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.EqualError(t, err, "s3: unlock .lego/accounts/example/foo@example.com: the lock is not held")
+
+	assert.NotNil(t, server.get(".lego/accounts/example/foo@example.com.lock"))
+}
+
+func setupStorage(t *testing.T) (*fakeS3, *Storage) {
+	t.Helper()
+
+	server := &fakeS3{
+		bucket:  "lego",
+		objects: map[string][]byte{},
+		headers: map[string]http.Header{},
+	}
+
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "user")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	config := NewDefaultConfig()
+	config.Bucket = "lego"
+	config.Endpoint = srv.URL
+	config.UsePathStyle = true
+	config.LockPollInterval = 10 * time.Millisecond
+
+	s, err := NewStorage(config)
+	require.NoError(t, err)
+
+	return server, s
+}
+
+// fakeS3 a minimal S3 server (path-style).
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+}
+
+func (f *fakeS3) put(key string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.objects[key] = data
+}
+
+func (f *fakeS3) get(key string) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.objects[key]
+}
+
+func (f *fakeS3) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, "/"+f.bucket), "/")
+
+	if key == "" && req.Method == http.MethodGet {
+		f.list(rw, req)
+		return
+	}
+
+	data, exists := f.objects[key]
+
+	switch req.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(rw, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		rw.Header().Set("ETag", etag(data))
+		_, _ = rw.Write(data)
+
+	case http.MethodHead:
+		if !exists {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		rw.Header().Set("ETag", etag(data))
+
+	case http.MethodPut:
+		if req.Header.Get("If-None-Match") == "*" && exists {
+			writeError(rw, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+
+		if match := req.Header.Get("If-Match"); match != "" && (!exists || match != etag(data)) {
+			writeError(rw, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+
+		if source := req.Header.Get("X-Amz-Copy-Source"); source != "" {
+			src, _ := url.PathUnescape(source)
+
+			srcData, ok := f.objects[strings.TrimPrefix(strings.TrimPrefix(src, "/"), f.bucket+"/")]
+			if !ok {
+				writeError(rw, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+
+			f.objects[key] = srcData
+
+			_, _ = rw.Write([]byte(`<CopyObjectResult><ETag>` + etag(srcData) + `</ETag></CopyObjectResult>`))
+
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+
+		f.objects[key] = body
+		f.headers[key] = req.Header.Clone()
+
+		rw.Header().Set("ETag", etag(body))
+
+	case http.MethodDelete:
+		if match := req.Header.Get("If-Match"); match != "" && (!exists || match != etag(data)) {
+			writeError(rw, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+
+		delete(f.objects, key)
+
+		rw.WriteHeader(http.StatusNoContent)
+
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) list(rw http.ResponseWriter, req *http.Request) {
+	prefix := req.URL.Query().Get("prefix")
+
+	type content struct {
+		Key string `xml:"Key"`
+	}
+
+	result := struct {
+		XMLName     xml.Name  `xml:"ListBucketResult"`
+		Name        string    `xml:"Name"`
+		Prefix      string    `xml:"Prefix"`
+		KeyCount    int       `xml:"KeyCount"`
+		IsTruncated bool      `xml:"IsTruncated"`
+		Contents    []content `xml:"Contents"`
+	}{Name: f.bucket, Prefix: prefix}
+
+	var keys []string
+
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{Key: key})
+	}
+
+	result.KeyCount = len(keys)
+
+	_ = xml.NewEncoder(rw).Encode(result)
+}
+
+func writeError(rw http.ResponseWriter, status int, code string) {
+	rw.WriteHeader(status)
+	_, _ = rw.Write([]byte(`<Error><Code>` + code + `</Code><Message>` + code + `</Message></Error>`))
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
@@ -0,0 +1,369 @@
+// Package sql implements a storage using a SQL database (SQLite, PostgreSQL).
+package sql
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digicert/lego/v4/storage"
+)
+
+// Dialect the SQL dialect of the database.
+type Dialect string
+
+// Supported dialects.
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+var (
+	_ storage.Storage = (*Storage)(nil)
+	_ storage.Locker  = (*Storage)(nil)
+)
+
+// The placeholders ($1, $2, ...) are supported by SQLite and PostgreSQL.
+const (
+	queryReadEntry   = `SELECT data FROM lego_entries WHERE name = $1`
+	queryWriteEntry  = `INSERT INTO lego_entries (name, data, updated_at) VALUES ($1, $2, $3) ON CONFLICT (name) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`
+	queryExistsEntry = `SELECT COUNT(*) FROM lego_entries WHERE name = $1`
+	queryListEntries = `SELECT name FROM lego_entries WHERE name LIKE $1 ESCAPE '\' ORDER BY name`
+	queryRenameEntry = `UPDATE lego_entries SET name = $1, updated_at = $2 WHERE name = $3`
+	queryRemoveEntry = `DELETE FROM lego_entries WHERE name = $1`
+
+	queryLock              = `INSERT INTO lego_locks (name, owner, expires_at) VALUES ($1, $2, $3) ON CONFLICT (name) DO NOTHING`
+	queryRemoveExpiredLock = `DELETE FROM lego_locks WHERE name = $1 AND expires_at < $2`
+	queryUnlock            = `DELETE FROM lego_locks WHERE name = $1 AND owner = $2`
+)
+
+// Config the configuration of the storage.
+type Config struct {
+	Dialect Dialect
+
+	// LockTTL the duration after which a lock is considered as abandoned.
+	LockTTL time.Duration
+	// LockPollInterval the time between two attempts to acquire a lock.
+	LockPollInterval time.Duration
+}
+
+// NewDefaultConfig returns a default configuration.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Dialect:          SQLite,
+		LockTTL:          10 * time.Minute,
+		LockPollInterval: 2 * time.Second,
+	}
+}
+
+// Storage a storage using a SQL database.
+// The names are converted to keys (slash-separated, relative).
+//
+// The database driver must be registered by the application (ex: `modernc.org/sqlite`, `github.com/jackc/pgx/v5/stdlib`).
+//
+// A lock is a row identified by a random owner token,
+// so only the instance that created the lock can remove it.
+type Storage struct {
+	config *Config
+	db     *sql.DB
+
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// NewStorage creates a new Storage, and the tables if needed.
+func NewStorage(ctx context.Context, db *sql.DB, config *Config) (*Storage, error) {
+	if config == nil {
+		return nil, errors.New("sql: the configuration is nil")
+	}
+
+	if db == nil {
+		return nil, errors.New("sql: the database is nil")
+	}
+
+	schema, err := config.Dialect.schema()
+	if err != nil {
+		return nil, fmt.Errorf("sql: %w", err)
+	}
+
+	for _, query := range schema {
+		_, err = db.ExecContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("sql: create tables: %w", err)
+		}
+	}
+
+	return &Storage{config: config, db: db, owners: map[string]string{}}, nil
+}
+
+// Open opens the database, then creates a new Storage.
+// The driver name defines the dialect: "postgres" and "pgx" for PostgreSQL, "sqlite" and "sqlite3" for SQLite.
+func Open(ctx context.Context, driverName, dsn string) (*Storage, error) {
+	config := NewDefaultConfig()
+
+	switch driverName {
+	case "postgres", "pgx":
+		config.Dialect = Postgres
+	case "sqlite", "sqlite3":
+		config.Dialect = SQLite
+	default:
+		return nil, fmt.Errorf("sql: unsupported driver: %q", driverName)
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql: %w", err)
+	}
+
+	return NewStorage(ctx, db, config)
+}
+
+// ReadFile reads the content of an entry.
+func (s *Storage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	var data []byte
+
+	err := s.db.QueryRowContext(ctx, queryReadEntry, key(name)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("sql: %s: %w", name, storage.ErrNotExist)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("sql: %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// WriteFile writes the content of an entry.
+func (s *Storage) WriteFile(ctx context.Context, name string, data []byte) error {
+	_, err := s.db.ExecContext(ctx, queryWriteEntry, key(name), data, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Exists checks if an entry exists.
+func (s *Storage) Exists(ctx context.Context, name string) (bool, error) {
+	var count int
+
+	err := s.db.QueryRowContext(ctx, queryExistsEntry, key(name)).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("sql: %s: %w", name, err)
+	}
+
+	return count > 0, nil
+}
+
+// List lists recursively the entries under a directory.
+func (s *Storage) List(ctx context.Context, dir string) ([]string, error) {
+	prefix := key(dir) + "/"
+
+	rows, err := s.db.QueryContext(ctx, queryListEntries, escapeLike(prefix)+"%")
+	if err != nil {
+		return nil, fmt.Errorf("sql: %s: %w", dir, err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var names []string
+
+	for rows.Next() {
+		var k string
+
+		err = rows.Scan(&k)
+		if err != nil {
+			return nil, fmt.Errorf("sql: %s: %w", dir, err)
+		}
+
+		names = append(names, filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(k, prefix))))
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("sql: %s: %w", dir, err)
+	}
+
+	return names, nil
+}
+
+// Rename renames an entry, the existing entry with the new name is replaced.
+func (s *Storage) Rename(ctx context.Context, oldName, newName string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", oldName, err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, queryRemoveEntry, key(newName))
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", newName, err)
+	}
+
+	result, err := tx.ExecContext(ctx, queryRenameEntry, key(newName), time.Now().Unix(), key(oldName))
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", oldName, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", oldName, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("sql: %s: %w", oldName, storage.ErrNotExist)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", oldName, err)
+	}
+
+	return nil
+}
+
+// Remove removes an entry.
+func (s *Storage) Remove(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, queryRemoveEntry, key(name))
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", name, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sql: %s: %w", name, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("sql: %s: %w", name, storage.ErrNotExist)
+	}
+
+	return nil
+}
+
+// Lock acquires the lock of an entry.
+// An abandoned lock (older than the LockTTL) is removed.
+func (s *Storage) Lock(ctx context.Context, name string) error {
+	k := key(name)
+
+	owner, err := newOwner()
+	if err != nil {
+		return fmt.Errorf("sql: lock %s: %w", name, err)
+	}
+
+	for {
+		result, err := s.db.ExecContext(ctx, queryLock, k, owner, time.Now().Add(s.config.LockTTL).Unix())
+		if err != nil {
+			return fmt.Errorf("sql: lock %s: %w", name, err)
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("sql: lock %s: %w", name, err)
+		}
+
+		if count > 0 {
+			s.mu.Lock()
+			s.owners[k] = owner
+			s.mu.Unlock()
+
+			return nil
+		}
+
+		_, err = s.db.ExecContext(ctx, queryRemoveExpiredLock, k, time.Now().Unix())
+		if err != nil {
+			return fmt.Errorf("sql: lock %s: %w", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("sql: lock %s: %w", name, ctx.Err())
+		case <-time.After(s.config.LockPollInterval):
+		}
+	}
+}
+
+// Unlock releases the lock of an entry.
+// The lock is only removed if it is still owned by this instance.
+func (s *Storage) Unlock(ctx context.Context, name string) error {
+	k := key(name)
+
+	s.mu.Lock()
+	owner, ok := s.owners[k]
+	delete(s.owners, k)
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("sql: unlock %s: the lock is not held", name)
+	}
+
+	result, err := s.db.ExecContext(ctx, queryUnlock, k, owner)
+	if err != nil {
+		return fmt.Errorf("sql: unlock %s: %w", name, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sql: unlock %s: %w", name, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("sql: unlock %s: the lock has been taken by another instance", name)
+	}
+
+	return nil
+}
+
+// Close closes the database.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func (d Dialect) schema() ([]string, error) {
+	var blobType string
+
+	switch d {
+	case SQLite:
+		blobType = "BLOB"
+	case Postgres:
+		blobType = "BYTEA"
+	default:
+		return nil, fmt.Errorf("unsupported dialect: %q", d)
+	}
+
+	return []string{
+		`CREATE TABLE IF NOT EXISTS lego_entries (name TEXT PRIMARY KEY, data ` + blobType + ` NOT NULL, updated_at BIGINT NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS lego_locks (name TEXT PRIMARY KEY, owner TEXT NOT NULL, expires_at BIGINT NOT NULL)`,
+	}, nil
+}
+
+// newOwner generates a random owner token for a lock.
+func newOwner() (string, error) {
+	raw := make([]byte, 16)
+
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// key converts a name to a key.
+func key(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
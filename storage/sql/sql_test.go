@@ -0,0 +1,419 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digicert/lego/v4/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_WriteFile_ReadFile(t *testing.T) {
+	s := setupStorage(t)
+
+	_, err := s.ReadFile(t.Context(), ".lego/certificates/example.com.crt")
+	require.ErrorIs(t, err, storage.ErrNotExist)
+
+	err = s.WriteFile(t.Context(), "./.lego/certificates/example.com.crt", []byte("a"))
+	require.NoError(t, err)
+
+	err = s.WriteFile(t.Context(), ".lego/certificates/example.com.crt", []byte("b"))
+	require.NoError(t, err)
+
+	data, err := s.ReadFile(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("b"), data)
+}
+
+func TestStorage_Exists(t *testing.T) {
+	s := setupStorage(t)
+
+	exists, err := s.Exists(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = s.WriteFile(t.Context(), ".lego/certificates/example.com.crt", []byte("data"))
+	require.NoError(t, err)
+
+	exists, err = s.Exists(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestStorage_List(t *testing.T) {
+	s := setupStorage(t)
+
+	for _, name := range []string{
+		".lego/certificates/example.com.crt",
+		".lego/certificates/example.com.key",
+		".lego/certificates_other/example.com.crt",
+		".lego/accounts/example/foo@example.com/account.json",
+	} {
+		err := s.WriteFile(t.Context(), name, []byte("data"))
+		require.NoError(t, err)
+	}
+
+	names, err := s.List(t.Context(), "./.lego/accounts")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(".lego", "accounts", "example", "foo@example.com", "account.json")}, names)
+
+	names, err = s.List(t.Context(), ".lego/certificates")
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(".lego", "certificates", "example.com.crt"),
+		filepath.Join(".lego", "certificates", "example.com.key"),
+	}
+
+	assert.Equal(t, expected, names)
+}
+
+func TestStorage_Rename(t *testing.T) {
+	s := setupStorage(t)
+
+	err := s.WriteFile(t.Context(), ".lego/certificates/*.example.com.crt", []byte("data"))
+	require.NoError(t, err)
+
+	err = s.Rename(t.Context(), ".lego/certificates/*.example.com.crt", ".lego/archives/1.*.example.com.crt")
+	require.NoError(t, err)
+
+	exists, err := s.Exists(t.Context(), ".lego/certificates/*.example.com.crt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	data, err := s.ReadFile(t.Context(), ".lego/archives/1.*.example.com.crt")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	err = s.Rename(t.Context(), ".lego/certificates/*.example.com.crt", ".lego/archives/2.*.example.com.crt")
+	require.ErrorIs(t, err, storage.ErrNotExist)
+}
+
+func TestStorage_Remove(t *testing.T) {
+	s := setupStorage(t)
+
+	err := s.Remove(t.Context(), ".lego/certificates/example.com.crt")
+	require.ErrorIs(t, err, storage.ErrNotExist)
+
+	err = s.WriteFile(t.Context(), ".lego/certificates/example.com.crt", []byte("data"))
+	require.NoError(t, err)
+
+	err = s.Remove(t.Context(), ".lego/certificates/example.com.crt")
+	require.NoError(t, err)
+}
+
+func TestStorage_Lock(t *testing.T) {
+	s := setupStorage(t)
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	// The lock is held: the second attempt waits until the deadline.
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	err = s.Lock(ctx, ".lego/accounts/example/foo@example.com")
+	require.ErrorContains(t, err, "context deadline exceeded")
+
+	err = s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	err = s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+}
+
+func TestStorage_Lock_expired(t *testing.T) {
+	s := setupStorage(t)
+
+	s.config.LockTTL = -time.Minute
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	s.config.LockTTL = time.Minute
+
+	err = s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+}
+
+func TestStorage_Unlock_taken(t *testing.T) {
+	s := setupStorage(t)
+
+	s.config.LockTTL = -time.Minute
+
+	err := s.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	// The lock has expired and has been taken by another instance.
+	other := setupStorageWithDB(t, s.db)
+
+	err = other.Lock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+
+	err = s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.EqualError(t, err, "sql: unlock .lego/accounts/example/foo@example.com: the lock has been taken by another instance")
+
+	err = other.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.NoError(t, err)
+}
+
+func TestStorage_Unlock_notHeld(t *testing.T) {
+	s := setupStorage(t)
+
+	err := s.Unlock(t.Context(), ".lego/accounts/example/foo@example.com")
+	require.EqualError(t, err, "sql: unlock .lego/accounts/example/foo@example.com: the lock is not held")
+}
+
+func TestOpen_unsupportedDriver(t *testing.T) {
+	_, err := Open(t.Context(), "mysql", "")
+	require.EqualError(t, err, `sql: unsupported driver: "mysql"`)
+}
+
+func Test_escapeLike(t *testing.T) {
+	assert.Equal(t, `.lego/a\_b\%c\\d/`, escapeLike(`.lego/a_b%c\d/`))
+}
+
+func setupStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	db := sql.OpenDB(&fakeConnector{db: &fakeDB{
+		entries: map[string][]byte{},
+		locks:   map[string]fakeLock{},
+	}})
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return setupStorageWithDB(t, db)
+}
+
+func setupStorageWithDB(t *testing.T, db *sql.DB) *Storage {
+	t.Helper()
+
+	config := NewDefaultConfig()
+	config.LockPollInterval = 10 * time.Millisecond
+
+	s, err := NewStorage(t.Context(), db, config)
+	require.NoError(t, err)
+
+	return s
+}
+
+// fakeDB an in-memory database understanding only the queries of the storage.
+type fakeDB struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	locks   map[string]fakeLock
+}
+
+type fakeLock struct {
+	owner     string
+	expiresAt int64
+}
+
+func (f *fakeDB) exec(query string, args []driver.Value) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch query {
+	case queryWriteEntry:
+		f.entries[args[0].(string)] = args[1].([]byte)
+		return 1, nil
+
+	case queryRenameEntry:
+		data, ok := f.entries[args[2].(string)]
+		if !ok {
+			return 0, nil
+		}
+
+		delete(f.entries, args[2].(string))
+		f.entries[args[0].(string)] = data
+
+		return 1, nil
+
+	case queryRemoveEntry:
+		return deleteKey(f.entries, args[0].(string)), nil
+
+	case queryLock:
+		if _, ok := f.locks[args[0].(string)]; ok {
+			return 0, nil
+		}
+
+		f.locks[args[0].(string)] = fakeLock{owner: args[1].(string), expiresAt: args[2].(int64)}
+
+		return 1, nil
+
+	case queryRemoveExpiredLock:
+		if lock, ok := f.locks[args[0].(string)]; ok && lock.expiresAt < args[1].(int64) {
+			return deleteKey(f.locks, args[0].(string)), nil
+		}
+
+		return 0, nil
+
+	case queryUnlock:
+		if lock, ok := f.locks[args[0].(string)]; ok && lock.owner == args[1].(string) {
+			return deleteKey(f.locks, args[0].(string)), nil
+		}
+
+		return 0, nil
+
+	default:
+		if strings.HasPrefix(query, "CREATE ") {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("unexpected query: %s", query)
+	}
+}
+
+func (f *fakeDB) query(query string, args []driver.Value) ([][]driver.Value, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch query {
+	case queryReadEntry:
+		data, ok := f.entries[args[0].(string)]
+		if !ok {
+			return nil, nil
+		}
+
+		return [][]driver.Value{{data}}, nil
+
+	case queryExistsEntry:
+		_, ok := f.entries[args[0].(string)]
+		if ok {
+			return [][]driver.Value{{int64(1)}}, nil
+		}
+
+		return [][]driver.Value{{int64(0)}}, nil
+
+	case queryListEntries:
+		prefix := strings.NewReplacer(`\\`, `\`, `\%`, `%`, `\_`, `_`).Replace(strings.TrimSuffix(args[0].(string), "%"))
+
+		var rows [][]driver.Value
+
+		for _, name := range slices.Sorted(maps.Keys(f.entries)) {
+			if strings.HasPrefix(name, prefix) {
+				rows = append(rows, []driver.Value{name})
+			}
+		}
+
+		return rows, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+}
+
+func deleteKey[V any](m map[string]V, key string) int64 {
+	if _, ok := m[key]; !ok {
+		return 0
+	}
+
+	delete(m, key)
+
+	return 1
+}
+
+type fakeConnector struct {
+	db *fakeDB
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: c.db}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	count, err := c.db.exec(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(count), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.db.query(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{rows: rows}, nil
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	var result []driver.Value
+	for _, arg := range args {
+		result = append(result, arg.Value)
+	}
+
+	return result
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// Package storage provides the backends used to persist the accounts and the certificates.
+package storage
+
+import (
+	"context"
+	"io/fs"
+)
+
+// ErrNotExist is returned (wrapped) when an entry doesn't exist.
+var ErrNotExist = fs.ErrNotExist
+
+// Storage a backend to store the data of the accounts and the certificates.
+//
+// The entries are identified by their names.
+// A name is a path using the layout of the filesystem storage (ex: `.lego/certificates/example.com.crt`),
+// the other backends use it as a key.
+type Storage interface {
+	// ReadFile reads the content of an entry.
+	// The error wraps ErrNotExist if the entry doesn't exist.
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+
+	// WriteFile writes the content of an entry, the entry is replaced if it already exists.
+	WriteFile(ctx context.Context, name string, data []byte) error
+
+	// Exists checks if an entry exists.
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// List lists recursively the names of the entries under a directory.
+	List(ctx context.Context, dir string) ([]string, error)
+
+	// Rename renames (moves) an entry.
+	Rename(ctx context.Context, oldName, newName string) error
+
+	// Remove removes an entry.
+	Remove(ctx context.Context, name string) error
+}
+
+// Locker is implemented by the storages supporting the locking of entries,
+// it allows several lego instances to share the same storage.
+type Locker interface {
+	// Lock acquires the lock of an entry, waiting until the lock is released by its owner.
+	Lock(ctx context.Context, name string) error
+
+	// Unlock releases the lock of an entry.
+	Unlock(ctx context.Context, name string) error
+}